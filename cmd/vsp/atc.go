@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/oisee/vibing-steampunk/pkg/adt"
+	"github.com/oisee/vibing-steampunk/pkg/dsl"
+	"github.com/spf13/cobra"
+)
+
+var atcCmd = &cobra.Command{
+	Use:   "atc [package-pattern]",
+	Short: "Run ATC checks for objects, a package or a transport",
+	Long: `Run ATC (ABAP Test Cockpit) checks for all classes/programs/interfaces
+matching a search pattern, for all objects of a package and its subpackages
+(--package), or for all objects of a transport request (--transport). The
+findings of all objects are merged into one worklist.
+
+--format selects the output: text (default), json, sarif, github (workflow
+annotations) or gitlab (code quality). Findings are mapped to abapGit file
+paths below --src-dir.
+
+With --baseline, only findings that are not in the baseline file are
+reported, together with the baseline findings that were fixed. If the
+baseline file does not exist, it is created from this run. The command fails
+if new findings with priority --fail-priority or higher (1=error, 2=warning,
+3=info) are found; without a baseline, all findings count as new.
+
+Examples:
+  vsp atc "$ZRAY*"
+  vsp atc --package '$ZRAY'
+  vsp atc --transport A4HK900123 --fail-priority 2
+  vsp atc "$ZRAY*" --variant ABAP_CLOUD_DEVELOPMENT --format sarif --out atc.sarif
+  vsp atc "$ZRAY*" --baseline atc-baseline.json --fail-priority 2
+  vsp atc "$ZRAY*" --baseline atc-baseline.json --update-baseline`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runATC,
+}
+
+var (
+	atcVariant        string
+	atcMax            int
+	atcBaseline       string
+	atcUpdateBaseline bool
+	atcFailPriority   int
+	atcPackage        string
+	atcTransport      string
+)
+
+func init() {
+	atcCmd.Flags().StringVar(&atcVariant, "variant", "", "ATC check variant (default: system variant)")
+	atcCmd.Flags().IntVar(&atcMax, "max", 1000, "Maximum findings per ATC run")
+	atcCmd.Flags().StringVar(&reportFormat, "format", "text", "Output format: text, json, sarif, github, gitlab")
+	atcCmd.Flags().StringVar(&reportOut, "out", "", "Write the report to file instead of stdout")
+	atcCmd.Flags().StringVar(&testSrcDir, "src-dir", "src", "abapGit source folder used for report file paths")
+	atcCmd.Flags().StringVar(&atcBaseline, "baseline", "", "Baseline file; report only new and fixed findings")
+	atcCmd.Flags().BoolVar(&atcUpdateBaseline, "update-baseline", false, "Overwrite the baseline with the findings of this run")
+	atcCmd.Flags().StringVar(&atcPackage, "package", "", "Check all objects of a package and its subpackages")
+	atcCmd.Flags().StringVar(&atcTransport, "transport", "", "Check all objects of a transport request")
+	atcCmd.Flags().IntVar(&atcFailPriority, "fail-priority", 1, "Fail on new findings with this priority or higher (0 = never fail)")
+
+	rootCmd.AddCommand(atcCmd)
+}
+
+func runATC(cmd *cobra.Command, args []string) error {
+	reportFormat = strings.ToLower(reportFormat)
+
+	resolveConfig(cmd.Parent())
+
+	if err := validateConfig(); err != nil {
+		return err
+	}
+
+	if err := processCookieAuth(cmd.Parent()); err != nil {
+		return err
+	}
+
+	scopes := len(args)
+	if atcPackage != "" {
+		scopes++
+	}
+	if atcTransport != "" {
+		scopes++
+	}
+	if scopes != 1 {
+		return fmt.Errorf("specify exactly one of a package pattern, --package or --transport")
+	}
+
+	client := createADTClient()
+	ctx := context.Background()
+	opts := adt.ATCSetOptions{Variant: atcVariant, MaxResults: atcMax}
+
+	var worklist *adt.ATCWorklist
+	var skipped []string
+	switch {
+	case atcPackage != "":
+		fmt.Fprintf(os.Stderr, "Running ATC for package %s\n", atcPackage)
+		result, err := client.RunATCCheckPackage(ctx, atcPackage, opts)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Checked %d objects\n", result.Summary.CheckedObjects)
+		worklist = result.Worklist
+	case atcTransport != "":
+		fmt.Fprintf(os.Stderr, "Running ATC for transport %s\n", atcTransport)
+		result, err := client.RunATCCheckTransport(ctx, atcTransport, opts)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Checked %d objects\n", result.Summary.CheckedObjects)
+		worklist, skipped = result.Worklist, result.Summary.Skipped
+	default:
+		objects, err := dsl.Search(client).
+			Query(args[0]).
+			Types(dsl.TypeClass, dsl.TypeProgram, dsl.TypeInterface).
+			MaxResults(500).
+			Execute(ctx)
+		if err != nil {
+			return fmt.Errorf("search failed: %w", err)
+		}
+
+		if len(objects) == 0 {
+			fmt.Fprintln(os.Stderr, "No objects found")
+			return nil
+		}
+
+		fmt.Fprintf(os.Stderr, "Running ATC for %d objects\n", len(objects))
+		if worklist, err = dsl.RunATC(ctx, client, objects, atcVariant, atcMax); err != nil {
+			return err
+		}
+	}
+	for _, s := range skipped {
+		fmt.Fprintf(os.Stderr, "Skipped (not checkable): %s\n", s)
+	}
+
+	var comparison *adt.ATCComparison
+	if atcBaseline != "" {
+		baseline, err := adt.LoadATCBaseline(atcBaseline)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			if err := adt.NewATCBaseline(worklist).Save(atcBaseline); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Baseline created: %s (%d findings)\n", atcBaseline, worklist.CountFindings(3))
+			return nil
+		case err != nil:
+			return err
+		}
+
+		comparison = baseline.Compare(worklist)
+		fmt.Fprintf(os.Stderr, "Baseline %s: %d new, %d fixed, %d unchanged\n",
+			atcBaseline, comparison.NewCount, len(comparison.Fixed), comparison.Unchanged)
+		if atcUpdateBaseline {
+			if err := adt.NewATCBaseline(worklist).Save(atcBaseline); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Baseline updated: %s\n", atcBaseline)
+		}
+		worklist = comparison.New
+	}
+
+	switch reportFormat {
+	case "text":
+		printATCFindings(worklist, comparison)
+	case "json":
+		var v interface{} = worklist
+		if comparison != nil {
+			v = comparison
+		}
+		if err := writeJSONReport(v); err != nil {
+			return err
+		}
+	default:
+		data, err := dsl.RenderReport(reportFormat, "ATC", testSrcDir, nil, worklist, nil)
+		if err != nil {
+			return err
+		}
+		if err := writeReport(data); err != nil {
+			return err
+		}
+	}
+
+	if atcFailPriority > 0 {
+		if n := worklist.CountFindings(atcFailPriority); n > 0 {
+			return fmt.Errorf("%d new ATC findings with priority %d or higher", n, atcFailPriority)
+		}
+	}
+	return nil
+}
+
+func printATCFindings(worklist *adt.ATCWorklist, comparison *adt.ATCComparison) {
+	findings := worklist.Findings()
+	title := "ATC Findings"
+	if comparison != nil {
+		title = "New ATC Findings"
+	}
+	fmt.Printf("=== %s ===\n", title)
+	if len(findings) == 0 {
+		fmt.Println("No findings")
+	}
+	for _, f := range findings {
+		status := strings.ToUpper(f.Severity)
+		if f.Suppressed {
+			status = "EXEMPT"
+		}
+		fmt.Printf("  [%s] %s:%d %s (%s)\n", status, f.Path(testSrcDir), f.Line, f.Message, f.RuleID)
+	}
+
+	if comparison != nil && len(comparison.Fixed) > 0 {
+		fmt.Println("\n=== Fixed ===")
+		for _, f := range comparison.Fixed {
+			fmt.Printf("  [P%d] %s %s (%s)\n", f.Priority, f.Object, f.Message, f.CheckID)
+		}
+	}
+	sum := worklist.Summary()
+	if len(sum.Objects) > 1 {
+		fmt.Println("\n=== By Object ===")
+		for _, o := range sum.Objects {
+			fmt.Printf("  %-40s %3d errors %3d warnings %3d infos\n", o.Type+" "+o.Name, o.Errors, o.Warnings, o.Infos)
+		}
+	}
+	fmt.Printf("\n%d findings (%d errors, %d warnings, %d infos) in %d objects\n",
+		sum.TotalFindings, sum.Errors, sum.Warnings, sum.Infos, sum.TotalObjects)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/oisee/vibing-steampunk/pkg/adt"
 	"github.com/oisee/vibing-steampunk/pkg/config"
+	"github.com/oisee/vibing-steampunk/pkg/dsl"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +27,7 @@ func init() {
 	rootCmd.AddCommand(exportCmd)
 	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(sourceCmd)
+	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(systemsCmd)
 }
 
@@ -39,6 +41,7 @@ type systemParams struct {
 	Insecure     bool
 	CookieFile   string
 	CookieString string
+	UI5Backend   string
 }
 
 // resolveSystemParams resolves system parameters from --system flag or env vars.
@@ -78,6 +81,7 @@ func resolveSystemParams(cmd *cobra.Command) (*systemParams, error) {
 			Insecure:     sys.Insecure,
 			CookieFile:   sys.CookieFile,
 			CookieString: sys.CookieString,
+			UI5Backend:   sys.UI5Backend,
 		}, nil
 	}
 
@@ -94,12 +98,13 @@ func resolveSystemParams(cmd *cobra.Command) (*systemParams, error) {
 	}
 
 	return &systemParams{
-		URL:      url,
-		User:     user,
-		Password: password,
-		Client:   getEnvOrDefault("SAP_CLIENT", "001"),
-		Language: getEnvOrDefault("SAP_LANGUAGE", "EN"),
-		Insecure: os.Getenv("SAP_INSECURE") == "true",
+		URL:        url,
+		User:       user,
+		Password:   password,
+		Client:     getEnvOrDefault("SAP_CLIENT", "001"),
+		Language:   getEnvOrDefault("SAP_LANGUAGE", "EN"),
+		Insecure:   os.Getenv("SAP_INSECURE") == "true",
+		UI5Backend: os.Getenv("SAP_UI5_BACKEND"),
 	}, nil
 }
 
@@ -112,6 +117,9 @@ func getClient(params *systemParams) (*adt.Client, error) {
 	if params.Insecure {
 		opts = append(opts, adt.WithInsecureSkipVerify())
 	}
+	if params.UI5Backend != "" {
+		opts = append(opts, adt.WithUI5Backend(params.UI5Backend))
+	}
 
 	// Use cookie auth if available
 	if params.CookieFile != "" {
@@ -160,20 +168,35 @@ func getEnvOrDefault(key, defaultVal string) string {
 
 var exportCmd = &cobra.Command{
 	Use:   "export <packages...>",
-	Short: "Export packages to ZIP (abapGit format)",
+	Short: "Export packages or a transport (abapGit format)",
 	Long: `Export one or more packages to a ZIP file in abapGit-compatible format.
 
+With --transport, all objects of a transport request and its tasks are
+written to a directory (default: the transport number) in abapGit layout,
+ready for a Git diff. A transport-manifest.json lists the entries that could
+not be serialized. Objects are serialized by abapGit via ZADT_VSP; if the
+WebSocket is not available (or with --source), only their source is exported
+via ADT.
+
 Examples:
   vsp -s a4h export '$ZORK' '$ZLLM' -o packages.zip
   vsp export '$TMP' --output my-package.zip
-  vsp -s dev export 'Z*' --subpackages`,
-	Args: cobra.MinimumNArgs(1),
+  vsp -s dev export 'Z*' --subpackages
+  vsp -s dev export --transport A4HK900123 -o review/a4hk900123`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if transport, _ := cmd.Flags().GetString("transport"); transport != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: runExport,
 }
 
 func init() {
-	exportCmd.Flags().StringVarP(&outputFile, "output", "o", "export.zip", "Output ZIP file path")
+	exportCmd.Flags().StringVarP(&outputFile, "output", "o", "export.zip", "Output ZIP file path (directory with --transport)")
 	exportCmd.Flags().BoolP("subpackages", "r", true, "Include subpackages")
+	exportCmd.Flags().String("transport", "", "Export all objects of a transport request into a directory")
+	exportCmd.Flags().Bool("source", false, "With --transport: export source via ADT only (no ZADT_VSP)")
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
@@ -183,6 +206,9 @@ func runExport(cmd *cobra.Command, args []string) error {
 	}
 
 	ctx := context.Background()
+	if transport, _ := cmd.Flags().GetString("transport"); transport != "" {
+		return runExportTransport(ctx, cmd, params, transport)
+	}
 	wsClient, err := getWSClient(ctx, params)
 	if err != nil {
 		return err
@@ -209,6 +235,45 @@ func runExport(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runExportTransport(ctx context.Context, cmd *cobra.Command, params *systemParams, transport string) error {
+	client, err := getClient(params)
+	if err != nil {
+		return err
+	}
+
+	dir := ""
+	if cmd.Flags().Changed("output") {
+		dir = outputFile
+	}
+
+	var wsClient *adt.AMDPWebSocketClient
+	if sourceOnly, _ := cmd.Flags().GetBool("source"); !sourceOnly {
+		wsClient, err = getWSClient(ctx, params)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "abapGit export not available (%v), exporting source via ADT\n", err)
+			wsClient = nil
+		} else {
+			defer wsClient.Close()
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Exporting transport %s\n", strings.ToUpper(transport))
+	manifest, err := dsl.ExportTransport(ctx, client, wsClient, transport, dir)
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	if dir == "" {
+		dir = strings.ToLower(manifest.Transport)
+	}
+	fmt.Println(dsl.TransportExportSummary(manifest))
+	for _, skip := range manifest.NotSerialized {
+		fmt.Printf("  not serialized: %s %s %s (%s)\n", skip.PgmID, skip.Type, skip.Name, skip.Reason)
+	}
+	fmt.Printf("Written to %s (see %s)\n", dir, adt.TransportExportManifestFile)
+	return nil
+}
+
 // --- search command ---
 
 var searchCmd = &cobra.Command{
@@ -304,6 +369,44 @@ func runSource(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// --- run command ---
+
+var runCmd = &cobra.Command{
+	Use:   "run <class>",
+	Short: "Run an ABAP console class",
+	Long: `Run a class implementing IF_OO_ADT_CLASSRUN and print its console output.
+
+Examples:
+  vsp -s a4h run ZCL_MY_RUNNER
+  vsp run ZCL_DEMO_CONSOLE`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRun,
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	params, err := resolveSystemParams(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient(params)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	console, err := client.RunClass(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to run class: %w", err)
+	}
+
+	fmt.Print(console)
+	if console != "" && !strings.HasSuffix(console, "\n") {
+		fmt.Println()
+	}
+	return nil
+}
+
 // --- systems command ---
 
 var systemsCmd = &cobra.Command{
@@ -472,6 +472,9 @@ func runVspToMcp(cmd *cobra.Command, args []string) error {
 		if len(sys.AllowedPackages) > 0 {
 			serverArgs = append(serverArgs, "--allowed-packages", strings.Join(sys.AllowedPackages, ","))
 		}
+		if sys.UI5Backend != "" {
+			serverArgs = append(serverArgs, "--ui5-backend", sys.UI5Backend)
+		}
 
 		// Build env block - only add password placeholder if using user auth
 		envBlock := make(map[string]string)
@@ -757,6 +760,7 @@ func GetAllToolNames() []string {
 		"GetFunctionGroup", "GetInclude", "GetTable", "GetTableContents",
 		"GetStructure", "GetPackage", "GetMessages", "GetTransaction", "GetTypeInfo",
 		"GetClassInfo", "GetClassComponents", "GetClassInclude", "GetCDSDependencies",
+		"GetCDSElementInfo", "GetCDSAnnotations", "PreviewCDS",
 		// Core write tools
 		"WriteSource", "WriteClass", "WriteProgram", "EditSource", "UpdateSource",
 		"CreateObject", "DeleteObject", "CloneObject", "RenameObject", "MoveObject",
@@ -766,7 +770,7 @@ func GetAllToolNames() []string {
 		// Development tools
 		"SyntaxCheck", "Activate", "ActivatePackage", "PrettyPrint",
 		"GetPrettyPrinterSettings", "SetPrettyPrinterSettings",
-		"RunUnitTests", "RunATCCheck", "GetATCCustomizing",
+		"RunUnitTests", "RunClass", "RunATCCheck", "GetATCCustomizing",
 		"GetInactiveObjects", "CreatePackage", "CreateTable",
 		"CompareSource", "CreateClassWithTests", "CreateTestInclude",
 		"CreateAndActivateProgram", "UpdateClassInclude",
@@ -777,14 +781,17 @@ func GetAllToolNames() []string {
 		"AnalyzeCallGraph", "CompareCallGraphs", "TraceExecution",
 		// System info
 		"GetSystemInfo", "GetInstalledComponents", "GetConnectionInfo", "GetFeatures",
+		// ABAP Cloud
+		"GetAPIReleaseState", "CheckReleasedAPIs",
 		// Dumps / traces
 		"ListDumps", "GetDump", "ListTraces", "GetTrace",
 		"GetSQLTraceState", "ListSQLTraces",
 		// File I/O
 		"ImportFromFile", "ExportToFile", "DeployFromFile", "SaveToFile",
 		// Transport
-		"ListTransports", "GetTransport", "GetTransportInfo", "GetUserTransports",
+		"ListTransports", "GetTransport", "GetTransportInfo", "GetUserTransports", "PreReleaseCheck",
 		"CreateTransport", "ReleaseTransport", "DeleteTransport",
+		"AddTransportObjects", "RemoveTransportObjects", "MergeTransportTasks", "CreateTransportOfCopies",
 		// Report execution (requires ZADT_VSP)
 		"RunReport", "RunReportAsync", "GetAsyncResult",
 		"GetVariants", "GetTextElements", "SetTextElements",
@@ -804,8 +811,9 @@ func GetAllToolNames() []string {
 		// UI5/BSP
 		"UI5ListApps", "UI5GetApp", "UI5GetFileContent",
 		"UI5CreateApp", "UI5DeleteApp", "UI5DeleteFile", "UI5UploadFile",
-		// Service binding
-		"PublishServiceBinding", "UnpublishServiceBinding",
+		"UI5Pull", "UI5Push",
+		// Service binding / RAP
+		"PublishServiceBinding", "UnpublishServiceBinding", "GenerateRAPStack",
 	}
 }
 
@@ -821,10 +829,11 @@ func GetFocusedToolNames() []string {
 		// Data/Metadata read
 		"GetTable", "GetTableContents", "RunQuery",
 		"GetPackage", "GetFunctionGroup", "GetCDSDependencies", "GetMessages",
+		"GetCDSElementInfo", "GetCDSAnnotations", "PreviewCDS",
 		// Code intelligence
 		"FindDefinition", "FindReferences",
 		// Development tools
-		"SyntaxCheck", "RunUnitTests", "RunATCCheck",
+		"SyntaxCheck", "RunUnitTests", "RunClass", "RunATCCheck",
 		"Activate", "ActivatePackage", "PrettyPrint",
 		"GetInactiveObjects", "CreatePackage", "CreateTable",
 		"CompareSource", "CloneObject", "GetClassInfo",
@@ -834,6 +843,8 @@ func GetFocusedToolNames() []string {
 		"ImportFromFile", "ExportToFile",
 		// System info
 		"GetSystemInfo", "GetInstalledComponents", "GetConnectionInfo", "GetFeatures",
+		// ABAP Cloud
+		"GetAPIReleaseState", "CheckReleasedAPIs",
 		// Code analysis
 		"GetCallGraph", "GetObjectStructure", "GetCallersOf", "GetCalleesOf",
 		"AnalyzeCallGraph", "CompareCallGraphs", "TraceExecution",
@@ -880,6 +891,17 @@ SAP_MODE=focused
 # SAP_FEATURE_AMDP=auto
 # SAP_FEATURE_UI5=auto
 # SAP_FEATURE_TRANSPORT=auto
+# SAP_FEATURE_CLOUD=auto
+# SAP_FEATURE_ZADT_VSP=auto
+# SAP_FEATURE_ATC=auto
+# SAP_FEATURE_REFACTORING=auto
+# SAP_FEATURE_COVERAGE=auto
+# SAP_FEATURE_ENHANCEMENTS=auto
+
+# Capability profile: probe results cached per system (off unless a directory
+# is set; a negative TTL disables it)
+# SAP_CAPABILITY_DIR=.vsp-capabilities
+# SAP_CAPABILITY_TTL=24h
 `
 
 var vspSystemsExample = func() string {
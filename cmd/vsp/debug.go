@@ -30,7 +30,10 @@ Commands:
   c, continue  Continue execution
   r, stack     Show call stack
   v, vars      Show local variables
-  b <prog> <line>  Set breakpoint
+  p <expr>     Evaluate expression
+  t <table>    Page through internal table
+  b <prog> <line> [if <cond>] [hits <n>]  Set breakpoint
+  b watch <var> [to <value>] [if <cond>]  Set watchpoint (attached)
   d <id>       Delete breakpoint
   l            List breakpoints
   q, quit      Detach and exit
@@ -258,6 +261,16 @@ func (s *debugSession) repl() error {
 				fmt.Printf("Error: %v\n", err)
 			}
 
+		case "p", "print", "eval":
+			if err := s.evaluate(args); err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+
+		case "t", "table":
+			if err := s.showTable(args); err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+
 		case "b", "break", "bp":
 			if err := s.setBreakpoint(args); err != nil {
 				fmt.Printf("Error: %v\n", err)
@@ -307,10 +320,16 @@ Commands:
   Inspection:
     r, stack     Show call stack
     v, vars      Show local variables
+    p <expr>     Evaluate expression (<fs>, ls-comp, lo->attr, lt[ 5 ]-comp)
+    t <table> [from <n>] [max <n>] [cols <a,b>] [where <cond>]
+                 Page through internal table rows (default: first 20)
 
   Breakpoints:
-    b <prog> <line>  Set line breakpoint
-    d <id>           Delete breakpoint
+    b <prog> <line> [if <cond>] [hits <n>]
+                     Set line breakpoint (optional condition / hit count)
+    b watch <var> [to <value>] [if <cond>]
+                     Set watchpoint: stop when <var> changes (needs attach)
+    d <id>           Delete breakpoint or watchpoint
     l, list          List all breakpoints
 
   Trigger:
@@ -373,11 +392,55 @@ func (s *debugSession) attach() error {
 	s.debuggeeID = result.Debuggee.ID
 
 	fmt.Printf("Attached! Session: %s\n", attachResult.DebugSessionID)
-	fmt.Printf("%s:%d\n", result.Debuggee.Program, result.Debuggee.Line)
+	if s.resolveStop() {
+		s.showPosition()
+	}
 
 	return nil
 }
 
+// resolveStop applies the conditions, hit counts and log points of the
+// breakpoints set in this session to the current stop, continuing past hits
+// that do not count. Returns false if the debuggee ran to the end.
+func (s *debugSession) resolveStop() bool {
+	if s.wsClient == nil {
+		return true
+	}
+	frame, err := s.client.DebuggerCurrentFrame(s.ctx)
+	if err != nil {
+		fmt.Printf("Warning: breakpoint conditions not applied: %v\n", err)
+		return true
+	}
+
+	s.wsClient.SetLogPointHandler(func(e adt.LogPointEntry) {
+		fmt.Printf("log %s\n", e.String())
+	})
+	defer s.wsClient.SetLogPointHandler(nil)
+
+	hit, err := s.wsClient.ContinueToStopIn(s.ctx, s.client.BreakpointTarget(), frame)
+	switch {
+	case err != nil:
+		fmt.Printf("Warning: breakpoint conditions not applied: %v\n", err)
+	case hit == nil:
+		s.attached = false
+		s.debuggeeID = ""
+		fmt.Println("Debuggee terminated (no breakpoint stop met its condition or hit count)")
+		return false
+	case hit.BreakpointID != "":
+		fmt.Printf("Breakpoint %s, hit #%d\n", hit.BreakpointID, hit.Hits)
+	}
+	return true
+}
+
+// showPosition prints the current stack position.
+func (s *debugSession) showPosition() {
+	stack, err := s.client.DebuggerGetStack(s.ctx, false)
+	if err == nil && len(stack.Stack) > 0 {
+		top := stack.Stack[0]
+		fmt.Printf("%s:%d  %s\n", top.ProgramName, top.Line, top.EventName)
+	}
+}
+
 func (s *debugSession) detach() {
 	if !s.attached {
 		fmt.Println("Not attached")
@@ -418,13 +481,12 @@ func (s *debugSession) step(stepType adt.DebugStepType) error {
 		return nil
 	}
 
-	// Show current position
-	stack, err := s.client.DebuggerGetStack(s.ctx, false)
-	if err == nil && len(stack.Stack) > 0 {
-		top := stack.Stack[0]
-		fmt.Printf("%s:%d  %s\n", top.ProgramName, top.Line, top.EventName)
+	// Continuing runs into the next breakpoint: apply its condition and hit count
+	if stepType == adt.DebugStepContinue && !s.resolveStop() {
+		return nil
 	}
 
+	s.showPosition()
 	return nil
 }
 
@@ -494,15 +556,119 @@ func (s *debugSession) showVariables() error {
 	return nil
 }
 
+func (s *debugSession) evaluate(args []string) error {
+	if !s.attached {
+		return fmt.Errorf("not attached - use 'attach' first")
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("usage: p <expression>")
+	}
+
+	result, err := s.client.DebuggerEvaluateExpression(s.ctx, strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	v := result.Variable
+	value := v.Value
+	if v.MetaType == adt.DebugMetaTypeTable {
+		value = fmt.Sprintf("<%d rows>", v.TableLines)
+	}
+	fmt.Printf("%s = %s (%s)\n", result.Expression, value, v.DeclaredTypeName)
+	for _, c := range result.Components {
+		value := c.Value
+		if c.MetaType == adt.DebugMetaTypeTable {
+			value = fmt.Sprintf("<%d rows>", c.TableLines)
+		}
+		fmt.Printf("  %s = %s\n", c.Name, value)
+	}
+	return nil
+}
+
+func (s *debugSession) showTable(args []string) error {
+	if !s.attached {
+		return fmt.Errorf("not attached - use 'attach' first")
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("usage: t <table> [from <n>] [max <n>] [cols <a,b>] [where <condition>]")
+	}
+
+	opts := adt.DebugTableReadOptions{Limit: 20}
+	for i := 1; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "from", "max", "cols":
+			if i+1 >= len(args) {
+				return fmt.Errorf("missing value after '%s'", args[i])
+			}
+			key, val := strings.ToLower(args[i]), args[i+1]
+			i++
+			if key == "cols" {
+				opts.Columns = strings.Split(val, ",")
+				continue
+			}
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid number: %s", val)
+			}
+			if key == "from" {
+				opts.Offset = n
+			} else {
+				opts.Limit = n
+			}
+		case "where":
+			opts.Filter = strings.Join(args[i+1:], " ")
+			i = len(args)
+		default:
+			return fmt.Errorf("unknown option: %s", args[i])
+		}
+	}
+
+	page, err := s.client.DebuggerReadTable(s.ctx, args[0], opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s: %d lines\n", page.Table, page.TotalLines)
+	if len(page.Rows) == 0 {
+		fmt.Println("  (no rows)")
+	} else {
+		fmt.Printf("  %6s  %s\n", "#", strings.Join(page.Columns, " | "))
+		for _, row := range page.Rows {
+			values := make([]string, len(page.Columns))
+			for i, col := range page.Columns {
+				values[i] = row.Values[col]
+			}
+			fmt.Printf("  %6d  %s\n", row.Index, strings.Join(values, " | "))
+		}
+	}
+	if page.HasMore {
+		fmt.Printf("  ... more rows, continue with 'from %d'\n", page.NextOffset)
+	}
+	fmt.Println()
+	return nil
+}
+
 func (s *debugSession) setBreakpoint(args []string) error {
-	if len(args) < 2 {
-		return fmt.Errorf("usage: b <program> <line>")
+	positional, opts, value, err := parseBreakpointArgs(args)
+	if err != nil {
+		return err
 	}
 
-	program := strings.ToUpper(args[0])
-	line, err := strconv.Atoi(args[1])
+	if len(positional) > 0 && (strings.EqualFold(positional[0], "watch") || strings.EqualFold(positional[0], "w")) {
+		if len(positional) < 2 {
+			return fmt.Errorf("usage: b watch <variable> [to <value>] [if <condition>]")
+		}
+		return s.setWatchpoint(strings.ToUpper(positional[1]), value, opts)
+	}
+
+	if len(positional) < 2 {
+		return fmt.Errorf("usage: b <program> <line> [if <condition>] [hits <n>]")
+	}
+
+	program := strings.ToUpper(positional[0])
+	line, err := strconv.Atoi(positional[1])
 	if err != nil {
-		return fmt.Errorf("invalid line number: %s", args[1])
+		return fmt.Errorf("invalid line number: %s", positional[1])
 	}
 
 	// Try WebSocket first (preferred)
@@ -510,17 +676,122 @@ func (s *debugSession) setBreakpoint(args []string) error {
 		ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 		defer cancel()
 
-		bpID, err := s.wsClient.SetLineBreakpoint(ctx, program, line)
+		bpID, err := s.wsClient.SetBreakpoint(ctx, adt.WSBreakpointSpec{
+			Kind:    "line",
+			Program: program,
+			Line:    line,
+			Options: opts,
+		})
 		if err != nil {
 			return fmt.Errorf("set breakpoint failed: %w", err)
 		}
-		fmt.Printf("Breakpoint %s set at %s:%d\n", bpID, program, line)
+		fmt.Printf("Breakpoint %s set at %s:%d%s\n", bpID, program, line, describeBreakpointOptions(opts))
 		return nil
 	}
 
 	return fmt.Errorf("WebSocket not connected - cannot set breakpoints")
 }
 
+// setWatchpoint sets a watchpoint in the attached debug session.
+func (s *debugSession) setWatchpoint(variable, value string, opts adt.BreakpointOptions) error {
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	switch {
+	case s.attached:
+		if err := adt.CheckWatchpointOptions(opts); err != nil {
+			return err
+		}
+		wp, err := s.client.DebuggerSetWatchpoint(ctx, variable, adt.WatchpointCondition(variable, value, opts.Condition))
+		if err != nil {
+			return fmt.Errorf("set watchpoint failed: %w", err)
+		}
+		fmt.Printf("Watchpoint %s set on %s%s\n", wp.ID, variable, describeWatchTarget(value, opts))
+	case s.wsClient != nil && s.wsClient.IsAttached():
+		wpID, err := s.wsClient.SetWatchpoint(ctx, variable, value, opts)
+		if err != nil {
+			return fmt.Errorf("set watchpoint failed: %w", err)
+		}
+		fmt.Printf("Watchpoint %s set on %s%s\n", wpID, variable, describeWatchTarget(value, opts))
+	default:
+		return fmt.Errorf("not attached - watchpoints need a debug session, use 'attach' first")
+	}
+	return nil
+}
+
+// parseBreakpointArgs splits breakpoint command arguments into positional
+// arguments and modifiers: "if <condition...>", "hits <n>", "to <value>".
+func parseBreakpointArgs(args []string) ([]string, adt.BreakpointOptions, string, error) {
+	var positional []string
+	var opts adt.BreakpointOptions
+	var value string
+
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "if":
+			var cond []string
+			for i+1 < len(args) && !isBreakpointKeyword(args[i+1]) {
+				i++
+				cond = append(cond, args[i])
+			}
+			if len(cond) == 0 {
+				return nil, opts, "", fmt.Errorf("missing condition after 'if'")
+			}
+			opts.Condition = strings.Join(cond, " ")
+		case "hits":
+			if i+1 >= len(args) {
+				return nil, opts, "", fmt.Errorf("missing count after 'hits'")
+			}
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 1 {
+				return nil, opts, "", fmt.Errorf("invalid hit count: %s", args[i])
+			}
+			opts.HitCount = n
+		case "to":
+			if i+1 >= len(args) {
+				return nil, opts, "", fmt.Errorf("missing value after 'to'")
+			}
+			i++
+			value = args[i]
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+
+	return positional, opts, value, nil
+}
+
+func isBreakpointKeyword(arg string) bool {
+	switch strings.ToLower(arg) {
+	case "hits", "to":
+		return true
+	}
+	return false
+}
+
+func describeBreakpointOptions(opts adt.BreakpointOptions) string {
+	var parts []string
+	if opts.Condition != "" {
+		parts = append(parts, "if "+opts.Condition)
+	}
+	if opts.HitCount > 1 {
+		parts = append(parts, fmt.Sprintf("from hit #%d", opts.HitCount))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+func describeWatchTarget(value string, opts adt.BreakpointOptions) string {
+	desc := describeBreakpointOptions(opts)
+	if value != "" {
+		return " (changes to " + value + ")" + desc
+	}
+	return desc
+}
+
 func (s *debugSession) deleteBreakpoint(args []string) error {
 	if len(args) < 1 {
 		return fmt.Errorf("usage: d <breakpoint-id>")
@@ -533,16 +804,42 @@ func (s *debugSession) deleteBreakpoint(args []string) error {
 		defer cancel()
 
 		if err := s.wsClient.DeleteBreakpoint(ctx, bpID); err != nil {
+			// Session-bound REST watchpoints are not known to ZADT_VSP
+			if s.attached && s.client.DebuggerDeleteWatchpoint(ctx, bpID) == nil {
+				fmt.Printf("Watchpoint %s deleted\n", bpID)
+				return nil
+			}
 			return fmt.Errorf("delete breakpoint failed: %w", err)
 		}
 		fmt.Printf("Breakpoint %s deleted\n", bpID)
 		return nil
 	}
 
+	if s.attached {
+		if err := s.client.DebuggerDeleteWatchpoint(s.ctx, bpID); err != nil {
+			return fmt.Errorf("delete watchpoint failed: %w", err)
+		}
+		fmt.Printf("Watchpoint %s deleted\n", bpID)
+		return nil
+	}
+
 	return fmt.Errorf("WebSocket not connected - cannot delete breakpoints")
 }
 
 func (s *debugSession) listBreakpoints() error {
+	if s.attached {
+		if wps, err := s.client.DebuggerGetWatchpoints(s.ctx); err == nil && len(wps) > 0 {
+			fmt.Println("\nWatchpoints:")
+			for _, wp := range wps {
+				cond := ""
+				if wp.Condition != "" {
+					cond = " if " + wp.Condition
+				}
+				fmt.Printf("  %s: %s = %s%s\n", wp.ID, wp.Variable, wp.CurrentValue, cond)
+			}
+		}
+	}
+
 	if s.wsClient != nil {
 		ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
 		defer cancel()
@@ -562,7 +859,14 @@ func (s *debugSession) listBreakpoints() error {
 			id, _ := bp["id"].(string)
 			program, _ := bp["program"].(string)
 			line, _ := bp["line"].(float64)
-			fmt.Printf("  %s: %s:%d\n", id, program, int(line))
+			if variable, ok := bp["variable"].(string); ok {
+				fmt.Printf("  %s: watch %s\n", id, variable)
+				continue
+			}
+			cond, _ := bp["condition"].(string)
+			hitCount, _ := bp["hitCount"].(int)
+			fmt.Printf("  %s: %s:%d%s\n", id, program, int(line),
+				describeBreakpointOptions(adt.BreakpointOptions{Condition: cond, HitCount: hitCount}))
 		}
 		fmt.Println()
 		return nil
@@ -695,6 +999,9 @@ func (s *debugSession) runProgram(args []string) error {
 		s.attached = true
 		s.debuggeeID = result.debuggee.ID
 		fmt.Printf("Attached! Session: %s\n", attachResult.DebugSessionID)
+		if s.resolveStop() {
+			s.showPosition()
+		}
 
 	case <-s.ctx.Done():
 		return s.ctx.Err()
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/oisee/vibing-steampunk/pkg/adt"
+	"github.com/spf13/cobra"
+)
+
+var dumpCmd = &cobra.Command{
+	Use:   "dump <dump-id>",
+	Short: "Analyze a short dump post-mortem",
+	Long: `Fetch a short dump (ST22) and show the call stack with source context
+around each frame and the variables at the crash point.
+
+With --post-mortem, vsp listens briefly for a post-mortem debuggee of the
+dump and reads the live stack and variables from it. Post-mortem debuggees
+only exist for dumps raised while a debug listener was active.
+
+The crash state is saved as an execution recording with a "dump" checkpoint,
+which can be replayed into a debug session with forceReplay().
+
+Examples:
+  vsp dump 20251205_123456_DEVELOPER
+  vsp dump 20251205_123456_DEVELOPER --post-mortem --context 10
+  vsp dump 20251205_123456_DEVELOPER --json --no-save`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDump,
+}
+
+var (
+	dumpPostMortem bool
+	dumpTimeout    int
+	dumpContext    int
+	dumpFrames     int
+	dumpJSON       bool
+	dumpNoSave     bool
+	dumpStorePath  string
+)
+
+func init() {
+	dumpCmd.Flags().BoolVar(&dumpPostMortem, "post-mortem", false, "Attach to a post-mortem debuggee if available")
+	dumpCmd.Flags().IntVarP(&dumpTimeout, "timeout", "t", 5, "Seconds to wait for the post-mortem debuggee")
+	dumpCmd.Flags().IntVarP(&dumpContext, "context", "c", 5, "Source lines before/after each frame line")
+	dumpCmd.Flags().IntVar(&dumpFrames, "frames", 10, "Number of frames to show source for")
+	dumpCmd.Flags().BoolVar(&dumpJSON, "json", false, "Output the analysis as JSON")
+	dumpCmd.Flags().BoolVar(&dumpNoSave, "no-save", false, "Do not save the execution recording")
+	dumpCmd.Flags().StringVar(&dumpStorePath, "store", ".vsp-recordings", "Recording store directory")
+
+	rootCmd.AddCommand(dumpCmd)
+}
+
+func runDump(cmd *cobra.Command, args []string) error {
+	resolveConfig(cmd.Parent())
+	if err := validateConfig(); err != nil {
+		return err
+	}
+	if err := processCookieAuth(cmd.Parent()); err != nil {
+		return err
+	}
+
+	client := createADTClient()
+	if dumpPostMortem {
+		adt.SetTerminalIDUser(cfg.Username)
+	}
+
+	analysis, err := client.AnalyzeDump(context.Background(), args[0], adt.DumpAnalysisOptions{
+		ContextLines:  dumpContext,
+		MaxFrames:     dumpFrames,
+		PostMortem:    dumpPostMortem,
+		ListenTimeout: dumpTimeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !dumpNoSave {
+		history, err := adt.NewHistoryManager(dumpStorePath)
+		if err != nil {
+			return err
+		}
+		if err := history.SaveRecording(analysis.Recording); err != nil {
+			return err
+		}
+	} else {
+		analysis.RecordingID = ""
+	}
+
+	if dumpJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(analysis)
+	}
+
+	printDumpAnalysis(analysis)
+	return nil
+}
+
+func printDumpAnalysis(a *adt.DumpAnalysis) {
+	d := a.Dump
+	fmt.Printf("Dump:       %s\n", d.ID)
+	if d.Category != "" {
+		fmt.Printf("Error:      %s\n", d.Category)
+	}
+	if d.ExceptionType != "" {
+		fmt.Printf("Exception:  %s\n", d.ExceptionType)
+	}
+	if text := d.ErrorDetails["shortText"]; text != "" {
+		fmt.Printf("Short text: %s\n", text)
+	}
+	switch {
+	case a.PostMortem:
+		fmt.Println("Source:     post-mortem debugger")
+	case a.PostMortemError != "":
+		fmt.Printf("Source:     dump text (post-mortem: %s)\n", a.PostMortemError)
+	}
+
+	fmt.Println()
+	if len(a.Frames) == 0 {
+		fmt.Println("No stack frames found in the dump.")
+	}
+	for i, f := range a.Frames {
+		fmt.Printf("#%d %s %s  %s:%d\n", i, f.EventType, f.Event, f.Include, f.Line)
+		if f.SourceError != "" {
+			fmt.Printf("   (source unavailable: %s)\n", f.SourceError)
+		}
+		for _, l := range f.Source {
+			marker := " "
+			if l.Current {
+				marker = ">"
+			}
+			fmt.Printf("  %s %5d  %s\n", marker, l.Line, l.Text)
+		}
+		fmt.Println()
+	}
+
+	if len(a.Variables) > 0 {
+		fmt.Println("Variables at crash point:")
+		for _, v := range a.Variables {
+			value := v.Value
+			if len(value) > 80 {
+				value = value[:77] + "..."
+			}
+			if v.Type != "" {
+				fmt.Printf("  %-30s %-20s = %s\n", v.Name, v.Type, value)
+			} else {
+				fmt.Printf("  %-30s = %s\n", v.Name, value)
+			}
+		}
+		fmt.Println()
+	}
+
+	if a.RecordingID != "" {
+		fmt.Printf("Recording saved: %s (checkpoint %q)\n", a.RecordingID, "dump")
+		fmt.Printf("Replay in a debug session: forceReplay(%q)\n", a.RecordingID)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/oisee/vibing-steampunk/pkg/adt"
+	"github.com/spf13/cobra"
+)
+
+// OAuth settings of BTP ABAP Environment systems (flags or SAP_* env vars)
+var (
+	serviceKeyPath    string
+	oauthRefreshToken string
+	oauthLoginPort    int
+)
+
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in to a BTP ABAP Environment system in the browser",
+	Long: `Run the OAuth login of a BTP ABAP Environment (Steampunk) system in the
+browser and print the refresh token. With the token in SAP_OAUTH_REFRESH_TOKEN
+(or --oauth-refresh-token) later starts need no browser login.
+
+The service key is created in the BTP cockpit or with
+"cf create-service-key"; its system URL is used unless --url is given.
+
+Examples:
+  vsp login --service-key abap-key.json
+  SAP_SERVICE_KEY=abap-key.json SAP_OAUTH_REFRESH_TOKEN=... vsp`,
+	Args: cobra.NoArgs,
+	RunE: runLogin,
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&serviceKeyPath, "service-key", "", "BTP ABAP Environment service key file (OAuth login, implies ABAP Cloud)")
+	rootCmd.Flags().StringVar(&oauthRefreshToken, "oauth-refresh-token", "", "OAuth refresh token from 'vsp login' (with --service-key)")
+
+	loginCmd.Flags().StringVar(&serviceKeyPath, "service-key", "", "BTP ABAP Environment service key file")
+	loginCmd.Flags().IntVar(&oauthLoginPort, "oauth-port", 3000, "Local port for the OAuth login redirect")
+
+	rootCmd.AddCommand(loginCmd)
+}
+
+func runLogin(cmd *cobra.Command, args []string) error {
+	resolveConfig(cmd.Parent())
+	if serviceKeyPath == "" {
+		return fmt.Errorf("service key required. Use --service-key or SAP_SERVICE_KEY")
+	}
+	if err := applyServiceKey(); err != nil {
+		return err
+	}
+
+	token, err := oauthLogin(cfg.OAuth)
+	if err != nil {
+		return err
+	}
+	if token.RefreshToken == "" {
+		return fmt.Errorf("login succeeded, but the authorization server returned no refresh token")
+	}
+	fmt.Fprintf(os.Stderr, "Logged in to %s\n", cfg.BaseURL)
+	fmt.Printf("SAP_OAUTH_REFRESH_TOKEN=%s\n", token.RefreshToken)
+	return nil
+}
+
+// applyServiceKey configures OAuth from the service key, if one is given: the
+// system URL (unless set explicitly), the OAuth client and ABAP Cloud mode.
+func applyServiceKey() error {
+	if serviceKeyPath == "" {
+		return nil
+	}
+	key, err := adt.LoadServiceKey(serviceKeyPath)
+	if err != nil {
+		return err
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = key.SystemURL()
+	}
+	cfg.OAuth = key.OAuthConfig()
+	cfg.OAuth.RefreshToken = oauthRefreshToken
+	if cfg.FeatureCloud == "" || cfg.FeatureCloud == "auto" {
+		cfg.FeatureCloud = "on"
+	}
+	if cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "[VERBOSE] Service key loaded from %s (system %s)\n", serviceKeyPath, key.SystemURL())
+	}
+	return nil
+}
+
+// oauthLogin runs the browser login and keeps the refresh token for the
+// client.
+func oauthLogin(oauth *adt.OAuthConfig) (*adt.OAuthToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	token, err := adt.OAuthLogin(ctx, oauth, oauthLoginPort, func(loginURL string) error {
+		fmt.Fprintf(os.Stderr, "Log in to the BTP ABAP Environment in your browser:\n  %s\n", loginURL)
+		if err := openBrowser(loginURL); err != nil && cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Could not open the browser: %v\n", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("OAuth login: %w", err)
+	}
+	oauth.RefreshToken = token.RefreshToken
+	return token, nil
+}
+
+// openBrowser opens a URL in the default browser.
+func openBrowser(url string) error {
+	switch runtime.GOOS {
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+	case "darwin":
+		return exec.Command("open", url).Start()
+	default:
+		return exec.Command("xdg-open", url).Start()
+	}
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
+	"github.com/oisee/vibing-steampunk/pkg/adt"
 	"github.com/oisee/vibing-steampunk/pkg/scripting"
 	"github.com/spf13/cobra"
 )
@@ -19,6 +21,7 @@ With a script file, executes the script.
 Available modules:
   - All MCP tools (searchObject, getSource, setBreakpoint, etc.)
   - Debug session management (listen, attach, stepOver, etc.)
+  - Log points via ZADT_VSP (setLogPoint, collectLogPoints)
   - Checkpoints (saveCheckpoint, injectCheckpoint)
   - JSON encoding/decoding
   - print, sleep utilities
@@ -69,6 +72,23 @@ func runLua(cmd *cobra.Command, args []string) error {
 	engine := scripting.NewLuaEngine(client)
 	defer engine.Close()
 
+	// Connect WebSocket for log points (optional - requires ZADT_VSP)
+	wsClient := adt.NewDebugWebSocketClient(
+		cfg.BaseURL,
+		cfg.Client,
+		cfg.Username,
+		cfg.Password,
+		cfg.InsecureSkipVerify,
+	)
+	if err := wsClient.Connect(context.Background()); err != nil {
+		if luaVerbose {
+			fmt.Fprintf(os.Stderr, "[LUA] WebSocket (ZADT_VSP) unavailable: %v\n", err)
+		}
+	} else {
+		defer wsClient.Close()
+		engine.SetDebugWebSocketClient(wsClient)
+	}
+
 	// Set output for verbose mode
 	if luaVerbose {
 		fmt.Fprintf(os.Stderr, "[LUA] Connected to: %s\n", cfg.BaseURL)
@@ -43,7 +43,10 @@ Examples:
 
   # Using cookie authentication
   vsp --url https://host:44300 --cookie-string "session=abc123; token=xyz"
-  vsp --url https://host:44300 --cookie-file cookies.txt`,
+  vsp --url https://host:44300 --cookie-file cookies.txt
+
+  # BTP ABAP Environment (OAuth via service key, ABAP Cloud mode)
+  vsp --service-key abap-key.json --oauth-refresh-token <token from 'vsp login'>`,
 	Version: fmt.Sprintf("%s (commit: %s, built: %s)", Version, Commit, BuildDate),
 	RunE:    runServer,
 }
@@ -93,6 +96,18 @@ func init() {
 	rootCmd.Flags().StringVar(&cfg.FeatureAMDP, "feature-amdp", "auto", "AMDP/HANA debugger: auto, on, off")
 	rootCmd.Flags().StringVar(&cfg.FeatureUI5, "feature-ui5", "auto", "UI5/Fiori BSP management: auto, on, off")
 	rootCmd.Flags().StringVar(&cfg.FeatureTransport, "feature-transport", "auto", "CTS transport management: auto, on, off")
+	rootCmd.Flags().StringVar(&cfg.FeatureCloud, "feature-cloud", "auto", "ABAP Cloud restrictions: auto, on, off")
+	rootCmd.Flags().StringVar(&cfg.FeatureZADTVSP, "feature-zadt-vsp", "auto", "ZADT_VSP WebSocket tools: auto, on, off")
+	rootCmd.Flags().StringVar(&cfg.FeatureATC, "feature-atc", "auto", "ATC tools: auto, on, off")
+	rootCmd.Flags().StringVar(&cfg.FeatureRefactoring, "feature-refactoring", "auto", "ADT refactoring tools: auto, on, off")
+	rootCmd.Flags().StringVar(&cfg.FeatureCoverage, "feature-coverage", "auto", "ABAP Unit code coverage: auto, on, off")
+	rootCmd.Flags().StringVar(&cfg.FeatureEnhancements, "feature-enhancements", "auto", "Enhancement framework: auto, on, off")
+
+	// Capability profile (feature probe results cached per system)
+	rootCmd.Flags().StringVar(&cfg.CapabilityProfileDir, "capability-dir", "", "Directory of the capability profiles, e.g. "+adt.DefaultCapabilityProfileDir+" (default: none, never cache or probe at start)")
+	rootCmd.Flags().DurationVar(&cfg.CapabilityProfileTTL, "capability-ttl", 0, "How long probed features are reused (default: 24h, negative: never cache or probe at start)")
+
+	rootCmd.Flags().StringVar(&cfg.UI5Backend, "ui5-backend", "", "UI5 deployment backend: odata (default) or filestore")
 
 	// Debugger configuration
 	rootCmd.Flags().StringVar(&cfg.TerminalID, "terminal-id", "", "SAP GUI terminal ID for cross-tool breakpoint sharing")
@@ -109,6 +124,8 @@ func init() {
 	viper.BindPFlag("insecure", rootCmd.Flags().Lookup("insecure"))
 	viper.BindPFlag("cookie-file", rootCmd.Flags().Lookup("cookie-file"))
 	viper.BindPFlag("cookie-string", rootCmd.Flags().Lookup("cookie-string"))
+	viper.BindPFlag("service-key", rootCmd.Flags().Lookup("service-key"))
+	viper.BindPFlag("oauth-refresh-token", rootCmd.Flags().Lookup("oauth-refresh-token"))
 	viper.BindPFlag("read-only", rootCmd.Flags().Lookup("read-only"))
 	viper.BindPFlag("block-free-sql", rootCmd.Flags().Lookup("block-free-sql"))
 	viper.BindPFlag("allowed-ops", rootCmd.Flags().Lookup("allowed-ops"))
@@ -129,6 +146,15 @@ func init() {
 	viper.BindPFlag("feature-amdp", rootCmd.Flags().Lookup("feature-amdp"))
 	viper.BindPFlag("feature-ui5", rootCmd.Flags().Lookup("feature-ui5"))
 	viper.BindPFlag("feature-transport", rootCmd.Flags().Lookup("feature-transport"))
+	viper.BindPFlag("feature-cloud", rootCmd.Flags().Lookup("feature-cloud"))
+	viper.BindPFlag("feature-zadt-vsp", rootCmd.Flags().Lookup("feature-zadt-vsp"))
+	viper.BindPFlag("feature-atc", rootCmd.Flags().Lookup("feature-atc"))
+	viper.BindPFlag("feature-refactoring", rootCmd.Flags().Lookup("feature-refactoring"))
+	viper.BindPFlag("feature-coverage", rootCmd.Flags().Lookup("feature-coverage"))
+	viper.BindPFlag("feature-enhancements", rootCmd.Flags().Lookup("feature-enhancements"))
+	viper.BindPFlag("capability-dir", rootCmd.Flags().Lookup("capability-dir"))
+	viper.BindPFlag("capability-ttl", rootCmd.Flags().Lookup("capability-ttl"))
+	viper.BindPFlag("ui5-backend", rootCmd.Flags().Lookup("ui5-backend"))
 
 	// Debugger configuration
 	viper.BindPFlag("terminal-id", rootCmd.Flags().Lookup("terminal-id"))
@@ -167,7 +193,9 @@ func runServer(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "[VERBOSE] SAP URL: %s\n", cfg.BaseURL)
 		fmt.Fprintf(os.Stderr, "[VERBOSE] SAP Client: %s\n", cfg.Client)
 		fmt.Fprintf(os.Stderr, "[VERBOSE] SAP Language: %s\n", cfg.Language)
-		if cfg.Username != "" {
+		if cfg.OAuth != nil {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Auth: OAuth (client: %s)\n", cfg.OAuth.ClientID)
+		} else if cfg.Username != "" {
 			fmt.Fprintf(os.Stderr, "[VERBOSE] Auth: Basic (user: %s)\n", cfg.Username)
 		} else if len(cfg.Cookies) > 0 {
 			fmt.Fprintf(os.Stderr, "[VERBOSE] Auth: Cookie (%d cookies)\n", len(cfg.Cookies))
@@ -360,6 +388,63 @@ func resolveConfig(cmd *cobra.Command) {
 			cfg.FeatureTransport = v
 		}
 	}
+	if !cmd.Flags().Changed("feature-cloud") {
+		if v := viper.GetString("FEATURE_CLOUD"); v != "" {
+			cfg.FeatureCloud = v
+		}
+	}
+	if !cmd.Flags().Changed("feature-zadt-vsp") {
+		if v := viper.GetString("FEATURE_ZADT_VSP"); v != "" {
+			cfg.FeatureZADTVSP = v
+		}
+	}
+	if !cmd.Flags().Changed("feature-atc") {
+		if v := viper.GetString("FEATURE_ATC"); v != "" {
+			cfg.FeatureATC = v
+		}
+	}
+	if !cmd.Flags().Changed("feature-refactoring") {
+		if v := viper.GetString("FEATURE_REFACTORING"); v != "" {
+			cfg.FeatureRefactoring = v
+		}
+	}
+	if !cmd.Flags().Changed("feature-coverage") {
+		if v := viper.GetString("FEATURE_COVERAGE"); v != "" {
+			cfg.FeatureCoverage = v
+		}
+	}
+	if !cmd.Flags().Changed("feature-enhancements") {
+		if v := viper.GetString("FEATURE_ENHANCEMENTS"); v != "" {
+			cfg.FeatureEnhancements = v
+		}
+	}
+
+	// Capability profile: flag > SAP_CAPABILITY_* env
+	if !cmd.Flags().Changed("capability-dir") {
+		if v := viper.GetString("CAPABILITY_DIR"); v != "" {
+			cfg.CapabilityProfileDir = v
+		}
+	}
+	if !cmd.Flags().Changed("capability-ttl") {
+		if v := viper.GetDuration("CAPABILITY_TTL"); v != 0 {
+			cfg.CapabilityProfileTTL = v
+		}
+	}
+
+	// UI5 backend: flag > SAP_UI5_BACKEND env
+	if !cmd.Flags().Changed("ui5-backend") {
+		if v := viper.GetString("UI5_BACKEND"); v != "" {
+			cfg.UI5Backend = v
+		}
+	}
+
+	// OAuth (BTP ABAP Environment): flag > SAP_SERVICE_KEY / SAP_OAUTH_REFRESH_TOKEN env
+	if serviceKeyPath == "" {
+		serviceKeyPath = viper.GetString("SERVICE_KEY")
+	}
+	if oauthRefreshToken == "" {
+		oauthRefreshToken = viper.GetString("OAUTH_REFRESH_TOKEN")
+	}
 
 	// Terminal ID for debugger: flag > SAP_TERMINAL_ID env
 	if !cmd.Flags().Changed("terminal-id") {
@@ -370,6 +455,11 @@ func resolveConfig(cmd *cobra.Command) {
 }
 
 func validateConfig() error {
+	// A service key provides the URL and OAuth client
+	if err := applyServiceKey(); err != nil {
+		return err
+	}
+
 	if cfg.BaseURL == "" {
 		return fmt.Errorf("SAP URL is required. Use --url flag or SAP_URL environment variable")
 	}
@@ -396,9 +486,12 @@ func processCookieAuth(cmd *cobra.Command) error {
 		cookieString = viper.GetString("COOKIE_STRING")
 	}
 
-	// Count authentication methods
+	// Count authentication methods; with OAuth, user and password are only
+	// used for the password grant
 	authMethods := 0
-	if cfg.Username != "" && cfg.Password != "" {
+	if cfg.OAuth != nil {
+		authMethods++
+	} else if cfg.Username != "" && cfg.Password != "" {
 		authMethods++
 	}
 	if cookieFile != "" {
@@ -409,11 +502,17 @@ func processCookieAuth(cmd *cobra.Command) error {
 	}
 
 	if authMethods > 1 {
-		return fmt.Errorf("only one authentication method can be used at a time (basic auth, cookie-file, or cookie-string)")
+		return fmt.Errorf("only one authentication method can be used at a time (basic auth, service-key, cookie-file, or cookie-string)")
 	}
 
 	if authMethods == 0 {
-		return fmt.Errorf("authentication required. Use --user/--password, --cookie-file, or --cookie-string")
+		return fmt.Errorf("authentication required. Use --user/--password, --service-key, --cookie-file, or --cookie-string")
+	}
+
+	// OAuth needs a refresh token or a technical user; the browser login
+	// only runs in 'vsp login'
+	if cfg.OAuth != nil && cfg.OAuth.RefreshToken == "" && cfg.Password == "" {
+		return fmt.Errorf("no OAuth refresh token for the service key. Run 'vsp login --service-key <file>' and set SAP_OAUTH_REFRESH_TOKEN (or --oauth-refresh-token), or use --user/--password for the password grant")
 	}
 
 	// Process cookie file
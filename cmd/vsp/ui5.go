@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/oisee/vibing-steampunk/pkg/adt"
+	"github.com/spf13/cobra"
+)
+
+var ui5Cmd = &cobra.Command{
+	Use:   "ui5",
+	Short: "Synchronize UI5/Fiori BSP applications with local folders",
+}
+
+var ui5PullCmd = &cobra.Command{
+	Use:   "pull <app> <dir>",
+	Short: "Download a BSP application into a folder",
+	Long: `Download all files of a UI5/Fiori BSP application into a local folder.
+Files with unchanged content are not rewritten. The file versions are
+recorded in .vsp-ui5-sync.json for later pushes.
+
+Examples:
+  vsp ui5 pull ZACME_APP ./zacme_app
+  vsp ui5 pull ZACME_APP ./zacme_app --dry-run`,
+	Args: cobra.ExactArgs(2),
+	RunE: runUI5Pull,
+}
+
+var ui5PushCmd = &cobra.Command{
+	Use:   "push <dir> [app]",
+	Short: "Upload a folder to a BSP application",
+	Long: `Upload a local folder to a UI5/Fiori BSP application. The folder is
+zipped and deployed through the UI5 ABAP repository OData service
+(/UI5/ABAP_REPOSITORY_SRV) like 'fiori deploy': the archive replaces the app
+and the repository's checks run on the server (--dry-run deploys in test
+mode, --unsafe disables safe mode). The app is created if it does not exist.
+
+The app name, package and transport default to the deploy-to-abap task of
+ui5-deploy.yaml or ui5.yaml; the app ID is read from manifest.json. For
+built projects the dist folder is uploaded (--source to choose another).
+
+With --backend filestore the files are written one by one through the ADT
+filestore instead: only changed files are uploaded, new files and folders
+are created and remote files that no longer exist locally are deleted
+(--keep-removed to skip). Changes are detected from the file versions and
+content hashes that pull and push record in .vsp-ui5-sync.json. Many releases reject filestore writes (HTTP 405).
+The backend can also come from ui5_backend of the --system profile,
+--ui5-backend or SAP_UI5_BACKEND.
+
+Examples:
+  vsp ui5 push ./zacme_app                          # uses ui5-deploy.yaml
+  vsp ui5 push ./zacme_app ZACME_APP --package '$ZACME'
+  vsp ui5 push ./zacme_app --transport A4HK900123 --dry-run
+  vsp -s dev ui5 push ./zacme_app --backend filestore`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runUI5Push,
+}
+
+var (
+	ui5Package     string
+	ui5Transport   string
+	ui5Source      string
+	ui5KeepRemoved bool
+	ui5Backend     string
+	ui5Unsafe      bool
+	ui5DryRun      bool
+	ui5JSON        bool
+)
+
+func init() {
+	for _, c := range []*cobra.Command{ui5PullCmd, ui5PushCmd} {
+		c.Flags().BoolVar(&ui5DryRun, "dry-run", false, "Only show what would change")
+		c.Flags().BoolVar(&ui5JSON, "json", false, "Print the result as JSON")
+	}
+	ui5PushCmd.Flags().StringVar(&ui5Package, "package", "", "Package for a new app (default from ui5-deploy.yaml)")
+	ui5PushCmd.Flags().StringVar(&ui5Transport, "transport", "", "Transport request (default from ui5-deploy.yaml)")
+	ui5PushCmd.Flags().StringVar(&ui5Source, "source", "", "Folder to upload, relative to <dir> (default: dist if built)")
+	ui5PushCmd.Flags().BoolVar(&ui5KeepRemoved, "keep-removed", false, "filestore: do not delete remote files missing locally")
+	ui5PushCmd.Flags().StringVar(&ui5Backend, "backend", "", "Deployment backend: odata or filestore (default from system profile, else odata)")
+	ui5PushCmd.Flags().BoolVar(&ui5Unsafe, "unsafe", false, "odata: disable the repository's safe-mode check")
+
+	ui5Cmd.AddCommand(ui5PullCmd)
+	ui5Cmd.AddCommand(ui5PushCmd)
+	rootCmd.AddCommand(ui5Cmd)
+}
+
+// ui5Client creates the client from the --system profile if given,
+// otherwise from flags and environment.
+func ui5Client(cmd *cobra.Command) (*adt.Client, error) {
+	if systemName != "" {
+		params, err := resolveSystemParams(cmd)
+		if err != nil {
+			return nil, err
+		}
+		return getClient(params)
+	}
+
+	resolveConfig(cmd.Parent().Parent())
+
+	if err := validateConfig(); err != nil {
+		return nil, err
+	}
+
+	if err := processCookieAuth(cmd.Parent().Parent()); err != nil {
+		return nil, err
+	}
+
+	return createADTClient(), nil
+}
+
+func runUI5Pull(cmd *cobra.Command, args []string) error {
+	client, err := ui5Client(cmd)
+	if err != nil {
+		return err
+	}
+	result, err := client.UI5Pull(context.Background(), args[0], args[1], adt.UI5SyncOptions{DryRun: ui5DryRun})
+	if err != nil {
+		return err
+	}
+	return printUI5SyncResult(result)
+}
+
+func runUI5Push(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("not a directory: %s", dir)
+	}
+	appName := ""
+	if len(args) > 1 {
+		appName = args[1]
+	}
+
+	client, err := ui5Client(cmd)
+	if err != nil {
+		return err
+	}
+	result, err := client.UI5Push(context.Background(), dir, appName, adt.UI5SyncOptions{
+		Package:     ui5Package,
+		Transport:   ui5Transport,
+		Source:      ui5Source,
+		KeepRemoved: ui5KeepRemoved,
+		DryRun:      ui5DryRun,
+		Backend:     ui5Backend,
+		UnsafeMode:  ui5Unsafe,
+	})
+	if result != nil && err != nil {
+		printUI5SyncResult(result)
+	}
+	if err != nil {
+		return err
+	}
+	return printUI5SyncResult(result)
+}
+
+func printUI5SyncResult(result *adt.UI5SyncResult) error {
+	if ui5JSON {
+		data, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	for _, p := range result.Created {
+		fmt.Printf("  + %s\n", p)
+	}
+	for _, p := range result.Updated {
+		fmt.Printf("  M %s\n", p)
+	}
+	for _, p := range result.Deleted {
+		fmt.Printf("  - %s\n", p)
+	}
+	for _, m := range result.Messages {
+		fmt.Printf("  %s\n", m)
+	}
+	fmt.Println(result.Summary())
+	return nil
+}
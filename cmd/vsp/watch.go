@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/oisee/vibing-steampunk/pkg/dsl"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch <dir>",
+	Short: "Deploy changed files and run affected unit tests on save",
+	Long: `Watch an abapGit source folder. When a file is saved, the object is
+deployed to SAP (created in --package if it does not exist), syntax checked
+and activated. If that succeeds, the unit tests of the object and of its
+direct callers are run.
+
+Results are printed to the terminal. With --events, every step is also
+written as one JSON object per line (use "-" for stdout).
+
+Examples:
+  vsp watch src --package '$ZRAY'
+  vsp watch src --package '$ZRAY' --no-callers
+  vsp watch src --transport A4HK900123 --events watch.jsonl`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWatch,
+}
+
+var (
+	watchPackage   string
+	watchTransport string
+	watchNoCallers bool
+	watchEvents    string
+	watchDebounce  time.Duration
+)
+
+func init() {
+	watchCmd.Flags().StringVar(&watchPackage, "package", "$TMP", "Package for new objects")
+	watchCmd.Flags().StringVar(&watchTransport, "transport", "", "Transport request for changes")
+	watchCmd.Flags().BoolVar(&watchNoCallers, "no-callers", false, "Run only the tests of the changed object")
+	watchCmd.Flags().StringVar(&watchEvents, "events", "", "Write events as JSON lines to file (- for stdout)")
+	watchCmd.Flags().DurationVar(&watchDebounce, "debounce", 300*time.Millisecond, "Wait until a file is unchanged for this long")
+
+	rootCmd.AddCommand(watchCmd)
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	resolveConfig(cmd.Parent())
+
+	if err := validateConfig(); err != nil {
+		return err
+	}
+
+	if err := processCookieAuth(cmd.Parent()); err != nil {
+		return err
+	}
+
+	dir := args[0]
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("not a directory: %s", dir)
+	}
+
+	var events *json.Encoder
+	switch watchEvents {
+	case "":
+	case "-":
+		events = json.NewEncoder(os.Stdout)
+	default:
+		f, err := os.OpenFile(watchEvents, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("opening event file: %w", err)
+		}
+		defer f.Close()
+		events = json.NewEncoder(f)
+	}
+
+	// Keep stdout clean for the event stream
+	var out io.Writer = os.Stdout
+	if watchEvents == "-" {
+		out = os.Stderr
+	}
+
+	watcher := dsl.Watch(createADTClient(), dir).
+		Package(watchPackage).
+		Transport(watchTransport).
+		Debounce(watchDebounce).
+		OnEvent(func(e dsl.WatchEvent) {
+			printWatchEvent(out, e)
+			if events != nil {
+				_ = events.Encode(e)
+			}
+		})
+	if watchNoCallers {
+		watcher.WithoutCallers()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(out, "Watching %s (Ctrl+C to stop)\n", dir)
+	return watcher.Run(ctx)
+}
+
+func printWatchEvent(w io.Writer, e dsl.WatchEvent) {
+	ts := e.Time.Format("15:04:05")
+	switch e.Kind {
+	case dsl.WatchEventDeploy:
+		fmt.Fprintf(w, "[%s] %s %s: %s\n", ts, statusString(e.Success), e.Object, e.Message)
+		for _, msg := range e.SyntaxErrors {
+			fmt.Fprintf(w, "    %s\n", msg)
+		}
+	case dsl.WatchEventTest:
+		fmt.Fprintf(w, "[%s] %s tests (%d objects): %s\n", ts, statusString(e.Success), len(e.TestedURLs), e.Message)
+		for _, f := range e.Failures {
+			fmt.Fprintf(w, "    %s\n", f)
+		}
+	default:
+		fmt.Fprintf(w, "[%s] ERROR %s: %s\n", ts, e.File, e.Message)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/oisee/vibing-steampunk/pkg/adt"
@@ -46,10 +47,19 @@ var workflowTestCmd = &cobra.Command{
 	Short: "Run unit tests for a package pattern",
 	Long: `Run ABAP Unit tests for all classes/programs matching a package pattern.
 
+With --coverage, statement/branch/procedure coverage is measured and can be
+exported as Cobertura XML or LCOV with file paths in abapGit layout.
+
+--format selects the output: text (default), json, junit, or the findings
+formats sarif, github (workflow annotations) and gitlab (code quality), which
+report failed tests at their abapGit file and line.
+
 Examples:
   vsp workflow test "$TMP"
   vsp workflow test "$ZRAY*"
-  vsp workflow test "ZCL_*" --parallel 4`,
+  vsp workflow test "ZCL_*" --parallel 4
+  vsp workflow test "$ZRAY*" --coverage --coverage-out coverage.xml --coverage-min 80
+  vsp workflow test "$ZRAY*" --format junit --out junit.xml`,
 	Args: cobra.ExactArgs(1),
 	RunE: runTestWorkflow,
 }
@@ -63,6 +73,15 @@ var (
 	testLong        bool
 	testStopOnFail  bool
 	outputJSON      bool
+
+	testCoverage       bool
+	testCoverageFormat string
+	testCoverageOut    string
+	testCoverageMin    float64
+	testSrcDir         string
+
+	reportFormat string
+	reportOut    string
 )
 
 func init() {
@@ -77,6 +96,13 @@ func init() {
 	workflowTestCmd.Flags().BoolVar(&testLong, "long", false, "Include long duration tests")
 	workflowTestCmd.Flags().BoolVar(&testStopOnFail, "stop-on-fail", false, "Stop on first failure")
 	workflowTestCmd.Flags().BoolVar(&outputJSON, "json", false, "Output results as JSON")
+	workflowTestCmd.Flags().BoolVar(&testCoverage, "coverage", false, "Measure code coverage")
+	workflowTestCmd.Flags().StringVar(&testCoverageFormat, "coverage-format", "cobertura", "Coverage report format: cobertura, lcov")
+	workflowTestCmd.Flags().StringVar(&testCoverageOut, "coverage-out", "", "Write coverage report to file")
+	workflowTestCmd.Flags().Float64Var(&testCoverageMin, "coverage-min", 0, "Fail if statement coverage is below this percentage")
+	workflowTestCmd.Flags().StringVar(&testSrcDir, "src-dir", "src", "abapGit source folder used for report file paths")
+	workflowTestCmd.Flags().StringVar(&reportFormat, "format", "text", "Output format: text, json, junit, sarif, github, gitlab")
+	workflowTestCmd.Flags().StringVar(&reportOut, "out", "", "Write the report to file instead of stdout")
 
 	workflowCmd.AddCommand(workflowRunCmd)
 	workflowCmd.AddCommand(workflowTestCmd)
@@ -158,6 +184,12 @@ func runTestWorkflow(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if outputJSON {
+		reportFormat = "json"
+	}
+	reportFormat = strings.ToLower(reportFormat)
+	quiet := reportFormat != "text"
+
 	// Create ADT client
 	client := createADTClient()
 
@@ -196,16 +228,19 @@ func runTestWorkflow(cmd *cobra.Command, args []string) error {
 	if testStopOnFail {
 		runner.StopOnFirstFailure()
 	}
+	if testCoverage || testCoverageOut != "" || testCoverageMin > 0 {
+		runner.WithCoverage()
+	}
 
 	// Add progress callbacks
 	runner.OnStart(func(obj dsl.ObjectRef) {
-		if !outputJSON {
+		if !quiet {
 			fmt.Fprintf(os.Stderr, "Testing: %s...\n", obj.Name)
 		}
 	})
 
 	runner.OnComplete(func(obj dsl.ObjectRef, result dsl.TestResult) {
-		if !outputJSON {
+		if !quiet {
 			status := "PASS"
 			if !result.Success {
 				status = "FAIL"
@@ -222,22 +257,84 @@ func runTestWorkflow(cmd *cobra.Command, args []string) error {
 	}
 
 	// Output results
-	if outputJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		enc.Encode(summary)
-	} else {
+	switch reportFormat {
+	case "text":
 		fmt.Fprintf(os.Stderr, "\n")
 		printTestSummary(summary)
+	case "json":
+		if err := writeJSONReport(summary); err != nil {
+			return err
+		}
+	default:
+		data, err := dsl.RenderReport(reportFormat, "ABAP Unit: "+packagePattern, testSrcDir, summary, nil, nil)
+		if err != nil {
+			return err
+		}
+		if err := writeReport(data); err != nil {
+			return err
+		}
+	}
+
+	if summary.Coverage != nil && testCoverageOut != "" {
+		if err := writeCoverageReport(summary.Coverage, testCoverageFormat, testCoverageOut); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Coverage report written to %s\n", testCoverageOut)
 	}
 
 	if summary.FailedTests > 0 {
 		return fmt.Errorf("%d tests failed", summary.FailedTests)
 	}
 
+	if testCoverageMin > 0 {
+		if summary.Coverage == nil {
+			return fmt.Errorf("no coverage measured")
+		}
+		if pct := summary.Coverage.Statement.Percent(); pct < testCoverageMin {
+			return fmt.Errorf("statement coverage %.1f%% is below %.1f%%", pct, testCoverageMin)
+		}
+	}
+
+	return nil
+}
+
+// writeReport writes a rendered report to --out or stdout.
+func writeReport(data []byte) error {
+	if reportOut == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(reportOut, data, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Report written to %s\n", reportOut)
 	return nil
 }
 
+func writeJSONReport(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeReport(append(data, '\n'))
+}
+
+func writeCoverageReport(report *adt.CoverageReport, format, path string) error {
+	var data []byte
+	switch strings.ToLower(format) {
+	case "cobertura", "":
+		var err error
+		if data, err = report.Cobertura(testSrcDir); err != nil {
+			return err
+		}
+	case "lcov":
+		data = report.LCOV(testSrcDir)
+	default:
+		return fmt.Errorf("unknown coverage format %q (expected cobertura or lcov)", format)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
 func createADTClient() *adt.Client {
 	opts := []adt.Option{
 		adt.WithClient(cfg.Client),
@@ -252,6 +349,18 @@ func createADTClient() *adt.Client {
 		opts = append(opts, adt.WithCookies(cfg.Cookies))
 	}
 
+	if cfg.UI5Backend != "" {
+		opts = append(opts, adt.WithUI5Backend(cfg.UI5Backend))
+	}
+
+	if cfg.OAuth != nil {
+		opts = append(opts, adt.WithOAuth(cfg.OAuth))
+	}
+
+	if cfg.FeatureCloud == "on" {
+		opts = append(opts, adt.WithCloudMode())
+	}
+
 	return adt.NewClient(cfg.BaseURL, cfg.Username, cfg.Password, opts...)
 }
 
@@ -290,6 +399,10 @@ func printTestSummary(summary *dsl.TestSummary) {
 	fmt.Printf("Tests:   %d total, %d passed, %d failed\n",
 		summary.TotalTests, summary.PassedTests, summary.FailedTests)
 	fmt.Printf("Time:    %v\n", summary.TotalTime.Round(time.Millisecond))
+	if cov := summary.Coverage; cov != nil {
+		fmt.Printf("Coverage: %.1f%% statements, %.1f%% branches, %.1f%% procedures\n",
+			cov.Statement.Percent(), cov.Branch.Percent(), cov.Procedure.Percent())
+	}
 
 	if summary.FailedTests > 0 {
 		fmt.Println("\nFailed tests:")
@@ -0,0 +1,131 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/oisee/vibing-steampunk/pkg/adt"
+)
+
+// featureTools lists the tools that need an optional feature. A tool is
+// hidden if the system reports one of its features as unavailable.
+var featureTools = map[adt.FeatureID][]string{
+	adt.FeatureAbapGit: {"GitTypes", "GitExport"},
+	adt.FeatureRAP:     {"PublishServiceBinding", "UnpublishServiceBinding", "GenerateRAPStack"},
+	adt.FeatureAMDP: {
+		"AMDPDebuggerStart", "AMDPDebuggerResume", "AMDPDebuggerStop",
+		"AMDPDebuggerStep", "AMDPGetVariables", "AMDPSetBreakpoint", "AMDPGetBreakpoints",
+	},
+	adt.FeatureUI5: {
+		"UI5ListApps", "UI5GetApp", "UI5GetFileContent", "UI5UploadFile", "UI5DeleteFile",
+		"UI5CreateApp", "UI5DeleteApp", "UI5Pull", "UI5Push",
+	},
+	adt.FeatureTransport: {
+		"ListTransports", "GetTransport", "PreReleaseCheck",
+		"CreateTransport", "ReleaseTransport", "DeleteTransport",
+		"AddTransportObjects", "RemoveTransportObjects", "MergeTransportTasks", "CreateTransportOfCopies",
+		"GetUserTransports", "GetTransportInfo",
+	},
+	adt.FeatureZADTVSP: {
+		// WebSocket breakpoints and RFC
+		"SetBreakpoint", "GetBreakpoints", "DeleteBreakpoint", "DebuggerCollectLogPoints", "CallRFC", "MoveObject",
+		// AMDP debugger session
+		"AMDPDebuggerStart", "AMDPDebuggerResume", "AMDPDebuggerStop",
+		"AMDPDebuggerStep", "AMDPGetVariables", "AMDPSetBreakpoint", "AMDPGetBreakpoints",
+		// abapGit serialization and report execution
+		"GitTypes", "GitExport",
+		"RunReport", "RunReportAsync", "GetVariants", "GetTextElements", "SetTextElements",
+	},
+	adt.FeatureATC:         {"RunATCCheck", "GetATCCustomizing", "ATCQuickFix", "ATCRequestExemption"},
+	adt.FeatureRefactoring: {"RenameSymbol", "ExtractMethod"},
+}
+
+// cloudRestrictedTools cannot work on ABAP Cloud systems: they create or run
+// classic programs, call RFC or deploy custom code outside ABAP Cloud.
+// ExecuteABAP stays: it uses a class runner in ABAP Cloud mode.
+var cloudRestrictedTools = []string{
+	"CreateAndActivateProgram", "WriteProgram", "GetTransaction", "CallRFC",
+	"RunReport", "RunReportAsync", "GetVariants", "GetTextElements", "SetTextElements",
+	"InstallZADTVSP", "InstallAbapGit", "InstallDummyTest",
+}
+
+// toolUnavailableReason returns why a tool cannot be served according to the
+// known feature status, or "" if nothing speaks against it. Failed probes
+// never hide a tool.
+func toolUnavailableReason(tool string, features map[adt.FeatureID]*adt.FeatureStatus) string {
+	if status := features[adt.FeatureCloud]; status != nil && status.Available {
+		for _, name := range cloudRestrictedTools {
+			if name == tool {
+				return fmt.Sprintf("not supported on ABAP Cloud (%s)", status.Message)
+			}
+		}
+	}
+	for _, id := range adt.AllFeatures {
+		status := features[id]
+		if status == nil || status.Available || status.Failed {
+			continue
+		}
+		for _, name := range featureTools[id] {
+			if name == tool {
+				return fmt.Sprintf("requires %s: %s", id, status.Message)
+			}
+		}
+	}
+	return ""
+}
+
+// applyCloudMode switches the ADT client to ABAP Cloud mode (released APIs
+// only, class runner instead of programs) once the system is known to be one.
+func (s *Server) applyCloudMode(features map[adt.FeatureID]*adt.FeatureStatus) {
+	if status := features[adt.FeatureCloud]; status != nil && !status.Failed {
+		s.adtClient.SetCloudMode(status.Available)
+	}
+}
+
+// hideUnavailableTools removes the registered tools that the system cannot
+// serve and records why. Hidden tools return after a restart once the
+// capability profile says the feature is available.
+func (s *Server) hideUnavailableTools(features map[adt.FeatureID]*adt.FeatureStatus) {
+	s.applyCloudMode(features)
+	s.toolsMu.Lock()
+	var names []string
+	for tool := range s.registeredTools {
+		if reason := toolUnavailableReason(tool, features); reason != "" {
+			delete(s.registeredTools, tool)
+			s.hiddenTools[tool] = reason
+			names = append(names, tool)
+		}
+	}
+	s.toolsMu.Unlock()
+
+	if len(names) > 0 {
+		sort.Strings(names)
+		s.mcpServer.DeleteTools(names...)
+		if s.config.Verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Hidden tools (not supported by the system): %v\n", names)
+		}
+	}
+}
+
+// probeCapabilities probes the features that the capability profile does not
+// answer and hides the tools the system cannot serve.
+func (s *Server) probeCapabilities() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	s.hideUnavailableTools(s.featureProber.ProbeAll(ctx))
+}
+
+// HiddenTools returns the tools hidden because the system cannot serve them,
+// with the reason.
+func (s *Server) HiddenTools() map[string]string {
+	s.toolsMu.Lock()
+	defer s.toolsMu.Unlock()
+	hidden := make(map[string]string, len(s.hiddenTools))
+	for tool, reason := range s.hiddenTools {
+		hidden[tool] = reason
+	}
+	return hidden
+}
@@ -289,3 +289,42 @@ func (s *Server) handleTraceExecution(ctx context.Context, request mcp.CallToolR
 	jsonResult, _ := json.MarshalIndent(output, "", "  ")
 	return mcp.NewToolResultText(string(jsonResult)), nil
 }
+
+func (s *Server) handleImpactAnalysis(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	objectURL, ok := request.Params.Arguments["object_url"].(string)
+	if !ok || objectURL == "" {
+		return newToolResultError("object_url is required"), nil
+	}
+
+	opts := adt.ImpactOptions{Cache: s.impactCache}
+	opts.Method, _ = request.Params.Arguments["method"].(string)
+	if depth, ok := request.Params.Arguments["max_depth"].(float64); ok && depth > 0 {
+		opts.MaxDepth = int(depth)
+	}
+	if max, ok := request.Params.Arguments["max_objects"].(float64); ok && max > 0 {
+		opts.MaxObjects = int(max)
+	}
+	if callers, ok := request.Params.Arguments["callers"].(bool); ok {
+		opts.SkipCallers = !callers
+	}
+	if refs, ok := request.Params.Arguments["references"].(bool); ok {
+		opts.SkipReferences = !refs
+	}
+
+	impact, err := s.adtClient.AnalyzeImpact(ctx, objectURL, opts)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("Impact analysis failed: %v", err)), nil
+	}
+
+	format, _ := request.Params.Arguments["format"].(string)
+	if format != "" && format != "json" {
+		text, err := adt.FormatImpact(impact, format)
+		if err != nil {
+			return newToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	}
+
+	result, _ := json.MarshalIndent(impact, "", "  ")
+	return mcp.NewToolResultText(string(result)), nil
+}
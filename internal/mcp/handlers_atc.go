@@ -5,7 +5,10 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/oisee/vibing-steampunk/pkg/adt"
@@ -13,10 +16,30 @@ import (
 
 // --- ATC Handlers ---
 
+// atcBaselineMaxResults is the default finding limit of a run with a baseline.
+const atcBaselineMaxResults = 10000
+
 func (s *Server) handleRunATCCheck(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	objectURL, ok := request.Params.Arguments["object_url"].(string)
-	if !ok || objectURL == "" {
-		return newToolResultError("object_url is required"), nil
+	objectURL, _ := request.Params.Arguments["object_url"].(string)
+	packageName, _ := request.Params.Arguments["package"].(string)
+	transport, _ := request.Params.Arguments["transport"].(string)
+	var objectURLs []string
+	if urls, ok := request.Params.Arguments["object_urls"].([]interface{}); ok {
+		for _, u := range urls {
+			if str, ok := u.(string); ok && str != "" {
+				objectURLs = append(objectURLs, str)
+			}
+		}
+	}
+
+	scopes := 0
+	for _, set := range []bool{objectURL != "", packageName != "", transport != "", len(objectURLs) > 0} {
+		if set {
+			scopes++
+		}
+	}
+	if scopes != 1 {
+		return newToolResultError("exactly one of object_url, object_urls, package or transport is required"), nil
 	}
 
 	variant := ""
@@ -24,45 +47,98 @@ func (s *Server) handleRunATCCheck(ctx context.Context, request mcp.CallToolRequ
 		variant = v
 	}
 
+	// A baseline needs every finding, so it is built from a larger run
+	baselinePath, _ := request.Params.Arguments["baseline"].(string)
+	updateBaseline, _ := request.Params.Arguments["update_baseline"].(bool)
 	maxResults := 100
+	if baselinePath != "" {
+		maxResults = atcBaselineMaxResults
+	}
 	if mr, ok := request.Params.Arguments["max_results"].(float64); ok && mr > 0 {
 		maxResults = int(mr)
 	}
 
-	result, err := s.adtClient.RunATCCheck(ctx, objectURL, variant, maxResults)
+	var result *adt.ATCWorklist
+	var setResult *adt.ATCSetResult
+	truncated := false
+	var err error
+	opts := adt.ATCSetOptions{Variant: variant, MaxResults: maxResults}
+	switch {
+	case packageName != "":
+		setResult, err = s.adtClient.RunATCCheckPackage(ctx, packageName, opts)
+	case transport != "":
+		setResult, err = s.adtClient.RunATCCheckTransport(ctx, transport, opts)
+	case len(objectURLs) > 0:
+		setResult, err = s.adtClient.RunATCCheckObjects(ctx, objectURLs, opts)
+	default:
+		result, err = s.adtClient.RunATCCheck(ctx, objectURL, variant, maxResults)
+		truncated = err == nil && adt.ATCWorklistTruncated(result, maxResults)
+	}
 	if err != nil {
 		return newToolResultError(fmt.Sprintf("ATC check failed: %v", err)), nil
 	}
+	if setResult != nil {
+		result, truncated = setResult.Worklist, setResult.Truncated
+	}
+
+	// Baseline mode: report only findings that are not in the baseline
+	var comparison *adt.ATCComparison
+	baselineCreated := false
+	if baselinePath != "" {
+		if truncated {
+			return newToolResultError(fmt.Sprintf("ATC result reached max_results (%d), findings may be missing: the baseline is neither written nor compared. Raise max_results.", maxResults)), nil
+		}
+		baseline, err := adt.LoadATCBaseline(baselinePath)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			if err := adt.NewATCBaseline(result).Save(baselinePath); err != nil {
+				return newToolResultError(err.Error()), nil
+			}
+			baselineCreated = true
+		case err != nil:
+			return newToolResultError(err.Error()), nil
+		default:
+			comparison = baseline.Compare(result)
+			if updateBaseline {
+				if err := adt.NewATCBaseline(result).Save(baselinePath); err != nil {
+					return newToolResultError(err.Error()), nil
+				}
+			}
+			result = comparison.New
+		}
+	}
 
 	// Format output with summary
-	type summary struct {
-		TotalObjects  int `json:"totalObjects"`
-		TotalFindings int `json:"totalFindings"`
-		Errors        int `json:"errors"`
-		Warnings      int `json:"warnings"`
-		Infos         int `json:"infos"`
+	type baselineInfo struct {
+		Path      string                   `json:"path"`
+		Created   bool                     `json:"created,omitempty"`
+		Updated   bool                     `json:"updated,omitempty"`
+		New       int                      `json:"new"`
+		Unchanged int                      `json:"unchanged"`
+		Fixed     []adt.ATCBaselineFinding `json:"fixed,omitempty"`
 	}
 	type output struct {
-		Summary  summary          `json:"summary"`
+		Summary  adt.ATCSummary   `json:"summary"`
+		Baseline *baselineInfo    `json:"baseline,omitempty"`
 		Worklist *adt.ATCWorklist `json:"worklist"`
 	}
 
-	sum := summary{TotalObjects: len(result.Objects)}
-	for _, obj := range result.Objects {
-		sum.TotalFindings += len(obj.Findings)
-		for _, f := range obj.Findings {
-			switch f.Priority {
-			case 1:
-				sum.Errors++
-			case 2:
-				sum.Warnings++
-			default:
-				sum.Infos++
-			}
-		}
+	sum := result.Summary()
+	if setResult != nil {
+		sum.CheckedObjects = setResult.Summary.CheckedObjects
+		sum.Skipped = setResult.Summary.Skipped
 	}
 
 	out := output{Summary: sum, Worklist: result}
+	if baselinePath != "" {
+		out.Baseline = &baselineInfo{Path: baselinePath, Created: baselineCreated}
+		if comparison != nil {
+			out.Baseline.Updated = updateBaseline
+			out.Baseline.New = comparison.NewCount
+			out.Baseline.Unchanged = comparison.Unchanged
+			out.Baseline.Fixed = comparison.Fixed
+		}
+	}
 	outputJSON, _ := json.MarshalIndent(out, "", "  ")
 	return mcp.NewToolResultText(string(outputJSON)), nil
 }
@@ -76,3 +152,76 @@ func (s *Server) handleGetATCCustomizing(ctx context.Context, request mcp.CallTo
 	output, _ := json.MarshalIndent(result, "", "  ")
 	return mcp.NewToolResultText(string(output)), nil
 }
+
+func (s *Server) handleATCQuickFix(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	location, ok := request.Params.Arguments["location"].(string)
+	if !ok || location == "" {
+		return newToolResultError("location is required"), nil
+	}
+
+	fixes, err := s.adtClient.GetATCQuickFixes(ctx, location)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("Failed to get quick fixes: %v", err)), nil
+	}
+
+	fixNum, _ := request.Params.Arguments["fix"].(float64)
+	if fixNum == 0 {
+		if len(fixes) == 0 {
+			return mcp.NewToolResultText("No quick fixes available for this location"), nil
+		}
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Quick fixes for %s:\n", location)
+		for i, f := range fixes {
+			fmt.Fprintf(&sb, "  %d. %s", i+1, f.Name)
+			if f.Description != "" && f.Description != f.Name {
+				fmt.Fprintf(&sb, " - %s", f.Description)
+			}
+			sb.WriteString("\n")
+		}
+		return mcp.NewToolResultText(sb.String()), nil
+	}
+	if int(fixNum) < 1 || int(fixNum) > len(fixes) {
+		return newToolResultError(fmt.Sprintf("fix must be between 1 and %d", len(fixes))), nil
+	}
+	fix := fixes[int(fixNum)-1]
+
+	apply, _ := request.Params.Arguments["apply"].(bool)
+	if !apply {
+		preview, err := s.adtClient.PreviewATCQuickFix(ctx, location, fix)
+		if err != nil {
+			return newToolResultError(fmt.Sprintf("Failed to preview quick fix: %v", err)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Preview of quick fix %q (not saved):\n\n%s", fix.Name, preview.Diff)), nil
+	}
+
+	opts := &adt.ATCQuickFixOptions{SyntaxCheck: true}
+	if sc, ok := request.Params.Arguments["syntax_check"].(bool); ok {
+		opts.SyntaxCheck = sc
+	}
+	opts.Transport, _ = request.Params.Arguments["transport"].(string)
+
+	result, err := s.adtClient.ApplyATCQuickFix(ctx, location, fix, opts)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("Failed to apply quick fix: %v", err)), nil
+	}
+	output, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(output)), nil
+}
+
+func (s *Server) handleATCRequestExemption(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	markerID, _ := request.Params.Arguments["marker_id"].(string)
+	reason, _ := request.Params.Arguments["reason"].(string)
+	if markerID == "" || reason == "" {
+		return newToolResultError("marker_id and reason are required"), nil
+	}
+	req := adt.ATCExemptionRequest{Reason: reason}
+	req.Justification, _ = request.Params.Arguments["justification"].(string)
+	req.Approver, _ = request.Params.Arguments["approver"].(string)
+
+	result, err := s.adtClient.RequestATCExemption(ctx, markerID, req)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("Failed to request exemption: %v", err)), nil
+	}
+	output, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(output)), nil
+}
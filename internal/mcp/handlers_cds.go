@@ -0,0 +1,127 @@
+// Package mcp provides the MCP server implementation for ABAP ADT tools.
+// handlers_cds.go contains handlers for CDS element info, data preview and annotations.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/oisee/vibing-steampunk/pkg/adt"
+)
+
+// --- CDS Handlers ---
+
+func (s *Server) handleGetCDSElementInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	entity, ok := request.Params.Arguments["entity"].(string)
+	if !ok || entity == "" {
+		return newToolResultError("entity is required"), nil
+	}
+
+	info, err := s.adtClient.GetCDSElementInfo(ctx, entity)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("GetCDSElementInfo failed: %v", err)), nil
+	}
+
+	if withProps, _ := request.Params.Arguments["include_properties"].(bool); !withProps {
+		info.Properties = nil
+		for i := range info.Elements {
+			info.Elements[i].Properties = nil
+		}
+		for i := range info.Parameters {
+			info.Parameters[i].Properties = nil
+		}
+		for i := range info.Associations {
+			info.Associations[i].Properties = nil
+		}
+	}
+
+	result, _ := json.MarshalIndent(info, "", "  ")
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handlePreviewCDS(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	entity, ok := request.Params.Arguments["entity"].(string)
+	if !ok || entity == "" {
+		return newToolResultError("entity is required"), nil
+	}
+
+	opts := adt.CDSPreviewOptions{MaxRows: 100}
+	switch params := request.Params.Arguments["parameters"].(type) {
+	case string:
+		if params != "" {
+			if err := json.Unmarshal([]byte(params), &opts.Parameters); err != nil {
+				return newToolResultError(fmt.Sprintf("Invalid parameters JSON: %v", err)), nil
+			}
+		}
+	case map[string]interface{}:
+		opts.Parameters = make(map[string]string, len(params))
+		for k, v := range params {
+			opts.Parameters[k] = fmt.Sprint(v)
+		}
+	}
+	if assoc, ok := request.Params.Arguments["association"].(string); ok {
+		opts.Association = assoc
+	}
+	if fields, ok := request.Params.Arguments["fields"].(string); ok && fields != "" {
+		for _, f := range strings.Split(fields, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				opts.Fields = append(opts.Fields, f)
+			}
+		}
+	}
+	if where, ok := request.Params.Arguments["where"].(string); ok {
+		opts.Where = where
+	}
+	if orderBy, ok := request.Params.Arguments["order_by"].(string); ok {
+		opts.OrderBy = orderBy
+	}
+	if mr, ok := request.Params.Arguments["max_rows"].(float64); ok && mr > 0 {
+		opts.MaxRows = int(mr)
+	}
+
+	contents, err := s.adtClient.PreviewCDS(ctx, entity, opts)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("PreviewCDS failed: %v", err)), nil
+	}
+
+	result, _ := json.MarshalIndent(contents, "", "  ")
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleGetCDSAnnotations(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	entity, ok := request.Params.Arguments["entity"].(string)
+	if !ok || entity == "" {
+		return newToolResultError("entity is required"), nil
+	}
+
+	var extensions []string
+	if exts, ok := request.Params.Arguments["extensions"].(string); ok && exts != "" {
+		for _, e := range strings.Split(exts, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				extensions = append(extensions, e)
+			}
+		}
+	}
+
+	active, err := s.adtClient.GetCDSActiveAnnotations(ctx, entity, extensions)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("GetCDSAnnotations failed: %v", err)), nil
+	}
+
+	if element, ok := request.Params.Arguments["element"].(string); ok && element != "" {
+		for _, e := range active.Elements {
+			if strings.EqualFold(e.Name, element) {
+				active.Elements = []adt.CDSElementAnnotations{e}
+				result, _ := json.MarshalIndent(active, "", "  ")
+				return mcp.NewToolResultText(string(result)), nil
+			}
+		}
+		return newToolResultError(fmt.Sprintf("Element %s not found in %s", element, active.Entity)), nil
+	}
+
+	result, _ := json.MarshalIndent(active, "", "  ")
+	return mcp.NewToolResultText(string(result)), nil
+}
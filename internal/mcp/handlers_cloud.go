@@ -0,0 +1,81 @@
+// Package mcp provides the MCP server implementation for ABAP ADT tools.
+// handlers_cloud.go contains handlers for ABAP Cloud released-API checks and
+// the class runner.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/oisee/vibing-steampunk/pkg/adt"
+)
+
+// --- ABAP Cloud Handlers ---
+
+func (s *Server) handleGetAPIReleaseState(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, ok := request.Params.Arguments["name"].(string)
+	if !ok || name == "" {
+		return newToolResultError("name is required"), nil
+	}
+
+	state, err := s.adtClient.GetObjectReleaseState(ctx, name)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("GetAPIReleaseState failed: %v", err)), nil
+	}
+
+	output := struct {
+		*adt.APIReleaseState
+		CloudState string `json:"cloudState"`
+	}{state, state.CloudState()}
+	result, _ := json.MarshalIndent(output, "", "  ")
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleCheckReleasedAPIs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	source, _ := request.Params.Arguments["source"].(string)
+	objectType, _ := request.Params.Arguments["object_type"].(string)
+	name, _ := request.Params.Arguments["name"].(string)
+
+	if source == "" {
+		if objectType == "" || name == "" {
+			return newToolResultError("source or object_type and name are required"), nil
+		}
+		var err error
+		source, err = s.adtClient.GetSource(ctx, objectType, name, nil)
+		if err != nil {
+			return newToolResultError(fmt.Sprintf("Failed to read %s %s: %v", objectType, name, err)), nil
+		}
+	}
+
+	check, err := s.adtClient.CheckReleasedAPIs(ctx, source)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("CheckReleasedAPIs failed: %v", err)), nil
+	}
+
+	output := struct {
+		Summary   string `json:"summary"`
+		CloudMode bool   `json:"cloudMode"` // Writes of failing code are refused
+		*adt.ReleasedAPICheckResult
+	}{check.Summary(), s.adtClient.CloudMode(), check}
+	result, _ := json.MarshalIndent(output, "", "  ")
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleRunClass(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	className, ok := request.Params.Arguments["class_name"].(string)
+	if !ok || className == "" {
+		return newToolResultError("class_name is required"), nil
+	}
+
+	console, err := s.adtClient.RunClass(ctx, className)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("RunClass failed: %v", err)), nil
+	}
+	if console == "" {
+		return mcp.NewToolResultText(fmt.Sprintf("%s ran without console output", strings.ToUpper(className))), nil
+	}
+	return mcp.NewToolResultText(console), nil
+}
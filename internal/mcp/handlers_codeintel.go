@@ -359,7 +359,7 @@ func (s *Server) registerGetSource() {
 		mcp.WithDescription("Unified tool for reading ABAP source code across different object types. Replaces GetProgram, GetClass, GetInterface, GetFunction, GetInclude, GetFunctionGroup, GetClassInclude."),
 		mcp.WithString("object_type",
 			mcp.Required(),
-			mcp.Description("Object type: PROG (program), CLAS (class), INTF (interface), FUNC (function module), FUGR (function group), INCL (include), DDLS (CDS DDL source), VIEW (DDIC view), BDEF (behavior definition), SRVD (service definition), SRVB (service binding), MSAG (message class)"),
+			mcp.Description("Object type: PROG (program), CLAS (class), INTF (interface), FUNC (function module), FUGR (function group), INCL (include), DDLS (CDS DDL source), DDLX (CDS metadata extension), VIEW (DDIC view), BDEF (behavior definition), SRVD (service definition), SRVB (service binding), MSAG (message class)"),
 		),
 		mcp.WithString("name",
 			mcp.Required(),
@@ -678,3 +678,125 @@ func (s *Server) handleGrepPackages(ctx context.Context, request mcp.CallToolReq
 	output, _ := json.MarshalIndent(result, "", "  ")
 	return mcp.NewToolResultText(string(output)), nil
 }
+
+// --- Refactoring Handlers ---
+
+func (s *Server) handleRenameSymbol(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sourceURL, ok := request.Params.Arguments["source_url"].(string)
+	if !ok || sourceURL == "" {
+		return newToolResultError("source_url is required"), nil
+	}
+	line, _ := request.Params.Arguments["line"].(float64)
+	column, _ := request.Params.Arguments["column"].(float64)
+	if line < 1 || column < 1 {
+		return newToolResultError("line and column are required"), nil
+	}
+	newName, ok := request.Params.Arguments["new_name"].(string)
+	if !ok || newName == "" {
+		return newToolResultError("new_name is required"), nil
+	}
+
+	preview, err := s.adtClient.PreviewRename(ctx, sourceURL, int(line), int(column), newName)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("Rename failed: %v", err)), nil
+	}
+	return s.refactoringResult(ctx, request, preview)
+}
+
+func (s *Server) handleExtractMethod(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sourceURL, ok := request.Params.Arguments["source_url"].(string)
+	if !ok || sourceURL == "" {
+		return newToolResultError("source_url is required"), nil
+	}
+	startLine, _ := request.Params.Arguments["start_line"].(float64)
+	if startLine < 1 {
+		return newToolResultError("start_line is required"), nil
+	}
+	endLine, _ := request.Params.Arguments["end_line"].(float64)
+	methodName, ok := request.Params.Arguments["method_name"].(string)
+	if !ok || methodName == "" {
+		return newToolResultError("method_name is required"), nil
+	}
+
+	preview, err := s.adtClient.PreviewExtractMethod(ctx, sourceURL, int(startLine), int(endLine), methodName)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("Extract method failed: %v", err)), nil
+	}
+	return s.refactoringResult(ctx, request, preview)
+}
+
+func (s *Server) handleChangeSignature(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	classURL, ok := request.Params.Arguments["class_url"].(string)
+	if !ok || classURL == "" {
+		return newToolResultError("class_url is required"), nil
+	}
+	method, ok := request.Params.Arguments["method"].(string)
+	if !ok || method == "" {
+		return newToolResultError("method is required"), nil
+	}
+
+	var change adt.SignatureChange
+	if add, ok := request.Params.Arguments["add"].([]interface{}); ok {
+		data, _ := json.Marshal(add)
+		if err := json.Unmarshal(data, &change.Add); err != nil {
+			return newToolResultError(fmt.Sprintf("invalid add: %v", err)), nil
+		}
+	}
+	if remove, ok := request.Params.Arguments["remove"].([]interface{}); ok {
+		for _, r := range remove {
+			if name, ok := r.(string); ok && name != "" {
+				change.Remove = append(change.Remove, name)
+			}
+		}
+	}
+	if rename, ok := request.Params.Arguments["rename"].(map[string]interface{}); ok {
+		change.Rename = make(map[string]string)
+		for oldName, v := range rename {
+			if newName, ok := v.(string); ok && newName != "" {
+				change.Rename[oldName] = newName
+			}
+		}
+	}
+
+	preview, err := s.adtClient.PreviewChangeSignature(ctx, classURL, method, change)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("Change signature failed: %v", err)), nil
+	}
+	return s.refactoringResult(ctx, request, preview)
+}
+
+// refactoringResult returns the preview of a refactoring or, with
+// execute=true, applies it.
+func (s *Server) refactoringResult(ctx context.Context, request mcp.CallToolRequest, preview *adt.RefactoringPreview) (*mcp.CallToolResult, error) {
+	var sb strings.Builder
+	if len(preview.Changes) == 0 {
+		sb.WriteString("No changes.\n")
+	}
+	for _, w := range preview.Warnings {
+		fmt.Fprintf(&sb, "WARNING: %s\n", w)
+	}
+
+	execute, _ := request.Params.Arguments["execute"].(bool)
+	if !execute || len(preview.Changes) == 0 {
+		if len(preview.Changes) > 0 {
+			fmt.Fprintf(&sb, "Preview (not saved), %d sources:\n\n%s", len(preview.Changes), preview.Diff())
+		}
+		return mcp.NewToolResultText(sb.String()), nil
+	}
+
+	opts := adt.RefactoringOptions{}
+	opts.Transport, _ = request.Params.Arguments["transport"].(string)
+	result, err := s.adtClient.ExecuteRefactoring(ctx, preview, opts)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("Refactoring failed: %v", err)), nil
+	}
+	sb.WriteString(result.Message + "\n")
+	for _, e := range result.Errors {
+		fmt.Fprintf(&sb, "  %s\n", e)
+	}
+	if !result.Success {
+		return newToolResultError(sb.String()), nil
+	}
+	fmt.Fprintf(&sb, "\n%s", preview.Diff())
+	return mcp.NewToolResultText(sb.String()), nil
+}
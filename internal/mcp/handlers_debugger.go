@@ -33,6 +33,84 @@ func (s *Server) ensureDebugWSClient(ctx context.Context) error {
 	return s.debugWSClient.Connect(ctx)
 }
 
+// debugWS returns the ZADT_VSP client of the debug session named by the
+// session argument, connecting it if needed. Every named session has its own
+// WebSocket connection and thus its own attached debuggee, breakpoint
+// modifiers, hit counters and log points; without a session the shared client
+// is used. Only sessions attached through DebuggerAttach can be named.
+func (s *Server) debugWS(ctx context.Context, request mcp.CallToolRequest) (*adt.DebugWebSocketClient, error) {
+	sessionID, _ := request.Params.Arguments["session"].(string)
+	if sessionID == "" {
+		if err := s.ensureDebugWSClient(ctx); err != nil {
+			return nil, err
+		}
+		return s.debugWSClient, nil
+	}
+
+	session, err := s.debugSessions.Session(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	key := session.ID
+	s.debugWSMu.Lock()
+	defer s.debugWSMu.Unlock()
+	if client := s.debugWSSessions[key]; client != nil && client.IsConnected() {
+		return client, nil
+	}
+
+	client := adt.NewDebugWebSocketClient(
+		s.config.BaseURL,
+		s.config.Client,
+		s.config.Username,
+		s.config.Password,
+		s.config.InsecureSkipVerify,
+	)
+	if err := client.Connect(ctx); err != nil {
+		return nil, err
+	}
+	s.debugWSSessions[key] = client
+	return client, nil
+}
+
+// sessionDebugWS returns the ZADT_VSP client whose breakpoints apply to the
+// debug session sessionID: the session's own client if breakpoints were set
+// for it, otherwise the shared one (nil if none is connected).
+func (s *Server) sessionDebugWS(sessionID string) *adt.DebugWebSocketClient {
+	s.debugWSMu.Lock()
+	defer s.debugWSMu.Unlock()
+	if client := s.debugWSSessions[strings.ToUpper(sessionID)]; client != nil {
+		return client
+	}
+	return s.debugWSClient
+}
+
+// attachedDebugWS returns the ZADT_VSP client of the debug session sessionID
+// (the shared one without a session) if it is attached to a debuggee, or nil.
+func (s *Server) attachedDebugWS(sessionID string) *adt.DebugWebSocketClient {
+	s.debugWSMu.Lock()
+	client := s.debugWSClient
+	if sessionID != "" {
+		client = s.debugWSSessions[strings.ToUpper(sessionID)]
+	}
+	s.debugWSMu.Unlock()
+	if client == nil || !client.IsAttached() {
+		return nil
+	}
+	return client
+}
+
+// closeDebugWS closes the ZADT_VSP client of a debug session, which ends its
+// attachment and removes its breakpoints.
+func (s *Server) closeDebugWS(sessionID string) {
+	s.debugWSMu.Lock()
+	client := s.debugWSSessions[strings.ToUpper(sessionID)]
+	delete(s.debugWSSessions, strings.ToUpper(sessionID))
+	s.debugWSMu.Unlock()
+	if client != nil {
+		client.Close()
+	}
+}
+
 func (s *Server) handleSetBreakpoint(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	// Get breakpoint kind (default: "line")
 	kind, _ := request.Params.Arguments["kind"].(string)
@@ -40,15 +118,20 @@ func (s *Server) handleSetBreakpoint(ctx context.Context, request mcp.CallToolRe
 		kind = "line"
 	}
 
-	// Ensure WebSocket client is connected
-	if err := s.ensureDebugWSClient(ctx); err != nil {
+	// Ensure WebSocket client of the session is connected
+	ws, err := s.debugWS(ctx, request)
+	if err != nil {
 		return newToolResultError(fmt.Sprintf("Failed to connect to ZADT_VSP WebSocket: %v. Ensure ZADT_VSP is deployed and SAPC/SICF are configured.", err)), nil
 	}
 
 	var bpID string
-	var err error
 	var msg strings.Builder
 
+	opts, warning, errResult := s.parseBreakpointOptions(ctx, request)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	switch kind {
 	case "line":
 		program, ok := request.Params.Arguments["program"].(string)
@@ -71,7 +154,9 @@ func (s *Server) handleSetBreakpoint(ctx context.Context, request mcp.CallToolRe
 
 		// Use method-aware breakpoint if method is specified
 		if method != "" {
-			bpID, err = s.debugWSClient.SetMethodBreakpoint(ctx, program, method, line)
+			bpID, err = ws.SetBreakpoint(ctx, adt.WSBreakpointSpec{
+				Kind: "line", Program: program, Method: method, Line: line, Options: opts,
+			})
 			if err != nil {
 				return newToolResultError(fmt.Sprintf("SetMethodBreakpoint failed: %v", err)), nil
 			}
@@ -87,7 +172,9 @@ func (s *Server) handleSetBreakpoint(ctx context.Context, request mcp.CallToolRe
 			fmt.Fprintf(&msg, "Line: %d (relative to method start)\n", line)
 			msg.WriteString("\nℹ️  Line number is relative to the METHOD implementation, not the full class.\n")
 		} else {
-			bpID, err = s.debugWSClient.SetLineBreakpoint(ctx, program, line)
+			bpID, err = ws.SetBreakpoint(ctx, adt.WSBreakpointSpec{
+				Kind: "line", Program: program, Line: line, Options: opts,
+			})
 			if err != nil {
 				return newToolResultError(fmt.Sprintf("SetLineBreakpoint failed: %v", err)), nil
 			}
@@ -108,7 +195,9 @@ func (s *Server) handleSetBreakpoint(ctx context.Context, request mcp.CallToolRe
 			return newToolResultError("statement is required for statement breakpoints (e.g., 'CALL FUNCTION', 'SELECT', 'LOOP')"), nil
 		}
 
-		bpID, err = s.debugWSClient.SetStatementBreakpoint(ctx, statement)
+		bpID, err = ws.SetBreakpoint(ctx, adt.WSBreakpointSpec{
+			Kind: "statement", Statement: statement, Options: opts,
+		})
 		if err != nil {
 			return newToolResultError(fmt.Sprintf("SetStatementBreakpoint failed: %v", err)), nil
 		}
@@ -124,7 +213,9 @@ func (s *Server) handleSetBreakpoint(ctx context.Context, request mcp.CallToolRe
 			return newToolResultError("exception is required for exception breakpoints (e.g., 'CX_SY_ZERODIVIDE')"), nil
 		}
 
-		bpID, err = s.debugWSClient.SetExceptionBreakpoint(ctx, exception)
+		bpID, err = ws.SetBreakpoint(ctx, adt.WSBreakpointSpec{
+			Kind: "exception", Exception: exception, Options: opts,
+		})
 		if err != nil {
 			return newToolResultError(fmt.Sprintf("SetExceptionBreakpoint failed: %v", err)), nil
 		}
@@ -134,10 +225,50 @@ func (s *Server) handleSetBreakpoint(ctx context.Context, request mcp.CallToolRe
 		fmt.Fprintf(&msg, "Exception: %s\n", exception)
 		msg.WriteString("\nThis breakpoint will trigger when this exception is raised.\n")
 
+	case "watchpoint":
+		variable, ok := request.Params.Arguments["variable"].(string)
+		if !ok || variable == "" {
+			return newToolResultError("variable is required for watchpoints (e.g., 'LV_STATUS', 'ME->MV_STATE', 'LS_DATA-FIELD')"), nil
+		}
+		value, _ := request.Params.Arguments["value"].(string)
+
+		if ws.IsAttached() {
+			bpID, err = ws.SetWatchpoint(ctx, variable, value, opts)
+		} else {
+			// Session-bound REST watchpoint for debuggees attached via DebuggerAttach
+			client, errResult := s.debugClient(request)
+			if errResult != nil {
+				return errResult, nil
+			}
+			if err := adt.CheckWatchpointOptions(opts); err != nil {
+				return newToolResultError(err.Error()), nil
+			}
+			var wp *adt.Watchpoint
+			wp, err = client.DebuggerSetWatchpoint(ctx, variable, adt.WatchpointCondition(variable, value, opts.Condition))
+			if err == nil {
+				bpID = wp.ID
+			}
+		}
+		if err != nil {
+			return newToolResultError(fmt.Sprintf("SetWatchpoint failed: %v. Watchpoints require an attached debuggee (DebuggerAttach).", err)), nil
+		}
+
+		msg.WriteString("Watchpoint set successfully!\n\n")
+		fmt.Fprintf(&msg, "Watchpoint ID: %s\n", bpID)
+		fmt.Fprintf(&msg, "Variable: %s\n", strings.ToUpper(variable))
+		if value != "" {
+			fmt.Fprintf(&msg, "Stops when value changes to: %s\n", value)
+		} else {
+			msg.WriteString("Stops whenever the value changes.\n")
+		}
+		msg.WriteString("\nWatchpoints belong to the current debug session and are removed when it ends.\n")
+
 	default:
-		return newToolResultError(fmt.Sprintf("Invalid breakpoint kind: %s. Valid kinds: line, statement, exception", kind)), nil
+		return newToolResultError(fmt.Sprintf("Invalid breakpoint kind: %s. Valid kinds: line, statement, exception, watchpoint", kind)), nil
 	}
 
+	writeBreakpointOptions(&msg, opts, warning)
+
 	msg.WriteString("\n⚠️  IMPORTANT: Breakpoints only trigger for code executed in a DIFFERENT SAP session.\n")
 	msg.WriteString("Use DebuggerListen in this session, then trigger execution from another session\n")
 	msg.WriteString("(e.g., SAP GUI, HTTP request, RunUnitTests from another connection).")
@@ -145,6 +276,60 @@ func (s *Server) handleSetBreakpoint(ctx context.Context, request mcp.CallToolRe
 	return mcp.NewToolResultText(msg.String()), nil
 }
 
+// parseBreakpointOptions reads condition, hit_count and log_expressions from the request.
+// The condition is additionally checked with the ADT condition validator; a rejection
+// there is reported as a warning since ZADT_VSP evaluates conditions client-side.
+func (s *Server) parseBreakpointOptions(ctx context.Context, request mcp.CallToolRequest) (adt.BreakpointOptions, string, *mcp.CallToolResult) {
+	var opts adt.BreakpointOptions
+	var warning string
+
+	if cond, ok := request.Params.Arguments["condition"].(string); ok {
+		opts.Condition = strings.TrimSpace(cond)
+	}
+	if hc, ok := request.Params.Arguments["hit_count"].(float64); ok {
+		if hc < 0 {
+			return opts, "", newToolResultError("hit_count must not be negative")
+		}
+		opts.HitCount = int(hc)
+	}
+	if exprs, ok := request.Params.Arguments["log_expressions"].([]interface{}); ok {
+		for _, e := range exprs {
+			if str, ok := e.(string); ok && strings.TrimSpace(str) != "" {
+				opts.LogExpressions = append(opts.LogExpressions, strings.TrimSpace(str))
+			}
+		}
+	}
+
+	if opts.Condition != "" {
+		valid, message, err := s.adtClient.ValidateBreakpointCondition(ctx, opts.Condition)
+		if err == nil && !valid {
+			warning = fmt.Sprintf("ADT condition validator rejected the condition: %s", message)
+		}
+	}
+
+	return opts, warning, nil
+}
+
+// writeBreakpointOptions appends breakpoint modifiers to a SetBreakpoint result.
+func writeBreakpointOptions(msg *strings.Builder, opts adt.BreakpointOptions, warning string) {
+	if opts.IsEmpty() {
+		return
+	}
+	msg.WriteString("\nModifiers (evaluated client-side when hit, in DebuggerAttach, DebuggerStep stepContinue and DebuggerCollectLogPoints):\n")
+	if opts.Condition != "" {
+		fmt.Fprintf(msg, "  Condition: %s\n", opts.Condition)
+	}
+	if opts.HitCount > 1 {
+		fmt.Fprintf(msg, "  Hit count: stop from hit #%d on\n", opts.HitCount)
+	}
+	if opts.IsLogPoint() {
+		fmt.Fprintf(msg, "  Log point: %s (does not stop, use DebuggerCollectLogPoints)\n", strings.Join(opts.LogExpressions, ", "))
+	}
+	if warning != "" {
+		fmt.Fprintf(msg, "  ⚠️  %s\n", warning)
+	}
+}
+
 // convertToClassPool converts class/interface names to pool format for debugging.
 // Example: ZCL_TEST → ZCL_TEST================CP (padded to 30 chars + CP suffix)
 func convertToClassPool(program string) string {
@@ -180,11 +365,12 @@ func convertToClassPool(program string) string {
 }
 
 func (s *Server) handleGetBreakpoints(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	if err := s.ensureDebugWSClient(ctx); err != nil {
+	ws, err := s.debugWS(ctx, request)
+	if err != nil {
 		return newToolResultError(fmt.Sprintf("Failed to connect to ZADT_VSP WebSocket: %v", err)), nil
 	}
 
-	breakpoints, err := s.debugWSClient.GetBreakpoints(ctx)
+	breakpoints, err := ws.GetBreakpoints(ctx)
 	if err != nil {
 		return newToolResultError(fmt.Sprintf("GetBreakpoints failed: %v", err)), nil
 	}
@@ -206,6 +392,18 @@ func (s *Server) handleGetBreakpoints(ctx context.Context, request mcp.CallToolR
 		if line, ok := bp["line"]; ok {
 			fmt.Fprintf(&sb, "   Line: %v\n", line)
 		}
+		if cond, ok := bp["condition"]; ok {
+			fmt.Fprintf(&sb, "   Condition: %v\n", cond)
+		}
+		if hc, ok := bp["hitCount"]; ok {
+			fmt.Fprintf(&sb, "   Hit count: %v\n", hc)
+		}
+		if exprs, ok := bp["logExpressions"].([]string); ok {
+			fmt.Fprintf(&sb, "   Log point: %s\n", strings.Join(exprs, ", "))
+		}
+		if hits, ok := bp["hits"]; ok {
+			fmt.Fprintf(&sb, "   Hits: %v\n", hits)
+		}
 		sb.WriteString("\n")
 	}
 
@@ -218,17 +416,85 @@ func (s *Server) handleDeleteBreakpoint(ctx context.Context, request mcp.CallToo
 		return newToolResultError("breakpoint_id is required"), nil
 	}
 
-	if err := s.ensureDebugWSClient(ctx); err != nil {
+	ws, err := s.debugWS(ctx, request)
+	if err != nil {
 		return newToolResultError(fmt.Sprintf("Failed to connect to ZADT_VSP WebSocket: %v", err)), nil
 	}
 
-	if err := s.debugWSClient.DeleteBreakpoint(ctx, bpID); err != nil {
+	if err := ws.DeleteBreakpoint(ctx, bpID); err != nil {
 		return newToolResultError(fmt.Sprintf("DeleteBreakpoint failed: %v", err)), nil
 	}
 
 	return mcp.NewToolResultText(fmt.Sprintf("Breakpoint %s deleted successfully.", bpID)), nil
 }
 
+func (s *Server) handleDebuggerCollectLogPoints(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	timeout := 60
+	if t, ok := request.Params.Arguments["timeout"].(float64); ok && t > 0 {
+		timeout = int(t)
+		if timeout > 240 {
+			timeout = 240 // max 240 seconds
+		}
+	}
+
+	ws, err := s.debugWS(ctx, request)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("Failed to connect to ZADT_VSP WebSocket: %v", err)), nil
+	}
+
+	var entries []adt.LogPointEntry
+	ws.SetLogPointHandler(func(e adt.LogPointEntry) {
+		entries = append(entries, e)
+	})
+	defer ws.SetLogPointHandler(nil)
+
+	var frame *adt.DebugStackFrame
+	if ws.IsAttached() {
+		// Resume a debuggee left at a stopping breakpoint by a previous call
+		frame, err = ws.Step(ctx, "continue")
+		if err != nil {
+			return newToolResultError(fmt.Sprintf("Continue failed: %v", err)), nil
+		}
+	} else {
+		var debuggees []adt.DebugDebuggee
+		debuggees, err = ws.Listen(ctx, timeout)
+		if err != nil {
+			return newToolResultError(fmt.Sprintf("Listen failed: %v", err)), nil
+		}
+		if len(debuggees) == 0 {
+			return mcp.NewToolResultText("Listener timed out - no debuggee hit a breakpoint within the timeout period."), nil
+		}
+		frame, err = ws.Attach(ctx, debuggees[0].ID)
+		if err != nil {
+			return newToolResultError(fmt.Sprintf("Attach failed: %v", err)), nil
+		}
+	}
+
+	hit, err := ws.ContinueToStop(ctx, frame)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Log points (%d entries):\n\n", len(entries))
+	for _, e := range entries {
+		sb.WriteString(e.String())
+		sb.WriteString("\n")
+	}
+
+	switch {
+	case err != nil:
+		fmt.Fprintf(&sb, "\nStopped with error: %v\n", err)
+	case hit == nil:
+		sb.WriteString("\nDebuggee finished.\n")
+	default:
+		fmt.Fprintf(&sb, "\nStopped at %s:%d", hit.Frame.Program, hit.Frame.Line)
+		if hit.BreakpointID != "" {
+			fmt.Fprintf(&sb, " (breakpoint %s, hit #%d)", hit.BreakpointID, hit.Hits)
+		}
+		sb.WriteString("\nThe debuggee stays attached via WebSocket. Call DebuggerCollectLogPoints again (with the same session) to continue.\n")
+	}
+
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
 func (s *Server) handleCallRFC(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	function, ok := request.Params.Arguments["function"].(string)
 	if !ok || function == "" {
@@ -14,6 +14,60 @@ import (
 
 // --- Legacy REST-based Debugger Handlers (fallback) ---
 
+// debugClient returns the client of the debug session named by the optional
+// "session" argument (see DebuggerAttach). Without it, the only attached
+// session is used.
+func (s *Server) debugClient(request mcp.CallToolRequest) (*adt.Client, *mcp.CallToolResult) {
+	sessionID, _ := request.Params.Arguments["session"].(string)
+	client, err := s.debugSessions.Client(sessionID)
+	if err != nil {
+		return nil, newToolResultError(err.Error())
+	}
+	return client, nil
+}
+
+// continueToBreakpointStop applies the conditions, hit counts and log points
+// of breakpoints set with SetBreakpoint to a REST session that just stopped:
+// skipped hits and log points are continued past until a breakpoint really
+// stops. The breakpoints of the same session ID are used, or the shared ones.
+// The hit is nil if the debuggee ended instead.
+func (s *Server) continueToBreakpointStop(ctx context.Context, sessionID string, client *adt.Client) (*adt.BreakpointHit, []adt.LogPointEntry, error) {
+	ws := s.sessionDebugWS(sessionID)
+	if ws == nil {
+		// Breakpoints with modifiers are only set through the WebSocket client
+		return &adt.BreakpointHit{Action: adt.BreakpointActionStop}, nil, nil
+	}
+	frame, err := client.DebuggerCurrentFrame(ctx)
+	if err != nil {
+		return &adt.BreakpointHit{Action: adt.BreakpointActionStop}, nil, err
+	}
+
+	var entries []adt.LogPointEntry
+	ws.SetLogPointHandler(func(e adt.LogPointEntry) {
+		entries = append(entries, e)
+	})
+	defer ws.SetLogPointHandler(nil)
+
+	hit, err := ws.ContinueToStopIn(ctx, client.BreakpointTarget(), frame)
+	return hit, entries, err
+}
+
+// writeBreakpointStop describes the outcome of continueToBreakpointStop.
+func writeBreakpointStop(sb *strings.Builder, hit *adt.BreakpointHit, entries []adt.LogPointEntry, err error) {
+	if len(entries) > 0 {
+		fmt.Fprintf(sb, "\nLog points (%d entries):\n", len(entries))
+		for _, e := range entries {
+			sb.WriteString("  " + e.String() + "\n")
+		}
+	}
+	switch {
+	case err != nil:
+		fmt.Fprintf(sb, "\n⚠️  Breakpoint condition/hit count could not be applied: %v\n", err)
+	case hit != nil && hit.BreakpointID != "" && hit.Frame != nil:
+		fmt.Fprintf(sb, "\nStopped at %s:%d (breakpoint %s, hit #%d)\n", hit.Frame.Program, hit.Frame.Line, hit.BreakpointID, hit.Hits)
+	}
+}
+
 func (s *Server) handleDebuggerListen(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	user, _ := request.Params.Arguments["user"].(string)
 	if user == "" {
@@ -27,7 +81,7 @@ func (s *Server) handleDebuggerListen(ctx context.Context, request mcp.CallToolR
 		}
 	}
 
-	result, err := s.adtClient.DebuggerListen(ctx, &adt.ListenOptions{
+	debuggees, conflict, err := s.debugSessions.ListenAll(ctx, &adt.ListenOptions{
 		DebuggingMode:  adt.DebuggingModeUser,
 		User:           user,
 		TimeoutSeconds: timeout,
@@ -36,31 +90,30 @@ func (s *Server) handleDebuggerListen(ctx context.Context, request mcp.CallToolR
 		return newToolResultError(fmt.Sprintf("DebuggerListen failed: %v", err)), nil
 	}
 
-	if result.TimedOut {
-		return mcp.NewToolResultText("Listener timed out - no debuggee hit a breakpoint within the timeout period."), nil
-	}
-
-	if result.Conflict != nil {
+	if conflict != nil {
 		return mcp.NewToolResultText(fmt.Sprintf("Listener conflict detected: %s (user: %s)",
-			result.Conflict.ConflictText, result.Conflict.IdeUser)), nil
+			conflict.ConflictText, conflict.IdeUser)), nil
 	}
 
-	if result.Debuggee != nil {
-		var sb strings.Builder
-		sb.WriteString("Debuggee caught!\n\n")
-		fmt.Fprintf(&sb, "Debuggee ID: %s\n", result.Debuggee.ID)
-		fmt.Fprintf(&sb, "User: %s\n", result.Debuggee.User)
-		fmt.Fprintf(&sb, "Program: %s\n", result.Debuggee.Program)
-		fmt.Fprintf(&sb, "Include: %s\n", result.Debuggee.Include)
-		fmt.Fprintf(&sb, "Line: %d\n", result.Debuggee.Line)
-		fmt.Fprintf(&sb, "Kind: %s\n", result.Debuggee.Kind)
-		fmt.Fprintf(&sb, "Attachable: %v\n", result.Debuggee.IsAttachable)
-		fmt.Fprintf(&sb, "App Server: %s\n", result.Debuggee.AppServer)
-		sb.WriteString("\nUse DebuggerAttach with the debuggee_id to attach to this session.")
-		return mcp.NewToolResultText(sb.String()), nil
+	if len(debuggees) == 0 {
+		return mcp.NewToolResultText("Listener timed out - no debuggee hit a breakpoint within the timeout period."), nil
 	}
 
-	return mcp.NewToolResultText("Listener returned with no result."), nil
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d debuggee(s) caught!\n", len(debuggees))
+	for _, d := range debuggees {
+		sb.WriteString("\n")
+		fmt.Fprintf(&sb, "Debuggee ID: %s\n", d.ID)
+		fmt.Fprintf(&sb, "User: %s\n", d.User)
+		fmt.Fprintf(&sb, "Program: %s\n", d.Program)
+		fmt.Fprintf(&sb, "Include: %s\n", d.Include)
+		fmt.Fprintf(&sb, "Line: %d\n", d.Line)
+		fmt.Fprintf(&sb, "Kind: %s\n", d.Kind)
+		fmt.Fprintf(&sb, "Attachable: %v\n", d.IsAttachable)
+		fmt.Fprintf(&sb, "App Server: %s\n", d.AppServer)
+	}
+	sb.WriteString("\nUse DebuggerAttach with a debuggee_id to attach. Each debuggee gets its own session.")
+	return mcp.NewToolResultText(sb.String()), nil
 }
 
 func (s *Server) handleDebuggerAttach(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -74,13 +127,28 @@ func (s *Server) handleDebuggerAttach(ctx context.Context, request mcp.CallToolR
 		user = s.config.Username // Default to connection user
 	}
 
-	result, err := s.adtClient.DebuggerAttach(ctx, debuggeeID, user)
+	session, err := s.debugSessions.Attach(ctx, debuggeeID, user)
 	if err != nil {
 		return newToolResultError(fmt.Sprintf("DebuggerAttach failed: %v", err)), nil
 	}
+	result := session.Attach
+
+	// Conditions and hit counts of the breakpoints decide whether this stop counts
+	hit, entries, hitErr := s.continueToBreakpointStop(ctx, session.ID, session.Client())
+	if hit == nil && hitErr == nil {
+		var sb strings.Builder
+		sb.WriteString("The debuggee ran to the end: no breakpoint stop met its condition or hit count.\n")
+		writeBreakpointStop(&sb, nil, entries, nil)
+		if _, err := s.debugSessions.Detach(ctx, session.ID); err != nil {
+			fmt.Fprintf(&sb, "\nDetach failed: %v\n", err)
+		}
+		s.closeDebugWS(session.ID)
+		return mcp.NewToolResultText(sb.String()), nil
+	}
 
 	var sb strings.Builder
 	sb.WriteString("Successfully attached to debuggee!\n\n")
+	fmt.Fprintf(&sb, "Session: %s (pass session=%q to the other debugger tools)\n", session.ID, session.ID)
 	fmt.Fprintf(&sb, "Debug Session ID: %s\n", result.DebugSessionID)
 	fmt.Fprintf(&sb, "Process ID: %d\n", result.ProcessID)
 	fmt.Fprintf(&sb, "Server: %s\n", result.ServerName)
@@ -100,18 +168,32 @@ func (s *Server) handleDebuggerAttach(ctx context.Context, request mcp.CallToolR
 			fmt.Fprintf(&sb, "  - %s: %s\n", action.Name, action.Title)
 		}
 	}
+	writeBreakpointStop(&sb, hit, entries, hitErr)
 
 	sb.WriteString("\nUse DebuggerGetStack to see the call stack, DebuggerGetVariables to inspect variables.")
 	return mcp.NewToolResultText(sb.String()), nil
 }
 
 func (s *Server) handleDebuggerDetach(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	err := s.adtClient.DebuggerDetach(ctx)
+	sessionID, _ := request.Params.Arguments["session"].(string)
+
+	// Without attached sessions, terminate whatever the main client is attached to
+	if sessionID == "" && s.debugSessions.Count() == 0 {
+		if err := s.adtClient.DebuggerDetach(ctx); err != nil {
+			return newToolResultError(fmt.Sprintf("DebuggerDetach failed: %v", err)), nil
+		}
+		return mcp.NewToolResultText("Successfully detached from debug session."), nil
+	}
+
+	session, err := s.debugSessions.Detach(ctx, sessionID)
+	if session != nil {
+		s.closeDebugWS(session.ID)
+	}
 	if err != nil {
 		return newToolResultError(fmt.Sprintf("DebuggerDetach failed: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText("Successfully detached from debug session."), nil
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully detached from debug session %s.", session.ID)), nil
 }
 
 func (s *Server) handleDebuggerStep(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -141,11 +223,34 @@ func (s *Server) handleDebuggerStep(ctx context.Context, request mcp.CallToolReq
 
 	uri, _ := request.Params.Arguments["uri"].(string)
 
-	result, err := s.adtClient.DebuggerStep(ctx, stepType, uri)
+	client, errResult := s.debugClient(request)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	result, err := client.DebuggerStep(ctx, stepType, uri)
 	if err != nil {
 		return newToolResultError(fmt.Sprintf("DebuggerStep failed: %v", err)), nil
 	}
 
+	// Continuing runs into the next breakpoint: apply its condition and hit count
+	var hit *adt.BreakpointHit
+	var entries []adt.LogPointEntry
+	var hitErr error
+	if stepType == adt.DebugStepContinue && result.IsSteppingPossible {
+		sessionID, _ := request.Params.Arguments["session"].(string)
+		if session, err := s.debugSessions.Session(sessionID); err == nil {
+			sessionID = session.ID
+		}
+		hit, entries, hitErr = s.continueToBreakpointStop(ctx, sessionID, client)
+		if hit == nil && hitErr == nil {
+			var sb strings.Builder
+			sb.WriteString("Step 'stepContinue' executed.\n\nThe debuggee ran to the end: no further breakpoint stop met its condition or hit count.\n")
+			writeBreakpointStop(&sb, nil, entries, nil)
+			return mcp.NewToolResultText(sb.String()), nil
+		}
+	}
+
 	var sb strings.Builder
 	fmt.Fprintf(&sb, "Step '%s' executed.\n\n", stepTypeStr)
 	fmt.Fprintf(&sb, "Session: %s\n", result.DebugSessionID)
@@ -158,13 +263,19 @@ func (s *Server) handleDebuggerStep(ctx context.Context, request mcp.CallToolReq
 			fmt.Fprintf(&sb, "  - ID: %s (kind: %s)\n", bp.ID, bp.Kind)
 		}
 	}
+	writeBreakpointStop(&sb, hit, entries, hitErr)
 
 	sb.WriteString("\nUse DebuggerGetStack to see current position, DebuggerGetVariables to inspect variables.")
 	return mcp.NewToolResultText(sb.String()), nil
 }
 
 func (s *Server) handleDebuggerGetStack(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	result, err := s.adtClient.DebuggerGetStack(ctx, true)
+	client, errResult := s.debugClient(request)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	result, err := client.DebuggerGetStack(ctx, true)
 	if err != nil {
 		return newToolResultError(fmt.Sprintf("DebuggerGetStack failed: %v", err)), nil
 	}
@@ -194,6 +305,11 @@ func (s *Server) handleDebuggerGetStack(ctx context.Context, request mcp.CallToo
 }
 
 func (s *Server) handleDebuggerGetVariables(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, errResult := s.debugClient(request)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	// Parse variable_ids from request
 	var variableIDs []string
 
@@ -212,7 +328,7 @@ func (s *Server) handleDebuggerGetVariables(ctx context.Context, request mcp.Cal
 
 	// If @ROOT is requested, use GetChildVariables for top-level vars
 	if len(variableIDs) == 1 && variableIDs[0] == "@ROOT" {
-		result, err := s.adtClient.DebuggerGetChildVariables(ctx, []string{"@ROOT", "@DATAAGING"})
+		result, err := client.DebuggerGetChildVariables(ctx, []string{"@ROOT", "@DATAAGING"})
 		if err != nil {
 			return newToolResultError(fmt.Sprintf("DebuggerGetVariables failed: %v", err)), nil
 		}
@@ -232,7 +348,7 @@ func (s *Server) handleDebuggerGetVariables(ctx context.Context, request mcp.Cal
 	}
 
 	// Get specific variables
-	result, err := s.adtClient.DebuggerGetVariables(ctx, variableIDs)
+	result, err := client.DebuggerGetVariables(ctx, variableIDs)
 	if err != nil {
 		return newToolResultError(fmt.Sprintf("DebuggerGetVariables failed: %v", err)), nil
 	}
@@ -258,3 +374,150 @@ func (s *Server) handleDebuggerGetVariables(ctx context.Context, request mcp.Cal
 
 	return mcp.NewToolResultText(sb.String()), nil
 }
+
+func (s *Server) handleDebuggerEvaluate(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	expression, ok := request.Params.Arguments["expression"].(string)
+	if !ok || expression == "" {
+		return newToolResultError("expression is required"), nil
+	}
+
+	client, errResult := s.debugClient(request)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	result, err := client.DebuggerEvaluateExpression(ctx, expression)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("DebuggerEvaluate failed: %v", err)), nil
+	}
+
+	v := result.Variable
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s = %s\n", result.Expression, v.DeclaredTypeName, v.Value)
+	fmt.Fprintf(&sb, "  MetaType: %s, Kind: %s\n", v.MetaType, v.Kind)
+	if v.IsValueIncomplete {
+		sb.WriteString("  (value truncated)\n")
+	}
+	if v.MetaType == adt.DebugMetaTypeTable {
+		fmt.Fprintf(&sb, "  Table Lines: %d (use DebuggerReadTable to page through rows)\n", v.TableLines)
+	}
+
+	if len(result.Components) > 0 {
+		sb.WriteString("\nComponents:\n")
+		for _, c := range result.Components {
+			value := c.Value
+			if c.MetaType == adt.DebugMetaTypeTable {
+				value = fmt.Sprintf("<%d rows>", c.TableLines)
+			}
+			fmt.Fprintf(&sb, "  %s = %s (%s)\n", c.Name, value, c.DeclaredTypeName)
+		}
+	}
+
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+func (s *Server) handleDebuggerReadTable(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	table, ok := request.Params.Arguments["table"].(string)
+	if !ok || table == "" {
+		return newToolResultError("table is required"), nil
+	}
+
+	var opts adt.DebugTableReadOptions
+	if v, ok := request.Params.Arguments["offset"].(float64); ok {
+		opts.Offset = int(v)
+	}
+	if v, ok := request.Params.Arguments["limit"].(float64); ok {
+		opts.Limit = int(v)
+	}
+	if v, ok := request.Params.Arguments["max_scan"].(float64); ok {
+		opts.MaxScan = int(v)
+	}
+	opts.Filter, _ = request.Params.Arguments["filter"].(string)
+	if cols, ok := request.Params.Arguments["columns"].([]interface{}); ok {
+		for _, c := range cols {
+			if name, ok := c.(string); ok && name != "" {
+				opts.Columns = append(opts.Columns, name)
+			}
+		}
+	}
+
+	// A debuggee attached through ZADT_VSP is filtered in the debugger
+	// session; REST sessions are scanned page by page on this side.
+	var page *adt.DebugTablePage
+	var err error
+	scannedBy := "client-side"
+	sessionID, _ := request.Params.Arguments["session"].(string)
+	if ws := s.attachedDebugWS(sessionID); ws != nil {
+		page, err = ws.ReadTable(ctx, table, opts)
+		scannedBy = "by ZADT_VSP"
+		if err != nil && strings.Contains(err.Error(), "UNKNOWN_ACTION") {
+			err = fmt.Errorf("%w (ZADT_VSP is outdated, reinstall it with InstallZADTVSP)", err)
+		}
+	} else {
+		client, errResult := s.debugClient(request)
+		if errResult != nil {
+			return errResult, nil
+		}
+		page, err = client.DebuggerReadTable(ctx, table, opts)
+	}
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("DebuggerReadTable failed: %v", err)), nil
+	}
+
+	var sb strings.Builder
+	if page.HasMore && page.TotalLines == 0 {
+		fmt.Fprintf(&sb, "Table %s\n", page.Table)
+	} else {
+		fmt.Fprintf(&sb, "Table %s: %d lines\n", page.Table, page.TotalLines)
+	}
+	if opts.Filter != "" {
+		fmt.Fprintf(&sb, "Filter: %s (%d rows scanned %s, %d matched)\n", opts.Filter, page.Scanned, scannedBy, len(page.Rows))
+	}
+	sb.WriteString("\n")
+
+	if len(page.Rows) == 0 {
+		sb.WriteString("No rows.\n")
+	} else {
+		sb.WriteString("#\t" + strings.Join(page.Columns, "\t") + "\n")
+		for _, row := range page.Rows {
+			values := make([]string, len(page.Columns))
+			for i, col := range page.Columns {
+				values[i] = row.Values[col]
+			}
+			fmt.Fprintf(&sb, "%d\t%s\n", row.Index, strings.Join(values, "\t"))
+		}
+	}
+
+	if page.HasMore {
+		fmt.Fprintf(&sb, "\nMore rows available: continue with offset=%d\n", page.NextOffset)
+	}
+
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+func (s *Server) handleDebuggerSessions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sessions := s.debugSessions.Sessions()
+	waiting := s.debugSessions.Waiting()
+
+	var sb strings.Builder
+	if len(sessions) == 0 {
+		sb.WriteString("No debug sessions attached.\n")
+	} else {
+		fmt.Fprintf(&sb, "Attached sessions (%d):\n", len(sessions))
+		for _, session := range sessions {
+			d := session.Debuggee
+			fmt.Fprintf(&sb, "  %s: debuggee %s, user %s, %s line %d (attached %s)\n",
+				session.ID, d.ID, d.User, d.Program, d.Line, session.AttachedAt.Format("15:04:05"))
+		}
+	}
+
+	if len(waiting) > 0 {
+		fmt.Fprintf(&sb, "\nWaiting debuggees (%d):\n", len(waiting))
+		for _, d := range waiting {
+			fmt.Fprintf(&sb, "  %s: user %s, %s line %d\n", d.ID, d.User, d.Program, d.Line)
+		}
+		sb.WriteString("\nUse DebuggerAttach with a debuggee_id to attach.\n")
+	}
+
+	return mcp.NewToolResultText(sb.String()), nil
+}
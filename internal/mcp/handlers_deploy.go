@@ -265,21 +265,33 @@ func (s *Server) handleDeployZip(ctx context.Context, request mcp.CallToolReques
 	fmt.Fprintf(&sb, "\n  Phase 2 summary: %d uploaded, %d failed\n\n", uploadSuccess, uploadFailed)
 
 	// ================================================================
-	// PHASE 3: Iterative activation
+	// PHASE 3: Planned activation (dependency batches with retry)
 	// ================================================================
-	sb.WriteString("Phase 3: Iterative activation...\n")
-	iterResult, err := s.adtClient.ActivatePackageIterative(ctx, packageName, 5)
+	sb.WriteString("Phase 3: Planned activation...\n")
+	planResult, err := s.adtClient.ActivatePackagePlanned(ctx, packageName, adt.ActivationPlanOptions{})
 	if err != nil {
 		fmt.Fprintf(&sb, "  Activation error: %v\n", err)
 	} else {
-		for i, pass := range iterResult.Passes {
-			fmt.Fprintf(&sb, "  Pass %d: %d activated, %d failed\n", i+1, len(pass.Activated), len(pass.Failed))
+		for i, batch := range planResult.Batches {
+			cycle := ""
+			if batch.Cycle {
+				cycle = " (cycle)"
+			}
+			fmt.Fprintf(&sb, "  Batch %d, round %d%s: %d activated, %d failed\n", i+1, batch.Round, cycle, len(batch.Activated), len(batch.Failed))
 		}
-		fmt.Fprintf(&sb, "  Total: %d activated across %d pass(es)\n", iterResult.TotalActivated, iterResult.Iterations)
-		if iterResult.StillInactive == 0 {
+		fmt.Fprintf(&sb, "  Total: %s\n", planResult.Summary)
+		if len(planResult.Failed) == 0 {
 			sb.WriteString("  All objects active — deployment verified.\n")
-		} else {
-			fmt.Fprintf(&sb, "  WARNING: %d object(s) still inactive in %s\n", iterResult.StillInactive, packageName)
+		}
+		for _, cause := range planResult.RootCauses {
+			fmt.Fprintf(&sb, "  ROOT CAUSE %s %s", cause.Type, cause.Object)
+			if len(cause.Errors) > 0 {
+				fmt.Fprintf(&sb, ": %s", cause.Errors[0].ShortText)
+			}
+			sb.WriteString("\n")
+			if len(cause.Blocked) > 0 {
+				fmt.Fprintf(&sb, "    blocks: %s\n", strings.Join(cause.Blocked, ", "))
+			}
 		}
 	}
 
@@ -290,8 +302,8 @@ func (s *Server) handleDeployZip(ctx context.Context, request mcp.CallToolReques
 	fmt.Fprintf(&sb, "Deployment complete:\n")
 	fmt.Fprintf(&sb, "  Phase 1 (Create):   %d ok, %d existed, %d failed\n", createSuccess, createSkipped, createFailed)
 	fmt.Fprintf(&sb, "  Phase 2 (Upload):   %d ok, %d failed\n", uploadSuccess, uploadFailed)
-	if iterResult != nil {
-		fmt.Fprintf(&sb, "  Phase 3 (Activate): %s\n", iterResult.Summary)
+	if planResult != nil {
+		fmt.Fprintf(&sb, "  Phase 3 (Activate): %s\n", planResult.Summary)
 	}
 
 	if len(uploadFailures) > 0 {
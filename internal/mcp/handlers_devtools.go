@@ -64,6 +64,19 @@ func (s *Server) handleActivatePackage(ctx context.Context, request mcp.CallTool
 		maxObjects = int(max)
 	}
 
+	if planned, ok := request.Params.Arguments["planned"].(bool); ok && planned {
+		opts := adt.ActivationPlanOptions{MaxObjects: maxObjects}
+		if rounds, ok := request.Params.Arguments["max_rounds"].(float64); ok && rounds > 0 {
+			opts.MaxRounds = int(rounds)
+		}
+		result, err := s.adtClient.ActivatePackagePlanned(ctx, packageName, opts)
+		if err != nil {
+			return newToolResultError(fmt.Sprintf("Planned activation failed: %v", err)), nil
+		}
+		output, _ := json.MarshalIndent(result, "", "  ")
+		return mcp.NewToolResultText(string(output)), nil
+	}
+
 	result, err := s.adtClient.ActivatePackage(ctx, packageName, maxObjects)
 	if err != nil {
 		return newToolResultError(fmt.Sprintf("Batch activation failed: %v", err)), nil
@@ -90,11 +103,38 @@ func (s *Server) handleRunUnitTests(ctx context.Context, request mcp.CallToolReq
 		flags.Long = true
 	}
 
-	result, err := s.adtClient.RunUnitTests(ctx, objectURL, &flags)
+	if withCoverage, ok := request.Params.Arguments["with_coverage"].(bool); ok && withCoverage {
+		flags.Coverage = true
+	}
+
+	opts := adt.UnitTestRunOptions{Flags: &flags}
+	if tests, ok := request.Params.Arguments["tests"].([]interface{}); ok {
+		for _, t := range tests {
+			if uri, ok := t.(string); ok && uri != "" {
+				opts.Tests = append(opts.Tests, uri)
+			}
+		}
+	}
+	opts.FailedOnly, _ = request.Params.Arguments["rerun_failed"].(bool)
+
+	// Runs are only recorded when history is requested or needed
+	var store *adt.UnitTestStore
+	if history, _ := request.Params.Arguments["history"].(bool); history || opts.FailedOnly {
+		store = adt.NewUnitTestStore("")
+	}
+
+	run, err := s.adtClient.RunUnitTestsTracked(ctx, objectURL, store, opts)
 	if err != nil {
 		return newToolResultError(fmt.Sprintf("Unit test run failed: %v", err)), nil
 	}
 
-	output, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(output)), nil
+	// Result fields stay at the top level; tests, diff and message are added
+	type output struct {
+		*adt.UnitTestResult
+		Tests   []string             `json:"tests,omitempty"`
+		Diff    *adt.UnitTestRunDiff `json:"diff,omitempty"`
+		Message string               `json:"message,omitempty"`
+	}
+	outputJSON, _ := json.MarshalIndent(output{UnitTestResult: run.Result, Tests: run.Tests, Diff: run.Diff, Message: run.Message}, "", "  ")
+	return mcp.NewToolResultText(string(outputJSON)), nil
 }
@@ -63,3 +63,45 @@ func (s *Server) handleGetDump(ctx context.Context, request mcp.CallToolRequest)
 	result, _ := json.MarshalIndent(dump, "", "  ")
 	return mcp.NewToolResultText(string(result)), nil
 }
+
+func (s *Server) handleAnalyzeDump(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	dumpID, ok := request.Params.Arguments["dump_id"].(string)
+	if !ok || dumpID == "" {
+		return newToolResultError("dump_id is required"), nil
+	}
+
+	opts := adt.DumpAnalysisOptions{Sessions: s.debugSessions}
+	if pm, ok := request.Params.Arguments["post_mortem"].(bool); ok {
+		opts.PostMortem = pm
+	}
+	if timeout, ok := request.Params.Arguments["listen_timeout"].(float64); ok && timeout > 0 {
+		opts.ListenTimeout = int(timeout)
+	}
+	if lines, ok := request.Params.Arguments["context_lines"].(float64); ok && lines > 0 {
+		opts.ContextLines = int(lines)
+	}
+	if frames, ok := request.Params.Arguments["max_frames"].(float64); ok && frames > 0 {
+		opts.MaxFrames = int(frames)
+	}
+
+	analysis, err := s.adtClient.AnalyzeDump(ctx, dumpID, opts)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("Failed to analyze dump: %v", err)), nil
+	}
+
+	// Recordings are only written on request, and only to the default store
+	if save, _ := request.Params.Arguments["save_recording"].(bool); save {
+		history, err := adt.NewHistoryManager(".vsp-recordings")
+		if err == nil {
+			err = history.SaveRecording(analysis.Recording)
+		}
+		if err != nil {
+			return newToolResultError(fmt.Sprintf("Failed to save dump recording: %v", err)), nil
+		}
+	} else {
+		analysis.RecordingID = ""
+	}
+
+	result, _ := json.MarshalIndent(analysis, "", "  ")
+	return mcp.NewToolResultText(string(result)), nil
+}
@@ -75,8 +75,25 @@ func (s *Server) handleGitExport(ctx context.Context, request mcp.CallToolReques
 		params.IncludeSubpackages = true // default
 	}
 
+	// Transport: export the objects of a request and its tasks
+	var plan *adt.TransportExportPlan
+	if nr, ok := request.Params.Arguments["transport"].(string); ok && nr != "" {
+		if len(params.Packages) > 0 || len(params.Objects) > 0 {
+			return newToolResultError("transport cannot be combined with packages or objects"), nil
+		}
+		var err error
+		plan, err = s.adtClient.PlanTransportExport(ctx, nr)
+		if err != nil {
+			return newToolResultError(fmt.Sprintf("GitExport failed: %v", err)), nil
+		}
+		if len(plan.Objects) == 0 {
+			return newToolResultError(fmt.Sprintf("Transport %s has no exportable objects", plan.Transport)), nil
+		}
+		params.Objects = plan.Objects
+	}
+
 	if len(params.Packages) == 0 && len(params.Objects) == 0 {
-		return newToolResultError("Either packages or objects parameter is required"), nil
+		return newToolResultError("Either packages, objects or transport parameter is required"), nil
 	}
 
 	result, err := s.amdpWSClient.GitExport(ctx, params)
@@ -92,7 +109,9 @@ func (s *Server) handleGitExport(ctx context.Context, request mcp.CallToolReques
 
 	// Generate filename with timestamp
 	var zipName string
-	if len(params.Packages) > 0 {
+	if plan != nil {
+		zipName = fmt.Sprintf("%s_%s.zip", strings.ToLower(plan.Transport), time.Now().Format("20060102_150405"))
+	} else if len(params.Packages) > 0 {
 		// Use first package name (sanitize $ for filename)
 		pkgName := strings.ReplaceAll(params.Packages[0], "$", "")
 		zipName = fmt.Sprintf("%s_%s.zip", pkgName, time.Now().Format("20060102_150405"))
@@ -122,5 +141,38 @@ func (s *Server) handleGitExport(ctx context.Context, request mcp.CallToolReques
 		fmt.Fprintf(&sb, "  %s (%d bytes)\n", f.Path, f.Size)
 	}
 
+	if plan != nil {
+		manifestPath, manifest, err := writeTransportExportManifest(plan, result, zipPath)
+		if err != nil {
+			return newToolResultError(fmt.Sprintf("Failed to write manifest: %v", err)), nil
+		}
+		fmt.Fprintf(&sb, "\nManifest: %s\n", manifestPath)
+		if len(manifest.NotSerialized) > 0 {
+			fmt.Fprintf(&sb, "\nNot serialized (%d):\n", len(manifest.NotSerialized))
+			for _, skip := range manifest.NotSerialized {
+				fmt.Fprintf(&sb, "  %s %s %s: %s\n", skip.PgmID, skip.Type, skip.Name, skip.Reason)
+			}
+		}
+	}
+
 	return mcp.NewToolResultText(sb.String()), nil
 }
+
+// writeTransportExportManifest writes the manifest of a transport export
+// next to its ZIP (<name>.manifest.json).
+func writeTransportExportManifest(plan *adt.TransportExportPlan, result *adt.GitExportResult, zipPath string) (string, *adt.TransportExportManifest, error) {
+	files := make([]string, 0, len(result.Files))
+	for _, f := range result.Files {
+		files = append(files, f.Path)
+	}
+	manifest := adt.NewTransportExportManifest(plan, files, adt.TransportExportAbapGit)
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", nil, err
+	}
+	manifestPath := strings.TrimSuffix(zipPath, ".zip") + ".manifest.json"
+	if err := os.WriteFile(manifestPath, append(data, '\n'), 0644); err != nil {
+		return "", nil, err
+	}
+	return manifestPath, manifest, nil
+}
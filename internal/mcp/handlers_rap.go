@@ -0,0 +1,42 @@
+// Package mcp provides the MCP server implementation for ABAP ADT tools.
+// handlers_rap.go contains the handler for the RAP stack generator.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/oisee/vibing-steampunk/pkg/dsl"
+)
+
+// --- RAP Generator Handlers ---
+
+func (s *Server) handleGenerateRAPStack(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	table, ok := request.Params.Arguments["table"].(string)
+	if !ok || table == "" {
+		return newToolResultError("table is required"), nil
+	}
+
+	var opts dsl.RAPStackOptions
+	opts.Entity, _ = request.Params.Arguments["entity"].(string)
+	opts.Prefix, _ = request.Params.Arguments["prefix"].(string)
+	opts.Description, _ = request.Params.Arguments["description"].(string)
+	opts.BindingVersion, _ = request.Params.Arguments["binding_version"].(string)
+	opts.Package, _ = request.Params.Arguments["package"].(string)
+	opts.Transport, _ = request.Params.Arguments["transport"].(string)
+	opts.OutputDir, _ = request.Params.Arguments["output_dir"].(string)
+	opts.DryRun, _ = request.Params.Arguments["dry_run"].(bool)
+	if draft, ok := request.Params.Arguments["draft"].(bool); ok {
+		opts.NoDraft = !draft
+	}
+
+	result, err := dsl.GenerateRAPStack(ctx, s.adtClient, table, opts)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("GenerateRAPStack failed: %v", err)), nil
+	}
+
+	output, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(dsl.RAPStackSummary(result) + "\n\n" + string(output)), nil
+}
@@ -54,18 +54,27 @@ func (s *Server) handleGetConnectionInfo(ctx context.Context, request mcp.CallTo
 }
 
 func (s *Server) handleGetFeatures(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Probe all features
+	if refresh, _ := request.Params.Arguments["refresh"].(bool); refresh {
+		s.featureProber.Refresh()
+	}
+
+	// Probe all features (fresh capability profile entries are reused)
 	results := s.featureProber.ProbeAll(ctx)
+	s.hideUnavailableTools(results)
 
 	// Format output
 	type featureOutput struct {
-		Features map[string]*adt.FeatureStatus `json:"features"`
-		Summary  string                        `json:"summary"`
+		Features    map[string]*adt.FeatureStatus `json:"features"`
+		Summary     string                        `json:"summary"`
+		HiddenTools map[string]string             `json:"hidden_tools,omitempty"` // Tool -> why the system cannot serve it
+		Profile     string                        `json:"profile,omitempty"`
 	}
 
 	output := featureOutput{
-		Features: make(map[string]*adt.FeatureStatus),
-		Summary:  s.featureProber.FeatureSummary(ctx),
+		Features:    make(map[string]*adt.FeatureStatus),
+		Summary:     s.featureProber.FeatureSummary(ctx),
+		HiddenTools: s.HiddenTools(),
+		Profile:     s.featureProber.ProfilePath(),
 	}
 
 	for id, status := range results {
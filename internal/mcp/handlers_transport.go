@@ -136,6 +136,14 @@ func (s *Server) handleExecuteABAP(ctx context.Context, request mcp.CallToolRequ
 		opts.ProgramPrefix = prefix
 	}
 
+	if pkg, ok := request.Params.Arguments["package"].(string); ok && pkg != "" {
+		opts.Package = pkg
+	}
+
+	if runner, ok := request.Params.Arguments["runner"].(string); ok && runner != "" {
+		opts.Runner = runner
+	}
+
 	result, err := s.adtClient.ExecuteABAP(ctx, code, opts)
 	if err != nil {
 		return newToolResultError(fmt.Sprintf("ExecuteABAP failed: %v", err)), nil
@@ -270,9 +278,12 @@ func (s *Server) handleReleaseTransport(ctx context.Context, request mcp.CallToo
 	ignoreLocks, _ := request.Params.Arguments["ignore_locks"].(bool)
 	skipATC, _ := request.Params.Arguments["skip_atc"].(bool)
 
+	check, _ := request.Params.Arguments["check"].(bool)
+
 	opts := adt.ReleaseTransportOptions{
 		IgnoreLocks: ignoreLocks,
 		SkipATC:     skipATC,
+		Check:       check,
 	}
 
 	err := s.adtClient.ReleaseTransportV2(ctx, transport, opts)
@@ -283,6 +294,51 @@ func (s *Server) handleReleaseTransport(ctx context.Context, request mcp.CallToo
 	return mcp.NewToolResultText(fmt.Sprintf("Transport %s released successfully.", transport)), nil
 }
 
+func (s *Server) handlePreReleaseCheck(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	transport, ok := request.Params.Arguments["transport"].(string)
+	if !ok || transport == "" {
+		return newToolResultError("transport is required"), nil
+	}
+
+	// Check safety config for transport operations
+	if err := s.adtClient.Safety().CheckTransport(transport, "PreReleaseCheck", false); err != nil {
+		return newToolResultError(err.Error()), nil
+	}
+
+	opts := adt.PreReleaseCheckOptions{}
+	opts.ATCVariant, _ = request.Params.Arguments["atc_variant"].(string)
+	if p, ok := request.Params.Arguments["atc_max_priority"].(float64); ok {
+		opts.ATCMaxPriority = int(p)
+	}
+	opts.SkipSyntax, _ = request.Params.Arguments["skip_syntax"].(bool)
+	opts.SkipATC, _ = request.Params.Arguments["skip_atc"].(bool)
+	opts.SkipUnitTests, _ = request.Params.Arguments["skip_unit_tests"].(bool)
+	opts.SkipLocks, _ = request.Params.Arguments["skip_locks"].(bool)
+	opts.SkipReferences, _ = request.Params.Arguments["skip_references"].(bool)
+
+	result, err := s.adtClient.PreReleaseCheck(ctx, transport, opts)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("PreReleaseCheck failed: %v", err)), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(result.Summary() + "\n")
+	for _, issue := range result.Issues {
+		mark := "WARN"
+		if issue.Blocking {
+			mark = "FAIL"
+		}
+		fmt.Fprintf(&sb, "  %s [%s] %s: %s\n", mark, issue.Check, issue.Object, issue.Message)
+	}
+	if len(result.Skipped) > 0 {
+		fmt.Fprintf(&sb, "Not checked: %s\n", strings.Join(result.Skipped, ", "))
+	}
+	for _, note := range result.Notes {
+		fmt.Fprintf(&sb, "Note: %s\n", note)
+	}
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
 func (s *Server) handleDeleteTransport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	transport, ok := request.Params.Arguments["transport"].(string)
 	if !ok || transport == "" {
@@ -301,3 +357,120 @@ func (s *Server) handleDeleteTransport(ctx context.Context, request mcp.CallTool
 
 	return mcp.NewToolResultText(fmt.Sprintf("Transport %s deleted successfully.", transport)), nil
 }
+
+// transportObjectsArg parses the objects argument of the object list tools.
+func transportObjectsArg(request mcp.CallToolRequest) ([]adt.TransportObjectV2, error) {
+	raw, ok := request.Params.Arguments["objects"].([]interface{})
+	if !ok || len(raw) == 0 {
+		return nil, fmt.Errorf("objects is required")
+	}
+	var values []string
+	for _, v := range raw {
+		if str, ok := v.(string); ok {
+			values = append(values, str)
+		}
+	}
+	return adt.ParseTransportObjects(values)
+}
+
+func formatTransportObjectsResult(sb *strings.Builder, result *adt.TransportObjectsResult) {
+	for _, obj := range result.Objects {
+		fmt.Fprintf(sb, "  %s %s %s\n", obj.PgmID, obj.Type, obj.Name)
+	}
+	if result.Message != "" {
+		fmt.Fprintf(sb, "Note: %s\n", result.Message)
+	}
+}
+
+func (s *Server) handleAddTransportObjects(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	transport, ok := request.Params.Arguments["transport"].(string)
+	if !ok || transport == "" {
+		return newToolResultError("transport is required"), nil
+	}
+	objects, err := transportObjectsArg(request)
+	if err != nil {
+		return newToolResultError(err.Error()), nil
+	}
+
+	result, err := s.adtClient.AddTransportObjects(ctx, transport, objects)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("AddTransportObjects failed: %v", err)), nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Added %d entries to %s:\n", result.Count, result.Transport)
+	formatTransportObjectsResult(&sb, result)
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+func (s *Server) handleRemoveTransportObjects(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	transport, ok := request.Params.Arguments["transport"].(string)
+	if !ok || transport == "" {
+		return newToolResultError("transport is required"), nil
+	}
+	objects, err := transportObjectsArg(request)
+	if err != nil {
+		return newToolResultError(err.Error()), nil
+	}
+
+	result, err := s.adtClient.RemoveTransportObjects(ctx, transport, objects)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("RemoveTransportObjects failed: %v", err)), nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Removed %d entries from %s:\n", result.Count, result.Transport)
+	formatTransportObjectsResult(&sb, result)
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+func (s *Server) handleMergeTransportTasks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	source, ok := request.Params.Arguments["source"].(string)
+	if !ok || source == "" {
+		return newToolResultError("source is required"), nil
+	}
+	target, ok := request.Params.Arguments["target"].(string)
+	if !ok || target == "" {
+		return newToolResultError("target is required"), nil
+	}
+
+	result, err := s.adtClient.MergeTransportTasks(ctx, source, target)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("MergeTransportTasks failed: %v", err)), nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Moved %d entries from %s to %s", result.Count, strings.Join(result.Sources, ", "), result.Transport)
+	if result.Deleted != "" {
+		fmt.Fprintf(&sb, ", task %s deleted", result.Deleted)
+	}
+	sb.WriteString(":\n")
+	formatTransportObjectsResult(&sb, result)
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+func (s *Server) handleCreateTransportOfCopies(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	transport, ok := request.Params.Arguments["transport"].(string)
+	if !ok || transport == "" {
+		return newToolResultError("transport is required"), nil
+	}
+
+	opts := adt.TransportOfCopiesOptions{}
+	opts.Target, _ = request.Params.Arguments["target"].(string)
+	opts.Description, _ = request.Params.Arguments["description"].(string)
+	opts.Release, _ = request.Params.Arguments["release"].(bool)
+
+	result, err := s.adtClient.CreateTransportOfCopies(ctx, transport, opts)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("CreateTransportOfCopies failed: %v", err)), nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Transport of copies %s created from %s with %d entries", result.Transport, strings.Join(result.Sources, ", "), result.Count)
+	if result.Released {
+		sb.WriteString(", released")
+	}
+	sb.WriteString(":\n")
+	formatTransportObjectsResult(&sb, result)
+	return mcp.NewToolResultText(sb.String()), nil
+}
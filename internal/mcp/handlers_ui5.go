@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/oisee/vibing-steampunk/pkg/adt"
 )
 
 // --- UI5/Fiori BSP Management Handlers ---
@@ -186,3 +187,73 @@ func (s *Server) handleUI5DeleteApp(ctx context.Context, request mcp.CallToolReq
 
 	return mcp.NewToolResultText(fmt.Sprintf("Successfully deleted UI5 application %s", appName)), nil
 }
+
+func (s *Server) handleUI5Pull(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	appName, ok := request.Params.Arguments["app_name"].(string)
+	if !ok || appName == "" {
+		return newToolResultError("app_name is required"), nil
+	}
+
+	dir, ok := request.Params.Arguments["dir"].(string)
+	if !ok || dir == "" {
+		return newToolResultError("dir is required"), nil
+	}
+
+	opts := adt.UI5SyncOptions{}
+	opts.DryRun, _ = request.Params.Arguments["dry_run"].(bool)
+
+	result, err := s.adtClient.UI5Pull(ctx, appName, dir, opts)
+	if err != nil {
+		return newToolResultError(fmt.Sprintf("UI5Pull failed: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(formatUI5SyncResult(result)), nil
+}
+
+func (s *Server) handleUI5Push(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	dir, ok := request.Params.Arguments["dir"].(string)
+	if !ok || dir == "" {
+		return newToolResultError("dir is required"), nil
+	}
+
+	appName, _ := request.Params.Arguments["app_name"].(string)
+	opts := adt.UI5SyncOptions{}
+	opts.Package, _ = request.Params.Arguments["package"].(string)
+	opts.Transport, _ = request.Params.Arguments["transport"].(string)
+	opts.Source, _ = request.Params.Arguments["source"].(string)
+	opts.KeepRemoved, _ = request.Params.Arguments["keep_removed"].(bool)
+	opts.DryRun, _ = request.Params.Arguments["dry_run"].(bool)
+	opts.Backend, _ = request.Params.Arguments["backend"].(string)
+	opts.UnsafeMode, _ = request.Params.Arguments["unsafe"].(bool)
+
+	result, err := s.adtClient.UI5Push(ctx, dir, appName, opts)
+	if err != nil {
+		if result != nil {
+			return newToolResultError(fmt.Sprintf("UI5Push failed: %v\n\nDone before the error:\n%s", err, formatUI5SyncResult(result))), nil
+		}
+		return newToolResultError(fmt.Sprintf("UI5Push failed: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(formatUI5SyncResult(result)), nil
+}
+
+func formatUI5SyncResult(result *adt.UI5SyncResult) string {
+	var sb strings.Builder
+	sb.WriteString(result.Summary() + "\n")
+	if result.AppID != "" {
+		fmt.Fprintf(&sb, "App ID: %s\n", result.AppID)
+	}
+	for _, p := range result.Created {
+		fmt.Fprintf(&sb, "  + %s\n", p)
+	}
+	for _, p := range result.Updated {
+		fmt.Fprintf(&sb, "  M %s\n", p)
+	}
+	for _, p := range result.Deleted {
+		fmt.Fprintf(&sb, "  - %s\n", p)
+	}
+	for _, m := range result.Messages {
+		fmt.Fprintf(&sb, "  %s\n", m)
+	}
+	return sb.String()
+}
@@ -4,6 +4,7 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -30,9 +31,21 @@ type Server struct {
 	adtClient      *adt.Client
 	amdpWSClient   *adt.AMDPWebSocketClient   // WebSocket-based AMDP client (ZADT_VSP)
 	debugWSClient  *adt.DebugWebSocketClient  // WebSocket-based debug client (ZADT_VSP)
+	debugSessions  *adt.DebugSessionManager   // Concurrent REST debug sessions by session ID
 	config         *Config                    // Server configuration for session manager creation
 	featureProber  *adt.FeatureProber         // Feature detection system (safety network)
 	featureConfig  adt.FeatureConfig          // Feature configuration
+	impactCache    *adt.ImpactCache           // Lookups reused by ImpactAnalysis
+
+	// ZADT_VSP clients of named debug sessions (session argument), one
+	// WebSocket connection each
+	debugWSMu       sync.Mutex
+	debugWSSessions map[string]*adt.DebugWebSocketClient
+
+	// Tools hidden because the system cannot serve them (see capabilities.go)
+	toolsMu         sync.Mutex
+	registeredTools map[string]bool   // Registered tools that may be hidden (not enabled explicitly)
+	hiddenTools     map[string]string // Tool -> reason
 
 	// Async task management
 	asyncTasks   map[string]*AsyncTask
@@ -77,12 +90,28 @@ type Config struct {
 
 	// Feature configuration (safety network)
 	// Values: "auto" (default, probe system), "on" (force enabled), "off" (force disabled)
-	FeatureHANA      string // HANA database detection (required for some AMDP features)
-	FeatureAbapGit   string // abapGit integration
-	FeatureRAP       string // RAP/OData development (DDLS, BDEF, SRVD, SRVB)
-	FeatureAMDP      string // AMDP/HANA debugger
-	FeatureUI5       string // UI5/Fiori BSP management
-	FeatureTransport string // CTS transport management (distinct from EnableTransports safety)
+	FeatureHANA         string // HANA database detection (required for some AMDP features)
+	FeatureAbapGit      string // abapGit integration
+	FeatureRAP          string // RAP/OData development (DDLS, BDEF, SRVD, SRVB)
+	FeatureAMDP         string // AMDP/HANA debugger
+	FeatureUI5          string // UI5/Fiori BSP management
+	FeatureTransport    string // CTS transport management (distinct from EnableTransports safety)
+	FeatureCloud        string // ABAP Cloud detection (hides tools that need classic ABAP)
+	FeatureZADTVSP      string // ZADT_VSP WebSocket handler (version compatibility)
+	FeatureATC          string // ABAP Test Cockpit
+	FeatureRefactoring  string // ADT refactoring API
+	FeatureCoverage     string // ABAP Unit code coverage
+	FeatureEnhancements string // Enhancement framework
+
+	// Capability profile: probe results cached per system
+	CapabilityProfileDir string        // Directory of the profiles (empty: no profile, no probing at start)
+	CapabilityProfileTTL time.Duration // Reuse of probe results (default: 24h, negative: no profile, no probing at start)
+
+	// UI5 deployment backend for UI5Push: "odata" (default) or "filestore"
+	UI5Backend string
+
+	// OAuth bearer token authentication (BTP ABAP Environment service key)
+	OAuth *adt.OAuthConfig
 
 	// Debugger configuration
 	TerminalID string // SAP GUI terminal ID for cross-tool breakpoint sharing
@@ -109,6 +138,12 @@ func NewServer(cfg *Config) *Server {
 	if cfg.Verbose {
 		opts = append(opts, adt.WithVerbose())
 	}
+	if cfg.UI5Backend != "" {
+		opts = append(opts, adt.WithUI5Backend(cfg.UI5Backend))
+	}
+	if cfg.OAuth != nil {
+		opts = append(opts, adt.WithOAuth(cfg.OAuth))
+	}
 
 	// Configure safety settings
 	safety := adt.UnrestrictedSafetyConfig() // Default: unrestricted for backwards compatibility
@@ -152,16 +187,29 @@ func NewServer(cfg *Config) *Server {
 
 	// Configure feature detection (safety network)
 	featureConfig := adt.FeatureConfig{
-		HANA:      parseFeatureMode(cfg.FeatureHANA),
-		AbapGit:   parseFeatureMode(cfg.FeatureAbapGit),
-		RAP:       parseFeatureMode(cfg.FeatureRAP),
-		AMDP:      parseFeatureMode(cfg.FeatureAMDP),
-		UI5:       parseFeatureMode(cfg.FeatureUI5),
-		Transport: parseFeatureMode(cfg.FeatureTransport),
-	}
-
-	// Create feature prober
+		HANA:         parseFeatureMode(cfg.FeatureHANA),
+		AbapGit:      parseFeatureMode(cfg.FeatureAbapGit),
+		RAP:          parseFeatureMode(cfg.FeatureRAP),
+		AMDP:         parseFeatureMode(cfg.FeatureAMDP),
+		UI5:          parseFeatureMode(cfg.FeatureUI5),
+		Transport:    parseFeatureMode(cfg.FeatureTransport),
+		Cloud:        parseFeatureMode(cfg.FeatureCloud),
+		ZADTVSP:      parseFeatureMode(cfg.FeatureZADTVSP),
+		ATC:          parseFeatureMode(cfg.FeatureATC),
+		Refactoring:  parseFeatureMode(cfg.FeatureRefactoring),
+		Coverage:     parseFeatureMode(cfg.FeatureCoverage),
+		Enhancements: parseFeatureMode(cfg.FeatureEnhancements),
+	}
+
+	// Create feature prober, backed by the capability profile of the system
 	featureProber := adt.NewFeatureProber(adtClient, featureConfig, cfg.Verbose)
+	useProfile := cfg.CapabilityProfileDir != "" && cfg.CapabilityProfileTTL >= 0
+	if useProfile {
+		store := adt.NewCapabilityProfileStore(cfg.CapabilityProfileDir, cfg.CapabilityProfileTTL)
+		if err := featureProber.UseProfile(store); err != nil && cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Capability profile ignored: %v\n", err)
+		}
+	}
 
 	// Create MCP server
 	mcpServer := server.NewMCPServer(
@@ -172,17 +220,28 @@ func NewServer(cfg *Config) *Server {
 	)
 
 	s := &Server{
-		mcpServer:     mcpServer,
-		adtClient:     adtClient,
-		config:        cfg,
-		featureProber: featureProber,
-		featureConfig: featureConfig,
-		asyncTasks:    make(map[string]*AsyncTask),
+		mcpServer:       mcpServer,
+		adtClient:       adtClient,
+		debugSessions:   adt.NewDebugSessionManager(adtClient),
+		debugWSSessions: make(map[string]*adt.DebugWebSocketClient),
+		config:          cfg,
+		featureProber:   featureProber,
+		featureConfig:   featureConfig,
+		asyncTasks:      make(map[string]*AsyncTask),
+		impactCache:     adt.NewImpactCache(0),
+		registeredTools: make(map[string]bool),
+		hiddenTools:     make(map[string]string),
 	}
 
 	// Register tools based on mode, disabled groups, and granular tool config
 	s.registerTools(cfg.Mode, cfg.DisabledGroups, cfg.ToolsConfig)
 
+	// Probe what the capability profile does not know yet in the background;
+	// tools the system cannot serve are removed when the results come in
+	if useProfile && len(featureProber.Known()) < len(adt.AllFeatures) {
+		go s.probeCapabilities()
+	}
+
 	return s
 }
 
@@ -207,11 +266,11 @@ func (s *Server) ServeStdio() error {
 // Mode "focused" registers essential tools.
 // Mode "expert" registers all tools.
 // DisabledGroups can disable specific tool groups using short codes:
-//   - "5" or "U" = UI5/BSP tools (3 tools, read-only)
+//   - "5" or "U" = UI5/BSP tools (5 tools, UI5Push writes)
 //   - "T" = Test tools: RunUnitTests, RunATCCheck (2 tools)
 //   - "H" = HANA/AMDP debugger (7 tools)
 //   - "D" = ABAP Debugger (6 session tools)
-//   - "C" = CTS/Transport tools (5 tools)
+//   - "C" = CTS/Transport tools (10 tools)
 //   - "G" = Git/abapGit tools (2 tools)
 //   - "R" = Report tools (4 tools)
 //   - "I" = Install tools (4 tools)
@@ -225,23 +284,25 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 	// Define tool groups for selective disablement
 	// Short codes: 5/U=UI5, T=Tests, H=HANA, D=Debug, C=CTS, X=Experimental
 	toolGroups := map[string][]string{
-		"5": { // UI5/BSP tools (also mapped as "U") - read-only, write ops need custom plugin
-			"UI5ListApps", "UI5GetApp", "UI5GetFileContent",
+		"5": { // UI5/BSP tools (also mapped as "U") - UI5Push deploys via the ABAP repository service
+			"UI5ListApps", "UI5GetApp", "UI5GetFileContent", "UI5Pull", "UI5Push",
 		},
 		"T": { // Test tools
-			"RunUnitTests", "RunATCCheck",
+			"RunUnitTests", "RunATCCheck", "ATCQuickFix", "ATCRequestExemption",
 		},
 		"H": { // HANA/AMDP debugger
 			"AMDPDebuggerStart", "AMDPDebuggerResume", "AMDPDebuggerStop",
 			"AMDPDebuggerStep", "AMDPGetVariables", "AMDPSetBreakpoint", "AMDPGetBreakpoints",
 		},
 		"D": { // ABAP debugger (session tools - breakpoints via WebSocket ZADT_VSP)
-			"DebuggerListen", "DebuggerAttach", "DebuggerDetach",
+			"DebuggerListen", "DebuggerAttach", "DebuggerDetach", "DebuggerSessions",
 			"DebuggerStep", "DebuggerGetStack", "DebuggerGetVariables",
+			"DebuggerEvaluate", "DebuggerReadTable", "DebuggerCollectLogPoints",
 		},
 		"C": { // CTS/Transport tools
-			"ListTransports", "GetTransport",
+			"ListTransports", "GetTransport", "PreReleaseCheck",
 			"CreateTransport", "ReleaseTransport", "DeleteTransport",
+			"AddTransportObjects", "RemoveTransportObjects", "MergeTransportTasks", "CreateTransportOfCopies",
 		},
 		"G": { // Git/abapGit tools (via ZADT_VSP WebSocket)
 			"GitTypes", "GitExport",
@@ -257,9 +318,10 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		},
 		"X": { // EXPERIMENTAL - Tools requiring special setup or with known limitations
 			// ABAP Debugger - requires ZADT_VSP WebSocket handler
-			"SetBreakpoint", "GetBreakpoints", "DeleteBreakpoint",
-			"DebuggerListen", "DebuggerAttach", "DebuggerDetach",
+			"SetBreakpoint", "GetBreakpoints", "DeleteBreakpoint", "DebuggerCollectLogPoints",
+			"DebuggerListen", "DebuggerAttach", "DebuggerDetach", "DebuggerSessions",
 			"DebuggerStep", "DebuggerGetStack", "DebuggerGetVariables",
+			"DebuggerEvaluate", "DebuggerReadTable",
 			// AMDP/HANA Debugger - experimental, session management issues
 			"AMDPDebuggerStart", "AMDPDebuggerResume", "AMDPDebuggerStop",
 			"AMDPDebuggerStep", "AMDPGetVariables", "AMDPSetBreakpoint", "AMDPGetBreakpoints",
@@ -294,13 +356,16 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		// Primary workflow (1)
 		"EditSource": true,
 
-		// Data/Metadata read (6)
+		// Data/Metadata read (9)
 		"GetTable":            true,
 		"GetTableContents":    true,
 		"RunQuery":            true,
 		"GetPackage":          true, // Metadata: package contents
 		"GetFunctionGroup":    true, // Metadata: function module list
 		"GetCDSDependencies":  true, // CDS dependency tree
+		"GetCDSElementInfo":   true, // CDS fields, types, associations
+		"GetCDSAnnotations":   true, // Active annotations incl. metadata extensions
+		"PreviewCDS":          true, // CDS data with parameters/associations
 		"GetMessages":         true, // Message class texts (SE91)
 
 		// Code intelligence (2)
@@ -310,6 +375,7 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		// Development tools (11)
 		"SyntaxCheck":         true,
 		"RunUnitTests":        true,
+		"RunClass":            true,  // Console output of IF_OO_ADT_CLASSRUN classes
 		"RunATCCheck":         true,  // Code quality checks
 		"Activate":            true,  // Re-activate objects without editing
 		"ActivatePackage":     true,  // Batch activation of all inactive objects
@@ -333,6 +399,10 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		"GetSystemInfo":         true, // System ID, release, kernel
 		"GetInstalledComponents": true, // Installed software components
 
+		// ABAP Cloud (2)
+		"GetAPIReleaseState": true, // Release state of an SAP object
+		"CheckReleasedAPIs":  true, // Released-API check of source code
+
 		// Code analysis (7)
 		"GetCallGraph":       true, // Call hierarchy for methods/functions
 		"GetObjectStructure": true, // Object explorer tree
@@ -341,10 +411,12 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		"AnalyzeCallGraph":   true, // Call graph statistics
 		"CompareCallGraphs":  true, // Compare static vs actual execution
 		"TraceExecution":     true, // Composite RCA tool
+		"ImpactAnalysis":     true, // Affected objects and tests of a change
 
-		// Runtime errors / Short dumps (2)
-		"ListDumps": true, // List runtime errors (consistent with List* pattern)
-		"GetDump":   true, // Get dump details
+		// Runtime errors / Short dumps (3)
+		"ListDumps":   true, // List runtime errors (consistent with List* pattern)
+		"GetDump":     true, // Get dump details
+		"AnalyzeDump": true, // Stack with source context + recording of the crash state
 
 		// ABAP Profiler / Traces (2)
 		"ListTraces": true, // List trace files
@@ -359,23 +431,29 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		"SetBreakpoint":    true, // Set line breakpoint
 		"GetBreakpoints":   true, // List active breakpoints
 		"DeleteBreakpoint": true, // Remove breakpoint
+		"DebuggerCollectLogPoints": true, // Run past log points, collect values
 		"CallRFC":          true, // Call function module via WebSocket (trigger execution)
 		"MoveObject":       true, // Move object to different package
 
-		// Debugger Session (6)
+		// Debugger Session (9)
 		"DebuggerListen":       true, // Wait for debuggee to hit breakpoint
 		"DebuggerAttach":       true, // Attach to debuggee
 		"DebuggerDetach":       true, // Detach from debug session
+		"DebuggerSessions":     true, // List concurrent debug sessions
 		"DebuggerStep":         true, // Step through code
 		"DebuggerGetStack":     true, // Get call stack
 		"DebuggerGetVariables": true, // Get variable values
+		"DebuggerEvaluate":     true, // Evaluate variable expression
+		"DebuggerReadTable":    true, // Page through internal table
 
-		// UI5/Fiori BSP Management (3 read-only - ADT filestore is read-only)
+		// UI5/Fiori BSP Management (4 read-only; UI5Push deploys in expert mode)
 		"UI5ListApps":       true, // List UI5 applications
 		"UI5GetApp":         true, // Get UI5 app details
 		"UI5GetFileContent": true, // Get file content from UI5 app
-		// Write ops disabled - ADT filestore API is read-only (405 on POST)
-		// Future: implement via custom plugin using /UI5/CL_REPOSITORY_LOAD
+		"UI5Pull":           true, // Download app into a local folder
+		// Single-file write ops stay disabled - the ADT filestore rejects
+		// writes (405 on POST) on many releases; UI5Push uses the UI5 ABAP
+		// repository service (/UI5/ABAP_REPOSITORY_SRV) instead
 		// "UI5UploadFile":     true, // Upload file to UI5 app
 		// "UI5DeleteFile":     true, // Delete file from UI5 app
 		// "UI5CreateApp":      true, // Create new UI5 app
@@ -390,10 +468,11 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		"AMDPSetBreakpoint":  true,
 		"AMDPGetBreakpoints": true,
 
-		// CTS/Transport Management (2 read-only in focused mode)
+		// CTS/Transport Management (3 read-only in focused mode)
 		// Write operations (Create, Release, Delete) only in expert mode
-		"ListTransports": true, // List transport requests
-		"GetTransport":   true, // Get transport details with objects
+		"ListTransports":  true, // List transport requests
+		"GetTransport":    true, // Get transport details with objects
+		"PreReleaseCheck": true, // Check transport objects before release
 
 		// Git/abapGit Integration (via ZADT_VSP WebSocket)
 		"GitTypes":  true, // List 158 supported object types
@@ -414,6 +493,11 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		"InstallDummyTest": true, // Test tool for verifying Install* workflow
 	}
 
+	// Tools the system cannot serve according to forced feature modes and
+	// the capability profile (no probing here)
+	knownFeatures := s.featureProber.Known()
+	s.applyCloudMode(knownFeatures)
+
 	// Helper to check if tool should be registered
 	shouldRegister := func(toolName string) bool {
 		// Priority 1: Check granular tool config from .vsp.json (highest priority)
@@ -427,10 +511,18 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 			return false
 		}
 		// Priority 3: Check mode
-		if mode == "expert" {
-			return true // Expert mode: register all tools (except disabled)
+		if mode != "expert" && !focusedTools[toolName] {
+			return false // Focused mode: only whitelisted tools (except disabled)
+		}
+		// Priority 4: Hide tools the system cannot serve
+		s.toolsMu.Lock()
+		defer s.toolsMu.Unlock()
+		if reason := toolUnavailableReason(toolName, knownFeatures); reason != "" {
+			s.hiddenTools[toolName] = reason
+			return false
 		}
-		return focusedTools[toolName] // Focused mode: only whitelisted tools (except disabled)
+		s.registeredTools[toolName] = true
+		return true
 	}
 
 	// Unified Tools (Focused Mode) - NEW
@@ -583,6 +675,66 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 	), s.handleGetCDSDependencies)
 	}
 
+	// GetCDSElementInfo
+	if shouldRegister("GetCDSElementInfo") {
+		s.mcpServer.AddTool(mcp.NewTool("GetCDSElementInfo",
+			mcp.WithDescription("Get the element info of a CDS entity as resolved by ADT: fields with key flag, data element, DDIC type, length and label, parameters, associations with their targets, and entity/field annotations. Use before writing code or annotations against a CDS view instead of guessing field names."),
+			mcp.WithString("entity",
+				mcp.Required(),
+				mcp.Description("CDS entity name (e.g., 'I_COUNTRY', '/DMO/I_TRAVEL_U')"),
+			),
+			mcp.WithBoolean("include_properties",
+				mcp.Description("Include all raw ADT properties per element (default: false)"),
+			),
+		), s.handleGetCDSElementInfo)
+	}
+
+	// GetCDSAnnotations
+	if shouldRegister("GetCDSAnnotations") {
+		s.mcpServer.AddTool(mcp.NewTool("GetCDSAnnotations",
+			mcp.WithDescription("Get the active annotations of a CDS entity: annotations resolved by ADT merged with its metadata extensions (DDLX) by layer (CORE < LOCALIZATION < INDUSTRY < PARTNER < CUSTOMER). Each annotation names the object it comes from. Keys are flattened (UI.LINEITEM$1$.POSITION)."),
+			mcp.WithString("entity",
+				mcp.Required(),
+				mcp.Description("CDS entity name (e.g., 'ZC_TRAVEL')"),
+			),
+			mcp.WithString("extensions",
+				mcp.Description("Comma-separated metadata extensions to merge (default: all found via where-used)"),
+			),
+			mcp.WithString("element",
+				mcp.Description("Only return the annotations of this element"),
+			),
+		), s.handleGetCDSAnnotations)
+	}
+
+	// PreviewCDS
+	if shouldRegister("PreviewCDS") {
+		s.mcpServer.AddTool(mcp.NewTool("PreviewCDS",
+			mcp.WithDescription("Read data of a CDS entity (data preview). Supports entity parameters, following an association path (rows of the association target) and ABAP SQL filter/order."),
+			mcp.WithString("entity",
+				mcp.Required(),
+				mcp.Description("CDS entity name (e.g., 'ZI_TRAVEL')"),
+			),
+			mcp.WithString("parameters",
+				mcp.Description("JSON object of entity parameters: {\"P_KEYDATE\":\"20240101\"}"),
+			),
+			mcp.WithString("association",
+				mcp.Description("Association path to follow, e.g. '_Booking' or '_Booking\\_Customer'"),
+			),
+			mcp.WithString("fields",
+				mcp.Description("Comma-separated fields to select (default: all)"),
+			),
+			mcp.WithString("where",
+				mcp.Description("ABAP SQL condition, e.g. \"Status = 'O'\""),
+			),
+			mcp.WithString("order_by",
+				mcp.Description("ABAP SQL ORDER BY list"),
+			),
+			mcp.WithNumber("max_rows",
+				mcp.Description("Maximum number of rows (default 100)"),
+			),
+		), s.handlePreviewCDS)
+	}
+
 
 	// GetStructure
 	if shouldRegister("GetStructure") {
@@ -659,6 +811,33 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		), s.handleGetInstalledComponents)
 	}
 
+	// GetAPIReleaseState
+	if shouldRegister("GetAPIReleaseState") {
+		s.mcpServer.AddTool(mcp.NewTool("GetAPIReleaseState",
+			mcp.WithDescription("Get the API release state of an SAP object (class, interface, CDS entity, table): release contracts C0-C4 and whether it may be used in ABAP Cloud (C1, cloud development), plus the successor of deprecated objects."),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("Object name (e.g., 'CL_ABAP_CONTEXT_INFO', 'I_COUNTRY')"),
+			),
+		), s.handleGetAPIReleaseState)
+	}
+
+	// CheckReleasedAPIs
+	if shouldRegister("CheckReleasedAPIs") {
+		s.mcpServer.AddTool(mcp.NewTool("CheckReleasedAPIs",
+			mcp.WithDescription("Check ABAP or CDS source code against the released APIs of ABAP Cloud: finds the SAP classes, interfaces, tables, CDS entities and function modules it uses and reports those not released for cloud development. On ABAP Cloud systems WriteSource, EditSource and ExecuteABAP refuse such code."),
+			mcp.WithString("source",
+				mcp.Description("Source code to check"),
+			),
+			mcp.WithString("object_type",
+				mcp.Description("Check an existing object instead: PROG, CLAS, INTF, FUNC, DDLS, BDEF, ..."),
+			),
+			mcp.WithString("name",
+				mcp.Description("Name of the existing object"),
+			),
+		), s.handleCheckReleasedAPIs)
+	}
+
 	// GetConnectionInfo - Self-inspection tool
 	// Always registered - useful for debugging and introspection
 	s.mcpServer.AddTool(mcp.NewTool("GetConnectionInfo",
@@ -668,7 +847,10 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 	// GetFeatures - Feature Detection (Safety Network)
 	// Always registered - provides visibility into what's available
 	s.mcpServer.AddTool(mcp.NewTool("GetFeatures",
-		mcp.WithDescription("Probe SAP system for available features. Returns status of optional capabilities like abapGit, RAP/OData, AMDP debugging, UI5/BSP, and CTS transports. Use this to understand what features are available before attempting to use them."),
+		mcp.WithDescription("Probe SAP system for available features. Returns status of optional capabilities like abapGit, RAP/OData, AMDP debugging, UI5/BSP, CTS transports, ZADT_VSP version, ABAP release/SP, ABAP Cloud, ATC, refactoring, coverage and enhancements, plus the tools hidden because the system cannot serve them and why. Results are cached per system in a capability profile when a capability directory is configured. Use this to understand what features are available before attempting to use them."),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Probe again, ignoring the cached capability profile (default: false)"),
+		),
 	), s.handleGetFeatures)
 
 	// GetAbapHelp - ABAP Keyword Documentation
@@ -800,6 +982,35 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		), s.handleTraceExecution)
 	}
 
+	// ImpactAnalysis - blast radius of a change
+	if shouldRegister("ImpactAnalysis") {
+		s.mcpServer.AddTool(mcp.NewTool("ImpactAnalysis",
+			mcp.WithDescription("Find what a change affects before making it. Walks where-used references and callers transitively, groups affected objects by package (flagging transportable ones) and lists the ABAP Unit tests that cover them. Pass the test objects to RunUnitTests."),
+			mcp.WithString("object_url",
+				mcp.Required(),
+				mcp.Description("ADT URL of the object to change (e.g., /sap/bc/adt/oo/classes/ZCL_TEST)"),
+			),
+			mcp.WithString("method",
+				mcp.Description("Class method to analyze instead of the whole class"),
+			),
+			mcp.WithNumber("max_depth",
+				mcp.Description("Levels of users to follow (default: 3)"),
+			),
+			mcp.WithNumber("max_objects",
+				mcp.Description("Maximum affected objects (default: 200)"),
+			),
+			mcp.WithBoolean("callers",
+				mcp.Description("Follow the call graph (default: true)"),
+			),
+			mcp.WithBoolean("references",
+				mcp.Description("Follow where-used references (default: true)"),
+			),
+			mcp.WithString("format",
+				mcp.Description("Output: json (default), tree, list or dot"),
+			),
+		), s.handleImpactAnalysis)
+	}
+
 	// --- Runtime Errors / Short Dumps (RABAX) ---
 
 	// ListDumps (renamed from GetDumps for consistency with List* pattern)
@@ -841,6 +1052,32 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		), s.handleGetDump)
 	}
 
+	// AnalyzeDump
+	if shouldRegister("AnalyzeDump") {
+		s.mcpServer.AddTool(mcp.NewTool("AnalyzeDump",
+			mcp.WithDescription("Post-mortem analysis of a short dump: stack with source context around each frame and the variables at the crash point. Optionally attaches to a post-mortem debuggee (only available for dumps raised while a debug listener was active). With save_recording, the crash state is saved in .vsp-recordings as an execution recording with a 'dump' checkpoint for ForceReplay."),
+			mcp.WithString("dump_id",
+				mcp.Required(),
+				mcp.Description("Dump ID from ListDumps result"),
+			),
+			mcp.WithBoolean("post_mortem",
+				mcp.Description("Try to attach to a post-mortem debuggee for live stack and variables (default: false). The session stays attached for the debugger tools."),
+			),
+			mcp.WithNumber("listen_timeout",
+				mcp.Description("Seconds to wait for the post-mortem debuggee (default: 5)"),
+			),
+			mcp.WithNumber("context_lines",
+				mcp.Description("Source lines before/after each frame line (default: 5)"),
+			),
+			mcp.WithNumber("max_frames",
+				mcp.Description("Number of frames to fetch source for (default: 10)"),
+			),
+			mcp.WithBoolean("save_recording",
+				mcp.Description("Save the crash state as an execution recording in .vsp-recordings (default: false)"),
+			),
+		), s.handleAnalyzeDump)
+	}
+
 	// --- ABAP Profiler / Runtime Traces (ATRA) ---
 
 	// ListTraces
@@ -905,9 +1142,9 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 	// SetBreakpoint - WebSocket-based (supports line, statement, and exception breakpoints)
 	if shouldRegister("SetBreakpoint") {
 		s.mcpServer.AddTool(mcp.NewTool("SetBreakpoint",
-			mcp.WithDescription("Set a breakpoint in ABAP code. Supports three types: 'line' (specific location), 'statement' (ABAP keyword), 'exception' (exception class). For class methods, use 'method' parameter for include-relative line numbers. Uses WebSocket connection to ZADT_VSP."),
+			mcp.WithDescription("Set a breakpoint in ABAP code. Supports four types: 'line' (specific location), 'statement' (ABAP keyword), 'exception' (exception class), 'watchpoint' (break when a variable changes; requires an attached debuggee). For class methods, use 'method' parameter for include-relative line numbers. Uses WebSocket connection to ZADT_VSP."),
 			mcp.WithString("kind",
-				mcp.Description("Breakpoint type: 'line' (default), 'statement', or 'exception'"),
+				mcp.Description("Breakpoint type: 'line' (default), 'statement', 'exception', or 'watchpoint'"),
 			),
 			mcp.WithString("program",
 				mcp.Description("Program name for line breakpoints (e.g., 'ZADT_DBG_PROG' or 'ZCL_MY_CLASS')"),
@@ -924,13 +1161,47 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 			mcp.WithString("exception",
 				mcp.Description("Exception class for exception breakpoints (e.g., 'CX_SY_ZERODIVIDE', 'CX_SY_OPEN_SQL_DB')"),
 			),
+			mcp.WithString("variable",
+				mcp.Description("Variable for watchpoints (e.g., 'LV_STATUS', 'ME->MV_STATE', 'LS_DATA-FIELD', '(ZPROG)GV_GLOBAL')"),
+			),
+			mcp.WithString("value",
+				mcp.Description("Watchpoints: only stop when the variable changes to this value"),
+			),
+			mcp.WithString("condition",
+				mcp.Description("Optional condition for line breakpoints and watchpoints, e.g. \"LV_COUNT > 10 AND LS_DATA-STATUS = 'E'\". Supports =, <>, <, >, <=, >= (or EQ, NE, ...), IS [NOT] INITIAL, AND/OR. Watchpoint conditions are evaluated by the ABAP debugger"),
+			),
+			mcp.WithNumber("hit_count",
+				mcp.Description("Line breakpoints: stop only from the Nth qualifying hit on (e.g., 100 = break on 100th hit)"),
+			),
+			mcp.WithArray("log_expressions",
+				mcp.Description("Line breakpoints: turns the breakpoint into a log point: these expressions are evaluated on each hit and execution continues (e.g., ['LV_COUNT', 'LS_DATA-STATUS']). Collect values with DebuggerCollectLogPoints"),
+			),
+			mcp.WithString("session",
+				mcp.Description("Debug session ID (e.g., 'S1' from DebuggerAttach). Each session has its own ZADT_VSP connection, breakpoints, hit counters and log points; omit for the shared session"),
+			),
 		), s.handleSetBreakpoint)
 	}
 
+	// DebuggerCollectLogPoints - WebSocket-based log point runner
+	if shouldRegister("DebuggerCollectLogPoints") {
+		s.mcpServer.AddTool(mcp.NewTool("DebuggerCollectLogPoints",
+			mcp.WithDescription("Wait for a debuggee, then run it past log points and unmet conditional/hit-count breakpoints, collecting log point values. Returns the collected values and where execution stopped (a regular breakpoint or program end). Uses WebSocket connection to ZADT_VSP."),
+			mcp.WithNumber("timeout",
+				mcp.Description("Listen timeout in seconds (default: 60, max: 240)"),
+			),
+			mcp.WithString("session",
+				mcp.Description("Debug session ID (e.g., 'S1' from DebuggerAttach). Each session has its own ZADT_VSP connection, breakpoints, hit counters and log points; omit for the shared session"),
+			),
+		), s.handleDebuggerCollectLogPoints)
+	}
+
 	// GetBreakpoints - WebSocket-based
 	if shouldRegister("GetBreakpoints") {
 		s.mcpServer.AddTool(mcp.NewTool("GetBreakpoints",
 			mcp.WithDescription("Get all breakpoints registered in the current debug session. Uses WebSocket connection to ZADT_VSP."),
+			mcp.WithString("session",
+				mcp.Description("Debug session ID (e.g., 'S1' from DebuggerAttach). Each session has its own ZADT_VSP connection, breakpoints, hit counters and log points; omit for the shared session"),
+			),
 		), s.handleGetBreakpoints)
 	}
 
@@ -942,6 +1213,9 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 				mcp.Required(),
 				mcp.Description("ID of the breakpoint to delete"),
 			),
+			mcp.WithString("session",
+				mcp.Description("Debug session ID (e.g., 'S1' from DebuggerAttach). Each session has its own ZADT_VSP connection, breakpoints, hit counters and log points; omit for the shared session"),
+			),
 		), s.handleDeleteBreakpoint)
 	}
 
@@ -981,7 +1255,7 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 	// DebuggerListen
 	if shouldRegister("DebuggerListen") {
 		s.mcpServer.AddTool(mcp.NewTool("DebuggerListen",
-			mcp.WithDescription("Start a debug listener that waits for a debuggee to hit a breakpoint. This is a BLOCKING call that uses long-polling. Returns when a debuggee is caught, timeout occurs, or a conflict is detected."),
+			mcp.WithDescription("Start a debug listener that waits for a debuggee to hit a breakpoint. This is a BLOCKING call that uses long-polling. Returns all waiting debuggees once one is caught, or when timeout occurs or a conflict is detected."),
 			mcp.WithString("user",
 				mcp.Description("User to listen for (defaults to current user)"),
 			),
@@ -994,7 +1268,7 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 	// DebuggerAttach
 	if shouldRegister("DebuggerAttach") {
 		s.mcpServer.AddTool(mcp.NewTool("DebuggerAttach",
-			mcp.WithDescription("Attach to a debuggee that has hit a breakpoint. Use the debuggee_id from DebuggerListen result."),
+			mcp.WithDescription("Attach to a debuggee that has hit a breakpoint. Use the debuggee_id from DebuggerListen result. Each attached debuggee gets its own session ID (S1, S2, ...) so several debuggees can be debugged concurrently. Breakpoint conditions, hit counts and log points are applied here (and on DebuggerStep stepContinue): hits that do not count are continued past."),
 			mcp.WithString("debuggee_id",
 				mcp.Required(),
 				mcp.Description("ID of the debuggee (from DebuggerListen result)"),
@@ -1005,10 +1279,20 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		), s.handleDebuggerAttach)
 	}
 
+	// DebuggerSessions
+	if shouldRegister("DebuggerSessions") {
+		s.mcpServer.AddTool(mcp.NewTool("DebuggerSessions",
+			mcp.WithDescription("List attached debug sessions and debuggees that are waiting to be attached."),
+		), s.handleDebuggerSessions)
+	}
+
 	// DebuggerDetach
 	if shouldRegister("DebuggerDetach") {
 		s.mcpServer.AddTool(mcp.NewTool("DebuggerDetach",
-			mcp.WithDescription("Detach from the current debug session and release the debuggee."),
+			mcp.WithDescription("Detach from a debug session and release the debuggee."),
+			mcp.WithString("session",
+				mcp.Description("Debug session ID from DebuggerAttach (e.g., 'S1'); optional when only one session is attached"),
+			),
 		), s.handleDebuggerDetach)
 	}
 
@@ -1023,6 +1307,9 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 			mcp.WithString("uri",
 				mcp.Description("Target URI for stepRunToLine/stepJumpToLine (e.g., '/sap/bc/adt/programs/programs/ZTEST/source/main#start=42')"),
 			),
+			mcp.WithString("session",
+				mcp.Description("Debug session ID from DebuggerAttach (e.g., 'S1'); optional when only one session is attached"),
+			),
 		), s.handleDebuggerStep)
 	}
 
@@ -1030,6 +1317,9 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 	if shouldRegister("DebuggerGetStack") {
 		s.mcpServer.AddTool(mcp.NewTool("DebuggerGetStack",
 			mcp.WithDescription("Get the current call stack during a debug session."),
+			mcp.WithString("session",
+				mcp.Description("Debug session ID from DebuggerAttach (e.g., 'S1'); optional when only one session is attached"),
+			),
 		), s.handleDebuggerGetStack)
 	}
 
@@ -1040,9 +1330,55 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 			mcp.WithArray("variable_ids",
 				mcp.Description("Variable IDs to retrieve (e.g., ['@ROOT'] for top-level, or specific IDs like ['LV_COUNT', 'LS_DATA'])"),
 			),
+			mcp.WithString("session",
+				mcp.Description("Debug session ID from DebuggerAttach (e.g., 'S1'); optional when only one session is attached"),
+			),
 		), s.handleDebuggerGetVariables)
 	}
 
+	// DebuggerEvaluate
+	if shouldRegister("DebuggerEvaluate") {
+		s.mcpServer.AddTool(mcp.NewTool("DebuggerEvaluate",
+			mcp.WithDescription("Evaluate a variable expression in the attached debug session: field symbols (<FS>), structure components (LS_DATA-FIELD), object attributes (LO_OBJ->ATTR) and table rows (LT_TAB[ 5 ]-FIELD). Structures are returned with their components; for tables use DebuggerReadTable."),
+			mcp.WithString("expression",
+				mcp.Required(),
+				mcp.Description("Expression to evaluate (e.g., 'ls_order-status', '<fs_line>', 'lt_items[ 3 ]-matnr')"),
+			),
+			mcp.WithString("session",
+				mcp.Description("Debug session ID from DebuggerAttach (e.g., 'S1'); optional when only one session is attached"),
+			),
+		), s.handleDebuggerEvaluate)
+	}
+
+	// DebuggerReadTable
+	if shouldRegister("DebuggerReadTable") {
+		s.mcpServer.AddTool(mcp.NewTool("DebuggerReadTable",
+			mcp.WithDescription("Read a page of rows from an internal table in the attached debug session. Supports row offset/limit, column selection and a row filter, so large tables can be inspected without dumping them. Continue with next_offset while more rows are available. For a debuggee attached through ZADT_VSP the filter is applied in the debugger session, so only matching rows are transferred; for REST debug sessions it is applied by vsp to rows fetched in chunks of 100. One call scans at most max_scan rows, so a page can hold fewer matches than limit while more rows remain."),
+			mcp.WithString("table",
+				mcp.Required(),
+				mcp.Description("Internal table expression (e.g., 'LT_ITEMS', 'LS_ORDER-ITEMS', 'LO_OBJ->MT_DATA')"),
+			),
+			mcp.WithNumber("offset",
+				mcp.Description("0-based row to start from (default 0)"),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description("Maximum rows to return (default 50, max 1000)"),
+			),
+			mcp.WithArray("columns",
+				mcp.Description("Columns to return (default: all; ZADT_VSP returns the whole row as TABLE_LINE), e.g. ['MATNR', 'MENGE']"),
+			),
+			mcp.WithString("filter",
+				mcp.Description("Row condition on column names, e.g. \"STATUS = 'E' AND MENGE > 100\". Supports =, <>, <, >, <=, >=, IS [NOT] INITIAL, AND, OR. Elementary tables use TABLE_LINE"),
+			),
+			mcp.WithNumber("max_scan",
+				mcp.Description("Maximum rows fetched and scanned per call when filtering (default 5000); continue with next_offset to scan further"),
+			),
+			mcp.WithString("session",
+				mcp.Description("Debug session ID from DebuggerAttach (e.g., 'S1'); optional when only one session is attached"),
+			),
+		), s.handleDebuggerReadTable)
+	}
+
 	// SearchObject
 	if shouldRegister("SearchObject") {
 		s.mcpServer.AddTool(mcp.NewTool("SearchObject",
@@ -1094,30 +1430,48 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 	// ActivatePackage - Batch activation of inactive objects
 	if shouldRegister("ActivatePackage") {
 		s.mcpServer.AddTool(mcp.NewTool("ActivatePackage",
-			mcp.WithDescription("Activate all inactive objects. Objects are sorted by dependency order (interfaces before classes). If no package specified, activates ALL inactive objects for current user."),
+			mcp.WithDescription("Activate all inactive objects. Objects are sorted by dependency order (interfaces before classes). If no package specified, activates ALL inactive objects for current user. With planned=true, activates in dependency batches learned from CDS dependencies and activation errors, activates cycles together, retries, and groups unresolved errors by root-cause object."),
 			mcp.WithString("package",
 				mcp.Description("Package name to filter (optional, empty = all packages)"),
 			),
 			mcp.WithNumber("max_objects",
 				mcp.Description("Maximum number of objects to activate (default: 100)"),
 			),
+			mcp.WithBoolean("planned",
+				mcp.Description("Dependency-ordered activation with retry and root-cause report (default: false)"),
+			),
+			mcp.WithNumber("max_rounds",
+				mcp.Description("Planned activation: rounds of activate and retry (default: 5)"),
+			),
 		), s.handleActivatePackage)
 	}
 
 	// RunUnitTests
 	if shouldRegister("RunUnitTests") {
 		s.mcpServer.AddTool(mcp.NewTool("RunUnitTests",
-		mcp.WithDescription("Run ABAP Unit tests for an object"),
+		mcp.WithDescription("Run ABAP Unit tests for an object, or only selected test classes/methods. With history (or rerun_failed), the last result per object is recorded in .vsp-unittests; later runs with history include a diff (newly failing, fixed, slower) and can rerun only the failed tests."),
 		mcp.WithString("object_url",
 			mcp.Required(),
 			mcp.Description("ADT URL of the object (e.g., /sap/bc/adt/oo/classes/ZCL_TEST)"),
 		),
+		mcp.WithArray("tests",
+			mcp.Description("Run only these test classes/methods (uri or navigationUri from an earlier result)"),
+		),
+		mcp.WithBoolean("rerun_failed",
+			mcp.Description("Run only the tests that failed in the last recorded run of the object (default: false)"),
+		),
+		mcp.WithBoolean("history",
+			mcp.Description("Record the result and diff it against the last recorded run of the object (default: false)"),
+		),
 		mcp.WithBoolean("include_dangerous",
 			mcp.Description("Include dangerous risk level tests (default: false)"),
 		),
 		mcp.WithBoolean("include_long",
 			mcp.Description("Include long duration tests (default: false)"),
 		),
+		mcp.WithBoolean("with_coverage",
+			mcp.Description("Collect statement/branch/procedure coverage with per-line hits (default: false)"),
+		),
 	), s.handleRunUnitTests)
 	}
 
@@ -1126,16 +1480,30 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 	// RunATCCheck - Convenience tool (combines variant + run + worklist)
 	if shouldRegister("RunATCCheck") {
 		s.mcpServer.AddTool(mcp.NewTool("RunATCCheck",
-			mcp.WithDescription("Run ATC (ABAP Test Cockpit) code quality check on an object. Returns findings with priority, check title, message, and location. Priority: 1=Error, 2=Warning, 3=Info."),
+			mcp.WithDescription("Run ATC (ABAP Test Cockpit) code quality check on an object, a list of objects, a package (with subpackages) or a transport request. Returns findings grouped by object with priority counts, check title, message, and location. Priority: 1=Error, 2=Warning, 3=Info."),
 			mcp.WithString("object_url",
-				mcp.Required(),
 				mcp.Description("ADT URL of the object (e.g., /sap/bc/adt/oo/classes/ZCL_TEST)"),
 			),
+			mcp.WithArray("object_urls",
+				mcp.Description("ADT URLs of several objects, e.g. from a search result"),
+			),
+			mcp.WithString("package",
+				mcp.Description("Package to check, including all subpackages"),
+			),
+			mcp.WithString("transport",
+				mcp.Description("Transport request to check (all objects of the request and its tasks)"),
+			),
 			mcp.WithString("variant",
 				mcp.Description("Check variant name (empty = use system default)"),
 			),
 			mcp.WithNumber("max_results",
-				mcp.Description("Maximum number of findings to return (default: 100)"),
+				mcp.Description("Maximum number of findings to return (default: 100, with a baseline: 10000)"),
+			),
+			mcp.WithString("baseline",
+				mcp.Description("Path to an ATC baseline file. Only findings not in the baseline are returned, plus the fixed ones. Created from this run if the file does not exist. Refused if the run reaches max_results, since findings may be missing."),
+			),
+			mcp.WithBoolean("update_baseline",
+				mcp.Description("Overwrite the baseline with the findings of this run (default: false)"),
 			),
 		), s.handleRunATCCheck)
 	}
@@ -1147,6 +1515,50 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		), s.handleGetATCCustomizing)
 	}
 
+	// ATCQuickFix - List, preview and apply ADT quick fixes for an ATC finding
+	if shouldRegister("ATCQuickFix") {
+		s.mcpServer.AddTool(mcp.NewTool("ATCQuickFix",
+			mcp.WithDescription("List the quick fixes ADT offers for an ATC finding, preview one as unified diff, or apply it (lock, update, activate). Without 'fix' the available fixes are listed; with 'fix' the fix is previewed unless apply=true."),
+			mcp.WithString("location",
+				mcp.Required(),
+				mcp.Description("Finding location from RunATCCheck, including the position (e.g., /sap/bc/adt/oo/classes/zcl_foo/source/main#start=12,4)"),
+			),
+			mcp.WithNumber("fix",
+				mcp.Description("Number of the fix to preview or apply (1-based, from the listing)"),
+			),
+			mcp.WithBoolean("apply",
+				mcp.Description("Apply the fix and activate the object (default: false = preview only)"),
+			),
+			mcp.WithBoolean("syntax_check",
+				mcp.Description("Check the fixed source before saving (default: true)"),
+			),
+			mcp.WithString("transport",
+				mcp.Description("Transport request number (required for non-local packages)"),
+			),
+		), s.handleATCQuickFix)
+	}
+
+	// ATCRequestExemption - Request an exemption for an ATC finding
+	if shouldRegister("ATCRequestExemption") {
+		s.mcpServer.AddTool(mcp.NewTool("ATCRequestExemption",
+			mcp.WithDescription("Request an ATC exemption for a single finding. Reason IDs are listed by GetATCCustomizing."),
+			mcp.WithString("marker_id",
+				mcp.Required(),
+				mcp.Description("Marker ID of the finding (quickfixInfo from RunATCCheck)"),
+			),
+			mcp.WithString("reason",
+				mcp.Required(),
+				mcp.Description("Exemption reason ID (e.g., FPOS for false positive)"),
+			),
+			mcp.WithString("justification",
+				mcp.Description("Justification text (mandatory for some reasons)"),
+			),
+			mcp.WithString("approver",
+				mcp.Description("Approver user (default: approver proposed by the system)"),
+			),
+		), s.handleATCRequestExemption)
+	}
+
 
 	// --- CRUD Operations ---
 
@@ -1477,6 +1889,45 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 	}
 
 
+	// GenerateRAPStack
+	if shouldRegister("GenerateRAPStack") {
+		s.mcpServer.AddTool(mcp.NewTool("GenerateRAPStack",
+		mcp.WithDescription("Generate a managed RAP business object from a DDIC table: root and projection views, metadata extension, behavior definitions, behavior pool, draft table, service definition and OData UI binding. Deploys via the file import in RAP order and activates everything together; dry_run only writes the abapGit files"),
+		mcp.WithString("table",
+			mcp.Required(),
+			mcp.Description("Database table (includes are resolved), e.g. ZTRAVEL"),
+		),
+		mcp.WithString("entity",
+			mcp.Description("Entity alias, e.g. Travel (default: derived from the table name). Objects are named <prefix>R_/C_/UI_<ENTITY>"),
+		),
+		mcp.WithString("prefix",
+			mcp.Description("Object name prefix: Z, Y or a namespace (default: namespace of the table, else Z)"),
+		),
+		mcp.WithString("description",
+			mcp.Description("Label of the views (default: table description)"),
+		),
+		mcp.WithBoolean("draft",
+			mcp.Description("Generate with draft (default: true). Draft needs a last-changed timestamp field (ABP_LASTCHANGE_TSTMPL)"),
+		),
+		mcp.WithString("binding_version",
+			mcp.Description("OData version of the service binding: V4 (default) or V2"),
+		),
+		mcp.WithString("package",
+			mcp.Description("Target package (required unless dry_run)"),
+		),
+		mcp.WithString("transport",
+			mcp.Description("Transport request (optional for local packages)"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Only write the abapGit files and rap-stack.json, deploy nothing"),
+		),
+		mcp.WithString("output_dir",
+			mcp.Description("Directory for the generated files (default in dry run: root view name; otherwise not kept)"),
+		),
+	), s.handleGenerateRAPStack)
+	}
+
+
 	// --- Workflow Tools ---
 
 	// WriteProgram
@@ -1899,6 +2350,93 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		), s.handleGetClassComponents)
 	}
 
+	// RenameSymbol - rename with preview across all using objects
+	if shouldRegister("RenameSymbol") {
+		s.mcpServer.AddTool(mcp.NewTool("RenameSymbol",
+			mcp.WithDescription("Rename a method, attribute, local variable, class or other identifier everywhere it is used. Returns a unified diff per affected object; with execute=true the changes are saved and activated, and rolled back if that fails."),
+			mcp.WithString("source_url",
+				mcp.Required(),
+				mcp.Description("ADT URL of the object or include containing the identifier (e.g., /sap/bc/adt/oo/classes/ZCL_TEST)"),
+			),
+			mcp.WithNumber("line",
+				mcp.Required(),
+				mcp.Description("Line of the identifier (1-based)"),
+			),
+			mcp.WithNumber("column",
+				mcp.Required(),
+				mcp.Description("Column within the identifier (1-based)"),
+			),
+			mcp.WithString("new_name",
+				mcp.Required(),
+				mcp.Description("New name"),
+			),
+			mcp.WithBoolean("execute",
+				mcp.Description("Save and activate the changes (default: false = preview only)"),
+			),
+			mcp.WithString("transport",
+				mcp.Description("Transport request number (required for non-local packages)"),
+			),
+		), s.handleRenameSymbol)
+	}
+
+	// ExtractMethod - move a line range into a new method
+	if shouldRegister("ExtractMethod") {
+		s.mcpServer.AddTool(mcp.NewTool("ExtractMethod",
+			mcp.WithDescription("Extract the statements of a line range into a new method of the same class; ADT derives the parameters. Returns a unified diff; with execute=true the change is saved and activated, and rolled back if that fails."),
+			mcp.WithString("source_url",
+				mcp.Required(),
+				mcp.Description("ADT URL of the class or include (e.g., /sap/bc/adt/oo/classes/ZCL_TEST/source/main)"),
+			),
+			mcp.WithNumber("start_line",
+				mcp.Required(),
+				mcp.Description("First line to extract (1-based)"),
+			),
+			mcp.WithNumber("end_line",
+				mcp.Description("Last line to extract (default: start_line)"),
+			),
+			mcp.WithString("method_name",
+				mcp.Required(),
+				mcp.Description("Name of the new method"),
+			),
+			mcp.WithBoolean("execute",
+				mcp.Description("Save and activate the change (default: false = preview only)"),
+			),
+			mcp.WithString("transport",
+				mcp.Description("Transport request number (required for non-local packages)"),
+			),
+		), s.handleExtractMethod)
+	}
+
+	// ChangeSignature - add, remove and rename method parameters and update callers
+	if shouldRegister("ChangeSignature") {
+		s.mcpServer.AddTool(mcp.NewTool("ChangeSignature",
+			mcp.WithDescription("Change the signature of a global class method: add, remove or rename parameters. Updates the METHODS definition, the parameter names in the implementation and all functional calls found by where-used. Returns a unified diff per object plus warnings for calls that could not be updated; with execute=true the changes are saved and activated together, and rolled back if that fails."),
+			mcp.WithString("class_url",
+				mcp.Required(),
+				mcp.Description("ADT URL of the class (e.g., /sap/bc/adt/oo/classes/ZCL_TEST)"),
+			),
+			mcp.WithString("method",
+				mcp.Required(),
+				mcp.Description("Method name"),
+			),
+			mcp.WithArray("add",
+				mcp.Description(`Parameters to add: [{"name":"iv_mode","type":"string","kind":"importing|exporting|changing|returning","optional":true,"default":"'A'","callerValue":"'A'"}]. Required importing/changing parameters need callerValue, which existing calls pass.`),
+			),
+			mcp.WithArray("remove",
+				mcp.Description("Names of parameters to remove"),
+			),
+			mcp.WithObject("rename",
+				mcp.Description(`Parameters to rename: {"old_name":"new_name"}`),
+			),
+			mcp.WithBoolean("execute",
+				mcp.Description("Save and activate the changes (default: false = preview only)"),
+			),
+			mcp.WithString("transport",
+				mcp.Description("Transport request number (required for non-local packages)"),
+			),
+		), s.handleChangeSignature)
+	}
+
 	// GetInactiveObjects - list objects that need activation
 	if shouldRegister("GetInactiveObjects") {
 		s.mcpServer.AddTool(mcp.NewTool("GetInactiveObjects",
@@ -1933,10 +2471,21 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		), s.handleGetTransportInfo)
 	}
 
+	// RunClass - execute a class implementing IF_OO_ADT_CLASSRUN
+	if shouldRegister("RunClass") {
+		s.mcpServer.AddTool(mcp.NewTool("RunClass",
+			mcp.WithDescription("Run a class implementing IF_OO_ADT_CLASSRUN (like F9 in ADT) and return what its main method writes to the console. Works on on-premise and ABAP Cloud systems."),
+			mcp.WithString("class_name",
+				mcp.Required(),
+				mcp.Description("Name of the class, e.g. ZCL_MY_RUNNER"),
+			),
+		), s.handleRunClass)
+	}
+
 	// ExecuteABAP - execute arbitrary ABAP code via unit test wrapper (Expert mode only)
 	if shouldRegister("ExecuteABAP") {
 		s.mcpServer.AddTool(mcp.NewTool("ExecuteABAP",
-			mcp.WithDescription("Execute arbitrary ABAP code via unit test wrapper. Creates temp program, injects code into test method, runs via RunUnitTests, extracts results from assertion messages, cleans up. Use lv_result variable to return output. WARNING: Powerful tool - use responsibly."),
+			mcp.WithDescription("Execute arbitrary ABAP code via unit test wrapper. Creates temp program, injects code into test method, runs via RunUnitTests, extracts results from assertion messages, cleans up. On ABAP Cloud systems the code runs in a temporary IF_OO_ADT_CLASSRUN class instead and may use released APIs only. Use lv_result variable to return output. WARNING: Powerful tool - use responsibly."),
 			mcp.WithString("code",
 				mcp.Required(),
 				mcp.Description("ABAP code to execute. Set lv_result variable to return output via assertion message."),
@@ -1953,6 +2502,12 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 			mcp.WithString("program_prefix",
 				mcp.Description("Prefix for temp program name (default: ZTEMP_EXEC_)"),
 			),
+			mcp.WithString("package",
+				mcp.Description("Package of the temp program or class (default: $TMP)"),
+			),
+			mcp.WithString("runner",
+				mcp.Description("program (default): temp program with unit test wrapper; class: temp IF_OO_ADT_CLASSRUN class, lv_result is written to the console (always used on ABAP Cloud)"),
+			),
 		), s.handleExecuteABAP)
 	}
 
@@ -2069,6 +2624,59 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		), s.handleUI5DeleteApp)
 	}
 
+	// UI5Pull - download a whole app into a local folder
+	if shouldRegister("UI5Pull") {
+		s.mcpServer.AddTool(mcp.NewTool("UI5Pull",
+			mcp.WithDescription("Download all files of a UI5/Fiori BSP application into a local folder (recursive). Files with unchanged content are not rewritten."),
+			mcp.WithString("app_name",
+				mcp.Required(),
+				mcp.Description("Name of the UI5 application"),
+			),
+			mcp.WithString("dir",
+				mcp.Required(),
+				mcp.Description("Local target folder"),
+			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Only list what would change"),
+			),
+		), s.handleUI5Pull)
+	}
+
+	// UI5Push - upload a local folder, changed files only
+	if shouldRegister("UI5Push") {
+		s.mcpServer.AddTool(mcp.NewTool("UI5Push",
+			mcp.WithDescription("Upload a local folder to a UI5/Fiori BSP application in one call. By default the zipped folder is deployed through the UI5 ABAP repository service (/UI5/ABAP_REPOSITORY_SRV) like 'fiori deploy', replacing the app, running the repository's checks and creating the app if needed. App name, package and transport default to the deploy-to-abap task of ui5-deploy.yaml/ui5.yaml; built projects upload dist. Afterwards the app index is only checked for the app, not recalculated; if it is stale, run report /UI5/APP_INDEX_CALCULATE. backend=filestore writes file by file through the ADT filestore instead (uploads only changed files, deletes remote files missing locally); many releases reject filestore writes with HTTP 405."),
+			mcp.WithString("dir",
+				mcp.Required(),
+				mcp.Description("Local project or app folder"),
+			),
+			mcp.WithString("app_name",
+				mcp.Description("Name of the UI5 application (default from ui5-deploy.yaml)"),
+			),
+			mcp.WithString("package",
+				mcp.Description("Package for a new app (default from ui5-deploy.yaml)"),
+			),
+			mcp.WithString("transport",
+				mcp.Description("Transport request number (default from ui5-deploy.yaml)"),
+			),
+			mcp.WithString("source",
+				mcp.Description("Folder to upload, relative to dir (default: dist if built)"),
+			),
+			mcp.WithBoolean("keep_removed",
+				mcp.Description("filestore: do not delete remote files missing locally (default: false)"),
+			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Only list what would change (odata: deploy in test mode)"),
+			),
+			mcp.WithString("backend",
+				mcp.Description("Deployment backend: odata or filestore (default from system configuration, else odata)"),
+			),
+			mcp.WithBoolean("unsafe",
+				mcp.Description("odata: disable the repository's safe-mode check (default: false)"),
+			),
+		), s.handleUI5Push)
+	}
+
 	// --- AMDP (HANA) Debugger ---
 
 	// AMDPDebuggerStart
@@ -2158,6 +2766,38 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		), s.handleGetTransport)
 	}
 
+	// PreReleaseCheck
+	if shouldRegister("PreReleaseCheck") {
+		s.mcpServer.AddTool(mcp.NewTool("PreReleaseCheck",
+			mcp.WithDescription("Check all objects of a transport request and its tasks before release: inactive versions, syntax errors, ATC findings up to a priority, failing unit tests, locks held by other requests, and referenced objects that are neither on the transport nor released before (local objects, objects only on other open requests). Reports blocking issues and whether the release can go ahead. Requires --enable-transports OR --allow-transportable-edits flag."),
+			mcp.WithString("transport",
+				mcp.Required(),
+				mcp.Description("Transport request number (e.g., 'A4HK900094')"),
+			),
+			mcp.WithString("atc_variant",
+				mcp.Description("ATC check variant (default: system default)"),
+			),
+			mcp.WithNumber("atc_max_priority",
+				mcp.Description("ATC findings up to this priority block the release: 1=errors (default), 2=+warnings, 3=all"),
+			),
+			mcp.WithBoolean("skip_syntax",
+				mcp.Description("Skip the syntax check (default: false)"),
+			),
+			mcp.WithBoolean("skip_atc",
+				mcp.Description("Skip ATC (default: false)"),
+			),
+			mcp.WithBoolean("skip_unit_tests",
+				mcp.Description("Skip unit tests (default: false)"),
+			),
+			mcp.WithBoolean("skip_locks",
+				mcp.Description("Skip the lock check (default: false)"),
+			),
+			mcp.WithBoolean("skip_references",
+				mcp.Description("Skip the referenced-object check, which needs freestyle SQL (default: false)"),
+			),
+		), s.handlePreReleaseCheck)
+	}
+
 	// CreateTransport (expert mode only)
 	if shouldRegister("CreateTransport") {
 		s.mcpServer.AddTool(mcp.NewTool("CreateTransport",
@@ -2193,6 +2833,9 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 			mcp.WithBoolean("skip_atc",
 				mcp.Description("Skip ATC quality checks (default: false)"),
 			),
+			mcp.WithBoolean("check",
+				mcp.Description("Run PreReleaseCheck first and refuse to release on blocking issues or checks that could not run (default: false)"),
+			),
 		), s.handleReleaseTransport)
 	}
 
@@ -2207,6 +2850,71 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 		), s.handleDeleteTransport)
 	}
 
+	// AddTransportObjects (expert mode only)
+	if shouldRegister("AddTransportObjects") {
+		s.mcpServer.AddTool(mcp.NewTool("AddTransportObjects",
+			mcp.WithDescription("Add objects to the object list of a modifiable transport request or task, like SE09. Runs a temporary ABAP program (ExecuteABAP). Requires --enable-transports flag and not --transport-read-only."),
+			mcp.WithString("transport",
+				mcp.Required(),
+				mcp.Description("Transport request or task number"),
+			),
+			mcp.WithArray("objects",
+				mcp.Required(),
+				mcp.Description("Objects as ADT URIs ('/sap/bc/adt/oo/classes/zcl_order'), TADIR keys ('CLAS ZCL_ORDER') or E071 keys ('R3TR TABU ZCONFIG', 'LIMU METH ZCL_ORDER CALC')"),
+			),
+		), s.handleAddTransportObjects)
+	}
+
+	// RemoveTransportObjects (expert mode only)
+	if shouldRegister("RemoveTransportObjects") {
+		s.mcpServer.AddTool(mcp.NewTool("RemoveTransportObjects",
+			mcp.WithDescription("Remove entries (and their table keys) from the object list of a modifiable transport request or task. Object locks stay with the request. Runs a temporary ABAP program (ExecuteABAP). Requires --enable-transports flag and not --transport-read-only."),
+			mcp.WithString("transport",
+				mcp.Required(),
+				mcp.Description("Transport request or task number"),
+			),
+			mcp.WithArray("objects",
+				mcp.Required(),
+				mcp.Description("Objects as ADT URIs, TADIR keys ('CLAS ZCL_ORDER') or E071 keys ('R3TR TABU ZCONFIG')"),
+			),
+		), s.handleRemoveTransportObjects)
+	}
+
+	// MergeTransportTasks (expert mode only)
+	if shouldRegister("MergeTransportTasks") {
+		s.mcpServer.AddTool(mcp.NewTool("MergeTransportTasks",
+			mcp.WithDescription("Move the object list of a task into another task of the same request and delete the emptied task. Requires --enable-transports flag and not --transport-read-only."),
+			mcp.WithString("source",
+				mcp.Required(),
+				mcp.Description("Task whose objects are moved (deleted afterwards)"),
+			),
+			mcp.WithString("target",
+				mcp.Required(),
+				mcp.Description("Task that receives the objects"),
+			),
+		), s.handleMergeTransportTasks)
+	}
+
+	// CreateTransportOfCopies (expert mode only)
+	if shouldRegister("CreateTransportOfCopies") {
+		s.mcpServer.AddTool(mcp.NewTool("CreateTransportOfCopies",
+			mcp.WithDescription("Create a transport of copies (type T) with the object list of a request and all its tasks, optionally releasing it. The source request stays open. Requires --enable-transports flag and not --transport-read-only."),
+			mcp.WithString("transport",
+				mcp.Required(),
+				mcp.Description("Source transport request number"),
+			),
+			mcp.WithString("target",
+				mcp.Description("Target system (default: target of the source request)"),
+			),
+			mcp.WithString("description",
+				mcp.Description("Description (default: 'ToC of <source>: <description>')"),
+			),
+			mcp.WithBoolean("release",
+				mcp.Description("Release the transport of copies right away (default: false)"),
+			),
+		), s.handleCreateTransportOfCopies)
+	}
+
 	// --- Git/abapGit Integration (via ZADT_VSP WebSocket) ---
 
 	// GitTypes
@@ -2219,13 +2927,16 @@ func (s *Server) registerTools(mode string, disabledGroups string, toolsConfig m
 	// GitExport
 	if shouldRegister("GitExport") {
 		s.mcpServer.AddTool(mcp.NewTool("GitExport",
-			mcp.WithDescription("Export ABAP objects as abapGit-compatible ZIP. Supports 158 object types. Saves ZIP file to output_dir (default: current directory). Use packages OR objects parameter."),
+			mcp.WithDescription("Export ABAP objects as abapGit-compatible ZIP. Supports 158 object types. Saves ZIP file to output_dir (default: current directory). Use packages, objects OR transport parameter. A transport export also writes a manifest listing entries that could not be serialized."),
 			mcp.WithString("packages",
 				mcp.Description("Comma-separated package names to export (e.g., '$ZRAY,$TMP'). Supports wildcards."),
 			),
 			mcp.WithString("objects",
 				mcp.Description("JSON array of objects: [{\"type\":\"CLAS\",\"name\":\"ZCL_TEST\"}]"),
 			),
+			mcp.WithString("transport",
+				mcp.Description("Transport request number: export all objects of the request and its tasks (e.g., 'A4HK900123')"),
+			),
 			mcp.WithBoolean("include_subpackages",
 				mcp.Description("Include subpackages when exporting by package (default: true)"),
 			),
@@ -1,9 +1,14 @@
 package mcp
 
 import (
+	"context"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/oisee/vibing-steampunk/pkg/adt"
 )
 
 func TestNewToolResultError(t *testing.T) {
@@ -68,6 +73,9 @@ func TestNewServer(t *testing.T) {
 		Password: "testpass",
 		Client:   "001",
 		Language: "EN",
+
+		CapabilityProfileDir: t.TempDir(),
+		CapabilityProfileTTL: -1,
 	}
 
 	server := NewServer(cfg)
@@ -81,4 +89,124 @@ func TestNewServer(t *testing.T) {
 	if server.adtClient == nil {
 		t.Error("ADT client should not be nil")
 	}
+	// Capability profiles must never end up in the source tree
+	if _, err := os.Stat(adt.DefaultCapabilityProfileDir); err == nil {
+		t.Errorf("%s written to the package directory", adt.DefaultCapabilityProfileDir)
+	}
+}
+
+func TestToolUnavailableReason(t *testing.T) {
+	features := map[adt.FeatureID]*adt.FeatureStatus{
+		adt.FeatureZADTVSP: {ID: adt.FeatureZADTVSP, Message: "ZADT_VSP not installed"},
+		adt.FeatureATC:     {ID: adt.FeatureATC, Failed: true, Message: "probe failed: timeout"},
+		adt.FeatureCloud:   {ID: adt.FeatureCloud, Available: true, Message: "ABAP Cloud system"},
+	}
+	tests := map[string]string{
+		"GitExport":   "requires zadt_vsp: ZADT_VSP not installed",
+		"RunReport":   "not supported on ABAP Cloud (ABAP Cloud system)",
+		"RunATCCheck": "", // Failed probes never hide a tool
+		"ExecuteABAP": "", // Runs through the class runner on ABAP Cloud
+		"GetSource":   "",
+	}
+	for tool, want := range tests {
+		if got := toolUnavailableReason(tool, features); got != want {
+			t.Errorf("%s: got %q, want %q", tool, got, want)
+		}
+	}
+}
+
+func TestNewServerHidesUnavailableTools(t *testing.T) {
+	dir := t.TempDir()
+	profile := &adt.CapabilityProfile{
+		System:   "https://sap.example.com:44300?sap-client=001",
+		ProbedAt: time.Now(),
+		Features: make(map[adt.FeatureID]*adt.FeatureStatus),
+	}
+	for _, id := range adt.AllFeatures {
+		profile.Features[id] = &adt.FeatureStatus{ID: id, Available: true, Mode: adt.FeatureModeAuto, ProbedAt: time.Now()}
+	}
+	profile.Features[adt.FeatureCloud].Available = false
+	profile.Features[adt.FeatureZADTVSP].Available = false
+	profile.Features[adt.FeatureZADTVSP].Message = "ZADT_VSP not installed"
+	if err := adt.NewCapabilityProfileStore(dir, 0).Save(profile); err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer(&Config{
+		BaseURL:              "https://sap.example.com:44300",
+		Username:             "testuser",
+		Password:             "testpass",
+		Client:               "001",
+		Mode:                 "expert",
+		FeatureATC:           "off",
+		CapabilityProfileDir: dir,
+		ToolsConfig:          map[string]bool{"GitTypes": true},
+	})
+
+	hidden := server.HiddenTools()
+	if hidden["RunReport"] != "requires zadt_vsp: ZADT_VSP not installed" || hidden["RunATCCheck"] != "requires atc: forced disabled" {
+		t.Errorf("hidden = %v", hidden)
+	}
+	if _, ok := hidden["GitTypes"]; ok || !server.registeredTools["GetSource"] || server.registeredTools["RunReport"] {
+		t.Errorf("explicitly enabled or unaffected tool hidden: %v", hidden)
+	}
+	if server.adtClient.CloudMode() {
+		t.Error("cloud mode enabled for an on-premise system")
+	}
+}
+
+func TestNewServerCloudMode(t *testing.T) {
+	server := NewServer(&Config{
+		BaseURL:              "https://sap.example.com:44300",
+		Username:             "testuser",
+		Password:             "testpass",
+		Client:               "001",
+		Mode:                 "expert",
+		FeatureCloud:         "on",
+		CapabilityProfileDir: t.TempDir(),
+		CapabilityProfileTTL: -1,
+	})
+
+	if !server.adtClient.CloudMode() {
+		t.Error("forced ABAP Cloud feature should enable cloud mode")
+	}
+	if !server.registeredTools["ExecuteABAP"] || !server.registeredTools["CheckReleasedAPIs"] || server.registeredTools["RunReport"] {
+		t.Errorf("unexpected tools for ABAP Cloud: hidden = %v", server.HiddenTools())
+	}
+}
+
+func TestSessionDebugWS(t *testing.T) {
+	server := NewServer(&Config{BaseURL: "https://sap.example.com:44300", Username: "testuser", Password: "testpass", CapabilityProfileDir: t.TempDir(), CapabilityProfileTTL: -1})
+	if ws := server.sessionDebugWS("S1"); ws != nil {
+		t.Errorf("expected no client without breakpoints, got %v", ws)
+	}
+
+	shared := adt.NewDebugWebSocketClient("https://sap.example.com:44300", "001", "testuser", "testpass", false)
+	session := adt.NewDebugWebSocketClient("https://sap.example.com:44300", "001", "testuser", "testpass", false)
+	server.debugWSClient = shared
+	server.debugWSSessions["S1"] = session
+
+	if ws := server.sessionDebugWS("s1"); ws != session {
+		t.Error("session S1 does not use its own client")
+	}
+	if ws := server.sessionDebugWS("S2"); ws != shared {
+		t.Error("session S2 does not fall back to the shared client")
+	}
+	server.closeDebugWS("S1")
+	if ws := server.sessionDebugWS("S1"); ws != shared {
+		t.Error("closed session client still used")
+	}
+}
+
+func TestDebugWSUnknownSession(t *testing.T) {
+	server := NewServer(&Config{BaseURL: "https://sap.example.com:44300", Username: "testuser", Password: "testpass", CapabilityProfileDir: t.TempDir(), CapabilityProfileTTL: -1})
+
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]interface{}{"session": "S9"}
+	if _, err := server.debugWS(context.Background(), request); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected unknown session to be rejected, got %v", err)
+	}
+	if len(server.debugWSSessions) != 0 {
+		t.Errorf("client cached for unknown session: %v", server.debugWSSessions)
+	}
 }
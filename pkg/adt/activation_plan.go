@@ -0,0 +1,625 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// --- Planned Activation ---
+//
+// Mass activation in dependency order. The planner starts from the known
+// dependencies (CDS views and the RAP type order), activates the objects in
+// topological batches and learns further dependencies from the activation
+// messages: an error of one object that names another inactive object means
+// the named object has to be activated first. Objects that depend on each
+// other (cycles) are activated together in one request.
+
+// ActivationPlanOptions configures a planned activation.
+type ActivationPlanOptions struct {
+	MaxRounds           int  // Rounds of plan, activate, learn (default 5)
+	MaxObjects          int  // Limit of objects to activate (0 = all)
+	SkipCDSDependencies bool // Do not read CDS dependencies up front
+}
+
+// ActivationBatch is one activation request of the plan.
+type ActivationBatch struct {
+	Round     int      `json:"round"`
+	Objects   []string `json:"objects"`
+	Cycle     bool     `json:"cycle,omitempty"` // Contains objects that depend on each other
+	Activated []string `json:"activated,omitempty"`
+	Failed    []string `json:"failed,omitempty"`
+}
+
+// ActivationRootCause groups unresolved errors by the object that causes
+// them: its own errors and the objects that could not be activated because
+// they depend on it.
+type ActivationRootCause struct {
+	Object  string                    `json:"object"`
+	Type    string                    `json:"type"`
+	Errors  []ActivationResultMessage `json:"errors"`
+	Blocked []string                  `json:"blocked,omitempty"`
+}
+
+// ActivationPlanResult is the result of a planned activation.
+type ActivationPlanResult struct {
+	Rounds       int                   `json:"rounds"`
+	Batches      []ActivationBatch     `json:"batches"`
+	Activated    []ActivatedObject     `json:"activated"`
+	Failed       []ActivationFailed    `json:"failed"`
+	RootCauses   []ActivationRootCause `json:"rootCauses,omitempty"`
+	Dependencies map[string][]string   `json:"dependencies,omitempty"` // Object -> objects it needs
+	Cycles       [][]string            `json:"cycles,omitempty"`
+	Added        []string              `json:"added,omitempty"` // Inactive objects outside the set that activation required
+	Summary      string                `json:"summary"`
+}
+
+// activationNode is an object of the plan.
+type activationNode struct {
+	obj       InactiveObject
+	key       string
+	deps      map[string]bool
+	errors    []ActivationResultMessage
+	activated bool
+}
+
+// activationPlan holds the dependency graph of the objects to activate.
+type activationPlan struct {
+	nodes  map[string]*activationNode
+	byName map[string][]string // Upper-case name -> keys
+}
+
+func newActivationPlan() *activationPlan {
+	return &activationPlan{nodes: make(map[string]*activationNode), byName: make(map[string][]string)}
+}
+
+func (p *activationPlan) add(obj InactiveObject) *activationNode {
+	key := strings.ToLower(obj.URI)
+	if n, ok := p.nodes[key]; ok {
+		return n
+	}
+	n := &activationNode{obj: obj, key: key, deps: make(map[string]bool)}
+	p.nodes[key] = n
+	name := strings.ToUpper(obj.Name)
+	p.byName[name] = append(p.byName[name], key)
+	return n
+}
+
+func (p *activationPlan) label(key string) string {
+	n := p.nodes[key]
+	return n.obj.Name + " (" + n.obj.Type + ")"
+}
+
+// addDependency records that from needs to. Returns true if the edge is new.
+func (p *activationPlan) addDependency(from, to string) bool {
+	if from == to || p.nodes[from] == nil || p.nodes[to] == nil || p.nodes[from].deps[to] {
+		return false
+	}
+	p.nodes[from].deps[to] = true
+	return true
+}
+
+// addTypeOrder adds the dependencies between objects of the same name with
+// a fixed order, such as a behavior definition on its CDS view.
+func (p *activationPlan) addTypeOrder() {
+	for _, keys := range p.byName {
+		for _, a := range keys {
+			for _, b := range keys {
+				pa, pb := objectTypePriority(p.nodes[a].obj.Type), objectTypePriority(p.nodes[b].obj.Type)
+				if pa > pb && pb < 50 {
+					p.addDependency(a, b)
+				}
+			}
+		}
+	}
+}
+
+// activationNameRegex matches the object names in activation messages.
+var activationNameRegex = regexp.MustCompile(`[A-Za-z0-9_/$]+`)
+
+// referencedObjects returns the keys of the objects named in a message,
+// except the object itself and objects of the same name.
+func (p *activationPlan) referencedObjects(self string, text string) []string {
+	selfName := strings.ToUpper(p.nodes[self].obj.Name)
+	var keys []string
+	for _, token := range activationNameRegex.FindAllString(text, -1) {
+		name := strings.ToUpper(token)
+		if name == selfName {
+			continue
+		}
+		keys = append(keys, p.byName[name]...)
+	}
+	return dedupeStrings(keys)
+}
+
+// components returns the strongly connected components of the pending
+// objects in dependency order: every component comes after the components
+// it depends on (Tarjan's algorithm).
+func (p *activationPlan) components(pending map[string]bool) [][]string {
+	keys := make([]string, 0, len(pending))
+	for k := range pending {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var result [][]string
+	next := 0
+
+	var visit func(k string)
+	visit = func(k string) {
+		index[k], low[k] = next, next
+		next++
+		stack = append(stack, k)
+		onStack[k] = true
+
+		deps := make([]string, 0, len(p.nodes[k].deps))
+		for d := range p.nodes[k].deps {
+			if pending[d] {
+				deps = append(deps, d)
+			}
+		}
+		sort.Strings(deps)
+		for _, d := range deps {
+			if _, seen := index[d]; !seen {
+				visit(d)
+				low[k] = min(low[k], low[d])
+			} else if onStack[d] {
+				low[k] = min(low[k], index[d])
+			}
+		}
+
+		if low[k] == index[k] {
+			var comp []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				comp = append(comp, top)
+				if top == k {
+					break
+				}
+			}
+			sort.Strings(comp)
+			result = append(result, comp)
+		}
+	}
+	for _, k := range keys {
+		if _, seen := index[k]; !seen {
+			visit(k)
+		}
+	}
+	return result
+}
+
+// levels groups components into batches: a component is in the first batch
+// after all components it depends on.
+func (p *activationPlan) levels(comps [][]string, pending map[string]bool) [][][]string {
+	compOf := make(map[string]int)
+	for i, comp := range comps {
+		for _, k := range comp {
+			compOf[k] = i
+		}
+	}
+	level := make([]int, len(comps))
+	var levels [][][]string
+	// Tarjan returns dependencies before dependents
+	for i, comp := range comps {
+		for _, k := range comp {
+			for d := range p.nodes[k].deps {
+				if j, ok := compOf[d]; ok && pending[d] && j != i && level[j]+1 > level[i] {
+					level[i] = level[j] + 1
+				}
+			}
+		}
+		for len(levels) <= level[i] {
+			levels = append(levels, nil)
+		}
+		levels[level[i]] = append(levels[level[i]], comp)
+	}
+	return levels
+}
+
+// ActivatePackagePlanned activates the inactive objects of a package (all
+// inactive objects of the user if packageName is empty) in dependency order.
+func (c *Client) ActivatePackagePlanned(ctx context.Context, packageName string, opts ActivationPlanOptions) (*ActivationPlanResult, error) {
+	if err := c.checkSafety(OpActivate, "ActivatePackage"); err != nil {
+		return nil, err
+	}
+
+	inactive, err := c.GetInactiveObjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting inactive objects: %w", err)
+	}
+
+	var objects []InactiveObject
+	packageName = strings.ToUpper(packageName)
+	for _, rec := range inactive {
+		if rec.Object == nil || rec.Object.Name == "" || rec.Object.URI == "" {
+			continue
+		}
+		if packageName == "" || objectBelongsToPackage(rec.Object, packageName) {
+			objects = append(objects, *rec.Object)
+		}
+	}
+	return c.ActivateObjectsPlanned(ctx, objects, opts)
+}
+
+// ActivateObjectsPlanned activates objects in dependency order, retrying
+// failed objects after the objects their errors name, and reports the
+// unresolved errors grouped by root cause.
+func (c *Client) ActivateObjectsPlanned(ctx context.Context, objects []InactiveObject, opts ActivationPlanOptions) (*ActivationPlanResult, error) {
+	if err := c.checkSafety(OpActivate, "ActivateObjectsPlanned"); err != nil {
+		return nil, err
+	}
+	if opts.MaxRounds <= 0 {
+		opts.MaxRounds = 5
+	}
+	if opts.MaxObjects > 0 && len(objects) > opts.MaxObjects {
+		objects = objects[:opts.MaxObjects]
+	}
+
+	plan := newActivationPlan()
+	for _, obj := range objects {
+		plan.add(obj)
+	}
+	plan.addTypeOrder()
+	if !opts.SkipCDSDependencies {
+		c.addCDSDependencies(ctx, plan)
+	}
+
+	result := &ActivationPlanResult{
+		Batches:   []ActivationBatch{},
+		Activated: []ActivatedObject{},
+		Failed:    []ActivationFailed{},
+	}
+	pending := make(map[string]bool)
+	for k := range plan.nodes {
+		pending[k] = true
+	}
+	cycles := make(map[string][]string)
+
+	for round := 1; round <= opts.MaxRounds && len(pending) > 0; round++ {
+		result.Rounds = round
+		progress := false
+		failed := make(map[string]bool)
+
+		comps := plan.components(pending)
+		for _, level := range plan.levels(comps, pending) {
+			batch := ActivationBatch{Round: round}
+			var keys []string
+			for _, comp := range level {
+				if len(comp) > 1 {
+					batch.Cycle = true
+					var names []string
+					for _, k := range comp {
+						names = append(names, plan.label(k))
+					}
+					cycles[strings.Join(comp, "|")] = names
+				}
+				// Skip components that need an object that failed in this round
+				blocked := false
+				for _, k := range comp {
+					for d := range plan.nodes[k].deps {
+						if failed[d] {
+							blocked = true
+						}
+					}
+				}
+				if blocked {
+					for _, k := range comp {
+						failed[k] = true
+					}
+					continue
+				}
+				keys = append(keys, comp...)
+			}
+			if len(keys) == 0 {
+				continue
+			}
+
+			for _, k := range keys {
+				batch.Objects = append(batch.Objects, plan.label(k))
+			}
+			activated, learned, err := c.activateBatch(ctx, plan, keys, result)
+			if err != nil {
+				return nil, err
+			}
+			if learned {
+				progress = true
+			}
+			for _, k := range keys {
+				if activated[k] {
+					delete(pending, k)
+					plan.nodes[k].activated = true
+					progress = true
+					batch.Activated = append(batch.Activated, plan.label(k))
+					obj := plan.nodes[k].obj
+					result.Activated = append(result.Activated, ActivatedObject{Name: obj.Name, Type: obj.Type, URI: obj.URI})
+				} else {
+					failed[k] = true
+					batch.Failed = append(batch.Failed, plan.label(k))
+				}
+			}
+			result.Batches = append(result.Batches, batch)
+		}
+
+		// Nothing activated and nothing learned: retrying will not help
+		if !progress {
+			break
+		}
+	}
+
+	for _, names := range cycles {
+		result.Cycles = append(result.Cycles, names)
+	}
+	sort.Slice(result.Cycles, func(i, j int) bool { return result.Cycles[i][0] < result.Cycles[j][0] })
+
+	result.Dependencies = make(map[string][]string)
+	for k, n := range plan.nodes {
+		for d := range n.deps {
+			result.Dependencies[plan.label(k)] = append(result.Dependencies[plan.label(k)], plan.label(d))
+		}
+		sort.Strings(result.Dependencies[plan.label(k)])
+	}
+
+	var remaining []string
+	for k := range pending {
+		remaining = append(remaining, k)
+	}
+	sort.Strings(remaining)
+	for _, k := range remaining {
+		n := plan.nodes[k]
+		reason := "not activated"
+		if len(n.errors) > 0 {
+			reason = n.errors[0].ShortText
+		}
+		result.Failed = append(result.Failed, ActivationFailed{Name: n.obj.Name, Type: n.obj.Type, Reason: reason})
+	}
+	result.RootCauses = plan.rootCauses(pending)
+
+	result.Summary = fmt.Sprintf("Activated %d objects in %d batches (%d rounds), %d failed", len(result.Activated), len(result.Batches), result.Rounds, len(result.Failed))
+	if len(result.RootCauses) > 0 {
+		result.Summary += fmt.Sprintf(", %d root causes", len(result.RootCauses))
+	}
+	return result, nil
+}
+
+// addCDSDependencies adds the dependencies of CDS views on other objects
+// of the plan.
+func (c *Client) addCDSDependencies(ctx context.Context, plan *activationPlan) {
+	for key, n := range plan.nodes {
+		if !strings.HasPrefix(strings.ToUpper(n.obj.Type), "DDLS") {
+			continue
+		}
+		deps, err := c.GetCDSDependencies(ctx, n.obj.Name, CDSDependencyOptions{})
+		if err != nil {
+			continue
+		}
+		for _, d := range deps.FlattenDependencies()[1:] {
+			for _, name := range []string{d.Name, d.DDLSName} {
+				for _, target := range plan.byName[strings.ToUpper(name)] {
+					plan.addDependency(key, target)
+				}
+			}
+		}
+	}
+}
+
+// activateBatch activates objects in one request. It returns the activated
+// objects and whether new dependencies were learned from the messages.
+func (c *Client) activateBatch(ctx context.Context, plan *activationPlan, keys []string, result *ActivationPlanResult) (map[string]bool, bool, error) {
+	var refs strings.Builder
+	for _, k := range keys {
+		obj := plan.nodes[k].obj
+		fmt.Fprintf(&refs, "\n  <adtcore:objectReference adtcore:uri=\"%s\" adtcore:name=\"%s\"/>", xmlEscape(obj.URI), xmlEscape(obj.Name))
+	}
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<adtcore:objectReferences xmlns:adtcore="http://www.sap.com/adt/core">%s
+</adtcore:objectReferences>`, refs.String())
+
+	resp, err := c.transport.Request(ctx, "/sap/bc/adt/activation?method=activate&preauditRequested=true", &RequestOptions{
+		Method:      http.MethodPost,
+		Body:        []byte(body),
+		ContentType: "application/xml",
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("activation failed: %w", err)
+	}
+	activation, err := parseActivationResult(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	inBatch := make(map[string]bool)
+	for _, k := range keys {
+		inBatch[k] = true
+		plan.nodes[k].errors = nil
+	}
+	failed := make(map[string]bool)
+	learned := false
+
+	// Errors of an object that name other objects are dependencies
+	var unassigned []ActivationResultMessage
+	for _, m := range activation.Messages {
+		if !strings.ContainsAny(m.Type, "EAX") {
+			continue
+		}
+		owner := activationMessageOwner(plan, keys, m)
+		if owner == "" {
+			unassigned = append(unassigned, m)
+			continue
+		}
+		failed[owner] = true
+		plan.nodes[owner].errors = append(plan.nodes[owner].errors, m)
+		for _, dep := range plan.referencedObjects(owner, m.ShortText) {
+			if !plan.nodes[dep].activated && plan.addDependency(owner, dep) {
+				learned = true
+			}
+		}
+	}
+
+	// Inactive objects returned by the pre-audit must be activated with the
+	// batch; nothing was activated.
+	for _, in := range activation.Inactive {
+		key := strings.ToLower(in.URI)
+		if inBatch[key] || in.URI == "" {
+			continue
+		}
+		if plan.nodes[key] == nil {
+			plan.add(in)
+			result.Added = append(result.Added, plan.label(key))
+		}
+		for _, k := range keys {
+			if plan.addDependency(k, key) {
+				learned = true
+			}
+		}
+	}
+
+	activated := make(map[string]bool)
+	if len(activation.Inactive) > 0 {
+		for _, k := range keys {
+			failed[k] = true
+		}
+	}
+	if !activation.Success && len(failed) == 0 {
+		for _, k := range keys {
+			failed[k] = true
+		}
+	}
+	for _, k := range keys {
+		if failed[k] {
+			if len(plan.nodes[k].errors) == 0 {
+				plan.nodes[k].errors = append(plan.nodes[k].errors, unassigned...)
+			}
+			continue
+		}
+		activated[k] = true
+	}
+	return activated, learned, nil
+}
+
+// activationMessageOwner returns the object of the batch a message belongs
+// to, by its link or description.
+func activationMessageOwner(plan *activationPlan, keys []string, m ActivationResultMessage) string {
+	if len(keys) == 1 {
+		return keys[0]
+	}
+	href := strings.ToLower(m.Href)
+	for _, k := range keys {
+		if href != "" && (href == k || strings.HasPrefix(href, k+"/") || strings.HasPrefix(href, k+"#")) {
+			return k
+		}
+	}
+	descr := strings.ToUpper(m.ObjDescr)
+	for _, k := range keys {
+		name := strings.ToUpper(plan.nodes[k].obj.Name)
+		for _, token := range activationNameRegex.FindAllString(descr, -1) {
+			if token == name {
+				return k
+			}
+		}
+	}
+	return ""
+}
+
+// rootCauses groups the objects that could not be activated by the failed
+// objects they depend on. A root cause is a failed object that does not
+// need another failed object (or a cycle of such objects).
+func (p *activationPlan) rootCauses(failed map[string]bool) []ActivationRootCause {
+	if len(failed) == 0 {
+		return nil
+	}
+	comps := p.components(failed)
+	compOf := make(map[string]int)
+	for i, comp := range comps {
+		for _, k := range comp {
+			compOf[k] = i
+		}
+	}
+
+	isRoot := make([]bool, len(comps))
+	for i, comp := range comps {
+		isRoot[i] = true
+		for _, k := range comp {
+			for d := range p.nodes[k].deps {
+				if failed[d] && compOf[d] != i {
+					isRoot[i] = false
+				}
+			}
+		}
+	}
+
+	// roots returns the root components a component depends on
+	memo := make(map[int][]int)
+	var roots func(i int) []int
+	roots = func(i int) []int {
+		if r, ok := memo[i]; ok {
+			return r
+		}
+		if isRoot[i] {
+			memo[i] = []int{i}
+			return memo[i]
+		}
+		memo[i] = nil
+		seen := make(map[int]bool)
+		var r []int
+		for _, k := range comps[i] {
+			for d := range p.nodes[k].deps {
+				if j, ok := compOf[d]; ok && failed[d] && j != i {
+					for _, root := range roots(j) {
+						if !seen[root] {
+							seen[root] = true
+							r = append(r, root)
+						}
+					}
+				}
+			}
+		}
+		memo[i] = r
+		return r
+	}
+
+	blocked := make(map[int][]string)
+	for i, comp := range comps {
+		if isRoot[i] {
+			continue
+		}
+		for _, root := range roots(i) {
+			for _, k := range comp {
+				blocked[root] = append(blocked[root], p.label(k))
+			}
+		}
+	}
+
+	var causes []ActivationRootCause
+	for i, comp := range comps {
+		if !isRoot[i] {
+			continue
+		}
+		for _, k := range comp {
+			n := p.nodes[k]
+			cause := ActivationRootCause{Object: n.obj.Name, Type: n.obj.Type, Errors: n.errors}
+			if cause.Errors == nil {
+				cause.Errors = []ActivationResultMessage{}
+			}
+			cause.Blocked = dedupeStrings(blocked[i])
+			sort.Strings(cause.Blocked)
+			causes = append(causes, cause)
+		}
+	}
+	sort.Slice(causes, func(i, j int) bool {
+		if len(causes[i].Blocked) != len(causes[j].Blocked) {
+			return len(causes[i].Blocked) > len(causes[j].Blocked)
+		}
+		return causes[i].Object < causes[j].Object
+	})
+	return causes
+}
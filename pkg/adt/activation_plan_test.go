@@ -0,0 +1,131 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestActivateObjectsPlanned(t *testing.T) {
+	objects := []InactiveObject{
+		{URI: "/sap/bc/adt/oo/classes/zbp_i_travel", Type: "CLAS/OC", Name: "ZBP_I_TRAVEL"},
+		{URI: "/sap/bc/adt/bo/behaviordefinitions/zi_travel", Type: "BDEF/BDO", Name: "ZI_TRAVEL"},
+		{URI: "/sap/bc/adt/ddic/ddl/sources/zi_travel", Type: "DDLS/DF", Name: "ZI_TRAVEL"},
+		{URI: "/sap/bc/adt/oo/classes/zcl_broken", Type: "CLAS/OC", Name: "ZCL_BROKEN"},
+		{URI: "/sap/bc/adt/oo/classes/zcl_user", Type: "CLAS/OC", Name: "ZCL_USER"},
+	}
+
+	uriRegex := regexp.MustCompile(`adtcore:uri="([^"]+)"`)
+	active := make(map[string]bool)
+	var batches [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+		case "/sap/bc/adt/activation":
+			var batch []string
+			for _, m := range uriRegex.FindAllStringSubmatch(string(body), -1) {
+				batch = append(batch, m[1])
+			}
+			batches = append(batches, batch)
+
+			var msgs strings.Builder
+			errorMsg := func(uri, text string) {
+				fmt.Fprintf(&msgs, `<msg type="E" href="%s/source/main#start=1,1"><shortText><txt>%s</txt></shortText></msg>`, uri, text)
+			}
+			for _, uri := range batch {
+				switch {
+				case strings.HasSuffix(uri, "zbp_i_travel") && !active["/sap/bc/adt/bo/behaviordefinitions/zi_travel"]:
+					errorMsg(uri, "Behavior definition ZI_TRAVEL is not active")
+				case strings.HasSuffix(uri, "behaviordefinitions/zi_travel") && !active["/sap/bc/adt/ddic/ddl/sources/zi_travel"]:
+					errorMsg(uri, "CDS entity ZI_TRAVEL is not active")
+				case strings.HasSuffix(uri, "zcl_broken"):
+					errorMsg(uri, "Statement is not accessible")
+				case strings.HasSuffix(uri, "zcl_user"):
+					errorMsg(uri, `The type "ZCL_BROKEN" is unknown`)
+				}
+			}
+			if msgs.Len() > 0 {
+				fmt.Fprintf(w, `<chkl:messages xmlns:chkl="http://www.sap.com/abapxml/checklist">%s</chkl:messages>`, msgs.String())
+			}
+			for _, uri := range batch {
+				if !strings.Contains(msgs.String(), uri) {
+					active[uri] = true
+				}
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"))
+	result, err := client.ActivateObjectsPlanned(context.Background(), objects, ActivationPlanOptions{SkipCDSDependencies: true})
+	if err != nil {
+		t.Fatalf("ActivateObjectsPlanned failed: %v", err)
+	}
+
+	// The behavior definition is planned after its CDS view
+	if len(batches) < 2 || !containsString(batches[0], "/sap/bc/adt/ddic/ddl/sources/zi_travel") || containsString(batches[0], "/sap/bc/adt/bo/behaviordefinitions/zi_travel") {
+		t.Errorf("unexpected first batches: %v", batches)
+	}
+	if len(result.Activated) != 3 {
+		t.Errorf("expected 3 activated objects, got %+v", result.Activated)
+	}
+	if len(result.Failed) != 2 {
+		t.Errorf("expected 2 failed objects, got %+v", result.Failed)
+	}
+	if len(result.RootCauses) != 1 {
+		t.Fatalf("expected 1 root cause, got %+v", result.RootCauses)
+	}
+	cause := result.RootCauses[0]
+	if cause.Object != "ZCL_BROKEN" || len(cause.Errors) != 1 || len(cause.Blocked) != 1 || cause.Blocked[0] != "ZCL_USER (CLAS/OC)" {
+		t.Errorf("unexpected root cause: %+v", cause)
+	}
+	if deps := result.Dependencies["ZBP_I_TRAVEL (CLAS/OC)"]; len(deps) != 2 {
+		t.Errorf("expected learned dependencies of the behavior pool, got %v", deps)
+	}
+}
+
+func TestActivationPlanCycles(t *testing.T) {
+	plan := newActivationPlan()
+	for _, name := range []string{"ZCL_A", "ZCL_B", "ZCL_C", "ZIF_D"} {
+		plan.add(InactiveObject{URI: "/sap/bc/adt/oo/classes/" + strings.ToLower(name), Type: "CLAS/OC", Name: name})
+	}
+	key := func(name string) string { return "/sap/bc/adt/oo/classes/" + strings.ToLower(name) }
+	plan.addDependency(key("ZCL_A"), key("ZCL_B"))
+	plan.addDependency(key("ZCL_B"), key("ZCL_A"))
+	plan.addDependency(key("ZCL_A"), key("ZIF_D"))
+	plan.addDependency(key("ZCL_C"), key("ZCL_A"))
+
+	pending := map[string]bool{key("ZCL_A"): true, key("ZCL_B"): true, key("ZCL_C"): true, key("ZIF_D"): true}
+	levels := plan.levels(plan.components(pending), pending)
+	if len(levels) != 3 {
+		t.Fatalf("expected 3 batches, got %v", levels)
+	}
+	if len(levels[0]) != 1 || levels[0][0][0] != key("ZIF_D") {
+		t.Errorf("expected ZIF_D first, got %v", levels[0])
+	}
+	if len(levels[1]) != 1 || len(levels[1][0]) != 2 {
+		t.Errorf("expected the ZCL_A/ZCL_B cycle in one component, got %v", levels[1])
+	}
+	if levels[2][0][0] != key("ZCL_C") {
+		t.Errorf("expected ZCL_C last, got %v", levels[2])
+	}
+}
+
+func containsString(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
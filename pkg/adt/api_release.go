@@ -0,0 +1,383 @@
+package adt
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// --- API Release State (ABAP Cloud) ---
+//
+// On ABAP Cloud systems code may only use SAP objects released for cloud
+// development (C1 contract, "use in Cloud Development"). ADT reports the
+// release state of an object under /sap/bc/adt/apireleases.
+
+// APIReleaseContract is the state of one release contract (C0 to C4).
+type APIReleaseContract struct {
+	Contract         string `json:"contract"`
+	State            string `json:"state"` // RELEASED, DEPRECATED, NOT_RELEASED
+	UseInCloud       bool   `json:"useInCloudDevelopment,omitempty"`
+	UseInKeyUserApps bool   `json:"useInKeyUserApps,omitempty"`
+}
+
+// APIReleaseState is the release state of an object.
+type APIReleaseState struct {
+	Name      string               `json:"name"`
+	Type      string               `json:"type"`
+	URI       string               `json:"uri"`
+	Contracts []APIReleaseContract `json:"contracts,omitempty"`
+	Successor string               `json:"successor,omitempty"` // Replacement of a deprecated object
+}
+
+// CloudState returns the state of the object for ABAP Cloud development:
+// RELEASED, DEPRECATED or NOT_RELEASED.
+func (s *APIReleaseState) CloudState() string {
+	for _, c := range s.Contracts {
+		if c.Contract == "C1" && c.UseInCloud && c.State != "" {
+			return c.State
+		}
+	}
+	return "NOT_RELEASED"
+}
+
+// GetAPIReleaseState returns the release state of the object at objectURI
+// (e.g. /sap/bc/adt/oo/classes/cl_abap_context_info).
+func (c *Client) GetAPIReleaseState(ctx context.Context, objectURI string) (*APIReleaseState, error) {
+	if err := c.checkSafety(OpRead, "GetAPIReleaseState"); err != nil {
+		return nil, err
+	}
+	if objectURI == "" {
+		return nil, fmt.Errorf("object URI is required")
+	}
+
+	resp, err := c.transport.Request(ctx, "/sap/bc/adt/apireleases/"+url.PathEscape(strings.ToLower(objectURI)), &RequestOptions{
+		Method: http.MethodGet,
+		Accept: "application/vnd.sap.adt.apirelease.v10+xml",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting API release state: %w", err)
+	}
+	return parseAPIReleaseState(resp.Body)
+}
+
+// GetObjectReleaseState returns the release state of an object given by name
+// (class, interface, CDS entity, table, ...).
+func (c *Client) GetObjectReleaseState(ctx context.Context, name string) (*APIReleaseState, error) {
+	uri, _, err := c.resolveAPIReference(ctx, APIReference{Name: strings.ToUpper(name)})
+	if err != nil {
+		return nil, err
+	}
+	if uri == "" {
+		return nil, fmt.Errorf("object %s not found", strings.ToUpper(name))
+	}
+	return c.GetAPIReleaseState(ctx, uri)
+}
+
+func parseAPIReleaseState(data []byte) (*APIReleaseState, error) {
+	type contractXML struct {
+		XMLName          xml.Name
+		Contract         string `xml:"contract,attr"`
+		UseInCloud       bool   `xml:"useInSAPCloudPlatform,attr"`
+		UseInKeyUserApps bool   `xml:"useInKeyUserApps,attr"`
+		Status           struct {
+			State string `xml:"state,attr"`
+		} `xml:"status"`
+	}
+	var resp struct {
+		Object struct {
+			URI  string `xml:"uri,attr"`
+			Type string `xml:"type,attr"`
+			Name string `xml:"name,attr"`
+		} `xml:"releasableObject"`
+		Contracts  []contractXML `xml:",any"`
+		Successors []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"behaviour>successors>successor"`
+	}
+	if err := xml.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("parsing API release state: %w", err)
+	}
+
+	state := &APIReleaseState{
+		Name: resp.Object.Name,
+		Type: resp.Object.Type,
+		URI:  resp.Object.URI,
+	}
+	for _, c := range resp.Contracts {
+		local := c.XMLName.Local
+		if !strings.HasSuffix(local, "Release") || len(local) != len("c1Release") {
+			continue
+		}
+		contract := c.Contract
+		if contract == "" {
+			contract = strings.ToUpper(local[:2])
+		}
+		state.Contracts = append(state.Contracts, APIReleaseContract{
+			Contract:         contract,
+			State:            c.Status.State,
+			UseInCloud:       c.UseInCloud,
+			UseInKeyUserApps: c.UseInKeyUserApps,
+		})
+	}
+	if len(resp.Successors) > 0 {
+		state.Successor = resp.Successors[0].Name
+	}
+	return state, nil
+}
+
+// APIReference is an SAP object used by a piece of source code.
+type APIReference struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"` // CLAS (class or interface), DDLS (CDS or table), FUNC
+}
+
+// ReleasedAPIViolation is a used object that is not released for ABAP Cloud.
+type ReleasedAPIViolation struct {
+	Name      string `json:"name"`
+	Type      string `json:"type,omitempty"`
+	State     string `json:"state"` // NOT_RELEASED, DEPRECATED or NOT_FOUND
+	Successor string `json:"successor,omitempty"`
+}
+
+// ReleasedAPICheckResult is the result of checking source code against the
+// released APIs.
+type ReleasedAPICheckResult struct {
+	Passed     bool                   `json:"passed"` // No object is unreleased or unknown
+	Checked    []string               `json:"checked"`
+	Violations []ReleasedAPIViolation `json:"violations,omitempty"`
+	Deprecated []ReleasedAPIViolation `json:"deprecated,omitempty"` // Still allowed, but should be replaced
+}
+
+// Summary returns a one-line description of the violations.
+func (r *ReleasedAPICheckResult) Summary() string {
+	if r.Passed {
+		return fmt.Sprintf("all %d used SAP objects are released for ABAP Cloud", len(r.Checked))
+	}
+	var parts []string
+	for _, v := range r.Violations {
+		part := fmt.Sprintf("%s (%s)", v.Name, strings.ToLower(strings.ReplaceAll(v.State, "_", " ")))
+		if v.Successor != "" {
+			part += " -> use " + v.Successor
+		}
+		parts = append(parts, part)
+	}
+	return "not released for ABAP Cloud: " + strings.Join(parts, ", ")
+}
+
+// CheckReleasedAPIs checks the SAP objects used by ABAP or CDS source code
+// against their release state for ABAP Cloud. Customer objects (Z*, Y*),
+// local classes and built-in types are not checked.
+func (c *Client) CheckReleasedAPIs(ctx context.Context, source string) (*ReleasedAPICheckResult, error) {
+	if err := c.checkSafety(OpRead, "CheckReleasedAPIs"); err != nil {
+		return nil, err
+	}
+
+	result := &ReleasedAPICheckResult{Checked: []string{}}
+	for _, ref := range FindAPIReferences(source) {
+		result.Checked = append(result.Checked, ref.Name)
+
+		v, err := c.apiReleaseState(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		switch v.State {
+		case "RELEASED":
+		case "DEPRECATED":
+			result.Deprecated = append(result.Deprecated, v)
+		default:
+			result.Violations = append(result.Violations, v)
+		}
+	}
+	result.Passed = len(result.Violations) == 0
+	return result, nil
+}
+
+// apiReleaseState returns the cloud release state of a referenced object.
+// Release states rarely change, so they are cached for the lifetime of the
+// client: each check would otherwise search and read every object again.
+func (c *Client) apiReleaseState(ctx context.Context, ref APIReference) (ReleasedAPIViolation, error) {
+	key := ref.Kind + " " + ref.Name
+	c.releaseMu.Lock()
+	v, ok := c.releaseCache[key]
+	c.releaseMu.Unlock()
+	if ok {
+		return v, nil
+	}
+
+	uri, objType, err := c.resolveAPIReference(ctx, ref)
+	if err != nil {
+		return ReleasedAPIViolation{}, err
+	}
+	if uri == "" {
+		v = ReleasedAPIViolation{Name: ref.Name, State: "NOT_FOUND"}
+	} else {
+		state, err := c.GetAPIReleaseState(ctx, uri)
+		if err != nil {
+			return ReleasedAPIViolation{}, fmt.Errorf("release state of %s: %w", ref.Name, err)
+		}
+		v = ReleasedAPIViolation{Name: ref.Name, Type: objType, State: state.CloudState(), Successor: state.Successor}
+	}
+
+	c.releaseMu.Lock()
+	if c.releaseCache == nil {
+		c.releaseCache = make(map[string]ReleasedAPIViolation)
+	}
+	c.releaseCache[key] = v
+	c.releaseMu.Unlock()
+	return v, nil
+}
+
+// checkCloudSource refuses source code that uses objects not released for
+// ABAP Cloud, if the client is in cloud mode.
+func (c *Client) checkCloudSource(ctx context.Context, source string) error {
+	if !c.CloudMode() {
+		return nil
+	}
+	check, err := c.CheckReleasedAPIs(ctx, source)
+	if err != nil {
+		return fmt.Errorf("checking released APIs: %w", err)
+	}
+	if !check.Passed {
+		return fmt.Errorf("ABAP Cloud: %s", check.Summary())
+	}
+	return nil
+}
+
+// resolveAPIReference finds the ADT URI of a referenced object; "" if the
+// system does not know it.
+func (c *Client) resolveAPIReference(ctx context.Context, ref APIReference) (string, string, error) {
+	results, err := c.SearchObject(ctx, ref.Name, 20)
+	if err != nil {
+		return "", "", fmt.Errorf("resolving %s: %w", ref.Name, err)
+	}
+	var fallback *SearchResult
+	for i, r := range results {
+		if !strings.EqualFold(r.Name, ref.Name) {
+			continue
+		}
+		objType := strings.SplitN(r.Type, "/", 2)[0]
+		switch {
+		case ref.Kind == "CLAS" && (objType == "CLAS" || objType == "INTF"),
+			ref.Kind == "DDLS" && (objType == "DDLS" || objType == "TABL" || objType == "VIEW"),
+			ref.Kind == "FUNC" && objType == "FUGR":
+			return r.URI, r.Type, nil
+		}
+		if fallback == nil {
+			fallback = &results[i]
+		}
+	}
+	if fallback != nil {
+		return fallback.URI, fallback.Type, nil
+	}
+	return "", "", nil
+}
+
+var (
+	apiClassRefPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\bTYPE\s+REF\s+TO\s+([\w/]+)`),
+		regexp.MustCompile(`(?i)\bNEW\s+([\w/]+)\s*\(`),
+		regexp.MustCompile(`(?i)\bCAST\s+([\w/]+)\s*\(`),
+		regexp.MustCompile(`(?i)([\w/]+)=>`),
+		regexp.MustCompile(`(?i)\bINTERFACES\s+([\w/]+)`),
+		regexp.MustCompile(`(?i)\bINHERITING\s+FROM\s+([\w/]+)`),
+		regexp.MustCompile(`(?i)\bCREATE\s+OBJECT\s+\S+\s+TYPE\s+([\w/]+)`),
+		regexp.MustCompile(`(?i)\bRAISE\s+EXCEPTION\s+TYPE\s+([\w/]+)`),
+		regexp.MustCompile(`(?i)\b(?:RAISING|CATCH)\s+([\w/\s]+?)(?:\.|,|\bINTO\b)`),
+	}
+	apiDataRefPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\bSELECT\b[^.]*?\bFROM\s+([@\w/]+)`),
+		regexp.MustCompile(`(?i)\bJOIN\s+([@\w/]+)`),
+		regexp.MustCompile(`(?i)\bASSOCIATION\s+(?:\[[^\]]*\]\s+)?TO\s+(?:PARENT\s+)?([\w/]+)`),
+		regexp.MustCompile(`(?i)\bCOMPOSITION\s+(?:\[[^\]]*\]\s+)?OF\s+([\w/]+)`),
+		regexp.MustCompile(`(?i)\bPROJECTION\s+ON\s+([\w/]+)`),
+	}
+	apiFunctionRefPattern = regexp.MustCompile(`(?i)\bCALL\s+FUNCTION\s+'([\w/]+)'`)
+	apiStringPattern      = regexp.MustCompile("'[^'\n]*'|`[^`\n]*`|\\|[^|\n]*\\|")
+	apiLineCommentPattern = regexp.MustCompile(`(?m)^\*.*$|"[^\n]*$|//[^\n]*$`)
+	apiDefinitionPattern  = regexp.MustCompile(`(?i)\b(?:CLASS|INTERFACE)\s+([\w/]+)\s+(?:DEFINITION|IMPLEMENTATION|PUBLIC)|\bdefine\s+(?:root\s+)?(?:view\s+entity|view|table\s+function|abstract\s+entity|custom\s+entity)\s+([\w/]+)`)
+)
+
+// apiIgnoredNames are words the patterns match that are no object names.
+var apiIgnoredNames = map[string]bool{
+	"ME": true, "SUPER": true, "DATA": true, "LINE": true, "TABLE": true, "OF": true,
+	"OBJECT": true, "EXCEPTION": true, "TYPE": true, "RESUMABLE": true,
+}
+
+// apiBuiltinTypes are the predefined ABAP types, e.g. in TYPE REF TO i.
+var apiBuiltinTypes = map[string]bool{
+	"I": true, "INT8": true, "F": true, "P": true, "C": true, "N": true, "D": true, "T": true,
+	"X": true, "B": true, "S": true, "STRING": true, "XSTRING": true, "DECFLOAT16": true,
+	"DECFLOAT34": true, "UTCLONG": true, "ANY": true, "SIMPLE": true, "NUMERIC": true,
+	"CLIKE": true, "CSEQUENCE": true, "XSEQUENCE": true, "DECFLOAT": true,
+}
+
+// FindAPIReferences returns the SAP objects used by ABAP or CDS source code:
+// classes and interfaces, tables and CDS entities, and function modules.
+// Customer objects (Z*, Y*), local classes and the objects defined by the
+// source itself are left out.
+func FindAPIReferences(source string) []APIReference {
+	refs := make(map[string]string)
+	add := func(name, kind string) {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" || apiIgnoredNames[name] || apiBuiltinTypes[name] || isCustomerObject(name) {
+			return
+		}
+		if _, ok := refs[name]; !ok {
+			refs[name] = kind
+		}
+	}
+
+	for _, m := range apiFunctionRefPattern.FindAllStringSubmatch(source, -1) {
+		add(m[1], "FUNC")
+	}
+	code := apiLineCommentPattern.ReplaceAllString(apiStringPattern.ReplaceAllString(source, "''"), "")
+
+	defined := make(map[string]bool)
+	for _, m := range apiDefinitionPattern.FindAllStringSubmatch(code, -1) {
+		defined[strings.ToUpper(m[1]+m[2])] = true
+	}
+
+	for _, p := range apiClassRefPatterns {
+		for _, m := range p.FindAllStringSubmatch(code, -1) {
+			for _, name := range strings.Fields(m[1]) {
+				add(name, "CLAS")
+			}
+		}
+	}
+	for _, p := range apiDataRefPatterns {
+		for _, m := range p.FindAllStringSubmatch(code, -1) {
+			if strings.HasPrefix(m[1], "@") {
+				continue
+			}
+			add(m[1], "DDLS")
+		}
+	}
+
+	result := make([]APIReference, 0, len(refs))
+	for name, kind := range refs {
+		if !defined[name] {
+			result = append(result, APIReference{Name: name, Kind: kind})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// isCustomerObject reports whether a name is in the customer namespace or a
+// local class or interface, which need no release.
+func isCustomerObject(name string) bool {
+	if strings.HasPrefix(name, "Z") || strings.HasPrefix(name, "Y") {
+		return true
+	}
+	for _, prefix := range []string{"LCL_", "LIF_", "LTC_", "LTH_", "LCX_"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testAPIRelease = `<ars:apiRelease xmlns:ars="http://www.sap.com/adt/ars" xmlns:adtcore="http://www.sap.com/adt/core">
+  <ars:releasableObject adtcore:uri="/sap/bc/adt/oo/classes/cl_abap_context_info" adtcore:type="CLAS/OC" adtcore:name="CL_ABAP_CONTEXT_INFO"/>
+  <ars:c0Release ars:contract="C0"><ars:status ars:state="NOT_RELEASED"/></ars:c0Release>
+  <ars:c1Release ars:contract="C1" ars:useInSAPCloudPlatform="true" ars:useInKeyUserApps="true">
+    <ars:status ars:state="RELEASED" ars:stateDescription="Released"/>
+  </ars:c1Release>
+</ars:apiRelease>`
+
+func TestParseAPIReleaseState(t *testing.T) {
+	state, err := parseAPIReleaseState([]byte(testAPIRelease))
+	if err != nil {
+		t.Fatalf("parseAPIReleaseState failed: %v", err)
+	}
+	if state.Name != "CL_ABAP_CONTEXT_INFO" || state.Type != "CLAS/OC" || len(state.Contracts) != 2 {
+		t.Fatalf("unexpected state: %+v", state)
+	}
+	if state.CloudState() != "RELEASED" {
+		t.Errorf("CloudState() = %s, want RELEASED", state.CloudState())
+	}
+
+	state.Contracts[1].UseInCloud = false
+	if state.CloudState() != "NOT_RELEASED" {
+		t.Errorf("C1 without cloud use: CloudState() = %s, want NOT_RELEASED", state.CloudState())
+	}
+}
+
+func TestFindAPIReferences(t *testing.T) {
+	source := `CLASS zcl_demo DEFINITION PUBLIC FINAL CREATE PUBLIC.
+  PUBLIC SECTION.
+    INTERFACES if_oo_adt_classrun.
+    METHODS run RAISING cx_static_check.
+ENDCLASS.
+
+CLASS zcl_demo IMPLEMENTATION.
+  METHOD run.
+* SELECT * FROM tadir. (comment)
+    DATA lo_local TYPE REF TO lcl_helper.
+    DATA lo_data TYPE REF TO data.
+    DATA lr_count TYPE REF TO i.
+    DATA lr_text TYPE REF TO string.
+    DATA(lv_date) = cl_abap_context_info=>get_system_date( ).
+    SELECT SINGLE * FROM mara INTO @DATA(ls_mara). " FROM usr02
+    SELECT * FROM @lt_items AS items INTO TABLE @DATA(lt_copy).
+    READ TABLE lt_copy INTO DATA(ls_copy) FROM ls_key.
+    DATA(lo_obj) = NEW zcl_other( ).
+    DATA(lv_text) = |FROM bkpf { lv_date }|.
+    CALL FUNCTION 'BAPI_USER_GET_DETAIL'.
+    TRY.
+        lo_obj->go( ).
+      CATCH cx_sy_zerodivide cx_sy_arithmetic_overflow INTO DATA(lx).
+    ENDTRY.
+  ENDMETHOD.
+ENDCLASS.`
+
+	var names []string
+	for _, ref := range FindAPIReferences(source) {
+		names = append(names, ref.Name+":"+ref.Kind)
+	}
+	want := []string{
+		"BAPI_USER_GET_DETAIL:FUNC",
+		"CL_ABAP_CONTEXT_INFO:CLAS",
+		"CX_STATIC_CHECK:CLAS",
+		"CX_SY_ARITHMETIC_OVERFLOW:CLAS",
+		"CX_SY_ZERODIVIDE:CLAS",
+		"IF_OO_ADT_CLASSRUN:CLAS",
+		"MARA:DDLS",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("FindAPIReferences() = %v, want %v", names, want)
+	}
+}
+
+func TestFindAPIReferencesCDS(t *testing.T) {
+	source := `@AccessControl.authorizationCheck: #NOT_REQUIRED
+define root view entity ZI_Travel as select from /dmo/travel as Travel
+  // association [0..1] to I_Country as _Old
+  composition [0..*] of ZI_Booking as _Booking
+  association [0..1] to I_Currency as _Currency on $projection.CurrencyCode = _Currency.Currency
+  inner join I_Customer as Customer on Customer.Customer = Travel.customer_id
+{
+  key travel_id as TravelID
+}`
+
+	var names []string
+	for _, ref := range FindAPIReferences(source) {
+		names = append(names, ref.Name)
+	}
+	want := []string{"/DMO/TRAVEL", "I_CURRENCY", "I_CUSTOMER"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("FindAPIReferences() = %v, want %v", names, want)
+	}
+}
+
+func TestCheckReleasedAPIs(t *testing.T) {
+	states := map[string]string{
+		"cl_abap_context_info":     "RELEASED",
+		"cl_gui_frontend_services": "NOT_RELEASED",
+		"cl_abap_char_utilities":   "DEPRECATED",
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case r.URL.Path == "/sap/bc/adt/repository/informationsystem/search":
+			name := strings.ToUpper(r.URL.Query().Get("query"))
+			if _, ok := states[strings.ToLower(name)]; !ok {
+				fmt.Fprint(w, `<adtcore:objectReferences xmlns:adtcore="http://www.sap.com/adt/core"/>`)
+				return
+			}
+			fmt.Fprintf(w, `<adtcore:objectReferences xmlns:adtcore="http://www.sap.com/adt/core">
+  <adtcore:objectReference adtcore:uri="/sap/bc/adt/oo/classes/%s" adtcore:type="CLAS/OC" adtcore:name="%s"/>
+</adtcore:objectReferences>`, strings.ToLower(name), name)
+		case strings.HasPrefix(r.URL.Path, "/sap/bc/adt/apireleases/"):
+			name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			fmt.Fprintf(w, `<ars:apiRelease xmlns:ars="http://www.sap.com/adt/ars" xmlns:adtcore="http://www.sap.com/adt/core">
+  <ars:releasableObject adtcore:uri="/sap/bc/adt/oo/classes/%[1]s" adtcore:type="CLAS/OC" adtcore:name="%[1]s"/>
+  <ars:c1Release ars:contract="C1" ars:useInSAPCloudPlatform="true"><ars:status ars:state="%[2]s"/></ars:c1Release>
+</ars:apiRelease>`, name, states[name])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass")
+	source := `DATA(lv_date) = cl_abap_context_info=>get_system_date( ).
+cl_gui_frontend_services=>gui_download( ).
+DATA(lv_nl) = cl_abap_char_utilities=>newline.
+DATA lo_x TYPE REF TO cl_unknown_thing.`
+
+	result, err := client.CheckReleasedAPIs(context.Background(), source)
+	if err != nil {
+		t.Fatalf("CheckReleasedAPIs failed: %v", err)
+	}
+	if result.Passed || len(result.Checked) != 4 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if len(result.Violations) != 2 || result.Violations[0].Name != "CL_GUI_FRONTEND_SERVICES" || result.Violations[1].State != "NOT_FOUND" {
+		t.Errorf("violations = %+v", result.Violations)
+	}
+	if len(result.Deprecated) != 1 || result.Deprecated[0].Name != "CL_ABAP_CHAR_UTILITIES" {
+		t.Errorf("deprecated = %+v", result.Deprecated)
+	}
+	if !strings.Contains(result.Summary(), "CL_GUI_FRONTEND_SERVICES (not released)") {
+		t.Errorf("Summary() = %s", result.Summary())
+	}
+
+	// Release states are cached per client
+	requests = 0
+	if _, err := client.CheckReleasedAPIs(context.Background(), source); err != nil || requests != 0 {
+		t.Errorf("second check: %d requests, %v", requests, err)
+	}
+
+	// Outside cloud mode sources are not checked; in cloud mode they are refused
+	if err := client.checkCloudSource(context.Background(), source); err != nil {
+		t.Errorf("checkCloudSource without cloud mode: %v", err)
+	}
+	client.SetCloudMode(true)
+	if err := client.checkCloudSource(context.Background(), source); err == nil || !strings.Contains(err.Error(), "ABAP Cloud") {
+		t.Errorf("checkCloudSource in cloud mode = %v", err)
+	}
+}
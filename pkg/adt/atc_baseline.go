@@ -0,0 +1,212 @@
+package adt
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- ATC Baseline ---
+//
+// A baseline records the ATC findings of a known state (typically legacy
+// code) so that later runs can report only what changed. Findings are
+// matched by fingerprint: check ID, message ID, object, normalized location
+// and message text. The location is normalized to the source file without
+// line/column, so findings survive unrelated edits that shift line numbers.
+// Identical fingerprints are counted, so a second occurrence of a known
+// finding in the same file is still reported as new.
+
+// ATCBaselineVersion is the current baseline file format version.
+const ATCBaselineVersion = 1
+
+// ATCBaselineFinding is a finding recorded in a baseline.
+type ATCBaselineFinding struct {
+	Fingerprint string `json:"fingerprint"`
+	CheckID     string `json:"checkId"`
+	MessageID   string `json:"messageId,omitempty"`
+	Object      string `json:"object"`   // TYPE/NAME
+	Location    string `json:"location"` // Normalized location
+	Message     string `json:"message"`
+	Priority    int    `json:"priority"`
+	Line        int    `json:"line,omitempty"` // Informational, not part of the fingerprint
+}
+
+// ATCBaseline is a set of accepted ATC findings, stored as JSON.
+type ATCBaseline struct {
+	Version  int                  `json:"version"`
+	Created  time.Time            `json:"created"`
+	Findings []ATCBaselineFinding `json:"findings"`
+}
+
+// ATCComparison is the result of comparing a worklist with a baseline.
+type ATCComparison struct {
+	New       *ATCWorklist         `json:"new"`   // Findings not in the baseline, grouped by object
+	Fixed     []ATCBaselineFinding `json:"fixed"` // Baseline findings no longer reported
+	NewCount  int                  `json:"newCount"`
+	Unchanged int                  `json:"unchanged"`
+}
+
+// ATCFindingFingerprint returns the baseline fingerprint of a finding.
+func ATCFindingFingerprint(obj ATCObject, f ATCFinding) string {
+	return newATCBaselineFinding(obj, f).Fingerprint
+}
+
+func newATCBaselineFinding(obj ATCObject, f ATCFinding) ATCBaselineFinding {
+	location := f.Location
+	if location == "" {
+		location = obj.URI
+	}
+	bf := ATCBaselineFinding{
+		CheckID:   f.CheckID,
+		MessageID: f.MessageID,
+		Object:    strings.ToUpper(obj.Type + "/" + obj.Name),
+		Location:  normalizeATCLocation(location),
+		Message:   strings.Join(strings.Fields(f.MessageTitle), " "),
+		Priority:  f.Priority,
+		Line:      f.Line,
+	}
+	key := strings.Join([]string{bf.CheckID, bf.MessageID, bf.Object, bf.Location, bf.Message}, "\x00")
+	sum := sha1.Sum([]byte(key))
+	bf.Fingerprint = hex.EncodeToString(sum[:])
+	return bf
+}
+
+// normalizeATCLocation drops the position from an ATC location and maps it
+// to the abapGit file name where possible.
+func normalizeATCLocation(location string) string {
+	if file := AbapGitFileName(location); file != "" {
+		return file
+	}
+	if i := strings.IndexAny(location, "?#"); i >= 0 {
+		location = location[:i]
+	}
+	return strings.ToLower(location)
+}
+
+// NewATCBaseline captures all findings of the worklist as a baseline.
+func NewATCBaseline(w *ATCWorklist) *ATCBaseline {
+	b := &ATCBaseline{
+		Version:  ATCBaselineVersion,
+		Created:  time.Now().UTC().Truncate(time.Second),
+		Findings: []ATCBaselineFinding{},
+	}
+	for _, obj := range w.Objects {
+		for _, f := range obj.Findings {
+			b.Findings = append(b.Findings, newATCBaselineFinding(obj, f))
+		}
+	}
+	b.sort()
+	return b
+}
+
+func (b *ATCBaseline) sort() {
+	sort.SliceStable(b.Findings, func(i, j int) bool {
+		x, y := b.Findings[i], b.Findings[j]
+		if x.Object != y.Object {
+			return x.Object < y.Object
+		}
+		if x.Location != y.Location {
+			return x.Location < y.Location
+		}
+		if x.Line != y.Line {
+			return x.Line < y.Line
+		}
+		return x.CheckID < y.CheckID
+	})
+}
+
+// LoadATCBaseline reads a baseline file.
+func LoadATCBaseline(path string) (*ATCBaseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading ATC baseline: %w", err)
+	}
+	var b ATCBaseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing ATC baseline %s: %w", path, err)
+	}
+	if b.Version > ATCBaselineVersion {
+		return nil, fmt.Errorf("ATC baseline %s has unsupported version %d", path, b.Version)
+	}
+	return &b, nil
+}
+
+// Save writes the baseline as indented JSON, sorted for stable diffs.
+func (b *ATCBaseline) Save(path string) error {
+	b.sort()
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding ATC baseline: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing ATC baseline: %w", err)
+	}
+	return nil
+}
+
+// Compare returns the findings of the worklist that are not in the baseline
+// and the baseline findings that are no longer reported. The baseline should
+// cover the same objects as the worklist; findings of objects that were not
+// checked are reported as fixed.
+func (b *ATCBaseline) Compare(w *ATCWorklist) *ATCComparison {
+	known := make(map[string]int)
+	for _, f := range b.Findings {
+		known[f.Fingerprint]++
+	}
+
+	cmp := &ATCComparison{
+		New: &ATCWorklist{
+			ID:                  w.ID,
+			Timestamp:           w.Timestamp,
+			UsedObjectSet:       w.UsedObjectSet,
+			ObjectSetIsComplete: w.ObjectSetIsComplete,
+			ObjectSets:          w.ObjectSets,
+		},
+		Fixed: []ATCBaselineFinding{},
+	}
+	for _, obj := range w.Objects {
+		newObj := obj
+		newObj.Findings = nil
+		for _, f := range obj.Findings {
+			fp := ATCFindingFingerprint(obj, f)
+			if known[fp] > 0 {
+				known[fp]--
+				cmp.Unchanged++
+				continue
+			}
+			newObj.Findings = append(newObj.Findings, f)
+		}
+		if len(newObj.Findings) > 0 {
+			cmp.New.Objects = append(cmp.New.Objects, newObj)
+			cmp.NewCount += len(newObj.Findings)
+		}
+	}
+
+	// Whatever is left in known was not matched by the current run
+	for _, f := range b.Findings {
+		if known[f.Fingerprint] > 0 {
+			known[f.Fingerprint]--
+			cmp.Fixed = append(cmp.Fixed, f)
+		}
+	}
+	return cmp
+}
+
+// CountFindings returns the number of findings that are not exempted and
+// have a priority of maxPriority or higher (1 = error, 2 = warning, 3 = info).
+func (w *ATCWorklist) CountFindings(maxPriority int) int {
+	n := 0
+	for _, obj := range w.Objects {
+		for _, f := range obj.Findings {
+			if f.ExemptionKind == "" && f.Priority > 0 && f.Priority <= maxPriority {
+				n++
+			}
+		}
+	}
+	return n
+}
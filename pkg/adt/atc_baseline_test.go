@@ -0,0 +1,63 @@
+package adt
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func testBaselineWorklist() *ATCWorklist {
+	return &ATCWorklist{ID: "WL1", Objects: []ATCObject{{
+		URI:  "/sap/bc/adt/oo/classes/zcl_legacy",
+		Type: "CLAS",
+		Name: "ZCL_LEGACY",
+		Findings: []ATCFinding{
+			{Location: "/sap/bc/adt/oo/classes/zcl_legacy/source/main#start=10,2", Priority: 2, CheckID: "CL_CI_TEST_SELECT", MessageID: "0001", MessageTitle: "SELECT *  used", Line: 10},
+			{Location: "/sap/bc/adt/oo/classes/zcl_legacy/source/main#start=40,2", Priority: 1, CheckID: "CL_CI_TEST_EXTENDED", MessageID: "0002", MessageTitle: "Unused variable", Line: 40},
+		},
+	}}}
+}
+
+func TestATCBaseline_Compare(t *testing.T) {
+	baseline := NewATCBaseline(testBaselineWorklist())
+	if len(baseline.Findings) != 2 || baseline.Findings[0].Location != "zcl_legacy.clas.abap" {
+		t.Fatalf("unexpected baseline: %+v", baseline.Findings)
+	}
+
+	path := filepath.Join(t.TempDir(), "atc-baseline.json")
+	if err := baseline.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := LoadATCBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadATCBaseline failed: %v", err)
+	}
+
+	// Lines shifted by an edit, one finding fixed, two new findings (one of
+	// them a second occurrence of a known finding)
+	current := testBaselineWorklist()
+	obj := &current.Objects[0]
+	obj.Findings[0].Location = "/sap/bc/adt/oo/classes/zcl_legacy/source/main#start=14,2"
+	obj.Findings[0].Line = 14
+	obj.Findings[0].MessageTitle = "SELECT * used"
+	obj.Findings = append(obj.Findings[:1],
+		ATCFinding{Location: "/sap/bc/adt/oo/classes/zcl_legacy/source/main#start=60,2", Priority: 2, CheckID: "CL_CI_TEST_SELECT", MessageID: "0001", MessageTitle: "SELECT * used", Line: 60},
+		ATCFinding{Location: "/sap/bc/adt/oo/classes/zcl_legacy/source/main#start=70,2", Priority: 3, CheckID: "CL_CI_NAMING", MessageTitle: "Naming"},
+	)
+
+	cmp := loaded.Compare(current)
+	if cmp.Unchanged != 1 || cmp.NewCount != 2 || len(cmp.Fixed) != 1 {
+		t.Fatalf("unchanged=%d new=%d fixed=%d", cmp.Unchanged, cmp.NewCount, len(cmp.Fixed))
+	}
+	if cmp.Fixed[0].CheckID != "CL_CI_TEST_EXTENDED" {
+		t.Errorf("unexpected fixed finding: %+v", cmp.Fixed[0])
+	}
+	if newFindings := cmp.New.Objects[0].Findings; newFindings[0].Line != 60 || newFindings[1].CheckID != "CL_CI_NAMING" {
+		t.Errorf("unexpected new findings: %+v", newFindings)
+	}
+	if n := cmp.New.CountFindings(2); n != 1 {
+		t.Errorf("new findings with priority <= 2 = %d, want 1", n)
+	}
+	if n := cmp.New.CountFindings(3); n != 2 {
+		t.Errorf("new findings with priority <= 3 = %d, want 2", n)
+	}
+}
@@ -0,0 +1,573 @@
+package adt
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// --- ATC Quick Fixes and Exemptions ---
+//
+// Quick fixes are evaluated by ADT at the location of a finding. Applying a
+// fix returns text deltas for the source, which are merged locally, shown as
+// a unified diff, and written through the usual lock/update/activate path.
+// Exemptions are requested via the finding's marker ID (quickfixInfo).
+
+// ATCQuickFix is a quick fix proposed by ADT for a source position.
+type ATCQuickFix struct {
+	URI         string `json:"uri"`
+	Type        string `json:"type,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	UserContent string `json:"-"` // Opaque data passed back when the fix is applied
+}
+
+// ATCQuickFixDelta is a text change of a quick fix. Lines are 1-based,
+// columns 0-based; the end position is exclusive.
+type ATCQuickFixDelta struct {
+	URI       string `json:"uri"`
+	StartLine int    `json:"startLine"`
+	StartCol  int    `json:"startColumn"`
+	EndLine   int    `json:"endLine"`
+	EndCol    int    `json:"endColumn"`
+	Content   string `json:"content"`
+}
+
+// ATCQuickFixPreview is the effect of a quick fix on the source.
+type ATCQuickFixPreview struct {
+	Fix       ATCQuickFix        `json:"fix"`
+	SourceURL string             `json:"sourceUrl"`
+	Deltas    []ATCQuickFixDelta `json:"deltas"`
+	Diff      string             `json:"diff"`
+	Source    string             `json:"-"` // Source with the fix applied
+}
+
+// ATCQuickFixOptions configures ApplyATCQuickFix.
+type ATCQuickFixOptions struct {
+	Transport   string // Transport request (required for non-local packages)
+	SyntaxCheck bool   // Check the fixed source before saving
+}
+
+// ATCQuickFixResult is the result of applying a quick fix.
+type ATCQuickFixResult struct {
+	Success      bool                `json:"success"`
+	Preview      *ATCQuickFixPreview `json:"preview,omitempty"`
+	SyntaxErrors []string            `json:"syntaxErrors,omitempty"`
+	Activation   *ActivationResult   `json:"activation,omitempty"`
+	Message      string              `json:"message,omitempty"`
+}
+
+// atcSourceURL returns the source URL of a finding location, e.g.
+// "/sap/bc/adt/oo/classes/zcl_foo/source/main" for
+// "/sap/bc/adt/oo/classes/zcl_foo/source/main#start=10,2".
+func atcSourceURL(location string) string {
+	if i := strings.Index(location, "#"); i >= 0 {
+		location = location[:i]
+	}
+	return location
+}
+
+// atcMainObjectURL returns the URL of the object that owns a source URL,
+// which is the object to lock and activate.
+func atcMainObjectURL(sourceURL string) string {
+	if i := strings.Index(sourceURL, "/includes/"); i > 0 {
+		return sourceURL[:i]
+	}
+	return strings.TrimSuffix(sourceURL, "/source/main")
+}
+
+// GetATCQuickFixes returns the quick fixes ADT offers at a finding location
+// (ATCFinding.Location, which includes the #start position).
+func (c *Client) GetATCQuickFixes(ctx context.Context, location string) ([]ATCQuickFix, error) {
+	if err := c.checkSafety(OpRead, "GetATCQuickFixes"); err != nil {
+		return nil, err
+	}
+	if !strings.Contains(location, "#start=") {
+		return nil, fmt.Errorf("location %q has no source position", location)
+	}
+
+	source, err := c.readATCSource(ctx, atcSourceURL(location))
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("uri", location)
+	resp, err := c.transport.Request(ctx, "/sap/bc/adt/quickfixes/evaluation", &RequestOptions{
+		Method:      http.MethodPost,
+		Query:       query,
+		Body:        []byte(source),
+		ContentType: "application/*",
+		Accept:      "application/*",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("evaluating quick fixes: %w", err)
+	}
+	return parseQuickFixEvaluation(resp.Body)
+}
+
+func parseQuickFixEvaluation(data []byte) ([]ATCQuickFix, error) {
+	xmlStr := string(data)
+	xmlStr = strings.ReplaceAll(xmlStr, "qf:", "")
+	xmlStr = strings.ReplaceAll(xmlStr, "adtcore:", "")
+
+	type evaluationResult struct {
+		ObjectReference struct {
+			URI         string `xml:"uri,attr"`
+			Type        string `xml:"type,attr"`
+			Name        string `xml:"name,attr"`
+			Description string `xml:"description,attr"`
+		} `xml:"objectReference"`
+		UserContent string `xml:"userContent"`
+	}
+	var resp struct {
+		Results []evaluationResult `xml:"evaluationResult"`
+	}
+	if err := xml.Unmarshal([]byte(xmlStr), &resp); err != nil {
+		return nil, fmt.Errorf("parsing quick fix evaluation: %w", err)
+	}
+
+	fixes := []ATCQuickFix{}
+	for _, r := range resp.Results {
+		fixes = append(fixes, ATCQuickFix{
+			URI:         r.ObjectReference.URI,
+			Type:        r.ObjectReference.Type,
+			Name:        r.ObjectReference.Name,
+			Description: r.ObjectReference.Description,
+			UserContent: r.UserContent,
+		})
+	}
+	return fixes, nil
+}
+
+// PreviewATCQuickFix computes the changes of a quick fix without saving
+// them. Fixes that change other sources than the finding's are rejected.
+func (c *Client) PreviewATCQuickFix(ctx context.Context, location string, fix ATCQuickFix) (*ATCQuickFixPreview, error) {
+	if err := c.checkSafety(OpRead, "PreviewATCQuickFix"); err != nil {
+		return nil, err
+	}
+
+	sourceURL := atcSourceURL(location)
+	source, err := c.readATCSource(ctx, sourceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<quickfixes:proposalRequest xmlns:quickfixes="http://www.sap.com/adt/quickfixes" xmlns:adtcore="http://www.sap.com/adt/core">
+  <input>
+    <content>%s</content>
+    <adtcore:objectReference adtcore:uri="%s"/>
+  </input>
+  <userContent>%s</userContent>
+</quickfixes:proposalRequest>`, xmlEscape(source), xmlEscape(location), xmlEscape(fix.UserContent))
+
+	resp, err := c.transport.Request(ctx, fix.URI, &RequestOptions{
+		Method:      http.MethodPost,
+		Body:        []byte(body),
+		ContentType: "application/*",
+		Accept:      "application/*",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("applying quick fix %s: %w", fix.Name, err)
+	}
+
+	deltas, err := parseQuickFixDeltas(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deltas {
+		if !strings.EqualFold(atcSourceURL(d.URI), sourceURL) {
+			return nil, fmt.Errorf("quick fix %s changes %s; only changes to %s are supported", fix.Name, atcSourceURL(d.URI), sourceURL)
+		}
+	}
+
+	fixed, err := applyQuickFixDeltas(source, deltas)
+	if err != nil {
+		return nil, err
+	}
+	name := atcMainObjectURL(sourceURL)
+	return &ATCQuickFixPreview{
+		Fix:       fix,
+		SourceURL: sourceURL,
+		Deltas:    deltas,
+		Diff:      generateUnifiedDiff(name, name+" (fixed)", strings.Split(source, "\n"), strings.Split(fixed, "\n")),
+		Source:    fixed,
+	}, nil
+}
+
+var quickFixRangeRegex = regexp.MustCompile(`#start=(\d+),(\d+)(?:;end=(\d+),(\d+))?`)
+
+func parseQuickFixDeltas(data []byte) ([]ATCQuickFixDelta, error) {
+	xmlStr := string(data)
+	xmlStr = strings.ReplaceAll(xmlStr, "quickfixes:", "")
+	xmlStr = strings.ReplaceAll(xmlStr, "adtcore:", "")
+
+	type unit struct {
+		ObjectReference struct {
+			URI string `xml:"uri,attr"`
+		} `xml:"objectReference"`
+		Content string `xml:"content"`
+	}
+	var resp struct {
+		Deltas struct {
+			Units []unit `xml:"unit"`
+		} `xml:"deltas"`
+	}
+	if err := xml.Unmarshal([]byte(xmlStr), &resp); err != nil {
+		return nil, fmt.Errorf("parsing quick fix result: %w", err)
+	}
+
+	deltas := []ATCQuickFixDelta{}
+	for _, u := range resp.Deltas.Units {
+		m := quickFixRangeRegex.FindStringSubmatch(u.ObjectReference.URI)
+		if m == nil {
+			return nil, fmt.Errorf("quick fix delta without range: %s", u.ObjectReference.URI)
+		}
+		d := ATCQuickFixDelta{URI: u.ObjectReference.URI, Content: u.Content}
+		fmt.Sscanf(m[1]+" "+m[2], "%d %d", &d.StartLine, &d.StartCol)
+		d.EndLine, d.EndCol = d.StartLine, d.StartCol
+		if m[3] != "" {
+			fmt.Sscanf(m[3]+" "+m[4], "%d %d", &d.EndLine, &d.EndCol)
+		}
+		deltas = append(deltas, d)
+	}
+	return deltas, nil
+}
+
+// applyQuickFixDeltas replaces the delta ranges in source, last range first
+// so that earlier positions stay valid.
+func applyQuickFixDeltas(source string, deltas []ATCQuickFixDelta) (string, error) {
+	source = normalizeLineEndings(source)
+	lines := strings.Split(source, "\n")
+
+	// offset converts a line/column position into a byte offset
+	offset := func(line, col int) (int, error) {
+		if line < 1 || line > len(lines)+1 {
+			return 0, fmt.Errorf("quick fix position %d,%d is outside the source", line, col)
+		}
+		pos := 0
+		for i := 0; i < line-1 && i < len(lines); i++ {
+			pos += len(lines[i]) + 1
+		}
+		if line <= len(lines) && col > len(lines[line-1]) {
+			col = len(lines[line-1])
+		}
+		if pos+col > len(source) {
+			return len(source), nil
+		}
+		return pos + col, nil
+	}
+
+	sorted := append([]ATCQuickFixDelta{}, deltas...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].StartLine != sorted[j].StartLine {
+			return sorted[i].StartLine > sorted[j].StartLine
+		}
+		return sorted[i].StartCol > sorted[j].StartCol
+	})
+
+	for _, d := range sorted {
+		start, err := offset(d.StartLine, d.StartCol)
+		if err != nil {
+			return "", err
+		}
+		end, err := offset(d.EndLine, d.EndCol)
+		if err != nil {
+			return "", err
+		}
+		if end < start {
+			return "", fmt.Errorf("invalid quick fix range %d,%d-%d,%d", d.StartLine, d.StartCol, d.EndLine, d.EndCol)
+		}
+		source = source[:start] + normalizeLineEndings(d.Content) + source[end:]
+	}
+	return source, nil
+}
+
+// ApplyATCQuickFix applies a quick fix and activates the object.
+//
+// Workflow: PreviewATCQuickFix → SyntaxCheck → Lock → UpdateSource → Unlock → Activate
+func (c *Client) ApplyATCQuickFix(ctx context.Context, location string, fix ATCQuickFix, opts *ATCQuickFixOptions) (*ATCQuickFixResult, error) {
+	if err := c.checkSafety(OpUpdate, "ApplyATCQuickFix"); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &ATCQuickFixOptions{SyntaxCheck: true}
+	}
+	if err := c.checkTransportableEdit(opts.Transport, "ApplyATCQuickFix"); err != nil {
+		return nil, err
+	}
+
+	preview, err := c.PreviewATCQuickFix(ctx, location, fix)
+	if err != nil {
+		return nil, err
+	}
+	result := &ATCQuickFixResult{Preview: preview}
+	if len(preview.Deltas) == 0 {
+		result.Message = fmt.Sprintf("Quick fix %s returned no changes", fix.Name)
+		return result, nil
+	}
+
+	objectURL := atcMainObjectURL(preview.SourceURL)
+	objectName := strings.ToUpper(objectURL[strings.LastIndex(objectURL, "/")+1:])
+	if decoded, err := url.PathUnescape(objectName); err == nil {
+		objectName = decoded
+	}
+
+	if opts.SyntaxCheck {
+		checkURL := objectURL
+		if strings.Contains(preview.SourceURL, "/includes/") {
+			checkURL = preview.SourceURL
+		}
+		syntaxErrors, err := c.SyntaxCheck(ctx, checkURL, preview.Source)
+		if err != nil {
+			result.Message = fmt.Sprintf("Syntax check failed: %v", err)
+			return result, nil
+		}
+		for _, e := range syntaxErrors {
+			if e.Severity == "E" || e.Severity == "A" || e.Severity == "X" {
+				result.SyntaxErrors = append(result.SyntaxErrors, fmt.Sprintf("Line %d: %s", e.Line, e.Text))
+			}
+		}
+		if len(result.SyntaxErrors) > 0 {
+			result.Message = fmt.Sprintf("Quick fix would introduce %d syntax errors. Changes NOT saved.", len(result.SyntaxErrors))
+			return result, nil
+		}
+	}
+
+	lock, err := c.LockObject(ctx, objectURL, "MODIFY")
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to lock object: %v", err)
+		return result, nil
+	}
+	unlocked := false
+	defer func() {
+		if !unlocked {
+			_ = c.UnlockObject(ctx, objectURL, lock.LockHandle)
+		}
+	}()
+
+	if err := c.UpdateSource(ctx, preview.SourceURL, preview.Source, lock.LockHandle, opts.Transport); err != nil {
+		result.Message = fmt.Sprintf("Failed to update source: %v", err)
+		return result, nil
+	}
+
+	err = c.UnlockObject(ctx, objectURL, lock.LockHandle)
+	unlocked = true
+	if err != nil {
+		result.Message = fmt.Sprintf("Source updated but unlock failed: %v", err)
+		return result, nil
+	}
+
+	activation, err := c.Activate(ctx, objectURL, objectName)
+	if err != nil {
+		result.Message = fmt.Sprintf("Source updated but activation failed: %v", err)
+		return result, nil
+	}
+	result.Activation = activation
+	result.Success = activation.Success
+	result.Message = fmt.Sprintf("Applied quick fix %s and activated %s", fix.Name, objectName)
+	if !activation.Success {
+		result.Message = fmt.Sprintf("Applied quick fix %s but activation of %s reported errors", fix.Name, objectName)
+	}
+	return result, nil
+}
+
+func (c *Client) readATCSource(ctx context.Context, sourceURL string) (string, error) {
+	resp, err := c.transport.Request(ctx, sourceURL, &RequestOptions{
+		Method: http.MethodGet,
+		Accept: "text/plain",
+	})
+	if err != nil {
+		return "", fmt.Errorf("reading source %s: %w", sourceURL, err)
+	}
+	return normalizeLineEndings(string(resp.Body)), nil
+}
+
+// --- Exemptions ---
+
+// ATCExemptionProposal is the exemption template ADT returns for a finding.
+type ATCExemptionProposal struct {
+	MarkerID           string `json:"markerId"`
+	Package            string `json:"package,omitempty"`
+	SubObject          string `json:"subObject,omitempty"`
+	SubObjectType      string `json:"subObjectType,omitempty"`
+	SubObjectTypeDescr string `json:"subObjectTypeDescr,omitempty"`
+	ObjectTypeDescr    string `json:"objectTypeDescr,omitempty"`
+	CheckClass         string `json:"checkClass,omitempty"`
+	Approver           string `json:"approver,omitempty"`
+	Reason             string `json:"reason,omitempty"`
+	Justification      string `json:"justification,omitempty"`
+	Notify             string `json:"notify,omitempty"`
+	findingXML         string // Finding element, sent back unchanged
+}
+
+// ATCExemptionRequest holds the user input for an exemption.
+type ATCExemptionRequest struct {
+	Reason        string // Reason ID from ATC customizing (e.g. FPOS)
+	Justification string
+	Approver      string // Empty = approver from the proposal
+}
+
+// ATCExemptionResult is the answer of the system to an exemption request.
+type ATCExemptionResult struct {
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message"`
+}
+
+// GetATCExemptionProposal returns the exemption template for a finding.
+// markerID is ATCFinding.QuickfixInfo.
+func (c *Client) GetATCExemptionProposal(ctx context.Context, markerID string) (*ATCExemptionProposal, error) {
+	if markerID == "" {
+		return nil, fmt.Errorf("finding has no marker ID (quickfixInfo)")
+	}
+	query := url.Values{}
+	query.Set("markerId", markerID)
+	resp, err := c.transport.Request(ctx, "/sap/bc/adt/atc/exemptions/apply", &RequestOptions{
+		Method: http.MethodGet,
+		Query:  query,
+		Accept: "application/atc.xmpt.v1+xml, application/atc.xmptapp.v1+xml",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting exemption proposal: %w", err)
+	}
+	proposal, err := parseATCExemptionProposal(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	proposal.MarkerID = markerID
+	return proposal, nil
+}
+
+var atcExemptionFindingRegex = regexp.MustCompile(`(?s)<atcfinding:finding\b.*?(?:/>|</atcfinding:finding>)`)
+
+func parseATCExemptionProposal(data []byte) (*ATCExemptionProposal, error) {
+	xmlStr := string(data)
+	if status, err := parseATCExemptionStatus(data); err == nil && status.Message != "" {
+		return nil, fmt.Errorf("exemption not possible: %s", status.Message)
+	}
+	findingXML := atcExemptionFindingRegex.FindString(xmlStr)
+
+	xmlStr = strings.ReplaceAll(xmlStr, "atcexmpt:", "")
+	xmlStr = strings.ReplaceAll(xmlStr, "atcfinding:", "")
+	xmlStr = strings.ReplaceAll(xmlStr, "adtcore:", "")
+
+	var resp struct {
+		XMLName            xml.Name `xml:"exemptionProposal"`
+		Package            string   `xml:"package"`
+		SubObject          string   `xml:"subObject"`
+		SubObjectType      string   `xml:"subObjectType"`
+		SubObjectTypeDescr string   `xml:"subObjectTypeDescr"`
+		ObjectTypeDescr    string   `xml:"objectTypeDescr"`
+		CheckClass         string   `xml:"checkClass"`
+		Approver           string   `xml:"approver"`
+		Reason             string   `xml:"reason"`
+		Justification      string   `xml:"justification"`
+		Notify             string   `xml:"notify"`
+	}
+	if err := xml.Unmarshal([]byte(xmlStr), &resp); err != nil {
+		return nil, fmt.Errorf("parsing exemption proposal: %w", err)
+	}
+	return &ATCExemptionProposal{
+		Package:            resp.Package,
+		SubObject:          resp.SubObject,
+		SubObjectType:      resp.SubObjectType,
+		SubObjectTypeDescr: resp.SubObjectTypeDescr,
+		ObjectTypeDescr:    resp.ObjectTypeDescr,
+		CheckClass:         resp.CheckClass,
+		Approver:           resp.Approver,
+		Reason:             resp.Reason,
+		Justification:      resp.Justification,
+		Notify:             resp.Notify,
+		findingXML:         findingXML,
+	}, nil
+}
+
+func parseATCExemptionStatus(data []byte) (*ATCExemptionResult, error) {
+	xmlStr := strings.ReplaceAll(string(data), "atcexmpt:", "")
+	var status struct {
+		XMLName xml.Name `xml:"status"`
+		Type    string   `xml:"type,attr"`
+		Message string   `xml:"message,attr"`
+		Text    string   `xml:"message"`
+	}
+	if err := xml.Unmarshal([]byte(xmlStr), &status); err != nil {
+		return nil, err
+	}
+	if status.Message == "" {
+		status.Message = strings.TrimSpace(status.Text)
+	}
+	return &ATCExemptionResult{Type: status.Type, Message: status.Message}, nil
+}
+
+// RequestATCExemption requests an exemption for a single finding.
+// markerID is ATCFinding.QuickfixInfo; the reason IDs are listed by
+// GetATCCustomizing.
+func (c *Client) RequestATCExemption(ctx context.Context, markerID string, req ATCExemptionRequest) (*ATCExemptionResult, error) {
+	if err := c.checkSafety(OpUpdate, "RequestATCExemption"); err != nil {
+		return nil, err
+	}
+	if req.Reason == "" {
+		return nil, fmt.Errorf("exemption reason is required")
+	}
+
+	proposal, err := c.GetATCExemptionProposal(ctx, markerID)
+	if err != nil {
+		return nil, err
+	}
+	approver := req.Approver
+	if approver == "" {
+		approver = proposal.Approver
+	}
+	notify := proposal.Notify
+	if notify == "" {
+		notify = "on_rejection"
+	}
+
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<atcexmpt:exemptionApply xmlns:adtcore="http://www.sap.com/adt/core" xmlns:atcexmpt="http://www.sap.com/adt/atc/exemption" xmlns:atcfinding="http://www.sap.com/adt/atc/finding">
+  <atcexmpt:exemptionProposal>
+    %s
+    <atcexmpt:package>%s</atcexmpt:package>
+    <atcexmpt:subObject>%s</atcexmpt:subObject>
+    <atcexmpt:subObjectType>%s</atcexmpt:subObjectType>
+    <atcexmpt:subObjectTypeDescr>%s</atcexmpt:subObjectTypeDescr>
+    <atcexmpt:objectTypeDescr>%s</atcexmpt:objectTypeDescr>
+    <atcexmpt:restriction>
+      <atcexmpt:thisFinding>true</atcexmpt:thisFinding>
+    </atcexmpt:restriction>
+    <atcexmpt:approver>%s</atcexmpt:approver>
+    <atcexmpt:reason>%s</atcexmpt:reason>
+    <atcexmpt:justification>%s</atcexmpt:justification>
+    <atcexmpt:notify>%s</atcexmpt:notify>
+  </atcexmpt:exemptionProposal>
+</atcexmpt:exemptionApply>`,
+		proposal.findingXML,
+		xmlEscape(proposal.Package), xmlEscape(proposal.SubObject), xmlEscape(proposal.SubObjectType),
+		xmlEscape(proposal.SubObjectTypeDescr), xmlEscape(proposal.ObjectTypeDescr),
+		xmlEscape(approver), xmlEscape(req.Reason), xmlEscape(req.Justification), xmlEscape(notify))
+
+	resp, err := c.transport.Request(ctx, "/sap/bc/adt/atc/exemptions/apply", &RequestOptions{
+		Method:      http.MethodPost,
+		Body:        []byte(body),
+		ContentType: "application/atc.xmptapp.v1+xml",
+		Accept:      "application/atc.xmpt.v1+xml, application/atc.xmptapp.v1+xml",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("requesting exemption: %w", err)
+	}
+
+	result, err := parseATCExemptionStatus(resp.Body)
+	if err != nil {
+		return &ATCExemptionResult{Message: "Exemption requested"}, nil
+	}
+	if result.Type == "E" || strings.EqualFold(result.Type, "error") {
+		return nil, fmt.Errorf("exemption request failed: %s", result.Message)
+	}
+	return result, nil
+}
@@ -0,0 +1,166 @@
+package adt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testQuickFixSource = "REPORT zfoo.\nDATA lv_unused TYPE i.\nSELECT * FROM t000 INTO TABLE @DATA(lt).\n"
+
+const testQuickFixEvaluation = `<?xml version="1.0" encoding="utf-8"?>
+<qf:evaluationResults xmlns:qf="http://www.sap.com/adt/quickfixes" xmlns:adtcore="http://www.sap.com/adt/core">
+  <evaluationResult>
+    <adtcore:objectReference adtcore:uri="/sap/bc/adt/quickfixes/fix1" adtcore:type="quickfix/proposal" adtcore:name="Delete unused variable" adtcore:description="Removes the declaration"/>
+    <userContent>LV_UNUSED</userContent>
+  </evaluationResult>
+</qf:evaluationResults>`
+
+const testQuickFixDeltas = `<?xml version="1.0" encoding="utf-8"?>
+<quickfixes:proposalResult xmlns:quickfixes="http://www.sap.com/adt/quickfixes" xmlns:adtcore="http://www.sap.com/adt/core">
+  <deltas>
+    <unit>
+      <adtcore:objectReference adtcore:uri="/sap/bc/adt/programs/programs/zfoo/source/main#start=2,0;end=3,0"/>
+      <content></content>
+    </unit>
+    <unit>
+      <adtcore:objectReference adtcore:uri="/sap/bc/adt/programs/programs/zfoo/source/main#start=3,7;end=3,8"/>
+      <content>mandt</content>
+    </unit>
+  </deltas>
+</quickfixes:proposalResult>`
+
+func TestApplyATCQuickFix(t *testing.T) {
+	var evaluationQuery, proposalBody, updatedSource string
+	var activated bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/sap/bc/adt/programs/programs/zfoo/source/main" && r.Method == http.MethodGet:
+			w.Write([]byte(testQuickFixSource))
+		case r.URL.Path == "/sap/bc/adt/programs/programs/zfoo/source/main" && r.Method == http.MethodPut:
+			updatedSource = string(body)
+		case r.URL.Path == "/sap/bc/adt/quickfixes/evaluation":
+			evaluationQuery = r.URL.Query().Get("uri")
+			w.Write([]byte(testQuickFixEvaluation))
+		case r.URL.Path == "/sap/bc/adt/quickfixes/fix1":
+			proposalBody = string(body)
+			w.Write([]byte(testQuickFixDeltas))
+		case r.URL.Path == "/sap/bc/adt/programs/programs/zfoo" && r.URL.Query().Get("_action") == "LOCK":
+			w.Write([]byte(`<asx:abap xmlns:asx="http://www.sap.com/abapxml"><asx:values><DATA><LOCK_HANDLE>LH1</LOCK_HANDLE></DATA></asx:values></asx:abap>`))
+		case r.URL.Path == "/sap/bc/adt/programs/programs/zfoo":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/sap/bc/adt/activation":
+			activated = true
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"))
+	ctx := context.Background()
+	location := "/sap/bc/adt/programs/programs/zfoo/source/main#start=2,5"
+
+	fixes, err := client.GetATCQuickFixes(ctx, location)
+	if err != nil {
+		t.Fatalf("GetATCQuickFixes failed: %v", err)
+	}
+	if evaluationQuery != location {
+		t.Errorf("evaluation uri = %q", evaluationQuery)
+	}
+	if len(fixes) != 1 || fixes[0].Name != "Delete unused variable" || fixes[0].UserContent != "LV_UNUSED" {
+		t.Fatalf("unexpected fixes: %+v", fixes)
+	}
+
+	result, err := client.ApplyATCQuickFix(ctx, location, fixes[0], &ATCQuickFixOptions{})
+	if err != nil {
+		t.Fatalf("ApplyATCQuickFix failed: %v", err)
+	}
+	if !result.Success || !activated {
+		t.Fatalf("quick fix not applied: %+v", result)
+	}
+	if !strings.Contains(proposalBody, "<userContent>LV_UNUSED</userContent>") {
+		t.Errorf("user content not sent back: %s", proposalBody)
+	}
+	want := "REPORT zfoo.\nSELECT mandt FROM t000 INTO TABLE @DATA(lt).\n"
+	if updatedSource != want {
+		t.Errorf("updated source = %q, want %q", updatedSource, want)
+	}
+	if !strings.Contains(result.Preview.Diff, "-DATA lv_unused TYPE i.") || !strings.Contains(result.Preview.Diff, "+SELECT mandt FROM") {
+		t.Errorf("unexpected diff:\n%s", result.Preview.Diff)
+	}
+
+	readOnly := NewClient(server.URL, "testuser", "testpass", WithReadOnly())
+	if _, err := readOnly.ApplyATCQuickFix(ctx, location, fixes[0], nil); err == nil {
+		t.Error("expected read-only client to reject ApplyATCQuickFix")
+	}
+}
+
+func TestRequestATCExemption(t *testing.T) {
+	proposal := `<?xml version="1.0" encoding="utf-8"?>
+<atcexmpt:exemptionProposal xmlns:atcexmpt="http://www.sap.com/adt/atc/exemption" xmlns:atcfinding="http://www.sap.com/adt/atc/finding" xmlns:adtcore="http://www.sap.com/adt/core">
+  <atcfinding:finding adtcore:uri="/sap/bc/adt/atc/findings/itemid/1" checkId="CL_CI_TEST_SELECT" quickfixInfo="MARKER1"/>
+  <atcexmpt:package>$ZPKG</atcexmpt:package>
+  <atcexmpt:subObject>ZFOO</atcexmpt:subObject>
+  <atcexmpt:approver>LEAD</atcexmpt:approver>
+  <atcexmpt:reason/>
+  <atcexmpt:notify>on_rejection</atcexmpt:notify>
+</atcexmpt:exemptionProposal>`
+
+	var applyBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/sap/bc/adt/atc/exemptions/apply" && r.Method == http.MethodGet:
+			if r.URL.Query().Get("markerId") != "MARKER1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(proposal))
+		case r.URL.Path == "/sap/bc/adt/atc/exemptions/apply":
+			applyBody = string(body)
+			w.Write([]byte(`<atcexmpt:status xmlns:atcexmpt="http://www.sap.com/adt/atc/exemption" type="S" message="Exemption requested"/>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"))
+	result, err := client.RequestATCExemption(context.Background(), "MARKER1", ATCExemptionRequest{
+		Reason:        "FPOS",
+		Justification: "Buffered table <100 rows",
+	})
+	if err != nil {
+		t.Fatalf("RequestATCExemption failed: %v", err)
+	}
+	if result.Message != "Exemption requested" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	for _, want := range []string{
+		`quickfixInfo="MARKER1"`,
+		"<atcexmpt:package>$ZPKG</atcexmpt:package>",
+		"<atcexmpt:approver>LEAD</atcexmpt:approver>",
+		"<atcexmpt:reason>FPOS</atcexmpt:reason>",
+		"<atcexmpt:justification>Buffered table &lt;100 rows</atcexmpt:justification>",
+	} {
+		if !strings.Contains(applyBody, want) {
+			t.Errorf("exemption request missing %q:\n%s", want, applyBody)
+		}
+	}
+
+	readOnly := NewClient(server.URL, "testuser", "testpass", WithReadOnly())
+	if _, err := readOnly.RequestATCExemption(context.Background(), "MARKER1", ATCExemptionRequest{Reason: "FPOS"}); err == nil {
+		t.Error("expected read-only client to reject RequestATCExemption")
+	}
+}
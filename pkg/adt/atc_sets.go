@@ -0,0 +1,296 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// --- ATC Object Sets ---
+//
+// ATC runs over many objects: the objects of a package including its
+// subpackages, the objects of a transport request, or any list of object
+// URIs (e.g. a search result). The objects are checked in batches and the
+// worklists are merged into one, grouped by object.
+
+// atcBatchSize is the maximum number of objects per ATC run.
+const atcBatchSize = 100
+
+// ATCSetOptions configures an ATC run over an object set.
+type ATCSetOptions struct {
+	Variant    string // Check variant (empty = system default)
+	MaxResults int    // Maximum findings per batch (default 1000)
+}
+
+// ATCObjectSummary counts the findings of one object by priority.
+type ATCObjectSummary struct {
+	URI      string `json:"uri"`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Package  string `json:"package,omitempty"`
+	Errors   int    `json:"errors"`
+	Warnings int    `json:"warnings"`
+	Infos    int    `json:"infos"`
+}
+
+// ATCSummary counts the findings of a worklist by priority.
+type ATCSummary struct {
+	CheckedObjects int                `json:"checkedObjects,omitempty"`
+	TotalObjects   int                `json:"totalObjects"` // Objects with findings
+	TotalFindings  int                `json:"totalFindings"`
+	Errors         int                `json:"errors"`
+	Warnings       int                `json:"warnings"`
+	Infos          int                `json:"infos"`
+	Skipped        []string           `json:"skipped,omitempty"` // Objects that cannot be checked
+	Objects        []ATCObjectSummary `json:"objects,omitempty"`
+}
+
+// ATCSetResult is the merged result of an ATC run over an object set.
+type ATCSetResult struct {
+	Summary   ATCSummary   `json:"summary"`
+	Worklist  *ATCWorklist `json:"worklist"`
+	Truncated bool         `json:"truncated,omitempty"` // A batch reached MaxResults, findings may be missing
+}
+
+// Summary counts the findings of the worklist by priority, overall and per
+// object. Objects are ordered by errors, then warnings, then name.
+func (w *ATCWorklist) Summary() ATCSummary {
+	sum := ATCSummary{TotalObjects: len(w.Objects)}
+	for _, obj := range w.Objects {
+		objSum := ATCObjectSummary{URI: obj.URI, Type: obj.Type, Name: obj.Name, Package: obj.PackageName}
+		for _, f := range obj.Findings {
+			switch f.Priority {
+			case 1:
+				objSum.Errors++
+			case 2:
+				objSum.Warnings++
+			default:
+				objSum.Infos++
+			}
+		}
+		sum.Errors += objSum.Errors
+		sum.Warnings += objSum.Warnings
+		sum.Infos += objSum.Infos
+		sum.TotalFindings += len(obj.Findings)
+		sum.Objects = append(sum.Objects, objSum)
+	}
+	sort.SliceStable(sum.Objects, func(i, j int) bool {
+		a, b := sum.Objects[i], sum.Objects[j]
+		if a.Errors != b.Errors {
+			return a.Errors > b.Errors
+		}
+		if a.Warnings != b.Warnings {
+			return a.Warnings > b.Warnings
+		}
+		return a.Name < b.Name
+	})
+	return sum
+}
+
+// RunATCCheckObjects runs ATC over a set of object URIs and merges the
+// worklists of all batches into one.
+func (c *Client) RunATCCheckObjects(ctx context.Context, objectURLs []string, opts ATCSetOptions) (*ATCSetResult, error) {
+	if opts.MaxResults <= 0 {
+		opts.MaxResults = 1000
+	}
+
+	merged := &ATCWorklist{Objects: []ATCObject{}}
+	truncated := false
+	seen := make(map[string]bool)
+	var unique []string
+	for _, u := range objectURLs {
+		if u != "" && !seen[u] {
+			seen[u] = true
+			unique = append(unique, u)
+		}
+	}
+
+	for start := 0; start < len(unique); start += atcBatchSize {
+		end := start + atcBatchSize
+		if end > len(unique) {
+			end = len(unique)
+		}
+
+		worklistID, err := c.GetATCCheckVariant(ctx, opts.Variant)
+		if err != nil {
+			return nil, fmt.Errorf("getting check variant: %w", err)
+		}
+		run, err := c.CreateATCRunForObjects(ctx, worklistID, unique[start:end], opts.MaxResults)
+		if err != nil {
+			return nil, err
+		}
+		worklist, err := c.GetATCWorklist(ctx, run.WorklistID, false)
+		if err != nil {
+			return nil, fmt.Errorf("getting ATC worklist: %w", err)
+		}
+		truncated = truncated || ATCWorklistTruncated(worklist, opts.MaxResults)
+		mergeATCWorklist(merged, worklist)
+	}
+
+	result := &ATCSetResult{Summary: merged.Summary(), Worklist: merged, Truncated: truncated}
+	result.Summary.CheckedObjects = len(unique)
+	return result, nil
+}
+
+// ATCWorklistTruncated reports whether the worklist of a run limited to
+// maxResults findings reached the limit, so that findings may be missing.
+func ATCWorklistTruncated(w *ATCWorklist, maxResults int) bool {
+	if maxResults <= 0 {
+		return false
+	}
+	n := 0
+	for _, obj := range w.Objects {
+		n += len(obj.Findings)
+	}
+	return n >= maxResults
+}
+
+// mergeATCWorklist adds the objects of w to merged, combining the findings
+// of objects that occur in both.
+func mergeATCWorklist(merged, w *ATCWorklist) {
+	if merged.ID == "" {
+		merged.ID = w.ID
+		merged.Timestamp = w.Timestamp
+		merged.UsedObjectSet = w.UsedObjectSet
+	}
+	merged.ObjectSets = append(merged.ObjectSets, w.ObjectSets...)
+	for _, obj := range w.Objects {
+		found := false
+		for i := range merged.Objects {
+			if merged.Objects[i].URI == obj.URI {
+				merged.Objects[i].Findings = append(merged.Objects[i].Findings, obj.Findings...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged.Objects = append(merged.Objects, obj)
+		}
+	}
+}
+
+// RunATCCheckPackage runs ATC over all objects of a package and its
+// subpackages.
+func (c *Client) RunATCCheckPackage(ctx context.Context, packageName string, opts ATCSetOptions) (*ATCSetResult, error) {
+	urls, err := c.packageATCObjects(ctx, strings.ToUpper(packageName))
+	if err != nil {
+		return nil, err
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("package %s contains no objects", packageName)
+	}
+	return c.RunATCCheckObjects(ctx, urls, opts)
+}
+
+func (c *Client) packageATCObjects(ctx context.Context, packageName string) ([]string, error) {
+	packages, err := c.collectSubpackages(ctx, packageName)
+	if err != nil {
+		return nil, fmt.Errorf("reading package %s: %w", packageName, err)
+	}
+
+	var urls []string
+	for _, pkg := range packages {
+		content, err := c.GetPackage(ctx, pkg)
+		if err != nil {
+			return nil, fmt.Errorf("reading package %s: %w", pkg, err)
+		}
+		for _, obj := range content.Objects {
+			if obj.URI != "" {
+				urls = append(urls, obj.URI)
+			}
+		}
+	}
+	return urls, nil
+}
+
+// RunATCCheckTransport runs ATC over all objects of a transport request and
+// its tasks. Transport entries without an ADT equivalent (e.g. table
+// contents) are listed in Summary.Skipped.
+func (c *Client) RunATCCheckTransport(ctx context.Context, transport string, opts ATCSetOptions) (*ATCSetResult, error) {
+	details, err := c.GetTransport(ctx, transport)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := append([]TransportObjectV2{}, details.Objects...)
+	for _, task := range details.Tasks {
+		objects = append(objects, task.Objects...)
+	}
+
+	var urls, skipped []string
+	for _, obj := range objects {
+		if u := TransportObjectURL(obj); u != "" {
+			urls = append(urls, u)
+		} else {
+			skipped = append(skipped, strings.TrimSpace(obj.PgmID+" "+obj.Type+" "+obj.Name))
+		}
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("transport %s contains no checkable objects", transport)
+	}
+
+	result, err := c.RunATCCheckObjects(ctx, urls, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.Summary.Skipped = dedupeStrings(skipped)
+	return result, nil
+}
+
+// TransportObjectURL returns the ADT URL of the object behind a transport
+// entry, or "" if it has none. Sub-object entries (LIMU) are mapped to their
+// main object, e.g. a method (LIMU METH) to its class.
+func TransportObjectURL(obj TransportObjectV2) string {
+	name := strings.TrimSpace(obj.Name)
+	if name == "" {
+		return ""
+	}
+
+	switch obj.PgmID + " " + obj.Type {
+	case "R3TR CLAS", "LIMU CLSD", "LIMU CPUB", "LIMU CPRO", "LIMU CPRI", "LIMU CINC", "LIMU METH":
+		// METH entries are "CLASSNAME<padding>METHOD"; class pool includes "CLASS====...CM001"
+		if fields := strings.Fields(name); len(fields) > 0 {
+			name = fields[0]
+		}
+		if i := strings.Index(name, "="); i > 0 {
+			name = name[:i]
+		}
+		return GetObjectURL(ObjectTypeClass, name, "")
+	case "R3TR INTF", "LIMU INTD":
+		return GetObjectURL(ObjectTypeInterface, name, "")
+	case "R3TR PROG":
+		return GetObjectURL(ObjectTypeProgram, name, "")
+	case "LIMU REPS":
+		if i := strings.Index(name, "="); i > 0 {
+			return GetObjectURL(ObjectTypeClass, name[:i], "")
+		}
+		return GetObjectURL(ObjectTypeInclude, name, "")
+	case "R3TR FUGR":
+		return GetObjectURL(ObjectTypeFunctionGroup, name, "")
+	case "R3TR DDLS":
+		return GetObjectURL(ObjectTypeDDLS, name, "")
+	case "R3TR BDEF":
+		return GetObjectURL(ObjectTypeBDEF, name, "")
+	case "R3TR SRVD":
+		return GetObjectURL(ObjectTypeSRVD, name, "")
+	case "R3TR SRVB":
+		return GetObjectURL(ObjectTypeSRVB, name, "")
+	case "R3TR TABL":
+		return "/sap/bc/adt/ddic/tables/" + url.PathEscape(strings.ToLower(name))
+	}
+	return ""
+}
+
+func dedupeStrings(values []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,135 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testATCSetWorklist = `<?xml version="1.0" encoding="utf-8"?>
+<atcworklist:worklist xmlns:atcworklist="http://www.sap.com/adt/atc/worklist"
+                      xmlns:atcobject="http://www.sap.com/adt/atc/object"
+                      xmlns:atcfinding="http://www.sap.com/adt/atc/finding"
+                      id="%s" timestamp="2025-12-04T10:30:00Z" usedObjectSet="LAST_RUN" objectSetIsComplete="true">
+  <atcworklist:objects>%s
+  </atcworklist:objects>
+</atcworklist:worklist>`
+
+func testATCSetObject(name string, priorities ...int) string {
+	var findings strings.Builder
+	for i, p := range priorities {
+		fmt.Fprintf(&findings, `
+        <atcfinding:finding location="/sap/bc/adt/oo/classes/%s/source/main#start=%d,1" priority="%d" checkId="CL_CI_TEST" messageTitle="Finding %d"/>`,
+			strings.ToLower(name), 10+i, p, i)
+	}
+	return fmt.Sprintf(`
+    <atcobject:object uri="/sap/bc/adt/oo/classes/%s" type="CLAS/OC" name="%s" packageName="$ZPKG">
+      <atcfinding:findings>%s
+      </atcfinding:findings>
+    </atcobject:object>`, strings.ToLower(name), name, findings.String())
+}
+
+func TestRunATCCheckObjects_Batches(t *testing.T) {
+	var runBodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+		case "/sap/bc/adt/atc/worklists":
+			fmt.Fprintf(w, "WL%d", len(runBodies)+1)
+		case "/sap/bc/adt/atc/runs":
+			runBodies = append(runBodies, string(body))
+			fmt.Fprintf(w, `<atcworklist:worklistRun xmlns:atcworklist="http://www.sap.com/adt/atc/worklist"><atcworklist:worklistId>%s</atcworklist:worklistId></atcworklist:worklistRun>`,
+				r.URL.Query().Get("worklistId"))
+		case "/sap/bc/adt/atc/worklists/WL1":
+			fmt.Fprintf(w, testATCSetWorklist, "WL1", testATCSetObject("ZCL_A", 2)+testATCSetObject("ZCL_B", 3))
+		case "/sap/bc/adt/atc/worklists/WL2":
+			fmt.Fprintf(w, testATCSetWorklist, "WL2", testATCSetObject("ZCL_A", 1)+testATCSetObject("ZCL_C", 1, 1, 2))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"))
+
+	var urls []string
+	for i := 0; i < 150; i++ {
+		urls = append(urls, fmt.Sprintf("/sap/bc/adt/oo/classes/zcl_%03d", i))
+	}
+	urls = append(urls, urls[0]) // duplicates are checked once
+
+	result, err := client.RunATCCheckObjects(context.Background(), urls, ATCSetOptions{Variant: "DEFAULT"})
+	if err != nil {
+		t.Fatalf("RunATCCheckObjects failed: %v", err)
+	}
+	if len(runBodies) != 2 {
+		t.Fatalf("expected 2 batches, got %d", len(runBodies))
+	}
+	if n := strings.Count(runBodies[0], "<adtcore:objectReference "); n != atcBatchSize {
+		t.Errorf("first batch has %d objects", n)
+	}
+	if n := strings.Count(runBodies[1], "<adtcore:objectReference "); n != 50 {
+		t.Errorf("second batch has %d objects", n)
+	}
+
+	sum := result.Summary
+	if sum.CheckedObjects != 150 || sum.TotalObjects != 3 || sum.TotalFindings != 6 {
+		t.Errorf("unexpected summary: %+v", sum)
+	}
+	if sum.Errors != 3 || sum.Warnings != 2 || sum.Infos != 1 {
+		t.Errorf("unexpected priority counts: %+v", sum)
+	}
+	if len(sum.Objects) != 3 || sum.Objects[0].Name != "ZCL_C" || sum.Objects[1].Name != "ZCL_A" {
+		t.Fatalf("unexpected object order: %+v", sum.Objects)
+	}
+	if a := sum.Objects[1]; a.Errors != 1 || a.Warnings != 1 || a.Package != "$ZPKG" {
+		t.Errorf("findings of ZCL_A were not merged: %+v", a)
+	}
+	if result.Worklist.ID != "WL1" || len(result.Worklist.Objects) != 3 {
+		t.Errorf("unexpected merged worklist: %s, %d objects", result.Worklist.ID, len(result.Worklist.Objects))
+	}
+	if result.Truncated {
+		t.Error("result reported as truncated")
+	}
+
+	// The second batch returns as many findings as allowed
+	runBodies = nil
+	result, err = client.RunATCCheckObjects(context.Background(), urls, ATCSetOptions{Variant: "DEFAULT", MaxResults: 4})
+	if err != nil {
+		t.Fatalf("RunATCCheckObjects failed: %v", err)
+	}
+	if !result.Truncated {
+		t.Error("result that reached max results not reported as truncated")
+	}
+}
+
+func TestTransportObjectURL(t *testing.T) {
+	tests := []struct {
+		pgmID, typ, name string
+		want             string
+	}{
+		{"R3TR", "CLAS", "ZCL_FOO", "/sap/bc/adt/oo/classes/ZCL_FOO"},
+		{"LIMU", "METH", "ZCL_FOO                       DO_IT", "/sap/bc/adt/oo/classes/ZCL_FOO"},
+		{"LIMU", "CINC", "ZCL_FOO=======================CCAU", "/sap/bc/adt/oo/classes/ZCL_FOO"},
+		{"LIMU", "REPS", "ZCL_FOO=======================CM001", "/sap/bc/adt/oo/classes/ZCL_FOO"},
+		{"LIMU", "REPS", "ZFOO_TOP", "/sap/bc/adt/programs/includes/ZFOO_TOP"},
+		{"R3TR", "INTF", "ZIF_FOO", "/sap/bc/adt/oo/interfaces/ZIF_FOO"},
+		{"R3TR", "PROG", "ZFOO", "/sap/bc/adt/programs/programs/ZFOO"},
+		{"R3TR", "TABL", "ZFOO_T", "/sap/bc/adt/ddic/tables/zfoo_t"},
+		{"R3TR", "TABU", "ZFOO_T", ""},
+		{"CORR", "RELE", "A4HK900123", ""},
+	}
+	for _, tt := range tests {
+		got := TransportObjectURL(TransportObjectV2{PgmID: tt.pgmID, Type: tt.typ, Name: tt.name})
+		if !strings.EqualFold(got, tt.want) {
+			t.Errorf("TransportObjectURL(%s %s %s) = %q, want %q", tt.pgmID, tt.typ, tt.name, got, tt.want)
+		}
+	}
+}
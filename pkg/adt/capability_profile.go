@@ -0,0 +1,104 @@
+package adt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// --- Capability Profile ---
+//
+// The results of the feature probes are kept per system in a local profile,
+// so that a server start does not probe again while the profile is fresh.
+
+// DefaultCapabilityProfileDir is the default directory of the capability profiles.
+const DefaultCapabilityProfileDir = ".vsp-capabilities"
+
+// DefaultCapabilityProfileTTL is how long a probed feature status is reused.
+const DefaultCapabilityProfileTTL = 24 * time.Hour
+
+// CapabilityProfile is the cached feature status of one system.
+type CapabilityProfile struct {
+	System   string                       `json:"system"` // Base URL and client
+	ProbedAt time.Time                    `json:"probed_at"`
+	Features map[FeatureID]*FeatureStatus `json:"features"`
+}
+
+// CapabilityProfileStore keeps one capability profile per system as JSON files.
+type CapabilityProfileStore struct {
+	Dir string
+	TTL time.Duration
+}
+
+// NewCapabilityProfileStore creates a store in dir (DefaultCapabilityProfileDir
+// if empty) whose entries expire after ttl (DefaultCapabilityProfileTTL if 0).
+func NewCapabilityProfileStore(dir string, ttl time.Duration) *CapabilityProfileStore {
+	if dir == "" {
+		dir = DefaultCapabilityProfileDir
+	}
+	if ttl == 0 {
+		ttl = DefaultCapabilityProfileTTL
+	}
+	return &CapabilityProfileStore{Dir: dir, TTL: ttl}
+}
+
+var capabilityFileNameRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Path returns the profile file of a system.
+func (s *CapabilityProfileStore) Path(system string) string {
+	name := strings.ToLower(system)
+	if i := strings.Index(name, "://"); i >= 0 {
+		name = name[i+3:]
+	}
+	name = strings.Trim(capabilityFileNameRegex.ReplaceAllString(name, "_"), "_")
+	return filepath.Join(s.Dir, name+".json")
+}
+
+// Load returns the profile of a system, or nil if there is none.
+func (s *CapabilityProfileStore) Load(system string) (*CapabilityProfile, error) {
+	data, err := os.ReadFile(s.Path(system))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading capability profile: %w", err)
+	}
+	var profile CapabilityProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("parsing capability profile of %s: %w", system, err)
+	}
+	if profile.Features == nil {
+		profile.Features = make(map[FeatureID]*FeatureStatus)
+	}
+	return &profile, nil
+}
+
+// Save writes the profile of a system.
+func (s *CapabilityProfileStore) Save(profile *CapabilityProfile) error {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return fmt.Errorf("creating capability profile directory: %w", err)
+	}
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding capability profile: %w", err)
+	}
+	if err := os.WriteFile(s.Path(profile.System), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing capability profile: %w", err)
+	}
+	return nil
+}
+
+// Fresh reports whether a probed status is younger than the TTL of the store.
+func (s *CapabilityProfileStore) Fresh(status *FeatureStatus) bool {
+	return status != nil && time.Since(status.ProbedAt) < s.TTL
+}
+
+// capabilitySystem identifies the system of a client in capability profiles.
+func capabilitySystem(cfg *Config) string {
+	return fmt.Sprintf("%s?sap-client=%s", strings.TrimRight(cfg.BaseURL, "/"), cfg.Client)
+}
@@ -0,0 +1,445 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Metadata extension layers, lowest priority first. A higher layer
+// overrides the annotations of lower layers and of the entity itself.
+var cdsLayerPriority = map[string]int{
+	"CORE":         1,
+	"LOCALIZATION": 2,
+	"INDUSTRY":     3,
+	"PARTNER":      4,
+	"CUSTOMER":     5,
+}
+
+// CDSMetadataExtension is a parsed metadata extension (DDLX).
+type CDSMetadataExtension struct {
+	Name        string                     `json:"name"`
+	Entity      string                     `json:"entity"`
+	Layer       string                     `json:"layer"`
+	Annotations []CDSAnnotation            `json:"annotations,omitempty"` // entity level
+	Elements    map[string][]CDSAnnotation `json:"elements,omitempty"`    // by upper-case element name
+}
+
+// CDSElementAnnotations are the active annotations of one element.
+type CDSElementAnnotations struct {
+	Name        string          `json:"name"`
+	Annotations []CDSAnnotation `json:"annotations"`
+}
+
+// CDSActiveAnnotations are the annotations in effect for a CDS entity after
+// merging its metadata extensions by layer.
+type CDSActiveAnnotations struct {
+	Entity      string                  `json:"entity"`
+	Extensions  []string                `json:"extensions,omitempty"` // "NAME (LAYER)", lowest layer first
+	Annotations []CDSAnnotation         `json:"annotations"`
+	Elements    []CDSElementAnnotations `json:"elements"`
+}
+
+// GetDDLX retrieves the source of a metadata extension.
+func (c *Client) GetDDLX(ctx context.Context, ddlxName string) (string, error) {
+	ddlxName = strings.ToUpper(ddlxName)
+	sourcePath := fmt.Sprintf("/sap/bc/adt/ddic/ddlx/sources/%s/source/main", url.PathEscape(ddlxName))
+	resp, err := c.transport.Request(ctx, sourcePath, &RequestOptions{
+		Method: http.MethodGet,
+		Accept: "text/plain",
+	})
+	if err != nil {
+		return "", fmt.Errorf("getting DDLX source: %w", err)
+	}
+	return string(resp.Body), nil
+}
+
+// FindMetadataExtensions returns the names of the metadata extensions that
+// annotate a CDS entity (where-used list of its DDL source).
+func (c *Client) FindMetadataExtensions(ctx context.Context, entity string) ([]string, error) {
+	refs, err := c.FindReferences(ctx, "/sap/bc/adt/ddic/ddl/sources/"+url.PathEscape(strings.ToLower(entity)), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		name := strings.ToUpper(ref.Name)
+		if strings.HasPrefix(ref.Type, "DDLX") && name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// GetCDSActiveAnnotations merges the annotations resolved by ADT for an
+// entity with its metadata extensions. extensions names the DDLX objects to
+// merge; when empty they are found via where-used.
+func (c *Client) GetCDSActiveAnnotations(ctx context.Context, entity string, extensions []string) (*CDSActiveAnnotations, error) {
+	info, err := c.GetCDSElementInfo(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+	if len(extensions) == 0 {
+		if extensions, err = c.FindMetadataExtensions(ctx, entity); err != nil {
+			return nil, fmt.Errorf("finding metadata extensions: %w", err)
+		}
+	}
+
+	var parsed []*CDSMetadataExtension
+	for _, name := range extensions {
+		source, err := c.GetDDLX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		ext, err := ParseCDSMetadataExtension(source)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", strings.ToUpper(name), err)
+		}
+		ext.Name = strings.ToUpper(name)
+		parsed = append(parsed, ext)
+	}
+	return MergeCDSAnnotations(info, parsed), nil
+}
+
+// MergeCDSAnnotations applies metadata extensions to the annotations of an
+// entity. Extensions are applied from the lowest layer to the highest; an
+// annotation replaces the annotation of the same name from lower layers,
+// array annotations (UI.LINEITEM$n$...) are replaced as a whole.
+func MergeCDSAnnotations(info *CDSElementInfo, extensions []*CDSMetadataExtension) *CDSActiveAnnotations {
+	exts := append([]*CDSMetadataExtension{}, extensions...)
+	sort.SliceStable(exts, func(i, j int) bool {
+		return cdsLayerPriority[exts[i].Layer] < cdsLayerPriority[exts[j].Layer]
+	})
+
+	result := &CDSActiveAnnotations{Entity: info.Name}
+	for _, ext := range exts {
+		result.Extensions = append(result.Extensions, ext.Name+" ("+ext.Layer+")")
+	}
+
+	source := "DDLS " + info.Name
+	entity := withCDSSource(info.Annotations, source)
+	for _, ext := range exts {
+		entity = overrideCDSAnnotations(entity, withCDSSource(ext.Annotations, "DDLX "+ext.Name))
+	}
+	result.Annotations = entity
+
+	var names []string
+	elements := make(map[string][]CDSAnnotation)
+	add := func(name string, annotations []CDSAnnotation) {
+		key := strings.ToUpper(name)
+		if _, ok := elements[key]; !ok {
+			names = append(names, name)
+		}
+		elements[key] = withCDSSource(annotations, source)
+	}
+	for _, e := range info.Parameters {
+		add(e.Name, e.Annotations)
+	}
+	for _, e := range info.Elements {
+		add(e.Name, e.Annotations)
+	}
+	for _, a := range info.Associations {
+		add(a.Name, a.Annotations)
+	}
+	for _, ext := range exts {
+		extNames := make([]string, 0, len(ext.Elements))
+		for name := range ext.Elements {
+			extNames = append(extNames, name)
+		}
+		sort.Strings(extNames)
+		for _, name := range extNames {
+			if _, ok := elements[name]; !ok {
+				names = append(names, name)
+			}
+			elements[name] = overrideCDSAnnotations(elements[name], withCDSSource(ext.Elements[name], "DDLX "+ext.Name))
+		}
+	}
+
+	result.Annotations = nonNilAnnotations(result.Annotations)
+	result.Elements = make([]CDSElementAnnotations, 0, len(names))
+	for _, name := range names {
+		result.Elements = append(result.Elements, CDSElementAnnotations{
+			Name:        name,
+			Annotations: nonNilAnnotations(elements[strings.ToUpper(name)]),
+		})
+	}
+	return result
+}
+
+func withCDSSource(annotations []CDSAnnotation, source string) []CDSAnnotation {
+	out := make([]CDSAnnotation, len(annotations))
+	for i, a := range annotations {
+		if a.Source == "" {
+			a.Source = source
+		}
+		out[i] = a
+	}
+	return out
+}
+
+func nonNilAnnotations(annotations []CDSAnnotation) []CDSAnnotation {
+	if annotations == nil {
+		return []CDSAnnotation{}
+	}
+	return annotations
+}
+
+// cdsAnnotationGroup is the unit of overriding: the key up to the first
+// array index, so a layer replaces a whole array.
+func cdsAnnotationGroup(key string) string {
+	if i := strings.Index(key, "$"); i > 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// overrideCDSAnnotations drops the annotations of base that upper defines
+// and returns the result sorted by key.
+func overrideCDSAnnotations(base, upper []CDSAnnotation) []CDSAnnotation {
+	groups := make(map[string]bool)
+	for _, a := range upper {
+		groups[cdsAnnotationGroup(a.Key)] = true
+	}
+	var out []CDSAnnotation
+	for _, a := range base {
+		if !groups[cdsAnnotationGroup(a.Key)] {
+			out = append(out, a)
+		}
+	}
+	out = append(out, upper...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// ParseCDSMetadataExtension parses the source of a metadata extension into
+// flattened entity and element annotations.
+//
+//	@Metadata.layer: #CUSTOMER
+//	@UI.headerInfo.typeName: 'Travel'
+//	annotate entity ZC_TRAVEL with
+//	{
+//	  @UI.lineItem: [{ position: 10 }]
+//	  TravelID;
+//	}
+func ParseCDSMetadataExtension(source string) (*CDSMetadataExtension, error) {
+	p := &cdsAnnotationParser{tokens: tokenizeCDS(source)}
+	ext := &CDSMetadataExtension{Layer: "CORE", Elements: make(map[string][]CDSAnnotation)}
+
+	// Header: entity annotations up to "annotate entity|view NAME with {"
+	var pending []CDSAnnotation
+	for tok := p.next(); !strings.EqualFold(tok, "annotate"); tok = p.next() {
+		switch tok {
+		case "":
+			return nil, fmt.Errorf("missing annotate statement")
+		case "@":
+			anns, err := p.annotation()
+			if err != nil {
+				return nil, err
+			}
+			pending = append(pending, anns...)
+		default:
+			return nil, fmt.Errorf("unexpected %q before annotate statement", tok)
+		}
+	}
+	if kind := p.next(); !strings.EqualFold(kind, "entity") && !strings.EqualFold(kind, "view") {
+		return nil, fmt.Errorf("expected 'annotate entity', got %q", kind)
+	}
+	ext.Entity = strings.ToUpper(p.next())
+	if !strings.EqualFold(p.next(), "with") || p.next() != "{" {
+		return nil, fmt.Errorf("expected 'with {' after annotate entity %s", ext.Entity)
+	}
+	for _, a := range pending {
+		if a.Key == "METADATA.LAYER" {
+			ext.Layer = strings.ToUpper(strings.TrimPrefix(a.Value, "#"))
+			continue
+		}
+		ext.Annotations = append(ext.Annotations, a)
+	}
+
+	// Body: annotations followed by the element they annotate
+	pending = nil
+	element := ""
+	for {
+		tok := p.next()
+		switch {
+		case tok == "":
+			return nil, fmt.Errorf("missing closing brace")
+		case tok == "}":
+			return ext, nil
+		case tok == "@" || tok == "@<":
+			anns, err := p.annotation()
+			if err != nil {
+				return nil, err
+			}
+			pending = append(pending, anns...)
+		case tok == ";":
+			if element == "" {
+				return nil, fmt.Errorf("annotations without element")
+			}
+			key := strings.ToUpper(element)
+			ext.Elements[key] = append(ext.Elements[key], pending...)
+			pending, element = nil, ""
+		case strings.EqualFold(tok, "$parameters") && p.peek() == ".":
+			p.next()
+			element = p.next()
+		default:
+			element = tok
+		}
+	}
+}
+
+type cdsAnnotationParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *cdsAnnotationParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	p.pos++
+	return p.tokens[p.pos-1]
+}
+
+func (p *cdsAnnotationParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// path reads a dotted annotation name.
+func (p *cdsAnnotationParser) path() string {
+	name := p.next()
+	for p.peek() == "." {
+		p.next()
+		name += "." + p.next()
+	}
+	return strings.ToUpper(name)
+}
+
+// annotation reads "name[: value]" after "@"; a missing value means true.
+func (p *cdsAnnotationParser) annotation() ([]CDSAnnotation, error) {
+	key := p.path()
+	if key == "" {
+		return nil, fmt.Errorf("missing annotation name")
+	}
+	if p.peek() != ":" {
+		return []CDSAnnotation{{Key: key, Value: "true"}}, nil
+	}
+	p.next()
+	var out []CDSAnnotation
+	err := p.value(key, &out)
+	return out, err
+}
+
+// value reads a scalar, structure or array and flattens it under key.
+func (p *cdsAnnotationParser) value(key string, out *[]CDSAnnotation) error {
+	switch tok := p.next(); tok {
+	case "":
+		return fmt.Errorf("missing value for %s", key)
+	case "{":
+		for p.peek() != "}" {
+			sub := p.path()
+			if sub == "" {
+				return fmt.Errorf("unterminated structure in %s", key)
+			}
+			if p.peek() == ":" {
+				p.next()
+				if err := p.value(key+"."+sub, out); err != nil {
+					return err
+				}
+			} else {
+				*out = append(*out, CDSAnnotation{Key: key + "." + sub, Value: "true"})
+			}
+			if p.peek() == "," {
+				p.next()
+			}
+		}
+		p.next()
+	case "[":
+		for i := 1; p.peek() != "]"; i++ {
+			if p.peek() == "" {
+				return fmt.Errorf("unterminated array in %s", key)
+			}
+			if err := p.value(key+"$"+strconv.Itoa(i)+"$", out); err != nil {
+				return err
+			}
+			if p.peek() == "," {
+				p.next()
+			}
+		}
+		p.next()
+	case "#":
+		*out = append(*out, CDSAnnotation{Key: key, Value: "#" + strings.ToUpper(p.next())})
+	case "-":
+		*out = append(*out, CDSAnnotation{Key: key, Value: "-" + p.next()})
+	default:
+		*out = append(*out, CDSAnnotation{Key: key, Value: tok})
+	}
+	return nil
+}
+
+// tokenizeCDS splits CDS source into names, string literals (quotes kept)
+// and punctuation; comments are dropped.
+func tokenizeCDS(src string) []string {
+	var tokens []string
+	r := []rune(src)
+	for i := 0; i < len(r); {
+		ch := r[i]
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+		case ch == '/' && i+1 < len(r) && r[i+1] == '/', ch == '-' && i+1 < len(r) && r[i+1] == '-':
+			for i < len(r) && r[i] != '\n' {
+				i++
+			}
+		case ch == '/' && i+1 < len(r) && r[i+1] == '*':
+			i += 2
+			for i < len(r) && !(r[i] == '*' && i+1 < len(r) && r[i+1] == '/') {
+				i++
+			}
+			i += 2
+		case ch == '\'':
+			j := i + 1
+			for j < len(r) {
+				if r[j] == '\'' {
+					if j+1 < len(r) && r[j+1] == '\'' {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			if j < len(r) {
+				j++
+			}
+			tokens = append(tokens, string(r[i:j]))
+			i = j
+		case ch == '@' && i+1 < len(r) && r[i+1] == '<':
+			tokens = append(tokens, "@<")
+			i += 2
+		case unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '_' || ch == '$' || ch == '/':
+			j := i
+			for j < len(r) && (unicode.IsLetter(r[j]) || unicode.IsDigit(r[j]) || r[j] == '_' || r[j] == '$' || r[j] == '/' ||
+				(r[j] == '.' && unicode.IsDigit(r[i]) && j+1 < len(r) && unicode.IsDigit(r[j+1]))) {
+				j++
+			}
+			tokens = append(tokens, string(r[i:j]))
+			i = j
+		default:
+			tokens = append(tokens, string(ch))
+			i++
+		}
+	}
+	return tokens
+}
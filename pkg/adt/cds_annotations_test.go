@@ -0,0 +1,177 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testDDLXCustomer = `@Metadata.layer: #CUSTOMER
+@UI: { headerInfo: { typeName: 'Trip', typeNamePlural: 'Trips' } }
+annotate entity ZI_TRAVEL with
+{
+  /* line items */
+  @UI.lineItem: [ { position: 20, importance: #HIGH },
+                  { type: #FOR_ACTION, dataAction: 'accept', label: 'Accept' } ]
+  @UI.selectionField: [{ position: 10 }]
+  TravelID;
+  @UI.hidden
+  @EndUserText.label: 'Don''t show' // comment
+  TotalPrice;
+  @Consumption.filter.defaultValue: '-1.5'
+  Status;
+}`
+
+const testDDLXPartner = `@Metadata.layer: #PARTNER
+annotate view ZI_TRAVEL with
+{
+  @UI.lineItem: [{ position: 99 }]
+  @UI.identification: [{ position: 1 }]
+  TravelID;
+}`
+
+func TestParseCDSMetadataExtension(t *testing.T) {
+	ext, err := ParseCDSMetadataExtension(testDDLXCustomer)
+	if err != nil {
+		t.Fatalf("ParseCDSMetadataExtension failed: %v", err)
+	}
+	if ext.Entity != "ZI_TRAVEL" || ext.Layer != "CUSTOMER" {
+		t.Errorf("entity = %s, layer = %s", ext.Entity, ext.Layer)
+	}
+	if got := formatTestAnnotations(ext.Annotations); got != "UI.HEADERINFO.TYPENAME='Trip' UI.HEADERINFO.TYPENAMEPLURAL='Trips'" {
+		t.Errorf("entity annotations = %s", got)
+	}
+	want := map[string]string{
+		"TRAVELID": "UI.LINEITEM$1$.POSITION=20 UI.LINEITEM$1$.IMPORTANCE=#HIGH UI.LINEITEM$2$.TYPE=#FOR_ACTION " +
+			"UI.LINEITEM$2$.DATAACTION='accept' UI.LINEITEM$2$.LABEL='Accept' UI.SELECTIONFIELD$1$.POSITION=10",
+		"TOTALPRICE": "UI.HIDDEN=true ENDUSERTEXT.LABEL='Don''t show'",
+		"STATUS":     "CONSUMPTION.FILTER.DEFAULTVALUE='-1.5'",
+	}
+	if len(ext.Elements) != len(want) {
+		t.Errorf("elements = %v", ext.Elements)
+	}
+	for name, w := range want {
+		if got := formatTestAnnotations(ext.Elements[name]); got != w {
+			t.Errorf("%s = %s\nwant %s", name, got, w)
+		}
+	}
+
+	for _, bad := range []string{"", "annotate entity X with { A;", "@UI.hidden X", "annotate entity X { A; }"} {
+		if _, err := ParseCDSMetadataExtension(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestMergeCDSAnnotations(t *testing.T) {
+	customer, _ := ParseCDSMetadataExtension(testDDLXCustomer)
+	customer.Name = "ZI_TRAVEL_CUST"
+	partner, _ := ParseCDSMetadataExtension(testDDLXPartner)
+	partner.Name = "ZI_TRAVEL_PART"
+
+	info := &CDSElementInfo{
+		Name: "ZI_TRAVEL",
+		Annotations: []CDSAnnotation{
+			{Key: "ENDUSERTEXT.LABEL", Value: "'Travel'"},
+			{Key: "UI.HEADERINFO.TYPENAME", Value: "'Travel'"},
+		},
+		Elements: []CDSElement{
+			{Name: "TravelID", Annotations: []CDSAnnotation{
+				{Key: "UI.LINEITEM$1$.POSITION", Value: "10"},
+				{Key: "UI.LINEITEM$1$.LABEL", Value: "'ID'"},
+				{Key: "SEARCH.DEFAULTSEARCHELEMENT", Value: "true"},
+			}},
+			{Name: "TotalPrice"},
+		},
+		Associations: []CDSAssociation{{Name: "_Booking"}},
+	}
+
+	// Customer wins over partner regardless of the order given
+	active := MergeCDSAnnotations(info, []*CDSMetadataExtension{customer, partner})
+	if strings.Join(active.Extensions, ",") != "ZI_TRAVEL_PART (PARTNER),ZI_TRAVEL_CUST (CUSTOMER)" {
+		t.Errorf("extensions = %v", active.Extensions)
+	}
+	if got := formatTestAnnotations(active.Annotations); got != "ENDUSERTEXT.LABEL='Travel' UI.HEADERINFO.TYPENAME='Trip' UI.HEADERINFO.TYPENAMEPLURAL='Trips'" {
+		t.Errorf("entity annotations = %s", got)
+	}
+	if active.Annotations[1].Source != "DDLX ZI_TRAVEL_CUST" || active.Annotations[0].Source != "DDLS ZI_TRAVEL" {
+		t.Errorf("sources = %+v", active.Annotations)
+	}
+
+	var names []string
+	for _, e := range active.Elements {
+		names = append(names, e.Name)
+	}
+	if strings.Join(names, ",") != "TravelID,TotalPrice,_Booking,STATUS" {
+		t.Errorf("elements = %v", names)
+	}
+
+	travelID := active.Elements[0].Annotations
+	got := formatTestAnnotations(travelID)
+	for _, w := range []string{"SEARCH.DEFAULTSEARCHELEMENT=true", "UI.IDENTIFICATION$1$.POSITION=1", "UI.LINEITEM$1$.POSITION=20", "UI.LINEITEM$2$.LABEL='Accept'"} {
+		if !strings.Contains(got, w) {
+			t.Errorf("TravelID lacks %s: %s", w, got)
+		}
+	}
+	// Arrays are replaced as a whole: neither the entity's label nor the partner's position survive
+	if strings.Contains(got, "'ID'") || strings.Contains(got, "=99") {
+		t.Errorf("TravelID kept overridden line item: %s", got)
+	}
+	if len(active.Elements[2].Annotations) != 0 {
+		t.Errorf("_Booking = %+v", active.Elements[2].Annotations)
+	}
+}
+
+func TestGetCDSActiveAnnotations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+		case "/sap/bc/adt/ddic/ddl/elementinfo":
+			fmt.Fprint(w, testCDSElementInfo)
+		case "/sap/bc/adt/repository/informationsystem/usageReferences":
+			if r.URL.Query().Get("uri") != "/sap/bc/adt/ddic/ddl/sources/zi_travel" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `<usageReferences:usageReferenceResult xmlns:usageReferences="http://www.sap.com/adt/ris/usageReferences" xmlns:adtcore="http://www.sap.com/adt/core">
+  <usageReferences:referencedObjects>
+    <usageReferences:referencedObject uri="/sap/bc/adt/ddic/ddlx/sources/zi_travel_cust" isResult="true">
+      <usageReferences:adtObject adtcore:name="ZI_TRAVEL_CUST" adtcore:type="DDLX/EX"/>
+    </usageReferences:referencedObject>
+    <usageReferences:referencedObject uri="/sap/bc/adt/ddic/ddl/sources/zc_travel" isResult="true">
+      <usageReferences:adtObject adtcore:name="ZC_TRAVEL" adtcore:type="DDLS/DF"/>
+    </usageReferences:referencedObject>
+  </usageReferences:referencedObjects>
+</usageReferences:usageReferenceResult>`)
+		case "/sap/bc/adt/ddic/ddlx/sources/ZI_TRAVEL_CUST/source/main":
+			fmt.Fprint(w, testDDLXCustomer)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass")
+	active, err := client.GetCDSActiveAnnotations(context.Background(), "ZI_TRAVEL", nil)
+	if err != nil {
+		t.Fatalf("GetCDSActiveAnnotations failed: %v", err)
+	}
+	if strings.Join(active.Extensions, ",") != "ZI_TRAVEL_CUST (CUSTOMER)" {
+		t.Errorf("extensions = %v", active.Extensions)
+	}
+	if got := formatTestAnnotations(active.Annotations); !strings.Contains(got, "UI.HEADERINFO.TYPENAME='Trip'") {
+		t.Errorf("entity annotations = %s", got)
+	}
+}
+
+func formatTestAnnotations(annotations []CDSAnnotation) string {
+	parts := make([]string, len(annotations))
+	for i, a := range annotations {
+		parts[i] = a.Key + "=" + a.Value
+	}
+	return strings.Join(parts, " ")
+}
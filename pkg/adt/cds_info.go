@@ -0,0 +1,285 @@
+package adt
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CDSAnnotation is an annotation in the flattened form ADT uses: upper-case
+// key, array entries indexed as $n$ ("UI.LINEITEM$1$.POSITION").
+type CDSAnnotation struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source,omitempty"` // entity or metadata extension that defines it
+}
+
+// CDSElement is a field or parameter of a CDS entity.
+type CDSElement struct {
+	Name        string            `json:"name"`
+	Type        string            `json:"type,omitempty"` // ADT object type of the element
+	IsKey       bool              `json:"isKey,omitempty"`
+	DataElement string            `json:"dataElement,omitempty"`
+	DataType    string            `json:"dataType,omitempty"`
+	Length      int               `json:"length,omitempty"`
+	Decimals    int               `json:"decimals,omitempty"`
+	Label       string            `json:"label,omitempty"`
+	Annotations []CDSAnnotation   `json:"annotations,omitempty"`
+	Properties  map[string]string `json:"properties,omitempty"`
+}
+
+// CDSAssociation is an association (or composition) exposed by a CDS entity.
+type CDSAssociation struct {
+	Name        string            `json:"name"`
+	Target      string            `json:"target,omitempty"`
+	Cardinality string            `json:"cardinality,omitempty"`
+	Annotations []CDSAnnotation   `json:"annotations,omitempty"`
+	Properties  map[string]string `json:"properties,omitempty"`
+}
+
+// CDSElementInfo is the element info of a CDS entity as resolved by ADT:
+// fields with their DDIC types, associations and annotations (including
+// those inherited from data elements and underlying entities).
+type CDSElementInfo struct {
+	Name         string            `json:"name"`
+	Type         string            `json:"type,omitempty"`
+	Annotations  []CDSAnnotation   `json:"annotations,omitempty"`
+	Parameters   []CDSElement      `json:"parameters,omitempty"`
+	Elements     []CDSElement      `json:"elements"`
+	Associations []CDSAssociation  `json:"associations,omitempty"`
+	Properties   map[string]string `json:"properties,omitempty"`
+}
+
+// GetCDSElementInfo returns fields, types, associations and annotations of
+// a CDS entity. Association targets are resolved by ADT.
+func (c *Client) GetCDSElementInfo(ctx context.Context, entity string) (*CDSElementInfo, error) {
+	if err := c.checkSafety(OpRead, "GetCDSElementInfo"); err != nil {
+		return nil, err
+	}
+	if entity == "" {
+		return nil, fmt.Errorf("entity is required")
+	}
+
+	params := url.Values{}
+	params.Set("path", strings.ToUpper(entity))
+	params.Set("getTargetForAssociation", "true")
+	params.Set("getExtensionViews", "true")
+	params.Set("getSecondaryObjects", "true")
+
+	resp, err := c.transport.Request(ctx, "/sap/bc/adt/ddic/ddl/elementinfo", &RequestOptions{
+		Method: http.MethodGet,
+		Query:  params,
+		Accept: "application/vnd.sap.adt.elementinfo+xml",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting CDS element info: %w", err)
+	}
+	return parseCDSElementInfo(resp.Body)
+}
+
+// cdsElementInfoXML is an abapsource:elementInfo node; elements are nested
+// elementInfo nodes, association targets are nested inside the association.
+type cdsElementInfoXML struct {
+	Name    string `xml:"name,attr"`
+	Type    string `xml:"type,attr"`
+	Entries []struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"properties>entry"`
+	Children []cdsElementInfoXML `xml:"elementInfo"`
+}
+
+// split returns the plain properties and the annotations of a node.
+// Annotation entries carry the annotation key prefixed with "@".
+func (n *cdsElementInfoXML) split() (map[string]string, []CDSAnnotation) {
+	props := make(map[string]string)
+	var annotations []CDSAnnotation
+	for _, e := range n.Entries {
+		if key, ok := strings.CutPrefix(e.Key, "@"); ok {
+			annotations = append(annotations, CDSAnnotation{Key: strings.ToUpper(key), Value: strings.TrimSpace(e.Value)})
+			continue
+		}
+		props[e.Key] = strings.TrimSpace(e.Value)
+	}
+	return props, annotations
+}
+
+func parseCDSElementInfo(data []byte) (*CDSElementInfo, error) {
+	var root cdsElementInfoXML
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing CDS element info: %w", err)
+	}
+
+	info := &CDSElementInfo{Name: root.Name, Type: root.Type, Elements: []CDSElement{}}
+	info.Properties, info.Annotations = root.split()
+	if len(info.Properties) == 0 {
+		info.Properties = nil
+	}
+
+	for _, child := range root.Children {
+		props, annotations := child.split()
+		if isCDSAssociation(&child, props) {
+			assoc := CDSAssociation{
+				Name:        child.Name,
+				Target:      props["ddicTargetEntity"],
+				Cardinality: props["ddicCardinality"],
+				Annotations: annotations,
+				Properties:  props,
+			}
+			if assoc.Target == "" && len(child.Children) > 0 {
+				assoc.Target = child.Children[0].Name
+			}
+			info.Associations = append(info.Associations, assoc)
+			continue
+		}
+
+		elem := CDSElement{
+			Name:        child.Name,
+			Type:        child.Type,
+			IsKey:       props["ddicIsKey"] == "true",
+			DataElement: props["ddicDataElement"],
+			DataType:    props["ddicDataType"],
+			Label:       props["ddicLabelMedium"],
+			Annotations: annotations,
+			Properties:  props,
+		}
+		elem.Length, _ = strconv.Atoi(strings.TrimLeft(props["ddicLength"], "0"))
+		elem.Decimals, _ = strconv.Atoi(strings.TrimLeft(props["ddicDecimals"], "0"))
+		if elem.Label == "" {
+			elem.Label = props["ddicLabelLong"]
+		}
+		if props["ddicIsParameter"] == "true" {
+			info.Parameters = append(info.Parameters, elem)
+			continue
+		}
+		info.Elements = append(info.Elements, elem)
+	}
+	return info, nil
+}
+
+// isCDSAssociation tells associations from fields: ADT resolves the target
+// of an association as a nested element or a target property.
+func isCDSAssociation(n *cdsElementInfoXML, props map[string]string) bool {
+	return props["ddicTargetEntity"] != "" || props["ddicIsAssociation"] == "true" || len(n.Children) > 0
+}
+
+// FindElement returns the element (field or parameter) with the given
+// name, ignoring case.
+func (info *CDSElementInfo) FindElement(name string) *CDSElement {
+	for _, list := range [][]CDSElement{info.Elements, info.Parameters} {
+		for i := range list {
+			if strings.EqualFold(list[i].Name, name) {
+				return &list[i]
+			}
+		}
+	}
+	return nil
+}
+
+// CDSPreviewOptions configures a CDS data preview.
+type CDSPreviewOptions struct {
+	Parameters  map[string]string // entity parameters: name -> value
+	Association string            // association path to follow, e.g. "_Booking" or "_Booking\_Customer"
+	Fields      []string          // columns to select (default: all)
+	Where       string            // ABAP SQL condition on the previewed rows
+	OrderBy     string            // ABAP SQL ORDER BY list
+	MaxRows     int               // default 100
+}
+
+var (
+	cdsEntityName  = regexp.MustCompile(`^(/[A-Za-z0-9_]+/)?[A-Za-z0-9_]+$`)
+	cdsElementName = regexp.MustCompile(`^(/[A-Za-z0-9_]+/)?_?[A-Za-z0-9_]+$`)
+)
+
+// BuildCDSPreviewQuery builds the ABAP SQL statement of a CDS preview:
+// parameters are passed to the entity as literals, an association path is
+// followed as a path expression.
+func BuildCDSPreviewQuery(entity string, opts CDSPreviewOptions) (string, error) {
+	if !cdsEntityName.MatchString(entity) {
+		return "", fmt.Errorf("invalid CDS entity name %q", entity)
+	}
+	source := strings.ToUpper(entity)
+
+	if len(opts.Parameters) > 0 {
+		names := make([]string, 0, len(opts.Parameters))
+		for name := range opts.Parameters {
+			if !cdsElementName.MatchString(name) {
+				return "", fmt.Errorf("invalid parameter name %q", name)
+			}
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool { return strings.ToUpper(names[i]) < strings.ToUpper(names[j]) })
+		args := make([]string, len(names))
+		for i, name := range names {
+			value := "'" + strings.ReplaceAll(opts.Parameters[name], "'", "''") + "'"
+			args[i] = strings.ToUpper(name) + " = " + value
+		}
+		source += "( " + strings.Join(args, ", ") + " )"
+	}
+
+	if path := strings.TrimSpace(opts.Association); path != "" {
+		segments := strings.FieldsFunc(path, func(r rune) bool { return r == '\\' || r == '.' })
+		alias := ""
+		for _, seg := range segments {
+			if !cdsElementName.MatchString(seg) {
+				return "", fmt.Errorf("invalid association %q", seg)
+			}
+			source += `\` + seg
+			alias = strings.TrimLeft(seg, "_")
+		}
+		if alias == "" || strings.HasPrefix(alias, "/") {
+			alias = "target"
+		}
+		source += " AS " + alias
+	}
+
+	fields := "*"
+	if len(opts.Fields) > 0 {
+		fields = strings.Join(opts.Fields, ", ")
+	}
+	query := "SELECT " + fields + " FROM " + source
+	if opts.Where != "" {
+		query += " WHERE " + opts.Where
+	}
+	if opts.OrderBy != "" {
+		query += " ORDER BY " + opts.OrderBy
+	}
+	return query, nil
+}
+
+// PreviewCDS reads the data of a CDS entity, optionally with parameters,
+// a filter and an association to follow.
+func (c *Client) PreviewCDS(ctx context.Context, entity string, opts CDSPreviewOptions) (*TableContentsResult, error) {
+	if err := c.checkSafety(OpQuery, "PreviewCDS"); err != nil {
+		return nil, err
+	}
+	query, err := BuildCDSPreviewQuery(entity, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxRows <= 0 {
+		opts.MaxRows = 100
+	}
+
+	params := url.Values{}
+	params.Set("rowNumber", strconv.Itoa(opts.MaxRows))
+	params.Set("ddicEntityName", strings.ToUpper(entity))
+
+	resp, err := c.transport.Request(ctx, "/sap/bc/adt/datapreview/ddic", &RequestOptions{
+		Method:      http.MethodPost,
+		Query:       params,
+		Accept:      "application/*",
+		Body:        []byte(query),
+		ContentType: "text/plain",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CDS data preview of %s: %w", strings.ToUpper(entity), err)
+	}
+	return parseTableContents(resp.Body)
+}
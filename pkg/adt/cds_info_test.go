@@ -0,0 +1,164 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testCDSElementInfo = `<abapsource:elementInfo xmlns:abapsource="http://www.sap.com/adt/abapsource" xmlns:adtcore="http://www.sap.com/adt/core" adtcore:name="ZI_TRAVEL" adtcore:type="DDLS/DF">
+  <abapsource:properties>
+    <abapsource:entry abapsource:key="@ENDUSERTEXT.LABEL">'Travel'</abapsource:entry>
+    <abapsource:entry abapsource:key="@UI.HEADERINFO.TYPENAME">'Travel'</abapsource:entry>
+  </abapsource:properties>
+  <abapsource:elementInfo adtcore:name="P_KEYDATE" adtcore:type="DDLS/PA">
+    <abapsource:properties>
+      <abapsource:entry abapsource:key="ddicIsParameter">true</abapsource:entry>
+      <abapsource:entry abapsource:key="ddicDataType">DATS</abapsource:entry>
+    </abapsource:properties>
+  </abapsource:elementInfo>
+  <abapsource:elementInfo adtcore:name="TravelID" adtcore:type="DDLS/EL">
+    <abapsource:properties>
+      <abapsource:entry abapsource:key="ddicIsKey">true</abapsource:entry>
+      <abapsource:entry abapsource:key="ddicDataElement">/DMO/TRAVEL_ID</abapsource:entry>
+      <abapsource:entry abapsource:key="ddicDataType">NUMC</abapsource:entry>
+      <abapsource:entry abapsource:key="ddicLength">000008</abapsource:entry>
+      <abapsource:entry abapsource:key="ddicLabelMedium">Travel ID</abapsource:entry>
+      <abapsource:entry abapsource:key="@UI.LINEITEM$1$.POSITION">10</abapsource:entry>
+    </abapsource:properties>
+  </abapsource:elementInfo>
+  <abapsource:elementInfo adtcore:name="TotalPrice" adtcore:type="DDLS/EL">
+    <abapsource:properties>
+      <abapsource:entry abapsource:key="ddicDataType">CURR</abapsource:entry>
+      <abapsource:entry abapsource:key="ddicLength">16</abapsource:entry>
+      <abapsource:entry abapsource:key="ddicDecimals">2</abapsource:entry>
+    </abapsource:properties>
+  </abapsource:elementInfo>
+  <abapsource:elementInfo adtcore:name="_Booking" adtcore:type="DDLS/AS">
+    <abapsource:properties>
+      <abapsource:entry abapsource:key="ddicCardinality">[0..*]</abapsource:entry>
+    </abapsource:properties>
+    <abapsource:elementInfo adtcore:name="ZI_BOOKING" adtcore:type="DDLS/DF"/>
+  </abapsource:elementInfo>
+</abapsource:elementInfo>`
+
+func TestGetCDSElementInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+		case "/sap/bc/adt/ddic/ddl/elementinfo":
+			if r.URL.Query().Get("path") != "ZI_TRAVEL" || r.URL.Query().Get("getTargetForAssociation") != "true" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, testCDSElementInfo)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass")
+	info, err := client.GetCDSElementInfo(context.Background(), "zi_travel")
+	if err != nil {
+		t.Fatalf("GetCDSElementInfo failed: %v", err)
+	}
+	if info.Name != "ZI_TRAVEL" || len(info.Annotations) != 2 || info.Annotations[0].Key != "ENDUSERTEXT.LABEL" {
+		t.Errorf("unexpected entity: %+v", info)
+	}
+	if len(info.Parameters) != 1 || info.Parameters[0].Name != "P_KEYDATE" {
+		t.Errorf("parameters = %+v", info.Parameters)
+	}
+	if len(info.Elements) != 2 {
+		t.Fatalf("elements = %+v", info.Elements)
+	}
+	id := info.FindElement("travelid")
+	if id == nil || !id.IsKey || id.DataElement != "/DMO/TRAVEL_ID" || id.Length != 8 || id.Label != "Travel ID" ||
+		len(id.Annotations) != 1 || id.Annotations[0].Key != "UI.LINEITEM$1$.POSITION" {
+		t.Errorf("TravelID = %+v", id)
+	}
+	if price := info.Elements[1]; price.Length != 16 || price.Decimals != 2 || price.IsKey {
+		t.Errorf("TotalPrice = %+v", price)
+	}
+	if len(info.Associations) != 1 || info.Associations[0].Target != "ZI_BOOKING" || info.Associations[0].Cardinality != "[0..*]" {
+		t.Errorf("associations = %+v", info.Associations)
+	}
+}
+
+func TestBuildCDSPreviewQuery(t *testing.T) {
+	tests := []struct {
+		entity string
+		opts   CDSPreviewOptions
+		want   string
+	}{
+		{"zi_travel", CDSPreviewOptions{}, "SELECT * FROM ZI_TRAVEL"},
+		{"ZI_TRAVEL", CDSPreviewOptions{
+			Parameters: map[string]string{"p_keydate": "20240101", "P_LANG": "E'X", "P_DAYS": "30"},
+			Fields:     []string{"TravelID", "Status"},
+			Where:      "Status = 'O'",
+			OrderBy:    "TravelID",
+		}, "SELECT TravelID, Status FROM ZI_TRAVEL( P_DAYS = '30', P_KEYDATE = '20240101', P_LANG = 'E''X' ) WHERE Status = 'O' ORDER BY TravelID"},
+		{"ZI_TRAVEL", CDSPreviewOptions{Association: `_Booking\_Customer`}, `SELECT * FROM ZI_TRAVEL\_Booking\_Customer AS Customer`},
+		{"/DMO/I_TRAVEL_U", CDSPreviewOptions{Association: "_Agency"}, `SELECT * FROM /DMO/I_TRAVEL_U\_Agency AS Agency`},
+	}
+	for _, tt := range tests {
+		got, err := BuildCDSPreviewQuery(tt.entity, tt.opts)
+		if err != nil || got != tt.want {
+			t.Errorf("BuildCDSPreviewQuery(%s) = %q, %v\nwant %q", tt.entity, got, err, tt.want)
+		}
+	}
+
+	for _, opts := range []CDSPreviewOptions{
+		{Parameters: map[string]string{"P X": "1"}},
+		{Association: "_Booking; DELETE"},
+	} {
+		if _, err := BuildCDSPreviewQuery("ZI_TRAVEL", opts); err == nil {
+			t.Errorf("expected error for %+v", opts)
+		}
+	}
+	if _, err := BuildCDSPreviewQuery("ZI_TRAVEL WHERE", CDSPreviewOptions{}); err == nil {
+		t.Error("expected error for invalid entity")
+	}
+}
+
+func TestPreviewCDS(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+		case "/sap/bc/adt/datapreview/ddic":
+			body, _ := io.ReadAll(r.Body)
+			query = string(body)
+			if r.URL.Query().Get("ddicEntityName") != "ZI_TRAVEL" || r.URL.Query().Get("rowNumber") != "5" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, testTableData([]string{"BOOKINGID"}, [][]string{{"0001"}, {"0002"}}))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass")
+	result, err := client.PreviewCDS(context.Background(), "ZI_TRAVEL", CDSPreviewOptions{Association: "_Booking", MaxRows: 5})
+	if err != nil {
+		t.Fatalf("PreviewCDS failed: %v", err)
+	}
+	if len(result.Rows) != 2 || result.Rows[1]["BOOKINGID"] != "0002" {
+		t.Errorf("rows = %v", result.Rows)
+	}
+	if query != `SELECT * FROM ZI_TRAVEL\_Booking AS Booking` {
+		t.Errorf("query = %q", query)
+	}
+
+	client = NewClient(server.URL, "testuser", "testpass", WithSafety(SafetyConfig{AllowedOps: "R"}))
+	if _, err := client.PreviewCDS(context.Background(), "ZI_TRAVEL", CDSPreviewOptions{}); err == nil {
+		t.Error("expected safety error when queries are not allowed")
+	}
+}
@@ -0,0 +1,549 @@
+package adt
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// --- CI Reports ---
+//
+// Unit test results are rendered as JUnit XML. ATC findings, syntax check
+// messages and failed unit tests are converted to Findings, which render as
+// SARIF 2.1.0, GitHub workflow annotations or GitLab code quality JSON. All
+// reports use abapGit file paths (see AbapGitFileName) below a source folder,
+// so CI systems can link them to the files in the repository.
+
+// Finding severities (SARIF levels).
+const (
+	FindingError   = "error"
+	FindingWarning = "warning"
+	FindingNote    = "note"
+)
+
+// Finding is a check result located in an abapGit source file.
+type Finding struct {
+	Tool       string `json:"tool"`   // ATC, Syntax Check, ABAP Unit
+	RuleID     string `json:"ruleId"` // Check or message ID
+	RuleTitle  string `json:"ruleTitle,omitempty"`
+	Message    string `json:"message"`
+	Severity   string `json:"severity"` // error, warning, note
+	Object     string `json:"object,omitempty"`
+	URI        string `json:"uri,omitempty"`  // ADT location
+	File       string `json:"file,omitempty"` // abapGit file name
+	Line       int    `json:"line,omitempty"`
+	Column     int    `json:"column,omitempty"` // 0-based, as returned by ADT
+	Suppressed bool   `json:"suppressed,omitempty"`
+}
+
+// Path returns the file path of the finding below srcDir, or "" if the
+// finding cannot be mapped to a file.
+func (f Finding) Path(srcDir string) string {
+	if f.File == "" {
+		return ""
+	}
+	return path.Join(srcDir, f.File)
+}
+
+func (f Finding) fingerprint(srcDir string) string {
+	sum := sha1.Sum([]byte(strings.Join([]string{f.Tool, f.RuleID, f.Path(srcDir), f.Object, f.Message}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Findings converts the ATC worklist into findings. Priority 1 is an error,
+// priority 2 a warning and everything else a note. Exempted findings are
+// returned as suppressed.
+func (w *ATCWorklist) Findings() []Finding {
+	var findings []Finding
+	for _, obj := range w.Objects {
+		for _, af := range obj.Findings {
+			f := Finding{
+				Tool:       "ATC",
+				RuleID:     af.CheckID,
+				RuleTitle:  af.CheckTitle,
+				Message:    af.MessageTitle,
+				Severity:   FindingNote,
+				Object:     obj.Name,
+				URI:        af.Location,
+				Line:       af.Line,
+				Column:     af.Column,
+				Suppressed: af.ExemptionKind != "",
+			}
+			if af.MessageID != "" {
+				f.RuleID = af.CheckID + "/" + af.MessageID
+			}
+			switch af.Priority {
+			case 1:
+				f.Severity = FindingError
+			case 2:
+				f.Severity = FindingWarning
+			}
+			if f.URI == "" {
+				f.URI = obj.URI
+			}
+			if f.File = AbapGitFileName(f.URI); f.File == "" {
+				f.File = AbapGitFileName(obj.URI)
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// SyntaxFindings converts syntax check messages into findings.
+func SyntaxFindings(results []SyntaxCheckResult) []Finding {
+	var findings []Finding
+	for _, r := range results {
+		f := Finding{
+			Tool:     "Syntax Check",
+			RuleID:   "SYNTAX",
+			Message:  r.Text,
+			Severity: FindingNote,
+			URI:      r.URI,
+			File:     AbapGitFileName(r.URI),
+			Line:     r.Line,
+			Column:   r.Offset,
+		}
+		switch r.Severity {
+		case "E", "A", "X":
+			f.Severity = FindingError
+		case "W":
+			f.Severity = FindingWarning
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// Findings converts failed test methods into findings, located at the
+// innermost stack entry of the first alert (usually the failed assertion).
+func (r *UnitTestResult) Findings() []Finding {
+	var findings []Finding
+	for _, class := range r.Classes {
+		for _, method := range class.TestMethods {
+			if len(method.Alerts) == 0 {
+				continue
+			}
+			alert := method.Alerts[0]
+			f := Finding{
+				Tool:     "ABAP Unit",
+				RuleID:   alert.Kind,
+				Message:  fmt.Sprintf("%s->%s: %s", class.Name, method.Name, alert.Title),
+				Severity: FindingError,
+				Object:   class.Name,
+				URI:      method.URI,
+			}
+			if alert.Kind == "warning" {
+				f.Severity = FindingWarning
+			}
+			if uri, line := alertLocation(alert); line > 0 {
+				f.URI, f.Line = uri, line
+			}
+			if f.File = AbapGitFileName(f.URI); f.File == "" {
+				f.File = AbapGitFileName(class.URI)
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// alertLocation returns the first stack entry of an alert with a line.
+func alertLocation(alert UnitTestAlert) (string, int) {
+	for _, entry := range alert.Stack {
+		if line, _ := parseSourceRange(entry.URI); line > 0 {
+			return entry.URI, line
+		}
+	}
+	return "", 0
+}
+
+// --- JUnit ---
+
+// UnitTestRun is the unit test result of one tested object, the input of
+// the JUnit report. Error is set if the run itself failed.
+type UnitTestRun struct {
+	Object string
+	Result *UnitTestResult
+	Error  string
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      float64         `xml:"time,attr"`
+	File      string          `xml:"file,attr,omitempty"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// JUnit renders unit test runs as JUnit XML. Each test class becomes a test
+// suite named OBJECT.TESTCLASS; exceptions are reported as errors, all other
+// alerts as failures. srcDir is prepended to the abapGit file names.
+func JUnit(name string, runs []UnitTestRun, srcDir string) ([]byte, error) {
+	doc := junitTestSuites{Name: name}
+
+	for _, run := range runs {
+		if run.Error != "" {
+			doc.Suites = append(doc.Suites, junitTestSuite{
+				Name:   run.Object,
+				Tests:  1,
+				Errors: 1,
+				TestCases: []junitTestCase{{
+					Name:      "run",
+					ClassName: run.Object,
+					Error:     &junitFailure{Message: run.Error, Type: "runError"},
+				}},
+			})
+		}
+		if run.Result == nil {
+			continue
+		}
+		for _, class := range run.Result.Classes {
+			suite := junitTestSuite{Name: run.Object + "." + class.Name}
+			if file := AbapGitFileName(class.URI); file != "" {
+				suite.File = path.Join(srcDir, file)
+			}
+			for _, method := range class.TestMethods {
+				tc := junitTestCase{
+					Name:      method.Name,
+					ClassName: suite.Name,
+					Time:      method.ExecutionTime,
+					File:      suite.File,
+				}
+				if len(method.Alerts) > 0 {
+					alert := method.Alerts[0]
+					failure := &junitFailure{Message: alert.Title, Type: alert.Kind, Text: alertText(method.Alerts)}
+					if alert.Kind == "exception" {
+						tc.Error = failure
+						suite.Errors++
+					} else {
+						tc.Failure = failure
+						suite.Failures++
+					}
+					if uri, line := alertLocation(alert); line > 0 {
+						tc.Line = line
+						if file := AbapGitFileName(uri); file != "" {
+							tc.File = path.Join(srcDir, file)
+						}
+					}
+				}
+				suite.Tests++
+				suite.Time += method.ExecutionTime
+				suite.TestCases = append(suite.TestCases, tc)
+			}
+			// Class-level alerts (e.g. failing setup) without methods
+			if len(class.TestMethods) == 0 && len(class.Alerts) > 0 {
+				suite.Tests++
+				suite.Errors++
+				suite.TestCases = append(suite.TestCases, junitTestCase{
+					Name:      class.Name,
+					ClassName: suite.Name,
+					File:      suite.File,
+					Error:     &junitFailure{Message: class.Alerts[0].Title, Type: class.Alerts[0].Kind, Text: alertText(class.Alerts)},
+				})
+			}
+			doc.Suites = append(doc.Suites, suite)
+		}
+	}
+
+	for _, s := range doc.Suites {
+		doc.Tests += s.Tests
+		doc.Failures += s.Failures
+		doc.Errors += s.Errors
+		doc.Time += s.Time
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("rendering junit report: %w", err)
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// alertText renders alert titles, details and stacks as failure text.
+func alertText(alerts []UnitTestAlert) string {
+	var b strings.Builder
+	for _, a := range alerts {
+		fmt.Fprintf(&b, "[%s] %s\n", a.Kind, a.Title)
+		for _, d := range a.Details {
+			fmt.Fprintf(&b, "  %s\n", d)
+		}
+		for _, s := range a.Stack {
+			fmt.Fprintf(&b, "  at %s\n", s.Description)
+		}
+	}
+	return b.String()
+}
+
+// --- SARIF ---
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region *sarifRegion `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+}
+
+type sarifSuppression struct {
+	Kind string `json:"kind"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations,omitempty"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints,omitempty"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name           string      `json:"name"`
+			InformationURI string      `json:"informationUri,omitempty"`
+			Rules          []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+// SARIF renders findings as a SARIF 2.1.0 log with one run per tool.
+// Findings that cannot be mapped to a file are located at their ADT URI.
+func SARIF(findings []Finding, srcDir string) ([]byte, error) {
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{},
+	}
+
+	runs := make(map[string]*sarifRun)
+	var tools []string
+	for _, f := range findings {
+		run, ok := runs[f.Tool]
+		if !ok {
+			run = &sarifRun{Results: []sarifResult{}}
+			run.Tool.Driver.Name = f.Tool
+			run.Tool.Driver.InformationURI = "https://github.com/oisee/vibing-steampunk"
+			run.Tool.Driver.Rules = []sarifRule{}
+			runs[f.Tool] = run
+			tools = append(tools, f.Tool)
+		}
+
+		known := false
+		for _, r := range run.Tool.Driver.Rules {
+			if r.ID == f.RuleID {
+				known = true
+				break
+			}
+		}
+		if !known {
+			rule := sarifRule{ID: f.RuleID}
+			if f.RuleTitle != "" {
+				rule.ShortDescription = &sarifMessage{Text: f.RuleTitle}
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+
+		result := sarifResult{
+			RuleID:              f.RuleID,
+			Level:               f.Severity,
+			Message:             sarifMessage{Text: f.Message},
+			PartialFingerprints: map[string]string{"vspFinding/v1": f.fingerprint(srcDir)},
+		}
+		var loc sarifLocation
+		loc.PhysicalLocation.ArtifactLocation.URI = f.Path(srcDir)
+		if loc.PhysicalLocation.ArtifactLocation.URI == "" {
+			loc.PhysicalLocation.ArtifactLocation.URI = f.URI
+		}
+		if f.Line > 0 {
+			loc.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line, StartColumn: f.Column + 1}
+		}
+		if loc.PhysicalLocation.ArtifactLocation.URI != "" {
+			result.Locations = []sarifLocation{loc}
+		}
+		if f.Suppressed {
+			result.Suppressions = []sarifSuppression{{Kind: "external"}}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	for _, tool := range tools {
+		log.Runs = append(log.Runs, *runs[tool])
+	}
+
+	out, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("rendering sarif report: %w", err)
+	}
+	return append(out, '\n'), nil
+}
+
+// --- GitHub / GitLab ---
+
+// GitHubAnnotations renders findings as GitHub Actions workflow commands
+// (::error file=...,line=...::message). Suppressed findings are skipped.
+func GitHubAnnotations(findings []Finding, srcDir string) []byte {
+	var buf bytes.Buffer
+	for _, f := range findings {
+		if f.Suppressed {
+			continue
+		}
+		command := "notice"
+		switch f.Severity {
+		case FindingError:
+			command = "error"
+		case FindingWarning:
+			command = "warning"
+		}
+
+		var props []string
+		if p := f.Path(srcDir); p != "" {
+			props = append(props, "file="+githubEscapeProperty(p))
+			if f.Line > 0 {
+				props = append(props, fmt.Sprintf("line=%d", f.Line), fmt.Sprintf("col=%d", f.Column+1))
+			}
+		}
+		title := f.Tool + " " + f.RuleID
+		if f.RuleTitle != "" {
+			title += ": " + f.RuleTitle
+		}
+		props = append(props, "title="+githubEscapeProperty(title))
+
+		message := f.Message
+		if f.File == "" && f.URI != "" {
+			message += " (" + f.URI + ")"
+		}
+		fmt.Fprintf(&buf, "::%s %s::%s\n", command, strings.Join(props, ","), githubEscapeData(message))
+	}
+	return buf.Bytes()
+}
+
+func githubEscapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func githubEscapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+type gitlabIssue struct {
+	Description string `json:"description"`
+	CheckName   string `json:"check_name"`
+	Fingerprint string `json:"fingerprint"`
+	Severity    string `json:"severity"`
+	Location    struct {
+		Path  string `json:"path"`
+		Lines struct {
+			Begin int `json:"begin"`
+		} `json:"lines"`
+	} `json:"location"`
+}
+
+// GitLabCodeQuality renders findings as a GitLab code quality report
+// (Code Climate JSON subset). Suppressed findings are skipped; findings
+// without a file are reported against their ADT URI.
+func GitLabCodeQuality(findings []Finding, srcDir string) ([]byte, error) {
+	issues := []gitlabIssue{}
+	for _, f := range findings {
+		if f.Suppressed {
+			continue
+		}
+		issue := gitlabIssue{
+			Description: f.Message,
+			CheckName:   f.RuleID,
+			Fingerprint: f.fingerprint(srcDir),
+			Severity:    "info",
+		}
+		switch f.Severity {
+		case FindingError:
+			issue.Severity = "major"
+		case FindingWarning:
+			issue.Severity = "minor"
+		}
+		if issue.Location.Path = f.Path(srcDir); issue.Location.Path == "" {
+			issue.Location.Path = f.URI
+		}
+		issue.Location.Lines.Begin = f.Line
+		if issue.Location.Lines.Begin == 0 {
+			issue.Location.Lines.Begin = 1
+		}
+		issues = append(issues, issue)
+	}
+
+	out, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("rendering code quality report: %w", err)
+	}
+	return append(out, '\n'), nil
+}
+
+// RenderFindings renders findings in the given format: sarif, github or
+// gitlab (alias codequality).
+func RenderFindings(format string, findings []Finding, srcDir string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "sarif":
+		return SARIF(findings, srcDir)
+	case "github":
+		return GitHubAnnotations(findings, srcDir), nil
+	case "gitlab", "codequality":
+		return GitLabCodeQuality(findings, srcDir)
+	default:
+		return nil, fmt.Errorf("unknown findings format %q (expected sarif, github or gitlab)", format)
+	}
+}
@@ -0,0 +1,211 @@
+package adt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testReportUnitResult() *UnitTestResult {
+	return &UnitTestResult{Classes: []UnitTestClass{{
+		URI:  "/sap/bc/adt/oo/classes/zcl_calc/includes/testclasses#type=CLAS%2FOL;name=LTCL_CALC",
+		Name: "LTCL_CALC",
+		TestMethods: []UnitTestMethod{
+			{Name: "ADD", ExecutionTime: 0.002},
+			{
+				Name:          "SUB",
+				URI:           "/sap/bc/adt/oo/classes/zcl_calc/includes/testclasses#type=CLAS%2FOLD;name=SUB",
+				ExecutionTime: 0.001,
+				Alerts: []UnitTestAlert{{
+					Kind:    "failedAssertion",
+					Title:   "Expected [2] but was [3]",
+					Details: []string{"Test 'LTCL_CALC->SUB' in Main Program 'ZCL_CALC'."},
+					Stack: []UnitTestStackEntry{{
+						URI:         "/sap/bc/adt/oo/classes/zcl_calc/includes/testclasses#start=21,0",
+						Description: "Include: <ZCL_CALC=======CCAU> Line: <21>",
+					}},
+				}},
+			},
+			{Name: "DIV", Alerts: []UnitTestAlert{{Kind: "exception", Title: "CX_SY_ZERODIVIDE"}}},
+		},
+	}}}
+}
+
+func TestJUnit(t *testing.T) {
+	runs := []UnitTestRun{
+		{Object: "ZCL_CALC", Result: testReportUnitResult()},
+		{Object: "ZCL_BROKEN", Error: "unable to determine object URL"},
+	}
+
+	data, err := JUnit("ABAP Unit", runs, "src")
+	if err != nil {
+		t.Fatalf("JUnit failed: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		`<testsuites name="ABAP Unit" tests="4" failures="1" errors="2"`,
+		`<testsuite name="ZCL_CALC.LTCL_CALC" tests="3" failures="1" errors="1"`,
+		`file="src/zcl_calc.clas.testclasses.abap"`,
+		`<testcase name="SUB" classname="ZCL_CALC.LTCL_CALC" time="0.001" file="src/zcl_calc.clas.testclasses.abap" line="21">`,
+		`<failure message="Expected [2] but was [3]" type="failedAssertion">`,
+		`<error message="CX_SY_ZERODIVIDE" type="exception">`,
+		`<error message="unable to determine object URL" type="runError">`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("JUnit missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func testReportFindings() []Finding {
+	worklist := &ATCWorklist{Objects: []ATCObject{{
+		URI:  "/sap/bc/adt/oo/classes/zcl_calc",
+		Name: "ZCL_CALC",
+		Findings: []ATCFinding{
+			{
+				Location:     "/sap/bc/adt/oo/classes/zcl_calc/source/main#start=12,4",
+				Priority:     1,
+				CheckID:      "CL_CI_TEST_SELECT",
+				CheckTitle:   "Search problematic statements",
+				MessageID:    "0001",
+				MessageTitle: "SELECT * used",
+				Line:         12,
+				Column:       4,
+			},
+			{Priority: 3, CheckID: "CL_CI_NAMING", MessageTitle: "Naming, with comma", ExemptionKind: "OBJECT"},
+		},
+	}}}
+
+	findings := worklist.Findings()
+	findings = append(findings, SyntaxFindings([]SyntaxCheckResult{
+		{URI: "/sap/bc/adt/programs/programs/ztest/source/main", Line: 3, Severity: "W", Text: "Variable unused"},
+	})...)
+	return append(findings, testReportUnitResult().Findings()...)
+}
+
+func TestFindings(t *testing.T) {
+	findings := testReportFindings()
+	if len(findings) != 5 {
+		t.Fatalf("got %d findings, want 5: %+v", len(findings), findings)
+	}
+
+	atc := findings[0]
+	if atc.RuleID != "CL_CI_TEST_SELECT/0001" || atc.Severity != FindingError || atc.Path("src") != "src/zcl_calc.clas.abap" || atc.Line != 12 {
+		t.Errorf("unexpected ATC finding: %+v", atc)
+	}
+	if exempt := findings[1]; !exempt.Suppressed || exempt.File != "zcl_calc.clas.abap" || exempt.Severity != FindingNote {
+		t.Errorf("unexpected exempted finding: %+v", exempt)
+	}
+	if syntax := findings[2]; syntax.Severity != FindingWarning || syntax.File != "ztest.prog.abap" {
+		t.Errorf("unexpected syntax finding: %+v", syntax)
+	}
+	if test := findings[3]; test.Line != 21 || test.File != "zcl_calc.clas.testclasses.abap" || !strings.Contains(test.Message, "LTCL_CALC->SUB") {
+		t.Errorf("unexpected unit test finding: %+v", test)
+	}
+	if test := findings[4]; test.Line != 0 || test.File != "zcl_calc.clas.testclasses.abap" {
+		t.Errorf("unexpected unit test finding without stack: %+v", test)
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	data, err := SARIF(testReportFindings(), "src")
+	if err != nil {
+		t.Fatalf("SARIF failed: %v", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+				Suppressions []struct {
+					Kind string `json:"kind"`
+				} `json:"suppressions"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 3 {
+		t.Fatalf("unexpected SARIF log: version %s, %d runs", log.Version, len(log.Runs))
+	}
+	atc := log.Runs[0]
+	if atc.Tool.Driver.Name != "ATC" || len(atc.Tool.Driver.Rules) != 2 || len(atc.Results) != 2 {
+		t.Fatalf("unexpected ATC run: %+v", atc)
+	}
+	loc := atc.Results[0].Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "src/zcl_calc.clas.abap" || loc.Region.StartLine != 12 || loc.Region.StartColumn != 5 {
+		t.Errorf("unexpected location: %+v", loc)
+	}
+	if atc.Results[0].Level != "error" || len(atc.Results[1].Suppressions) != 1 {
+		t.Errorf("unexpected results: %+v", atc.Results)
+	}
+	if log.Runs[2].Tool.Driver.Name != "ABAP Unit" || len(log.Runs[2].Results) != 2 {
+		t.Errorf("unexpected unit test run: %+v", log.Runs[2])
+	}
+}
+
+func TestGitHubAnnotations(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(string(GitHubAnnotations(testReportFindings(), "src"))), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d annotations, want 4 (suppressed skipped):\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	want := "::error file=src/zcl_calc.clas.abap,line=12,col=5,title=ATC CL_CI_TEST_SELECT/0001%3A Search problematic statements::SELECT * used"
+	if lines[0] != want {
+		t.Errorf("annotation = %q\nwant %q", lines[0], want)
+	}
+	if !strings.HasPrefix(lines[1], "::warning file=src/ztest.prog.abap,line=3,") {
+		t.Errorf("unexpected syntax annotation: %s", lines[1])
+	}
+}
+
+func TestGitLabCodeQuality(t *testing.T) {
+	data, err := GitLabCodeQuality(testReportFindings(), "src")
+	if err != nil {
+		t.Fatalf("GitLabCodeQuality failed: %v", err)
+	}
+
+	var issues []gitlabIssue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		t.Fatalf("invalid code quality JSON: %v", err)
+	}
+	if len(issues) != 4 {
+		t.Fatalf("got %d issues, want 4", len(issues))
+	}
+	first := issues[0]
+	if first.Severity != "major" || first.Location.Path != "src/zcl_calc.clas.abap" || first.Location.Lines.Begin != 12 || len(first.Fingerprint) != 40 {
+		t.Errorf("unexpected issue: %+v", first)
+	}
+	if last := issues[3]; last.Location.Lines.Begin != 1 {
+		t.Errorf("issue without line should start at line 1: %+v", last)
+	}
+	if issues[2].Fingerprint == issues[3].Fingerprint {
+		t.Error("fingerprints must differ between findings")
+	}
+
+	if _, err := RenderFindings("checkstyle", nil, "src"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,167 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// --- Class Runner (IF_OO_ADT_CLASSRUN) ---
+//
+// ADT executes classes implementing IF_OO_ADT_CLASSRUN and returns what they
+// write to the console. Unlike programs and unit test wrappers this works on
+// ABAP Cloud systems as well.
+
+// RunClass executes the main method of a class implementing
+// IF_OO_ADT_CLASSRUN and returns its console output.
+func (c *Client) RunClass(ctx context.Context, className string) (string, error) {
+	if err := c.checkSafety(OpWorkflow, "RunClass"); err != nil {
+		return "", err
+	}
+	return c.runClass(ctx, className)
+}
+
+func (c *Client) runClass(ctx context.Context, className string) (string, error) {
+	className = strings.ToUpper(strings.TrimSpace(className))
+	if className == "" {
+		return "", fmt.Errorf("class name is required")
+	}
+	resp, err := c.transport.Request(ctx, "/sap/bc/adt/oo/classrun/"+url.PathEscape(className), &RequestOptions{
+		Method: http.MethodPost,
+		Accept: "text/plain",
+	})
+	if err != nil {
+		return "", fmt.Errorf("running class %s: %w", className, err)
+	}
+	return string(resp.Body), nil
+}
+
+// executeABAPClassRunner is ExecuteABAP with the class runner (ABAP Cloud or
+// Runner "class"): the code runs in the main method of a temporary class
+// runner, the return variable is written to the console.
+func (c *Client) executeABAPClassRunner(ctx context.Context, code string, opts *ExecuteABAPOptions) (*ExecuteABAPResult, error) {
+	result := &ExecuteABAPResult{
+		Output: []string{},
+	}
+
+	prefix := opts.ClassPrefix
+	if prefix == "" {
+		prefix = "ZCL_VSP_EXEC_"
+	}
+	timestamp := fmt.Sprintf("%d", time.Now().UnixNano()/1000000)
+	className := strings.ToUpper(prefix + timestamp[len(timestamp)-8:])
+	result.ProgramName = className
+	objectURL := fmt.Sprintf("/sap/bc/adt/oo/classes/%s", url.PathEscape(className))
+
+	source := fmt.Sprintf(`CLASS %[1]s DEFINITION PUBLIC FINAL CREATE PUBLIC.
+  PUBLIC SECTION.
+    INTERFACES if_oo_adt_classrun.
+ENDCLASS.
+
+*"---------------------------------------------------------------------*
+*" Auto-generated class runner for code execution
+*" Generated by vsp ExecuteABAP workflow
+*"---------------------------------------------------------------------*
+CLASS %[1]s IMPLEMENTATION.
+  METHOD if_oo_adt_classrun~main.
+    DATA %[2]s TYPE string.
+
+    " === USER CODE START ===
+%[3]s
+    " === USER CODE END ===
+
+    out->write( |EXEC_RESULT:{ %[2]s }| ).
+  ENDMETHOD.
+ENDCLASS.
+`, strings.ToLower(className), opts.ReturnVariable, code)
+
+	if err := c.checkCloudSource(ctx, code); err != nil {
+		result.Message = err.Error()
+		return result, nil
+	}
+
+	err := c.CreateObject(ctx, CreateObjectOptions{
+		ObjectType:  ObjectTypeClass,
+		Name:        className,
+		Description: "Temp class for ExecuteABAP",
+		PackageName: opts.Package,
+	})
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to create temp class: %v", err)
+		return result, nil
+	}
+
+	defer func() {
+		if !opts.KeepProgram {
+			lock, lockErr := c.LockObject(ctx, objectURL, "MODIFY")
+			if lockErr == nil {
+				_ = c.DeleteObject(ctx, objectURL, lock.LockHandle, "")
+				result.CleanedUp = true
+			}
+		}
+	}()
+
+	lock, err := c.LockObject(ctx, objectURL, "MODIFY")
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to lock temp class: %v", err)
+		return result, nil
+	}
+	err = c.UpdateSource(ctx, objectURL+"/source/main", source, lock.LockHandle, "")
+	if err != nil {
+		_ = c.UnlockObject(ctx, objectURL, lock.LockHandle)
+		result.Message = fmt.Sprintf("Failed to update source: %v", err)
+		return result, nil
+	}
+	if err := c.UnlockObject(ctx, objectURL, lock.LockHandle); err != nil {
+		result.Message = fmt.Sprintf("Failed to unlock: %v", err)
+		return result, nil
+	}
+
+	activation, err := c.Activate(ctx, objectURL, className)
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to activate: %v", err)
+		return result, nil
+	}
+	if !activation.Success {
+		var errs []string
+		for _, m := range activation.Messages {
+			if m.Type == "E" {
+				errs = append(errs, fmt.Sprintf("line %d: %s", m.Line, m.ShortText))
+			}
+		}
+		result.Message = fmt.Sprintf("Failed to activate: %s", strings.Join(errs, "; "))
+		return result, nil
+	}
+
+	start := time.Now()
+	console, err := c.runClass(ctx, className)
+	result.ExecutionTime = time.Since(start).Seconds()
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to run class: %v", err)
+		return result, nil
+	}
+	result.Output = parseExecOutput(console)
+
+	result.Success = true
+	if len(result.Output) > 0 {
+		result.Message = fmt.Sprintf("Executed successfully, %d output(s) returned", len(result.Output))
+	} else {
+		result.Message = "Executed successfully (no output captured)"
+	}
+	return result, nil
+}
+
+// parseExecOutput returns the non-empty lines of class runner console
+// output, with the EXEC_RESULT marker of the return variable removed.
+func parseExecOutput(console string) []string {
+	output := []string{}
+	for _, line := range strings.Split(strings.ReplaceAll(console, "\r\n", "\n"), "\n") {
+		if line = strings.TrimPrefix(line, "EXEC_RESULT:"); strings.TrimSpace(line) != "" {
+			output = append(output, line)
+		}
+	}
+	return output
+}
@@ -0,0 +1,78 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseExecOutput(t *testing.T) {
+	console := "Starting\r\nEXEC_RESULT:42\r\n\r\nEXEC_RESULT:done\n"
+	got := parseExecOutput(console)
+	want := []string{"Starting", "42", "done"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseExecOutput() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteSource_CloudModeRejectsPrograms(t *testing.T) {
+	client := NewClient("http://localhost:1", "user", "pass", WithCloudMode())
+	if !client.CloudMode() {
+		t.Fatal("WithCloudMode did not enable cloud mode")
+	}
+	result, err := client.WriteSource(context.Background(), "PROG", "ZTEST", "REPORT ztest.", nil)
+	if err != nil {
+		t.Fatalf("WriteSource failed: %v", err)
+	}
+	if result.Success || !strings.Contains(result.Message, "IF_OO_ADT_CLASSRUN") {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestRunClass(t *testing.T) {
+	var method, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead || r.Header.Get("X-CSRF-Token") == "fetch" {
+			w.Header().Set("X-CSRF-Token", "test-token")
+			return
+		}
+		method, path = r.Method, r.URL.Path
+		fmt.Fprint(w, "Hello from ZCL_DEMO_RUNNER\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user", "pass")
+	console, err := client.RunClass(context.Background(), "zcl_demo_runner")
+	if err != nil {
+		t.Fatalf("RunClass failed: %v", err)
+	}
+	if method != http.MethodPost || path != "/sap/bc/adt/oo/classrun/ZCL_DEMO_RUNNER" {
+		t.Errorf("unexpected request: %s %s", method, path)
+	}
+	if console != "Hello from ZCL_DEMO_RUNNER\n" {
+		t.Errorf("console = %q", console)
+	}
+
+	readOnly := NewClient(server.URL, "user", "pass", WithReadOnly())
+	if _, err := readOnly.RunClass(context.Background(), "ZCL_DEMO_RUNNER"); err == nil {
+		t.Error("expected RunClass to be blocked in read-only mode")
+	}
+}
+
+func TestExecuteABAP_Runner(t *testing.T) {
+	client := NewClient("http://localhost:1", "user", "pass")
+	if _, err := client.ExecuteABAP(context.Background(), "lv_result = 1.", &ExecuteABAPOptions{Runner: "report"}); err == nil {
+		t.Error("expected error for unknown runner")
+	}
+	if client.usesClassRunner(nil) || !client.usesClassRunner(&ExecuteABAPOptions{Runner: "CLASS"}) {
+		t.Error("class runner not selected by the Runner option")
+	}
+	client.SetCloudMode(true)
+	if !client.usesClassRunner(&ExecuteABAPOptions{Runner: ExecuteRunnerProgram}) {
+		t.Error("cloud mode must always use the class runner")
+	}
+}
@@ -7,30 +7,51 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // Client is the main ADT API client.
 type Client struct {
 	transport *Transport
 	config    *Config
+	cloud     atomic.Bool // ABAP Cloud mode, may be switched on after probing
+
+	releaseMu    sync.Mutex
+	releaseCache map[string]ReleasedAPIViolation // Release states by kind and name
 }
 
 // NewClient creates a new ADT client with the given configuration.
 func NewClient(baseURL, username, password string, opts ...Option) *Client {
 	cfg := NewConfig(baseURL, username, password, opts...)
-	return &Client{
+	c := &Client{
 		transport: NewTransport(cfg),
 		config:    cfg,
 	}
+	c.cloud.Store(cfg.Cloud)
+	return c
 }
 
 // NewClientWithTransport creates a new client with a custom transport.
 // This is useful for testing.
 func NewClientWithTransport(cfg *Config, transport *Transport) *Client {
-	return &Client{
+	c := &Client{
 		transport: transport,
 		config:    cfg,
 	}
+	c.cloud.Store(cfg.Cloud)
+	return c
+}
+
+// SetCloudMode switches ABAP Cloud mode, e.g. once the system was probed.
+func (c *Client) SetCloudMode(on bool) {
+	c.cloud.Store(on)
+}
+
+// CloudMode reports whether the client works in ABAP Cloud mode: generated
+// code must use released APIs only and ExecuteABAP runs a class runner.
+func (c *Client) CloudMode() bool {
+	return c.cloud.Load()
 }
 
 // checkSafety checks if an operation is allowed by the safety configuration.
@@ -1540,10 +1561,11 @@ type DumpDetails struct {
 
 // StackFrame represents a single frame in the stack trace.
 type StackFrame struct {
-	Program string `json:"program"`
-	Include string `json:"include,omitempty"`
-	Line    int    `json:"line"`
-	Event   string `json:"event,omitempty"`
+	Program   string `json:"program"`
+	Include   string `json:"include,omitempty"`
+	Line      int    `json:"line"`
+	EventType string `json:"eventType,omitempty"` // METHOD, FUNCTION, FORM, EVENT, ...
+	Event     string `json:"event,omitempty"`
 }
 
 // DumpVariable represents a variable captured in the dump.
@@ -1741,6 +1763,9 @@ func parseDumpDetails(data []byte, dumpID string) (*DumpDetails, error) {
 		}
 	}
 
+	// Header fields, active calls and chosen variables (ST22 layout)
+	parseDumpText(details, dumpText(html))
+
 	return details, nil
 }
 
@@ -48,6 +48,13 @@ type Config struct {
 	Features FeatureConfig
 	// TerminalID for debugger session (shared with SAP GUI for cross-tool debugging)
 	TerminalID string
+	// UI5Backend selects how UI5Push deploys: "filestore" (default) or "odata"
+	UI5Backend string
+	// OAuth enables bearer token authentication (BTP ABAP Environment)
+	OAuth *OAuthConfig
+	// Cloud enables ABAP Cloud mode: released APIs only, class runner
+	// instead of programs
+	Cloud bool
 }
 
 // Option is a functional option for configuring the ADT client.
@@ -164,11 +171,31 @@ func WithAllowTransportableEdits() Option {
 	}
 }
 
+// WithOAuth enables OAuth bearer token authentication. Username and password,
+// if given, are used for the password grant.
+func WithOAuth(oauth *OAuthConfig) Option {
+	return func(c *Config) {
+		c.OAuth = oauth
+	}
+}
+
+// WithCloudMode enables ABAP Cloud mode (BTP ABAP Environment).
+func WithCloudMode() Option {
+	return func(c *Config) {
+		c.Cloud = true
+	}
+}
+
 // HasBasicAuth returns true if username and password are configured.
 func (c *Config) HasBasicAuth() bool {
 	return c.Username != "" && c.Password != ""
 }
 
+// HasOAuth returns true if OAuth is configured.
+func (c *Config) HasOAuth() bool {
+	return c.OAuth != nil && c.OAuth.TokenURL != ""
+}
+
 // HasCookieAuth returns true if cookies are configured.
 func (c *Config) HasCookieAuth() bool {
 	return len(c.Cookies) > 0
@@ -213,6 +240,13 @@ func WithTerminalID(terminalID string) Option {
 	}
 }
 
+// WithUI5Backend sets the default UI5 deployment backend ("filestore" or "odata").
+func WithUI5Backend(backend string) Option {
+	return func(c *Config) {
+		c.UI5Backend = backend
+	}
+}
+
 // NewHTTPClient creates an http.Client configured for the given Config.
 func (c *Config) NewHTTPClient() *http.Client {
 	jar, _ := cookiejar.New(nil)
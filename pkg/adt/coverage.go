@@ -0,0 +1,586 @@
+package adt
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- ABAP Unit Code Coverage ---
+//
+// A unit test run with coverage active returns the URI of a coverage
+// measurement. The measurement is queried for the statement, branch and
+// procedure coverage tree (package → object → method) and, per method, for
+// the executed/unexecuted statements, which yield line coverage.
+
+// CoverageCounter is a covered/total pair for one coverage type.
+type CoverageCounter struct {
+	Total    int `json:"total"`
+	Executed int `json:"executed"`
+}
+
+// Percent returns the coverage in percent (100 if there is nothing to cover).
+func (c CoverageCounter) Percent() float64 {
+	if c.Total == 0 {
+		return 100
+	}
+	return float64(c.Executed) * 100 / float64(c.Total)
+}
+
+// Rate returns the coverage as a ratio between 0 and 1.
+func (c CoverageCounter) Rate() float64 {
+	return c.Percent() / 100
+}
+
+func (c *CoverageCounter) add(o CoverageCounter) {
+	c.Total += o.Total
+	c.Executed += o.Executed
+}
+
+// CoverageMetrics holds statement, branch and procedure coverage.
+type CoverageMetrics struct {
+	Statement CoverageCounter `json:"statement"`
+	Branch    CoverageCounter `json:"branch"`
+	Procedure CoverageCounter `json:"procedure"`
+}
+
+func (m *CoverageMetrics) add(o CoverageMetrics) {
+	m.Statement.add(o.Statement)
+	m.Branch.add(o.Branch)
+	m.Procedure.add(o.Procedure)
+}
+
+// MethodCoverage is the coverage of a method, function module or form.
+type MethodCoverage struct {
+	Name      string `json:"name"`
+	URI       string `json:"uri"`
+	File      string `json:"file,omitempty"` // abapGit file the method is implemented in
+	StartLine int    `json:"startLine,omitempty"`
+	EndLine   int    `json:"endLine,omitempty"`
+	CoverageMetrics
+}
+
+// LineCoverage is the execution count of a source line.
+type LineCoverage struct {
+	File string `json:"file"` // abapGit file name
+	Line int    `json:"line"`
+	Hits int    `json:"hits"`
+}
+
+// ObjectCoverage is the coverage of a repository object.
+type ObjectCoverage struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	URI      string `json:"uri"`
+	FilePath string `json:"filePath,omitempty"` // abapGit file name of the main source
+	CoverageMetrics
+	Methods []MethodCoverage `json:"methods,omitempty"`
+	Lines   []LineCoverage   `json:"lines,omitempty"`
+}
+
+// CoverageReport is the coverage result of one or more unit test runs.
+type CoverageReport struct {
+	MeasurementURIs []string `json:"measurementUris"`
+	CoverageMetrics
+	Objects []ObjectCoverage `json:"objects"`
+}
+
+// coverageObjectTypes are the node types treated as repository objects in the
+// coverage tree; their descendants are procedures.
+var coverageObjectTypes = map[string]bool{
+	"CLAS/OC": true,
+	"PROG/P":  true,
+	"PROG/I":  true,
+	"FUGR/F":  true,
+	"INTF/OI": true,
+}
+
+// GetCoverage reads the coverage measurement of a unit test run (see
+// UnitTestResult.CoverageURI) for the given objects, including line coverage.
+func (c *Client) GetCoverage(ctx context.Context, measurementURI string, objectURIs []string) (*CoverageReport, error) {
+	if measurementURI == "" {
+		return nil, fmt.Errorf("coverage measurement URI is required")
+	}
+
+	var refs strings.Builder
+	for _, uri := range objectURIs {
+		fmt.Fprintf(&refs, `
+    <adtcore:objectReference adtcore:uri="%s"/>`, xmlEscape(uri))
+	}
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<cov:query xmlns:cov="http://www.sap.com/adt/cov" xmlns:adtcore="http://www.sap.com/adt/core">
+  <adtcore:objectReferences>%s
+  </adtcore:objectReferences>
+</cov:query>`, refs.String())
+
+	resp, err := c.transport.Request(ctx, measurementURI, &RequestOptions{
+		Method:      http.MethodPost,
+		Body:        []byte(body),
+		ContentType: "application/xml",
+		Accept:      "application/xml",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting coverage measurement: %w", err)
+	}
+
+	report, err := parseCoverageMeasurement(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	report.MeasurementURIs = []string{measurementURI}
+
+	// Statement (line) coverage per object
+	statementsURI := coverageStatementsURI(measurementURI)
+	for i := range report.Objects {
+		obj := &report.Objects[i]
+		var uris []string
+		for _, m := range obj.Methods {
+			uris = append(uris, m.URI)
+		}
+		if len(uris) == 0 {
+			continue
+		}
+		lines, err := c.getStatementCoverage(ctx, statementsURI, uris)
+		if err != nil {
+			return nil, fmt.Errorf("getting statement coverage of %s: %w", obj.Name, err)
+		}
+		obj.Lines = lines
+	}
+
+	return report, nil
+}
+
+// coverageStatementsURI derives the statements endpoint from the measurement
+// URI: .../coverage/measurements/{id} → .../coverage/results/{id}/statements.
+func coverageStatementsURI(measurementURI string) string {
+	return strings.Replace(strings.TrimSuffix(measurementURI, "/"), "/coverage/measurements/", "/coverage/results/", 1) + "/statements"
+}
+
+func (c *Client) getStatementCoverage(ctx context.Context, statementsURI string, uris []string) ([]LineCoverage, error) {
+	var reqs strings.Builder
+	for _, uri := range uris {
+		fmt.Fprintf(&reqs, `
+  <statementsRequest get="%s"/>`, xmlEscape(uri))
+	}
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<cov:statementsBulkRequest xmlns:cov="http://www.sap.com/adt/cov">%s
+</cov:statementsBulkRequest>`, reqs.String())
+
+	resp, err := c.transport.Request(ctx, statementsURI, &RequestOptions{
+		Method:      http.MethodPost,
+		Body:        []byte(body),
+		ContentType: "application/xml",
+		Accept:      "application/xml",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseStatementCoverage(resp.Body)
+}
+
+// coverageNodeXML is a node of the coverage tree (namespaces stripped).
+type coverageNodeXML struct {
+	URI       string `xml:"uri,attr"`
+	Type      string `xml:"type,attr"`
+	Name      string `xml:"name,attr"`
+	ObjectRef struct {
+		URI  string `xml:"uri,attr"`
+		Type string `xml:"type,attr"`
+		Name string `xml:"name,attr"`
+	} `xml:"objectReference"`
+	Coverages struct {
+		Items []struct {
+			Type     string `xml:"type,attr"`
+			Total    int    `xml:"total,attr"`
+			Executed int    `xml:"executed,attr"`
+		} `xml:"coverage"`
+	} `xml:"coverages"`
+	Nodes struct {
+		Items []coverageNodeXML `xml:"node"`
+	} `xml:"nodes"`
+}
+
+func (n *coverageNodeXML) ref() (uri, typ, name string) {
+	uri, typ, name = n.URI, n.Type, n.Name
+	if n.ObjectRef.URI != "" {
+		uri = n.ObjectRef.URI
+	}
+	if n.ObjectRef.Type != "" {
+		typ = n.ObjectRef.Type
+	}
+	if n.ObjectRef.Name != "" {
+		name = n.ObjectRef.Name
+	}
+	return uri, typ, name
+}
+
+func (n *coverageNodeXML) metrics() CoverageMetrics {
+	var m CoverageMetrics
+	for _, cov := range n.Coverages.Items {
+		counter := CoverageCounter{Total: cov.Total, Executed: cov.Executed}
+		switch strings.ToLower(cov.Type) {
+		case "statement":
+			m.Statement = counter
+		case "branch":
+			m.Branch = counter
+		case "procedure":
+			m.Procedure = counter
+		}
+	}
+	return m
+}
+
+func parseCoverageMeasurement(data []byte) (*CoverageReport, error) {
+	xmlStr := string(data)
+	xmlStr = strings.ReplaceAll(xmlStr, "cov:", "")
+	xmlStr = strings.ReplaceAll(xmlStr, "adtcore:", "")
+
+	var resp struct {
+		Nodes struct {
+			Items []coverageNodeXML `xml:"node"`
+		} `xml:"nodes"`
+	}
+	if err := xml.Unmarshal([]byte(xmlStr), &resp); err != nil {
+		return nil, fmt.Errorf("parsing coverage measurement: %w", err)
+	}
+
+	report := &CoverageReport{Objects: []ObjectCoverage{}}
+	var walk func(nodes []coverageNodeXML)
+	walk = func(nodes []coverageNodeXML) {
+		for i := range nodes {
+			node := &nodes[i]
+			uri, typ, name := node.ref()
+			if !coverageObjectTypes[strings.ToUpper(typ)] {
+				walk(node.Nodes.Items)
+				continue
+			}
+			obj := ObjectCoverage{
+				Name:            name,
+				Type:            typ,
+				URI:             uri,
+				FilePath:        AbapGitFileName(uri),
+				CoverageMetrics: node.metrics(),
+			}
+			collectMethodCoverage(node.Nodes.Items, &obj.Methods)
+			report.CoverageMetrics.add(obj.CoverageMetrics)
+			report.Objects = append(report.Objects, obj)
+		}
+	}
+	walk(resp.Nodes.Items)
+
+	return report, nil
+}
+
+// collectMethodCoverage collects the leaf nodes below an object.
+func collectMethodCoverage(nodes []coverageNodeXML, methods *[]MethodCoverage) {
+	for i := range nodes {
+		node := &nodes[i]
+		if len(node.Nodes.Items) > 0 {
+			collectMethodCoverage(node.Nodes.Items, methods)
+			continue
+		}
+		uri, _, name := node.ref()
+		start, end := parseSourceRange(uri)
+		*methods = append(*methods, MethodCoverage{
+			Name:            name,
+			URI:             uri,
+			File:            AbapGitFileName(uri),
+			StartLine:       start,
+			EndLine:         end,
+			CoverageMetrics: node.metrics(),
+		})
+	}
+}
+
+func parseStatementCoverage(data []byte) ([]LineCoverage, error) {
+	xmlStr := string(data)
+	xmlStr = strings.ReplaceAll(xmlStr, "cov:", "")
+	xmlStr = strings.ReplaceAll(xmlStr, "adtcore:", "")
+
+	type statement struct {
+		Executed  string `xml:"executed,attr"`
+		ObjectRef struct {
+			URI string `xml:"uri,attr"`
+		} `xml:"objectReference"`
+	}
+	var resp struct {
+		Responses []struct {
+			Statements []statement `xml:"statement"`
+		} `xml:"statementsResponse"`
+	}
+	if err := xml.Unmarshal([]byte(xmlStr), &resp); err != nil {
+		return nil, fmt.Errorf("parsing statement coverage: %w", err)
+	}
+
+	type lineKey struct {
+		file string
+		line int
+	}
+	hits := make(map[lineKey]int)
+	var order []lineKey
+	for _, r := range resp.Responses {
+		for _, s := range r.Statements {
+			line, _ := parseSourceRange(s.ObjectRef.URI)
+			if line == 0 {
+				continue
+			}
+			key := lineKey{file: AbapGitFileName(s.ObjectRef.URI), line: line}
+			if _, seen := hits[key]; !seen {
+				order = append(order, key)
+				hits[key] = 0
+			}
+			hits[key] += statementHits(s.Executed)
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].file != order[j].file {
+			return order[i].file < order[j].file
+		}
+		return order[i].line < order[j].line
+	})
+	lines := make([]LineCoverage, 0, len(order))
+	for _, key := range order {
+		lines = append(lines, LineCoverage{File: key.file, Line: key.line, Hits: hits[key]})
+	}
+	return lines, nil
+}
+
+// statementHits interprets the executed attribute ("true", "false" or a count).
+func statementHits(executed string) int {
+	switch strings.ToLower(strings.TrimSpace(executed)) {
+	case "true":
+		return 1
+	case "", "false":
+		return 0
+	}
+	n, err := strconv.Atoi(executed)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Merge adds the objects of another report (e.g. from another test run).
+func (r *CoverageReport) Merge(other *CoverageReport) {
+	if other == nil {
+		return
+	}
+	r.MeasurementURIs = append(r.MeasurementURIs, other.MeasurementURIs...)
+	r.Objects = append(r.Objects, other.Objects...)
+	r.CoverageMetrics.add(other.CoverageMetrics)
+}
+
+// --- Coverage Export ---
+
+// LCOV renders the report as an LCOV tracefile. srcDir is prepended to the
+// abapGit file names (e.g. "src/").
+func (r *CoverageReport) LCOV(srcDir string) []byte {
+	var buf bytes.Buffer
+	for _, file := range r.files() {
+		fmt.Fprintf(&buf, "TN:\nSF:%s\n", path.Join(srcDir, file.name))
+
+		fnHit := 0
+		for _, m := range file.methods {
+			fmt.Fprintf(&buf, "FN:%d,%s\n", m.StartLine, m.Name)
+		}
+		for _, m := range file.methods {
+			hits := 0
+			if m.Procedure.Executed > 0 || m.Statement.Executed > 0 {
+				hits = 1
+				fnHit++
+			}
+			fmt.Fprintf(&buf, "FNDA:%d,%s\n", hits, m.Name)
+		}
+		fmt.Fprintf(&buf, "FNF:%d\nFNH:%d\n", len(file.methods), fnHit)
+
+		lineHit := 0
+		for _, l := range file.lines {
+			fmt.Fprintf(&buf, "DA:%d,%d\n", l.Line, l.Hits)
+			if l.Hits > 0 {
+				lineHit++
+			}
+		}
+		fmt.Fprintf(&buf, "LF:%d\nLH:%d\n", len(file.lines), lineHit)
+		buf.WriteString("end_of_record\n")
+	}
+	return buf.Bytes()
+}
+
+// Cobertura renders the report as Cobertura XML. srcDir is written as the
+// source root; class file names are abapGit file names below it.
+func (r *CoverageReport) Cobertura(srcDir string) ([]byte, error) {
+	type line struct {
+		Number int `xml:"number,attr"`
+		Hits   int `xml:"hits,attr"`
+	}
+	type method struct {
+		Name       string  `xml:"name,attr"`
+		Signature  string  `xml:"signature,attr"`
+		LineRate   float64 `xml:"line-rate,attr"`
+		BranchRate float64 `xml:"branch-rate,attr"`
+		Lines      []line  `xml:"lines>line"`
+	}
+	type class struct {
+		Name       string   `xml:"name,attr"`
+		Filename   string   `xml:"filename,attr"`
+		LineRate   float64  `xml:"line-rate,attr"`
+		BranchRate float64  `xml:"branch-rate,attr"`
+		Complexity float64  `xml:"complexity,attr"`
+		Methods    []method `xml:"methods>method"`
+		Lines      []line   `xml:"lines>line"`
+	}
+	type pkg struct {
+		Name       string  `xml:"name,attr"`
+		LineRate   float64 `xml:"line-rate,attr"`
+		BranchRate float64 `xml:"branch-rate,attr"`
+		Complexity float64 `xml:"complexity,attr"`
+		Classes    []class `xml:"classes>class"`
+	}
+	type coverage struct {
+		XMLName         xml.Name `xml:"coverage"`
+		LineRate        float64  `xml:"line-rate,attr"`
+		BranchRate      float64  `xml:"branch-rate,attr"`
+		LinesCovered    int      `xml:"lines-covered,attr"`
+		LinesValid      int      `xml:"lines-valid,attr"`
+		BranchesCovered int      `xml:"branches-covered,attr"`
+		BranchesValid   int      `xml:"branches-valid,attr"`
+		Complexity      float64  `xml:"complexity,attr"`
+		Version         string   `xml:"version,attr"`
+		Timestamp       int64    `xml:"timestamp,attr"`
+		Sources         []string `xml:"sources>source"`
+		Packages        []pkg    `xml:"packages>package"`
+	}
+
+	var total, branches CoverageCounter
+	p := pkg{Name: "abap"}
+	for _, file := range r.files() {
+		lineCounter := file.lineCounter()
+		total.add(lineCounter)
+		branches.add(file.branch)
+
+		cl := class{
+			Name:       file.object,
+			Filename:   file.name,
+			LineRate:   lineCounter.Rate(),
+			BranchRate: file.branch.Rate(),
+		}
+		for _, m := range file.methods {
+			cm := method{Name: m.Name, LineRate: m.Statement.Rate(), BranchRate: m.Branch.Rate()}
+			for _, l := range file.lines {
+				if m.StartLine > 0 && l.Line >= m.StartLine && (m.EndLine == 0 || l.Line <= m.EndLine) {
+					cm.Lines = append(cm.Lines, line{Number: l.Line, Hits: l.Hits})
+				}
+			}
+			cl.Methods = append(cl.Methods, cm)
+		}
+		for _, l := range file.lines {
+			cl.Lines = append(cl.Lines, line{Number: l.Line, Hits: l.Hits})
+		}
+		p.Classes = append(p.Classes, cl)
+	}
+	p.LineRate = total.Rate()
+	p.BranchRate = branches.Rate()
+
+	doc := coverage{
+		LineRate:        total.Rate(),
+		BranchRate:      branches.Rate(),
+		LinesCovered:    total.Executed,
+		LinesValid:      total.Total,
+		BranchesCovered: branches.Executed,
+		BranchesValid:   branches.Total,
+		Version:         "vsp",
+		Timestamp:       time.Now().UnixMilli(),
+		Sources:         []string{srcDir},
+		Packages:        []pkg{p},
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("rendering cobertura report: %w", err)
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// coverageFile groups coverage data by abapGit file.
+type coverageFile struct {
+	name      string
+	object    string
+	methods   []MethodCoverage
+	lines     []LineCoverage
+	statement CoverageCounter // Fallback when no line data is available
+	branch    CoverageCounter
+}
+
+// lineCounter counts covered lines, falling back to statement coverage.
+func (f *coverageFile) lineCounter() CoverageCounter {
+	if len(f.lines) == 0 {
+		return f.statement
+	}
+	var c CoverageCounter
+	for _, l := range f.lines {
+		c.Total++
+		if l.Hits > 0 {
+			c.Executed++
+		}
+	}
+	return c
+}
+
+// files groups methods and lines of all objects by file, sorted by name.
+func (r *CoverageReport) files() []*coverageFile {
+	byName := make(map[string]*coverageFile)
+	get := func(name, object string) *coverageFile {
+		f, ok := byName[name]
+		if !ok {
+			f = &coverageFile{name: name, object: object}
+			byName[name] = f
+		}
+		return f
+	}
+
+	for _, obj := range r.Objects {
+		mainFile := obj.FilePath
+		if mainFile == "" {
+			mainFile = strings.ToLower(strings.ReplaceAll(obj.Name, "/", "#")) + ".abap"
+		}
+		for _, m := range obj.Methods {
+			name := m.File
+			if name == "" {
+				name = mainFile
+			}
+			f := get(name, obj.Name)
+			f.methods = append(f.methods, m)
+			f.statement.add(m.Statement)
+			f.branch.add(m.Branch)
+		}
+		for _, l := range obj.Lines {
+			name := l.File
+			if name == "" {
+				name = mainFile
+			}
+			f := get(name, obj.Name)
+			f.lines = append(f.lines, l)
+		}
+		if len(obj.Methods) == 0 {
+			f := get(mainFile, obj.Name)
+			f.statement.add(obj.Statement)
+			f.branch.add(obj.Branch)
+		}
+	}
+
+	files := make([]*coverageFile, 0, len(byName))
+	for _, f := range byName {
+		sort.SliceStable(f.lines, func(i, j int) bool { return f.lines[i].Line < f.lines[j].Line })
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+	return files
+}
@@ -0,0 +1,179 @@
+package adt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testCoverageRunResult = `<?xml version="1.0" encoding="utf-8"?>
+<aunit:runResult xmlns:aunit="http://www.sap.com/adt/aunit" xmlns:adtcore="http://www.sap.com/adt/core">
+  <external>
+    <coverage adtcore:uri="/sap/bc/adt/runtime/traces/coverage/measurements/COV1"/>
+  </external>
+  <program adtcore:uri="/sap/bc/adt/oo/classes/zcl_calc" adtcore:type="CLAS/OC" adtcore:name="ZCL_CALC">
+    <testClasses>
+      <testClass adtcore:uri="/sap/bc/adt/oo/classes/zcl_calc/includes/testclasses#type=CLAS%2FOL;name=LTCL_CALC" adtcore:type="CLAS/OL" adtcore:name="LTCL_CALC">
+        <testMethods>
+          <testMethod adtcore:uri="/sap/bc/adt/oo/classes/zcl_calc/includes/testclasses#type=CLAS%2FOLD;name=LTCL_CALC%20%20%20%20%20%20%20%20%20%20%20%20%20%20%20%20%20%20%20%20%20%20ADD" adtcore:type="CLAS/OLD" adtcore:name="ADD" executionTime="0.001"/>
+        </testMethods>
+      </testClass>
+    </testClasses>
+  </program>
+</aunit:runResult>`
+
+const testCoverageMeasurement = `<?xml version="1.0" encoding="utf-8"?>
+<cov:result xmlns:cov="http://www.sap.com/adt/cov" xmlns:adtcore="http://www.sap.com/adt/core">
+  <cov:nodes>
+    <cov:node>
+      <adtcore:objectReference adtcore:uri="/sap/bc/adt/packages/%24tmp" adtcore:type="DEVC/K" adtcore:name="$TMP"/>
+      <cov:coverages>
+        <cov:coverage type="statement" total="6" executed="4"/>
+      </cov:coverages>
+      <cov:nodes>
+        <cov:node>
+          <adtcore:objectReference adtcore:uri="/sap/bc/adt/oo/classes/zcl_calc" adtcore:type="CLAS/OC" adtcore:name="ZCL_CALC"/>
+          <cov:coverages>
+            <cov:coverage type="branch" total="2" executed="1"/>
+            <cov:coverage type="procedure" total="2" executed="1"/>
+            <cov:coverage type="statement" total="6" executed="4"/>
+          </cov:coverages>
+          <cov:nodes>
+            <cov:node>
+              <adtcore:objectReference adtcore:uri="/sap/bc/adt/oo/classes/zcl_calc/source/main#start=8,2;end=11,11" adtcore:type="CLAS/OM" adtcore:name="ADD"/>
+              <cov:coverages>
+                <cov:coverage type="branch" total="2" executed="1"/>
+                <cov:coverage type="procedure" total="1" executed="1"/>
+                <cov:coverage type="statement" total="4" executed="4"/>
+              </cov:coverages>
+            </cov:node>
+            <cov:node>
+              <adtcore:objectReference adtcore:uri="/sap/bc/adt/oo/classes/zcl_calc/source/main#start=12,2;end=14,11" adtcore:type="CLAS/OM" adtcore:name="SUB"/>
+              <cov:coverages>
+                <cov:coverage type="procedure" total="1" executed="0"/>
+                <cov:coverage type="statement" total="2" executed="0"/>
+              </cov:coverages>
+            </cov:node>
+          </cov:nodes>
+        </cov:node>
+      </cov:nodes>
+    </cov:node>
+  </cov:nodes>
+</cov:result>`
+
+const testCoverageStatements = `<?xml version="1.0" encoding="utf-8"?>
+<cov:statementsBulkResponse xmlns:cov="http://www.sap.com/adt/cov" xmlns:adtcore="http://www.sap.com/adt/core">
+  <statementsResponse>
+    <statement executed="true"><adtcore:objectReference adtcore:uri="/sap/bc/adt/oo/classes/zcl_calc/source/main#start=9,4;end=9,20"/></statement>
+    <statement executed="3"><adtcore:objectReference adtcore:uri="/sap/bc/adt/oo/classes/zcl_calc/source/main#start=10,4;end=10,20"/></statement>
+  </statementsResponse>
+  <statementsResponse>
+    <statement executed="false"><adtcore:objectReference adtcore:uri="/sap/bc/adt/oo/classes/zcl_calc/source/main#start=13,4;end=13,20"/></statement>
+  </statementsResponse>
+</cov:statementsBulkResponse>`
+
+func TestRunUnitTests_Coverage(t *testing.T) {
+	var runBody, statementsBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+		case "/sap/bc/adt/abapunit/testruns":
+			runBody = string(body)
+			w.Write([]byte(testCoverageRunResult))
+		case "/sap/bc/adt/runtime/traces/coverage/measurements/COV1":
+			w.Write([]byte(testCoverageMeasurement))
+		case "/sap/bc/adt/runtime/traces/coverage/results/COV1/statements":
+			statementsBody = string(body)
+			w.Write([]byte(testCoverageStatements))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"))
+	flags := DefaultUnitTestFlags()
+	flags.Coverage = true
+
+	result, err := client.RunUnitTests(context.Background(), "/sap/bc/adt/oo/classes/ZCL_CALC", &flags)
+	if err != nil {
+		t.Fatalf("RunUnitTests failed: %v", err)
+	}
+	if !strings.Contains(runBody, `<coverage active="true"/>`) {
+		t.Error("coverage was not requested in the run configuration")
+	}
+	if !strings.Contains(statementsBody, `get="/sap/bc/adt/oo/classes/zcl_calc/source/main#start=12,2;end=14,11"`) {
+		t.Errorf("unexpected statements request: %s", statementsBody)
+	}
+
+	report := result.Coverage
+	if report == nil || len(report.Objects) != 1 {
+		t.Fatalf("unexpected coverage report: %+v", report)
+	}
+	obj := report.Objects[0]
+	if obj.Name != "ZCL_CALC" || obj.FilePath != "zcl_calc.clas.abap" {
+		t.Errorf("unexpected object: %s %s", obj.Name, obj.FilePath)
+	}
+	if obj.Statement.Executed != 4 || obj.Statement.Total != 6 || obj.Branch.Percent() != 50 {
+		t.Errorf("unexpected metrics: %+v", obj.CoverageMetrics)
+	}
+	if len(obj.Methods) != 2 || obj.Methods[1].Name != "SUB" || obj.Methods[1].StartLine != 12 {
+		t.Errorf("unexpected methods: %+v", obj.Methods)
+	}
+	if len(obj.Lines) != 3 || obj.Lines[1].Line != 10 || obj.Lines[1].Hits != 3 || obj.Lines[2].Hits != 0 {
+		t.Errorf("unexpected lines: %+v", obj.Lines)
+	}
+	if report.Statement.Total != 6 {
+		t.Errorf("report total = %+v", report.Statement)
+	}
+
+	lcov := string(report.LCOV("src"))
+	for _, want := range []string{"SF:src/zcl_calc.clas.abap", "FN:12,SUB", "FNDA:0,SUB", "FNH:1", "DA:10,3", "LF:3", "LH:2", "end_of_record"} {
+		if !strings.Contains(lcov, want) {
+			t.Errorf("LCOV missing %q:\n%s", want, lcov)
+		}
+	}
+
+	cobertura, err := report.Cobertura("src")
+	if err != nil {
+		t.Fatalf("Cobertura failed: %v", err)
+	}
+	for _, want := range []string{`lines-covered="2"`, `lines-valid="3"`, `filename="zcl_calc.clas.abap"`, `<method name="ADD"`, `<line number="13" hits="0"></line>`, `<source>src</source>`} {
+		if !strings.Contains(string(cobertura), want) {
+			t.Errorf("Cobertura missing %q:\n%s", want, cobertura)
+		}
+	}
+}
+
+func TestRunUnitTests_NoCoverageMeasurement(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sap/bc/adt/core/discovery" {
+			w.Header().Set("X-CSRF-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?><aunit:runResult xmlns:aunit="http://www.sap.com/adt/aunit"></aunit:runResult>`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"))
+	flags := DefaultUnitTestFlags()
+
+	result, err := client.RunUnitTests(context.Background(), "/sap/bc/adt/oo/classes/ZCL_CALC", &flags)
+	if err != nil || result.Coverage != nil {
+		t.Fatalf("unexpected result without coverage: %+v, %v", result, err)
+	}
+
+	// A missing measurement keeps the test results
+	flags.Coverage = true
+	result, err = client.RunUnitTests(context.Background(), "/sap/bc/adt/oo/classes/ZCL_CALC", &flags)
+	if err != nil || result == nil || result.CoverageError == "" {
+		t.Errorf("expected test results with a coverage error, got %+v, %v", result, err)
+	}
+}
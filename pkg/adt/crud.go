@@ -157,6 +157,7 @@ const (
 	ObjectTypePackage       CreatableObjectType = "DEVC/K"
 	// RAP object types (read-only via ADT, created via RAP generators)
 	ObjectTypeDDLS CreatableObjectType = "DDLS/DF"  // CDS DDL Source
+	ObjectTypeDDLX CreatableObjectType = "DDLX/EX"  // CDS Metadata Extension
 	ObjectTypeBDEF CreatableObjectType = "BDEF/BDO" // Behavior Definition
 	ObjectTypeSRVD CreatableObjectType = "SRVD/SRV" // Service Definition
 	ObjectTypeSRVB CreatableObjectType = "SRVB/SVB" // Service Binding
@@ -242,6 +243,11 @@ var objectTypes = map[CreatableObjectType]objectTypeInfo{
 		rootName:     "ddl:ddlSource",
 		namespace:    `xmlns:ddl="http://www.sap.com/adt/ddic/ddlsources"`,
 	},
+	ObjectTypeDDLX: {
+		creationPath: "/sap/bc/adt/ddic/ddlx/sources",
+		rootName:     "ddlx:ddlxSource",
+		namespace:    `xmlns:ddlx="http://www.sap.com/adt/ddic/ddlxsources"`,
+	},
 	ObjectTypeBDEF: {
 		creationPath: "/sap/bc/adt/bo/behaviordefinitions",
 		rootName:     "bdef:behaviorDefinition",
@@ -627,6 +633,8 @@ func GetObjectURL(objectType CreatableObjectType, name string, parentName string
 	// RAP object types - use lowercase for CDS objects
 	case ObjectTypeDDLS:
 		return fmt.Sprintf("/sap/bc/adt/ddic/ddl/sources/%s", url.PathEscape(strings.ToLower(name)))
+	case ObjectTypeDDLX:
+		return fmt.Sprintf("/sap/bc/adt/ddic/ddlx/sources/%s", url.PathEscape(strings.ToLower(name)))
 	case ObjectTypeBDEF:
 		return fmt.Sprintf("/sap/bc/adt/bo/behaviordefinitions/%s", url.PathEscape(strings.ToLower(name)))
 	case ObjectTypeSRVD:
@@ -869,8 +877,23 @@ func (c *Client) CreateTable(ctx context.Context, opts CreateTableOptions) error
 		opts.TableCategory = "TRANSPARENT"
 	}
 
-	// Generate DDL source
-	ddlSource := generateTableDDL(opts)
+	return c.CreateTableFromDDL(ctx, opts, generateTableDDL(opts))
+}
+
+// CreateTableFromDDL creates a DDIC table with the given DDL source
+// (create → set source → activate). Name, Description, Package and
+// Transport are taken from opts; Fields are ignored.
+func (c *Client) CreateTableFromDDL(ctx context.Context, opts CreateTableOptions, ddlSource string) error {
+	if err := c.checkSafety(OpCreate, "CreateTable"); err != nil {
+		return err
+	}
+	opts.Name = strings.ToUpper(opts.Name)
+	if opts.Name == "" || len(opts.Name) > 30 {
+		return fmt.Errorf("table name must be 1-30 characters")
+	}
+	if opts.Package == "" {
+		opts.Package = "$TMP"
+	}
 
 	// Step 1: Create table object
 	createBody := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
@@ -1050,6 +1050,63 @@ func (c *Client) DebuggerGetStack(ctx context.Context, semanticURIs bool) (*Debu
 	return parseStackResponse(resp.Body)
 }
 
+// DebuggerCurrentFrame returns the stack frame of the debug cursor.
+func (c *Client) DebuggerCurrentFrame(ctx context.Context) (*DebugStackFrame, error) {
+	stack, err := c.DebuggerGetStack(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(stack.Stack) == 0 {
+		return nil, fmt.Errorf("debugger returned an empty call stack")
+	}
+	entry := stack.Stack[0]
+	for _, e := range stack.Stack {
+		if e.StackPosition == stack.DebugCursorStackIndex {
+			entry = e
+			break
+		}
+	}
+	return &DebugStackFrame{
+		Index:     entry.StackPosition,
+		Program:   entry.ProgramName,
+		Include:   entry.IncludeName,
+		Line:      entry.Line,
+		Procedure: entry.EventName,
+		Active:    true,
+		System:    entry.SystemProgram,
+	}, nil
+}
+
+// BreakpointTarget returns the REST debug session of this client as the
+// target of breakpoint conditions and hit counts (see
+// DebugWebSocketClient.ContinueToStopIn).
+func (c *Client) BreakpointTarget() BreakpointTarget {
+	return restBreakpointTarget{client: c}
+}
+
+type restBreakpointTarget struct {
+	client *Client
+}
+
+func (t restBreakpointTarget) EvaluateBreakpointExpression(ctx context.Context, expression string) (string, error) {
+	result, err := t.client.DebuggerEvaluateExpression(ctx, expression)
+	if err != nil {
+		return "", err
+	}
+	return result.Variable.Value, nil
+}
+
+func (t restBreakpointTarget) ContinueToNextStop(ctx context.Context) (*DebugStackFrame, error) {
+	step, err := t.client.DebuggerStep(ctx, DebugStepContinue, "")
+	if err != nil {
+		return nil, err
+	}
+	if !step.IsSteppingPossible {
+		return nil, nil // Debuggee ended
+	}
+	return t.client.DebuggerCurrentFrame(ctx)
+}
+
 // DebuggerGetVariables retrieves the values of specific variables.
 // variableIDs: List of variable IDs to retrieve (e.g., ["@ROOT", "@DATAAGING", "LV_COUNT"])
 func (c *Client) DebuggerGetVariables(ctx context.Context, variableIDs []string) ([]DebugVariable, error) {
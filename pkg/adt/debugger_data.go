@@ -0,0 +1,338 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// --- Debugger Data Inspection (expressions, internal table paging) ---
+
+const (
+	debugTableDefaultLimit = 50
+	debugTableMaxLimit     = 1000
+	debugTableDefaultScan  = 5000
+	debugTableChunkSize    = 100
+)
+
+// DebugExpressionResult is the result of evaluating a debugger expression.
+type DebugExpressionResult struct {
+	Expression string          `json:"expression"` // Normalized expression (debugger syntax)
+	Variable   DebugVariable   `json:"variable"`
+	Components []DebugVariable `json:"components,omitempty"` // Set for structures
+}
+
+// DebugTableReadOptions controls paging through an internal table.
+type DebugTableReadOptions struct {
+	Offset  int      // 0-based row to start reading from
+	Limit   int      // Max rows to return (default 50, max 1000)
+	Columns []string // Columns to return (default: all)
+	Filter  string   // Row condition, e.g. "STATUS = 'E' AND AMOUNT > 100"
+	MaxScan int      // Max rows scanned per call when filtering (default 5000)
+}
+
+// DebugTableRow is a single internal table row.
+type DebugTableRow struct {
+	Index  int               `json:"index"` // 1-based row number (sy-tabix)
+	Values map[string]string `json:"values"`
+}
+
+// DebugTablePage is one page of internal table rows.
+type DebugTablePage struct {
+	Table      string          `json:"table"`
+	TotalLines int             `json:"totalLines"`
+	Offset     int             `json:"offset"`
+	Columns    []string        `json:"columns"`
+	Rows       []DebugTableRow `json:"rows"`
+	Scanned    int             `json:"scanned"`    // Rows read to build this page
+	NextOffset int             `json:"nextOffset"` // Offset to continue from
+	HasMore    bool            `json:"hasMore"`
+}
+
+// NormalizeDebugExpression converts an ABAP expression into the variable name
+// syntax understood by the debugger: upper case, no blanks, and 7.40 table
+// expressions rewritten to index access, e.g. "lt_tab[ 5 ]-field" becomes
+// "LT_TAB[5]-FIELD". Only index table expressions are supported.
+func NormalizeDebugExpression(expr string) (string, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return "", fmt.Errorf("expression is required")
+	}
+
+	var sb strings.Builder
+	depth := 0
+	var index strings.Builder
+	for _, r := range expr {
+		switch {
+		case unicode.IsSpace(r):
+			continue
+		case r == '[':
+			if depth > 0 {
+				return "", fmt.Errorf("nested table expressions are not supported: %s", expr)
+			}
+			depth++
+			index.Reset()
+			sb.WriteRune(r)
+		case r == ']':
+			if depth == 0 {
+				return "", fmt.Errorf("unbalanced ']' in expression: %s", expr)
+			}
+			depth--
+			n, err := strconv.Atoi(index.String())
+			if err != nil || n < 1 {
+				return "", fmt.Errorf("only index table expressions are supported (e.g. itab[ 5 ]), got [%s]", index.String())
+			}
+			sb.WriteString(strconv.Itoa(n))
+			sb.WriteRune(r)
+		case depth > 0:
+			index.WriteRune(r)
+		default:
+			sb.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	if depth != 0 {
+		return "", fmt.Errorf("unbalanced '[' in expression: %s", expr)
+	}
+
+	return sb.String(), nil
+}
+
+// DebuggerEvaluateExpression evaluates a variable expression in the current
+// debug session: field symbols (<FS>), structure components (LS_DATA-FIELD),
+// object attributes (LO_OBJ->ATTR) and table rows (LT_TAB[ 5 ]-FIELD).
+// For structures, the components are returned as well.
+func (c *Client) DebuggerEvaluateExpression(ctx context.Context, expression string) (*DebugExpressionResult, error) {
+	name, err := NormalizeDebugExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := c.DebuggerGetVariables(ctx, []string{name})
+	if err != nil {
+		return nil, fmt.Errorf("evaluating %s: %w", name, err)
+	}
+	if len(vars) == 0 {
+		return nil, fmt.Errorf("expression %s could not be evaluated", name)
+	}
+
+	result := &DebugExpressionResult{Expression: name, Variable: vars[0]}
+	if vars[0].MetaType == DebugMetaTypeStructure {
+		children, err := c.DebuggerGetChildVariables(ctx, []string{vars[0].ID})
+		if err != nil {
+			return nil, fmt.Errorf("reading components of %s: %w", name, err)
+		}
+		result.Components = childVariablesOf(children, vars[0].ID)
+	}
+
+	return result, nil
+}
+
+// DebuggerReadTable reads a page of rows from an internal table in the current
+// debug session. Rows are fetched in chunks; the ADT debugger has no row
+// filter, so a filter is applied here to the fetched rows and at most MaxScan
+// rows are read per call. DebugWebSocketClient.ReadTable filters in ZADT_VSP
+// instead.
+// Continue with NextOffset while HasMore is set.
+func (c *Client) DebuggerReadTable(ctx context.Context, table string, opts DebugTableReadOptions) (*DebugTablePage, error) {
+	name, err := NormalizeDebugExpression(table)
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := c.DebuggerGetVariables(ctx, []string{name})
+	if err != nil {
+		return nil, fmt.Errorf("reading table %s: %w", name, err)
+	}
+	if len(vars) == 0 {
+		return nil, fmt.Errorf("table %s not found", name)
+	}
+	if vars[0].MetaType != DebugMetaTypeTable {
+		return nil, fmt.Errorf("%s is not an internal table (meta type: %s)", name, vars[0].MetaType)
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = debugTableDefaultLimit
+	}
+	if limit > debugTableMaxLimit {
+		limit = debugTableMaxLimit
+	}
+	maxScan := opts.MaxScan
+	if maxScan <= 0 {
+		maxScan = debugTableDefaultScan
+	}
+	offset := opts.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	var filter parsedCondition
+	if strings.TrimSpace(opts.Filter) != "" {
+		if filter, err = parseBreakpointCondition(opts.Filter); err != nil {
+			return nil, fmt.Errorf("invalid filter: %w", err)
+		}
+	}
+
+	var columns []string
+	for _, col := range opts.Columns {
+		columns = append(columns, strings.ToUpper(strings.TrimSpace(col)))
+	}
+
+	page := &DebugTablePage{
+		Table:      name,
+		TotalLines: vars[0].TableLines,
+		Offset:     offset,
+		Columns:    columns,
+	}
+
+	pos := offset
+scan:
+	for pos < page.TotalLines && len(page.Rows) < limit {
+		chunk := debugTableChunkSize
+		if filter == nil && limit-len(page.Rows) < chunk {
+			chunk = limit - len(page.Rows)
+		}
+		if filter != nil {
+			if page.Scanned >= maxScan {
+				break
+			}
+			if maxScan-page.Scanned < chunk {
+				chunk = maxScan - page.Scanned
+			}
+		}
+		end := pos + chunk
+		if end > page.TotalLines {
+			end = page.TotalLines
+		}
+
+		rows, rowColumns, err := c.debuggerReadTableRows(ctx, name, pos, end)
+		if err != nil {
+			return nil, err
+		}
+		if page.Columns == nil {
+			page.Columns = rowColumns
+		}
+
+		for _, row := range rows {
+			pos = row.Index
+			page.Scanned++
+
+			if filter != nil {
+				match, err := filter.evaluate(func(col string) (string, error) {
+					v, ok := row.Values[strings.ToUpper(col)]
+					if !ok {
+						return "", fmt.Errorf("unknown column %s", strings.ToUpper(col))
+					}
+					return v, nil
+				})
+				if err != nil {
+					return nil, fmt.Errorf("evaluating filter on row %d: %w", row.Index, err)
+				}
+				if !match {
+					continue
+				}
+			}
+
+			page.Rows = append(page.Rows, selectTableColumns(row, columns))
+			if len(page.Rows) >= limit {
+				break scan
+			}
+		}
+		pos = end
+	}
+
+	page.NextOffset = pos
+	page.HasMore = pos < page.TotalLines
+	return page, nil
+}
+
+// debuggerReadTableRows reads rows [from, to) (0-based) of an internal table
+// with all components. Tables with an elementary line type yield a single
+// TABLE_LINE column.
+func (c *Client) debuggerReadTableRows(ctx context.Context, table string, from, to int) ([]DebugTableRow, []string, error) {
+	var rowIDs []string
+	for i := from; i < to; i++ {
+		rowIDs = append(rowIDs, fmt.Sprintf("%s[%d]", table, i+1))
+	}
+	if len(rowIDs) == 0 {
+		return nil, nil, nil
+	}
+
+	children, err := c.DebuggerGetChildVariables(ctx, rowIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading rows %d-%d of %s: %w", from+1, to, table, err)
+	}
+
+	var rows []DebugTableRow
+	var columns []string
+	if children != nil && len(children.Hierarchies) > 0 {
+		for i, id := range rowIDs {
+			row := DebugTableRow{Index: from + i + 1, Values: make(map[string]string)}
+			for _, v := range childVariablesOf(children, id) {
+				col := strings.ToUpper(v.Name)
+				row.Values[col] = v.Value
+				if i == 0 {
+					columns = append(columns, col)
+				}
+			}
+			rows = append(rows, row)
+		}
+		return rows, columns, nil
+	}
+
+	// Elementary line type: rows have no components
+	vars, err := c.DebuggerGetVariables(ctx, rowIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading rows %d-%d of %s: %w", from+1, to, table, err)
+	}
+	values := make(map[string]string, len(vars))
+	for _, v := range vars {
+		values[strings.ToUpper(v.ID)] = v.Value
+	}
+	for i, id := range rowIDs {
+		rows = append(rows, DebugTableRow{
+			Index:  from + i + 1,
+			Values: map[string]string{"TABLE_LINE": values[strings.ToUpper(id)]},
+		})
+	}
+	return rows, []string{"TABLE_LINE"}, nil
+}
+
+// childVariablesOf returns the children of parentID in hierarchy order.
+func childVariablesOf(info *DebugChildVariablesInfo, parentID string) []DebugVariable {
+	if info == nil {
+		return nil
+	}
+
+	byID := make(map[string]DebugVariable, len(info.Variables))
+	for _, v := range info.Variables {
+		byID[v.ID] = v
+	}
+
+	var result []DebugVariable
+	for _, h := range info.Hierarchies {
+		if !strings.EqualFold(h.ParentID, parentID) {
+			continue
+		}
+		if v, ok := byID[h.ChildID]; ok {
+			if h.ChildName != "" {
+				v.Name = h.ChildName
+			}
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func selectTableColumns(row DebugTableRow, columns []string) DebugTableRow {
+	if len(columns) == 0 {
+		return row
+	}
+	selected := DebugTableRow{Index: row.Index, Values: make(map[string]string, len(columns))}
+	for _, col := range columns {
+		selected.Values[col] = row.Values[col]
+	}
+	return selected
+}
@@ -0,0 +1,247 @@
+package adt
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeDebugExpression(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{"lv_count", "LV_COUNT", false},
+		{"ls_data-status", "LS_DATA-STATUS", false},
+		{"<fs_line>-matnr", "<FS_LINE>-MATNR", false},
+		{"lo_obj->mv_name", "LO_OBJ->MV_NAME", false},
+		{"lt_tab[ 5 ]-field", "LT_TAB[5]-FIELD", false},
+		{"lt_tab[5]", "LT_TAB[5]", false},
+		{"", "", true},
+		{"lt_tab[ key = 1 ]", "", true},
+		{"lt_tab[ 0 ]", "", true},
+		{"lt_tab[ 5", "", true},
+		{"lt_tab 5 ]", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := NormalizeDebugExpression(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error for %q, got %q", tt.expr, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeDebugExpression(%q) = %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+// newDebugTableServer mocks the debugger variable API for a table LT_DATA
+// with rows of components ID and STATUS (status "E" on every third row).
+func newDebugTableServer(t *testing.T, lines int) *httptest.Server {
+	idRe := regexp.MustCompile(`<ID>([^<]*)</ID>`)
+	parentRe := regexp.MustCompile(`<PARENT_ID>([^<]*)</PARENT_ID>`)
+	rowRe := regexp.MustCompile(`^LT_DATA\[(\d+)\]$`)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sap/bc/adt/core/discovery" {
+			w.Header().Set("X-CSRF-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var sb strings.Builder
+		sb.WriteString(`<?xml version="1.0" encoding="utf-8"?><asx:abap xmlns:asx="http://www.sap.com/abapxml" version="1.0"><asx:values><DATA>`)
+
+		switch r.URL.Query().Get("method") {
+		case "getVariables":
+			for _, m := range idRe.FindAllStringSubmatch(string(body), -1) {
+				if m[1] != "LT_DATA" {
+					t.Errorf("unexpected variable request %s", m[1])
+					continue
+				}
+				fmt.Fprintf(&sb, `<STPDA_ADT_VARIABLE><ID>LT_DATA</ID><NAME>LT_DATA</NAME><META_TYPE>table</META_TYPE><TABLE_LINES>%d</TABLE_LINES></STPDA_ADT_VARIABLE>`, lines)
+			}
+		case "getChildVariables":
+			var hier, vars strings.Builder
+			for _, m := range parentRe.FindAllStringSubmatch(string(body), -1) {
+				rm := rowRe.FindStringSubmatch(m[1])
+				if rm == nil {
+					t.Errorf("unexpected parent %s", m[1])
+					continue
+				}
+				status := "S"
+				if n, _ := strconv.Atoi(rm[1]); n%3 == 0 {
+					status = "E"
+				}
+				for _, col := range []struct{ name, value string }{{"ID", rm[1]}, {"STATUS", status}} {
+					childID := m[1] + "-" + col.name
+					fmt.Fprintf(&hier, `<STPDA_ADT_VARIABLE_HIERARCHY><PARENT_ID>%s</PARENT_ID><CHILD_ID>%s</CHILD_ID><CHILD_NAME>%s</CHILD_NAME></STPDA_ADT_VARIABLE_HIERARCHY>`, m[1], childID, col.name)
+					fmt.Fprintf(&vars, `<STPDA_ADT_VARIABLE><ID>%s</ID><NAME>%s</NAME><VALUE>%s</VALUE></STPDA_ADT_VARIABLE>`, childID, childID, col.value)
+				}
+			}
+			fmt.Fprintf(&sb, "<HIERARCHIES>%s</HIERARCHIES><VARIABLES>%s</VARIABLES>", hier.String(), vars.String())
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		sb.WriteString(`</DATA></asx:values></asx:abap>`)
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(sb.String()))
+	}))
+}
+
+func TestDebuggerReadTable_Paging(t *testing.T) {
+	server := newDebugTableServer(t, 250)
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"))
+
+	page, err := client.DebuggerReadTable(context.Background(), "lt_data", DebugTableReadOptions{Offset: 120, Limit: 10, Columns: []string{"id"}})
+	if err != nil {
+		t.Fatalf("DebuggerReadTable failed: %v", err)
+	}
+	if page.TotalLines != 250 {
+		t.Errorf("TotalLines = %d, want 250", page.TotalLines)
+	}
+	if len(page.Rows) != 10 {
+		t.Fatalf("got %d rows, want 10", len(page.Rows))
+	}
+	if page.Rows[0].Index != 121 || page.Rows[0].Values["ID"] != "121" {
+		t.Errorf("unexpected first row: %+v", page.Rows[0])
+	}
+	if _, ok := page.Rows[0].Values["STATUS"]; ok {
+		t.Error("unselected column STATUS returned")
+	}
+	if page.NextOffset != 130 || !page.HasMore {
+		t.Errorf("NextOffset = %d, HasMore = %v", page.NextOffset, page.HasMore)
+	}
+}
+
+func TestDebuggerReadTable_Filter(t *testing.T) {
+	server := newDebugTableServer(t, 250)
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"))
+
+	page, err := client.DebuggerReadTable(context.Background(), "LT_DATA", DebugTableReadOptions{Limit: 5, Filter: "STATUS = 'E' AND ID > 100"})
+	if err != nil {
+		t.Fatalf("DebuggerReadTable failed: %v", err)
+	}
+	want := []int{102, 105, 108, 111, 114}
+	if len(page.Rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(page.Rows), len(want))
+	}
+	for i, idx := range want {
+		if page.Rows[i].Index != idx {
+			t.Errorf("row %d: index %d, want %d", i, page.Rows[i].Index, idx)
+		}
+	}
+	if page.NextOffset != 114 {
+		t.Errorf("NextOffset = %d, want 114", page.NextOffset)
+	}
+	if len(page.Columns) != 2 {
+		t.Errorf("Columns = %v, want [ID STATUS]", page.Columns)
+	}
+
+	// Scan limit stops early without matches
+	page, err = client.DebuggerReadTable(context.Background(), "LT_DATA", DebugTableReadOptions{Filter: "STATUS = 'X'", MaxScan: 150})
+	if err != nil {
+		t.Fatalf("DebuggerReadTable failed: %v", err)
+	}
+	if len(page.Rows) != 0 || page.Scanned != 150 || page.NextOffset != 150 || !page.HasMore {
+		t.Errorf("unexpected page: rows=%d scanned=%d next=%d more=%v", len(page.Rows), page.Scanned, page.NextOffset, page.HasMore)
+	}
+
+	if _, err := client.DebuggerReadTable(context.Background(), "LT_DATA", DebugTableReadOptions{Filter: "MISSING = 1"}); err == nil {
+		t.Error("expected error for unknown filter column")
+	}
+}
+
+func TestReadTableParams(t *testing.T) {
+	params, err := readTableParams("lt_data", DebugTableReadOptions{
+		Offset:  10,
+		Limit:   5000,
+		Columns: []string{"id", " status"},
+		Filter:  "STATUS = 'E' AND ID > 100 OR NAME IS INITIAL",
+	})
+	if err != nil {
+		t.Fatalf("readTableParams failed: %v", err)
+	}
+
+	hexOf := func(s string) string { return strings.ToUpper(hex.EncodeToString([]byte(s))) }
+	want := map[string]any{
+		"table":   hexOf("LT_DATA"),
+		"offset":  10,
+		"limit":   1000,
+		"maxScan": 5000,
+		"columns": "ID,STATUS",
+		"terms":   3,
+
+		"t0_group": 0, "t0_left": hexOf("STATUS"), "t0_leftKind": "column",
+		"t0_op": "EQ", "t0_right": hexOf("E"), "t0_rightKind": "value",
+		"t1_group": 0, "t1_left": hexOf("ID"), "t1_leftKind": "column",
+		"t1_op": "GT", "t1_right": hexOf("100"), "t1_rightKind": "value",
+		"t2_group": 1, "t2_left": hexOf("NAME"), "t2_leftKind": "column",
+		"t2_op": "INITIAL", "t2_right": "", "t2_rightKind": "value",
+	}
+	for key, value := range want {
+		if params[key] != value {
+			t.Errorf("%s = %v, want %v", key, params[key], value)
+		}
+	}
+	if len(params) != len(want) {
+		t.Errorf("got %d params, want %d: %v", len(params), len(want), params)
+	}
+
+	for _, opts := range []DebugTableReadOptions{
+		{Filter: "STATUS ="},
+		{Columns: []string{"ID'"}},
+	} {
+		if _, err := readTableParams("LT_DATA", opts); err == nil {
+			t.Errorf("expected error for %+v", opts)
+		}
+	}
+}
+
+func TestParseReadTableResult(t *testing.T) {
+	params, _ := readTableParams("LT_DATA", DebugTableReadOptions{Offset: 3, Columns: []string{"ID", "STATUS"}})
+	data := `{"table":"LT_DATA","rows":[{"index":5,"values":{"ID":"5","STATUS":"E"}}],"scanned":4,"nextOffset":7,"ended":false}`
+	page, err := parseReadTableResult([]byte(data), params)
+	if err != nil {
+		t.Fatalf("parseReadTableResult failed: %v", err)
+	}
+	if page.Offset != 3 || page.NextOffset != 7 || page.Scanned != 4 || !page.HasMore || page.TotalLines != 0 {
+		t.Errorf("unexpected page: %+v", page)
+	}
+	if len(page.Columns) != 2 || len(page.Rows) != 1 || page.Rows[0].Values["STATUS"] != "E" {
+		t.Errorf("unexpected rows: columns=%v rows=%+v", page.Columns, page.Rows)
+	}
+
+	// The end of the table gives the line count
+	params, _ = readTableParams("LT_DATA", DebugTableReadOptions{})
+	page, err = parseReadTableResult([]byte(`{"table":"LT_DATA","rows":[],"scanned":0,"nextOffset":42,"ended":true}`), params)
+	if err != nil {
+		t.Fatalf("parseReadTableResult failed: %v", err)
+	}
+	if page.HasMore || page.TotalLines != 42 || page.Columns[0] != "TABLE_LINE" {
+		t.Errorf("unexpected page: %+v", page)
+	}
+}
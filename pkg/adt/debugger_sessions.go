@@ -0,0 +1,268 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Multi-Session Debugging ---
+//
+// A debugger attachment is bound to the HTTP session that performed the attach.
+// DebugSessionManager gives every attached debuggee its own client (and thus
+// its own server session), so several debuggees - e.g. a background job and a
+// dialog step - can be debugged at the same time.
+
+// After the first debuggee, ListenAll keeps polling for listenDrainWindow,
+// with at most maxListenDrain polls. A listen can report a debuggee that was
+// already seen (it stays waiting until attached) or nothing, so neither ends
+// the drain early.
+const (
+	listenDrainWindow = 5 * time.Second
+	maxListenDrain    = 20
+)
+
+// DebugSession is a debuggee attached through a DebugSessionManager.
+type DebugSession struct {
+	ID         string             `json:"id"`
+	Debuggee   Debuggee           `json:"debuggee"`
+	Attach     *DebugAttachResult `json:"attach,omitempty"`
+	AttachedAt time.Time          `json:"attachedAt"`
+
+	seq    int
+	client *Client
+}
+
+// Client returns the client bound to this debug session.
+func (s *DebugSession) Client() *Client {
+	return s.client
+}
+
+// DebugSessionManager tracks concurrently attached debug sessions by ID.
+type DebugSessionManager struct {
+	mu        sync.Mutex
+	listener  *Client
+	newClient func() *Client
+	sessions  map[string]*DebugSession
+	waiting   map[string]Debuggee
+	nextSeq   int
+}
+
+// NewDebugSessionManager creates a session manager that listens with client
+// and attaches every debuggee through a new session of the same configuration.
+func NewDebugSessionManager(client *Client) *DebugSessionManager {
+	return &DebugSessionManager{
+		listener:  client,
+		newClient: client.NewSessionClient,
+		sessions:  make(map[string]*DebugSession),
+		waiting:   make(map[string]Debuggee),
+	}
+}
+
+// NewSessionClient returns a client with the same configuration but its own
+// HTTP session (cookies, CSRF token, sap-contextid).
+func (c *Client) NewSessionClient() *Client {
+	cfg := *c.config
+	session := &Client{
+		transport: NewTransport(&cfg),
+		config:    &cfg,
+	}
+	session.cloud.Store(c.CloudMode())
+	return session
+}
+
+// ListenAll waits for a debuggee like DebuggerListen and then keeps listening
+// for listenDrainWindow to collect all other waiting debuggees, so none of
+// them is missed. Debuggees that are already attached are skipped.
+func (m *DebugSessionManager) ListenAll(ctx context.Context, opts *ListenOptions) ([]Debuggee, *ListenerConflict, error) {
+	result, err := m.listener.DebuggerListen(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if result.Conflict != nil {
+		return nil, result.Conflict, nil
+	}
+	if result.Debuggee == nil {
+		return nil, nil, nil
+	}
+
+	seen := map[string]bool{}
+	var debuggees []Debuggee
+	add := func(d *Debuggee) {
+		if d == nil || seen[d.ID] {
+			return
+		}
+		seen[d.ID] = true
+		if !m.isAttached(d.ID) {
+			debuggees = append(debuggees, *d)
+		}
+	}
+	add(result.Debuggee)
+
+	// Drain further waiting debuggees with short polls until the deadline
+	drainOpts := ListenOptions{}
+	if opts != nil {
+		drainOpts = *opts
+	}
+	drainOpts.TimeoutSeconds = 1
+	deadline := time.Now().Add(listenDrainWindow)
+	for i := 0; i < maxListenDrain && time.Now().Before(deadline); i++ {
+		next, err := m.listener.DebuggerListen(ctx, &drainOpts)
+		if err != nil || next.Conflict != nil {
+			break
+		}
+		add(next.Debuggee)
+	}
+
+	m.mu.Lock()
+	for _, d := range debuggees {
+		m.waiting[d.ID] = d
+	}
+	m.mu.Unlock()
+
+	return debuggees, nil, nil
+}
+
+// Attach attaches to a debuggee in a new session and returns it.
+func (m *DebugSessionManager) Attach(ctx context.Context, debuggeeID, user string) (*DebugSession, error) {
+	if debuggeeID == "" {
+		return nil, fmt.Errorf("debuggee ID is required")
+	}
+	if m.isAttached(debuggeeID) {
+		return nil, fmt.Errorf("debuggee %s is already attached", debuggeeID)
+	}
+
+	client := m.newClient()
+	result, err := client.DebuggerAttach(ctx, debuggeeID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextSeq++
+	session := &DebugSession{
+		ID:         fmt.Sprintf("S%d", m.nextSeq),
+		Debuggee:   m.waiting[debuggeeID],
+		Attach:     result,
+		AttachedAt: time.Now(),
+		seq:        m.nextSeq,
+		client:     client,
+	}
+	session.Debuggee.ID = debuggeeID
+	delete(m.waiting, debuggeeID)
+	m.sessions[session.ID] = session
+
+	return session, nil
+}
+
+// Session returns the session with the given ID. An empty ID selects the only
+// attached session and is an error if several sessions are attached.
+func (m *DebugSessionManager) Session(id string) (*DebugSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if id != "" {
+		session, ok := m.sessions[strings.ToUpper(id)]
+		if !ok {
+			return nil, fmt.Errorf("debug session %s not found", id)
+		}
+		return session, nil
+	}
+
+	switch len(m.sessions) {
+	case 0:
+		return nil, fmt.Errorf("no debug session attached")
+	case 1:
+		for _, session := range m.sessions {
+			return session, nil
+		}
+	}
+
+	var ids []string
+	for _, session := range m.sortedLocked() {
+		ids = append(ids, session.ID)
+	}
+	return nil, fmt.Errorf("%d debug sessions attached (%s) - specify the session", len(ids), strings.Join(ids, ", "))
+}
+
+// Client returns the client for session id. With an empty id and no attached
+// session, the listener client is returned for single-session compatibility.
+func (m *DebugSessionManager) Client(id string) (*Client, error) {
+	if id == "" && m.Count() == 0 {
+		return m.listener, nil
+	}
+	session, err := m.Session(id)
+	if err != nil {
+		return nil, err
+	}
+	return session.client, nil
+}
+
+// Detach terminates the debuggee of session id and forgets the session.
+func (m *DebugSessionManager) Detach(ctx context.Context, id string) (*DebugSession, error) {
+	session, err := m.Session(id)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	delete(m.sessions, session.ID)
+	m.mu.Unlock()
+
+	if err := session.client.DebuggerDetach(ctx); err != nil {
+		return session, err
+	}
+	return session, nil
+}
+
+// Sessions returns the attached sessions in attach order.
+func (m *DebugSessionManager) Sessions() []*DebugSession {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sortedLocked()
+}
+
+// Waiting returns debuggees reported by ListenAll that are not attached yet.
+func (m *DebugSessionManager) Waiting() []Debuggee {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]Debuggee, 0, len(m.waiting))
+	for _, d := range m.waiting {
+		result = append(result, d)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// Count returns the number of attached sessions.
+func (m *DebugSessionManager) Count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+func (m *DebugSessionManager) isAttached(debuggeeID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, session := range m.sessions {
+		if session.Debuggee.ID == debuggeeID {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *DebugSessionManager) sortedLocked() []*DebugSession {
+	result := make([]*DebugSession, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		result = append(result, session)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].seq < result[j].seq })
+	return result
+}
@@ -0,0 +1,143 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newDebugSessionServer mocks a listener that reports the debuggees of queue
+// in turn ("" is a poll without debuggee) and records which debuggee every
+// attach came from.
+func newDebugSessionServer(t *testing.T, queue ...string) (*httptest.Server, *[]string) {
+	var mu sync.Mutex
+	var attached []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sap/bc/adt/core/discovery" {
+			w.Header().Set("X-CSRF-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/sap/bc/adt/debugger/listeners":
+			if len(queue) == 0 {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			id := queue[0]
+			queue = queue[1:]
+			if id == "" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="utf-8"?><asx:abap xmlns:asx="http://www.sap.com/abapxml" version="1.0"><asx:values><DATA><STPDA_DEBUGGEE><DEBUGGEE_ID>%s</DEBUGGEE_ID><PRG_CURR>ZTEST</PRG_CURR><LINE_CURR>10</LINE_CURR></STPDA_DEBUGGEE></DATA></asx:values></asx:abap>`, id)
+		case r.URL.Path == "/sap/bc/adt/debugger" && r.URL.Query().Get("method") == "attach":
+			id := r.URL.Query().Get("debuggeeId")
+			attached = append(attached, id)
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="utf-8"?><dbg:attach xmlns:dbg="http://www.sap.com/adt/debugger" debugSessionId="%s" isSteppingPossible="true"></dbg:attach>`, strings.ToLower(id))
+		case r.URL.Path == "/sap/bc/adt/debugger" && r.URL.Query().Get("method") == "terminateDebuggee":
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?><dbg:step xmlns:dbg="http://www.sap.com/adt/debugger"></dbg:step>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server, &attached
+}
+
+func TestDebugSessionManager(t *testing.T) {
+	server, attached := newDebugSessionServer(t, "DBG_JOB", "DBG_DIALOG", "DBG_JOB")
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"))
+	manager := NewDebugSessionManager(client)
+	ctx := context.Background()
+
+	if c, err := manager.Client(""); err != nil || c != client {
+		t.Errorf("Client(\"\") without sessions should return the listener client")
+	}
+
+	debuggees, conflict, err := manager.ListenAll(ctx, &ListenOptions{TimeoutSeconds: 5})
+	if err != nil || conflict != nil {
+		t.Fatalf("ListenAll failed: %v %v", err, conflict)
+	}
+	if len(debuggees) != 2 || debuggees[0].ID != "DBG_JOB" || debuggees[1].ID != "DBG_DIALOG" {
+		t.Fatalf("unexpected debuggees: %+v", debuggees)
+	}
+	if len(manager.Waiting()) != 2 {
+		t.Errorf("expected 2 waiting debuggees, got %d", len(manager.Waiting()))
+	}
+
+	s1, err := manager.Attach(ctx, "DBG_JOB", "testuser")
+	if err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+	s2, err := manager.Attach(ctx, "DBG_DIALOG", "testuser")
+	if err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+	if s1.ID != "S1" || s2.ID != "S2" {
+		t.Errorf("unexpected session IDs %s, %s", s1.ID, s2.ID)
+	}
+	if s1.Client() == s2.Client() || s1.Client() == client {
+		t.Error("sessions must use separate clients")
+	}
+	if s1.Debuggee.Program != "ZTEST" || s2.Attach.DebugSessionID != "dbg_dialog" {
+		t.Errorf("unexpected session data: %+v %+v", s1.Debuggee, s2.Attach)
+	}
+	if len(*attached) != 2 || len(manager.Waiting()) != 0 {
+		t.Errorf("attached = %v, waiting = %d", *attached, len(manager.Waiting()))
+	}
+
+	if _, err := manager.Attach(ctx, "DBG_JOB", "testuser"); err == nil {
+		t.Error("expected error attaching the same debuggee twice")
+	}
+	if _, err := manager.Session(""); err == nil {
+		t.Error("expected error for ambiguous session")
+	}
+	if s, err := manager.Session("s2"); err != nil || s != s2 {
+		t.Errorf("Session(s2) = %v, %v", s, err)
+	}
+
+	if _, err := manager.Detach(ctx, "S1"); err != nil {
+		t.Fatalf("Detach failed: %v", err)
+	}
+	if s, err := manager.Session(""); err != nil || s != s2 {
+		t.Errorf("single remaining session should be selected by default, got %v, %v", s, err)
+	}
+	if sessions := manager.Sessions(); len(sessions) != 1 || sessions[0].ID != "S2" {
+		t.Errorf("unexpected sessions after detach: %d", len(sessions))
+	}
+}
+
+func TestDebugSessionManager_ListenAllDrain(t *testing.T) {
+	// The first debuggee is reported again and an empty poll comes before
+	// the second one: both must not end the drain
+	server, _ := newDebugSessionServer(t, "DBG_JOB", "DBG_JOB", "", "DBG_JOB", "DBG_DIALOG")
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"))
+	manager := NewDebugSessionManager(client)
+
+	debuggees, conflict, err := manager.ListenAll(context.Background(), &ListenOptions{TimeoutSeconds: 5})
+	if err != nil || conflict != nil {
+		t.Fatalf("ListenAll failed: %v %v", err, conflict)
+	}
+	if len(debuggees) != 2 || debuggees[0].ID != "DBG_JOB" || debuggees[1].ID != "DBG_DIALOG" {
+		t.Fatalf("unexpected debuggees: %+v", debuggees)
+	}
+	if len(manager.Waiting()) != 2 {
+		t.Errorf("expected 2 waiting debuggees, got %d", len(manager.Waiting()))
+	}
+}
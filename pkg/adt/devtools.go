@@ -212,6 +212,7 @@ func parseActivationResult(data []byte) (*ActivationResult, error) {
 		Entries []inactiveEntry `xml:"entry"`
 	}
 	type response struct {
+		Msgs     []msg           `xml:"msg"` // <chkl:messages> as root element
 		Messages messages        `xml:"messages"`
 		Inactive inactiveObjects `xml:"inactiveObjects"`
 	}
@@ -227,7 +228,7 @@ func parseActivationResult(data []byte) (*ActivationResult, error) {
 		return result, nil
 	}
 
-	for _, m := range resp.Messages.Msgs {
+	for _, m := range append(resp.Msgs, resp.Messages.Msgs...) {
 		result.Messages = append(result.Messages, ActivationResultMessage{
 			ObjDescr:       m.ObjDescr,
 			Type:           m.Type,
@@ -362,15 +363,19 @@ type ActivationFailed struct {
 // Lower number = activate first (interfaces before classes, etc.)
 func objectTypePriority(objType string) int {
 	priorities := map[string]int{
-		"DOMA/DD": 1, // Domains first
-		"DTEL/DE": 2, // Data elements
-		"TABL/DT": 3, // Tables/structures
-		"TTYP/TT": 4, // Table types
-		"INTF/OI": 5, // Interfaces before classes
-		"CLAS/OC": 6, // Classes
-		"FUGR/F":  7, // Function groups
-		"PROG/P":  8, // Programs
-		"DDLS/DF": 9, // CDS views
+		"DOMA/DD":  1,  // Domains first
+		"DTEL/DE":  2,  // Data elements
+		"TABL/DT":  3,  // Tables/structures
+		"TTYP/TT":  4,  // Table types
+		"INTF/OI":  5,  // Interfaces before classes
+		"CLAS/OC":  6,  // Classes
+		"FUGR/F":   7,  // Function groups
+		"PROG/P":   8,  // Programs
+		"DDLS/DF":  9,  // CDS views
+		"DCLS/DL":  10, // CDS access controls
+		"BDEF/BDO": 11, // Behavior definitions on their CDS views
+		"SRVD/SRV": 12, // Service definitions
+		"SRVB/SVB": 13, // Service bindings
 	}
 	if p, ok := priorities[objType]; ok {
 		return p
@@ -544,6 +549,7 @@ type UnitTestRunFlags struct {
 	Short     bool `json:"short"`     // Run short duration tests
 	Medium    bool `json:"medium"`    // Run medium duration tests
 	Long      bool `json:"long"`      // Run long duration tests
+	Coverage  bool `json:"coverage"`  // Measure code coverage
 }
 
 // DefaultUnitTestFlags returns the default test run configuration.
@@ -560,7 +566,10 @@ func DefaultUnitTestFlags() UnitTestRunFlags {
 
 // UnitTestResult represents the complete result of a unit test run.
 type UnitTestResult struct {
-	Classes []UnitTestClass `json:"classes"`
+	Classes       []UnitTestClass `json:"classes"`
+	CoverageURI   string          `json:"coverageUri,omitempty"` // Coverage measurement (coverage runs only)
+	Coverage      *CoverageReport `json:"coverage,omitempty"`
+	CoverageError string          `json:"coverageError,omitempty"` // Coverage requested but not read; the test results are valid
 }
 
 // UnitTestClass represents a test class result.
@@ -607,16 +616,34 @@ type UnitTestStackEntry struct {
 
 // RunUnitTests runs ABAP Unit tests for an object.
 // objectURL is the ADT URL of the object (e.g., "/sap/bc/adt/oo/classes/ZCL_TEST")
+// With flags.Coverage set, the coverage of the object is measured and returned
+// in the result; if it cannot be read, CoverageError is set instead.
 func (c *Client) RunUnitTests(ctx context.Context, objectURL string, flags *UnitTestRunFlags) (*UnitTestResult, error) {
+	return c.RunUnitTestsFor(ctx, []string{objectURL}, flags)
+}
+
+// RunUnitTestsFor runs ABAP Unit tests for a set of object references. Besides
+// object URLs, the references may be the URIs of single test classes or test
+// methods (UnitTestClass.URI, UnitTestMethod.URI or their NavigationURI), so
+// that only those tests are executed.
+func (c *Client) RunUnitTestsFor(ctx context.Context, uris []string, flags *UnitTestRunFlags) (*UnitTestResult, error) {
 	if flags == nil {
 		defaultFlags := DefaultUnitTestFlags()
 		flags = &defaultFlags
 	}
+	if len(uris) == 0 {
+		return nil, fmt.Errorf("no tests to run")
+	}
+
+	var refs strings.Builder
+	for _, uri := range uris {
+		fmt.Fprintf(&refs, "\n        <adtcore:objectReference adtcore:uri=\"%s\"/>", xmlEscape(uri))
+	}
 
 	body := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
 <aunit:runConfiguration xmlns:aunit="http://www.sap.com/adt/aunit">
   <external>
-    <coverage active="false"/>
+    <coverage active="%t"/>
   </external>
   <options>
     <uriType value="semantic"/>
@@ -627,15 +654,15 @@ func (c *Client) RunUnitTests(ctx context.Context, objectURL string, flags *Unit
   </options>
   <adtcore:objectSets xmlns:adtcore="http://www.sap.com/adt/core">
     <objectSet kind="inclusive">
-      <adtcore:objectReferences>
-        <adtcore:objectReference adtcore:uri="%s"/>
+      <adtcore:objectReferences>%s
       </adtcore:objectReferences>
     </objectSet>
   </adtcore:objectSets>
 </aunit:runConfiguration>`,
+		flags.Coverage,
 		flags.Harmless, flags.Dangerous, flags.Critical,
 		flags.Short, flags.Medium, flags.Long,
-		objectURL)
+		refs.String())
 
 	resp, err := c.transport.Request(ctx, "/sap/bc/adt/abapunit/testruns", &RequestOptions{
 		Method:      http.MethodPost,
@@ -647,7 +674,18 @@ func (c *Client) RunUnitTests(ctx context.Context, objectURL string, flags *Unit
 		return nil, fmt.Errorf("running unit tests: %w", err)
 	}
 
-	return parseUnitTestResult(resp.Body)
+	result, err := parseUnitTestResult(resp.Body)
+	if err != nil || !flags.Coverage {
+		return result, err
+	}
+	if result.CoverageURI == "" {
+		result.CoverageError = "unit test run returned no coverage measurement"
+		return result, nil
+	}
+	if result.Coverage, err = c.GetCoverage(ctx, result.CoverageURI, UnitTestObjectURLs(uris)); err != nil {
+		result.CoverageError = err.Error()
+	}
+	return result, nil
 }
 
 func parseUnitTestResult(data []byte) (*UnitTestResult, error) {
@@ -716,6 +754,11 @@ func parseUnitTestResult(data []byte) (*UnitTestResult, error) {
 		} `xml:"testClasses"`
 	}
 	type runResult struct {
+		External struct {
+			Coverage struct {
+				URI string `xml:"uri,attr"`
+			} `xml:"coverage"`
+		} `xml:"external"`
 		Programs []program `xml:"program"`
 	}
 
@@ -725,7 +768,8 @@ func parseUnitTestResult(data []byte) (*UnitTestResult, error) {
 	}
 
 	result := &UnitTestResult{
-		Classes: []UnitTestClass{},
+		Classes:     []UnitTestClass{},
+		CoverageURI: resp.External.Coverage.URI,
 	}
 
 	// Helper to convert alerts
@@ -970,20 +1014,30 @@ func (c *Client) GetATCCheckVariant(ctx context.Context, variant string) (string
 // worklistID is from GetATCCheckVariant, objectURL is the ADT URL of the object.
 // maxResults limits the number of findings returned (default 100).
 func (c *Client) CreateATCRun(ctx context.Context, worklistID string, objectURL string, maxResults int) (*ATCRunResult, error) {
+	return c.CreateATCRunForObjects(ctx, worklistID, []string{objectURL}, maxResults)
+}
+
+// CreateATCRunForObjects creates an ATC run over a set of objects.
+// maxResults limits the findings of the whole run.
+func (c *Client) CreateATCRunForObjects(ctx context.Context, worklistID string, objectURLs []string, maxResults int) (*ATCRunResult, error) {
 	if maxResults <= 0 {
 		maxResults = 100
 	}
 
+	var refs strings.Builder
+	for _, objectURL := range objectURLs {
+		fmt.Fprintf(&refs, "\n\t\t\t\t<adtcore:objectReference adtcore:uri=\"%s\"/>", xmlEscape(objectURL))
+	}
+
 	body := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
 <atc:run maximumVerdicts="%d" xmlns:atc="http://www.sap.com/adt/atc">
 	<objectSets xmlns:adtcore="http://www.sap.com/adt/core">
 		<objectSet kind="inclusive">
-			<adtcore:objectReferences>
-				<adtcore:objectReference adtcore:uri="%s"/>
+			<adtcore:objectReferences>%s
 			</adtcore:objectReferences>
 		</objectSet>
 	</objectSets>
-</atc:run>`, maxResults, objectURL)
+</atc:run>`, maxResults, refs.String())
 
 	resp, err := c.transport.Request(ctx, fmt.Sprintf("/sap/bc/adt/atc/runs?worklistId=%s", worklistID), &RequestOptions{
 		Method:      http.MethodPost,
@@ -0,0 +1,565 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// --- Post-Mortem Dump Analysis ---
+//
+// AnalyzeDump turns a short dump into something an agent can reason about:
+// the call stack with source context around every frame, the variables at the
+// crash point and an ExecutionRecording that can be saved to the history and
+// replayed with ForceReplay. If a post-mortem debuggee for the dump is
+// available, the live debugger stack and variables are used instead of what
+// the dump text contains.
+
+const (
+	dumpDefaultContextLines  = 5
+	dumpDefaultMaxFrames     = 10
+	dumpDefaultListenTimeout = 5
+)
+
+// DumpAnalysisOptions configures AnalyzeDump.
+type DumpAnalysisOptions struct {
+	ContextLines  int                  // Source lines before/after each frame line (default 5)
+	MaxFrames     int                  // Frames to fetch source for (default 10)
+	PostMortem    bool                 // Try to attach to a post-mortem debuggee of the dump
+	ListenTimeout int                  // Seconds to wait for the post-mortem debuggee (default 5)
+	User          string               // Debuggee user (default: client user)
+	Sessions      *DebugSessionManager // When set, the post-mortem session stays attached here
+}
+
+// DumpSourceLine is a single source line shown around a stack frame.
+type DumpSourceLine struct {
+	Line    int    `json:"line"`
+	Text    string `json:"text"`
+	Current bool   `json:"current,omitempty"` // The line the frame stopped at
+}
+
+// DumpFrameContext is a stack frame with the source around its line.
+type DumpFrameContext struct {
+	StackFrame
+	Source      []DumpSourceLine `json:"source,omitempty"`
+	SourceError string           `json:"sourceError,omitempty"`
+}
+
+// DumpAnalysis is the result of AnalyzeDump. Frames start at the crash point.
+type DumpAnalysis struct {
+	Dump            *DumpDetails       `json:"dump"`
+	Frames          []DumpFrameContext `json:"frames"`
+	Variables       []DumpVariable     `json:"variables,omitempty"` // Variables at the crash point
+	PostMortem      bool               `json:"postMortem"`          // Stack/variables come from a post-mortem session
+	PostMortemError string             `json:"postMortemError,omitempty"`
+	Session         string             `json:"session,omitempty"` // Attached post-mortem session ID
+	RecordingID     string             `json:"recordingId"`
+
+	// Recording holds the crash state; the last frame is the "dump" checkpoint.
+	Recording *ExecutionRecorder `json:"-"`
+}
+
+// AnalyzeDump fetches a short dump, resolves the source around each stack
+// frame and records the crash state as an ExecutionRecording checkpoint.
+// With PostMortem set, it listens briefly for a post-mortem debuggee of the
+// dump and reads the stack and variables from it. Post-mortem debuggees only
+// exist for dumps raised while a debug listener was active and are kept by
+// the system for a limited time; otherwise PostMortemError says why not.
+func (c *Client) AnalyzeDump(ctx context.Context, dumpID string, opts DumpAnalysisOptions) (*DumpAnalysis, error) {
+	if strings.TrimSpace(dumpID) == "" {
+		return nil, fmt.Errorf("dump ID is required")
+	}
+	if opts.ContextLines <= 0 {
+		opts.ContextLines = dumpDefaultContextLines
+	}
+	if opts.MaxFrames <= 0 {
+		opts.MaxFrames = dumpDefaultMaxFrames
+	}
+
+	dump, err := c.GetDump(ctx, dumpID)
+	if err != nil {
+		return nil, err
+	}
+	summary := *dump
+	summary.RawHTML = ""
+
+	analysis := &DumpAnalysis{Dump: &summary, Variables: dump.Variables}
+	frames := dump.StackTrace
+
+	if opts.PostMortem {
+		stack, vars, session, err := c.readPostMortem(ctx, dumpID, opts)
+		if err != nil {
+			analysis.PostMortemError = err.Error()
+		} else {
+			analysis.PostMortem = true
+			analysis.Session = session
+			if len(stack) > 0 {
+				frames = stack
+			}
+			analysis.Variables = vars
+		}
+	}
+
+	for i, frame := range frames {
+		fc := DumpFrameContext{StackFrame: frame}
+		if i < opts.MaxFrames && frame.Line > 0 {
+			fc.Source, err = c.dumpFrameSource(ctx, frame, opts.ContextLines)
+			if err != nil {
+				fc.SourceError = err.Error()
+			}
+		}
+		analysis.Frames = append(analysis.Frames, fc)
+	}
+
+	analysis.Recording = newDumpRecording(dump, analysis.Frames, analysis.Variables, analysis.PostMortem)
+	analysis.RecordingID = analysis.Recording.GetRecording().ID
+	return analysis, nil
+}
+
+// newDumpRecording records the stack from the outermost frame to the crash
+// point; the crash frame carries the variables and the "dump" checkpoint.
+func newDumpRecording(dump *DumpDetails, frames []DumpFrameContext, vars []DumpVariable, postMortem bool) *ExecutionRecorder {
+	program := dump.Program
+	if program == "" && len(frames) > 0 {
+		program = frames[0].Program
+	}
+
+	recorder := NewExecutionRecorder(dump.ID, program)
+	recording := recorder.GetRecording()
+	recording.Description = strings.TrimSpace(fmt.Sprintf("Dump %s %s", dump.Category, dump.Title))
+	recording.Tags = []string{"dump"}
+	if postMortem {
+		recording.Tags = append(recording.Tags, "postmortem")
+	}
+
+	for i := len(frames) - 1; i > 0; i-- {
+		recorder.RecordFrame(frames[i].location(), "stack", nil)
+	}
+
+	crash := CodeLocation{Program: dump.Program, Include: dump.Include, Line: dump.Line}
+	if len(frames) > 0 {
+		crash = frames[0].location()
+	}
+	values := make(map[string]VariableValue, len(vars))
+	for _, v := range vars {
+		values[v.Name] = VariableValue{Name: v.Name, Type: v.Type, Value: v.Value}
+	}
+	recorder.RecordFrame(crash, "dump", values)
+	recorder.AddCheckpoint("dump")
+	recorder.Complete()
+
+	return recorder
+}
+
+func (f DumpFrameContext) location() CodeLocation {
+	loc := CodeLocation{Program: f.Program, Include: f.Include, Line: f.Line}
+	for _, l := range f.Source {
+		if l.Current {
+			loc.Statement = strings.TrimSpace(l.Text)
+		}
+	}
+	return loc
+}
+
+// readPostMortem attaches to the post-mortem debuggee of dumpID and reads the
+// stack (crash point first) and the top-level variables.
+func (c *Client) readPostMortem(ctx context.Context, dumpID string, opts DumpAnalysisOptions) ([]StackFrame, []DumpVariable, string, error) {
+	manager := opts.Sessions
+	if manager == nil {
+		manager = NewDebugSessionManager(c)
+	}
+	user := opts.User
+	if user == "" {
+		user = c.config.Username
+	}
+	timeout := opts.ListenTimeout
+	if timeout <= 0 {
+		timeout = dumpDefaultListenTimeout
+	}
+
+	debuggees, conflict, err := manager.ListenAll(ctx, &ListenOptions{User: user, TimeoutSeconds: timeout})
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("listening for post-mortem debuggee: %w", err)
+	}
+	if conflict != nil {
+		return nil, nil, "", fmt.Errorf("listener conflict: %s", conflict.ConflictText)
+	}
+
+	var debuggee *Debuggee
+	for i := range debuggees {
+		if dumpMatchesDebuggee(dumpID, &debuggees[i]) {
+			debuggee = &debuggees[i]
+			break
+		}
+	}
+	if debuggee == nil {
+		return nil, nil, "", fmt.Errorf("no post-mortem debuggee available for dump %s", dumpID)
+	}
+
+	session, err := manager.Attach(ctx, debuggee.ID, user)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("attaching post-mortem debuggee: %w", err)
+	}
+	keep := opts.Sessions != nil
+	defer func() {
+		if !keep {
+			manager.Detach(ctx, session.ID)
+		}
+	}()
+
+	client := session.Client()
+	stack, err := client.DebuggerGetStack(ctx, false)
+	if err != nil {
+		keep = false
+		return nil, nil, "", fmt.Errorf("reading post-mortem stack: %w", err)
+	}
+	entries := append([]DebugStackEntry(nil), stack.Stack...)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].StackPosition > entries[j].StackPosition })
+
+	var frames []StackFrame
+	for _, e := range entries {
+		frames = append(frames, StackFrame{
+			Program:   e.ProgramName,
+			Include:   e.IncludeName,
+			Line:      e.Line,
+			EventType: e.EventType,
+			Event:     e.EventName,
+		})
+	}
+
+	var vars []DumpVariable
+	children, err := client.DebuggerGetChildVariables(ctx, []string{"@ROOT"})
+	if err != nil {
+		keep = false
+		return nil, nil, "", fmt.Errorf("reading post-mortem variables: %w", err)
+	}
+	for _, v := range childVariablesOf(children, "@ROOT") {
+		value := v.Value
+		if v.MetaType == DebugMetaTypeTable {
+			value = fmt.Sprintf("[%d lines]", v.TableLines)
+		}
+		vars = append(vars, DumpVariable{Name: v.Name, Value: value, Type: v.DeclaredTypeName})
+	}
+
+	sessionID := ""
+	if keep {
+		sessionID = session.ID
+	}
+	return frames, vars, sessionID, nil
+}
+
+// dumpMatchesDebuggee reports whether a post-mortem debuggee belongs to the
+// dump. dumpID may be a plain ID or a URI from ListDumps.
+func dumpMatchesDebuggee(dumpID string, d *Debuggee) bool {
+	if d.Kind != DebuggeeKindPostMortem && d.Kind != DebuggeeKindPostMortemDialog {
+		return false
+	}
+	key := dumpID
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		key = key[i+1:]
+	}
+	if unescaped, err := url.PathUnescape(key); err == nil {
+		key = unescaped
+	}
+	key = strings.ToUpper(key)
+
+	if d.DumpID != "" && strings.Contains(key, strings.ToUpper(d.DumpID)) {
+		return true
+	}
+	return d.DumpURI != "" && strings.Contains(strings.ToUpper(d.DumpURI), key)
+}
+
+// dumpFrameSource returns the source lines around the line of a stack frame.
+func (c *Client) dumpFrameSource(ctx context.Context, frame StackFrame, contextLines int) ([]DumpSourceLine, error) {
+	source, offset, err := c.frameIncludeSource(ctx, frame)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(source, "\r\n", "\n"), "\n"), "\n")
+	current := frame.Line + offset
+	if current < 1 || current > len(lines) {
+		return nil, fmt.Errorf("line %d is outside the source of %s", frame.Line, frame.Include)
+	}
+
+	from := current - contextLines
+	if from < 1 {
+		from = 1
+	}
+	to := current + contextLines
+	if to > len(lines) {
+		to = len(lines)
+	}
+
+	result := make([]DumpSourceLine, 0, to-from+1)
+	for n := from; n <= to; n++ {
+		result = append(result, DumpSourceLine{Line: n, Text: lines[n-1], Current: n == current})
+	}
+	return result, nil
+}
+
+// frameIncludeSource fetches the source holding a stack frame. Frame lines are
+// relative to the include; offset maps them to lines of the returned source
+// (class method includes are resolved within the class source).
+func (c *Client) frameIncludeSource(ctx context.Context, frame StackFrame) (string, int, error) {
+	program := strings.ToUpper(frame.Program)
+	include := strings.ToUpper(frame.Include)
+	if include == "" {
+		include = program
+	}
+
+	// Class pool: ZCL_FOO=====...CP with includes ZCL_FOO=====...CM001 etc.
+	if len(program) == 32 && strings.HasSuffix(program, "CP") {
+		className := strings.TrimRight(program[:30], "=")
+		section := strings.TrimPrefix(include, program[:30])
+
+		switch {
+		case strings.HasPrefix(section, "CM"):
+			source, err := c.GetClassSource(ctx, className)
+			if err != nil {
+				return "", 0, err
+			}
+			method := frame.Event
+			if i := strings.Index(method, "=>"); i >= 0 {
+				method = method[i+2:]
+			}
+			if method == "" {
+				return "", 0, fmt.Errorf("method of include %s is unknown", include)
+			}
+			start := findMethodLine(source, method)
+			if start == 0 {
+				return "", 0, fmt.Errorf("method %s not found in class %s", method, className)
+			}
+			return source, start - 1, nil
+		case section == "CCIMP":
+			source, err := c.GetClassInclude(ctx, className, ClassIncludeImplementations)
+			return source, 0, err
+		case section == "CCDEF":
+			source, err := c.GetClassInclude(ctx, className, ClassIncludeDefinitions)
+			return source, 0, err
+		case section == "CCMAC":
+			source, err := c.GetClassInclude(ctx, className, ClassIncludeMacros)
+			return source, 0, err
+		case section == "CCAU":
+			source, err := c.GetClassInclude(ctx, className, ClassIncludeTestClasses)
+			return source, 0, err
+		default:
+			return "", 0, fmt.Errorf("no source mapping for class include %s", include)
+		}
+	}
+
+	// Function module in a function group SAPL<group>
+	if strings.HasPrefix(program, "SAPL") && strings.EqualFold(frame.EventType, "FUNCTION") && frame.Event != "" {
+		source, err := c.GetFunction(ctx, frame.Event, strings.TrimPrefix(program, "SAPL"))
+		return source, 0, err
+	}
+
+	if include == program {
+		source, err := c.GetProgram(ctx, program)
+		return source, 0, err
+	}
+	source, err := c.GetInclude(ctx, include)
+	return source, 0, err
+}
+
+// findMethodLine returns the 1-based line of "METHOD <name>." or 0.
+func findMethodLine(source, method string) int {
+	re := regexp.MustCompile(`(?i)^\s*METHOD\s+` + regexp.QuoteMeta(method) + `\s*\.`)
+	for i, line := range strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n") {
+		if re.MatchString(line) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// --- Dump text parsing ---
+
+var (
+	dumpBlockTagRe = regexp.MustCompile(`(?i)<\s*(br|/p|/div|/tr|/h[1-6]|/li|/pre|/table)\b[^>]*>`)
+	dumpCellTagRe  = regexp.MustCompile(`(?i)<\s*/t[dh]\s*>`)
+	dumpTagRe      = regexp.MustCompile(`(?s)<[^>]*>`)
+	dumpScriptRe   = regexp.MustCompile(`(?is)<(script|style|title)[^>]*>.*?</(script|style|title)>`)
+
+	// "   2 METHOD  ZCL_X=====CP  ZCL_X=====CM001  12  [ZCL_X=>RUN]"
+	dumpCallRe = regexp.MustCompile(`^\s*(\d+)\s+(METHOD|FUNCTION|FORM|EVENT|MODULE \(PBO\)|MODULE \(PAI\)|MODULE)\s+(\S+)\s+(\S+)\s+(\d+)\s*(.*)$`)
+
+	// "... termination point in line 5 of the (Include) program "ZTEST"."
+	dumpTerminationRe = regexp.MustCompile(`(?i)in line (\d+)\s+of the (?:\(Include\) )?program "([^"]+)"`)
+
+	dumpHeaderKeys = map[string]string{
+		"category":              "category",
+		"runtime errors":        "runtimeError",
+		"except.":               "exception",
+		"exception":             "exception",
+		"date and time":         "dateTime",
+		"abap program":          "program",
+		"application component": "component",
+	}
+)
+
+// dumpText converts the dump HTML into plain text lines.
+func dumpText(data string) []string {
+	text := dumpScriptRe.ReplaceAllString(data, "")
+	text = dumpBlockTagRe.ReplaceAllString(text, "\n")
+	text = dumpCellTagRe.ReplaceAllString(text, "  ")
+	text = dumpTagRe.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = strings.ReplaceAll(text, " ", " ")
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// parseDumpText extracts header fields, the active calls and the variables
+// of the crash frame from the dump text. The layout follows ST22; sections
+// that cannot be recognized are left empty.
+func parseDumpText(details *DumpDetails, lines []string) {
+	if details.ErrorDetails == nil {
+		details.ErrorDetails = make(map[string]string)
+	}
+
+	type numberedFrame struct {
+		no    int
+		frame StackFrame
+	}
+	var calls []numberedFrame
+	section := ""
+	varFrames := 0 // "No. ... Ty." blocks seen in the variables section
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		switch lower := strings.ToLower(trimmed); {
+		case lower == "short text":
+			if next := nextDumpLine(lines, i); next != "" {
+				details.ErrorDetails["shortText"] = next
+			}
+			section = ""
+			continue
+		case strings.HasPrefix(lower, "active calls"):
+			section = "calls"
+			continue
+		case strings.HasPrefix(lower, "chosen variables"):
+			section = "variables"
+			continue
+		case strings.HasPrefix(lower, "internal notes"), strings.HasPrefix(lower, "list of abap programs"),
+			strings.HasPrefix(lower, "directory of application tables"), strings.HasPrefix(lower, "application calls"):
+			section = ""
+			continue
+		}
+
+		if m := dumpTerminationRe.FindStringSubmatch(trimmed); m != nil && details.Line == 0 {
+			details.Line, _ = strconv.Atoi(m[1])
+			details.Include = m[2]
+		}
+
+		switch section {
+		case "calls":
+			m := dumpCallRe.FindStringSubmatch(trimmed)
+			if m == nil {
+				continue
+			}
+			no, _ := strconv.Atoi(m[1])
+			lineNo, _ := strconv.Atoi(m[5])
+			frame := StackFrame{Program: m[3], Include: m[4], Line: lineNo, EventType: m[2], Event: strings.TrimSpace(m[6])}
+			if frame.Event == "" {
+				if next := nextDumpLine(lines, i); next != "" && dumpCallRe.FindStringSubmatch(next) == nil && !strings.HasPrefix(next, "---") {
+					frame.Event = next
+					i = skipDumpLine(lines, i)
+				}
+			}
+			calls = append(calls, numberedFrame{no: no, frame: frame})
+		case "variables":
+			if strings.HasPrefix(trimmed, "No.") {
+				varFrames++
+				continue
+			}
+			// Only the first block belongs to the crash frame
+			if varFrames > 1 || !dumpVariableNameRe.MatchString(trimmed) {
+				continue
+			}
+			v := DumpVariable{Name: trimmed}
+			if next := nextDumpLine(lines, i); next != "" && !dumpVariableNameRe.MatchString(next) && !strings.HasPrefix(next, "No.") {
+				v.Value = next
+				i = skipDumpLine(lines, i)
+			}
+			details.Variables = append(details.Variables, v)
+		default:
+			parseDumpHeaderLine(details, trimmed)
+		}
+	}
+
+	sort.SliceStable(calls, func(i, j int) bool { return calls[i].no > calls[j].no })
+	for _, c := range calls {
+		details.StackTrace = append(details.StackTrace, c.frame)
+	}
+
+	if len(details.StackTrace) > 0 {
+		top := details.StackTrace[0]
+		if details.Program == "" {
+			details.Program = top.Program
+		}
+		if details.Include == "" || details.Line == 0 {
+			details.Include = top.Include
+			details.Line = top.Line
+		}
+	}
+	if details.Category == "" {
+		details.Category = details.ErrorDetails["runtimeError"]
+	}
+	if details.ExceptionType == "" {
+		details.ExceptionType = details.ErrorDetails["exception"]
+	}
+	if len(details.ErrorDetails) == 0 {
+		details.ErrorDetails = nil
+	}
+}
+
+// parseDumpHeaderLine reads "Runtime Errors   COMPUTE_INT_ZERODIVIDE" style lines.
+func parseDumpHeaderLine(details *DumpDetails, line string) {
+	for label, key := range dumpHeaderKeys {
+		if len(line) <= len(label) || !strings.EqualFold(line[:len(label)], label) {
+			continue
+		}
+		rest := line[len(label):]
+		if rest[0] != ' ' && rest[0] != '\t' {
+			continue
+		}
+		if value := strings.TrimSpace(rest); value != "" {
+			if _, exists := details.ErrorDetails[key]; !exists {
+				details.ErrorDetails[key] = value
+			}
+		}
+		return
+	}
+}
+
+var dumpVariableNameRe = regexp.MustCompile(`^[A-Z_<%][A-Z0-9_<>\-~=%/\[\]]*$`)
+
+// nextDumpLine returns the next non-empty line after index i.
+func nextDumpLine(lines []string, i int) string {
+	for j := i + 1; j < len(lines); j++ {
+		if t := strings.TrimSpace(lines[j]); t != "" {
+			return t
+		}
+	}
+	return ""
+}
+
+// skipDumpLine returns the index of the next non-empty line after i.
+func skipDumpLine(lines []string, i int) int {
+	for j := i + 1; j < len(lines); j++ {
+		if strings.TrimSpace(lines[j]) != "" {
+			return j
+		}
+	}
+	return i
+}
@@ -0,0 +1,171 @@
+package adt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testDumpHTML = `<html><head><title>COMPUTE_INT_ZERODIVIDE</title></head><body>
+<h2>Short Text</h2>
+<p>Division by 0 (type I or P)</p>
+<pre>
+Category               ABAP Programming Error
+Runtime Errors         COMPUTE_INT_ZERODIVIDE
+Except.                CX_SY_ZERODIVIDE
+</pre>
+<h2>Active Calls/Events</h2>
+<pre>
+No.   Ty.          Program                             Include                             Line
+      Name
+----------------------------------------------------------------------------------------------------
+    2 METHOD       ZCL_DUMP_DEMO=================CP    ZCL_DUMP_DEMO=================CM001    3
+      ZCL_DUMP_DEMO=&gt;DIVIDE
+    1 EVENT        ZDUMP_DEMO                          ZDUMP_DEMO                             4
+      START-OF-SELECTION
+</pre>
+<h2>Chosen Variables</h2>
+<pre>
+Name
+    Val.
+----------------------------------------------------------------------------------------------------
+No.       2 Ty.          METHOD
+Name  ZCL_DUMP_DEMO=&gt;DIVIDE
+----------------------------------------------------------------------------------------------------
+IV_A
+    10
+IV_B
+    0
+No.       1 Ty.          EVENT
+Name  START-OF-SELECTION
+----------------------------------------------------------------------------------------------------
+LV_RESULT
+    0
+</pre>
+</body></html>`
+
+const testDumpClassSource = `CLASS zcl_dump_demo DEFINITION PUBLIC.
+  PUBLIC SECTION.
+    METHODS divide IMPORTING iv_a TYPE i iv_b TYPE i RETURNING VALUE(rv) TYPE i.
+ENDCLASS.
+
+CLASS zcl_dump_demo IMPLEMENTATION.
+  METHOD divide.
+    " divide
+    rv = iv_a / iv_b.
+  ENDMETHOD.
+ENDCLASS.`
+
+func TestParseDumpDetails_Text(t *testing.T) {
+	details, err := parseDumpDetails([]byte(testDumpHTML), "DUMP1")
+	if err != nil {
+		t.Fatalf("parseDumpDetails failed: %v", err)
+	}
+
+	if details.Title != "COMPUTE_INT_ZERODIVIDE" {
+		t.Errorf("Title = %q", details.Title)
+	}
+	if details.Category != "COMPUTE_INT_ZERODIVIDE" || details.ExceptionType != "CX_SY_ZERODIVIDE" {
+		t.Errorf("Category = %q, ExceptionType = %q", details.Category, details.ExceptionType)
+	}
+	if details.ErrorDetails["shortText"] != "Division by 0 (type I or P)" {
+		t.Errorf("shortText = %q", details.ErrorDetails["shortText"])
+	}
+
+	if len(details.StackTrace) != 2 {
+		t.Fatalf("got %d frames, want 2: %+v", len(details.StackTrace), details.StackTrace)
+	}
+	top := details.StackTrace[0]
+	if top.EventType != "METHOD" || top.Event != "ZCL_DUMP_DEMO=>DIVIDE" || top.Line != 3 {
+		t.Errorf("unexpected crash frame: %+v", top)
+	}
+	if details.StackTrace[1].Event != "START-OF-SELECTION" {
+		t.Errorf("unexpected caller frame: %+v", details.StackTrace[1])
+	}
+	if details.Program != "ZCL_DUMP_DEMO=================CP" || details.Line != 3 {
+		t.Errorf("Program = %q, Line = %d", details.Program, details.Line)
+	}
+
+	if len(details.Variables) != 2 || details.Variables[0].Name != "IV_A" || details.Variables[1].Value != "0" {
+		t.Errorf("unexpected variables: %+v", details.Variables)
+	}
+}
+
+func TestDumpMatchesDebuggee(t *testing.T) {
+	d := &Debuggee{Kind: DebuggeeKindPostMortem, DumpID: "20251205_123456_DEV"}
+	if !dumpMatchesDebuggee("/sap/bc/adt/vit/runtime/dumps/20251205_123456_DEV", d) {
+		t.Error("expected dump URI to match debuggee")
+	}
+	if dumpMatchesDebuggee("20251205_999999_DEV", d) {
+		t.Error("unexpected match for other dump")
+	}
+	d.Kind = DebuggeeKindDebuggee
+	if dumpMatchesDebuggee("20251205_123456_DEV", d) {
+		t.Error("regular debuggees must not match")
+	}
+}
+
+func TestAnalyzeDump(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+		case "/sap/bc/adt/runtime/dump/DUMP1":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(testDumpHTML))
+		case "/sap/bc/adt/oo/classes/ZCL_DUMP_DEMO/source/main":
+			w.Write([]byte(testDumpClassSource))
+		case "/sap/bc/adt/programs/programs/ZDUMP_DEMO/source/main":
+			w.Write([]byte("REPORT zdump_demo.\n\nSTART-OF-SELECTION.\n  DATA(lv_result) = zcl_dump_demo=>divide( iv_a = 10 iv_b = 0 ).\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"))
+
+	analysis, err := client.AnalyzeDump(context.Background(), "DUMP1", DumpAnalysisOptions{ContextLines: 1})
+	if err != nil {
+		t.Fatalf("AnalyzeDump failed: %v", err)
+	}
+	if analysis.Dump.RawHTML != "" {
+		t.Error("raw HTML should not be part of the analysis")
+	}
+	if len(analysis.Frames) != 2 {
+		t.Fatalf("got %d frames, want 2", len(analysis.Frames))
+	}
+
+	// Method include line 3 maps to class source line 9
+	crash := analysis.Frames[0]
+	if crash.SourceError != "" {
+		t.Fatalf("source error: %s", crash.SourceError)
+	}
+	if len(crash.Source) != 3 || crash.Source[1].Line != 9 || !crash.Source[1].Current {
+		t.Fatalf("unexpected crash source: %+v", crash.Source)
+	}
+	if crash.Source[1].Text != "    rv = iv_a / iv_b." {
+		t.Errorf("crash line = %q", crash.Source[1].Text)
+	}
+	if caller := analysis.Frames[1]; len(caller.Source) != 2 || !caller.Source[1].Current {
+		t.Errorf("unexpected caller source: %+v (%s)", caller.Source, caller.SourceError)
+	}
+
+	recording := analysis.Recording.GetRecording()
+	if recording.TotalSteps != 2 || recording.Checkpoints["dump"] != 2 || !recording.IsComplete {
+		t.Fatalf("unexpected recording: steps=%d checkpoints=%v", recording.TotalSteps, recording.Checkpoints)
+	}
+	last := recording.Frames[1]
+	if last.StepType != "dump" || last.Location.Statement != "rv = iv_a / iv_b." {
+		t.Errorf("unexpected crash frame: %+v", last)
+	}
+	vars := last.Variables
+	if vars == nil {
+		vars = last.VariableDelta
+	}
+	if vars["IV_B"].Value != "0" {
+		t.Errorf("IV_B = %v, want 0", vars["IV_B"].Value)
+	}
+}
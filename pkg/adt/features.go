@@ -3,12 +3,16 @@ package adt
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 // FeatureID identifies a specific optional feature that can be probed
@@ -27,8 +31,44 @@ const (
 	FeatureTransport FeatureID = "transport"
 	// FeatureHANA indicates HANA database (required for some AMDP features)
 	FeatureHANA FeatureID = "hana"
+	// FeatureRelease reports the ABAP release and support package (SAP_BASIS)
+	FeatureRelease FeatureID = "release"
+	// FeatureCloud indicates an ABAP Cloud system (BTP ABAP Environment, S/4HANA Cloud);
+	// available means the ABAP Cloud restrictions apply
+	FeatureCloud FeatureID = "abap_cloud"
+	// FeatureZADTVSP indicates a compatible ZADT_VSP WebSocket handler is installed
+	FeatureZADTVSP FeatureID = "zadt_vsp"
+	// FeatureATC indicates the ABAP Test Cockpit is available
+	FeatureATC FeatureID = "atc"
+	// FeatureRefactoring indicates the ADT refactoring API is available
+	FeatureRefactoring FeatureID = "refactoring"
+	// FeatureCoverage indicates ABAP Unit code coverage is available
+	FeatureCoverage FeatureID = "coverage"
+	// FeatureEnhancements indicates the enhancement framework is available
+	FeatureEnhancements FeatureID = "enhancements"
 )
 
+// AllFeatures lists the probed features in probe order
+var AllFeatures = []FeatureID{
+	FeatureHANA, // Probe first - other features may depend on it
+	FeatureRelease,
+	FeatureCloud,
+	FeatureAbapGit,
+	FeatureRAP,
+	FeatureAMDP,
+	FeatureUI5,
+	FeatureTransport,
+	FeatureZADTVSP,
+	FeatureATC,
+	FeatureRefactoring,
+	FeatureCoverage,
+	FeatureEnhancements,
+}
+
+// ZADTVSPMinVersion is the oldest ZADT_VSP handler the WebSocket tools work
+// with; InstallZADTVSP deploys the current one.
+const ZADTVSPMinVersion = "2.3.0"
+
 // FeatureMode controls how a feature is enabled
 type FeatureMode string
 
@@ -43,11 +83,14 @@ const (
 
 // FeatureStatus represents the probed status of a feature
 type FeatureStatus struct {
-	ID        FeatureID `json:"id"`
-	Available bool      `json:"available"`
+	ID        FeatureID   `json:"id"`
+	Available bool        `json:"available"`
 	Mode      FeatureMode `json:"mode"`
-	Message   string    `json:"message,omitempty"`
-	ProbedAt  time.Time `json:"probed_at,omitempty"`
+	Version   string      `json:"version,omitempty"` // Release or component version, if the probe reports one
+	Message   string      `json:"message,omitempty"`
+	ProbedAt  time.Time   `json:"probed_at,omitempty"`
+	Failed    bool        `json:"failed,omitempty"` // The probe itself failed; availability is unknown
+	Cached    bool        `json:"cached,omitempty"` // Taken from the capability profile
 }
 
 // FeatureConfig controls which optional features are enabled
@@ -64,17 +107,35 @@ type FeatureConfig struct {
 	UI5 FeatureMode
 	// Transport controls CTS transport tools (default: auto)
 	Transport FeatureMode
+	// Cloud controls ABAP Cloud detection (default: auto)
+	Cloud FeatureMode
+	// ZADTVSP controls the ZADT_VSP WebSocket tools (default: auto)
+	ZADTVSP FeatureMode
+	// ATC controls ATC tools (default: auto)
+	ATC FeatureMode
+	// Refactoring controls server-side refactoring tools (default: auto)
+	Refactoring FeatureMode
+	// Coverage controls ABAP Unit coverage detection (default: auto)
+	Coverage FeatureMode
+	// Enhancements controls enhancement framework detection (default: auto)
+	Enhancements FeatureMode
 }
 
 // DefaultFeatureConfig returns default feature configuration (all auto-detect)
 func DefaultFeatureConfig() FeatureConfig {
 	return FeatureConfig{
-		HANA:      FeatureModeAuto,
-		AbapGit:   FeatureModeAuto,
-		RAP:       FeatureModeAuto,
-		AMDP:      FeatureModeAuto,
-		UI5:       FeatureModeAuto,
-		Transport: FeatureModeAuto,
+		HANA:         FeatureModeAuto,
+		AbapGit:      FeatureModeAuto,
+		RAP:          FeatureModeAuto,
+		AMDP:         FeatureModeAuto,
+		UI5:          FeatureModeAuto,
+		Transport:    FeatureModeAuto,
+		Cloud:        FeatureModeAuto,
+		ZADTVSP:      FeatureModeAuto,
+		ATC:          FeatureModeAuto,
+		Refactoring:  FeatureModeAuto,
+		Coverage:     FeatureModeAuto,
+		Enhancements: FeatureModeAuto,
 	}
 }
 
@@ -93,6 +154,18 @@ func (f *FeatureConfig) GetMode(id FeatureID) FeatureMode {
 		return f.UI5
 	case FeatureTransport:
 		return f.Transport
+	case FeatureCloud:
+		return f.Cloud
+	case FeatureZADTVSP:
+		return f.ZADTVSP
+	case FeatureATC:
+		return f.ATC
+	case FeatureRefactoring:
+		return f.Refactoring
+	case FeatureCoverage:
+		return f.Coverage
+	case FeatureEnhancements:
+		return f.Enhancements
 	default:
 		return FeatureModeAuto
 	}
@@ -100,11 +173,14 @@ func (f *FeatureConfig) GetMode(id FeatureID) FeatureMode {
 
 // FeatureProber probes SAP system for available features
 type FeatureProber struct {
-	client  *Client
-	config  FeatureConfig
-	cache   map[FeatureID]*FeatureStatus
-	mu      sync.RWMutex
-	verbose bool
+	client     *Client
+	config     FeatureConfig
+	cache      map[FeatureID]*FeatureStatus
+	mu         sync.RWMutex
+	verbose    bool
+	store      *CapabilityProfileStore
+	profile    *CapabilityProfile
+	components []InstalledComponent // Shared by the release and ABAP Cloud probes
 }
 
 // NewFeatureProber creates a new feature prober
@@ -117,22 +193,60 @@ func NewFeatureProber(client *Client, config FeatureConfig, verbose bool) *Featu
 	}
 }
 
-// ProbeAll probes all features and returns their status
-func (p *FeatureProber) ProbeAll(ctx context.Context) map[FeatureID]*FeatureStatus {
-	features := []FeatureID{
-		FeatureHANA,     // Probe first - other features may depend on it
-		FeatureAbapGit,
-		FeatureRAP,
-		FeatureAMDP,
-		FeatureUI5,
-		FeatureTransport,
+// UseProfile makes the prober reuse the fresh entries of the capability
+// profile of its system in store, and record new probe results there. An
+// unreadable profile is reported and replaced by the next probe results.
+func (p *FeatureProber) UseProfile(store *CapabilityProfileStore) error {
+	profile, err := store.Load(capabilitySystem(p.client.config))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.store = store
+	p.profile = profile
+	return err
+}
+
+// ProfilePath returns the capability profile file, or "" without profile.
+func (p *FeatureProber) ProfilePath() string {
+	if p.store == nil {
+		return ""
 	}
+	return p.store.Path(capabilitySystem(p.client.config))
+}
+
+// Refresh drops all probe results, including the capability profile entries,
+// so that the next probe asks the system again.
+func (p *FeatureProber) Refresh() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cache = make(map[FeatureID]*FeatureStatus)
+	p.profile = nil
+	p.components = nil
+}
 
+// ProbeAll probes all features and returns their status. New results are
+// written to the capability profile.
+func (p *FeatureProber) ProbeAll(ctx context.Context) map[FeatureID]*FeatureStatus {
 	results := make(map[FeatureID]*FeatureStatus)
-	for _, id := range features {
+	for _, id := range AllFeatures {
 		status := p.Probe(ctx, id)
 		results[id] = status
 	}
+	p.saveProfile(results)
+	return results
+}
+
+// Known returns the status of the features that is known without asking the
+// system: forced modes, earlier probes and fresh profile entries.
+func (p *FeatureProber) Known() map[FeatureID]*FeatureStatus {
+	results := make(map[FeatureID]*FeatureStatus)
+	for _, id := range AllFeatures {
+		p.mu.RLock()
+		_, probed := p.cache[id]
+		p.mu.RUnlock()
+		if probed || p.config.GetMode(id) == FeatureModeOn || p.config.GetMode(id) == FeatureModeOff || p.profileStatus(id) != nil {
+			results[id] = p.Probe(context.Background(), id)
+		}
+	}
 	return results
 }
 
@@ -168,8 +282,10 @@ func (p *FeatureProber) Probe(ctx context.Context, id FeatureID) *FeatureStatus
 			ProbedAt:  time.Now(),
 		}
 	default: // FeatureModeAuto
-		status = p.probeFeature(ctx, id)
-		status.Mode = mode
+		if status = p.profileStatus(id); status == nil {
+			status = p.probeFeature(ctx, id)
+		}
+		status.Mode = FeatureModeAuto
 	}
 
 	// Cache result
@@ -193,6 +309,63 @@ func (p *FeatureProber) IsAvailable(ctx context.Context, id FeatureID) bool {
 	return p.Probe(ctx, id).Available
 }
 
+// profileStatus returns a copy of the fresh profile entry of a feature.
+func (p *FeatureProber) profileStatus(id FeatureID) *FeatureStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.profile == nil || !p.store.Fresh(p.profile.Features[id]) {
+		return nil
+	}
+	status := *p.profile.Features[id]
+	status.Cached = true
+	return &status
+}
+
+// saveProfile records the probed (not forced) statuses in the capability
+// profile, if any of them is new. Failed probes are kept next to successful
+// ones, so they are not repeated on every start; a profile in which every
+// probe failed (e.g. the system was not reachable) is never saved.
+func (p *FeatureProber) saveProfile(results map[FeatureID]*FeatureStatus) {
+	p.mu.Lock()
+	if p.store == nil {
+		p.mu.Unlock()
+		return
+	}
+	profile := &CapabilityProfile{
+		System:   capabilitySystem(p.client.config),
+		Features: make(map[FeatureID]*FeatureStatus),
+	}
+	changed, reached := false, false
+	for id, status := range results {
+		if status.Mode != FeatureModeAuto {
+			continue
+		}
+		stored := *status
+		stored.Cached = false
+		profile.Features[id] = &stored
+		if stored.ProbedAt.After(profile.ProbedAt) {
+			profile.ProbedAt = stored.ProbedAt
+		}
+		if !status.Failed {
+			reached = true
+			changed = changed || !status.Cached
+		}
+	}
+	changed = changed && reached
+	if changed {
+		p.profile = profile
+	}
+	store := p.store
+	p.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	if err := store.Save(profile); err != nil && p.verbose {
+		fmt.Fprintf(LogOutput, "[feature] %v\n", err)
+	}
+}
+
 // probeFeature performs the actual probe for a specific feature
 func (p *FeatureProber) probeFeature(ctx context.Context, id FeatureID) *FeatureStatus {
 	status := &FeatureStatus{
@@ -214,6 +387,20 @@ func (p *FeatureProber) probeFeature(ctx context.Context, id FeatureID) *Feature
 		status.Available, status.Message, err = p.probeUI5(ctx)
 	case FeatureTransport:
 		status.Available, status.Message, err = p.probeTransport(ctx)
+	case FeatureRelease:
+		status.Available, status.Version, status.Message, err = p.probeRelease(ctx)
+	case FeatureCloud:
+		status.Available, status.Version, status.Message, err = p.probeCloud(ctx)
+	case FeatureZADTVSP:
+		status.Available, status.Version, status.Message, err = p.probeZADTVSP(ctx)
+	case FeatureATC:
+		status.Available, status.Message, err = p.probeEndpoint(ctx, "/sap/bc/adt/atc/runs", "ATC")
+	case FeatureRefactoring:
+		status.Available, status.Message, err = p.probeEndpoint(ctx, "/sap/bc/adt/refactorings", "refactoring API")
+	case FeatureCoverage:
+		status.Available, status.Message, err = p.probeEndpoint(ctx, "/sap/bc/adt/runtime/traces/coverage/measurements", "code coverage")
+	case FeatureEnhancements:
+		status.Available, status.Message, err = p.probeEndpoint(ctx, "/sap/bc/adt/enhancements/enhoxhh", "enhancement framework")
 	default:
 		status.Available = false
 		status.Message = "unknown feature"
@@ -221,6 +408,7 @@ func (p *FeatureProber) probeFeature(ctx context.Context, id FeatureID) *Feature
 
 	if err != nil {
 		status.Available = false
+		status.Failed = true
 		status.Message = fmt.Sprintf("probe failed: %v", err)
 	}
 
@@ -293,6 +481,9 @@ func (p *FeatureProber) probeRAP(ctx context.Context) (bool, string, error) {
 func (p *FeatureProber) probeAMDP(ctx context.Context) (bool, string, error) {
 	// AMDP requires HANA - check that first
 	hanaStatus := p.Probe(ctx, FeatureHANA)
+	if hanaStatus.Failed {
+		return false, "", fmt.Errorf("HANA check failed: %s", hanaStatus.Message)
+	}
 	if !hanaStatus.Available {
 		return false, "AMDP requires HANA database", nil
 	}
@@ -355,19 +546,143 @@ func (p *FeatureProber) probeTransport(ctx context.Context) (bool, string, error
 	return false, "CTS not responding", nil
 }
 
+// installedComponents returns the installed software components, read once
+func (p *FeatureProber) installedComponents(ctx context.Context) ([]InstalledComponent, error) {
+	p.mu.RLock()
+	components := p.components
+	p.mu.RUnlock()
+	if components != nil {
+		return components, nil
+	}
+
+	components, err := p.client.GetInstalledComponents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	p.components = components
+	p.mu.Unlock()
+	return components, nil
+}
+
+// probeRelease reads the ABAP release and support package from SAP_BASIS
+func (p *FeatureProber) probeRelease(ctx context.Context) (bool, string, string, error) {
+	components, err := p.installedComponents(ctx)
+	if err != nil {
+		return false, "", "", err
+	}
+	for _, comp := range components {
+		if strings.EqualFold(comp.Name, "SAP_BASIS") {
+			version := comp.Release
+			if comp.SupportPack != "" {
+				version += " SP " + comp.SupportPack
+			}
+			return true, version, "SAP_BASIS " + version, nil
+		}
+	}
+	return false, "", "SAP_BASIS not among the installed components", nil
+}
+
+// probeCloud checks for an ABAP Cloud system, which has the SAP_CLOUD
+// component and allows development with released APIs only
+func (p *FeatureProber) probeCloud(ctx context.Context) (bool, string, string, error) {
+	components, err := p.installedComponents(ctx)
+	if err != nil {
+		return false, "", "", err
+	}
+	for _, comp := range components {
+		if strings.EqualFold(comp.Name, "SAP_CLOUD") {
+			return true, comp.Release, "ABAP Cloud system: released APIs only, no classic programs, reports or RFC", nil
+		}
+	}
+	return false, "", "on-premise system", nil
+}
+
+// probeZADTVSP connects to the ZADT_VSP WebSocket handler and checks the
+// version of its welcome message
+func (p *FeatureProber) probeZADTVSP(ctx context.Context) (bool, string, string, error) {
+	cfg := p.client.config
+	ws := NewBaseWebSocketClient(cfg.BaseURL, cfg.Client, cfg.Username, cfg.Password, cfg.InsecureSkipVerify)
+	if err := ws.Connect(ctx); err != nil {
+		if errors.Is(err, websocket.ErrBadHandshake) {
+			return false, "", "ZADT_VSP not installed (deploy it with InstallZADTVSP)", nil
+		}
+		return false, "", "", err
+	}
+	defer ws.Close()
+
+	version := ws.Version()
+	if compareVersions(version, ZADTVSPMinVersion) < 0 {
+		if version == "" {
+			version = "unknown"
+		}
+		return false, version, fmt.Sprintf("ZADT_VSP %s is older than %s (update it with InstallZADTVSP)", version, ZADTVSPMinVersion), nil
+	}
+	return true, version, "ZADT_VSP " + version + " installed", nil
+}
+
+// probeEndpoint checks that an ADT endpoint exists: OPTIONS answered with
+// 200 or 405 (also reported as an error) means available, 404 means the
+// system lacks it
+func (p *FeatureProber) probeEndpoint(ctx context.Context, path, name string) (bool, string, error) {
+	resp, err := p.client.transport.Request(ctx, path, &RequestOptions{
+		Method: http.MethodOptions,
+	})
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "404"):
+			return false, name + " endpoint not available", nil
+		case strings.Contains(err.Error(), "405"):
+			return true, name + " available", nil
+		}
+		return false, "", err
+	}
+
+	if resp.StatusCode == 200 || resp.StatusCode == 405 {
+		return true, name + " available", nil
+	}
+
+	return false, name + " not responding", nil
+}
+
+// compareVersions compares dotted numeric versions like "2.3.0"; missing or
+// non-numeric parts count as 0
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
 // FeatureSummary returns a human-readable summary of all features
 func (p *FeatureProber) FeatureSummary(ctx context.Context) string {
 	results := p.ProbeAll(ctx)
 	var parts []string
 
-	features := []FeatureID{FeatureHANA, FeatureAbapGit, FeatureRAP, FeatureAMDP, FeatureUI5, FeatureTransport}
-	for _, id := range features {
+	for _, id := range AllFeatures {
 		status := results[id]
 		symbol := "✗"
 		if status.Available {
 			symbol = "✓"
 		}
-		parts = append(parts, fmt.Sprintf("%s %s", symbol, id))
+		part := fmt.Sprintf("%s %s", symbol, id)
+		if status.Version != "" && status.Available {
+			part += " " + status.Version
+		}
+		parts = append(parts, part)
 	}
 
 	return strings.Join(parts, " | ")
@@ -0,0 +1,190 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const testComponents = `<components>
+  <component name="SAP_BASIS" release="758" supportPack="0002" description="SAP Basis Component"/>
+  <component name="SAP_CLOUD" release="2408" description="SAP Cloud"/>
+</components>`
+
+// newFeatureTestServer serves the installed components, the ATC endpoint and
+// a ZADT_VSP handler announcing version; everything else is missing.
+func newFeatureTestServer(t *testing.T, version string, requests *int32) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		switch r.URL.Path {
+		case "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+		case "/sap/bc/adt/system/components":
+			fmt.Fprint(w, testComponents)
+		case "/sap/bc/adt/atc/runs":
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case "/sap/bc/apc/sap/zadt_vsp":
+			if version == "" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"welcome","success":true,"data":{"session":"S1","version":"`+version+`"}}`))
+			conn.ReadMessage() // Wait for the client to close
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestFeatureProberNewProbes(t *testing.T) {
+	var requests int32
+	server := newFeatureTestServer(t, "2.3.0", &requests)
+	defer server.Close()
+
+	prober := NewFeatureProber(NewClient(server.URL, "testuser", "testpass"), DefaultFeatureConfig(), false)
+	ctx := context.Background()
+
+	if s := prober.Probe(ctx, FeatureRelease); !s.Available || s.Version != "758 SP 0002" {
+		t.Errorf("release = %+v", s)
+	}
+	if s := prober.Probe(ctx, FeatureCloud); !s.Available || s.Version != "2408" {
+		t.Errorf("abap_cloud = %+v", s)
+	}
+	if s := prober.Probe(ctx, FeatureZADTVSP); !s.Available || s.Version != "2.3.0" {
+		t.Errorf("zadt_vsp = %+v", s)
+	}
+	if s := prober.Probe(ctx, FeatureATC); !s.Available {
+		t.Errorf("atc = %+v", s)
+	}
+	if s := prober.Probe(ctx, FeatureRefactoring); s.Available || s.Failed {
+		t.Errorf("refactoring = %+v", s)
+	}
+
+	// Outdated and missing handlers are unavailable, not failed
+	old := newFeatureTestServer(t, "2.1.0", &requests)
+	defer old.Close()
+	if s := NewFeatureProber(NewClient(old.URL, "u", "p"), DefaultFeatureConfig(), false).Probe(ctx, FeatureZADTVSP); s.Available || s.Failed || s.Version != "2.1.0" {
+		t.Errorf("outdated zadt_vsp = %+v", s)
+	}
+	missing := newFeatureTestServer(t, "", &requests)
+	defer missing.Close()
+	if s := NewFeatureProber(NewClient(missing.URL, "u", "p"), DefaultFeatureConfig(), false).Probe(ctx, FeatureZADTVSP); s.Available || s.Failed {
+		t.Errorf("missing zadt_vsp = %+v", s)
+	}
+}
+
+func TestFeatureProberProfile(t *testing.T) {
+	var requests int32
+	server := newFeatureTestServer(t, "2.3.0", &requests)
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClient(server.URL, "testuser", "testpass")
+	config := DefaultFeatureConfig()
+	config.UI5 = FeatureModeOff
+
+	prober := NewFeatureProber(client, config, false)
+	if err := prober.UseProfile(NewCapabilityProfileStore(dir, time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if known := prober.Known(); len(known) != 1 || known[FeatureUI5] == nil {
+		t.Errorf("known without profile = %v", known)
+	}
+	prober.ProbeAll(context.Background())
+
+	store := NewCapabilityProfileStore(dir, time.Hour)
+	profile, err := store.Load(capabilitySystem(client.config))
+	if err != nil || profile == nil {
+		t.Fatalf("profile not written: %v", err)
+	}
+	if profile.Features[FeatureUI5] != nil {
+		t.Error("forced feature stored in the profile")
+	}
+	if s := profile.Features[FeatureRelease]; s == nil || s.Version != "758 SP 0002" || s.Cached {
+		t.Errorf("stored release = %+v", s)
+	}
+
+	// A second prober answers from the profile without asking the system
+	atomic.StoreInt32(&requests, 0)
+	cached := NewFeatureProber(client, config, false)
+	if err := cached.UseProfile(store); err != nil {
+		t.Fatal(err)
+	}
+	if known := cached.Known(); len(known) != len(AllFeatures) {
+		t.Errorf("known from profile = %d features", len(known))
+	}
+	if s := cached.Probe(context.Background(), FeatureZADTVSP); !s.Available || !s.Cached {
+		t.Errorf("cached zadt_vsp = %+v", s)
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Errorf("%d requests despite a fresh profile", n)
+	}
+
+	// Expired entries are probed again
+	expired := NewFeatureProber(client, config, false)
+	if err := expired.UseProfile(NewCapabilityProfileStore(dir, time.Nanosecond)); err != nil {
+		t.Fatal(err)
+	}
+	if s := expired.Probe(context.Background(), FeatureRelease); s.Cached || atomic.LoadInt32(&requests) == 0 {
+		t.Errorf("expired release = %+v", s)
+	}
+}
+
+func TestFeatureProberUnreachable(t *testing.T) {
+	dir := t.TempDir()
+	client := NewClient("http://localhost:1", "testuser", "testpass")
+	prober := NewFeatureProber(client, DefaultFeatureConfig(), false)
+	if err := prober.UseProfile(NewCapabilityProfileStore(dir, time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	results := prober.ProbeAll(context.Background())
+	for id, s := range results {
+		if !s.Failed || s.Available {
+			t.Errorf("%s = %+v, want failed", id, s)
+		}
+	}
+	if profile, err := NewCapabilityProfileStore(dir, time.Hour).Load(capabilitySystem(client.config)); err != nil || profile != nil {
+		t.Errorf("profile of an unreachable system saved: %+v, %v", profile, err)
+	}
+}
+
+func TestCapabilityProfileStorePath(t *testing.T) {
+	store := NewCapabilityProfileStore("", 0)
+	if store.TTL != DefaultCapabilityProfileTTL {
+		t.Errorf("TTL = %v", store.TTL)
+	}
+	if got := store.Path("https://SAP.example.com:44300?sap-client=001"); got != ".vsp-capabilities/sap_example_com_44300_sap_client_001.json" {
+		t.Errorf("path = %s", got)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2.3.0", "2.3.0", 0},
+		{"2.3", "2.3.0", 0},
+		{"2.10.0", "2.3.0", 1},
+		{"2.2.9", "2.3.0", -1},
+		{"", "2.3.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
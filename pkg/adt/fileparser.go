@@ -3,6 +3,7 @@ package adt
 import (
 	"bufio"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -122,6 +123,11 @@ func ParseABAPFile(filePath string) (*ABAPFileInfo, error) {
 	// RAP object types (ABAPGit-compatible extensions)
 	case strings.HasSuffix(baseName, ".ddls.asddls"):
 		info.ObjectType = ObjectTypeDDLS
+	case strings.HasSuffix(baseName, ".ddlx.asddlxs"):
+		// A metadata extension is named independently of the entity it
+		// annotates, so the name comes from the filename
+		info.ObjectType = ObjectTypeDDLX
+		info.ObjectName = strings.ReplaceAll(strings.ToUpper(strings.TrimSuffix(baseName, ".ddlx.asddlxs")), "#", "/")
 	case strings.HasSuffix(baseName, ".bdef.asbdef"):
 		info.ObjectType = ObjectTypeBDEF
 	case strings.HasSuffix(baseName, ".srvd.srvdsrv"):
@@ -130,7 +136,7 @@ func ParseABAPFile(filePath string) (*ABAPFileInfo, error) {
 		// Generic .abap: detect from content
 		return parseFromContent(filePath)
 	default:
-		return nil, fmt.Errorf("unsupported file extension: %s (expected .clas.abap, .clas.testclasses.abap, .clas.locals_def.abap, .clas.locals_imp.abap, .prog.abap, .intf.abap, .fugr.abap, .func.abap, .ddls.asddls, .bdef.asbdef, or .srvd.srvdsrv)", ext)
+		return nil, fmt.Errorf("unsupported file extension: %s (expected .clas.abap, .clas.testclasses.abap, .clas.locals_def.abap, .clas.locals_imp.abap, .prog.abap, .intf.abap, .fugr.abap, .func.abap, .ddls.asddls, .ddlx.asddlxs, .bdef.asbdef, or .srvd.srvdsrv)", ext)
 	}
 
 	// 2. Parse file content to extract name and metadata
@@ -339,10 +345,10 @@ func parseFunctionModuleName(line string) string {
 	return ""
 }
 
-// parseDDLSName extracts CDS view name from "define view [entity] <name>" or "@AbapCatalog.viewEnhancementCategory"
+// parseDDLSName extracts CDS view name from "define [root] view [entity] <name>" or "@AbapCatalog.viewEnhancementCategory"
 func parseDDLSName(line string) string {
-	// Pattern: define view [entity] NAME
-	re := regexp.MustCompile(`(?i)^\s*define\s+view\s+(?:entity\s+)?([a-z0-9_/]+)`)
+	// Pattern: define [root] view [entity] NAME
+	re := regexp.MustCompile(`(?i)^\s*define\s+(?:root\s+)?view\s+(?:entity\s+)?([a-z0-9_/]+)`)
 	matches := re.FindStringSubmatch(line)
 	if len(matches) > 1 {
 		return strings.ToUpper(matches[1])
@@ -371,3 +377,74 @@ func parseSRVDName(line string) string {
 	}
 	return ""
 }
+
+// AbapGitFileName returns the abapGit-style file name of the source behind an
+// ADT object or source URI, e.g.
+//   - /sap/bc/adt/oo/classes/zcl_foo/source/main → zcl_foo.clas.abap
+//   - /sap/bc/adt/oo/classes/zcl_foo/includes/testclasses → zcl_foo.clas.testclasses.abap
+//   - /sap/bc/adt/functions/groups/zfg/fmodules/z_fm → zfg.fugr.z_fm.func.abap
+//   - /sap/bc/adt/oo/classes/%2fdmo%2fcl_flight → #dmo#cl_flight.clas.abap
+//
+// Query and fragment (#start=...) are ignored. Returns "" for unsupported URIs.
+func AbapGitFileName(uri string) string {
+	if i := strings.IndexAny(uri, "?#"); i >= 0 {
+		uri = uri[:i]
+	}
+	path := strings.TrimPrefix(uri, "/sap/bc/adt/")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	name := func(i int) string {
+		if i >= len(parts) {
+			return ""
+		}
+		n := parts[i]
+		if unescaped, err := url.PathUnescape(n); err == nil {
+			n = unescaped
+		}
+		return strings.ReplaceAll(strings.ToLower(n), "/", "#")
+	}
+	at := func(i int) string {
+		if i >= len(parts) {
+			return ""
+		}
+		return parts[i]
+	}
+
+	switch {
+	case at(0) == "oo" && at(1) == "classes" && name(2) != "":
+		if at(3) == "includes" {
+			switch ClassIncludeType(at(4)) {
+			case ClassIncludeTestClasses:
+				return name(2) + ".clas.testclasses.abap"
+			case ClassIncludeDefinitions:
+				return name(2) + ".clas.locals_def.abap"
+			case ClassIncludeImplementations:
+				return name(2) + ".clas.locals_imp.abap"
+			case ClassIncludeMacros:
+				return name(2) + ".clas.macros.abap"
+			}
+		}
+		return name(2) + ".clas.abap"
+	case at(0) == "oo" && at(1) == "interfaces" && name(2) != "":
+		return name(2) + ".intf.abap"
+	case at(0) == "programs" && (at(1) == "programs" || at(1) == "includes") && name(2) != "":
+		return name(2) + ".prog.abap"
+	case at(0) == "functions" && at(1) == "groups" && name(2) != "":
+		if at(3) == "fmodules" && name(4) != "" {
+			return name(2) + ".fugr." + name(4) + ".func.abap"
+		}
+		if at(3) == "includes" && name(4) != "" {
+			return name(2) + ".fugr." + name(4) + ".abap"
+		}
+		return name(2) + ".fugr.abap"
+	case at(0) == "ddic" && at(1) == "ddl" && at(2) == "sources" && name(3) != "":
+		return name(3) + ".ddls.asddls"
+	case at(0) == "ddic" && at(1) == "ddlx" && at(2) == "sources" && name(3) != "":
+		return name(3) + ".ddlx.asddlxs"
+	case at(0) == "bo" && at(1) == "behaviordefinitions" && name(2) != "":
+		return name(2) + ".bdef.asbdef"
+	case at(0) == "ddic" && at(1) == "srvd" && at(2) == "sources" && name(3) != "":
+		return name(3) + ".srvd.srvdsrv"
+	}
+	return ""
+}
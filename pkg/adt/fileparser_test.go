@@ -373,3 +373,58 @@ END-OF-DEFINITION.
 		t.Errorf("Expected ClassIncludeType %s, got %s", ClassIncludeMacros, info.ClassIncludeType)
 	}
 }
+
+func TestParseABAPFile_CDSSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"zr_travel.ddls.asddls":      "@EndUserText.label: 'Travel'\ndefine root view entity ZR_TRAVEL\n  as select from ztravel\n{\n  key travel_uuid as TravelUUID\n}\n",
+		"#dmo#c_travel.ddlx.asddlxs": "@Metadata.layer: #CUSTOMER\nannotate entity /DMO/C_TRAVEL_PROJ with\n{\n  TravelID;\n}\n",
+	}
+	want := map[string]struct {
+		objType CreatableObjectType
+		name    string
+	}{
+		"zr_travel.ddls.asddls":      {ObjectTypeDDLS, "ZR_TRAVEL"},
+		"#dmo#c_travel.ddlx.asddlxs": {ObjectTypeDDLX, "/DMO/C_TRAVEL"},
+	}
+
+	for name, source := range files {
+		filePath := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(filePath, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+		info, err := ParseABAPFile(filePath)
+		if err != nil {
+			t.Fatalf("ParseABAPFile(%s) failed: %v", name, err)
+		}
+		if info.ObjectType != want[name].objType || info.ObjectName != want[name].name {
+			t.Errorf("%s: got %s %s, want %s %s", name, info.ObjectType, info.ObjectName, want[name].objType, want[name].name)
+		}
+	}
+}
+
+func TestAbapGitFileName(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"/sap/bc/adt/oo/classes/zcl_foo", "zcl_foo.clas.abap"},
+		{"/sap/bc/adt/oo/classes/ZCL_FOO/source/main#start=12,4", "zcl_foo.clas.abap"},
+		{"/sap/bc/adt/oo/classes/zcl_foo/includes/testclasses", "zcl_foo.clas.testclasses.abap"},
+		{"/sap/bc/adt/oo/classes/zcl_foo/includes/implementations#start=3,0", "zcl_foo.clas.locals_imp.abap"},
+		{"/sap/bc/adt/oo/classes/%2fdmo%2fcl_flight/source/main", "#dmo#cl_flight.clas.abap"},
+		{"/sap/bc/adt/oo/interfaces/zif_foo/source/main", "zif_foo.intf.abap"},
+		{"/sap/bc/adt/programs/programs/ztest/source/main", "ztest.prog.abap"},
+		{"/sap/bc/adt/programs/includes/ztest_top", "ztest_top.prog.abap"},
+		{"/sap/bc/adt/functions/groups/zfg/fmodules/z_fm/source/main", "zfg.fugr.z_fm.func.abap"},
+		{"/sap/bc/adt/ddic/ddl/sources/zi_travel/source/main", "zi_travel.ddls.asddls"},
+		{"/sap/bc/adt/ddic/ddlx/sources/zc_travel/source/main", "zc_travel.ddlx.asddlxs"},
+		{"/sap/bc/adt/vit/wb/object_type/devck/object_name/ZPKG", ""},
+	}
+
+	for _, tt := range tests {
+		if got := AbapGitFileName(tt.uri); got != tt.want {
+			t.Errorf("AbapGitFileName(%q) = %q, want %q", tt.uri, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,528 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Impact Analysis ---
+//
+// The impact of a change is found by walking "who uses this" edges
+// transitively: where-used references (FindReferences) and callers
+// (GetCallersOf). Affected objects are grouped by package, and the unit
+// tests that cover them are listed, so the blast radius of a change is
+// known before it is made.
+
+// DefaultImpactCacheTTL is how long looked-up edges and packages are reused.
+const DefaultImpactCacheTTL = 10 * time.Minute
+
+// ImpactOptions configures an impact analysis.
+type ImpactOptions struct {
+	Method         string       // Class method to analyze instead of the whole object
+	MaxDepth       int          // Levels of users to follow (default 3)
+	MaxObjects     int          // Stop after this many affected objects (default 200)
+	SkipReferences bool         // Do not use where-used references
+	SkipCallers    bool         // Do not use the call graph
+	Cache          *ImpactCache // Reuse lookups across analyses (nil = per analysis)
+}
+
+// ImpactNode is an affected object. In the tree, the children of a node are
+// the objects that use it.
+type ImpactNode struct {
+	URI           string        `json:"uri"`
+	Name          string        `json:"name"`
+	Type          string        `json:"type,omitempty"`
+	Package       string        `json:"package,omitempty"`
+	Transportable bool          `json:"transportable"`
+	Depth         int           `json:"depth"`
+	Via           string        `json:"via,omitempty"` // "reference" or "caller"
+	Children      []*ImpactNode `json:"children,omitempty"`
+}
+
+// ImpactPackage lists the affected objects of one package.
+type ImpactPackage struct {
+	Name          string   `json:"name"`
+	Transportable bool     `json:"transportable"`
+	Objects       []string `json:"objects"`
+}
+
+// ImpactTest is an object with ABAP Unit tests that cover affected code.
+type ImpactTest struct {
+	ObjectURL     string   `json:"objectUrl"` // Object to run the tests of
+	Name          string   `json:"name"`
+	Type          string   `json:"type,omitempty"`
+	Package       string   `json:"package,omitempty"`
+	Transportable bool     `json:"transportable"`
+	TestClasses   []string `json:"testClasses,omitempty"`
+	Verified      bool     `json:"verified"` // false = test classes not checked (function groups)
+}
+
+// ImpactResult is the result of AnalyzeImpact.
+type ImpactResult struct {
+	Root      *ImpactNode     `json:"root"`
+	Objects   []ImpactNode    `json:"objects"` // Flat list without children, root first
+	Packages  []ImpactPackage `json:"packages"`
+	Tests     []ImpactTest    `json:"tests"`
+	Truncated bool            `json:"truncated,omitempty"`
+}
+
+// TestObjectURLs returns the objects whose tests cover the change.
+func (r *ImpactResult) TestObjectURLs() []string {
+	var urls []string
+	for _, t := range r.Tests {
+		urls = append(urls, t.ObjectURL)
+	}
+	return urls
+}
+
+// impactEdge is a user of an object.
+type impactEdge struct {
+	URI     string
+	Name    string
+	Type    string
+	Package string
+	Via     string
+}
+
+type impactCacheEntry[T any] struct {
+	value T
+	at    time.Time
+}
+
+// ImpactCache keeps looked-up users, packages and test classes of objects.
+// It is safe for concurrent use. Create it with NewImpactCache.
+type ImpactCache struct {
+	TTL time.Duration
+
+	mu       sync.Mutex
+	edges    map[string]impactCacheEntry[[]impactEdge]
+	packages map[string]impactCacheEntry[string]
+	tests    map[string]impactCacheEntry[[]string]
+}
+
+// NewImpactCache creates a cache (DefaultImpactCacheTTL if ttl is 0).
+func NewImpactCache(ttl time.Duration) *ImpactCache {
+	if ttl <= 0 {
+		ttl = DefaultImpactCacheTTL
+	}
+	return &ImpactCache{
+		TTL:      ttl,
+		edges:    make(map[string]impactCacheEntry[[]impactEdge]),
+		packages: make(map[string]impactCacheEntry[string]),
+		tests:    make(map[string]impactCacheEntry[[]string]),
+	}
+}
+
+func impactCacheGet[T any](c *ImpactCache, m map[string]impactCacheEntry[T], key string) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := m[strings.ToLower(key)]
+	if !ok || time.Since(e.at) > c.TTL {
+		var zero T
+		return zero, false
+	}
+	return e.value, true
+}
+
+func impactCachePut[T any](c *ImpactCache, m map[string]impactCacheEntry[T], key string, value T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m[strings.ToLower(key)] = impactCacheEntry[T]{value: value, at: time.Now()}
+}
+
+// UnitTestContainerURL returns the class, program or function group that
+// owns a URI and can contain unit tests, or "" for other objects.
+func UnitTestContainerURL(uri string) string {
+	u := atcMainObjectURL(atcSourceURL(uri))
+	if i := strings.Index(u, "/fmodules/"); i > 0 {
+		u = u[:i]
+	}
+	lower := strings.ToLower(u)
+	for _, prefix := range []string{"/sap/bc/adt/oo/classes/", "/sap/bc/adt/programs/programs/", "/sap/bc/adt/functions/groups/"} {
+		if strings.HasPrefix(lower, prefix) {
+			return u
+		}
+	}
+	return ""
+}
+
+// isTransportablePackage reports whether objects of a package are recorded
+// on transports. Local packages start with "$".
+func isTransportablePackage(pkg string) bool {
+	return pkg != "" && !strings.HasPrefix(pkg, "$")
+}
+
+func impactObjectName(uri string) string {
+	u := strings.TrimSuffix(uri, "/")
+	return strings.ToUpper(u[strings.LastIndex(u, "/")+1:])
+}
+
+// AnalyzeImpact finds the objects affected by a change of an object (or one
+// of its methods) and the unit tests that cover them.
+func (c *Client) AnalyzeImpact(ctx context.Context, objectURL string, opts ImpactOptions) (*ImpactResult, error) {
+	if err := c.checkSafety(OpRead, "AnalyzeImpact"); err != nil {
+		return nil, err
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 3
+	}
+	if opts.MaxObjects <= 0 {
+		opts.MaxObjects = 200
+	}
+	cache := opts.Cache
+	if cache == nil {
+		cache = NewImpactCache(0)
+	}
+
+	mainURL := atcMainObjectURL(atcSourceURL(objectURL))
+	root := &ImpactNode{URI: mainURL, Name: impactObjectName(mainURL), Type: extractTypeFromURI(mainURL)}
+	root.Package = c.impactPackage(ctx, cache, root.URI, root.Name)
+	root.Transportable = isTransportablePackage(root.Package)
+
+	// The first level of a method analysis uses the method position
+	rootRef := mainURL
+	if opts.Method != "" {
+		pos, err := c.methodPosition(ctx, mainURL, opts.Method)
+		if err != nil {
+			return nil, err
+		}
+		rootRef = pos
+		root.Name += "=>" + strings.ToUpper(opts.Method)
+	}
+
+	result := &ImpactResult{Root: root}
+	visited := map[string]*ImpactNode{strings.ToLower(mainURL): root}
+	order := []*ImpactNode{root}
+	level := []*ImpactNode{root}
+
+	for depth := 1; depth <= opts.MaxDepth && len(level) > 0 && !result.Truncated; depth++ {
+		var next []*ImpactNode
+		for _, node := range level {
+			if result.Truncated {
+				break
+			}
+			ref := node.URI
+			if node == root {
+				ref = rootRef
+			}
+			edges, err := c.impactEdges(ctx, cache, ref, opts)
+			if err != nil {
+				if node == root {
+					return nil, err
+				}
+				continue
+			}
+			for _, e := range edges {
+				key := strings.ToLower(e.URI)
+				if visited[key] != nil {
+					continue
+				}
+				if len(order) >= opts.MaxObjects {
+					result.Truncated = true
+					break
+				}
+				child := &ImpactNode{URI: e.URI, Name: e.Name, Type: e.Type, Package: e.Package, Depth: depth, Via: e.Via}
+				if child.Package == "" {
+					child.Package = c.impactPackage(ctx, cache, child.URI, child.Name)
+				}
+				child.Transportable = isTransportablePackage(child.Package)
+				visited[key] = child
+				order = append(order, child)
+				node.Children = append(node.Children, child)
+				next = append(next, child)
+			}
+		}
+		level = next
+	}
+
+	byPackage := make(map[string]*ImpactPackage)
+	for _, n := range order {
+		flat := *n
+		flat.Children = nil
+		result.Objects = append(result.Objects, flat)
+
+		p := byPackage[n.Package]
+		if p == nil {
+			p = &ImpactPackage{Name: n.Package, Transportable: n.Transportable}
+			byPackage[n.Package] = p
+		}
+		p.Objects = append(p.Objects, n.Name)
+	}
+	for _, p := range byPackage {
+		sort.Strings(p.Objects)
+		result.Packages = append(result.Packages, *p)
+	}
+	sort.Slice(result.Packages, func(i, j int) bool { return result.Packages[i].Name < result.Packages[j].Name })
+
+	result.Tests = c.impactTests(ctx, cache, order)
+	return result, nil
+}
+
+// methodPosition returns the URI of a method name in the class definition,
+// which is where FindReferences expects the cursor.
+func (c *Client) methodPosition(ctx context.Context, classURL, method string) (string, error) {
+	if !strings.HasPrefix(strings.ToLower(classURL), "/sap/bc/adt/oo/classes/") {
+		return "", fmt.Errorf("method impact analysis needs a class, got %s", classURL)
+	}
+	className := impactObjectName(classURL)
+	methods, err := c.GetClassMethods(ctx, className)
+	if err != nil {
+		return "", err
+	}
+	for _, m := range methods {
+		if !strings.EqualFold(m.Name, method) || m.DefinitionStart == 0 {
+			continue
+		}
+		source, err := c.GetClassSource(ctx, className)
+		if err != nil {
+			return "", err
+		}
+		lines := strings.Split(normalizeLineEndings(source), "\n")
+		col := 1
+		if m.DefinitionStart <= len(lines) {
+			if i := strings.Index(strings.ToUpper(lines[m.DefinitionStart-1]), strings.ToUpper(method)); i >= 0 {
+				col = i + 1
+			}
+		}
+		return fmt.Sprintf("%s/source/main#start=%d,%d", classURL, m.DefinitionStart, col), nil
+	}
+	return "", fmt.Errorf("method %s not found in class %s", strings.ToUpper(method), className)
+}
+
+// impactEdges returns the users of an object (or position), merged from
+// where-used references and callers.
+func (c *Client) impactEdges(ctx context.Context, cache *ImpactCache, ref string, opts ImpactOptions) ([]impactEdge, error) {
+	key := fmt.Sprintf("%s|%t|%t", ref, opts.SkipReferences, opts.SkipCallers)
+	if edges, ok := impactCacheGet(cache, cache.edges, key); ok {
+		return edges, nil
+	}
+
+	self := strings.ToLower(atcMainObjectURL(atcSourceURL(ref)))
+	seen := make(map[string]bool)
+	var edges []impactEdge
+	add := func(e impactEdge) {
+		k := strings.ToLower(e.URI)
+		if e.URI == "" || k == self || seen[k] {
+			return
+		}
+		seen[k] = true
+		edges = append(edges, e)
+	}
+
+	var refErr, callErr error
+	if !opts.SkipReferences {
+		refURL, line, col := ref, 0, 0
+		if m := impactPositionRegex.FindStringSubmatch(ref); m != nil {
+			refURL = m[1]
+			fmt.Sscanf(m[2], "%d", &line)
+			fmt.Sscanf(m[3], "%d", &col)
+		}
+		refs, err := c.FindReferences(ctx, refURL, line, col)
+		refErr = err
+		for _, r := range refs {
+			if strings.HasPrefix(strings.ToUpper(r.Type), "DEVC") {
+				continue
+			}
+			u := atcMainObjectURL(atcSourceURL(r.URI))
+			name := r.Name
+			if name == "" {
+				name = impactObjectName(u)
+			}
+			add(impactEdge{URI: u, Name: strings.ToUpper(name), Type: r.Type, Package: r.PackageName, Via: "reference"})
+		}
+	}
+	if !opts.SkipCallers {
+		node, err := c.GetCallersOf(ctx, ref, 1)
+		callErr = err
+		if node != nil {
+			for _, caller := range node.Children {
+				u := atcMainObjectURL(atcSourceURL(caller.URI))
+				typ := caller.Type
+				if typ == "" {
+					typ = extractTypeFromURI(u)
+				}
+				add(impactEdge{URI: u, Name: impactObjectName(u), Type: typ, Via: "caller"})
+			}
+		}
+	}
+	if (opts.SkipReferences || refErr != nil) && (opts.SkipCallers || callErr != nil) {
+		if refErr != nil {
+			return nil, refErr
+		}
+		if callErr != nil {
+			return nil, callErr
+		}
+	}
+
+	sort.Slice(edges, func(i, j int) bool { return edges[i].Name < edges[j].Name })
+	impactCachePut(cache, cache.edges, key, edges)
+	return edges, nil
+}
+
+var impactPositionRegex = regexp.MustCompile(`^(.+)/source/main#start=(\d+),(\d+)$`)
+
+// impactPackage looks up the package of an object by searching its name.
+func (c *Client) impactPackage(ctx context.Context, cache *ImpactCache, uri, name string) string {
+	if pkg, ok := impactCacheGet(cache, cache.packages, uri); ok {
+		return pkg
+	}
+	pkg := ""
+	if results, err := c.SearchObject(ctx, name, 20); err == nil {
+		for _, r := range results {
+			if strings.EqualFold(r.URI, uri) {
+				pkg = r.PackageName
+				break
+			}
+			if pkg == "" && strings.EqualFold(r.Name, name) {
+				pkg = r.PackageName
+			}
+		}
+	}
+	impactCachePut(cache, cache.packages, uri, pkg)
+	return pkg
+}
+
+// testClassRegex matches "CLASS x DEFINITION ... FOR TESTING".
+var testClassRegex = regexp.MustCompile(`(?is)\bCLASS\s+(\S+)\s+DEFINITION\b[^.]*?\bFOR\s+TESTING\b`)
+
+// findTestClasses returns the names of the test classes in ABAP source.
+func findTestClasses(source string) []string {
+	var names []string
+	for _, m := range testClassRegex.FindAllStringSubmatch(source, -1) {
+		names = append(names, strings.ToUpper(m[1]))
+	}
+	return names
+}
+
+// impactTests lists the objects with unit tests among the affected objects.
+func (c *Client) impactTests(ctx context.Context, cache *ImpactCache, nodes []*ImpactNode) []ImpactTest {
+	tests := []ImpactTest{}
+	seen := make(map[string]bool)
+	for _, n := range nodes {
+		container := UnitTestContainerURL(n.URI)
+		key := strings.ToLower(container)
+		if container == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		t := ImpactTest{ObjectURL: container, Name: impactObjectName(container), Type: extractTypeFromURI(container), Package: n.Package, Transportable: n.Transportable}
+		if strings.Contains(key, "/functions/groups/") {
+			// Test classes of function groups live in arbitrary includes
+			tests = append(tests, t)
+			continue
+		}
+		t.Verified = true
+		t.TestClasses = c.impactTestClasses(ctx, cache, container)
+		if len(t.TestClasses) > 0 {
+			tests = append(tests, t)
+		}
+	}
+	return tests
+}
+
+func (c *Client) impactTestClasses(ctx context.Context, cache *ImpactCache, container string) []string {
+	if names, ok := impactCacheGet(cache, cache.tests, container); ok {
+		return names
+	}
+	paths := []string{container + "/source/main"}
+	if strings.Contains(strings.ToLower(container), "/oo/classes/") {
+		paths = append(paths, container+"/includes/testclasses")
+	}
+	var names []string
+	for _, p := range paths {
+		resp, err := c.transport.Request(ctx, p, &RequestOptions{Method: http.MethodGet, Accept: "text/plain"})
+		if err != nil {
+			continue
+		}
+		names = append(names, findTestClasses(string(resp.Body))...)
+	}
+	names = dedupeStrings(names)
+	impactCachePut(cache, cache.tests, container, names)
+	return names
+}
+
+// FormatImpact renders an impact analysis as "tree", "list" or "dot".
+func FormatImpact(r *ImpactResult, format string) (string, error) {
+	format = strings.ToLower(format)
+	var sb strings.Builder
+	switch format {
+	case "", "tree":
+		var walk func(n *ImpactNode, indent string)
+		walk = func(n *ImpactNode, indent string) {
+			fmt.Fprintf(&sb, "%s%s\n", indent, impactNodeLabel(n))
+			for _, child := range n.Children {
+				walk(child, indent+"  ")
+			}
+		}
+		walk(r.Root, "")
+	case "list", "flat":
+		for _, n := range r.Objects {
+			fmt.Fprintf(&sb, "%d\t%s\t%s\t%s\t%s\n", n.Depth, n.Name, n.Type, n.Package, n.Via)
+		}
+	case "dot":
+		sb.WriteString("digraph impact {\n  rankdir=BT;\n  node [shape=box];\n")
+		for i, p := range r.Packages {
+			fmt.Fprintf(&sb, "  subgraph cluster_%d {\n    label=%q;\n", i, p.Name)
+			for _, name := range p.Objects {
+				fmt.Fprintf(&sb, "    %q;\n", name)
+			}
+			sb.WriteString("  }\n")
+		}
+		var walk func(n *ImpactNode)
+		walk = func(n *ImpactNode) {
+			for _, child := range n.Children {
+				fmt.Fprintf(&sb, "  %q -> %q [label=%q];\n", child.Name, n.Name, child.Via)
+				walk(child)
+			}
+		}
+		walk(r.Root)
+		sb.WriteString("}\n")
+	default:
+		return "", fmt.Errorf("unknown impact format %q (expected tree, list or dot)", format)
+	}
+
+	if format != "dot" {
+		if len(r.Tests) > 0 {
+			sb.WriteString("\nTests:\n")
+			for _, t := range r.Tests {
+				classes := strings.Join(t.TestClasses, ", ")
+				if !t.Verified {
+					classes = "not verified"
+				}
+				fmt.Fprintf(&sb, "  %s (%s)%s: %s\n", t.Name, t.Package, impactTransportMark(t.Transportable), classes)
+			}
+		}
+		if r.Truncated {
+			sb.WriteString("\n(truncated)\n")
+		}
+	}
+	return sb.String(), nil
+}
+
+func impactNodeLabel(n *ImpactNode) string {
+	label := n.Name
+	if n.Type != "" {
+		label += " [" + n.Type + "]"
+	}
+	if n.Package != "" {
+		label += " " + n.Package + impactTransportMark(n.Transportable)
+	}
+	if n.Via != "" {
+		label += " (" + n.Via + ")"
+	}
+	return label
+}
+
+func impactTransportMark(transportable bool) string {
+	if transportable {
+		return " *transportable*"
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeImpact(t *testing.T) {
+	var referenceCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+		case "/sap/bc/adt/repository/informationsystem/usageReferences":
+			referenceCalls++
+			if r.URL.Query().Get("uri") != "/sap/bc/adt/oo/classes/zcl_a" {
+				fmt.Fprint(w, `<usageReferences:usageReferenceResult xmlns:usageReferences="http://www.sap.com/adt/ris/usageReferences"><usageReferences:referencedObjects/></usageReferences:usageReferenceResult>`)
+				return
+			}
+			fmt.Fprint(w, `<usageReferences:usageReferenceResult xmlns:usageReferences="http://www.sap.com/adt/ris/usageReferences" xmlns:adtcore="http://www.sap.com/adt/core">
+  <usageReferences:referencedObjects>
+    <usageReferences:referencedObject uri="/sap/bc/adt/packages/%24zpkg">
+      <usageReferences:adtObject adtcore:type="DEVC/K" adtcore:name="$ZPKG"/>
+    </usageReferences:referencedObject>
+    <usageReferences:referencedObject uri="/sap/bc/adt/oo/classes/zcl_b/includes/implementations" isResult="true">
+      <usageReferences:adtObject adtcore:type="CLAS/OC" adtcore:name="ZCL_B">
+        <adtcore:packageRef adtcore:name="$ZPKG"/>
+      </usageReferences:adtObject>
+    </usageReferences:referencedObject>
+  </usageReferences:referencedObjects>
+</usageReferences:usageReferenceResult>`)
+		case "/sap/bc/adt/cai/callgraph":
+			if !strings.Contains(string(body), "/sap/bc/adt/oo/classes/zcl_a<") {
+				fmt.Fprint(w, `<callGraph><node uri="x" name="X"/></callGraph>`)
+				return
+			}
+			fmt.Fprint(w, `<callGraph><node uri="/sap/bc/adt/oo/classes/zcl_a" name="ZCL_A">
+  <node uri="/sap/bc/adt/programs/programs/zreport/source/main#start=5,1" name="ZREPORT" type="PROG/P"/>
+</node></callGraph>`)
+		case "/sap/bc/adt/repository/informationsystem/search":
+			name := strings.ToLower(r.URL.Query().Get("query"))
+			pkg, path := "$ZPKG", "oo/classes"
+			if name == "zreport" {
+				pkg, path = "ZPROD", "programs/programs"
+			}
+			fmt.Fprintf(w, `<adtcore:objectReferences xmlns:adtcore="http://www.sap.com/adt/core"><adtcore:objectReference adtcore:uri="/sap/bc/adt/%s/%s" adtcore:name="%s" adtcore:packageName="%s"/></adtcore:objectReferences>`,
+				path, name, strings.ToUpper(name), pkg)
+		case "/sap/bc/adt/oo/classes/zcl_a/includes/testclasses":
+			fmt.Fprint(w, "CLASS ltc_a DEFINITION FINAL\n  FOR TESTING RISK LEVEL HARMLESS.\nENDCLASS.")
+		case "/sap/bc/adt/oo/classes/zcl_a/source/main", "/sap/bc/adt/oo/classes/zcl_b/source/main":
+			fmt.Fprint(w, "CLASS zcl_x DEFINITION PUBLIC.\nENDCLASS.")
+		case "/sap/bc/adt/programs/programs/zreport/source/main":
+			fmt.Fprint(w, "REPORT zreport.\nCLASS ltc DEFINITION FOR TESTING.\nENDCLASS.")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"))
+	cache := NewImpactCache(0)
+	impact, err := client.AnalyzeImpact(context.Background(), "/sap/bc/adt/oo/classes/zcl_a", ImpactOptions{MaxDepth: 2, Cache: cache})
+	if err != nil {
+		t.Fatalf("AnalyzeImpact failed: %v", err)
+	}
+
+	if len(impact.Objects) != 3 || len(impact.Root.Children) != 2 {
+		t.Fatalf("expected root with 2 users, got %+v", impact.Objects)
+	}
+	b, report := impact.Root.Children[0], impact.Root.Children[1]
+	if b.Name != "ZCL_B" || b.URI != "/sap/bc/adt/oo/classes/zcl_b" || b.Via != "reference" || b.Transportable {
+		t.Errorf("unexpected reference node: %+v", b)
+	}
+	if report.Name != "ZREPORT" || report.Package != "ZPROD" || !report.Transportable || report.Via != "caller" {
+		t.Errorf("unexpected caller node: %+v", report)
+	}
+	if len(impact.Packages) != 2 || impact.Packages[0].Name != "$ZPKG" || len(impact.Packages[0].Objects) != 2 {
+		t.Errorf("unexpected packages: %+v", impact.Packages)
+	}
+
+	if len(impact.Tests) != 2 {
+		t.Fatalf("expected 2 test objects, got %+v", impact.Tests)
+	}
+	if impact.Tests[0].Name != "ZCL_A" || impact.Tests[0].TestClasses[0] != "LTC_A" {
+		t.Errorf("unexpected class tests: %+v", impact.Tests[0])
+	}
+	if impact.Tests[1].Name != "ZREPORT" || !impact.Tests[1].Transportable {
+		t.Errorf("unexpected program tests: %+v", impact.Tests[1])
+	}
+
+	// Lookups are cached
+	calls := referenceCalls
+	if _, err := client.AnalyzeImpact(context.Background(), "/sap/bc/adt/oo/classes/zcl_a", ImpactOptions{MaxDepth: 2, Cache: cache}); err != nil {
+		t.Fatalf("second AnalyzeImpact failed: %v", err)
+	}
+	if referenceCalls != calls {
+		t.Errorf("expected cached references, got %d new calls", referenceCalls-calls)
+	}
+
+	dot, err := FormatImpact(impact, "dot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dot, `"ZREPORT" -> "ZCL_A" [label="caller"]`) || !strings.Contains(dot, `label="ZPROD"`) {
+		t.Errorf("unexpected dot output:\n%s", dot)
+	}
+}
+
+func TestUnitTestContainerURL(t *testing.T) {
+	tests := map[string]string{
+		"/sap/bc/adt/oo/classes/zcl_foo/source/main":                       "/sap/bc/adt/oo/classes/zcl_foo",
+		"/sap/bc/adt/programs/programs/zprog":                              "/sap/bc/adt/programs/programs/zprog",
+		"/sap/bc/adt/functions/groups/zfg/fmodules/z_func/source/main#l=1": "/sap/bc/adt/functions/groups/zfg",
+		"/sap/bc/adt/oo/interfaces/zif_foo":                                "",
+		"/sap/bc/adt/ddic/ddl/sources/zi_foo":                              "",
+	}
+	for uri, want := range tests {
+		if got := UnitTestContainerURL(uri); got != want {
+			t.Errorf("UnitTestContainerURL(%q) = %q, want %q", uri, got, want)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package adt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- OAuth (BTP ABAP Environment) ---
+//
+// BTP ABAP Environment systems do not accept basic authentication for ADT.
+// The client gets an access token from the XSUAA of the service key and
+// sends it as bearer token. Tokens are obtained with the refresh token of an
+// earlier browser login (OAuthLogin), with the password grant for technical
+// users, or with client credentials.
+
+// ServiceKey is the service key of a BTP ABAP Environment instance, as
+// created in the BTP cockpit or with "cf create-service-key".
+type ServiceKey struct {
+	URL string `json:"url"`
+	UAA struct {
+		URL          string `json:"url"`
+		ClientID     string `json:"clientid"`
+		ClientSecret string `json:"clientsecret"`
+	} `json:"uaa"`
+	Endpoints map[string]string `json:"endpoints,omitempty"`
+	SystemID  string            `json:"systemid,omitempty"`
+}
+
+// LoadServiceKey reads a service key file. Keys exported with the cf CLI
+// wrap the key in a "credentials" object; both forms are accepted.
+func LoadServiceKey(path string) (*ServiceKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading service key: %w", err)
+	}
+	return ParseServiceKey(data)
+}
+
+// ParseServiceKey parses the JSON of a service key.
+func ParseServiceKey(data []byte) (*ServiceKey, error) {
+	var wrapped struct {
+		Credentials *ServiceKey `json:"credentials"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("parsing service key: %w", err)
+	}
+	key := wrapped.Credentials
+	if key == nil {
+		key = &ServiceKey{}
+		if err := json.Unmarshal(data, key); err != nil {
+			return nil, fmt.Errorf("parsing service key: %w", err)
+		}
+	}
+	if key.UAA.URL == "" || key.UAA.ClientID == "" {
+		return nil, fmt.Errorf("service key has no uaa url or clientid")
+	}
+	if key.SystemURL() == "" {
+		return nil, fmt.Errorf("service key has no system url")
+	}
+	return key, nil
+}
+
+// SystemURL returns the ABAP endpoint of the instance.
+func (k *ServiceKey) SystemURL() string {
+	if abap := k.Endpoints["abap"]; abap != "" {
+		return strings.TrimRight(abap, "/")
+	}
+	return strings.TrimRight(k.URL, "/")
+}
+
+// OAuthConfig returns the OAuth settings of the key.
+func (k *ServiceKey) OAuthConfig() *OAuthConfig {
+	uaa := strings.TrimRight(k.UAA.URL, "/")
+	return &OAuthConfig{
+		TokenURL:     uaa + "/oauth/token",
+		AuthorizeURL: uaa + "/oauth/authorize",
+		ClientID:     k.UAA.ClientID,
+		ClientSecret: k.UAA.ClientSecret,
+	}
+}
+
+// OAuthConfig holds the OAuth client of a system.
+type OAuthConfig struct {
+	TokenURL     string
+	AuthorizeURL string
+	ClientID     string
+	ClientSecret string
+	// RefreshToken from an earlier login; used before the password grant
+	RefreshToken string
+}
+
+// OAuthToken is a token response of the authorization server.
+type OAuthToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	ExpiresIn    int       `json:"expires_in,omitempty"`
+	Expiry       time.Time `json:"-"`
+}
+
+// valid reports whether the token can still be used for a while.
+func (t *OAuthToken) valid() bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || time.Until(t.Expiry) > 30*time.Second)
+}
+
+// oauthTokenSource fetches and caches the access token of a transport.
+type oauthTokenSource struct {
+	mu    sync.Mutex
+	token *OAuthToken
+}
+
+// oauthToken returns a valid access token, fetching a new one if needed. A
+// rotated refresh token replaces the configured one.
+func (t *Transport) oauthToken(ctx context.Context) (string, error) {
+	t.oauth.mu.Lock()
+	defer t.oauth.mu.Unlock()
+	if t.oauth.token.valid() {
+		return t.oauth.token.AccessToken, nil
+	}
+
+	oauth := t.config.OAuth
+	form := url.Values{}
+	switch {
+	case oauth.RefreshToken != "":
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", oauth.RefreshToken)
+	case t.config.HasBasicAuth():
+		form.Set("grant_type", "password")
+		form.Set("username", t.config.Username)
+		form.Set("password", t.config.Password)
+	default:
+		form.Set("grant_type", "client_credentials")
+	}
+
+	token, err := requestOAuthToken(ctx, t.httpClient, oauth, form)
+	if err != nil {
+		return "", err
+	}
+	if token.RefreshToken != "" {
+		oauth.RefreshToken = token.RefreshToken
+	}
+	t.oauth.token = token
+	return token.AccessToken, nil
+}
+
+// requestOAuthToken posts a grant to the token endpoint.
+func requestOAuthToken(ctx context.Context, client HTTPDoer, oauth *OAuthConfig, form url.Values) (*OAuthToken, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, oauth.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("creating token request: %w", err)
+	}
+	req.SetBasicAuth(oauth.ClientID, oauth.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting OAuth token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var token OAuthToken
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&oauthErr)
+		return nil, fmt.Errorf("OAuth %s grant failed (HTTP %d): %s %s", form.Get("grant_type"), resp.StatusCode, oauthErr.Error, oauthErr.Description)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("parsing OAuth token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("OAuth %s grant returned no access token", form.Get("grant_type"))
+	}
+	if token.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return &token, nil
+}
+
+// OAuthLogin runs the authorization code flow in the browser: open is called
+// with the login URL, the redirect is received on localhost:port. The token
+// carries the refresh token for later starts.
+func OAuthLogin(ctx context.Context, oauth *OAuthConfig, port int, open func(loginURL string) error) (*OAuthToken, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("listening for the login redirect: %w", err)
+	}
+	redirectURI := fmt.Sprintf("http://localhost:%d/callback", listener.Addr().(*net.TCPAddr).Port)
+
+	stateBytes := make([]byte, 16)
+	if _, err := rand.Read(stateBytes); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("creating login state: %w", err)
+	}
+	state := hex.EncodeToString(stateBytes)
+
+	type callback struct {
+		code string
+		err  error
+	}
+	done := make(chan callback, 1)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/callback" {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		var result callback
+		switch {
+		case q.Get("state") != state:
+			result.err = fmt.Errorf("login redirect with unexpected state")
+		case q.Get("error") != "":
+			result.err = fmt.Errorf("login failed: %s %s", q.Get("error"), q.Get("error_description"))
+		case q.Get("code") == "":
+			result.err = fmt.Errorf("login redirect without authorization code")
+		default:
+			result.code = q.Get("code")
+		}
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Login successful. You can close this window.")
+		}
+		select {
+		case done <- result:
+		default:
+		}
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", oauth.ClientID)
+	params.Set("redirect_uri", redirectURI)
+	params.Set("state", state)
+	if err := open(oauth.AuthorizeURL + "?" + params.Encode()); err != nil {
+		return nil, err
+	}
+
+	var result callback
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for login: %w", ctx.Err())
+	case result = <-done:
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", result.code)
+	form.Set("redirect_uri", redirectURI)
+	return requestOAuthToken(ctx, http.DefaultClient, oauth, form)
+}
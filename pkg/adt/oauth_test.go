@@ -0,0 +1,102 @@
+package adt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParseServiceKey(t *testing.T) {
+	plain := `{
+  "url": "https://abc123.abap.eu10.hana.ondemand.com",
+  "systemid": "TRL",
+  "uaa": {"url": "https://sub.authentication.eu10.hana.ondemand.com", "clientid": "sb-abc", "clientsecret": "secret"},
+  "endpoints": {"abap": "https://abc123.abap-web.eu10.hana.ondemand.com/"}
+}`
+	key, err := ParseServiceKey([]byte(plain))
+	if err != nil {
+		t.Fatalf("ParseServiceKey failed: %v", err)
+	}
+	if key.SystemURL() != "https://abc123.abap-web.eu10.hana.ondemand.com" || key.SystemID != "TRL" {
+		t.Errorf("unexpected key: %+v", key)
+	}
+	oauth := key.OAuthConfig()
+	if oauth.TokenURL != "https://sub.authentication.eu10.hana.ondemand.com/oauth/token" || oauth.ClientID != "sb-abc" || oauth.ClientSecret != "secret" {
+		t.Errorf("unexpected OAuth config: %+v", oauth)
+	}
+
+	wrapped := `{"credentials": {"url": "https://x.abap.eu10.hana.ondemand.com", "uaa": {"url": "https://uaa", "clientid": "c"}}}`
+	key, err = ParseServiceKey([]byte(wrapped))
+	if err != nil || key.SystemURL() != "https://x.abap.eu10.hana.ondemand.com" {
+		t.Errorf("wrapped key: %+v, %v", key, err)
+	}
+
+	if _, err := ParseServiceKey([]byte(`{"url": "https://x"}`)); err == nil {
+		t.Error("expected error for key without uaa")
+	}
+}
+
+func TestTransportOAuth(t *testing.T) {
+	var grants []url.Values
+	uaa := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "sb-abc" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"unauthorized","error_description":"Bad credentials"}`)
+			return
+		}
+		r.ParseForm()
+		grants = append(grants, r.PostForm)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  fmt.Sprintf("token-%d", len(grants)),
+			"refresh_token": "refresh-2",
+			"expires_in":    3600,
+		})
+	}))
+	defer uaa.Close()
+
+	system := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("X-CSRF-Token", "test-token")
+		fmt.Fprint(w, "ok")
+	}))
+	defer system.Close()
+
+	oauth := &OAuthConfig{TokenURL: uaa.URL, ClientID: "sb-abc", ClientSecret: "secret", RefreshToken: "refresh-1"}
+	client := NewClient(system.URL, "", "", WithOAuth(oauth))
+	for i := 0; i < 2; i++ {
+		if _, err := client.transport.Request(context.Background(), "/sap/bc/adt/discovery", nil); err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+	}
+	if len(grants) != 1 {
+		t.Fatalf("expected the token to be cached, got %d grants", len(grants))
+	}
+	if grants[0].Get("grant_type") != "refresh_token" || grants[0].Get("refresh_token") != "refresh-1" {
+		t.Errorf("unexpected grant: %v", grants[0])
+	}
+	if oauth.RefreshToken != "refresh-2" {
+		t.Errorf("rotated refresh token not kept: %s", oauth.RefreshToken)
+	}
+
+	// Without refresh token the user and password are used for the password grant
+	grants = nil
+	client = NewClient(system.URL, "CB9980000001", "pass", WithOAuth(&OAuthConfig{TokenURL: uaa.URL, ClientID: "sb-abc", ClientSecret: "secret"}))
+	if _, err := client.transport.Request(context.Background(), "/sap/bc/adt/discovery", nil); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if grants[0].Get("grant_type") != "password" || grants[0].Get("username") != "CB9980000001" {
+		t.Errorf("unexpected grant: %v", grants[0])
+	}
+
+	client = NewClient(system.URL, "", "", WithOAuth(&OAuthConfig{TokenURL: uaa.URL, ClientID: "sb-abc", ClientSecret: "wrong"}))
+	if _, err := client.transport.Request(context.Background(), "/sap/bc/adt/discovery", nil); err == nil {
+		t.Error("expected error for rejected client credentials")
+	}
+}
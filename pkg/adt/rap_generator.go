@@ -0,0 +1,793 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// --- RAP Stack Generator ---
+//
+// Generates a managed RAP business object from a DDIC table: a root view
+// entity (R_), a projection (C_) with metadata extension, the behavior
+// definitions of both layers, the behavior pool, a service definition (UI_)
+// and an OData UI service binding. With draft a draft table (<table>_D) is
+// generated as well. The generator only produces sources; deployment goes
+// through the file import (see dsl.GenerateRAPStack).
+
+// TableDefinitionField is a field of a DDIC table or structure as declared
+// in its DDL source.
+type TableDefinitionField struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"` // Data element or built-in type, e.g. abap.char(10)
+	IsKey       bool     `json:"isKey,omitempty"`
+	NotNull     bool     `json:"notNull,omitempty"`
+	Annotations []string `json:"annotations,omitempty"` // e.g. @Semantics.amount.currencyCode : 'ztravel.currency_code'
+}
+
+// TableDefinitionInclude is an include structure of a table definition,
+// inserted before the field at Position.
+type TableDefinitionInclude struct {
+	Name     string `json:"name"`
+	Suffix   string `json:"suffix,omitempty"`
+	Position int    `json:"position"`
+}
+
+// TableDefinition is the parsed DDL source of a DDIC table or structure.
+type TableDefinition struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description,omitempty"`
+	Fields      []TableDefinitionField   `json:"fields"`
+	Includes    []TableDefinitionInclude `json:"includes,omitempty"` // Unresolved includes
+}
+
+var (
+	tableDefineRegex   = regexp.MustCompile(`(?is)define\s+(?:table|structure)\s+([/\w]+)\s*\{(.*)\}`)
+	tableLabelRegex    = regexp.MustCompile(`(?i)@EndUserText\.label\s*:\s*'((?:[^']|'')*)'`)
+	tableIncludeRegex  = regexp.MustCompile(`(?i)^(?:key\s+)?(?:"?[%/\w]+"?\s*:\s*)?include\s+([/\w]+)(?:\s+with\s+suffix\s+(\w+))?`)
+	tableFieldRegex    = regexp.MustCompile(`(?i)^(key\s+)?([/\w]+)\s*:\s*([/\w.]+(?:\s*\([\d\s,]*\))?)(.*)$`)
+	tableNotNullRegex  = regexp.MustCompile(`(?i)\bnot\s+null\b`)
+	tableFieldRefRegex = regexp.MustCompile(`^(@[\w.]+)\s*:\s*'[/\w]+\.([/\w]+)'$`)
+	rapEntityRegex     = regexp.MustCompile(`^[A-Za-z]\w*$`)
+)
+
+// ParseTableDefinition parses the DDL source of a table or structure as
+// returned by GetTable or GetStructure. Includes are listed, not resolved.
+func ParseTableDefinition(source string) (*TableDefinition, error) {
+	source = stripDDLComments(source)
+	m := tableDefineRegex.FindStringSubmatchIndex(source)
+	if m == nil {
+		return nil, fmt.Errorf("no table or structure definition found")
+	}
+
+	def := &TableDefinition{
+		Name:   strings.ToUpper(source[m[2]:m[3]]),
+		Fields: []TableDefinitionField{},
+	}
+	if label := tableLabelRegex.FindStringSubmatch(source[:m[0]]); label != nil {
+		def.Description = strings.ReplaceAll(label[1], "''", "'")
+	}
+
+	for _, stmt := range strings.Split(source[m[4]:m[5]], ";") {
+		var annotations, parts []string
+		for _, line := range strings.Split(stmt, "\n") {
+			line = strings.TrimSpace(line)
+			switch {
+			case line == "":
+			case strings.HasPrefix(line, "@"):
+				annotations = append(annotations, line)
+			default:
+				parts = append(parts, line)
+			}
+		}
+		decl := strings.Join(parts, " ")
+		if decl == "" {
+			continue
+		}
+
+		if inc := tableIncludeRegex.FindStringSubmatch(decl); inc != nil {
+			def.Includes = append(def.Includes, TableDefinitionInclude{
+				Name:     strings.ToUpper(inc[1]),
+				Suffix:   strings.ToLower(inc[2]),
+				Position: len(def.Fields),
+			})
+			continue
+		}
+		f := tableFieldRegex.FindStringSubmatch(decl)
+		if f == nil {
+			return nil, fmt.Errorf("cannot parse field declaration %q", decl)
+		}
+		def.Fields = append(def.Fields, TableDefinitionField{
+			Name:        strings.ToLower(f[2]),
+			Type:        strings.ToLower(strings.Join(strings.Fields(f[3]), "")),
+			IsKey:       f[1] != "",
+			NotNull:     tableNotNullRegex.MatchString(f[4]),
+			Annotations: annotations,
+		})
+	}
+
+	if len(def.Fields) == 0 && len(def.Includes) == 0 {
+		return nil, fmt.Errorf("%s has no fields", def.Name)
+	}
+	return def, nil
+}
+
+// stripDDLComments removes // and /* */ comments outside of string literals.
+func stripDDLComments(source string) string {
+	var sb strings.Builder
+	inString := false
+	for i := 0; i < len(source); i++ {
+		ch := source[i]
+		switch {
+		case inString:
+			if ch == '\'' {
+				inString = false
+			}
+		case ch == '\'':
+			inString = true
+		case ch == '/' && i+1 < len(source) && source[i+1] == '/':
+			for i < len(source) && source[i] != '\n' {
+				i++
+			}
+			if i < len(source) {
+				sb.WriteByte('\n')
+			}
+			continue
+		case ch == '/' && i+1 < len(source) && source[i+1] == '*':
+			end := strings.Index(source[i+2:], "*/")
+			if end < 0 {
+				return sb.String()
+			}
+			i += end + 3
+			continue
+		}
+		sb.WriteByte(ch)
+	}
+	return sb.String()
+}
+
+// ReadTableDefinition reads and parses the DDL source of a table. Include
+// structures are read with GetStructure and expanded in place (with their
+// suffix, if any).
+func (c *Client) ReadTableDefinition(ctx context.Context, tableName string) (*TableDefinition, error) {
+	if err := c.checkSafety(OpRead, "ReadTableDefinition"); err != nil {
+		return nil, err
+	}
+	source, err := c.GetTable(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	def, err := ParseTableDefinition(source)
+	if err != nil {
+		return nil, fmt.Errorf("parsing table %s: %w", strings.ToUpper(tableName), err)
+	}
+	if err := c.resolveTableIncludes(ctx, def, 0); err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+func (c *Client) resolveTableIncludes(ctx context.Context, def *TableDefinition, depth int) error {
+	if len(def.Includes) == 0 {
+		return nil
+	}
+	if depth >= 5 {
+		return fmt.Errorf("includes of %s nested too deeply", def.Name)
+	}
+
+	// Insert from the last include so earlier positions stay valid
+	for i := len(def.Includes) - 1; i >= 0; i-- {
+		inc := def.Includes[i]
+		source, err := c.GetStructure(ctx, inc.Name)
+		if err != nil {
+			return fmt.Errorf("reading include %s: %w", inc.Name, err)
+		}
+		sub, err := ParseTableDefinition(source)
+		if err != nil {
+			return fmt.Errorf("parsing include %s: %w", inc.Name, err)
+		}
+		if err := c.resolveTableIncludes(ctx, sub, depth+1); err != nil {
+			return err
+		}
+		for j := range sub.Fields {
+			sub.Fields[j].Name += inc.Suffix
+		}
+		fields := append([]TableDefinitionField{}, def.Fields[:inc.Position]...)
+		fields = append(fields, sub.Fields...)
+		def.Fields = append(fields, def.Fields[inc.Position:]...)
+	}
+	def.Includes = nil
+	return nil
+}
+
+// RAPStackOptions configures the generated business object.
+type RAPStackOptions struct {
+	Entity         string // Entity alias, e.g. "Travel" (default: derived from the table name)
+	Prefix         string // Object name prefix: "Z", "Y" or a namespace (default: namespace of the table, else "Z")
+	Description    string // Label of the views (default: table description)
+	NoDraft        bool   // Generate without draft
+	BindingVersion string // OData version of the service binding: V4 (default) or V2
+}
+
+// RAPArtifact is a generated object. Artifacts with a FileName are written
+// in abapGit layout and deployed by the file import; the draft table and
+// the service binding are created via ADT.
+type RAPArtifact struct {
+	Type     string `json:"type"` // TABL, DDLS, DDLX, BDEF, CLAS, SRVD, SRVB
+	Name     string `json:"name"`
+	Include  string `json:"include,omitempty"` // Class include, e.g. implementations
+	FileName string `json:"fileName,omitempty"`
+	Source   string `json:"source,omitempty"`
+}
+
+// RAPStack is a generated RAP business object.
+type RAPStack struct {
+	Table             string        `json:"table"`
+	Entity            string        `json:"entity"`
+	Description       string        `json:"description"`
+	Draft             bool          `json:"draft"`
+	DraftTable        string        `json:"draftTable,omitempty"`
+	RootView          string        `json:"rootView"`
+	ProjectionView    string        `json:"projectionView"`
+	MetadataExtension string        `json:"metadataExtension"`
+	BehaviorPool      string        `json:"behaviorPool"`
+	ServiceDefinition string        `json:"serviceDefinition"`
+	ServiceBinding    string        `json:"serviceBinding"`
+	BindingVersion    string        `json:"bindingVersion"`
+	Artifacts         []RAPArtifact `json:"artifacts"`
+	Warnings          []string      `json:"warnings,omitempty"`
+}
+
+// RAPStackManifestFile is written next to the generated files and describes
+// the whole stack, including the objects that are not file-based.
+const RAPStackManifestFile = "rap-stack.json"
+
+// rapField is a table field with its CDS element name and administrative role.
+type rapField struct {
+	TableDefinitionField
+	Element string
+	Role    string // client, createdBy, createdAt, lastChangedBy, ...
+}
+
+// rapAdminTypes maps the data elements of administrative fields to their role.
+var rapAdminTypes = map[string]string{
+	"abp_creation_user":             "createdBy",
+	"abp_creation_tstmpl":           "createdAt",
+	"abp_locinst_lastchange_user":   "localInstanceLastChangedBy",
+	"abp_locinst_lastchange_tstmpl": "localInstanceLastChangedAt",
+	"abp_lastchange_user":           "lastChangedBy",
+	"abp_lastchange_tstmpl":         "lastChangedAt",
+}
+
+// rapAdminNames maps field name suffixes to roles for tables that do not
+// use the ABP data elements. More specific suffixes come first.
+var rapAdminNames = []struct{ suffix, role string }{
+	{"local_last_changed_by", "localInstanceLastChangedBy"},
+	{"local_last_changed_at", "localInstanceLastChangedAt"},
+	{"last_changed_by", "lastChangedBy"},
+	{"last_changed_at", "lastChangedAt"},
+	{"created_by", "createdBy"},
+	{"created_at", "createdAt"},
+}
+
+var rapSemantics = map[string]string{
+	"createdBy":                  "@Semantics.user.createdBy: true",
+	"createdAt":                  "@Semantics.systemDateTime.createdAt: true",
+	"localInstanceLastChangedBy": "@Semantics.user.localInstanceLastChangedBy: true",
+	"localInstanceLastChangedAt": "@Semantics.systemDateTime.localInstanceLastChangedAt: true",
+	"lastChangedBy":              "@Semantics.user.lastChangedBy: true",
+	"lastChangedAt":              "@Semantics.systemDateTime.lastChangedAt: true",
+}
+
+func rapFieldRole(f TableDefinitionField) string {
+	if f.IsKey && (f.Type == "abap.clnt" || f.Type == "mandt") {
+		return "client"
+	}
+	if role, ok := rapAdminTypes[f.Type]; ok {
+		return role
+	}
+	for _, n := range rapAdminNames {
+		if f.Name == n.suffix || strings.HasSuffix(f.Name, "_"+n.suffix) {
+			return n.role
+		}
+	}
+	return ""
+}
+
+// rapElementName converts a field name to a CDS element name:
+// travel_uuid → TravelUUID, agency_id → AgencyID.
+func rapElementName(field string) string {
+	if i := strings.LastIndex(field, "/"); i >= 0 {
+		field = field[i+1:]
+	}
+	var sb strings.Builder
+	for _, part := range strings.Split(strings.ToLower(field), "_") {
+		switch part {
+		case "":
+		case "id", "uuid":
+			sb.WriteString(strings.ToUpper(part))
+		default:
+			sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return sb.String()
+}
+
+// rapEntityName derives the entity alias from a table name: ZTRAVEL → Travel,
+// /DMO/A_TRAVEL_D → ATravelD.
+func rapEntityName(table string) string {
+	name := strings.ToLower(table)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	} else if strings.HasPrefix(name, "z") || strings.HasPrefix(name, "y") {
+		name = name[1:]
+	}
+	return rapElementName(name)
+}
+
+// tableNamespace returns the namespace of a name ("/DMO/") or "".
+func tableNamespace(name string) string {
+	if strings.HasPrefix(name, "/") {
+		if i := strings.Index(name[1:], "/"); i >= 0 {
+			return name[:i+2]
+		}
+	}
+	return ""
+}
+
+// rapFileName returns the abapGit file name of an object.
+func rapFileName(name, suffix string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "/", "#") + suffix
+}
+
+// GenerateRAPStack generates the sources of a managed RAP business object
+// for a table. Draft requires a field for the total ETag (data element
+// ABP_LASTCHANGE_TSTMPL or a *_LAST_CHANGED_AT field).
+func GenerateRAPStack(def *TableDefinition, opts RAPStackOptions) (*RAPStack, error) {
+	if def == nil || def.Name == "" {
+		return nil, fmt.Errorf("table definition is required")
+	}
+	if len(def.Includes) > 0 {
+		return nil, fmt.Errorf("includes of %s are not resolved", def.Name)
+	}
+
+	entity := opts.Entity
+	if entity == "" {
+		entity = rapEntityName(def.Name)
+	}
+	if !rapEntityRegex.MatchString(entity) {
+		return nil, fmt.Errorf("invalid entity name %q", entity)
+	}
+	prefix := strings.ToUpper(opts.Prefix)
+	if prefix == "" {
+		prefix = tableNamespace(def.Name)
+	}
+	if prefix == "" {
+		prefix = "Z"
+	}
+	version := strings.ToUpper(opts.BindingVersion)
+	if version == "" {
+		version = "V4"
+	}
+	if version != "V2" && version != "V4" {
+		return nil, fmt.Errorf("binding version must be V2 or V4, got %s", opts.BindingVersion)
+	}
+	description := opts.Description
+	if description == "" {
+		description = def.Description
+	}
+	if description == "" {
+		description = entity
+	}
+
+	upper := strings.ToUpper(entity)
+	s := &RAPStack{
+		Table:             def.Name,
+		Entity:            entity,
+		Description:       description,
+		Draft:             !opts.NoDraft,
+		RootView:          prefix + "R_" + upper,
+		ProjectionView:    prefix + "C_" + upper,
+		MetadataExtension: prefix + "C_" + upper,
+		BehaviorPool:      prefix + "BP_R_" + upper,
+		ServiceDefinition: prefix + "UI_" + upper,
+		ServiceBinding:    prefix + "UI_" + upper + "_O" + version[1:],
+		BindingVersion:    version,
+	}
+	for _, name := range []string{s.RootView, s.BehaviorPool, s.ServiceDefinition, s.ServiceBinding} {
+		if len(name) > 30 {
+			return nil, fmt.Errorf("object name %s is longer than 30 characters; use a shorter entity name", name)
+		}
+	}
+	if s.Draft {
+		ns := tableNamespace(def.Name)
+		base := def.Name[len(ns):]
+		if max := 16 - len(ns) - 2; len(base) > max {
+			base = base[:max]
+		}
+		s.DraftTable = ns + base + "_D"
+	}
+
+	// Classify fields
+	var fields []rapField
+	var keys []rapField
+	client := ""
+	roles := make(map[string]string) // role -> element
+	for _, f := range def.Fields {
+		rf := rapField{TableDefinitionField: f, Element: rapElementName(f.Name), Role: rapFieldRole(f)}
+		if rf.Element == "" {
+			return nil, fmt.Errorf("cannot derive an element name for field %s", f.Name)
+		}
+		if rf.Role == "client" {
+			client = f.Name
+			continue
+		}
+		if rf.Role != "" && roles[rf.Role] == "" {
+			roles[rf.Role] = rf.Element
+		} else if rf.Role != "" {
+			rf.Role = ""
+		}
+		if f.IsKey {
+			keys = append(keys, rf)
+		}
+		fields = append(fields, rf)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("table %s has no key fields besides the client", def.Name)
+	}
+	uuidKey := len(keys) == 1 && (keys[0].Type == "sysuuid_x16" || keys[0].Type == "abap.raw(16)")
+
+	if s.Draft && roles["lastChangedAt"] == "" {
+		return nil, fmt.Errorf("draft requires a total ETag field (data element ABP_LASTCHANGE_TSTMPL) in %s; add one or generate without draft", def.Name)
+	}
+	etag := roles["localInstanceLastChangedAt"]
+	if etag == "" {
+		etag = roles["lastChangedAt"]
+	}
+	if etag == "" {
+		s.Warnings = append(s.Warnings, "no last-changed timestamp field: the business object has no ETag and concurrent changes are not detected")
+	}
+	if client == "" {
+		s.Warnings = append(s.Warnings, fmt.Sprintf("%s is client-independent", def.Name))
+	}
+
+	gen := &rapGenerator{stack: s, def: def, fields: fields, keys: keys, uuidKey: uuidKey, etag: etag, roles: roles, client: client}
+	if s.Draft {
+		s.Artifacts = append(s.Artifacts, RAPArtifact{Type: "TABL", Name: s.DraftTable, Source: gen.draftTable()})
+	}
+	s.Artifacts = append(s.Artifacts,
+		RAPArtifact{Type: "DDLS", Name: s.RootView, FileName: rapFileName(s.RootView, ".ddls.asddls"), Source: gen.rootView()},
+		RAPArtifact{Type: "DDLS", Name: s.ProjectionView, FileName: rapFileName(s.ProjectionView, ".ddls.asddls"), Source: gen.projectionView()},
+		RAPArtifact{Type: "DDLX", Name: s.MetadataExtension, FileName: rapFileName(s.MetadataExtension, ".ddlx.asddlxs"), Source: gen.metadataExtension()},
+		RAPArtifact{Type: "BDEF", Name: s.RootView, FileName: rapFileName(s.RootView, ".bdef.asbdef"), Source: gen.behavior()},
+		RAPArtifact{Type: "BDEF", Name: s.ProjectionView, FileName: rapFileName(s.ProjectionView, ".bdef.asbdef"), Source: gen.projectionBehavior()},
+		RAPArtifact{Type: "CLAS", Name: s.BehaviorPool, FileName: rapFileName(s.BehaviorPool, ".clas.abap"), Source: gen.behaviorPool()},
+		RAPArtifact{Type: "CLAS", Name: s.BehaviorPool, Include: string(ClassIncludeImplementations), FileName: rapFileName(s.BehaviorPool, ".clas.locals_imp.abap"), Source: gen.behaviorHandler()},
+		RAPArtifact{Type: "SRVD", Name: s.ServiceDefinition, FileName: rapFileName(s.ServiceDefinition, ".srvd.srvdsrv"), Source: gen.serviceDefinition()},
+		RAPArtifact{Type: "SRVB", Name: s.ServiceBinding},
+	)
+	return s, nil
+}
+
+// ObjectURI returns the ADT URI of a generated object.
+func (a RAPArtifact) ObjectURI() string {
+	if a.Type == "TABL" {
+		return "/sap/bc/adt/ddic/tables/" + url.PathEscape(strings.ToLower(a.Name))
+	}
+	for _, t := range []CreatableObjectType{ObjectTypeDDLS, ObjectTypeDDLX, ObjectTypeBDEF, ObjectTypeClass, ObjectTypeSRVD, ObjectTypeSRVB} {
+		if strings.HasPrefix(string(t), a.Type+"/") {
+			return strings.ToLower(GetObjectURL(t, a.Name, ""))
+		}
+	}
+	return ""
+}
+
+// Objects returns the generated objects (one entry per object, without
+// class includes) for activation.
+func (s *RAPStack) Objects() []InactiveObject {
+	var objects []InactiveObject
+	for _, a := range s.Artifacts {
+		if a.Include != "" {
+			continue
+		}
+		objType := a.Type
+		switch a.Type {
+		case "TABL":
+			objType = string(ObjectTypeTable)
+		case "DDLS":
+			objType = string(ObjectTypeDDLS)
+		case "DDLX":
+			objType = string(ObjectTypeDDLX)
+		case "BDEF":
+			objType = string(ObjectTypeBDEF)
+		case "CLAS":
+			objType = string(ObjectTypeClass)
+		case "SRVD":
+			objType = string(ObjectTypeSRVD)
+		case "SRVB":
+			objType = string(ObjectTypeSRVB)
+		}
+		objects = append(objects, InactiveObject{URI: a.ObjectURI(), Type: objType, Name: a.Name})
+	}
+	return objects
+}
+
+// rapGenerator holds the classified fields while the sources are generated.
+type rapGenerator struct {
+	stack   *RAPStack
+	def     *TableDefinition
+	fields  []rapField // Without client
+	keys    []rapField
+	uuidKey bool
+	etag    string
+	roles   map[string]string
+	client  string
+}
+
+// element returns the element name of a table field, "" if unknown.
+func (g *rapGenerator) element(field string) string {
+	for _, f := range g.fields {
+		if strings.EqualFold(f.Name, field) {
+			return f.Element
+		}
+	}
+	return ""
+}
+
+// viewAnnotations returns the CDS annotations of a field: admin field
+// semantics and currency/unit references rewritten to element names.
+func (g *rapGenerator) viewAnnotations(f rapField) []string {
+	var annotations []string
+	if sem, ok := rapSemantics[f.Role]; ok {
+		annotations = append(annotations, sem)
+	}
+	for _, a := range f.Annotations {
+		if m := tableFieldRefRegex.FindStringSubmatch(a); m != nil {
+			if ref := g.element(m[2]); ref != "" {
+				annotations = append(annotations, fmt.Sprintf("%s: '%s'", m[1], ref))
+			}
+		}
+	}
+	return annotations
+}
+
+func (g *rapGenerator) rootView() string {
+	s := g.stack
+	var sb strings.Builder
+	sb.WriteString("@AccessControl.authorizationCheck: #NOT_REQUIRED\n")
+	fmt.Fprintf(&sb, "@EndUserText.label: '%s'\n", escapeQuote(s.Description))
+	fmt.Fprintf(&sb, "define root view entity %s\n", s.RootView)
+	fmt.Fprintf(&sb, "  as select from %s\n{\n", strings.ToLower(s.Table))
+	for i, f := range g.fields {
+		for _, a := range g.viewAnnotations(f) {
+			fmt.Fprintf(&sb, "  %s\n", a)
+		}
+		key := ""
+		if f.IsKey {
+			key = "key "
+		}
+		fmt.Fprintf(&sb, "  %s%s as %s%s\n", key, f.Name, f.Element, listSep(i, len(g.fields)))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (g *rapGenerator) projectionView() string {
+	s := g.stack
+	var sb strings.Builder
+	sb.WriteString("@AccessControl.authorizationCheck: #NOT_REQUIRED\n")
+	fmt.Fprintf(&sb, "@EndUserText.label: '%s'\n", escapeQuote(s.Description))
+	sb.WriteString("@Metadata.allowExtensions: true\n")
+	fmt.Fprintf(&sb, "define root view entity %s\n", s.ProjectionView)
+	sb.WriteString("  provider contract transactional_query\n")
+	fmt.Fprintf(&sb, "  as projection on %s\n{\n", s.RootView)
+	for i, f := range g.fields {
+		key := ""
+		if f.IsKey {
+			key = "key "
+		}
+		fmt.Fprintf(&sb, "  %s%s%s\n", key, f.Element, listSep(i, len(g.fields)))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// hidden tells whether a field is hidden on the UI: technical keys and
+// administrative fields.
+func (g *rapGenerator) hidden(f rapField) bool {
+	return (f.IsKey && g.uuidKey) || f.Role != ""
+}
+
+func (g *rapGenerator) metadataExtension() string {
+	s := g.stack
+	var sb strings.Builder
+	sb.WriteString("@Metadata.layer: #CUSTOMER\n")
+	sb.WriteString("@UI: {\n  headerInfo: {\n")
+	fmt.Fprintf(&sb, "    typeName: '%s',\n    typeNamePlural: '%ss'\n  }\n}\n", s.Entity, s.Entity)
+	fmt.Fprintf(&sb, "annotate entity %s with\n{\n", s.ProjectionView)
+
+	position := 0
+	for i, f := range g.fields {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		if i == 0 {
+			sb.WriteString("  @UI.facet: [ {\n")
+			sb.WriteString("    id: 'idIdentification',\n")
+			sb.WriteString("    type: #IDENTIFICATION_REFERENCE,\n")
+			fmt.Fprintf(&sb, "    label: '%s',\n", s.Entity)
+			sb.WriteString("    position: 10\n  } ]\n")
+		}
+		if g.hidden(f) {
+			fmt.Fprintf(&sb, "  @UI.hidden: true\n  %s;\n", f.Element)
+			continue
+		}
+		position += 10
+		sb.WriteString("  @UI: {\n")
+		fmt.Fprintf(&sb, "    lineItem: [ { position: %d, importance: #HIGH } ],\n", position)
+		if f.IsKey {
+			fmt.Fprintf(&sb, "    identification: [ { position: %d } ],\n", position)
+			fmt.Fprintf(&sb, "    selectionField: [ { position: %d } ]\n", position)
+		} else {
+			fmt.Fprintf(&sb, "    identification: [ { position: %d } ]\n", position)
+		}
+		fmt.Fprintf(&sb, "  }\n  %s;\n", f.Element)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (g *rapGenerator) behavior() string {
+	s := g.stack
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "managed implementation in class %s unique;\n", strings.ToLower(s.BehaviorPool))
+	sb.WriteString("strict ( 2 );\n")
+	if s.Draft {
+		sb.WriteString("with draft;\n")
+	}
+	fmt.Fprintf(&sb, "\ndefine behavior for %s alias %s\n", s.RootView, s.Entity)
+	fmt.Fprintf(&sb, "persistent table %s\n", strings.ToLower(s.Table))
+	if s.Draft {
+		fmt.Fprintf(&sb, "draft table %s\n", strings.ToLower(s.DraftTable))
+	}
+	if g.etag != "" {
+		fmt.Fprintf(&sb, "etag master %s\n", g.etag)
+	}
+	if s.Draft {
+		fmt.Fprintf(&sb, "lock master total etag %s\n", g.roles["lastChangedAt"])
+	} else {
+		sb.WriteString("lock master\n")
+	}
+	sb.WriteString("authorization master ( global )\n{\n")
+
+	var readOnly, keys []string
+	for _, f := range g.fields {
+		switch {
+		case f.IsKey:
+			keys = append(keys, f.Element)
+		case f.Role != "":
+			readOnly = append(readOnly, f.Element)
+		}
+	}
+	if g.uuidKey {
+		readOnly = append(append([]string{}, keys...), readOnly...)
+	}
+	if len(readOnly) > 0 {
+		fmt.Fprintf(&sb, "  field ( readonly )\n    %s;\n\n", strings.Join(readOnly, ",\n    "))
+	}
+	if g.uuidKey {
+		fmt.Fprintf(&sb, "  field ( numbering : managed )\n    %s;\n\n", keys[0])
+	} else {
+		fmt.Fprintf(&sb, "  field ( mandatory : create, readonly : update )\n    %s;\n\n", strings.Join(keys, ",\n    "))
+	}
+
+	sb.WriteString("  create;\n  update;\n  delete;\n\n")
+	if s.Draft {
+		sb.WriteString("  draft action Activate optimized;\n")
+		sb.WriteString("  draft action Discard;\n")
+		sb.WriteString("  draft action Edit;\n")
+		sb.WriteString("  draft action Resume;\n")
+		sb.WriteString("  draft determine action Prepare;\n\n")
+	}
+
+	fmt.Fprintf(&sb, "  mapping for %s\n  {\n", strings.ToLower(s.Table))
+	for _, f := range g.fields {
+		fmt.Fprintf(&sb, "    %s = %s;\n", f.Element, f.Name)
+	}
+	sb.WriteString("  }\n}\n")
+	return sb.String()
+}
+
+func (g *rapGenerator) projectionBehavior() string {
+	s := g.stack
+	var sb strings.Builder
+	sb.WriteString("projection;\nstrict ( 2 );\n")
+	if s.Draft {
+		sb.WriteString("use draft;\n")
+	}
+	fmt.Fprintf(&sb, "\ndefine behavior for %s alias %s\n", s.ProjectionView, s.Entity)
+	if g.etag != "" {
+		sb.WriteString("use etag\n")
+	}
+	sb.WriteString("{\n  use create;\n  use update;\n  use delete;\n")
+	if s.Draft {
+		sb.WriteString("\n  use action Activate;\n")
+		sb.WriteString("  use action Discard;\n")
+		sb.WriteString("  use action Edit;\n")
+		sb.WriteString("  use action Resume;\n")
+		sb.WriteString("  use action Prepare;\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (g *rapGenerator) behaviorPool() string {
+	s := g.stack
+	name := strings.ToLower(s.BehaviorPool)
+	return fmt.Sprintf("CLASS %s DEFINITION PUBLIC ABSTRACT FINAL FOR BEHAVIOR OF %s.\nENDCLASS.\n\nCLASS %s IMPLEMENTATION.\nENDCLASS.\n",
+		name, strings.ToLower(s.RootView), name)
+}
+
+func (g *rapGenerator) behaviorHandler() string {
+	handler := "lhc_" + strings.ToLower(g.stack.Entity)
+	if len(handler) > 30 {
+		handler = handler[:30]
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "CLASS %s DEFINITION INHERITING FROM cl_abap_behavior_handler.\n", handler)
+	sb.WriteString("  PRIVATE SECTION.\n")
+	sb.WriteString("    METHODS get_global_authorizations FOR GLOBAL AUTHORIZATION\n")
+	fmt.Fprintf(&sb, "      IMPORTING REQUEST requested_authorizations FOR %s RESULT result.\n", g.stack.Entity)
+	sb.WriteString("ENDCLASS.\n\n")
+	fmt.Fprintf(&sb, "CLASS %s IMPLEMENTATION.\n", handler)
+	sb.WriteString("  METHOD get_global_authorizations.\n  ENDMETHOD.\nENDCLASS.\n")
+	return sb.String()
+}
+
+func (g *rapGenerator) serviceDefinition() string {
+	s := g.stack
+	return fmt.Sprintf("@EndUserText.label: 'Service definition for %s'\ndefine service %s {\n  expose %s as %s;\n}\n",
+		s.ProjectionView, s.ServiceDefinition, s.ProjectionView, s.Entity)
+}
+
+// draftTable generates the draft table: the fields under their element
+// names plus the draft administrative data.
+func (g *rapGenerator) draftTable() string {
+	s := g.stack
+	table := strings.ToLower(s.DraftTable)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "@EndUserText.label : 'Draft table for %s'\n", s.RootView)
+	sb.WriteString("@AbapCatalog.enhancement.category : #EXTENSIBLE_ANY\n")
+	sb.WriteString("@AbapCatalog.tableCategory : #TRANSPARENT\n")
+	sb.WriteString("@AbapCatalog.deliveryClass : #A\n")
+	sb.WriteString("@AbapCatalog.dataMaintenance : #RESTRICTED\n")
+	fmt.Fprintf(&sb, "define table %s {\n\n", table)
+	if g.client != "" {
+		sb.WriteString("  key mandt : mandt not null;\n")
+	}
+	for _, f := range g.fields {
+		for _, a := range f.Annotations {
+			if m := tableFieldRefRegex.FindStringSubmatch(a); m != nil {
+				if ref := g.element(m[2]); ref != "" {
+					fmt.Fprintf(&sb, "  %s : '%s.%s'\n", m[1], table, strings.ToLower(ref))
+				}
+			}
+		}
+		if f.IsKey {
+			fmt.Fprintf(&sb, "  key %s : %s not null;\n", strings.ToLower(f.Element), f.Type)
+		} else {
+			fmt.Fprintf(&sb, "  %s : %s;\n", strings.ToLower(f.Element), f.Type)
+		}
+	}
+	sb.WriteString("  \"%admin\" : include sych_bdl_draft_admin_inc;\n\n}\n")
+	return sb.String()
+}
+
+func listSep(i, n int) string {
+	if i < n-1 {
+		return ","
+	}
+	return ""
+}
@@ -0,0 +1,243 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testTravelTable = `@EndUserText.label : 'Travel data'
+@AbapCatalog.enhancement.category : #NOT_EXTENSIBLE
+@AbapCatalog.tableCategory : #TRANSPARENT
+@AbapCatalog.deliveryClass : #A
+@AbapCatalog.dataMaintenance : #RESTRICTED
+define table ztravel {
+
+  key client      : abap.clnt not null;
+  key travel_uuid : sysuuid_x16 not null;
+  travel_id       : /dmo/travel_id; // readable ID
+  /* pricing */
+  @Semantics.amount.currencyCode : 'ztravel.currency_code'
+  total_price     : abap.curr(16, 2);
+  currency_code   : /dmo/currency_code
+    with foreign key [0..*,1] scurx
+      where currkey = ztravel.currency_code;
+  include zs_travel_admin;
+
+}`
+
+const testTravelAdmin = `@EndUserText.label : 'Admin data'
+define structure zs_travel_admin {
+  local_created_by      : abp_creation_user;
+  local_created_at      : abp_creation_tstmpl;
+  local_last_changed_by : abp_locinst_lastchange_user;
+  local_last_changed_at : abp_locinst_lastchange_tstmpl;
+  last_changed_at       : abp_lastchange_tstmpl;
+}`
+
+func TestParseTableDefinition(t *testing.T) {
+	def, err := ParseTableDefinition(testTravelTable)
+	if err != nil {
+		t.Fatalf("ParseTableDefinition failed: %v", err)
+	}
+	if def.Name != "ZTRAVEL" || def.Description != "Travel data" {
+		t.Errorf("name = %s, description = %s", def.Name, def.Description)
+	}
+	var names []string
+	for _, f := range def.Fields {
+		names = append(names, f.Name+":"+f.Type)
+	}
+	if got := strings.Join(names, " "); got != "client:abap.clnt travel_uuid:sysuuid_x16 travel_id:/dmo/travel_id total_price:abap.curr(16,2) currency_code:/dmo/currency_code" {
+		t.Errorf("fields = %s", got)
+	}
+	if !def.Fields[1].IsKey || !def.Fields[1].NotNull || def.Fields[2].IsKey {
+		t.Errorf("key flags = %+v", def.Fields[:3])
+	}
+	if len(def.Fields[3].Annotations) != 1 || def.Fields[3].Annotations[0] != "@Semantics.amount.currencyCode : 'ztravel.currency_code'" {
+		t.Errorf("annotations = %v", def.Fields[3].Annotations)
+	}
+	if len(def.Includes) != 1 || def.Includes[0].Name != "ZS_TRAVEL_ADMIN" || def.Includes[0].Position != 5 {
+		t.Errorf("includes = %+v", def.Includes)
+	}
+
+	for _, bad := range []string{"", "define table ztab { }", "define table ztab { key a b c; }"} {
+		if _, err := ParseTableDefinition(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestReadTableDefinition(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+		case "/sap/bc/adt/ddic/tables/ZTRAVEL/source/main":
+			fmt.Fprint(w, testTravelTable)
+		case "/sap/bc/adt/ddic/structures/ZS_TRAVEL_ADMIN/source/main":
+			fmt.Fprint(w, testTravelAdmin)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass")
+	def, err := client.ReadTableDefinition(context.Background(), "ztravel")
+	if err != nil {
+		t.Fatalf("ReadTableDefinition failed: %v", err)
+	}
+	if len(def.Fields) != 10 || def.Fields[5].Name != "local_created_by" || len(def.Includes) != 0 {
+		t.Errorf("fields = %+v", def.Fields)
+	}
+}
+
+func testTravelDefinition(t *testing.T) *TableDefinition {
+	t.Helper()
+	def, err := ParseTableDefinition(testTravelTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := ParseTableDefinition(testTravelAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	def.Fields = append(def.Fields, admin.Fields...)
+	def.Includes = nil
+	return def
+}
+
+func TestGenerateRAPStack(t *testing.T) {
+	stack, err := GenerateRAPStack(testTravelDefinition(t), RAPStackOptions{})
+	if err != nil {
+		t.Fatalf("GenerateRAPStack failed: %v", err)
+	}
+	if stack.Entity != "Travel" || stack.RootView != "ZR_TRAVEL" || stack.ProjectionView != "ZC_TRAVEL" ||
+		stack.BehaviorPool != "ZBP_R_TRAVEL" || stack.ServiceDefinition != "ZUI_TRAVEL" ||
+		stack.ServiceBinding != "ZUI_TRAVEL_O4" || stack.DraftTable != "ZTRAVEL_D" || !stack.Draft {
+		t.Errorf("names = %+v", stack)
+	}
+
+	sources := make(map[string]string)
+	var files []string
+	for _, a := range stack.Artifacts {
+		sources[a.Type+" "+a.Name+" "+a.Include] = a.Source
+		if a.FileName != "" {
+			files = append(files, a.FileName)
+		}
+	}
+	if got := strings.Join(files, " "); got != "zr_travel.ddls.asddls zc_travel.ddls.asddls zc_travel.ddlx.asddlxs zr_travel.bdef.asbdef "+
+		"zc_travel.bdef.asbdef zbp_r_travel.clas.abap zbp_r_travel.clas.locals_imp.abap zui_travel.srvd.srvdsrv" {
+		t.Errorf("files = %s", got)
+	}
+
+	root := sources["DDLS ZR_TRAVEL "]
+	for _, want := range []string{
+		"define root view entity ZR_TRAVEL\n  as select from ztravel\n{\n  key travel_uuid as TravelUUID,",
+		"  @Semantics.amount.currencyCode: 'CurrencyCode'\n  total_price as TotalPrice,",
+		"  @Semantics.systemDateTime.localInstanceLastChangedAt: true\n  local_last_changed_at as LocalLastChangedAt,",
+		"  last_changed_at as LastChangedAt\n}",
+	} {
+		if !strings.Contains(root, want) {
+			t.Errorf("root view lacks %q:\n%s", want, root)
+		}
+	}
+	if strings.Contains(root, "client") {
+		t.Errorf("root view exposes the client:\n%s", root)
+	}
+
+	bdef := sources["BDEF ZR_TRAVEL "]
+	for _, want := range []string{
+		"managed implementation in class zbp_r_travel unique;\nstrict ( 2 );\nwith draft;",
+		"persistent table ztravel\ndraft table ztravel_d\netag master LocalLastChangedAt\nlock master total etag LastChangedAt",
+		"field ( numbering : managed )\n    TravelUUID;",
+		"draft determine action Prepare;",
+		"    TotalPrice = total_price;",
+	} {
+		if !strings.Contains(bdef, want) {
+			t.Errorf("behavior definition lacks %q:\n%s", want, bdef)
+		}
+	}
+
+	draft := sources["TABL ZTRAVEL_D "]
+	for _, want := range []string{
+		"define table ztravel_d {",
+		"  key mandt : mandt not null;\n  key traveluuid : sysuuid_x16 not null;",
+		"  @Semantics.amount.currencyCode : 'ztravel_d.currencycode'\n  totalprice : abap.curr(16,2);",
+		`"%admin" : include sych_bdl_draft_admin_inc;`,
+	} {
+		if !strings.Contains(draft, want) {
+			t.Errorf("draft table lacks %q:\n%s", want, draft)
+		}
+	}
+
+	// The metadata extension is valid DDLX: keys and admin fields hidden, the rest positioned
+	ext, err := ParseCDSMetadataExtension(sources["DDLX ZC_TRAVEL "])
+	if err != nil {
+		t.Fatalf("generated DDLX does not parse: %v\n%s", err, sources["DDLX ZC_TRAVEL "])
+	}
+	if ext.Entity != "ZC_TRAVEL" || formatTestAnnotations(ext.Elements["LASTCHANGEDAT"]) != "UI.HIDDEN=true" {
+		t.Errorf("metadata extension = %+v", ext)
+	}
+	if got := formatTestAnnotations(ext.Elements["CURRENCYCODE"]); got != "UI.LINEITEM$1$.POSITION=30 UI.LINEITEM$1$.IMPORTANCE=#HIGH UI.IDENTIFICATION$1$.POSITION=30" {
+		t.Errorf("CurrencyCode = %s", got)
+	}
+
+	if !strings.Contains(sources["CLAS ZBP_R_TRAVEL implementations"], "FOR Travel RESULT result.") {
+		t.Errorf("handler = %s", sources["CLAS ZBP_R_TRAVEL implementations"])
+	}
+	if objects := stack.Objects(); len(objects) != 9 || objects[0].URI != "/sap/bc/adt/ddic/tables/ztravel_d" ||
+		objects[8].URI != "/sap/bc/adt/businessservices/bindings/zui_travel_o4" {
+		t.Errorf("objects = %+v", objects)
+	}
+}
+
+func TestGenerateRAPStackOptions(t *testing.T) {
+	def := &TableDefinition{Name: "/DMO/A_FLIGHT", Fields: []TableDefinitionField{
+		{Name: "client", Type: "abap.clnt", IsKey: true},
+		{Name: "carrier_id", Type: "/dmo/carrier_id", IsKey: true},
+		{Name: "connection_id", Type: "/dmo/connection_id", IsKey: true},
+		{Name: "price", Type: "/dmo/flight_price"},
+	}}
+
+	// Draft needs a total ETag field
+	if _, err := GenerateRAPStack(def, RAPStackOptions{}); err == nil || !strings.Contains(err.Error(), "ETag") {
+		t.Errorf("expected ETag error, got %v", err)
+	}
+
+	stack, err := GenerateRAPStack(def, RAPStackOptions{Entity: "Flight", NoDraft: true, BindingVersion: "v2"})
+	if err != nil {
+		t.Fatalf("GenerateRAPStack failed: %v", err)
+	}
+	if stack.RootView != "/DMO/R_FLIGHT" || stack.ServiceBinding != "/DMO/UI_FLIGHT_O2" || stack.DraftTable != "" || len(stack.Warnings) != 1 {
+		t.Errorf("stack = %+v", stack)
+	}
+	for _, a := range stack.Artifacts {
+		switch {
+		case a.Type == "TABL":
+			t.Error("draft table generated without draft")
+		case a.Type == "BDEF" && a.Name == "/DMO/R_FLIGHT":
+			if !strings.Contains(a.Source, "field ( mandatory : create, readonly : update )\n    CarrierID,\n    ConnectionID;") ||
+				strings.Contains(a.Source, "draft") || !strings.Contains(a.Source, "lock master\n") {
+				t.Errorf("behavior definition:\n%s", a.Source)
+			}
+		case a.Type == "CLAS" && a.Include == "":
+			if a.FileName != "#dmo#bp_r_flight.clas.abap" {
+				t.Errorf("class file = %s", a.FileName)
+			}
+		}
+	}
+
+	for _, opts := range []RAPStackOptions{
+		{Entity: "Flight Data"},
+		{NoDraft: true, BindingVersion: "V3"},
+		{NoDraft: true, Entity: "AVeryLongEntityNameThatDoesNotFit"},
+	} {
+		if _, err := GenerateRAPStack(def, opts); err == nil {
+			t.Errorf("expected error for %+v", opts)
+		}
+	}
+}
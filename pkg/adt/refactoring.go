@@ -0,0 +1,467 @@
+package adt
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Refactoring kinds.
+const (
+	RefactoringRename          = "rename"
+	RefactoringExtractMethod   = "extractMethod"
+	RefactoringChangeSignature = "changeSignature"
+)
+
+// ADT refactoring relations.
+const (
+	refactoringRelRename        = "http://www.sap.com/adt/relations/refactoring/rename"
+	refactoringRelExtractMethod = "http://www.sap.com/adt/relations/refactoring/extractmethod"
+)
+
+// RefactoringChange is the effect of a refactoring on one source.
+type RefactoringChange struct {
+	ObjectURL string             `json:"objectUrl"`
+	SourceURL string             `json:"sourceUrl"`
+	Name      string             `json:"name"`
+	Type      string             `json:"type,omitempty"`
+	Deltas    []ATCQuickFixDelta `json:"deltas,omitempty"`
+	Diff      string             `json:"diff"`
+
+	oldSource string
+	newSource string
+}
+
+// RefactoringPreview lists every change a refactoring makes. Pass it to
+// ExecuteRefactoring to apply it.
+type RefactoringPreview struct {
+	Kind     string              `json:"kind"`
+	Title    string              `json:"title,omitempty"`
+	Changes  []RefactoringChange `json:"changes"`
+	Warnings []string            `json:"warnings,omitempty"`
+
+	relation string // ADT refactoring relation, empty for client-side refactorings
+	document string // ADT refactoring document for the execute step
+}
+
+// Diff returns the diffs of all changes.
+func (p *RefactoringPreview) Diff() string {
+	var sb strings.Builder
+	for _, ch := range p.Changes {
+		sb.WriteString(ch.Diff)
+		if !strings.HasSuffix(ch.Diff, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// RefactoringOptions configures ExecuteRefactoring.
+type RefactoringOptions struct {
+	Transport      string // Transport request (required for non-local packages)
+	SkipActivation bool   // Leave the changed objects inactive
+}
+
+// RefactoringResult is the result of ExecuteRefactoring.
+type RefactoringResult struct {
+	Success    bool                  `json:"success"`
+	Preview    *RefactoringPreview   `json:"preview"`
+	Activation *ActivationPlanResult `json:"activation,omitempty"`
+	RolledBack bool                  `json:"rolledBack,omitempty"`
+	Message    string                `json:"message"`
+	Errors     []string              `json:"errors,omitempty"`
+}
+
+// PreviewRename previews renaming the identifier at a source position
+// (method, attribute, local variable, class, ...) in all objects that use
+// it. Line and column are 1-based; the column may point anywhere in the
+// identifier.
+func (c *Client) PreviewRename(ctx context.Context, sourceURL string, line, column int, newName string) (*RefactoringPreview, error) {
+	if err := c.checkSafety(OpRead, "PreviewRename"); err != nil {
+		return nil, err
+	}
+	if newName == "" {
+		return nil, fmt.Errorf("new name is required")
+	}
+	sourceURL = refactoringSourceURL(sourceURL)
+	source, err := c.readATCSource(ctx, sourceURL)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(normalizeLineEndings(source), "\n")
+	if line < 1 || line > len(lines) {
+		return nil, fmt.Errorf("line %d is outside the source", line)
+	}
+	start, end := identifierBounds(lines[line-1], column-1)
+	if start == end {
+		return nil, fmt.Errorf("no identifier at line %d, column %d", line, column)
+	}
+	oldName := lines[line-1][start:end]
+
+	uri := fmt.Sprintf("%s#start=%d,%d;end=%d,%d", sourceURL, line, start, line, end)
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<rename:renameRefactoring xmlns:adtcore="http://www.sap.com/adt/core" xmlns:generic="http://www.sap.com/adt/refactoring/genericrefactoring" xmlns:rename="http://www.sap.com/adt/refactoring/renamerefactoring">
+  <rename:oldName>%s</rename:oldName>
+  <rename:newName>%s</rename:newName>
+  <generic:genericRefactoring>
+    <generic:title>Rename %s</generic:title>
+    <generic:adtObjectUri>%s</generic:adtObjectUri>
+    <generic:affectedObjects/>
+    <generic:transport/>
+    <generic:ignoreSyntaxErrorsAllowed>false</generic:ignoreSyntaxErrorsAllowed>
+    <generic:ignoreSyntaxErrors>false</generic:ignoreSyntaxErrors>
+  </generic:genericRefactoring>
+  <rename:userContent/>
+</rename:renameRefactoring>`, xmlEscape(oldName), xmlEscape(newName), xmlEscape(oldName), xmlEscape(uri))
+
+	doc, err := c.refactoringStep(ctx, "evaluate", refactoringRelRename, uri, body)
+	if err != nil {
+		return nil, err
+	}
+	doc, ok := setRefactoringElement(doc, "rename:newName", newName)
+	if !ok {
+		return nil, fmt.Errorf("rename of %s is not possible at this position", oldName)
+	}
+	doc, err = c.refactoringStep(ctx, "preview", refactoringRelRename, "", doc)
+	if err != nil {
+		return nil, err
+	}
+	return c.refactoringPreview(ctx, RefactoringRename, refactoringRelRename, doc)
+}
+
+// PreviewExtractMethod previews extracting the statements of a line range
+// (1-based, inclusive) into a new method of the same class.
+func (c *Client) PreviewExtractMethod(ctx context.Context, sourceURL string, startLine, endLine int, methodName string) (*RefactoringPreview, error) {
+	if err := c.checkSafety(OpRead, "PreviewExtractMethod"); err != nil {
+		return nil, err
+	}
+	if methodName == "" {
+		return nil, fmt.Errorf("method name is required")
+	}
+	sourceURL = refactoringSourceURL(sourceURL)
+	source, err := c.readATCSource(ctx, sourceURL)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(normalizeLineEndings(source), "\n")
+	if endLine == 0 {
+		endLine = startLine
+	}
+	if startLine < 1 || endLine < startLine || endLine > len(lines) {
+		return nil, fmt.Errorf("line range %d-%d is outside the source", startLine, endLine)
+	}
+
+	uri := fmt.Sprintf("%s#start=%d,0;end=%d,%d", sourceURL, startLine, endLine, len(lines[endLine-1]))
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<extractmethod:extractMethodRefactoring xmlns:adtcore="http://www.sap.com/adt/core" xmlns:generic="http://www.sap.com/adt/refactoring/genericrefactoring" xmlns:extractmethod="http://www.sap.com/adt/refactoring/extractmethodrefactoring">
+  <extractmethod:name>%s</extractmethod:name>
+  <generic:genericRefactoring>
+    <generic:title>Extract Method</generic:title>
+    <generic:adtObjectUri>%s</generic:adtObjectUri>
+    <generic:affectedObjects/>
+    <generic:transport/>
+    <generic:ignoreSyntaxErrorsAllowed>false</generic:ignoreSyntaxErrorsAllowed>
+    <generic:ignoreSyntaxErrors>false</generic:ignoreSyntaxErrors>
+  </generic:genericRefactoring>
+</extractmethod:extractMethodRefactoring>`, xmlEscape(methodName), xmlEscape(uri))
+
+	doc, err := c.refactoringStep(ctx, "evaluate", refactoringRelExtractMethod, uri, body)
+	if err != nil {
+		return nil, err
+	}
+	doc, ok := setRefactoringElement(doc, "extractmethod:name", methodName)
+	if !ok {
+		return nil, fmt.Errorf("lines %d-%d cannot be extracted into a method", startLine, endLine)
+	}
+	doc, err = c.refactoringStep(ctx, "preview", refactoringRelExtractMethod, "", doc)
+	if err != nil {
+		return nil, err
+	}
+	return c.refactoringPreview(ctx, RefactoringExtractMethod, refactoringRelExtractMethod, doc)
+}
+
+// ExecuteRefactoring applies a previewed refactoring and activates the
+// changed objects together. If saving or activation fails, the previous
+// sources are written back and activated again.
+func (c *Client) ExecuteRefactoring(ctx context.Context, preview *RefactoringPreview, opts RefactoringOptions) (*RefactoringResult, error) {
+	if err := c.checkSafety(OpUpdate, "ExecuteRefactoring"); err != nil {
+		return nil, err
+	}
+	if err := c.checkTransportableEdit(opts.Transport, "ExecuteRefactoring"); err != nil {
+		return nil, err
+	}
+	if preview == nil || len(preview.Changes) == 0 {
+		return nil, fmt.Errorf("refactoring has no changes")
+	}
+	for _, ch := range preview.Changes {
+		if ch.oldSource == "" && ch.newSource == "" {
+			return nil, fmt.Errorf("refactoring preview of %s has no sources; create it with a Preview function", ch.Name)
+		}
+	}
+
+	result := &RefactoringResult{Preview: preview}
+	var written []RefactoringChange
+	var execErr error
+	if preview.document != "" {
+		// A failed execute may have saved some of the objects already, so
+		// all of them are restored
+		doc, _ := setRefactoringElement(preview.document, "generic:transport", opts.Transport)
+		_, execErr = c.refactoringStep(ctx, "execute", preview.relation, "", doc)
+		written = preview.Changes
+	} else {
+		for _, ch := range preview.Changes {
+			if execErr = c.writeRefactoringSource(ctx, ch, ch.newSource, opts.Transport); execErr != nil {
+				break
+			}
+			written = append(written, ch)
+		}
+	}
+	if execErr != nil {
+		result.Errors = append(result.Errors, execErr.Error())
+		result.Message = fmt.Sprintf("Refactoring failed: %v", execErr)
+		c.rollbackRefactoring(ctx, written, opts, result)
+		return result, nil
+	}
+
+	if !opts.SkipActivation {
+		activation, err := c.ActivateObjectsPlanned(ctx, refactoringObjects(preview.Changes), ActivationPlanOptions{SkipCDSDependencies: true})
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			result.Message = fmt.Sprintf("Refactoring saved but activation failed: %v", err)
+			c.rollbackRefactoring(ctx, written, opts, result)
+			return result, nil
+		}
+		result.Activation = activation
+		if len(activation.Failed) > 0 {
+			for _, f := range activation.Failed {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s (%s): %s", f.Name, f.Type, f.Reason))
+			}
+			result.Message = fmt.Sprintf("Activation of %d objects failed", len(activation.Failed))
+			c.rollbackRefactoring(ctx, written, opts, result)
+			return result, nil
+		}
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("Changed %d sources", len(preview.Changes))
+	if !opts.SkipActivation {
+		result.Message += " and activated them"
+	}
+	return result, nil
+}
+
+// rollbackRefactoring writes the previous sources back and activates them.
+func (c *Client) rollbackRefactoring(ctx context.Context, written []RefactoringChange, opts RefactoringOptions, result *RefactoringResult) {
+	if len(written) == 0 {
+		return
+	}
+	restored := true
+	for _, ch := range written {
+		if err := c.writeRefactoringSource(ctx, ch, ch.oldSource, opts.Transport); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("rollback of %s: %v", ch.Name, err))
+			restored = false
+		}
+	}
+	if restored && !opts.SkipActivation {
+		if activation, err := c.ActivateObjectsPlanned(ctx, refactoringObjects(written), ActivationPlanOptions{SkipCDSDependencies: true}); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("rollback activation: %v", err))
+		} else if len(activation.Failed) > 0 {
+			result.Errors = append(result.Errors, fmt.Sprintf("rollback activation: %s", activation.Summary))
+		}
+	}
+	result.RolledBack = restored
+	if restored {
+		result.Message += "; previous sources restored"
+	} else {
+		result.Message += "; ROLLBACK INCOMPLETE, check the objects"
+	}
+}
+
+// writeRefactoringSource saves one source under a lock.
+func (c *Client) writeRefactoringSource(ctx context.Context, ch RefactoringChange, source, transport string) error {
+	lock, err := c.LockObject(ctx, ch.ObjectURL, "MODIFY")
+	if err != nil {
+		return fmt.Errorf("locking %s: %w", ch.Name, err)
+	}
+	defer c.UnlockObject(ctx, ch.ObjectURL, lock.LockHandle)
+
+	if err := c.UpdateSource(ctx, ch.SourceURL, source, lock.LockHandle, transport); err != nil {
+		return fmt.Errorf("updating %s: %w", ch.Name, err)
+	}
+	return nil
+}
+
+// refactoringObjects returns the objects of the changes for activation.
+func refactoringObjects(changes []RefactoringChange) []InactiveObject {
+	seen := make(map[string]bool)
+	var objects []InactiveObject
+	for _, ch := range changes {
+		key := strings.ToLower(ch.ObjectURL)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		objects = append(objects, InactiveObject{URI: ch.ObjectURL, Type: ch.Type, Name: ch.Name})
+	}
+	return objects
+}
+
+// refactoringStep runs one step (evaluate, preview, execute) of an ADT
+// refactoring and returns the refactoring document.
+func (c *Client) refactoringStep(ctx context.Context, step, relation, uri, body string) (string, error) {
+	query := url.Values{}
+	query.Set("step", step)
+	query.Set("rel", relation)
+	if uri != "" {
+		query.Set("uri", uri)
+	}
+	resp, err := c.transport.Request(ctx, "/sap/bc/adt/refactorings", &RequestOptions{
+		Method:      http.MethodPost,
+		Query:       query,
+		Body:        []byte(body),
+		ContentType: "application/*",
+		Accept:      "application/*",
+	})
+	if err != nil {
+		return "", fmt.Errorf("refactoring %s failed: %w", step, err)
+	}
+	return string(resp.Body), nil
+}
+
+// setRefactoringElement sets the text of an element of a refactoring
+// document. It reports false if the element does not exist.
+func setRefactoringElement(doc, element, value string) (string, bool) {
+	re := regexp.MustCompile(`<` + regexp.QuoteMeta(element) + `(\s[^>]*)?(/>|>[^<]*</` + regexp.QuoteMeta(element) + `>)`)
+	loc := re.FindStringSubmatchIndex(doc)
+	if loc == nil {
+		return doc, false
+	}
+	attrs := ""
+	if loc[2] >= 0 {
+		attrs = doc[loc[2]:loc[3]]
+	}
+	replacement := fmt.Sprintf("<%s%s>%s</%s>", element, attrs, xmlEscape(value), element)
+	return doc[:loc[0]] + replacement + doc[loc[1]:], true
+}
+
+// refactoringPreview reads the affected sources of a refactoring document
+// and applies its text deltas.
+func (c *Client) refactoringPreview(ctx context.Context, kind, relation, doc string) (*RefactoringPreview, error) {
+	xmlStr := doc
+	for _, prefix := range []string{"generic:", "adtcore:", "rename:", "extractmethod:"} {
+		xmlStr = strings.ReplaceAll(xmlStr, prefix, "")
+	}
+	var parsed struct {
+		Title   string `xml:"genericRefactoring>title"`
+		Objects []struct {
+			URI    string `xml:"uri,attr"`
+			Name   string `xml:"name,attr"`
+			Type   string `xml:"type,attr"`
+			Deltas []struct {
+				Range      string `xml:"rangeFragment"`
+				ContentNew string `xml:"contentNew"`
+			} `xml:"textReplaceDeltas>textReplaceDelta"`
+		} `xml:"genericRefactoring>affectedObjects>affectedObject"`
+	}
+	if err := xml.Unmarshal([]byte(xmlStr), &parsed); err != nil {
+		return nil, fmt.Errorf("parsing refactoring: %w", err)
+	}
+
+	preview := &RefactoringPreview{Kind: kind, Title: parsed.Title, relation: relation, document: doc}
+	for _, obj := range parsed.Objects {
+		if len(obj.Deltas) == 0 {
+			continue
+		}
+		sourceURL := refactoringSourceURL(obj.URI)
+		var deltas []ATCQuickFixDelta
+		for _, d := range obj.Deltas {
+			m := quickFixRangeRegex.FindStringSubmatch(d.Range)
+			if m == nil {
+				return nil, fmt.Errorf("invalid refactoring range %q", d.Range)
+			}
+			delta := ATCQuickFixDelta{URI: sourceURL, Content: d.ContentNew}
+			delta.StartLine, _ = strconv.Atoi(m[1])
+			delta.StartCol, _ = strconv.Atoi(m[2])
+			delta.EndLine, delta.EndCol = delta.StartLine, delta.StartCol
+			if m[3] != "" {
+				delta.EndLine, _ = strconv.Atoi(m[3])
+				delta.EndCol, _ = strconv.Atoi(m[4])
+			}
+			deltas = append(deltas, delta)
+		}
+
+		oldSource, err := c.readATCSource(ctx, sourceURL)
+		if err != nil {
+			return nil, err
+		}
+		newSource, err := applyQuickFixDeltas(oldSource, deltas)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", obj.Name, err)
+		}
+		ch := newRefactoringChange(sourceURL, oldSource, newSource)
+		if obj.Name != "" {
+			ch.Name = strings.ToUpper(obj.Name)
+		}
+		if obj.Type != "" {
+			ch.Type = obj.Type
+		}
+		ch.Deltas = deltas
+		preview.Changes = append(preview.Changes, ch)
+	}
+	sort.SliceStable(preview.Changes, func(i, j int) bool {
+		return preview.Changes[i].SourceURL < preview.Changes[j].SourceURL
+	})
+	return preview, nil
+}
+
+// newRefactoringChange creates the change of one source with its diff.
+func newRefactoringChange(sourceURL, oldSource, newSource string) RefactoringChange {
+	objectURL := atcMainObjectURL(sourceURL)
+	oldSource = normalizeLineEndings(oldSource)
+	newSource = normalizeLineEndings(newSource)
+	return RefactoringChange{
+		ObjectURL: objectURL,
+		SourceURL: sourceURL,
+		Name:      impactObjectName(objectURL),
+		Type:      extractTypeFromURI(objectURL),
+		Diff:      generateUnifiedDiff(sourceURL, sourceURL, strings.Split(oldSource, "\n"), strings.Split(newSource, "\n")),
+		oldSource: oldSource,
+		newSource: newSource,
+	}
+}
+
+// refactoringSourceURL returns the source URL of an object or include URI.
+func refactoringSourceURL(uri string) string {
+	uri = atcSourceURL(uri)
+	if strings.Contains(uri, "/source/") || strings.Contains(uri, "/includes/") {
+		return uri
+	}
+	return strings.TrimSuffix(uri, "/") + "/source/main"
+}
+
+// identifierBounds returns the byte range of the ABAP identifier at col
+// (0-based) of a line.
+func identifierBounds(line string, col int) (int, int) {
+	if col < 0 || col >= len(line) || !isABAPIdentifierChar(line[col]) {
+		return col, col
+	}
+	start, end := col, col
+	for start > 0 && isABAPIdentifierChar(line[start-1]) {
+		start--
+	}
+	for end < len(line) && isABAPIdentifierChar(line[end]) {
+		end++
+	}
+	return start, end
+}
+
+// isABAPIdentifierChar reports whether b can be part of an ABAP name.
+func isABAPIdentifierChar(b byte) bool {
+	return b == '_' || b == '/' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
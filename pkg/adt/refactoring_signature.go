@@ -0,0 +1,794 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SignatureParameter is a parameter added by a signature change.
+type SignatureParameter struct {
+	Kind        string `json:"kind,omitempty"` // importing (default), exporting, changing, returning
+	Name        string `json:"name"`
+	Type        string `json:"type"` // e.g. "string", "REF TO zcl_foo", "LIKE ms_data"
+	Optional    bool   `json:"optional,omitempty"`
+	Default     string `json:"default,omitempty"`
+	CallerValue string `json:"callerValue,omitempty"` // Passed by existing calls (importing and changing)
+}
+
+// SignatureChange describes a change of a method signature.
+type SignatureChange struct {
+	Add    []SignatureParameter `json:"add,omitempty"`
+	Remove []string             `json:"remove,omitempty"`
+	Rename map[string]string    `json:"rename,omitempty"` // Old name -> new name
+}
+
+// Parameter sections of a method definition and the matching sections of
+// a call.
+var signatureSections = []struct{ definition, call string }{
+	{"IMPORTING", "EXPORTING"},
+	{"EXPORTING", "IMPORTING"},
+	{"CHANGING", "CHANGING"},
+	{"RETURNING", "RECEIVING"},
+}
+
+// ABAP character classes.
+const (
+	abapCode byte = iota
+	abapLiteral
+	abapComment
+)
+
+// methodParameter is a parameter of a method definition.
+type methodParameter struct {
+	section string   // IMPORTING, EXPORTING, CHANGING, RETURNING
+	name    string   // Upper case, without VALUE( )
+	tokens  []string // Declaration, starting with the name
+}
+
+func (p methodParameter) optional() bool {
+	for _, t := range p.tokens[1:] {
+		if u := strings.ToUpper(t); u == "OPTIONAL" || u == "DEFAULT" {
+			return true
+		}
+	}
+	return false
+}
+
+// methodSignature is a parsed METHODS statement.
+type methodSignature struct {
+	head       string   // Statement up to and including the method name
+	options    []string // Tokens between the name and the first section
+	params     []methodParameter
+	preferred  string
+	exceptions []string // RAISING or EXCEPTIONS section, with the keyword
+}
+
+// param returns the index of a parameter or -1.
+func (s *methodSignature) param(name string) int {
+	for i, p := range s.params {
+		if p.name == strings.ToUpper(strings.TrimPrefix(name, "!")) {
+			return i
+		}
+	}
+	return -1
+}
+
+// positionalParameter returns the importing parameter that receives the
+// value of a call with a single unnamed actual parameter.
+func (s *methodSignature) positionalParameter() string {
+	if s.preferred != "" {
+		return s.preferred
+	}
+	var importing, required []string
+	for _, p := range s.params {
+		if p.section != "IMPORTING" {
+			continue
+		}
+		importing = append(importing, p.name)
+		if !p.optional() {
+			required = append(required, p.name)
+		}
+	}
+	if len(importing) == 1 {
+		return importing[0]
+	}
+	if len(required) == 1 {
+		return required[0]
+	}
+	return ""
+}
+
+// render formats the signature as a METHODS statement.
+func (s *methodSignature) render() string {
+	indent := s.head[:len(s.head)-len(strings.TrimLeft(s.head, " \t"))]
+	var sb strings.Builder
+	sb.WriteString(s.head)
+	if len(s.options) > 0 {
+		sb.WriteString(" " + strings.Join(s.options, " "))
+	}
+	for _, section := range signatureSections {
+		first := true
+		for _, p := range s.params {
+			if p.section != section.definition {
+				continue
+			}
+			if first {
+				fmt.Fprintf(&sb, "\n%s  %s", indent, section.definition)
+				first = false
+			}
+			fmt.Fprintf(&sb, "\n%s    %s", indent, strings.Join(p.tokens, " "))
+		}
+		if section.definition == "IMPORTING" && s.preferred != "" && !first {
+			fmt.Fprintf(&sb, "\n%s    PREFERRED PARAMETER %s", indent, strings.ToLower(s.preferred))
+		}
+	}
+	if len(s.exceptions) > 0 {
+		fmt.Fprintf(&sb, "\n%s  %s", indent, s.exceptions[0])
+		for _, t := range s.exceptions[1:] {
+			fmt.Fprintf(&sb, "\n%s    %s", indent, t)
+		}
+	}
+	sb.WriteString(".")
+	return sb.String()
+}
+
+var methodParamNameRegex = regexp.MustCompile(`(?i)^(VALUE|REFERENCE)\((.+)\)$`)
+
+// parseMethodSignature parses the tokens of a METHODS statement after the
+// method name.
+func parseMethodSignature(head string, tokens []string) (*methodSignature, error) {
+	sig := &methodSignature{head: head}
+	section := ""
+	var current *methodParameter
+	flush := func() {
+		if current != nil {
+			sig.params = append(sig.params, *current)
+			current = nil
+		}
+	}
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		u := strings.ToUpper(t)
+		switch u {
+		case "IMPORTING", "EXPORTING", "CHANGING", "RETURNING":
+			flush()
+			section = u
+			continue
+		case "RAISING", "EXCEPTIONS":
+			flush()
+			sig.exceptions = append(sig.exceptions, tokens[i:]...)
+			return sig, nil
+		case "REDEFINITION":
+			return nil, fmt.Errorf("the signature of a redefinition cannot be changed")
+		}
+		if section == "" {
+			sig.options = append(sig.options, t)
+			continue
+		}
+		if u == "PREFERRED" && i+2 < len(tokens) && strings.EqualFold(tokens[i+1], "PARAMETER") {
+			sig.preferred = strings.ToUpper(tokens[i+2])
+			i += 2
+			continue
+		}
+		next := ""
+		if i+1 < len(tokens) {
+			next = strings.ToUpper(tokens[i+1])
+		}
+		if current == nil || next == "TYPE" || next == "LIKE" || (!current.typed() && u != "TYPE" && u != "LIKE") {
+			flush()
+			name := t
+			if m := methodParamNameRegex.FindStringSubmatch(t); m != nil {
+				name = m[2]
+			}
+			current = &methodParameter{section: section, name: strings.ToUpper(strings.TrimPrefix(name, "!")), tokens: []string{t}}
+			continue
+		}
+		current.tokens = append(current.tokens, t)
+	}
+	flush()
+	return sig, nil
+}
+
+func (p *methodParameter) typed() bool {
+	for _, t := range p.tokens[1:] {
+		if u := strings.ToUpper(t); u == "TYPE" || u == "LIKE" {
+			return true
+		}
+	}
+	return false
+}
+
+// apply changes the signature.
+func (s *methodSignature) apply(change SignatureChange) error {
+	for _, name := range change.Remove {
+		i := s.param(name)
+		if i < 0 {
+			return fmt.Errorf("parameter %s not found", strings.ToUpper(name))
+		}
+		if s.preferred == s.params[i].name {
+			s.preferred = ""
+		}
+		s.params = append(s.params[:i], s.params[i+1:]...)
+	}
+	for oldName, newName := range change.Rename {
+		i := s.param(oldName)
+		if i < 0 {
+			return fmt.Errorf("parameter %s not found", strings.ToUpper(oldName))
+		}
+		if j := s.param(newName); j >= 0 && j != i {
+			return fmt.Errorf("parameter %s already exists", strings.ToUpper(newName))
+		}
+		p := &s.params[i]
+		if m := methodParamNameRegex.FindStringSubmatch(p.tokens[0]); m != nil {
+			p.tokens[0] = m[1] + "(" + newName + ")"
+		} else {
+			p.tokens[0] = newName
+		}
+		if s.preferred == p.name {
+			s.preferred = strings.ToUpper(newName)
+		}
+		p.name = strings.ToUpper(newName)
+	}
+	for _, add := range change.Add {
+		section := strings.ToUpper(add.Kind)
+		if section == "" {
+			section = "IMPORTING"
+		}
+		if section != "IMPORTING" && section != "EXPORTING" && section != "CHANGING" && section != "RETURNING" {
+			return fmt.Errorf("invalid parameter kind %q", add.Kind)
+		}
+		if add.Name == "" || add.Type == "" {
+			return fmt.Errorf("added parameters need a name and a type")
+		}
+		if s.param(add.Name) >= 0 {
+			return fmt.Errorf("parameter %s already exists", strings.ToUpper(add.Name))
+		}
+		required := !add.Optional && add.Default == ""
+		if (section == "IMPORTING" || section == "CHANGING") && required && add.CallerValue == "" {
+			return fmt.Errorf("parameter %s is required: set optional, a default or the value existing calls pass", strings.ToUpper(add.Name))
+		}
+		name := add.Name
+		if section == "RETURNING" {
+			for _, p := range s.params {
+				if p.section == "RETURNING" {
+					return fmt.Errorf("method already has the returning parameter %s", p.name)
+				}
+			}
+			name = "VALUE(" + add.Name + ")"
+		}
+		tokens := []string{name}
+		typ := strings.Fields(add.Type)
+		if u := strings.ToUpper(typ[0]); u != "TYPE" && u != "LIKE" {
+			tokens = append(tokens, "TYPE")
+		}
+		tokens = append(tokens, typ...)
+		if add.Default != "" {
+			tokens = append(tokens, "DEFAULT", add.Default)
+		} else if add.Optional && section != "EXPORTING" && section != "RETURNING" {
+			tokens = append(tokens, "OPTIONAL")
+		}
+		s.params = append(s.params, methodParameter{section: section, name: strings.ToUpper(add.Name), tokens: tokens})
+	}
+	return nil
+}
+
+// PreviewChangeSignature previews a change of the signature of a global
+// class method: the definition, the parameter names in the implementation
+// and the calls in all objects that use the method.
+func (c *Client) PreviewChangeSignature(ctx context.Context, classURL, method string, change SignatureChange) (*RefactoringPreview, error) {
+	if err := c.checkSafety(OpRead, "PreviewChangeSignature"); err != nil {
+		return nil, err
+	}
+	if len(change.Add) == 0 && len(change.Remove) == 0 && len(change.Rename) == 0 {
+		return nil, fmt.Errorf("signature change is empty")
+	}
+	classURL = atcMainObjectURL(atcSourceURL(classURL))
+	mainURL := classURL + "/source/main"
+	source, err := c.readATCSource(ctx, mainURL)
+	if err != nil {
+		return nil, err
+	}
+
+	newSource, oldSig, warnings, err := changeMethodDefinition(normalizeLineEndings(source), method, change)
+	if err != nil {
+		return nil, err
+	}
+	newSource, w := rewriteMethodCalls(newSource, method, oldSig, change, true)
+	warnings = append(warnings, w...)
+
+	preview := &RefactoringPreview{
+		Kind:  RefactoringChangeSignature,
+		Title: fmt.Sprintf("Change signature of %s=>%s", impactObjectName(classURL), strings.ToUpper(method)),
+	}
+	preview.Changes = append(preview.Changes, newRefactoringChange(mainURL, source, newSource))
+
+	// Calls in other objects (and other includes of the class)
+	pos, err := c.methodPosition(ctx, classURL, method)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := c.FindReferences(ctx, pos, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("finding callers: %w", err)
+	}
+	seen := map[string]bool{strings.ToLower(mainURL): true}
+	for _, ref := range refs {
+		if ref.URI == "" || strings.HasPrefix(ref.Type, "DEVC") || !ref.IsResult {
+			continue
+		}
+		sourceURL := refactoringSourceURL(ref.URI)
+		if seen[strings.ToLower(sourceURL)] {
+			continue
+		}
+		seen[strings.ToLower(sourceURL)] = true
+
+		callerSource, err := c.readATCSource(ctx, sourceURL)
+		if err != nil {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("%s: %v", sourceURL, err))
+			continue
+		}
+		changed, w := rewriteMethodCalls(normalizeLineEndings(callerSource), method, oldSig, change, false)
+		for _, msg := range w {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", impactObjectName(atcMainObjectURL(sourceURL)), msg))
+		}
+		if changed != normalizeLineEndings(callerSource) {
+			ch := newRefactoringChange(sourceURL, callerSource, changed)
+			if ref.Type != "" {
+				ch.Type = ref.Type
+			}
+			preview.Changes = append(preview.Changes, ch)
+		}
+	}
+	preview.Warnings = append(preview.Warnings, warnings...)
+	return preview, nil
+}
+
+// changeMethodDefinition rewrites the METHODS statement of a method and
+// renames parameters in its implementation. It returns the new source and
+// the old signature.
+func changeMethodDefinition(source, method string, change SignatureChange) (string, *methodSignature, []string, error) {
+	classes := abapCharClasses(source)
+	defRegex := regexp.MustCompile(`(?im)^[ \t]*(CLASS-)?METHODS[ \t]+` + regexp.QuoteMeta(method) + `\b`)
+	var start, headEnd = -1, -1
+	for _, loc := range defRegex.FindAllStringIndex(source, -1) {
+		if classes[strings.IndexFunc(source[loc[0]:], func(r rune) bool { return r != ' ' && r != '\t' })+loc[0]] == abapCode {
+			start, headEnd = loc[0], loc[1]
+			break
+		}
+	}
+	if start < 0 {
+		return "", nil, nil, fmt.Errorf("definition of method %s not found (chained METHODS statements are not supported)", strings.ToUpper(method))
+	}
+	end := abapStatementEnd(source, classes, headEnd)
+	if end < 0 {
+		return "", nil, nil, fmt.Errorf("definition of method %s is not terminated", strings.ToUpper(method))
+	}
+
+	oldSig, err := parseMethodSignature(source[start:headEnd], abapTokens(source[headEnd:end], classes[headEnd:end]))
+	if err != nil {
+		return "", nil, nil, err
+	}
+	newSig, err := parseMethodSignature(source[start:headEnd], abapTokens(source[headEnd:end], classes[headEnd:end]))
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if err := newSig.apply(change); err != nil {
+		return "", nil, nil, err
+	}
+	source = source[:start] + newSig.render() + source[end+1:]
+
+	// Parameter names in the implementation
+	classes = abapCharClasses(source)
+	implRegex := regexp.MustCompile(`(?im)^[ \t]*METHOD[ \t]+` + regexp.QuoteMeta(method) + `[ \t]*\.`)
+	var warnings []string
+	for _, loc := range implRegex.FindAllStringIndex(source, -1) {
+		if classes[loc[1]-1] != abapCode {
+			continue
+		}
+		endRegex := regexp.MustCompile(`(?im)^[ \t]*ENDMETHOD[ \t]*\.`)
+		endLoc := endRegex.FindStringIndex(source[loc[1]:])
+		if endLoc == nil {
+			break
+		}
+		bodyStart, bodyEnd := loc[1], loc[1]+endLoc[0]
+		body := source[bodyStart:bodyEnd]
+		bodyClasses := classes[bodyStart:bodyEnd]
+		for oldName, newName := range change.Rename {
+			body, bodyClasses = replaceABAPIdentifier(body, bodyClasses, oldName, newName)
+		}
+		for _, name := range change.Remove {
+			if _, n := findABAPIdentifier(body, bodyClasses, name); n > 0 {
+				warnings = append(warnings, fmt.Sprintf("removed parameter %s is still used in the implementation", strings.ToUpper(name)))
+			}
+		}
+		source = source[:bodyStart] + body + source[bodyEnd:]
+		break
+	}
+	return source, oldSig, warnings, nil
+}
+
+// rewriteMethodCalls updates the calls of a method to a changed signature.
+// Calls without an object reference are only rewritten in the class itself.
+func rewriteMethodCalls(source, method string, oldSig *methodSignature, change SignatureChange, own bool) (string, []string) {
+	var warnings []string
+	callRegex := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(method) + `\(`)
+	for pos := 0; pos < len(source); {
+		classes := abapCharClasses(source)
+		loc := callRegex.FindStringIndex(source[pos:])
+		if loc == nil {
+			break
+		}
+		start, open := pos+loc[0], pos+loc[1]-1
+		pos = open + 1
+		if classes[start] != abapCode || !isMethodCallPrefix(source, start, own) {
+			continue
+		}
+		closing := abapClosingParen(source, classes, open)
+		if closing < 0 {
+			continue
+		}
+		args, ok := parseCallArguments(abapTokens(source[open+1:closing], classes[open+1:closing]))
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("line %d: call not understood, not updated", strings.Count(source[:start], "\n")+1))
+			continue
+		}
+		changed, err := args.apply(oldSig, change)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("line %d: %v", strings.Count(source[:start], "\n")+1, err))
+			continue
+		}
+		if changed {
+			source = source[:open] + args.render() + source[closing+1:]
+		}
+	}
+
+	classes := abapCharClasses(source)
+	callMethodRegex := regexp.MustCompile(`(?i)\bCALL\s+METHOD\s+\S*?\b` + regexp.QuoteMeta(method) + `\b`)
+	for _, loc := range callMethodRegex.FindAllStringIndex(source, -1) {
+		if classes[loc[0]] == abapCode {
+			warnings = append(warnings, fmt.Sprintf("line %d: CALL METHOD statement is not updated", strings.Count(source[:loc[0]], "\n")+1))
+		}
+	}
+	return source, warnings
+}
+
+// isMethodCallPrefix reports whether the method name at start is called:
+// after -> or =>, or without prefix in the class itself.
+func isMethodCallPrefix(source string, start int, own bool) bool {
+	if start == 0 {
+		return own
+	}
+	prev := source[start-1]
+	if prev == '>' {
+		return start >= 2 && (source[start-2] == '-' || source[start-2] == '=')
+	}
+	return own && !isABAPIdentifierChar(prev) && prev != '-' && prev != '~'
+}
+
+// callArguments are the actual parameters of a functional method call.
+type callArguments struct {
+	positional string
+	keywords   bool                           // Sections were named explicitly
+	sections   map[string][]callArgumentValue // Call section -> parameters
+}
+
+type callArgumentValue struct {
+	name  string
+	value string
+}
+
+var callSections = []string{"EXPORTING", "IMPORTING", "CHANGING", "RECEIVING", "EXCEPTIONS"}
+
+func isCallSection(token string) bool {
+	for _, s := range callSections {
+		if strings.EqualFold(token, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCallArguments parses the tokens between the parentheses of a call.
+func parseCallArguments(tokens []string) (*callArguments, bool) {
+	args := &callArguments{sections: make(map[string][]callArgumentValue)}
+	named := false
+	for _, t := range tokens {
+		if t == "=" || isCallSection(t) {
+			named = true
+		}
+	}
+	if !named {
+		args.positional = strings.Join(tokens, " ")
+		return args, true
+	}
+
+	section := "EXPORTING"
+	for i := 0; i < len(tokens); {
+		if isCallSection(tokens[i]) {
+			section = strings.ToUpper(tokens[i])
+			args.keywords = true
+			i++
+			continue
+		}
+		if i+1 >= len(tokens) || tokens[i+1] != "=" {
+			return nil, false
+		}
+		j := i + 2
+		for j < len(tokens) && !isCallSection(tokens[j]) && !(j+1 < len(tokens) && tokens[j+1] == "=") {
+			j++
+		}
+		if j == i+2 {
+			return nil, false
+		}
+		args.sections[section] = append(args.sections[section], callArgumentValue{name: tokens[i], value: strings.Join(tokens[i+2:j], " ")})
+		i = j
+	}
+	return args, true
+}
+
+// apply changes the actual parameters for a signature change and reports
+// whether anything changed.
+func (a *callArguments) apply(sig *methodSignature, change SignatureChange) (bool, error) {
+	callSection := func(definition string) string {
+		for _, s := range signatureSections {
+			if s.definition == definition {
+				return s.call
+			}
+		}
+		return ""
+	}
+
+	var added []callArgumentValue
+	var addedSections []string
+	for _, p := range change.Add {
+		kind := strings.ToUpper(p.Kind)
+		if kind == "" {
+			kind = "IMPORTING"
+		}
+		if p.CallerValue != "" && (kind == "IMPORTING" || kind == "CHANGING") {
+			added = append(added, callArgumentValue{name: strings.ToLower(p.Name), value: p.CallerValue})
+			addedSections = append(addedSections, callSection(kind))
+		}
+	}
+
+	changed := false
+	if a.positional != "" {
+		preferred := sig.positionalParameter()
+		affected := len(added) > 0
+		for _, name := range change.Remove {
+			affected = affected || strings.EqualFold(name, preferred)
+		}
+		for oldName := range change.Rename {
+			affected = affected || strings.EqualFold(oldName, preferred)
+		}
+		if !affected {
+			return false, nil
+		}
+		if preferred == "" {
+			return false, fmt.Errorf("call with an unnamed parameter not updated: the receiving parameter is ambiguous")
+		}
+		a.sections["EXPORTING"] = []callArgumentValue{{name: strings.ToLower(preferred), value: a.positional}}
+		a.positional = ""
+		changed = true
+	}
+
+	for _, name := range change.Remove {
+		for section, values := range a.sections {
+			for i, v := range values {
+				if strings.EqualFold(v.name, name) {
+					a.sections[section] = append(values[:i], values[i+1:]...)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	for oldName, newName := range change.Rename {
+		for _, values := range a.sections {
+			for i := range values {
+				if strings.EqualFold(values[i].name, oldName) {
+					values[i].name = newName
+					changed = true
+				}
+			}
+		}
+	}
+	for i, v := range added {
+		section := addedSections[i]
+		if section != "EXPORTING" {
+			a.keywords = true
+		}
+		a.sections[section] = append(a.sections[section], v)
+		changed = true
+	}
+	return changed, nil
+}
+
+// render formats the actual parameters including the parentheses.
+func (a *callArguments) render() string {
+	if a.positional != "" {
+		return "( " + a.positional + " )"
+	}
+	var parts []string
+	keywords := a.keywords
+	for _, section := range callSections {
+		if section != "EXPORTING" && len(a.sections[section]) > 0 {
+			keywords = true
+		}
+	}
+	for _, section := range callSections {
+		values := a.sections[section]
+		if len(values) == 0 {
+			continue
+		}
+		if keywords {
+			parts = append(parts, section)
+		}
+		for _, v := range values {
+			parts = append(parts, v.name+" = "+v.value)
+		}
+	}
+	if len(parts) == 0 {
+		return "( )"
+	}
+	return "( " + strings.Join(parts, " ") + " )"
+}
+
+// abapCharClasses classifies each byte of ABAP source as code, literal or
+// comment.
+func abapCharClasses(source string) []byte {
+	classes := make([]byte, len(source))
+	lineStart := true
+	for i := 0; i < len(source); i++ {
+		ch := source[i]
+		switch {
+		case (lineStart && ch == '*') || ch == '"':
+			for ; i < len(source) && source[i] != '\n'; i++ {
+				classes[i] = abapComment
+			}
+			lineStart = true
+			continue
+		case ch == '\'' || ch == '`' || ch == '|':
+			classes[i] = abapLiteral
+			for i++; i < len(source); i++ {
+				classes[i] = abapLiteral
+				if ch == '|' && source[i] == '\\' && i+1 < len(source) {
+					i++
+					classes[i] = abapLiteral
+					continue
+				}
+				if source[i] == ch {
+					if ch != '|' && i+1 < len(source) && source[i+1] == ch {
+						i++
+						classes[i] = abapLiteral
+						continue
+					}
+					break
+				}
+				if source[i] == '\n' && ch != '|' {
+					break
+				}
+			}
+		}
+		lineStart = ch == '\n'
+	}
+	return classes
+}
+
+// abapStatementEnd returns the position of the period that ends the
+// statement starting at from, or -1.
+func abapStatementEnd(source string, classes []byte, from int) int {
+	for i := from; i < len(source); i++ {
+		if source[i] == '.' && classes[i] == abapCode && (i+1 == len(source) || strings.ContainsRune(" \t\r\n\"", rune(source[i+1]))) {
+			return i
+		}
+	}
+	return -1
+}
+
+// abapClosingParen returns the position of the parenthesis that closes the
+// one at open, or -1.
+func abapClosingParen(source string, classes []byte, open int) int {
+	depth := 0
+	for i := open; i < len(source); i++ {
+		if classes[i] != abapCode {
+			continue
+		}
+		switch source[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// abapTokens splits code into whitespace-separated tokens, keeping
+// literals and bracketed expressions together and dropping comments.
+func abapTokens(code string, classes []byte) []string {
+	var tokens []string
+	var current strings.Builder
+	depth := 0
+	for i := 0; i < len(code); i++ {
+		ch := code[i]
+		switch classes[i] {
+		case abapComment:
+			continue
+		case abapLiteral:
+			current.WriteByte(ch)
+			continue
+		}
+		if depth == 0 && (ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n') {
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		switch ch {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		}
+		if depth > 0 && (ch == '\r' || ch == '\n' || ch == '\t') {
+			ch = ' '
+		}
+		current.WriteByte(ch)
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// findABAPIdentifier returns the first position of an identifier in code
+// and the number of occurrences. Components (after -, ~ or ->) do not count.
+func findABAPIdentifier(code string, classes []byte, name string) (int, int) {
+	first, count := -1, 0
+	upper := strings.ToUpper(code)
+	name = strings.ToUpper(name)
+	for i := 0; i+len(name) <= len(code); {
+		j := strings.Index(upper[i:], name)
+		if j < 0 {
+			break
+		}
+		at := i + j
+		i = at + 1
+		if classes[at] != abapCode {
+			continue
+		}
+		if at > 0 && (isABAPIdentifierChar(code[at-1]) || code[at-1] == '-' || code[at-1] == '~' || code[at-1] == '>') {
+			continue
+		}
+		if end := at + len(name); end < len(code) && isABAPIdentifierChar(code[end]) {
+			continue
+		}
+		if first < 0 {
+			first = at
+		}
+		count++
+	}
+	return first, count
+}
+
+// replaceABAPIdentifier renames an identifier in code.
+func replaceABAPIdentifier(code string, classes []byte, oldName, newName string) (string, []byte) {
+	for from := 0; from < len(code); {
+		at, n := findABAPIdentifier(code[from:], classes[from:], oldName)
+		if n == 0 {
+			break
+		}
+		at += from
+		code = code[:at] + newName + code[at+len(oldName):]
+		classes = append(append(append([]byte{}, classes[:at]...), make([]byte, len(newName))...), classes[at+len(oldName):]...)
+		from = at + len(newName)
+	}
+	return code, classes
+}
@@ -0,0 +1,198 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testRefactoringSource = "CLASS zcl_a IMPLEMENTATION.\n  METHOD run.\n    DATA lv_count TYPE i.\n    lv_count = 1.\n  ENDMETHOD.\nENDCLASS."
+
+func TestRenameWithRollback(t *testing.T) {
+	current := testRefactoringSource
+	var evaluateURI, executeBody string
+	var putSources []string
+	failExecute := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/sap/bc/adt/oo/classes/zcl_a/source/main" && r.Method == http.MethodGet:
+			fmt.Fprint(w, current)
+		case r.URL.Path == "/sap/bc/adt/oo/classes/zcl_a/source/main" && r.Method == http.MethodPut:
+			current = string(body)
+			putSources = append(putSources, current)
+		case r.URL.Path == "/sap/bc/adt/oo/classes/zcl_a" && r.URL.Query().Get("_action") == "LOCK":
+			fmt.Fprint(w, `<asx:abap xmlns:asx="http://www.sap.com/abapxml"><asx:values><DATA><LOCK_HANDLE>LH1</LOCK_HANDLE></DATA></asx:values></asx:abap>`)
+		case r.URL.Path == "/sap/bc/adt/oo/classes/zcl_a":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/sap/bc/adt/refactorings":
+			switch r.URL.Query().Get("step") {
+			case "evaluate":
+				evaluateURI = r.URL.Query().Get("uri")
+				fmt.Fprint(w, `<rename:renameRefactoring xmlns:rename="http://www.sap.com/adt/refactoring/renamerefactoring" xmlns:generic="http://www.sap.com/adt/refactoring/genericrefactoring"><rename:oldName>LV_COUNT</rename:oldName><rename:newName/><generic:genericRefactoring><generic:title>Rename</generic:title><generic:transport/></generic:genericRefactoring></rename:renameRefactoring>`)
+			case "preview":
+				if !strings.Contains(string(body), "<rename:newName>lv_total</rename:newName>") {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				fmt.Fprint(w, `<rename:renameRefactoring xmlns:rename="http://www.sap.com/adt/refactoring/renamerefactoring" xmlns:generic="http://www.sap.com/adt/refactoring/genericrefactoring" xmlns:adtcore="http://www.sap.com/adt/core">
+  <rename:newName>lv_total</rename:newName>
+  <generic:genericRefactoring>
+    <generic:title>Rename LV_COUNT</generic:title>
+    <generic:affectedObjects>
+      <generic:affectedObject adtcore:uri="/sap/bc/adt/oo/classes/zcl_a/source/main" adtcore:name="ZCL_A" adtcore:type="CLAS/OC">
+        <generic:textReplaceDeltas>
+          <generic:textReplaceDelta><generic:rangeFragment>#start=3,9;end=3,17</generic:rangeFragment><generic:contentOld>lv_count</generic:contentOld><generic:contentNew>lv_total</generic:contentNew></generic:textReplaceDelta>
+          <generic:textReplaceDelta><generic:rangeFragment>#start=4,4;end=4,12</generic:rangeFragment><generic:contentOld>lv_count</generic:contentOld><generic:contentNew>lv_total</generic:contentNew></generic:textReplaceDelta>
+        </generic:textReplaceDeltas>
+      </generic:affectedObject>
+    </generic:affectedObjects>
+    <generic:transport/>
+  </generic:genericRefactoring>
+</rename:renameRefactoring>`)
+			case "execute":
+				executeBody = string(body)
+				current = strings.ReplaceAll(current, "lv_count", "lv_total")
+				if failExecute {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}
+		case r.URL.Path == "/sap/bc/adt/activation":
+			if strings.Contains(current, "lv_total") {
+				fmt.Fprint(w, `<chkl:messages xmlns:chkl="http://www.sap.com/abapxml/checklist"><msg type="E" href="/sap/bc/adt/oo/classes/zcl_a/source/main#start=4,1"><shortText><txt>LV_TOTAL is already declared</txt></shortText></msg></chkl:messages>`)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"), WithAllowTransportableEdits())
+	ctx := context.Background()
+
+	preview, err := client.PreviewRename(ctx, "/sap/bc/adt/oo/classes/zcl_a", 4, 7, "lv_total")
+	if err != nil {
+		t.Fatalf("PreviewRename failed: %v", err)
+	}
+	if evaluateURI != "/sap/bc/adt/oo/classes/zcl_a/source/main#start=4,4;end=4,12" {
+		t.Errorf("evaluate uri = %q", evaluateURI)
+	}
+	if len(preview.Changes) != 1 || preview.Changes[0].Name != "ZCL_A" {
+		t.Fatalf("unexpected changes: %+v", preview.Changes)
+	}
+	if diff := preview.Diff(); !strings.Contains(diff, "+    DATA lv_total TYPE i.") || !strings.Contains(diff, "+    lv_total = 1.") {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+
+	result, err := client.ExecuteRefactoring(ctx, preview, RefactoringOptions{Transport: "DEVK900001"})
+	if err != nil {
+		t.Fatalf("ExecuteRefactoring failed: %v", err)
+	}
+	if !strings.Contains(executeBody, "<generic:transport>DEVK900001</generic:transport>") {
+		t.Errorf("transport not sent: %s", executeBody)
+	}
+	if result.Success || !result.RolledBack {
+		t.Fatalf("expected rolled back failure, got %+v", result)
+	}
+	if len(putSources) != 1 || putSources[0] != testRefactoringSource || current != testRefactoringSource {
+		t.Errorf("previous source not restored: %q", putSources)
+	}
+
+	// A failed execute may have saved the object anyway
+	failExecute = true
+	putSources = nil
+	result, err = client.ExecuteRefactoring(ctx, preview, RefactoringOptions{Transport: "DEVK900001"})
+	if err != nil {
+		t.Fatalf("ExecuteRefactoring failed: %v", err)
+	}
+	if result.Success || !result.RolledBack {
+		t.Fatalf("expected rolled back failure, got %+v", result)
+	}
+	if len(putSources) != 1 || current != testRefactoringSource {
+		t.Errorf("previous source not restored after failed execute: %q", putSources)
+	}
+
+	readOnly := NewClient(server.URL, "testuser", "testpass", WithReadOnly())
+	if _, err := readOnly.ExecuteRefactoring(ctx, preview, RefactoringOptions{}); err == nil {
+		t.Error("expected read-only client to reject ExecuteRefactoring")
+	}
+}
+
+func TestChangeMethodSignature(t *testing.T) {
+	source := `CLASS zcl_calc DEFINITION PUBLIC.
+  PUBLIC SECTION.
+    METHODS add
+      IMPORTING
+        iv_a TYPE i
+        iv_b TYPE i OPTIONAL " second operand
+      EXPORTING
+        ev_log TYPE string
+      RETURNING
+        VALUE(rv_sum) TYPE i.
+ENDCLASS.
+
+CLASS zcl_calc IMPLEMENTATION.
+  METHOD add.
+    rv_sum = iv_a + iv_b.
+    ls_x-iv_a = 'iv_a'.
+  ENDMETHOD.
+  METHOD twice.
+    rv = add( iv_a = iv + 0 iv_b = iv ).
+  ENDMETHOD.
+ENDCLASS.`
+
+	change := SignatureChange{
+		Add:    []SignatureParameter{{Name: "iv_round", Type: "abap_bool", CallerValue: "abap_false"}},
+		Remove: []string{"ev_log"},
+		Rename: map[string]string{"iv_a": "iv_first"},
+	}
+	changed, oldSig, warnings, err := changeMethodDefinition(source, "add", change)
+	if err != nil {
+		t.Fatalf("changeMethodDefinition failed: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	if oldSig.positionalParameter() != "IV_A" {
+		t.Errorf("positional parameter = %q", oldSig.positionalParameter())
+	}
+	wantDef := `    METHODS add
+      IMPORTING
+        iv_first TYPE i
+        iv_b TYPE i OPTIONAL
+        iv_round TYPE abap_bool
+      RETURNING
+        VALUE(rv_sum) TYPE i.`
+	if !strings.Contains(changed, wantDef) {
+		t.Errorf("unexpected definition:\n%s", changed)
+	}
+	if !strings.Contains(changed, "rv_sum = iv_first + iv_b.") || !strings.Contains(changed, "ls_x-iv_a = 'iv_a'.") {
+		t.Errorf("unexpected implementation:\n%s", changed)
+	}
+
+	changed, warnings = rewriteMethodCalls(changed, "add", oldSig, change, true)
+	if !strings.Contains(changed, "rv = add( iv_first = iv + 0 iv_b = iv iv_round = abap_false ).") {
+		t.Errorf("own call not rewritten:\n%s", changed)
+	}
+
+	caller := "lv = lo_calc->add( 1 ).\nlo_calc->add( EXPORTING iv_a = 1 IMPORTING ev_log = lv_log ).\nlv = zcl_other=>add( 2 ).\nlv = add( 3 ).\nCALL METHOD lo_calc->add EXPORTING iv_a = 1.\n\" lo_calc->add( 4 )"
+	got, warnings := rewriteMethodCalls(caller, "add", oldSig, change, false)
+	want := "lv = lo_calc->add( iv_first = 1 iv_round = abap_false ).\nlo_calc->add( EXPORTING iv_first = 1 iv_round = abap_false ).\nlv = zcl_other=>add( iv_first = 2 iv_round = abap_false ).\nlv = add( 3 ).\nCALL METHOD lo_calc->add EXPORTING iv_a = 1.\n\" lo_calc->add( 4 )"
+	if got != want {
+		t.Errorf("rewriteMethodCalls =\n%s\nwant\n%s", got, want)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "line 5: CALL METHOD") {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+
+	if _, _, _, err := changeMethodDefinition(source, "add", SignatureChange{Add: []SignatureParameter{{Name: "iv_c", Type: "i"}}}); err == nil {
+		t.Error("expected error for a required parameter without caller value")
+	}
+}
@@ -55,6 +55,7 @@ type TransportInfo struct {
 	Transports     []TransportRequest `json:"transports,omitempty"`
 	LockedByUser   string             `json:"lockedByUser,omitempty"`
 	LockedInTask   string             `json:"lockedInTask,omitempty"`
+	LockedInRequest string            `json:"lockedInRequest,omitempty"`
 }
 
 const (
@@ -237,6 +238,18 @@ func parseTransportInfo(data []byte) (*TransportInfo, error) {
 		Operation  string `xml:"OPERATION"`
 		DevClass   string `xml:"DEVCLASS"`
 		Recording  string `xml:"RECORDING"`
+		Locks      struct {
+			Holder struct {
+				Request struct {
+					Number string `xml:"TRKORR"`
+					User   string `xml:"AS4USER"`
+				} `xml:"REQ_HEADER"`
+				Tasks []struct {
+					Number string `xml:"TRKORR"`
+					User   string `xml:"AS4USER"`
+				} `xml:"TASK_HEADERS>CTS_TASK_HEADER"`
+			} `xml:"CTS_OBJECT_LOCK>LOCK_HOLDER"`
+		} `xml:"LOCKS"`
 	}
 	type values struct {
 		Data dataType `xml:"DATA"`
@@ -253,14 +266,24 @@ func parseTransportInfo(data []byte) (*TransportInfo, error) {
 		return nil, fmt.Errorf("parsing transport info: %w", err)
 	}
 
-	return &TransportInfo{
+	info := &TransportInfo{
 		PGMID:      resp.Values.Data.PGMID,
 		Object:     resp.Values.Data.Object,
 		ObjectName: resp.Values.Data.ObjectName,
 		Operation:  resp.Values.Data.Operation,
 		DevClass:   resp.Values.Data.DevClass,
 		Recording:  resp.Values.Data.Recording,
-	}, nil
+	}
+
+	// Lock held by a request (the object is already on that request)
+	holder := resp.Values.Data.Locks.Holder
+	info.LockedInRequest = holder.Request.Number
+	info.LockedByUser = holder.Request.User
+	if len(holder.Tasks) > 0 {
+		info.LockedInTask = holder.Tasks[0].Number
+		info.LockedByUser = holder.Tasks[0].User
+	}
+	return info, nil
 }
 
 // CreateTransport creates a new transport request.
@@ -426,8 +449,10 @@ type CreateTransportOptions struct {
 
 // ReleaseTransportOptions for releasing transports
 type ReleaseTransportOptions struct {
-	IgnoreLocks bool
-	SkipATC     bool
+	IgnoreLocks  bool
+	SkipATC      bool
+	Check        bool                   // Run PreReleaseCheck first and refuse to release on blocking issues
+	CheckOptions PreReleaseCheckOptions // Options of the pre-release check
 }
 
 // ListTransports returns transport requests for a user.
@@ -794,6 +819,16 @@ func (c *Client) ReleaseTransportV2(ctx context.Context, number string, opts Rel
 		return fmt.Errorf("transport number is required")
 	}
 
+	if opts.Check {
+		check, err := c.PreReleaseCheck(ctx, number, opts.CheckOptions)
+		if err != nil {
+			return fmt.Errorf("pre-release check of %s: %w", number, err)
+		}
+		if !check.Passed {
+			return &PreReleaseCheckError{Result: check}
+		}
+	}
+
 	// Determine release action
 	action := "newreleasejobs"
 	if opts.IgnoreLocks {
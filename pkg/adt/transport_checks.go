@@ -0,0 +1,495 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// --- Pre-Release Checks ---
+//
+// PreReleaseCheck inspects every object of a transport request (and its
+// tasks) before release: inactive versions, syntax errors, ATC findings,
+// failing unit tests, locks held by other requests, and objects referenced
+// by the transported code that are neither on the transport nor released
+// before.
+
+// Pre-release check names, used in PreReleaseIssue.Check.
+const (
+	PreReleaseInactive  = "inactive"
+	PreReleaseSyntax    = "syntax"
+	PreReleaseATC       = "atc"
+	PreReleaseUnitTest  = "unittest"
+	PreReleaseLock      = "lock"
+	PreReleaseReference = "reference"
+)
+
+// preReleaseQueryBatch is the number of names per IN list in the reference
+// queries.
+const preReleaseQueryBatch = 50
+
+// PreReleaseCheckOptions configures PreReleaseCheck.
+type PreReleaseCheckOptions struct {
+	ATCVariant     string // ATC check variant (empty = system default)
+	ATCMaxPriority int    // Findings up to this priority block the release: 1=errors (default), 2=+warnings, 3=all
+	SkipSyntax     bool
+	SkipATC        bool
+	SkipUnitTests  bool
+	SkipLocks      bool
+	SkipReferences bool
+}
+
+// PreReleaseIssue is one finding of a pre-release check.
+type PreReleaseIssue struct {
+	Check    string `json:"check"`  // inactive, syntax, atc, unittest, lock, reference
+	Object   string `json:"object"` // Type and name, e.g. "CLAS ZCL_ORDER"
+	URI      string `json:"uri,omitempty"`
+	Message  string `json:"message"`
+	Blocking bool   `json:"blocking"`
+}
+
+// PreReleaseCheckResult is the result of PreReleaseCheck.
+type PreReleaseCheckResult struct {
+	Transport string            `json:"transport"`
+	Target    string            `json:"target,omitempty"`
+	Objects   int               `json:"objects"`           // Checked objects
+	Skipped   []string          `json:"skipped,omitempty"` // Entries without an ADT object
+	Issues    []PreReleaseIssue `json:"issues"`
+	Notes     []string          `json:"notes,omitempty"`  // Why checks could not run
+	NotRun    []string          `json:"notRun,omitempty"` // Checks that were not skipped but could not run
+	Passed    bool              `json:"passed"`           // No blocking issues and every requested check ran
+}
+
+// notRun records a requested check that could not run; it fails the result.
+func (r *PreReleaseCheckResult) notRun(check, note string) {
+	r.Notes = append(r.Notes, note)
+	for _, c := range r.NotRun {
+		if c == check {
+			return
+		}
+	}
+	r.NotRun = append(r.NotRun, check)
+}
+
+// passed reports whether nothing blocks the release.
+func (r *PreReleaseCheckResult) passed() bool {
+	return len(r.Blocking()) == 0 && len(r.NotRun) == 0
+}
+
+// Blocking returns the issues that block the release.
+func (r *PreReleaseCheckResult) Blocking() []PreReleaseIssue {
+	var blocking []PreReleaseIssue
+	for _, issue := range r.Issues {
+		if issue.Blocking {
+			blocking = append(blocking, issue)
+		}
+	}
+	return blocking
+}
+
+// Summary returns a one-line description of the result.
+func (r *PreReleaseCheckResult) Summary() string {
+	status := "PASSED"
+	if !r.Passed {
+		status = "FAILED"
+	}
+	counts := make(map[string]int)
+	var checks []string
+	for _, issue := range r.Blocking() {
+		if counts[issue.Check] == 0 {
+			checks = append(checks, issue.Check)
+		}
+		counts[issue.Check]++
+	}
+	var parts []string
+	for _, check := range checks {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[check], check))
+	}
+	s := fmt.Sprintf("%s: pre-release check %s, %d objects, %d blocking issues", r.Transport, status, r.Objects, len(r.Blocking()))
+	if len(parts) > 0 {
+		s += " (" + strings.Join(parts, ", ") + ")"
+	}
+	if warnings := len(r.Issues) - len(r.Blocking()); warnings > 0 {
+		s += fmt.Sprintf(", %d warnings", warnings)
+	}
+	if len(r.NotRun) > 0 {
+		s += ", not run: " + strings.Join(r.NotRun, ", ")
+	}
+	return s
+}
+
+// PreReleaseCheckError is returned by ReleaseTransportV2 with Check set
+// when the pre-release check finds blocking issues.
+type PreReleaseCheckError struct {
+	Result *PreReleaseCheckResult
+}
+
+func (e *PreReleaseCheckError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("release blocked: " + e.Result.Summary())
+	for _, issue := range e.Result.Blocking() {
+		fmt.Fprintf(&sb, "\n  [%s] %s: %s", issue.Check, issue.Object, issue.Message)
+	}
+	for _, note := range e.Result.Notes {
+		fmt.Fprintf(&sb, "\n  %s", note)
+	}
+	return sb.String()
+}
+
+// preReleaseObject is a checkable object of the transport.
+type preReleaseObject struct {
+	url   string
+	label string
+}
+
+// PreReleaseCheck checks all objects of a transport request and its tasks
+// before release. Inactive versions are those of the current user. A check
+// that is not skipped but cannot run fails the result, so the release gate
+// never passes on checks that did not happen.
+func (c *Client) PreReleaseCheck(ctx context.Context, number string, opts PreReleaseCheckOptions) (*PreReleaseCheckResult, error) {
+	if err := c.checkSafety(OpRead, "PreReleaseCheck"); err != nil {
+		return nil, err
+	}
+	if opts.ATCMaxPriority <= 0 {
+		opts.ATCMaxPriority = 1
+	}
+	details, err := c.GetTransport(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	own := map[string]bool{strings.ToUpper(details.Number): true}
+	entries := append([]TransportObjectV2{}, details.Objects...)
+	for _, task := range details.Tasks {
+		own[strings.ToUpper(task.Number)] = true
+		entries = append(entries, task.Objects...)
+	}
+
+	result := &PreReleaseCheckResult{Transport: details.Number, Target: details.Target, Issues: []PreReleaseIssue{}}
+	var objects []preReleaseObject
+	byURL := make(map[string]preReleaseObject)
+	transported := make(map[string]bool)
+	for _, entry := range entries {
+		transported[strings.ToUpper(strings.TrimSpace(entry.Name))] = true
+		u := TransportObjectURL(entry)
+		if u == "" {
+			result.Skipped = append(result.Skipped, strings.TrimSpace(entry.PgmID+" "+entry.Type+" "+entry.Name))
+			continue
+		}
+		key := strings.ToLower(u)
+		if _, ok := byURL[key]; ok {
+			continue
+		}
+		name := impactObjectName(u)
+		obj := preReleaseObject{url: u, label: preReleaseLabel(entry, u, name)}
+		transported[name] = true
+		byURL[key] = obj
+		objects = append(objects, obj)
+	}
+	result.Skipped = dedupeStrings(result.Skipped)
+	result.Objects = len(objects)
+
+	issue := func(check string, obj preReleaseObject, message string, blocking bool) {
+		result.Issues = append(result.Issues, PreReleaseIssue{Check: check, Object: obj.label, URI: obj.url, Message: message, Blocking: blocking})
+	}
+	lookup := func(uri string) preReleaseObject {
+		main := atcMainObjectURL(atcSourceURL(uri))
+		if obj, ok := byURL[strings.ToLower(main)]; ok {
+			return obj
+		}
+		return preReleaseObject{url: main, label: impactObjectName(main)}
+	}
+	var urls []string
+	for _, obj := range objects {
+		urls = append(urls, obj.url)
+	}
+
+	// Inactive versions
+	inactive, err := c.GetInactiveObjects(ctx)
+	if err != nil {
+		result.notRun(PreReleaseInactive, fmt.Sprintf("inactive check failed: %v", err))
+	}
+	for _, rec := range inactive {
+		if rec.Object == nil {
+			continue
+		}
+		obj, ok := byURL[strings.ToLower(atcMainObjectURL(atcSourceURL(rec.Object.URI)))]
+		if !ok && (rec.Transport == nil || !own[strings.ToUpper(rec.Transport.Name)]) {
+			continue
+		}
+		if !ok {
+			obj = lookup(rec.Object.URI)
+		}
+		issue(PreReleaseInactive, obj, fmt.Sprintf("inactive version of %s %s", rec.Object.Type, rec.Object.Name), true)
+	}
+
+	if len(objects) == 0 {
+		result.Passed = result.passed()
+		return result, nil
+	}
+
+	// Syntax errors of the active versions
+	if !opts.SkipSyntax {
+		messages, err := c.syntaxCheckObjects(ctx, urls)
+		if err != nil {
+			result.notRun(PreReleaseSyntax, fmt.Sprintf("syntax check failed: %v", err))
+		}
+		for _, m := range messages {
+			if m.Severity == "E" {
+				issue(PreReleaseSyntax, lookup(m.URI), fmt.Sprintf("line %d: %s", m.Line, m.Text), true)
+			}
+		}
+	}
+
+	// ATC findings up to the threshold
+	if !opts.SkipATC {
+		atc, err := c.RunATCCheckObjects(ctx, urls, ATCSetOptions{Variant: opts.ATCVariant})
+		if err != nil {
+			result.notRun(PreReleaseATC, fmt.Sprintf("ATC check failed: %v", err))
+		} else {
+			for _, o := range atc.Worklist.Objects {
+				for _, f := range o.Findings {
+					if f.Priority > opts.ATCMaxPriority || f.ExemptionApproval != "" {
+						continue
+					}
+					msg := fmt.Sprintf("P%d %s: %s", f.Priority, f.CheckTitle, f.MessageTitle)
+					if f.Line > 0 {
+						msg = fmt.Sprintf("line %d: %s", f.Line, msg)
+					}
+					issue(PreReleaseATC, lookup(o.URI), msg, true)
+				}
+			}
+		}
+	}
+
+	// Failing unit tests
+	if !opts.SkipUnitTests {
+		var containers []string
+		for _, u := range urls {
+			if container := UnitTestContainerURL(u); container != "" {
+				containers = append(containers, container)
+			}
+		}
+		if containers = dedupeStrings(containers); len(containers) > 0 {
+			tests, err := c.RunUnitTestsFor(ctx, containers, nil)
+			if err != nil {
+				result.notRun(PreReleaseUnitTest, fmt.Sprintf("unit tests failed to run: %v", err))
+			} else {
+				for _, class := range tests.Classes {
+					for _, alert := range class.Alerts {
+						issue(PreReleaseUnitTest, lookup(class.URI), fmt.Sprintf("%s: %s", class.Name, alert.Title), true)
+					}
+					for _, m := range class.TestMethods {
+						for _, alert := range m.Alerts {
+							issue(PreReleaseUnitTest, lookup(class.URI), fmt.Sprintf("%s->%s: %s", class.Name, m.Name, alert.Title), true)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Locks held by other requests
+	if !opts.SkipLocks {
+		for _, obj := range objects {
+			info, err := c.GetTransportInfo(ctx, obj.url, "")
+			if err != nil {
+				result.notRun(PreReleaseLock, fmt.Sprintf("lock check for %s failed: %v", obj.label, err))
+				continue
+			}
+			if info.LockedInRequest == "" || own[strings.ToUpper(info.LockedInRequest)] {
+				continue
+			}
+			msg := "locked in request " + info.LockedInRequest
+			if info.LockedInTask != "" {
+				msg += ", task " + info.LockedInTask
+			}
+			if info.LockedByUser != "" {
+				msg += " by " + info.LockedByUser
+			}
+			issue(PreReleaseLock, obj, msg, true)
+		}
+	}
+
+	// Referenced objects that are not transported
+	if !opts.SkipReferences {
+		if err := c.preReleaseReferences(ctx, objects, transported, own, issue); err != nil {
+			result.notRun(PreReleaseReference, fmt.Sprintf("reference check failed: %v", err))
+		}
+	}
+
+	result.Passed = result.passed()
+	return result, nil
+}
+
+// preReleaseLabel returns "TYPE NAME" for a transport entry; sub-object
+// entries (LIMU) are labeled with the type of their main object.
+func preReleaseLabel(entry TransportObjectV2, u, mainName string) string {
+	typ := entry.Type
+	if entry.PgmID == "LIMU" {
+		if t := extractTypeFromURI(u); t != "" {
+			typ = t[:strings.Index(t, "/")]
+		}
+	}
+	return typ + " " + mainName
+}
+
+// syntaxCheckObjects runs the syntax check on the saved active versions of
+// the objects in one check run.
+func (c *Client) syntaxCheckObjects(ctx context.Context, urls []string) ([]SyntaxCheckResult, error) {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<chkrun:checkObjectList xmlns:chkrun="http://www.sap.com/adt/checkrun" xmlns:adtcore="http://www.sap.com/adt/core">`)
+	for _, u := range urls {
+		fmt.Fprintf(&sb, "\n  <chkrun:checkObject adtcore:uri=\"%s\" chkrun:version=\"active\"/>", escapeXMLAttr(u))
+	}
+	sb.WriteString("\n</chkrun:checkObjectList>")
+
+	resp, err := c.transport.Request(ctx, "/sap/bc/adt/checkruns?reporters=abapCheckRun", &RequestOptions{
+		Method:      http.MethodPost,
+		Body:        []byte(sb.String()),
+		ContentType: "application/*",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("syntax check failed: %w", err)
+	}
+	return parseSyntaxCheckResults(resp.Body)
+}
+
+// preReleaseNameRegex matches customer object names: Z*, Y* and
+// /NAMESPACE/ names.
+var preReleaseNameRegex = regexp.MustCompile(`^(?:[ZY][A-Z0-9_]*|/[A-Z0-9_]+/[A-Z0-9_]+)$`)
+
+// preReleaseSourceTypes are the URL prefixes of objects whose main source is
+// scanned for references.
+var preReleaseSourceTypes = []string{
+	"/sap/bc/adt/oo/classes/", "/sap/bc/adt/oo/interfaces/", "/sap/bc/adt/programs/programs/",
+	"/sap/bc/adt/programs/includes/", "/sap/bc/adt/ddic/ddl/sources/", "/sap/bc/adt/bo/behaviordefinitions/",
+}
+
+// preReleaseReferenceNames returns the customer object names referenced in
+// the code (not comments or literals) of a source.
+func preReleaseReferenceNames(source string) []string {
+	classes := abapCharClasses(source)
+	code := []byte(source)
+	for i := range code {
+		if classes[i] != abapCode {
+			code[i] = ' '
+		}
+	}
+	var names []string
+	for _, token := range regexp.MustCompile(`[A-Za-z0-9_/]+`).FindAllString(string(code), -1) {
+		name := strings.ToUpper(token)
+		if len(name) <= 40 && preReleaseNameRegex.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	return dedupeStrings(names)
+}
+
+// preReleaseReferences reports repository objects referenced by the
+// transported sources that are not on the transport: objects in local
+// packages and objects that are only on other open requests block the
+// release; objects never transported are reported as warnings. Objects
+// released on an earlier request are assumed to be in the target.
+func (c *Client) preReleaseReferences(ctx context.Context, objects []preReleaseObject, transported, own map[string]bool, issue func(string, preReleaseObject, string, bool)) error {
+	referencedBy := make(map[string][]preReleaseObject)
+	var candidates []string
+	for _, obj := range objects {
+		lower := strings.ToLower(obj.url)
+		scan := false
+		for _, prefix := range preReleaseSourceTypes {
+			scan = scan || strings.HasPrefix(lower, prefix)
+		}
+		if !scan {
+			continue
+		}
+		source, err := c.readATCSource(ctx, obj.url+"/source/main")
+		if err != nil {
+			continue
+		}
+		for _, name := range preReleaseReferenceNames(source) {
+			if transported[name] {
+				continue
+			}
+			if len(referencedBy[name]) == 0 {
+				candidates = append(candidates, name)
+			}
+			referencedBy[name] = append(referencedBy[name], obj)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.Strings(candidates)
+
+	type repoObject struct{ typ, pkg string }
+	type request struct{ number, status string }
+	repo := make(map[string]repoObject)
+	requests := make(map[string][]request)
+	for start := 0; start < len(candidates); start += preReleaseQueryBatch {
+		end := min(start+preReleaseQueryBatch, len(candidates))
+		in := "'" + strings.Join(candidates[start:end], "', '") + "'"
+
+		rows, err := c.RunQuery(ctx, `SELECT OBJ_NAME, OBJECT, DEVCLASS FROM TADIR WHERE PGMID = 'R3TR' AND OBJ_NAME IN (`+in+`)`, 1000)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows.Rows {
+			repo[getString(row, "OBJ_NAME")] = repoObject{typ: getString(row, "OBJECT"), pkg: getString(row, "DEVCLASS")}
+		}
+
+		rows, err = c.RunQuery(ctx, `SELECT e071~OBJ_NAME, e070~TRKORR, e070~STRKORR, e070~TRSTATUS
+		FROM E071 AS e071 INNER JOIN E070 AS e070 ON e070~TRKORR = e071~TRKORR
+		WHERE e071~OBJ_NAME IN (`+in+`)`, 5000)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows.Rows {
+			number := getString(row, "STRKORR")
+			if number == "" {
+				number = getString(row, "TRKORR")
+			}
+			name := getString(row, "OBJ_NAME")
+			requests[name] = append(requests[name], request{number: number, status: getString(row, "TRSTATUS")})
+		}
+	}
+
+	for _, name := range candidates {
+		obj, ok := repo[name]
+		if !ok {
+			continue // Not a repository object (variable, field, ...)
+		}
+		ref := fmt.Sprintf("%s %s", obj.typ, name)
+		var open []string
+		released := false
+		for _, r := range requests[name] {
+			switch {
+			case r.status == "R" || r.status == "N":
+				released = true
+			case !own[strings.ToUpper(r.number)]:
+				open = append(open, r.number)
+			}
+		}
+		var message string
+		blocking := true
+		switch {
+		case strings.HasPrefix(obj.pkg, "$"):
+			message = fmt.Sprintf("references %s in local package %s", ref, obj.pkg)
+		case released:
+			continue
+		case len(open) > 0:
+			message = fmt.Sprintf("references %s which is only on open request %s", ref, strings.Join(dedupeStrings(open), ", "))
+		default:
+			message = fmt.Sprintf("references %s (package %s) which was never transported", ref, obj.pkg)
+			blocking = false
+		}
+		for _, from := range referencedBy[name] {
+			issue(PreReleaseReference, from, message, blocking)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package adt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testTableData renders a freestyle SQL result.
+func testTableData(columns []string, rows [][]string) string {
+	var sb strings.Builder
+	sb.WriteString(`<dataPreview:tableData xmlns:dataPreview="http://www.sap.com/adt/dataPreview">`)
+	for i, col := range columns {
+		fmt.Fprintf(&sb, `<dataPreview:columns><dataPreview:metadata dataPreview:name="%s"/><dataPreview:dataSet>`, col)
+		for _, row := range rows {
+			fmt.Fprintf(&sb, `<dataPreview:data>%s</dataPreview:data>`, row[i])
+		}
+		sb.WriteString(`</dataPreview:dataSet></dataPreview:columns>`)
+	}
+	sb.WriteString(`</dataPreview:tableData>`)
+	return sb.String()
+}
+
+const testPreReleaseTransport = `<tm:root xmlns:tm="http://www.sap.com/cts/adt/tm">
+  <tm:request tm:number="DEVK900001" tm:owner="DEVELOPER" tm:desc="Orders" tm:type="K" tm:status="D" tm:target="QAS">
+    <tm:task tm:number="DEVK900002" tm:parent="DEVK900001" tm:owner="DEVELOPER" tm:type="S" tm:status="D">
+      <tm:abap_object tm:pgmid="R3TR" tm:type="CLAS" tm:name="ZCL_ORDER" tm:wbtype="CLAS/OC"/>
+      <tm:abap_object tm:pgmid="LIMU" tm:type="METH" tm:name="ZCL_ORDER                     CALC"/>
+      <tm:abap_object tm:pgmid="R3TR" tm:type="PROG" tm:name="ZORDER_REPORT" tm:wbtype="PROG/P"/>
+      <tm:abap_object tm:pgmid="R3TR" tm:type="TABU" tm:name="ZCONFIG"/>
+    </tm:task>
+  </tm:request>
+</tm:root>`
+
+const testPreReleaseSource = `CLASS zcl_order IMPLEMENTATION.
+  METHOD calc.
+    DATA(lo_helper) = NEW zcl_helper( ).
+    DATA lo_open TYPE REF TO zif_open.
+    zcl_done=>run( zvalue ).
+    zcl_new=>run( 'ZCL_LITERAL' ). " zcl_comment
+    zorder_report = 1.
+  ENDMETHOD.
+ENDCLASS.`
+
+func TestPreReleaseCheck(t *testing.T) {
+	var queries []string
+	var released bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+		case "/sap/bc/adt/cts/transportrequests/DEVK900001":
+			fmt.Fprint(w, testPreReleaseTransport)
+		case "/sap/bc/adt/cts/transportrequests/DEVK900001/newreleasejobs":
+			released = true
+		case "/sap/bc/adt/activation/inactiveobjects":
+			fmt.Fprint(w, `<ioc:inactiveObjects xmlns:ioc="http://www.sap.com/abapxml/inactiveCtsObjects" xmlns:adtcore="http://www.sap.com/adt/core">
+  <ioc:entry><ioc:object ioc:user="DEVELOPER"><ioc:ref adtcore:uri="/sap/bc/adt/programs/programs/zorder_report" adtcore:type="PROG/P" adtcore:name="ZORDER_REPORT"/></ioc:object></ioc:entry>
+  <ioc:entry><ioc:object ioc:user="DEVELOPER"><ioc:ref adtcore:uri="/sap/bc/adt/programs/programs/zother" adtcore:type="PROG/P" adtcore:name="ZOTHER"/></ioc:object></ioc:entry>
+</ioc:inactiveObjects>`)
+		case "/sap/bc/adt/checkruns":
+			if !strings.Contains(string(body), `adtcore:uri="/sap/bc/adt/oo/classes/ZCL_ORDER" chkrun:version="active"`) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `<chkrun:checkRunReports xmlns:chkrun="http://www.sap.com/adt/checkrun"><chkrun:checkReport><chkrun:checkMessageList>
+  <chkrun:checkMessage chkrun:uri="/sap/bc/adt/oo/classes/zcl_order/source/main#start=5,4" chkrun:type="E" chkrun:shortText="ZVALUE is unknown"/>
+  <chkrun:checkMessage chkrun:uri="/sap/bc/adt/oo/classes/zcl_order/source/main#start=3,4" chkrun:type="W" chkrun:shortText="Unused variable"/>
+</chkrun:checkMessageList></chkrun:checkReport></chkrun:checkRunReports>`)
+		case "/sap/bc/adt/atc/customizing":
+			fmt.Fprint(w, `<atccust:customizing xmlns:atccust="http://www.sap.com/adt/atc/customizing"><atccust:properties><atccust:property name="systemCheckVariant" value="DEFAULT"/></atccust:properties></atccust:customizing>`)
+		case "/sap/bc/adt/atc/worklists":
+			fmt.Fprint(w, "WL1")
+		case "/sap/bc/adt/atc/runs":
+			fmt.Fprint(w, `<atcworklist:worklistRun xmlns:atcworklist="http://www.sap.com/adt/atc/worklist"><atcworklist:worklistId>WL1</atcworklist:worklistId></atcworklist:worklistRun>`)
+		case "/sap/bc/adt/atc/worklists/WL1":
+			fmt.Fprintf(w, testATCSetWorklist, "WL1", testATCSetObject("ZCL_ORDER", 1, 2))
+		case "/sap/bc/adt/abapunit/testruns":
+			fmt.Fprint(w, `<aunit:runResult xmlns:aunit="http://www.sap.com/adt/aunit" xmlns:adtcore="http://www.sap.com/adt/core">
+  <program adtcore:uri="/sap/bc/adt/oo/classes/zcl_order" adtcore:type="CLAS/OC" adtcore:name="ZCL_ORDER">
+    <testClasses><testClass adtcore:uri="/sap/bc/adt/oo/classes/zcl_order/includes/testclasses#type=CLAS%2FOL;name=LTCL_ORDER" adtcore:type="CLAS/OL" adtcore:name="LTCL_ORDER">
+      <testMethods><testMethod adtcore:uri="/sap/bc/adt/oo/classes/zcl_order/includes/testclasses#type=CLAS%2FOLD;name=LTCL_ORDER%20CALC" adtcore:type="CLAS/OLD" adtcore:name="CALC" executionTime="0.010"><alerts><alert kind="failedAssertion" severity="critical"><title>Expected 3, got 4</title></alert></alerts></testMethod></testMethods>
+    </testClass></testClasses>
+  </program>
+</aunit:runResult>`)
+		case "/sap/bc/adt/cts/transportchecks":
+			request, user := "DEVK900001", "DEVELOPER"
+			if strings.Contains(string(body), "ZORDER_REPORT") {
+				request, user = "DEVK900099", "OTHER"
+			}
+			fmt.Fprintf(w, `<asx:abap xmlns:asx="http://www.sap.com/abapxml"><asx:values><DATA><PGMID>R3TR</PGMID><LOCKS><CTS_OBJECT_LOCK><LOCK_HOLDER><REQ_HEADER><TRKORR>%s</TRKORR><AS4USER>%s</AS4USER></REQ_HEADER></LOCK_HOLDER></CTS_OBJECT_LOCK></LOCKS></DATA></asx:values></asx:abap>`, request, user)
+		case "/sap/bc/adt/oo/classes/ZCL_ORDER/source/main":
+			fmt.Fprint(w, testPreReleaseSource)
+		case "/sap/bc/adt/programs/programs/ZORDER_REPORT/source/main":
+			fmt.Fprint(w, "REPORT zorder_report.")
+		case "/sap/bc/adt/datapreview/freestyle":
+			query := string(body)
+			queries = append(queries, query)
+			if strings.Contains(query, "FROM TADIR") {
+				fmt.Fprint(w, testTableData([]string{"OBJ_NAME", "OBJECT", "DEVCLASS"}, [][]string{
+					{"ZCL_HELPER", "CLAS", "$ZLOCAL"},
+					{"ZIF_OPEN", "INTF", "ZORDERS"},
+					{"ZCL_DONE", "CLAS", "ZORDERS"},
+					{"ZCL_NEW", "CLAS", "ZORDERS"},
+				}))
+				return
+			}
+			fmt.Fprint(w, testTableData([]string{"OBJ_NAME", "TRKORR", "STRKORR", "TRSTATUS"}, [][]string{
+				{"ZIF_OPEN", "DEVK900051", "DEVK900050", "D"},
+				{"ZCL_DONE", "DEVK900011", "DEVK900010", "R"},
+			}))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"), WithEnableTransports())
+	ctx := context.Background()
+
+	result, err := client.PreReleaseCheck(ctx, "devk900001", PreReleaseCheckOptions{})
+	if err != nil {
+		t.Fatalf("PreReleaseCheck failed: %v", err)
+	}
+	if result.Objects != 2 || len(result.Skipped) != 1 || result.Skipped[0] != "R3TR TABU ZCONFIG" {
+		t.Errorf("objects = %d, skipped = %v", result.Objects, result.Skipped)
+	}
+
+	var got []string
+	for _, issue := range result.Issues {
+		got = append(got, fmt.Sprintf("%s|%s|%s|%v", issue.Check, issue.Object, issue.Message, issue.Blocking))
+	}
+	want := []string{
+		"inactive|PROG ZORDER_REPORT|inactive version of PROG/P ZORDER_REPORT|true",
+		"syntax|CLAS ZCL_ORDER|line 5: ZVALUE is unknown|true",
+		"atc|CLAS ZCL_ORDER|line 10: P1 : Finding 0|true",
+		"unittest|CLAS ZCL_ORDER|LTCL_ORDER->CALC: Expected 3, got 4|true",
+		"lock|PROG ZORDER_REPORT|locked in request DEVK900099 by OTHER|true",
+		"reference|CLAS ZCL_ORDER|references CLAS ZCL_HELPER in local package $ZLOCAL|true",
+		"reference|CLAS ZCL_ORDER|references CLAS ZCL_NEW (package ZORDERS) which was never transported|false",
+		"reference|CLAS ZCL_ORDER|references INTF ZIF_OPEN which is only on open request DEVK900050|true",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if len(queries) != 2 || !strings.Contains(queries[0], "'ZCL_DONE', 'ZCL_HELPER', 'ZCL_NEW', 'ZIF_OPEN', 'ZVALUE'") {
+		t.Errorf("unexpected queries: %v", queries)
+	}
+	if result.Passed || result.Summary() != "DEVK900001: pre-release check FAILED, 2 objects, 7 blocking issues (1 inactive, 1 syntax, 1 atc, 1 unittest, 1 lock, 2 reference), 1 warnings" {
+		t.Errorf("summary = %q", result.Summary())
+	}
+
+	// The release gate refuses to release
+	err = client.ReleaseTransportV2(ctx, "DEVK900001", ReleaseTransportOptions{Check: true})
+	var blocked *PreReleaseCheckError
+	if !errors.As(err, &blocked) || released {
+		t.Fatalf("expected blocked release, got %v (released %v)", err, released)
+	}
+	if !strings.Contains(err.Error(), "[lock] PROG ZORDER_REPORT: locked in request DEVK900099 by OTHER") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := client.ReleaseTransportV2(ctx, "DEVK900001", ReleaseTransportOptions{}); err != nil || !released {
+		t.Errorf("ungated release failed: %v", err)
+	}
+}
+
+func TestPreReleaseCheck_FailsClosed(t *testing.T) {
+	var released bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+		case "/sap/bc/adt/cts/transportrequests/DEVK900001":
+			fmt.Fprint(w, testPreReleaseTransport)
+		case "/sap/bc/adt/cts/transportrequests/DEVK900001/newreleasejobs":
+			released = true
+		case "/sap/bc/adt/activation/inactiveobjects":
+			fmt.Fprint(w, `<ioc:inactiveObjects xmlns:ioc="http://www.sap.com/abapxml/inactiveCtsObjects"/>`)
+		default:
+			// The syntax check service is down
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"), WithEnableTransports())
+	ctx := context.Background()
+	opts := PreReleaseCheckOptions{SkipATC: true, SkipUnitTests: true, SkipLocks: true, SkipReferences: true}
+
+	result, err := client.PreReleaseCheck(ctx, "DEVK900001", opts)
+	if err != nil {
+		t.Fatalf("PreReleaseCheck failed: %v", err)
+	}
+	if result.Passed || len(result.NotRun) != 1 || result.NotRun[0] != PreReleaseSyntax {
+		t.Fatalf("a check that could not run must fail the result: %+v", result)
+	}
+	if !strings.HasSuffix(result.Summary(), "0 blocking issues, not run: syntax") {
+		t.Errorf("summary = %q", result.Summary())
+	}
+
+	err = client.ReleaseTransportV2(ctx, "DEVK900001", ReleaseTransportOptions{Check: true, CheckOptions: opts})
+	var blocked *PreReleaseCheckError
+	if !errors.As(err, &blocked) || released {
+		t.Fatalf("expected blocked release, got %v (released %v)", err, released)
+	}
+
+	// Skipping the failing check explicitly lets the release through
+	opts.SkipSyntax = true
+	if err := client.ReleaseTransportV2(ctx, "DEVK900001", ReleaseTransportOptions{Check: true, CheckOptions: opts}); err != nil || !released {
+		t.Errorf("release with skipped checks failed: %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package adt
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TransportExportManifestFile is the name of the manifest written next to
+// the files of a transport export.
+const TransportExportManifestFile = "transport-manifest.json"
+
+// Transport export backends.
+const (
+	TransportExportAbapGit = "abapgit" // GitExport via ZADT_VSP: full abapGit serialization
+	TransportExportSource  = "source"  // ADT source only, abapGit file names
+)
+
+// TransportExportSkip is a transport entry that was not exported.
+type TransportExportSkip struct {
+	PgmID  string `json:"pgmid"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// TransportExportPlan is the object list of a transport (request and tasks)
+// mapped to the main objects abapGit serializes.
+type TransportExportPlan struct {
+	Transport   string                `json:"transport"`
+	Description string                `json:"description,omitempty"`
+	Owner       string                `json:"owner,omitempty"`
+	Target      string                `json:"target,omitempty"`
+	Objects     []GitObjectRef        `json:"objects"`
+	Skipped     []TransportExportSkip `json:"skipped,omitempty"`
+}
+
+// TransportExportObject is an exported object and its files.
+type TransportExportObject struct {
+	Type  string   `json:"type"`
+	Name  string   `json:"name"`
+	Files []string `json:"files"`
+}
+
+// TransportExportManifest describes a transport export: what was written
+// and what could not be serialized.
+type TransportExportManifest struct {
+	Transport     string                  `json:"transport"`
+	Description   string                  `json:"description,omitempty"`
+	Owner         string                  `json:"owner,omitempty"`
+	Target        string                  `json:"target,omitempty"`
+	Backend       string                  `json:"backend"` // TransportExportAbapGit or TransportExportSource
+	ExportedAt    string                  `json:"exportedAt"`
+	Objects       []TransportExportObject `json:"objects"`
+	NotSerialized []TransportExportSkip   `json:"notSerialized,omitempty"`
+}
+
+// PlanTransportExport reads a transport and maps its entries to exportable
+// objects.
+func (c *Client) PlanTransportExport(ctx context.Context, number string) (*TransportExportPlan, error) {
+	details, err := c.GetTransport(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransportExportPlan(details), nil
+}
+
+// NewTransportExportPlan maps the entries of a request and its tasks to
+// main objects: sub-objects (LIMU) become their class, program, function
+// group or dictionary object. Table contents, request attributes and
+// function modules are skipped with a reason.
+func NewTransportExportPlan(details *TransportDetails) *TransportExportPlan {
+	plan := &TransportExportPlan{
+		Transport:   strings.ToUpper(details.Number),
+		Description: details.Description,
+		Owner:       details.Owner,
+		Target:      details.Target,
+		Objects:     []GitObjectRef{},
+	}
+	entries := append([]TransportObjectV2{}, details.Objects...)
+	for _, task := range details.Tasks {
+		entries = append(entries, task.Objects...)
+	}
+
+	seen := make(map[string]bool)
+	skipped := make(map[string]bool)
+	for _, entry := range entries {
+		ref, reason := transportExportRef(entry)
+		if reason != "" {
+			key := entry.PgmID + " " + entry.Type + " " + entry.Name
+			if !skipped[key] {
+				skipped[key] = true
+				plan.Skipped = append(plan.Skipped, TransportExportSkip{
+					PgmID: entry.PgmID, Type: entry.Type, Name: strings.TrimSpace(entry.Name), Reason: reason,
+				})
+			}
+			continue
+		}
+		if key := ref.Type + " " + ref.Name; !seen[key] {
+			seen[key] = true
+			plan.Objects = append(plan.Objects, ref)
+		}
+	}
+	return plan
+}
+
+// transportExportLIMU maps sub-object types to the type of their main object.
+var transportExportLIMU = map[string]string{
+	"METH": "CLAS", "CLSD": "CLAS", "CPUB": "CLAS", "CPRO": "CLAS", "CPRI": "CLAS", "CINC": "CLAS",
+	"INTD": "INTF",
+	"TABD": "TABL", "TABT": "TABL", "DOMD": "DOMA", "DTED": "DTEL", "VIED": "VIEW",
+	"TTYD": "TTYP", "SHLD": "SHLP", "ENQD": "ENQU", "MESS": "MSAG", "MSAD": "MSAG",
+}
+
+// functionGroupInclude matches the includes of a function group
+// (L<group>TOP, L<group>U01, L<group>F01, ...).
+var functionGroupInclude = regexp.MustCompile(`^(/[A-Z0-9_]+/)?L([A-Z0-9_]+)(TOP|UXX|[UFIOTDPEV][0-9]{2})$`)
+
+// transportExportRef returns the main object of a transport entry, or the
+// reason it is not exported.
+func transportExportRef(entry TransportObjectV2) (GitObjectRef, string) {
+	name := strings.TrimSpace(entry.Name)
+	if name == "" {
+		return GitObjectRef{}, "empty name"
+	}
+	switch entry.PgmID {
+	case "CORR":
+		return GitObjectRef{}, "request attribute, not a repository object"
+	case "R3TR":
+		switch entry.Type {
+		case "TABU", "TDAT", "CDAT", "VDAT":
+			return GitObjectRef{}, "table contents cannot be serialized"
+		}
+		return GitObjectRef{Type: entry.Type, Name: name}, ""
+	case "LIMU":
+	default:
+		return GitObjectRef{}, "unknown program ID " + entry.PgmID
+	}
+
+	// Sub-object names start with the main object: "ZCL_ORDER<padding>CALC"
+	main := strings.Fields(name)[0]
+	switch entry.Type {
+	case "FUNC":
+		return GitObjectRef{}, "function module: add R3TR FUGR of its function group"
+	case "REPS", "REPT", "DYNP", "CUAD":
+		if i := strings.Index(main, "="); i > 0 {
+			return GitObjectRef{Type: "CLAS", Name: main[:i]}, ""
+		}
+		if m := functionGroupInclude.FindStringSubmatch(main); m != nil {
+			return GitObjectRef{Type: "FUGR", Name: m[1] + m[2]}, ""
+		}
+		if group, ok := strings.CutPrefix(main, "SAPL"); ok {
+			return GitObjectRef{Type: "FUGR", Name: group}, ""
+		}
+		if strings.HasPrefix(main, "/") {
+			if ns, rest, ok := strings.Cut(main[1:], "/"); ok {
+				if group, ok := strings.CutPrefix(rest, "SAPL"); ok {
+					return GitObjectRef{Type: "FUGR", Name: "/" + ns + "/" + group}, ""
+				}
+			}
+		}
+		return GitObjectRef{Type: "PROG", Name: main}, ""
+	}
+	if typ, ok := transportExportLIMU[entry.Type]; ok {
+		if i := strings.Index(main, "="); i > 0 {
+			main = main[:i]
+		}
+		return GitObjectRef{Type: typ, Name: main}, ""
+	}
+	return GitObjectRef{}, "sub-object type " + entry.Type + " has no main object mapping"
+}
+
+// TransportExportFileName returns the abapGit base file name of an object
+// ("zcl_order.clas", "#dmo#i_travel.ddls"); its files start with it.
+func TransportExportFileName(ref GitObjectRef) string {
+	return strings.ToLower(strings.ReplaceAll(ref.Name, "/", "#")) + "." + strings.ToLower(ref.Type)
+}
+
+// MatchTransportExportFiles assigns exported file paths to the planned
+// objects. Objects without files are returned as not serialized.
+func MatchTransportExportFiles(plan *TransportExportPlan, files []string, reason string) ([]TransportExportObject, []TransportExportSkip) {
+	var objects []TransportExportObject
+	var missing []TransportExportSkip
+	for _, ref := range plan.Objects {
+		prefix := TransportExportFileName(ref) + "."
+		obj := TransportExportObject{Type: ref.Type, Name: ref.Name, Files: []string{}}
+		for _, f := range files {
+			if strings.HasPrefix(strings.ToLower(path.Base(f)), prefix) {
+				obj.Files = append(obj.Files, f)
+			}
+		}
+		if len(obj.Files) == 0 {
+			missing = append(missing, TransportExportSkip{PgmID: "R3TR", Type: ref.Type, Name: ref.Name, Reason: reason})
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	return objects, missing
+}
+
+// NewTransportExportManifest builds the manifest of a transport export from
+// the plan and the exported file paths. Planned objects without files are
+// listed as not serialized, after the entries the plan skipped.
+func NewTransportExportManifest(plan *TransportExportPlan, files []string, backend string) *TransportExportManifest {
+	reason := "not serialized by abapGit (unsupported type or object not found)"
+	if backend == TransportExportSource {
+		reason = "source could not be read"
+	}
+	objects, missing := MatchTransportExportFiles(plan, files, reason)
+	manifest := &TransportExportManifest{
+		Transport:     plan.Transport,
+		Description:   plan.Description,
+		Owner:         plan.Owner,
+		Target:        plan.Target,
+		Backend:       backend,
+		ExportedAt:    time.Now().UTC().Format(time.RFC3339),
+		Objects:       []TransportExportObject{},
+		NotSerialized: append(append([]TransportExportSkip{}, plan.Skipped...), missing...),
+	}
+	if objects != nil {
+		manifest.Objects = objects
+	}
+	return manifest
+}
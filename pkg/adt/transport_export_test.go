@@ -0,0 +1,108 @@
+package adt
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNewTransportExportPlan(t *testing.T) {
+	details := &TransportDetails{
+		TransportSummary: TransportSummary{Number: "devk900001", Description: "Orders"},
+		Objects: []TransportObjectV2{
+			{PgmID: "CORR", Type: "RELE", Name: "DEVK900001"},
+		},
+		Tasks: []TransportTaskV2{{
+			Number: "DEVK900002",
+			Objects: []TransportObjectV2{
+				{PgmID: "R3TR", Type: "CLAS", Name: "ZCL_ORDER"},
+				{PgmID: "LIMU", Type: "METH", Name: "ZCL_ORDER                     CALC"},
+				{PgmID: "LIMU", Type: "REPS", Name: "ZCL_ORDER=====================CCIMP"},
+				{PgmID: "LIMU", Type: "REPS", Name: "LZORDERSU01"},
+				{PgmID: "LIMU", Type: "REPS", Name: "/DMO/SAPLFLIGHT"},
+				{PgmID: "LIMU", Type: "REPS", Name: "ZORDER_REPORT"},
+				{PgmID: "LIMU", Type: "TABD", Name: "ZORDERS"},
+				{PgmID: "LIMU", Type: "FUNC", Name: "Z_ORDER_CREATE"},
+				{PgmID: "R3TR", Type: "TABU", Name: "ZCONFIG"},
+				{PgmID: "R3TR", Type: "TABU", Name: "ZCONFIG"},
+				{PgmID: "R3TR", Type: "DDLS", Name: "/DMO/I_TRAVEL"},
+			},
+		}},
+	}
+
+	plan := NewTransportExportPlan(details)
+	if plan.Transport != "DEVK900001" || plan.Description != "Orders" {
+		t.Errorf("unexpected header: %+v", plan)
+	}
+	var got []string
+	for _, ref := range plan.Objects {
+		got = append(got, ref.Type+" "+ref.Name)
+	}
+	want := "CLAS ZCL_ORDER,FUGR ZORDERS,FUGR /DMO/FLIGHT,PROG ZORDER_REPORT,TABL ZORDERS,DDLS /DMO/I_TRAVEL"
+	if strings.Join(got, ",") != want {
+		t.Errorf("objects = %s, want %s", strings.Join(got, ","), want)
+	}
+	var skipped []string
+	for _, s := range plan.Skipped {
+		skipped = append(skipped, s.PgmID+" "+s.Type+" "+s.Name)
+	}
+	if strings.Join(skipped, ",") != "CORR RELE DEVK900001,LIMU FUNC Z_ORDER_CREATE,R3TR TABU ZCONFIG" {
+		t.Errorf("skipped = %v", skipped)
+	}
+}
+
+func TestMatchTransportExportFiles(t *testing.T) {
+	plan := &TransportExportPlan{Objects: []GitObjectRef{
+		{Type: "CLAS", Name: "ZCL_ORDER"},
+		{Type: "DDLS", Name: "/DMO/I_TRAVEL"},
+		{Type: "TABL", Name: "ZORDERS"},
+	}}
+	files := []string{
+		"src/zcl_order.clas.abap",
+		"src/zcl_order.clas.locals_imp.abap",
+		"src/zcl_order.clas.xml",
+		"src/zcl_order_helper.clas.abap",
+		"src/#dmo#i_travel.ddls.asddls",
+		"package.devc.xml",
+	}
+
+	objects, missing := MatchTransportExportFiles(plan, files, "not found")
+	var got []string
+	for _, o := range objects {
+		got = append(got, fmt.Sprintf("%s %s %d", o.Type, o.Name, len(o.Files)))
+	}
+	if strings.Join(got, ",") != "CLAS ZCL_ORDER 3,DDLS /DMO/I_TRAVEL 1" {
+		t.Errorf("objects = %v", got)
+	}
+	if len(missing) != 1 || missing[0].Name != "ZORDERS" || missing[0].Reason != "not found" {
+		t.Errorf("missing = %+v", missing)
+	}
+	if name := TransportExportFileName(GitObjectRef{Type: "DDLS", Name: "/DMO/I_TRAVEL"}); name != "#dmo#i_travel.ddls" {
+		t.Errorf("TransportExportFileName = %q", name)
+	}
+}
+
+func TestNewTransportExportManifest(t *testing.T) {
+	plan := &TransportExportPlan{
+		Transport: "DEVK900001",
+		Objects:   []GitObjectRef{{Type: "CLAS", Name: "ZCL_ORDER"}, {Type: "TABL", Name: "ZORDERS"}},
+		Skipped:   []TransportExportSkip{{PgmID: "R3TR", Type: "TABU", Name: "ZCONFIG", Reason: "table contents cannot be serialized"}},
+	}
+
+	manifest := NewTransportExportManifest(plan, []string{"src/zcl_order.clas.abap"}, TransportExportAbapGit)
+	if manifest.Transport != "DEVK900001" || manifest.Backend != TransportExportAbapGit || manifest.ExportedAt == "" {
+		t.Errorf("unexpected header: %+v", manifest)
+	}
+	if len(manifest.Objects) != 1 || manifest.Objects[0].Name != "ZCL_ORDER" {
+		t.Errorf("objects = %+v", manifest.Objects)
+	}
+	if len(manifest.NotSerialized) != 2 || manifest.NotSerialized[0].Name != "ZCONFIG" ||
+		manifest.NotSerialized[1].Reason != "not serialized by abapGit (unsupported type or object not found)" {
+		t.Errorf("not serialized = %+v", manifest.NotSerialized)
+	}
+
+	manifest = NewTransportExportManifest(plan, nil, TransportExportSource)
+	if manifest.Objects == nil || len(manifest.Objects) != 0 || manifest.NotSerialized[2].Reason != "source could not be read" {
+		t.Errorf("unexpected source manifest: %+v", manifest)
+	}
+}
@@ -0,0 +1,566 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ADT has no service to edit the object list of a request, so these
+// operations run small ABAP programs through ExecuteABAP that call the same
+// CTS function modules as SE09 (TR_APPEND_TO_COMM_OBJS_KEYS,
+// TRINT_DELETE_COMM_OBJECT_KEYS, TR_COPY_COMM, TR_INSERT_REQUEST_WITH_TASKS,
+// TR_DELETE_COMM); they never write the CTS tables directly.
+
+// TransportObjectsResult is the outcome of an object list change.
+type TransportObjectsResult struct {
+	Transport string              `json:"transport"`
+	Objects   []TransportObjectV2 `json:"objects,omitempty"`
+	Count     int                 `json:"count"`             // Entries added, removed or copied
+	Sources   []string            `json:"sources,omitempty"` // Requests/tasks the entries came from
+	Deleted   string              `json:"deleted,omitempty"` // Emptied task that was deleted
+	Released  bool                `json:"released,omitempty"`
+	Message   string              `json:"message,omitempty"`
+}
+
+// TransportOfCopiesOptions configures CreateTransportOfCopies.
+type TransportOfCopiesOptions struct {
+	Description string // Default: "ToC of <source>: <source description>"
+	Target      string // Target system; default: target of the source request
+	Release     bool   // Release the transport of copies right away
+}
+
+var (
+	transportNumberRegex = regexp.MustCompile(`^[A-Z0-9]{3}K[0-9]{6}$`)
+	transportKeyRegex    = regexp.MustCompile(`^[A-Z0-9/_]{1,4}$`)
+	transportTargetRegex = regexp.MustCompile(`^[A-Z0-9_.]{1,10}$`)
+)
+
+// transportURLTypes maps ADT URL prefixes to TADIR object types (the inverse
+// of TransportObjectURL).
+var transportURLTypes = []struct{ prefix, typ string }{
+	{"/sap/bc/adt/oo/classes/", "CLAS"},
+	{"/sap/bc/adt/oo/interfaces/", "INTF"},
+	{"/sap/bc/adt/programs/programs/", "PROG"},
+	{"/sap/bc/adt/programs/includes/", "PROG"},
+	{"/sap/bc/adt/functions/groups/", "FUGR"},
+	{"/sap/bc/adt/ddic/ddl/sources/", "DDLS"},
+	{"/sap/bc/adt/bo/behaviordefinitions/", "BDEF"},
+	{"/sap/bc/adt/ddic/srvd/sources/", "SRVD"},
+	{"/sap/bc/adt/businessservices/bindings/", "SRVB"},
+	{"/sap/bc/adt/ddic/tables/", "TABL"},
+	{"/sap/bc/adt/ddic/structures/", "TABL"},
+	{"/sap/bc/adt/ddic/dataelements/", "DTEL"},
+	{"/sap/bc/adt/ddic/domains/", "DOMA"},
+	{"/sap/bc/adt/packages/", "DEVC"},
+	{"/sap/bc/adt/messageclass/", "MSAG"},
+}
+
+// ParseTransportObject parses an object list entry given as an ADT URI
+// ("/sap/bc/adt/oo/classes/zcl_order"), a TADIR key ("CLAS ZCL_ORDER") or
+// a full E071 key ("R3TR CLAS ZCL_ORDER", "LIMU METH ZCL_ORDER CALC").
+// Sub-objects of a URI (source includes, function modules) map to the main
+// object.
+func ParseTransportObject(s string) (TransportObjectV2, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "/sap/bc/adt/") {
+		lower := strings.ToLower(s)
+		for _, t := range transportURLTypes {
+			if !strings.HasPrefix(lower, t.prefix) {
+				continue
+			}
+			name := s[len(t.prefix):]
+			if i := strings.IndexAny(name, "/?#"); i >= 0 {
+				name = name[:i]
+			}
+			name = strings.ToUpper(strings.ReplaceAll(strings.ReplaceAll(name, "%2f", "/"), "%2F", "/"))
+			if name == "" {
+				break
+			}
+			return TransportObjectV2{PgmID: "R3TR", Type: t.typ, Name: name}, nil
+		}
+		return TransportObjectV2{}, fmt.Errorf("cannot map %s to a TADIR key; use \"TYPE NAME\"", s)
+	}
+
+	fields := strings.Fields(strings.ToUpper(s))
+	switch {
+	case len(fields) == 2:
+		fields = append([]string{"R3TR"}, fields...)
+	case len(fields) > 3 && fields[0] == "LIMU":
+		// Sub-object names are "MAIN<padding>SUB" (e.g. LIMU METH)
+		fields = []string{fields[0], fields[1], fmt.Sprintf("%-30s%s", fields[2], strings.Join(fields[3:], " "))}
+	}
+	if len(fields) != 3 || !transportKeyRegex.MatchString(fields[0]) || !transportKeyRegex.MatchString(fields[1]) {
+		return TransportObjectV2{}, fmt.Errorf("invalid object %q: expected an ADT URI, \"TYPE NAME\" or \"PGMID TYPE NAME\"", s)
+	}
+	switch fields[0] {
+	case "R3TR", "LIMU", "CORR":
+	default:
+		return TransportObjectV2{}, fmt.Errorf("invalid object %q: unknown program ID %s", s, fields[0])
+	}
+	if len(fields[2]) > 120 {
+		return TransportObjectV2{}, fmt.Errorf("invalid object %q: name too long", s)
+	}
+	return TransportObjectV2{PgmID: fields[0], Type: fields[1], Name: fields[2]}, nil
+}
+
+// ParseTransportObjects parses a list of object entries (see
+// ParseTransportObject) and drops duplicates.
+func ParseTransportObjects(values []string) ([]TransportObjectV2, error) {
+	var objects []TransportObjectV2
+	seen := make(map[string]bool)
+	for _, v := range values {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		obj, err := ParseTransportObject(v)
+		if err != nil {
+			return nil, err
+		}
+		key := obj.PgmID + " " + obj.Type + " " + obj.Name
+		if !seen[key] {
+			seen[key] = true
+			objects = append(objects, obj)
+		}
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no objects given")
+	}
+	return objects, nil
+}
+
+// AddTransportObjects adds entries to the object list of a modifiable
+// request or task, which is locked meanwhile. Objects are locked in the
+// request like in SE09.
+func (c *Client) AddTransportObjects(ctx context.Context, number string, objects []TransportObjectV2) (*TransportObjectsResult, error) {
+	number = strings.ToUpper(strings.TrimSpace(number))
+	if err := c.config.Safety.CheckTransport(number, "AddTransportObjects", true); err != nil {
+		return nil, err
+	}
+	if err := validateTransportNumber(number); err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no objects given")
+	}
+
+	out, err := c.runTransportABAP(ctx, "AddTransportObjects", addTransportObjectsABAP(number, objects))
+	if err != nil {
+		return nil, err
+	}
+	count, _ := strconv.Atoi(out)
+	return &TransportObjectsResult{Transport: number, Objects: objects, Count: count}, nil
+}
+
+// RemoveTransportObjects removes entries (and their table keys) from the
+// object list of a modifiable request or task. Object locks held by the
+// request are kept, as in SE09.
+func (c *Client) RemoveTransportObjects(ctx context.Context, number string, objects []TransportObjectV2) (*TransportObjectsResult, error) {
+	number = strings.ToUpper(strings.TrimSpace(number))
+	if err := c.config.Safety.CheckTransport(number, "RemoveTransportObjects", true); err != nil {
+		return nil, err
+	}
+	if err := validateTransportNumber(number); err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no objects given")
+	}
+
+	out, err := c.runTransportABAP(ctx, "RemoveTransportObjects", removeTransportObjectsABAP(number, objects))
+	if err != nil {
+		return nil, err
+	}
+	count, _ := strconv.Atoi(out)
+	result := &TransportObjectsResult{Transport: number, Objects: objects, Count: count}
+	if count == 0 {
+		result.Message = "none of the objects were on " + number
+	}
+	return result, nil
+}
+
+// MergeTransportTasks moves the object list of a task into another task of
+// the same request and deletes the emptied source task.
+func (c *Client) MergeTransportTasks(ctx context.Context, source, target string) (*TransportObjectsResult, error) {
+	source = strings.ToUpper(strings.TrimSpace(source))
+	target = strings.ToUpper(strings.TrimSpace(target))
+	for _, nr := range []string{source, target} {
+		if err := c.config.Safety.CheckTransport(nr, "MergeTransportTasks", true); err != nil {
+			return nil, err
+		}
+		if err := validateTransportNumber(nr); err != nil {
+			return nil, err
+		}
+	}
+	if source == target {
+		return nil, fmt.Errorf("cannot merge task %s into itself", source)
+	}
+
+	// Both tasks must be modifiable tasks of one request: the object locks
+	// belong to the request, so they stay valid for the target task. Reading
+	// a task returns its request.
+	details, err := c.GetTransport(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	request := strings.ToUpper(details.Number)
+	if request == source {
+		return nil, fmt.Errorf("%s is a request, not a task", source)
+	}
+	tasks := make(map[string]TransportTaskV2)
+	for _, task := range details.Tasks {
+		tasks[strings.ToUpper(task.Number)] = task
+	}
+	for _, nr := range []string{source, target} {
+		task, ok := tasks[nr]
+		if !ok {
+			return nil, fmt.Errorf("%s is not a task of request %s", nr, request)
+		}
+		if task.Status == "R" || task.Status == "O" || task.Status == "N" {
+			return nil, fmt.Errorf("task %s is not modifiable (status %s)", nr, task.Status)
+		}
+	}
+
+	out, err := c.runTransportABAP(ctx, "MergeTransportTasks", mergeTransportTasksABAP(source, target))
+	if err != nil {
+		return nil, err
+	}
+	count, _ := strconv.Atoi(out)
+	result := &TransportObjectsResult{Transport: target, Count: count, Sources: []string{source}, Objects: tasks[source].Objects}
+	if err := c.DeleteTransport(ctx, source); err != nil {
+		result.Message = fmt.Sprintf("objects moved, but task %s could not be deleted: %v", source, err)
+	} else {
+		result.Deleted = source
+	}
+	return result, nil
+}
+
+// CreateTransportOfCopies creates a transport of copies (type T) with the
+// object list of a request and its tasks, optionally releasing it.
+func (c *Client) CreateTransportOfCopies(ctx context.Context, source string, opts TransportOfCopiesOptions) (*TransportObjectsResult, error) {
+	source = strings.ToUpper(strings.TrimSpace(source))
+	if err := c.config.Safety.CheckTransport(source, "CreateTransportOfCopies", false); err != nil {
+		return nil, err
+	}
+	if err := c.config.Safety.CheckTransport("", "CreateTransport", true); err != nil {
+		return nil, err
+	}
+	if err := validateTransportNumber(source); err != nil {
+		return nil, err
+	}
+
+	details, err := c.GetTransport(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	sources := []string{strings.ToUpper(details.Number)}
+	var objects []TransportObjectV2
+	seen := make(map[string]bool)
+	add := func(entries []TransportObjectV2) {
+		for _, obj := range entries {
+			key := obj.PgmID + " " + obj.Type + " " + obj.Name
+			if !seen[key] {
+				seen[key] = true
+				objects = append(objects, obj)
+			}
+		}
+	}
+	add(details.Objects)
+	for _, task := range details.Tasks {
+		sources = append(sources, strings.ToUpper(task.Number))
+		add(task.Objects)
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("request %s has no objects", source)
+	}
+
+	target := strings.ToUpper(strings.TrimSpace(opts.Target))
+	if target == "" {
+		target = strings.ToUpper(details.Target)
+	}
+	if target == "" {
+		return nil, fmt.Errorf("request %s has no target system; specify the target", source)
+	}
+	if !transportTargetRegex.MatchString(target) {
+		return nil, fmt.Errorf("invalid target system %q", target)
+	}
+	description := opts.Description
+	if description == "" {
+		description = "ToC of " + source
+		if details.Description != "" {
+			description += ": " + details.Description
+		}
+	}
+	if r := []rune(description); len(r) > 60 {
+		description = string(r[:60])
+	}
+
+	out, err := c.runTransportABAP(ctx, "CreateTransportOfCopies", transportOfCopiesABAP(sources, description, target))
+	if err != nil {
+		return nil, err
+	}
+	number, count, _ := strings.Cut(out, " ")
+	result := &TransportObjectsResult{Transport: number, Objects: objects, Sources: sources}
+	result.Count, _ = strconv.Atoi(count)
+
+	if opts.Release {
+		if err := c.ReleaseTransportV2(ctx, number, ReleaseTransportOptions{}); err != nil {
+			result.Message = fmt.Sprintf("created, but release failed: %v", err)
+			return result, nil
+		}
+		result.Released = true
+	}
+	return result, nil
+}
+
+func validateTransportNumber(number string) error {
+	if number == "" {
+		return fmt.Errorf("transport number is required")
+	}
+	if !transportNumberRegex.MatchString(number) {
+		return fmt.Errorf("invalid transport number %q", number)
+	}
+	return nil
+}
+
+// runTransportABAP executes generated CTS code and returns what follows
+// "OK:" in its result; "ERROR:" results become errors.
+func (c *Client) runTransportABAP(ctx context.Context, op, code string) (string, error) {
+	result, err := c.ExecuteABAP(ctx, code, &ExecuteABAPOptions{RiskLevel: "dangerous", ProgramPrefix: "ZTEMP_CTS_"})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if !result.Success {
+		return "", fmt.Errorf("%s: %s", op, result.Message)
+	}
+	if len(result.Output) == 0 {
+		return "", fmt.Errorf("%s: no result returned", op)
+	}
+	return parseTransportABAPResult(op, result.Output[0])
+}
+
+func parseTransportABAPResult(op, output string) (string, error) {
+	output = strings.TrimSpace(output)
+	switch {
+	case strings.HasPrefix(output, "OK:"):
+		return strings.TrimSpace(strings.TrimPrefix(output, "OK:")), nil
+	case strings.HasPrefix(output, "ERROR:"):
+		return "", fmt.Errorf("%s: %s", op, strings.TrimSpace(strings.TrimPrefix(output, "ERROR:")))
+	}
+	return "", fmt.Errorf("%s: unexpected result %q", op, output)
+}
+
+// abapQuote quotes a value as an ABAP text literal.
+func abapQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// transportErrorABAP renders the current system message into lv_message.
+const transportErrorABAP = `MESSAGE ID sy-msgid TYPE 'S' NUMBER sy-msgno WITH sy-msgv1 sy-msgv2 sy-msgv3 sy-msgv4 INTO lv_message.`
+
+func addTransportObjectsABAP(number string, objects []TransportObjectV2) string {
+	var sb strings.Builder
+	sb.WriteString("DATA lt_e071 TYPE STANDARD TABLE OF e071 WITH EMPTY KEY.\n")
+	sb.WriteString("DATA lt_e071k TYPE STANDARD TABLE OF e071k WITH EMPTY KEY.\n")
+	sb.WriteString("DATA lv_message TYPE string.\n")
+	fmt.Fprintf(&sb, "DATA(lv_trkorr) = CONV trkorr( %s ).\n", abapQuote(number))
+	for _, obj := range objects {
+		fmt.Fprintf(&sb, "APPEND VALUE #( pgmid = %s object = %s obj_name = %s objfunc = space ) TO lt_e071.\n",
+			abapQuote(obj.PgmID), abapQuote(obj.Type), abapQuote(obj.Name))
+	}
+	sb.WriteString(transportModifiableABAP)
+	fmt.Fprintf(&sb, `IF lv_message IS INITIAL.
+  CALL FUNCTION 'TR_APPEND_TO_COMM_OBJS_KEYS'
+    EXPORTING
+      wi_trkorr = lv_trkorr
+    TABLES
+      wt_e071   = lt_e071
+      wt_e071k  = lt_e071k
+    EXCEPTIONS
+      OTHERS    = 1.
+  IF sy-subrc <> 0.
+    %s
+    ROLLBACK WORK.
+  ELSE.
+    COMMIT WORK AND WAIT.
+    lv_result = |OK:{ lines( lt_e071 ) }|.
+  ENDIF.
+ENDIF.
+IF lv_message IS NOT INITIAL.
+  lv_result = |ERROR:{ lv_message }|.
+ENDIF.
+CALL FUNCTION 'DEQUEUE_E_TRKORR'
+  EXPORTING
+    trkorr = lv_trkorr.
+`, transportErrorABAP)
+	return sb.String()
+}
+
+// transportModifiableABAP locks the request/task in lv_trkorr and sets
+// lv_message unless it is modifiable.
+const transportModifiableABAP = `CALL FUNCTION 'ENQUEUE_E_TRKORR'
+  EXPORTING
+    trkorr = lv_trkorr
+  EXCEPTIONS
+    OTHERS = 1.
+IF sy-subrc <> 0.
+  lv_message = |{ lv_trkorr } is locked by another user|.
+ELSE.
+  SELECT SINGLE trstatus FROM e070 WHERE trkorr = @lv_trkorr INTO @DATA(lv_status).
+  IF sy-subrc <> 0.
+    lv_message = |{ lv_trkorr } does not exist|.
+  ELSEIF lv_status <> 'D' AND lv_status <> 'L'.
+    lv_message = |{ lv_trkorr } is not modifiable (status { lv_status })|.
+  ENDIF.
+ENDIF.
+`
+
+// transportDeleteEntriesABAP deletes the entries in lt_e071 (with their table
+// keys) from lv_trkorr like SE09, counting them in lv_count; on failure it
+// sets lv_message and stops.
+const transportDeleteEntriesABAP = `LOOP AT lt_e071 INTO DATA(ls_e071).
+    CALL FUNCTION 'TRINT_DELETE_COMM_OBJECT_KEYS'
+      EXPORTING
+        is_e071_delete = ls_e071
+        iv_dialog_flag = abap_false
+      EXCEPTIONS
+        OTHERS         = 1.
+    IF sy-subrc <> 0.
+      ` + transportErrorABAP + `
+      lv_message = |{ ls_e071-pgmid } { ls_e071-object } { ls_e071-obj_name }: { lv_message }|.
+      EXIT.
+    ENDIF.
+    lv_count = lv_count + 1.
+  ENDLOOP.
+`
+
+func removeTransportObjectsABAP(number string, objects []TransportObjectV2) string {
+	var sb strings.Builder
+	sb.WriteString("DATA lv_message TYPE string.\n")
+	sb.WriteString("DATA lv_count TYPE i.\n")
+	sb.WriteString("DATA lt_e071 TYPE STANDARD TABLE OF e071 WITH EMPTY KEY.\n")
+	fmt.Fprintf(&sb, "DATA(lv_trkorr) = CONV trkorr( %s ).\n", abapQuote(number))
+	sb.WriteString(transportModifiableABAP)
+	sb.WriteString("IF lv_message IS INITIAL.\n")
+	for _, obj := range objects {
+		fmt.Fprintf(&sb, "  SELECT * FROM e071 WHERE trkorr = @lv_trkorr AND pgmid = %s AND object = %s AND obj_name = %s APPENDING TABLE @lt_e071.\n",
+			abapQuote(obj.PgmID), abapQuote(obj.Type), abapQuote(obj.Name))
+	}
+	sb.WriteString("  " + transportDeleteEntriesABAP)
+	sb.WriteString(`  IF lv_message IS INITIAL.
+    COMMIT WORK AND WAIT.
+    lv_result = |OK:{ lv_count }|.
+  ELSE.
+    ROLLBACK WORK.
+  ENDIF.
+ENDIF.
+IF lv_message IS NOT INITIAL.
+  lv_result = |ERROR:{ lv_message }|.
+ENDIF.
+CALL FUNCTION 'DEQUEUE_E_TRKORR'
+  EXPORTING
+    trkorr = lv_trkorr.
+`)
+	return sb.String()
+}
+
+func mergeTransportTasksABAP(source, target string) string {
+	return fmt.Sprintf(`DATA lv_message TYPE string.
+DATA lv_count TYPE i.
+DATA lt_e071 TYPE STANDARD TABLE OF e071 WITH EMPTY KEY.
+DATA(lv_trkorr) = CONV trkorr( %s ).
+DATA(lv_target) = CONV trkorr( %s ).
+%sIF lv_message IS INITIAL.
+  SELECT * FROM e071 WHERE trkorr = @lv_trkorr INTO TABLE @lt_e071.
+  CALL FUNCTION 'TR_COPY_COMM'
+    EXPORTING
+      wi_dialog                = abap_false
+      wi_trkorr_from           = lv_trkorr
+      wi_trkorr_to             = lv_target
+      wi_without_documentation = abap_false
+    EXCEPTIONS
+      OTHERS                   = 1.
+  IF sy-subrc <> 0.
+    %s
+  ELSE.
+    %s
+  ENDIF.
+  IF lv_message IS INITIAL.
+    COMMIT WORK AND WAIT.
+    lv_result = |OK:{ lv_count }|.
+  ELSE.
+    ROLLBACK WORK.
+  ENDIF.
+ENDIF.
+IF lv_message IS NOT INITIAL.
+  lv_result = |ERROR:{ lv_message }|.
+ENDIF.
+CALL FUNCTION 'DEQUEUE_E_TRKORR'
+  EXPORTING
+    trkorr = lv_trkorr.
+`, abapQuote(source), abapQuote(target), transportModifiableABAP, transportErrorABAP, transportDeleteEntriesABAP)
+}
+
+func transportOfCopiesABAP(sources []string, description, target string) string {
+	var quoted []string
+	for _, s := range sources {
+		quoted = append(quoted, "( "+abapQuote(s)+" )")
+	}
+	return fmt.Sprintf(`DATA lv_message TYPE string.
+DATA ls_header TYPE trwbo_request_header.
+DATA lt_from TYPE STANDARD TABLE OF trkorr WITH EMPTY KEY.
+lt_from = VALUE #( %s ).
+CALL FUNCTION 'TR_INSERT_REQUEST_WITH_TASKS'
+  EXPORTING
+    iv_type           = 'T'
+    iv_text           = %s
+    iv_owner          = sy-uname
+    iv_target         = %s
+  IMPORTING
+    es_request_header = ls_header
+  EXCEPTIONS
+    OTHERS            = 1.
+IF sy-subrc <> 0.
+  %s
+  lv_result = |ERROR:{ lv_message }|.
+ELSE.
+  LOOP AT lt_from INTO DATA(lv_from).
+    CALL FUNCTION 'TR_COPY_COMM'
+      EXPORTING
+        wi_dialog                = abap_false
+        wi_trkorr_from           = lv_from
+        wi_trkorr_to             = ls_header-trkorr
+        wi_without_documentation = abap_false
+      EXCEPTIONS
+        OTHERS                   = 1.
+    IF sy-subrc <> 0.
+      %s
+      lv_message = |copying { lv_from } to { ls_header-trkorr }: { lv_message }|.
+      EXIT.
+    ENDIF.
+  ENDLOOP.
+  IF lv_message IS INITIAL.
+    COMMIT WORK AND WAIT.
+    SELECT COUNT(*) FROM e071 WHERE trkorr = @ls_header-trkorr INTO @DATA(lv_count).
+    lv_result = |OK:{ ls_header-trkorr } { lv_count }|.
+  ELSE.
+    " Do not leave a half-filled transport of copies behind
+    ROLLBACK WORK.
+    CALL FUNCTION 'TR_DELETE_COMM'
+      EXPORTING
+        wi_dialog = abap_false
+        wi_trkorr = ls_header-trkorr
+      EXCEPTIONS
+        OTHERS    = 1.
+    IF sy-subrc = 0.
+      COMMIT WORK AND WAIT.
+      lv_result = |ERROR:{ lv_message } (transport of copies { ls_header-trkorr } deleted)|.
+    ELSE.
+      lv_result = |ERROR:{ lv_message } (transport of copies { ls_header-trkorr } was created but could not be deleted)|.
+    ENDIF.
+  ENDIF.
+ENDIF.
+`, strings.Join(quoted, " "), abapQuote(description), abapQuote(target), transportErrorABAP, transportErrorABAP)
+}
@@ -0,0 +1,187 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseTransportObject(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"/sap/bc/adt/oo/classes/zcl_order", "R3TR CLAS ZCL_ORDER"},
+		{"/sap/bc/adt/oo/classes/zcl_order/source/main", "R3TR CLAS ZCL_ORDER"},
+		{"/sap/bc/adt/functions/groups/zfg/fmodules/z_func", "R3TR FUGR ZFG"},
+		{"/sap/bc/adt/ddic/ddl/sources/%2fdmo%2fi_travel", "R3TR DDLS /DMO/I_TRAVEL"},
+		{"clas zcl_order", "R3TR CLAS ZCL_ORDER"},
+		{"R3TR TABU ZCONFIG", "R3TR TABU ZCONFIG"},
+		{"LIMU METH ZCL_ORDER CALC", "LIMU METH ZCL_ORDER                     CALC"},
+	}
+	for _, tt := range tests {
+		obj, err := ParseTransportObject(tt.in)
+		if err != nil {
+			t.Errorf("ParseTransportObject(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got := obj.PgmID + " " + obj.Type + " " + obj.Name; got != tt.want {
+			t.Errorf("ParseTransportObject(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"ZCL_ORDER", "XXXX CLAS ZCL_ORDER", "/sap/bc/adt/unknown/zx", "CLAS ZCL'ORDER X Y"} {
+		if _, err := ParseTransportObject(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+
+	objects, err := ParseTransportObjects([]string{"clas zcl_order", "/sap/bc/adt/oo/classes/ZCL_ORDER", "", "PROG ZREPORT"})
+	if err != nil || len(objects) != 2 {
+		t.Errorf("ParseTransportObjects = %v, %v", objects, err)
+	}
+}
+
+func TestTransportObjectsSafety(t *testing.T) {
+	ctx := context.Background()
+	objects := []TransportObjectV2{{PgmID: "R3TR", Type: "CLAS", Name: "ZCL_ORDER"}}
+
+	client := NewClient("http://localhost:1", "u", "p")
+	if _, err := client.AddTransportObjects(ctx, "DEVK900001", objects); err == nil || !strings.Contains(err.Error(), "transports not enabled") {
+		t.Errorf("expected transports not enabled error, got %v", err)
+	}
+
+	client = NewClient("http://localhost:1", "u", "p", WithEnableTransports(), WithAllowedTransports("DEVK9*"))
+	if _, err := client.RemoveTransportObjects(ctx, "QASK900001", objects); err == nil || !strings.Contains(err.Error(), "blocked by safety configuration") {
+		t.Errorf("expected whitelist error, got %v", err)
+	}
+	if _, err := client.AddTransportObjects(ctx, "DEVK9'0001", objects); err == nil || !strings.Contains(err.Error(), "invalid transport number") {
+		t.Errorf("expected invalid number error, got %v", err)
+	}
+	if _, err := client.MergeTransportTasks(ctx, "DEVK900002", "DEVK900002"); err == nil {
+		t.Error("expected error merging a task into itself")
+	}
+
+	client = NewClient("http://localhost:1", "u", "p", WithEnableTransports(), WithTransportReadOnly())
+	if _, err := client.CreateTransportOfCopies(ctx, "DEVK900001", TransportOfCopiesOptions{}); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("expected read-only error, got %v", err)
+	}
+}
+
+func TestCreateTransportOfCopies(t *testing.T) {
+	var source string
+	var released, deleted bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+		case r.URL.Path == "/sap/bc/adt/cts/transportrequests/DEVK900001":
+			fmt.Fprint(w, testPreReleaseTransport)
+		case r.URL.Path == "/sap/bc/adt/cts/transportrequests/DEVK900100/newreleasejobs":
+			released = true
+		case r.URL.Path == "/sap/bc/adt/repository/nodestructure":
+			// $TMP exists
+		case r.URL.Path == "/sap/bc/adt/programs/programs":
+			w.WriteHeader(http.StatusCreated)
+		case strings.HasPrefix(r.URL.Path, "/sap/bc/adt/programs/programs/ZTEMP_CTS_"):
+			switch {
+			case r.URL.Query().Get("_action") == "LOCK":
+				fmt.Fprint(w, `<asx:abap xmlns:asx="http://www.sap.com/abapxml"><asx:values><DATA><LOCK_HANDLE>LH1</LOCK_HANDLE></DATA></asx:values></asx:abap>`)
+			case r.Method == http.MethodPut:
+				source = string(body)
+			case r.Method == http.MethodDelete:
+				deleted = true
+			}
+		case r.URL.Path == "/sap/bc/adt/activation":
+			fmt.Fprint(w, `<chkl:messages xmlns:chkl="http://www.sap.com/abapxml/checklist"/>`)
+		case r.URL.Path == "/sap/bc/adt/abapunit/testruns":
+			fmt.Fprint(w, `<aunit:runResult xmlns:aunit="http://www.sap.com/adt/aunit" xmlns:adtcore="http://www.sap.com/adt/core">
+  <program adtcore:uri="/sap/bc/adt/programs/programs/ztemp" adtcore:type="PROG/P" adtcore:name="ZTEMP">
+    <testClasses><testClass adtcore:uri="/sap/bc/adt/programs/programs/ztemp" adtcore:type="PROG/OL" adtcore:name="LTC_EXECUTOR">
+      <testMethods><testMethod adtcore:uri="/sap/bc/adt/programs/programs/ztemp" adtcore:type="PROG/OLD" adtcore:name="EXECUTE_PAYLOAD" executionTime="0.010"><alerts><alert kind="failedAssertion" severity="critical"><title>EXEC_RESULT:OK:DEVK900100 3</title></alert></alerts></testMethod></testMethods>
+    </testClass></testClasses>
+  </program>
+</aunit:runResult>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"), WithEnableTransports())
+	result, err := client.CreateTransportOfCopies(context.Background(), "devk900001", TransportOfCopiesOptions{Release: true})
+	if err != nil {
+		t.Fatalf("CreateTransportOfCopies failed: %v", err)
+	}
+	if result.Transport != "DEVK900100" || result.Count != 3 || !result.Released || !released {
+		t.Errorf("unexpected result: %+v (released %v)", result, released)
+	}
+	if strings.Join(result.Sources, ",") != "DEVK900001,DEVK900002" || len(result.Objects) != 4 {
+		t.Errorf("sources = %v, objects = %v", result.Sources, result.Objects)
+	}
+	for _, want := range []string{
+		"lt_from = VALUE #( ( 'DEVK900001' ) ( 'DEVK900002' ) ).",
+		"iv_type           = 'T'",
+		"iv_text           = 'ToC of DEVK900001: Orders'",
+		"iv_target         = 'QAS'",
+		"CALL FUNCTION 'TR_COPY_COMM'",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("generated program lacks %q", want)
+		}
+	}
+	if !deleted {
+		t.Error("temporary program was not deleted")
+	}
+}
+
+func TestTransportObjectsABAP(t *testing.T) {
+	objects := []TransportObjectV2{{PgmID: "R3TR", Type: "CLAS", Name: "ZCL_ORDER"}, {PgmID: "R3TR", Type: "TABU", Name: "ZCONFIG"}}
+
+	add := addTransportObjectsABAP("DEVK900002", objects)
+	for _, want := range []string{
+		"APPEND VALUE #( pgmid = 'R3TR' object = 'TABU' obj_name = 'ZCONFIG' objfunc = space ) TO lt_e071.",
+		"DATA(lv_trkorr) = CONV trkorr( 'DEVK900002' ).",
+		"wi_trkorr = lv_trkorr",
+		"lv_status <> 'D' AND lv_status <> 'L'",
+		"CALL FUNCTION 'DEQUEUE_E_TRKORR'",
+	} {
+		if !strings.Contains(add, want) {
+			t.Errorf("add program lacks %q", want)
+		}
+	}
+
+	remove := removeTransportObjectsABAP("DEVK900002", objects)
+	if !strings.Contains(remove, "SELECT * FROM e071 WHERE trkorr = @lv_trkorr AND pgmid = 'R3TR' AND object = 'CLAS' AND obj_name = 'ZCL_ORDER' APPENDING TABLE @lt_e071.") ||
+		!strings.Contains(remove, "CALL FUNCTION 'TRINT_DELETE_COMM_OBJECT_KEYS'") ||
+		!strings.Contains(remove, "lv_status <> 'D' AND lv_status <> 'L'") {
+		t.Errorf("unexpected remove program:\n%s", remove)
+	}
+
+	// The CTS tables are only changed through the CTS function modules
+	merge := mergeTransportTasksABAP("DEVK900002", "DEVK900003")
+	toc := transportOfCopiesABAP([]string{"DEVK900001"}, "ToC", "QAS")
+	for _, program := range []string{add, remove, merge, toc} {
+		if strings.Contains(program, "DELETE FROM") {
+			t.Errorf("program writes the CTS tables directly:\n%s", program)
+		}
+	}
+	if !strings.Contains(merge, "CALL FUNCTION 'TRINT_DELETE_COMM_OBJECT_KEYS'") {
+		t.Errorf("unexpected merge program:\n%s", merge)
+	}
+	if !strings.Contains(toc, "CALL FUNCTION 'TR_DELETE_COMM'") || !strings.Contains(toc, "ROLLBACK WORK.") {
+		t.Errorf("failed copy does not remove the transport of copies:\n%s", toc)
+	}
+
+	if _, err := parseTransportABAPResult("AddTransportObjects", "ERROR:Request DEVK900002 is released"); err == nil || err.Error() != "AddTransportObjects: Request DEVK900002 is released" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if out, err := parseTransportABAPResult("AddTransportObjects", "OK:2"); err != nil || out != "2" {
+		t.Errorf("unexpected result: %q, %v", out, err)
+	}
+}
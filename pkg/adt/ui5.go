@@ -40,6 +40,7 @@ type UI5File struct {
 	Type        string `json:"type"` // "file" or "folder"
 	Size        int64  `json:"size,omitempty"`
 	ContentType string `json:"contentType,omitempty"`
+	ETag        string `json:"etag,omitempty"` // Version of the content (ETag, else last change)
 }
 
 // UI5AtomFeed wraps UI5 app search results in Atom format.
@@ -57,6 +58,11 @@ type UI5AtomEntry struct {
 	Category struct {
 		Term string `xml:"term,attr"`
 	} `xml:"category"`
+	Updated string `xml:"updated"`
+	Links   []struct {
+		Rel  string `xml:"rel,attr"`
+		ETag string `xml:"etag,attr"`
+	} `xml:"link"`
 }
 
 // UI5Folder represents a folder in UI5 app structure.
@@ -174,14 +180,26 @@ func extractFilesFromAtomFeed(feed *UI5AtomFeed, appName string) []UI5File {
 		}
 
 		fileType := "file"
+		etag := ""
 		if entry.Category.Term == "folder" {
 			fileType = "folder"
+		} else {
+			for _, link := range entry.Links {
+				if link.ETag != "" {
+					etag = link.ETag
+					break
+				}
+			}
+			if etag == "" {
+				etag = entry.Updated
+			}
 		}
 
 		files = append(files, UI5File{
 			Name: entry.Title,
 			Path: path,
 			Type: fileType,
+			ETag: etag,
 		})
 	}
 
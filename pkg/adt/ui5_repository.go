@@ -0,0 +1,300 @@
+package adt
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UI5 deployment backends.
+const (
+	UI5BackendOData     = "odata"     // /UI5/ABAP_REPOSITORY_SRV, zipped archive (default)
+	UI5BackendFilestore = "filestore" // ADT filestore, file by file; read-only on many releases
+)
+
+const (
+	ui5RepositoryService = "/sap/opu/odata/UI5/ABAP_REPOSITORY_SRV"
+	ui5AppIndexInfo      = "/sap/bc/ui2/app_index/ui5_app_info_json"
+)
+
+// ui5AppIndexRetries is how often the app index is polled after a deployment;
+// the repository service recalculates it asynchronously on some releases.
+var ui5AppIndexRetries = []time.Duration{0, time.Second, 3 * time.Second}
+
+// ParseUI5Backend validates a UI5 deployment backend name. An empty name
+// selects the default (odata): the ADT filestore rejects writes (HTTP 405)
+// on many releases.
+func ParseUI5Backend(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", UI5BackendOData, "repository", "abap_repository_srv":
+		return UI5BackendOData, nil
+	case UI5BackendFilestore, "adt":
+		return UI5BackendFilestore, nil
+	default:
+		return "", fmt.Errorf("unknown UI5 backend %q (use %s or %s)", name, UI5BackendFilestore, UI5BackendOData)
+	}
+}
+
+// ui5Backend returns the backend for a push: the option, else the client
+// configuration (system profile), else odata.
+func (c *Client) ui5Backend(opts UI5SyncOptions) (string, error) {
+	if opts.Backend != "" {
+		return ParseUI5Backend(opts.Backend)
+	}
+	return ParseUI5Backend(c.config.UI5Backend)
+}
+
+// ui5DeployRepository uploads the files as one ZIP archive through the UI5
+// ABAP repository OData service, the way `fiori deploy` does. The archive
+// replaces the whole app, so KeepRemoved is not supported. The package of an
+// existing app is checked against the safety configuration. Dry runs send
+// the archive in test mode: the server runs its checks without saving.
+func (c *Client) ui5DeployRepository(ctx context.Context, local map[string][]byte, opts UI5SyncOptions, result *UI5SyncResult) (*UI5SyncResult, error) {
+	if opts.KeepRemoved {
+		return nil, fmt.Errorf("keeping removed files is not supported by the %s backend (the archive replaces the app)", UI5BackendOData)
+	}
+	if len(local) == 0 {
+		return nil, fmt.Errorf("nothing to deploy: no files found")
+	}
+	result.Backend = UI5BackendOData
+
+	archive, paths, err := ui5ZipArchive(local)
+	if err != nil {
+		return nil, err
+	}
+	result.Archived = len(paths)
+
+	// Existence check; also fetches a CSRF token from the OData service
+	entityPath := fmt.Sprintf("%s/Repositories('%s')", ui5RepositoryService, url.PathEscape(result.App))
+	resp, err := c.transport.Request(ctx, entityPath, &RequestOptions{
+		Method:  http.MethodGet,
+		Query:   url.Values{"$format": []string{"json"}},
+		Headers: map[string]string{"X-CSRF-Token": "Fetch"},
+		Accept:  "application/json",
+	})
+	switch {
+	case err == nil:
+		var info struct {
+			D struct {
+				Package string `json:"Package"`
+			} `json:"d"`
+		}
+		pkg := ""
+		if json.Unmarshal(resp.Body, &info) == nil {
+			pkg = strings.ToUpper(info.D.Package)
+		}
+		if pkg == "" {
+			if pkg, err = c.ui5AppPackage(ctx, result.App); err != nil {
+				return nil, err
+			}
+		}
+		if err := c.checkPackageSafety(pkg); err != nil {
+			return nil, err
+		}
+		opts.Package = pkg
+		result.Package = pkg
+	case IsNotFoundError(err):
+		if opts.Package == "" {
+			return nil, fmt.Errorf("app %s does not exist; a package is required to create it", result.App)
+		}
+		if err := c.checkPackageSafety(opts.Package); err != nil {
+			return nil, err
+		}
+		result.AppCreated = true
+	default:
+		return nil, ui5RepositoryError(err)
+	}
+
+	query := url.Values{}
+	query.Set("CodePage", "'UTF8'")
+	query.Set("CondenseMessagesInHttpResponseHeader", "X")
+	query.Set("format", "json")
+	if opts.Transport != "" {
+		query.Set("TransportRequest", opts.Transport)
+	}
+	if opts.UnsafeMode {
+		query.Set("SafeMode", "false")
+	}
+	if opts.DryRun {
+		query.Set("TestMode", "true")
+	}
+
+	method, target := http.MethodPut, entityPath
+	if result.AppCreated {
+		method, target = http.MethodPost, ui5RepositoryService+"/Repositories"
+	}
+	resp, err = c.transport.Request(ctx, target, &RequestOptions{
+		Method:      method,
+		Query:       query,
+		Body:        []byte(ui5RepositoryEntry(c.config.BaseURL, result.App, opts.Package, opts.Description, archive)),
+		ContentType: "application/atom+xml; type=entry; charset=UTF-8",
+		Accept:      "application/json,application/xml,text/plain,*/*",
+	})
+	if err != nil {
+		return result, ui5RepositoryError(err)
+	}
+	result.Messages = ui5RepositoryMessages(resp.Headers.Get("sap-message"))
+
+	if !opts.DryRun && result.AppID != "" {
+		result.AppIndex = c.ui5CheckAppIndex(ctx, result.AppID)
+		if result.AppIndex == "stale" {
+			result.Messages = append(result.Messages, fmt.Sprintf("warning: app index has no entry for %s yet; run report /UI5/APP_INDEX_CALCULATE if the app does not show up in the launchpad", result.AppID))
+		}
+	}
+	return result, nil
+}
+
+// ui5CheckAppIndex reports whether the app index knows the app ID after a
+// deployment: "current", "stale" or "unknown" (app index not reachable).
+// It only polls; the recalculation is left to the repository service or to
+// report /UI5/APP_INDEX_CALCULATE.
+func (c *Client) ui5CheckAppIndex(ctx context.Context, appID string) string {
+	for _, wait := range ui5AppIndexRetries {
+		if wait > 0 {
+			select {
+			case <-ctx.Done():
+				return "unknown"
+			case <-time.After(wait):
+			}
+		}
+		resp, err := c.transport.Request(ctx, ui5AppIndexInfo, &RequestOptions{
+			Method: http.MethodGet,
+			Query:  url.Values{"id": []string{appID}},
+			Accept: "application/json",
+		})
+		if err != nil {
+			if IsNotFoundError(err) {
+				continue
+			}
+			return "unknown"
+		}
+		var info map[string]json.RawMessage
+		if json.Unmarshal(resp.Body, &info) != nil {
+			return "unknown"
+		}
+		if _, ok := info[appID]; ok {
+			return "current"
+		}
+	}
+	return "stale"
+}
+
+// ui5ZipArchive packs the files into a ZIP archive with deterministic
+// ordering and returns the archived paths.
+func ui5ZipArchive(files map[string][]byte) ([]byte, []string, error) {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, p := range paths {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: strings.TrimPrefix(p, "/"), Method: zip.Deflate})
+		if err != nil {
+			return nil, nil, fmt.Errorf("zipping %s: %w", p, err)
+		}
+		if _, err := w.Write(files[p]); err != nil {
+			return nil, nil, fmt.Errorf("zipping %s: %w", p, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, nil, fmt.Errorf("zipping archive: %w", err)
+	}
+	return buf.Bytes(), paths, nil
+}
+
+// ui5RepositoryEntry builds the Atom entry of a Repository with the archive.
+func ui5RepositoryEntry(baseURL, appName, pkg, description string, archive []byte) string {
+	base := strings.TrimSuffix(baseURL, "/") + ui5RepositoryService + "/"
+	name := xmlEscape(appName)
+	return `<?xml version="1.0" encoding="utf-8"?>
+<entry xmlns="http://www.w3.org/2005/Atom" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata" xmlns:d="http://schemas.microsoft.com/ado/2007/08/dataservices" xml:base="` + escapeXMLAttr(base) + `">
+  <id>` + xmlEscape(base) + `Repositories('` + name + `')</id>
+  <title type="text">Repositories('` + name + `')</title>
+  <updated>` + time.Now().UTC().Format(time.RFC3339) + `</updated>
+  <category term="/UI5/ABAP_REPOSITORY_SRV.Repository" scheme="http://schemas.microsoft.com/ado/2007/08/dataservices/scheme"/>
+  <link href="Repositories('` + name + `')" rel="edit" title="Repository"/>
+  <content type="application/xml">
+    <m:properties>
+      <d:Name>` + name + `</d:Name>
+      <d:Package>` + xmlEscape(strings.ToUpper(pkg)) + `</d:Package>
+      <d:Description>` + xmlEscape(description) + `</d:Description>
+      <d:ZipArchive>` + base64.StdEncoding.EncodeToString(archive) + `</d:ZipArchive>
+      <d:Info/>
+    </m:properties>
+  </content>
+</entry>`
+}
+
+// ui5RepositoryMessage is the condensed message format of the repository
+// service (sap-message header and error details).
+type ui5RepositoryMessage struct {
+	Code     string                 `json:"code"`
+	Message  string                 `json:"message"`
+	Severity string                 `json:"severity"`
+	Details  []ui5RepositoryMessage `json:"details"`
+}
+
+// ui5RepositoryMessages flattens the sap-message header into lines.
+func ui5RepositoryMessages(header string) []string {
+	if header == "" {
+		return nil
+	}
+	var msg ui5RepositoryMessage
+	if err := json.Unmarshal([]byte(header), &msg); err != nil {
+		return []string{header}
+	}
+	var lines []string
+	for _, m := range append([]ui5RepositoryMessage{msg}, msg.Details...) {
+		if m.Message == "" {
+			continue
+		}
+		if m.Severity != "" {
+			lines = append(lines, m.Severity+": "+m.Message)
+		} else {
+			lines = append(lines, m.Message)
+		}
+	}
+	return lines
+}
+
+// ui5RepositoryError turns an OData error body into a readable error that
+// includes the error details (failed safe-mode checks, missing transport).
+func ui5RepositoryError(err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	var body struct {
+		Error struct {
+			Message struct {
+				Value string `json:"value"`
+			} `json:"message"`
+			InnerError struct {
+				ErrorDetails []ui5RepositoryMessage `json:"errordetails"`
+			} `json:"innererror"`
+		} `json:"error"`
+	}
+	if json.Unmarshal([]byte(apiErr.Message), &body) != nil || body.Error.Message.Value == "" {
+		return err
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "UI5 repository: %s (HTTP %d)", body.Error.Message.Value, apiErr.StatusCode)
+	for _, d := range body.Error.InnerError.ErrorDetails {
+		if d.Message != "" && d.Message != body.Error.Message.Value {
+			sb.WriteString("\n  " + d.Message)
+		}
+	}
+	return errors.New(sb.String())
+}
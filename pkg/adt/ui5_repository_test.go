@@ -0,0 +1,147 @@
+package adt
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUI5DeployRepository(t *testing.T) {
+	defer func(retries []time.Duration) { ui5AppIndexRetries = retries }(ui5AppIndexRetries)
+	ui5AppIndexRetries = []time.Duration{0}
+
+	exists := false
+	var deployed []string
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		repository := ui5RepositoryService + "/Repositories('ZAPP')"
+		switch {
+		case r.URL.Path == "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "adt-token")
+		case r.URL.Path == repository && r.Method == http.MethodGet:
+			if r.Header.Get("X-CSRF-Token") == "Fetch" {
+				w.Header().Set("X-CSRF-Token", "odata-token")
+			}
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, `{"d":{"Name":"ZAPP","Package":"$ZPKG"}}`)
+		case r.URL.Path == ui5RepositoryService+"/Repositories" && r.Method == http.MethodPost,
+			r.URL.Path == repository && r.Method == http.MethodPut:
+			q := r.URL.Query()
+			requests = append(requests, fmt.Sprintf("%s %s test=%s safe=%s csrf=%s", r.Method, q.Get("TransportRequest"), q.Get("TestMode"), q.Get("SafeMode"), r.Header.Get("X-CSRF-Token")))
+			if r.Method == http.MethodPut && q.Get("SafeMode") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":{"code":"/UI5/UI5_REP_LOAD/043","message":{"value":"Safe mode check failed"},"innererror":{"errordetails":[{"message":"App was changed outside of the repository","severity":"error"}]}}}`)
+				return
+			}
+			var entry struct {
+				Name       string `xml:"content>properties>Name"`
+				Package    string `xml:"content>properties>Package"`
+				ZipArchive string `xml:"content>properties>ZipArchive"`
+			}
+			if err := xml.Unmarshal(body, &entry); err != nil || entry.Name != "ZAPP" || entry.Package != "$ZPKG" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			archive, _ := base64.StdEncoding.DecodeString(entry.ZipArchive)
+			zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			deployed = nil
+			for _, f := range zr.File {
+				deployed = append(deployed, f.Name)
+			}
+			sort.Strings(deployed)
+			w.Header().Set("sap-message", `{"code":"/UI5/UI5_REP_LOAD/000","message":"Upload successful","severity":"info","details":[{"code":"X","message":"App index calculated","severity":"info"}]}`)
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Path == ui5AppIndexInfo:
+			fmt.Fprintf(w, `{%q:{"url":"/sap/bc/ui5_ui5/sap/zapp"}}`, r.URL.Query().Get("id"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	write := func(name, content string) {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("manifest.json", `{"sap.app": {"id": "com.acme.app"}}`)
+	write("index.html", "<html/>")
+	write("i18n/i18n.properties", "title=App")
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"), WithAllowTransportableEdits(), WithUI5Backend(UI5BackendOData))
+	ctx := context.Background()
+
+	result, err := client.UI5Push(ctx, dir, "zapp", UI5SyncOptions{Package: "$zpkg", Transport: "DEVK900001", DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if !result.AppCreated || result.Archived != 3 || result.AppIndex != "" {
+		t.Errorf("unexpected dry run result: %+v", result)
+	}
+
+	result, err = client.UI5Push(ctx, dir, "zapp", UI5SyncOptions{Package: "$zpkg", Transport: "DEVK900001"})
+	if err != nil {
+		t.Fatalf("UI5Push failed: %v", err)
+	}
+	if result.Summary() != "ZAPP: 3 files deployed via ABAP repository service, app index current (app created)" {
+		t.Errorf("summary = %q", result.Summary())
+	}
+	if strings.Join(deployed, ",") != "i18n/i18n.properties,index.html,manifest.json" {
+		t.Errorf("archive = %v", deployed)
+	}
+	if len(result.Messages) != 2 || result.Messages[1] != "info: App index calculated" {
+		t.Errorf("messages = %v", result.Messages)
+	}
+
+	exists = true
+	if _, err := client.UI5Push(ctx, dir, "zapp", UI5SyncOptions{Transport: "DEVK900001"}); err == nil || !strings.Contains(err.Error(), "App was changed outside of the repository") {
+		t.Errorf("expected safe mode error, got %v", err)
+	}
+	if _, err := client.UI5Push(ctx, dir, "zapp", UI5SyncOptions{Transport: "DEVK900001", UnsafeMode: true}); err != nil {
+		t.Errorf("unsafe update failed: %v", err)
+	}
+
+	want := []string{
+		"POST DEVK900001 test=true safe= csrf=odata-token",
+		"POST DEVK900001 test= safe= csrf=odata-token",
+		"PUT DEVK900001 test= safe= csrf=odata-token",
+		"PUT DEVK900001 test= safe=false csrf=odata-token",
+	}
+	if strings.Join(requests, "|") != strings.Join(want, "|") {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+
+	restricted := NewClient(server.URL, "testuser", "testpass", WithClient("001"), WithAllowTransportableEdits(), WithAllowedPackages("$TMP"))
+	if _, err := restricted.UI5Push(ctx, dir, "zapp", UI5SyncOptions{Package: "$TMP", Backend: UI5BackendOData}); err == nil || !strings.Contains(err.Error(), "$ZPKG") {
+		t.Errorf("expected package safety error for the existing app, got %v", err)
+	}
+	if _, err := client.UI5Push(ctx, dir, "zapp", UI5SyncOptions{Backend: "ftp"}); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+	if backend, err := ParseUI5Backend(""); err != nil || backend != UI5BackendOData {
+		t.Errorf("default backend = %q, %v; want %s", backend, err, UI5BackendOData)
+	}
+}
@@ -0,0 +1,683 @@
+package adt
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UI5ProjectConfig is the deployment configuration of a local UI5 project,
+// read from ui5-deploy.yaml / ui5.yaml and manifest.json.
+type UI5ProjectConfig struct {
+	AppName     string `json:"appName,omitempty"`     // BSP application name
+	AppID       string `json:"appId,omitempty"`       // sap.app.id from manifest.json
+	Description string `json:"description,omitempty"` // BSP description
+	Package     string `json:"package,omitempty"`
+	Transport   string `json:"transport,omitempty"`
+}
+
+// UI5SyncOptions configures UI5Push and UI5Pull.
+type UI5SyncOptions struct {
+	Package     string // Package for a new app (default from ui5.yaml)
+	Transport   string // Transport request (default from ui5.yaml)
+	Description string // Description for a new app
+	Source      string // Folder to push, relative to the project (default: dist if built, else the project folder)
+	KeepRemoved bool   // Push: do not delete remote files that are missing locally
+	DryRun      bool   // Only compute the changes
+	Backend     string // Push: "odata" (default, UI5 ABAP repository service) or "filestore"
+	UnsafeMode  bool   // Push via odata: disable the repository's safe-mode check
+}
+
+// UI5SyncResult reports the changes of a push or pull.
+type UI5SyncResult struct {
+	App        string   `json:"app"`
+	AppID      string   `json:"appId,omitempty"`
+	Package    string   `json:"package,omitempty"`
+	Transport  string   `json:"transport,omitempty"`
+	Created    []string `json:"created,omitempty"` // New files
+	Updated    []string `json:"updated,omitempty"` // Changed files
+	Deleted    []string `json:"deleted,omitempty"` // Removed files and folders
+	Unchanged  int      `json:"unchanged"`
+	AppCreated bool     `json:"appCreated,omitempty"`
+	DryRun     bool     `json:"dryRun,omitempty"`
+	Backend    string   `json:"backend,omitempty"`
+	Archived   int      `json:"archived,omitempty"` // Files in the uploaded archive (odata)
+	AppIndex   string   `json:"appIndex,omitempty"` // current, stale or unknown (odata)
+	Messages   []string `json:"messages,omitempty"` // Messages of the repository service
+}
+
+// Summary returns a one-line description of the result.
+func (r *UI5SyncResult) Summary() string {
+	s := fmt.Sprintf("%s: %d created, %d updated, %d deleted, %d unchanged", r.App, len(r.Created), len(r.Updated), len(r.Deleted), r.Unchanged)
+	if r.Backend == UI5BackendOData {
+		s = fmt.Sprintf("%s: %d files deployed via ABAP repository service", r.App, r.Archived)
+		if r.AppIndex != "" {
+			s += ", app index " + r.AppIndex
+		}
+	}
+	if r.AppCreated {
+		s += " (app created)"
+	}
+	if r.DryRun {
+		s += " (dry run)"
+	}
+	return s
+}
+
+// ReadUI5ProjectConfig reads the app name, package and transport from the
+// deploy-to-abap task of ui5-deploy.yaml (or ui5.yaml) and the app ID from
+// manifest.json. Missing files are not an error.
+func ReadUI5ProjectConfig(dir string) (*UI5ProjectConfig, error) {
+	cfg := &UI5ProjectConfig{}
+	for _, name := range []string{"ui5-deploy.yaml", "ui5.yaml"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		var doc struct {
+			Builder struct {
+				CustomTasks []struct {
+					Name          string `yaml:"name"`
+					Configuration struct {
+						App struct {
+							Name        string `yaml:"name"`
+							Description string `yaml:"description"`
+							Package     string `yaml:"package"`
+							Transport   string `yaml:"transport"`
+						} `yaml:"app"`
+					} `yaml:"configuration"`
+				} `yaml:"customTasks"`
+			} `yaml:"builder"`
+		}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", name, err)
+		}
+		for _, task := range doc.Builder.CustomTasks {
+			if task.Name != "deploy-to-abap" {
+				continue
+			}
+			app := task.Configuration.App
+			cfg.AppName = strings.ToUpper(app.Name)
+			cfg.Description = app.Description
+			cfg.Package = strings.ToUpper(app.Package)
+			cfg.Transport = strings.ToUpper(app.Transport)
+		}
+		if cfg.AppName != "" {
+			break
+		}
+	}
+
+	for _, name := range []string{filepath.Join("webapp", "manifest.json"), "manifest.json", filepath.Join("dist", "manifest.json")} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		var manifest struct {
+			App struct {
+				ID    string `json:"id"`
+				Title string `json:"title"`
+			} `json:"sap.app"`
+		}
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", name, err)
+		}
+		cfg.AppID = manifest.App.ID
+		if cfg.Description == "" && !strings.HasPrefix(manifest.App.Title, "{{") {
+			cfg.Description = manifest.App.Title
+		}
+		break
+	}
+	return cfg, nil
+}
+
+// UI5ListFiles lists all files and folders of a UI5 app recursively.
+func (c *Client) UI5ListFiles(ctx context.Context, appName string) ([]UI5File, error) {
+	if err := c.checkSafety(OpRead, "UI5ListFiles"); err != nil {
+		return nil, err
+	}
+	appName = strings.ToUpper(appName)
+
+	var files []UI5File
+	visited := make(map[string]bool)
+	var walk func(folder string) error
+	walk = func(folder string) error {
+		visited[folder] = true
+		contentPath := fmt.Sprintf("%s/%s/content", ui5FilestoreBase, url.PathEscape(appName+folder))
+		resp, err := c.transport.Request(ctx, contentPath, &RequestOptions{
+			Method: http.MethodGet,
+			Accept: "application/atom+xml",
+		})
+		if err != nil {
+			return fmt.Errorf("listing %s%s: %w", appName, folder, err)
+		}
+		var feed UI5AtomFeed
+		if err := xml.Unmarshal(resp.Body, &feed); err != nil {
+			return fmt.Errorf("parsing content of %s%s: %w", appName, folder, err)
+		}
+		for _, f := range extractFilesFromAtomFeed(&feed, appName) {
+			files = append(files, f)
+			if f.Type == "folder" && !visited[f.Path] {
+				if err := walk(f.Path); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(""); err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// UI5Pull downloads all files of a UI5 app into dir. Files whose content is
+// unchanged are not rewritten; files recorded in the sync state of dir with
+// the current remote version are not even downloaded.
+func (c *Client) UI5Pull(ctx context.Context, appName, dir string, opts UI5SyncOptions) (*UI5SyncResult, error) {
+	if err := c.checkSafety(OpRead, "UI5Pull"); err != nil {
+		return nil, err
+	}
+	appName = strings.ToUpper(appName)
+	files, err := c.UI5ListFiles(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UI5SyncResult{App: appName, DryRun: opts.DryRun}
+	state := readUI5SyncState(dir, appName)
+	pulled := newUI5SyncState(appName)
+	for _, f := range files {
+		rel := filepath.FromSlash(strings.TrimPrefix(f.Path, "/"))
+		if !filepath.IsLocal(rel) {
+			return nil, fmt.Errorf("invalid file path %q in UI5 app %s", f.Path, appName)
+		}
+		local := filepath.Join(dir, rel)
+		if f.Type == "folder" {
+			if !opts.DryRun {
+				if err := os.MkdirAll(local, 0755); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		existing, readErr := os.ReadFile(local)
+		if readErr == nil && state.unchanged(f, ui5ContentHash(existing)) {
+			pulled.record(f, existing)
+			result.Unchanged++
+			continue
+		}
+		content, err := c.UI5GetFileContent(ctx, appName, f.Path)
+		if err != nil {
+			return nil, err
+		}
+		pulled.record(f, content)
+		switch {
+		case readErr != nil:
+			result.Created = append(result.Created, f.Path)
+		case ui5ContentHash(existing) != ui5ContentHash(content):
+			result.Updated = append(result.Updated, f.Path)
+		default:
+			result.Unchanged++
+			continue
+		}
+		if opts.DryRun {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(local), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(local, content, 0644); err != nil {
+			return nil, err
+		}
+	}
+	if !opts.DryRun {
+		if err := pulled.write(dir); err != nil {
+			result.Messages = append(result.Messages, fmt.Sprintf("warning: %v", err))
+		}
+	}
+	return result, nil
+}
+
+// UI5Push uploads a local folder to a UI5 app. By default the folder is
+// deployed as one archive through the UI5 ABAP repository service. With the
+// filestore backend files whose content hash differs from the remote file
+// are updated, new files and folders are created and remote files missing
+// locally are deleted. The package of an existing app is looked up and
+// checked against the safety configuration on every push. The remote
+// content hashes come from the sync state
+// that pull and push record in dir for every file version, so no file is
+// downloaded; files without a recorded version are uploaded. The app is created if it does not exist. App name,
+// package and transport default to the deploy configuration in
+// ui5-deploy.yaml / ui5.yaml.
+func (c *Client) UI5Push(ctx context.Context, dir, appName string, opts UI5SyncOptions) (*UI5SyncResult, error) {
+	if err := c.checkSafety(OpUpdate, "UI5Push"); err != nil {
+		return nil, err
+	}
+	cfg, err := ReadUI5ProjectConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+	if appName == "" {
+		appName = cfg.AppName
+	}
+	if appName == "" {
+		return nil, fmt.Errorf("app name is required (argument or app.name of the deploy-to-abap task in ui5-deploy.yaml)")
+	}
+	appName = strings.ToUpper(appName)
+	if opts.Package == "" {
+		opts.Package = cfg.Package
+	}
+	if opts.Transport == "" {
+		opts.Transport = cfg.Transport
+	}
+	if opts.Description == "" {
+		opts.Description = cfg.Description
+	}
+	if err := c.checkTransportableEdit(opts.Transport, "UI5Push"); err != nil {
+		return nil, err
+	}
+	backend, err := c.ui5Backend(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	root := dir
+	if opts.Source != "" {
+		root = filepath.Join(dir, opts.Source)
+	} else if cfg.AppName != "" || cfg.AppID != "" {
+		if info, err := os.Stat(filepath.Join(dir, "dist")); err == nil && info.IsDir() {
+			root = filepath.Join(dir, "dist")
+		}
+	}
+	local, err := ui5LocalFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UI5SyncResult{App: appName, AppID: cfg.AppID, Package: opts.Package, Transport: opts.Transport, DryRun: opts.DryRun}
+	if backend == UI5BackendOData {
+		return c.ui5DeployRepository(ctx, local, opts, result)
+	}
+
+	remote := make(map[string]UI5File)
+	files, err := c.UI5ListFiles(ctx, appName)
+	switch {
+	case err == nil:
+		for _, f := range files {
+			remote[f.Path] = f
+		}
+		pkg, err := c.ui5AppPackage(ctx, appName)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.checkPackageSafety(pkg); err != nil {
+			return nil, err
+		}
+		opts.Package = pkg
+		result.Package = pkg
+	case IsNotFoundError(err):
+		if opts.Package == "" {
+			return nil, fmt.Errorf("app %s does not exist; a package is required to create it", appName)
+		}
+		if err := c.checkPackageSafety(opts.Package); err != nil {
+			return nil, err
+		}
+		result.AppCreated = true
+		if !opts.DryRun {
+			if err := c.UI5CreateApp(ctx, appName, opts.Description, opts.Package, opts.Transport); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, err
+	}
+
+	state := readUI5SyncState(dir, appName)
+
+	// Folders first, parents before children
+	var paths []string
+	for p := range local {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		for _, folder := range ui5ParentFolders(p) {
+			if _, ok := remote[folder]; ok {
+				continue
+			}
+			remote[folder] = UI5File{Path: folder, Type: "folder"}
+			result.Created = append(result.Created, folder+"/")
+			if opts.DryRun {
+				continue
+			}
+			if err := c.ui5CreateEntry(ctx, appName, folder, nil, true, opts); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	for _, p := range paths {
+		content := local[p]
+		if f, ok := remote[p]; ok && f.Type != "folder" {
+			if state.unchanged(f, ui5ContentHash(content)) {
+				result.Unchanged++
+				continue
+			}
+			result.Updated = append(result.Updated, p)
+			if !opts.DryRun {
+				if err := c.ui5UpdateEntry(ctx, appName, p, content, opts.Transport); err != nil {
+					return result, err
+				}
+			}
+			continue
+		}
+		result.Created = append(result.Created, p)
+		if !opts.DryRun {
+			if err := c.ui5CreateEntry(ctx, appName, p, content, false, opts); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	if !opts.KeepRemoved {
+		if err := c.ui5DeleteRemoved(ctx, appName, paths, remote, opts, result); err != nil {
+			return result, err
+		}
+	}
+
+	// Record the new file versions for the next push
+	if !opts.DryRun {
+		if err := c.recordUI5SyncState(ctx, dir, appName, local); err != nil {
+			result.Messages = append(result.Messages, fmt.Sprintf("warning: %v", err))
+		}
+	}
+	return result, nil
+}
+
+// ui5DeleteRemoved deletes the remote files and folders that are not in
+// paths.
+func (c *Client) ui5DeleteRemoved(ctx context.Context, appName string, paths []string, remote map[string]UI5File, opts UI5SyncOptions, result *UI5SyncResult) error {
+	keep := make(map[string]bool)
+	for _, p := range paths {
+		keep[p] = true
+		for _, folder := range ui5ParentFolders(p) {
+			keep[folder] = true
+		}
+	}
+	var removed []string
+	for p := range remote {
+		if !keep[p] {
+			removed = append(removed, p)
+		}
+	}
+	// Children before their folders
+	sort.Sort(sort.Reverse(sort.StringSlice(removed)))
+	for _, p := range removed {
+		if ui5HasRemovedParent(p, removed) {
+			continue
+		}
+		result.Deleted = append(result.Deleted, p)
+		if opts.DryRun {
+			continue
+		}
+		if err := c.ui5DeleteEntry(ctx, appName, p, opts.Transport); err != nil {
+			return err
+		}
+	}
+	sort.Strings(result.Deleted)
+	return nil
+}
+
+// recordUI5SyncState lists the app after a push and records the versions of
+// the pushed files with the hashes of their content.
+func (c *Client) recordUI5SyncState(ctx context.Context, dir, appName string, local map[string][]byte) error {
+	files, err := c.UI5ListFiles(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("recording sync state: %w", err)
+	}
+	state := newUI5SyncState(appName)
+	for _, f := range files {
+		if content, ok := local[f.Path]; ok {
+			state.record(f, content)
+		}
+	}
+	return state.write(dir)
+}
+
+// ui5AppPackage looks up the package of an existing BSP application.
+func (c *Client) ui5AppPackage(ctx context.Context, appName string) (string, error) {
+	results, err := c.SearchObject(ctx, appName, 20)
+	if err != nil {
+		return "", fmt.Errorf("looking up the package of UI5 app %s: %w", appName, err)
+	}
+	for _, r := range results {
+		if strings.EqualFold(r.Name, appName) && strings.HasPrefix(r.Type, "WAPA") && r.PackageName != "" {
+			return strings.ToUpper(r.PackageName), nil
+		}
+	}
+	return "", fmt.Errorf("package of UI5 app %s not found", appName)
+}
+
+// ui5CreateEntry creates a file or folder in its parent folder.
+func (c *Client) ui5CreateEntry(ctx context.Context, appName, filePath string, content []byte, folder bool, opts UI5SyncOptions) error {
+	parent, name := path.Split(filePath)
+	parent = strings.TrimSuffix(parent, "/")
+	params := url.Values{}
+	params.Set("name", name)
+	params.Set("devclass", opts.Package)
+	if opts.Transport != "" {
+		params.Set("corrNr", opts.Transport)
+	}
+	reqOpts := &RequestOptions{Method: http.MethodPost, Query: params}
+	if folder {
+		params.Set("type", "folder")
+		params.Set("isBinary", "false")
+		reqOpts.ContentType = "application/atom+xml"
+	} else {
+		binary := ui5IsBinary(content)
+		params.Set("type", "file")
+		params.Set("isBinary", fmt.Sprintf("%t", binary))
+		if !binary {
+			params.Set("charset", "UTF-8")
+		}
+		reqOpts.Body = content
+		reqOpts.ContentType = ui5ContentType(filePath)
+	}
+	contentPath := fmt.Sprintf("%s/%s/content", ui5FilestoreBase, url.PathEscape(appName+parent))
+	if _, err := c.transport.Request(ctx, contentPath, reqOpts); err != nil {
+		return fmt.Errorf("creating %s in UI5 app %s: %w", filePath, appName, err)
+	}
+	return nil
+}
+
+// ui5UpdateEntry replaces the content of a file.
+func (c *Client) ui5UpdateEntry(ctx context.Context, appName, filePath string, content []byte, transport string) error {
+	params := url.Values{}
+	if transport != "" {
+		params.Set("corrNr", transport)
+	}
+	if !ui5IsBinary(content) {
+		params.Set("charset", "UTF-8")
+	}
+	contentPath := fmt.Sprintf("%s/%s/content", ui5FilestoreBase, url.PathEscape(appName+filePath))
+	_, err := c.transport.Request(ctx, contentPath, &RequestOptions{
+		Method:      http.MethodPut,
+		Query:       params,
+		Body:        content,
+		ContentType: ui5ContentType(filePath),
+	})
+	if err != nil {
+		return fmt.Errorf("uploading file %s to UI5 app %s: %w", filePath, appName, err)
+	}
+	return nil
+}
+
+// ui5DeleteEntry deletes a file or folder.
+func (c *Client) ui5DeleteEntry(ctx context.Context, appName, filePath, transport string) error {
+	params := url.Values{}
+	if transport != "" {
+		params.Set("corrNr", transport)
+	}
+	deletePath := fmt.Sprintf("%s/%s", ui5FilestoreBase, url.PathEscape(appName+filePath))
+	if _, err := c.transport.Request(ctx, deletePath, &RequestOptions{Method: http.MethodDelete, Query: params}); err != nil {
+		return fmt.Errorf("deleting %s from UI5 app %s: %w", filePath, appName, err)
+	}
+	return nil
+}
+
+// ui5SyncStateFile holds the sync state of a project folder. As a dot file
+// it is never pushed.
+const ui5SyncStateFile = ".vsp-ui5-sync.json"
+
+// ui5SyncState maps the app path of every synced file to the remote version
+// (ETag) and the hash of its content at that version. The filestore lists
+// versions but no hashes, so a push compares local hashes against this
+// record instead of downloading every remote file.
+type ui5SyncState struct {
+	App   string                  `json:"app"`
+	Files map[string]ui5SyncEntry `json:"files"`
+}
+
+type ui5SyncEntry struct {
+	ETag string `json:"etag"`
+	Hash string `json:"hash"`
+}
+
+func newUI5SyncState(appName string) *ui5SyncState {
+	return &ui5SyncState{App: appName, Files: make(map[string]ui5SyncEntry)}
+}
+
+// readUI5SyncState reads the sync state of dir for an app. A missing or
+// unreadable state, or one of another app, is empty.
+func readUI5SyncState(dir, appName string) *ui5SyncState {
+	state := newUI5SyncState(appName)
+	data, err := os.ReadFile(filepath.Join(dir, ui5SyncStateFile))
+	if err != nil {
+		return state
+	}
+	var saved ui5SyncState
+	if json.Unmarshal(data, &saved) != nil || saved.App != appName || saved.Files == nil {
+		return state
+	}
+	return &saved
+}
+
+// unchanged reports whether the remote file f is recorded at its current
+// version with the given content hash.
+func (s *ui5SyncState) unchanged(f UI5File, hash string) bool {
+	entry, ok := s.Files[f.Path]
+	return ok && f.ETag != "" && entry.ETag == f.ETag && entry.Hash == hash
+}
+
+// record stores the content of the remote file f at its current version.
+func (s *ui5SyncState) record(f UI5File, content []byte) {
+	if f.ETag != "" {
+		s.Files[f.Path] = ui5SyncEntry{ETag: f.ETag, Hash: ui5ContentHash(content)}
+	}
+}
+
+func (s *ui5SyncState) write(dir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ui5SyncStateFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing sync state: %w", err)
+	}
+	return nil
+}
+
+// ui5LocalFiles reads all files below root, keyed by app path
+// ("/webapp/index.html"). VCS folders, node_modules and dot files are
+// skipped.
+func ui5LocalFiles(root string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files["/"+filepath.ToSlash(rel)] = content
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", root, err)
+	}
+	return files, nil
+}
+
+// ui5ParentFolders returns the folders of an app path, outermost first.
+func ui5ParentFolders(filePath string) []string {
+	var folders []string
+	for i := 1; i < len(filePath); i++ {
+		if filePath[i] == '/' {
+			folders = append(folders, filePath[:i])
+		}
+	}
+	return folders
+}
+
+// ui5HasRemovedParent reports whether a folder of p is removed as well, so
+// that p is deleted with it.
+func ui5HasRemovedParent(p string, removed []string) bool {
+	for _, folder := range ui5ParentFolders(p) {
+		for _, r := range removed {
+			if r == folder {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ui5ContentHash hashes file content; line endings of text files do not
+// count as changes.
+func ui5ContentHash(content []byte) string {
+	if !ui5IsBinary(content) {
+		content = []byte(normalizeLineEndings(string(content)))
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func ui5IsBinary(content []byte) bool {
+	return !utf8.Valid(content) || strings.ContainsRune(string(content), 0)
+}
+
+func ui5ContentType(filePath string) string {
+	if t := mime.TypeByExtension(path.Ext(filePath)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,211 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// fakeFilestore serves a UI5 app from a map of app paths; folders have a
+// nil value. File versions (ETags) follow the content; changes and file
+// downloads are recorded in requests. The app belongs to package $ZPKG.
+func fakeFilestore(t *testing.T, app string, files map[string][]byte, requests *[]string) *httptest.Server {
+	t.Helper()
+	prefix := ui5FilestoreBase + "/"
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/sap/bc/adt/core/discovery" {
+			w.Header().Set("X-CSRF-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if r.URL.Path == "/sap/bc/adt/repository/informationsystem/search" {
+			fmt.Fprintf(w, `<adtcore:objectReferences xmlns:adtcore="http://www.sap.com/adt/core">
+  <adtcore:objectReference adtcore:uri="/sap/bc/adt/filestore/ui5-bsp/objects/%s" adtcore:type="WAPA/WO" adtcore:name="%s" adtcore:packageName="$ZPKG"/>
+</adtcore:objectReferences>`, app, app)
+			return
+		}
+		escaped := strings.TrimPrefix(r.URL.EscapedPath(), prefix)
+		isContent := strings.HasSuffix(escaped, "/content")
+		name, _ := url.PathUnescape(strings.TrimSuffix(escaped, "/content"))
+		p := strings.TrimPrefix(name, app)
+		if r.Method != http.MethodGet {
+			*requests = append(*requests, fmt.Sprintf("%s %s %s", r.Method, p, r.URL.Query().Get("corrNr")))
+		}
+
+		switch {
+		case r.Method == http.MethodGet && isContent:
+			if content, ok := files[p]; ok && content != nil {
+				*requests = append(*requests, "GET "+p)
+				w.Write(content)
+				return
+			}
+			if _, ok := files[p]; !ok && p != "" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			var entries []string
+			for f, content := range files {
+				if f == p || !strings.HasPrefix(f, p+"/") || strings.Contains(f[len(p)+1:], "/") {
+					continue
+				}
+				term, etag := "file", ""
+				if content == nil {
+					term = "folder"
+				} else {
+					etag = ui5ContentHash(content)[:12]
+				}
+				entries = append(entries, fmt.Sprintf(`<entry><id>%s</id><title>%s%s</title><category term="%s"/><link rel="self" afr:etag="%s"/></entry>`, f, app, f, term, etag))
+			}
+			sort.Strings(entries)
+			fmt.Fprintf(w, `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:afr="http://www.sap.com/adt/afr">%s</feed>`, strings.Join(entries, ""))
+		case r.Method == http.MethodPut:
+			files[p] = body
+		case r.Method == http.MethodPost:
+			child := p + "/" + r.URL.Query().Get("name")
+			if r.URL.Query().Get("type") == "folder" {
+				files[child] = nil
+			} else {
+				files[child] = body
+			}
+		case r.Method == http.MethodDelete:
+			for f := range files {
+				if f == p || strings.HasPrefix(f, p+"/") {
+					delete(files, f)
+				}
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestUI5Push(t *testing.T) {
+	remote := map[string][]byte{
+		"/webapp":               nil,
+		"/webapp/index.html":    []byte("<html>old</html>"),
+		"/webapp/manifest.json": []byte("{\n  \"sap.app\": {\"id\": \"com.acme.app\"}\n}"),
+		"/old":                  nil,
+		"/old/legacy.js":        []byte("x"),
+	}
+	var requests []string
+	server := fakeFilestore(t, "ZAPP", remote, &requests)
+	defer server.Close()
+
+	dir := t.TempDir()
+	write := func(name, content string) {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("ui5-deploy.yaml", `specVersion: "3.0"
+builder:
+  customTasks:
+    - name: deploy-to-abap
+      configuration:
+        app:
+          name: zapp
+          package: $ZPKG
+          transport: devk900001
+`)
+	write("webapp/manifest.json", "{\r\n  \"sap.app\": {\"id\": \"com.acme.app\"}\r\n}")
+	write("dist/webapp/manifest.json", "{\r\n  \"sap.app\": {\"id\": \"com.acme.app\"}\r\n}")
+	write("dist/webapp/index.html", "<html>new</html>")
+	write("dist/webapp/i18n/i18n.properties", "title=App")
+	write("dist/.DS_Store", "junk")
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"), WithAllowTransportableEdits())
+	ctx := context.Background()
+
+	dry, err := client.UI5Push(ctx, dir, "", UI5SyncOptions{DryRun: true, Backend: UI5BackendFilestore})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	// Without sync state every existing file is uploaded, nothing downloaded
+	if len(requests) != 0 || dry.Summary() != "ZAPP: 2 created, 2 updated, 1 deleted, 0 unchanged (dry run)" {
+		t.Fatalf("unexpected dry run: %s, requests %v", dry.Summary(), requests)
+	}
+
+	result, err := client.UI5Push(ctx, dir, "", UI5SyncOptions{Backend: UI5BackendFilestore})
+	if err != nil {
+		t.Fatalf("UI5Push failed: %v", err)
+	}
+	if result.AppID != "com.acme.app" || result.Package != "$ZPKG" || result.Transport != "DEVK900001" {
+		t.Errorf("config not honoured: %+v", result)
+	}
+	want := []string{
+		"POST /webapp DEVK900001",
+		"POST /webapp/i18n DEVK900001",
+		"PUT /webapp/index.html DEVK900001",
+		"PUT /webapp/manifest.json DEVK900001",
+		"DELETE /old DEVK900001",
+	}
+	if strings.Join(requests, "|") != strings.Join(want, "|") {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+	if string(remote["/webapp/i18n/i18n.properties"]) != "title=App" || string(remote["/webapp/index.html"]) != "<html>new</html>" {
+		t.Errorf("unexpected remote files: %v", remote)
+	}
+	if _, ok := remote["/old/legacy.js"]; ok {
+		t.Error("removed file still exists")
+	}
+
+	// The recorded versions make the next push a no-op without downloads
+	requests = nil
+	again, err := client.UI5Push(ctx, dir, "", UI5SyncOptions{Backend: UI5BackendFilestore})
+	if err != nil {
+		t.Fatalf("UI5Push failed: %v", err)
+	}
+	if len(requests) != 0 || again.Summary() != "ZAPP: 0 created, 0 updated, 0 deleted, 3 unchanged" {
+		t.Errorf("unexpected second push: %s, requests %v", again.Summary(), requests)
+	}
+
+	// A file changed on the server is uploaded again
+	remote["/webapp/index.html"] = []byte("<html>edited</html>")
+	requests = nil
+	if _, err := client.UI5Push(ctx, dir, "", UI5SyncOptions{Backend: UI5BackendFilestore}); err != nil {
+		t.Fatalf("UI5Push failed: %v", err)
+	}
+	if strings.Join(requests, "|") != "PUT /webapp/index.html DEVK900001" {
+		t.Errorf("requests = %v", requests)
+	}
+
+	// The package of the existing app is checked on every push
+	restricted := NewClient(server.URL, "testuser", "testpass", WithClient("001"), WithAllowTransportableEdits(), WithAllowedPackages("$TMP"))
+	if _, err := restricted.UI5Push(ctx, dir, "", UI5SyncOptions{Package: "$TMP", Backend: UI5BackendFilestore}); err == nil || !strings.Contains(err.Error(), "$ZPKG") {
+		t.Errorf("expected package safety error, got %v", err)
+	}
+
+	// Pull into an empty folder reproduces the pushed files
+	out := t.TempDir()
+	pulled, err := client.UI5Pull(ctx, "zapp", out, UI5SyncOptions{})
+	if err != nil {
+		t.Fatalf("UI5Pull failed: %v", err)
+	}
+	if len(pulled.Created) != 3 {
+		t.Errorf("expected 3 pulled files, got %v", pulled.Created)
+	}
+	if data, _ := os.ReadFile(filepath.Join(out, "webapp", "i18n", "i18n.properties")); string(data) != "title=App" {
+		t.Errorf("pulled content = %q", data)
+	}
+
+	// Pulled files are recorded: pulling again downloads nothing
+	requests = nil
+	pulled, err = client.UI5Pull(ctx, "zapp", out, UI5SyncOptions{})
+	if err != nil {
+		t.Fatalf("UI5Pull failed: %v", err)
+	}
+	if len(requests) != 0 || pulled.Unchanged != 3 {
+		t.Errorf("unexpected second pull: %s, requests %v", pulled.Summary(), requests)
+	}
+}
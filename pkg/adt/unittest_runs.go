@@ -0,0 +1,319 @@
+package adt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// --- Unit Test Run Tracking ---
+//
+// The last result of every tested object is kept in a local store, so that
+// only the tests that failed can be run again and consecutive runs can be
+// compared (newly failing, fixed, slower).
+
+// DefaultUnitTestStoreDir is the default directory of the unit test store.
+const DefaultUnitTestStoreDir = ".vsp-unittests"
+
+// A test counts as slower if its execution time grew by more than
+// slowerFactor and by at least slowerMinDelta seconds.
+const (
+	slowerFactor   = 1.5
+	slowerMinDelta = 0.1
+)
+
+// UnitTestObjectURLs returns the objects (classes, programs, ...) that own
+// the given test URIs, without duplicates.
+func UnitTestObjectURLs(uris []string) []string {
+	var objects []string
+	for _, uri := range uris {
+		objects = append(objects, atcMainObjectURL(atcSourceURL(uri)))
+	}
+	return dedupeStrings(objects)
+}
+
+// FailedTestURIs returns the URIs of the failed test methods of the result.
+// A test class whose alerts are not tied to a method (e.g. a failing
+// class_setup) is returned as a whole.
+func (r *UnitTestResult) FailedTestURIs() []string {
+	var uris []string
+	for _, class := range r.Classes {
+		if len(class.Alerts) > 0 {
+			uris = append(uris, unitTestRunURI(class.URI, class.NavigationURI))
+			continue
+		}
+		for _, m := range class.TestMethods {
+			if len(m.Alerts) > 0 {
+				uris = append(uris, unitTestRunURI(m.URI, m.NavigationURI))
+			}
+		}
+	}
+	return dedupeStrings(uris)
+}
+
+func unitTestRunURI(uri, navigationURI string) string {
+	if uri != "" {
+		return uri
+	}
+	return navigationURI
+}
+
+// unitTestKey identifies a test method across runs.
+func unitTestKey(class UnitTestClass, m UnitTestMethod) string {
+	if m.URI != "" {
+		return m.URI
+	}
+	return strings.ToUpper(class.Name + "=>" + m.Name)
+}
+
+// MergeUnitTestResults returns base with the tests of partial replaced or
+// added. It is used to keep the full picture of an object after a targeted
+// run.
+func MergeUnitTestResults(base, partial *UnitTestResult) *UnitTestResult {
+	if base == nil {
+		return partial
+	}
+	merged := &UnitTestResult{Classes: make([]UnitTestClass, len(base.Classes))}
+	for i, class := range base.Classes {
+		class.TestMethods = append([]UnitTestMethod{}, class.TestMethods...)
+		merged.Classes[i] = class
+	}
+
+	for _, pc := range partial.Classes {
+		idx := -1
+		for i, class := range merged.Classes {
+			if strings.EqualFold(class.Name, pc.Name) && (class.URI == pc.URI || class.URI == "" || pc.URI == "") {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			merged.Classes = append(merged.Classes, pc)
+			continue
+		}
+
+		class := &merged.Classes[idx]
+		class.Alerts = pc.Alerts
+		for _, pm := range pc.TestMethods {
+			key := unitTestKey(pc, pm)
+			replaced := false
+			for j, m := range class.TestMethods {
+				if unitTestKey(*class, m) == key {
+					class.TestMethods[j] = pm
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				class.TestMethods = append(class.TestMethods, pm)
+			}
+		}
+	}
+	return merged
+}
+
+// UnitTestChange is a test method whose outcome or duration changed.
+type UnitTestChange struct {
+	Class        string  `json:"class"`
+	Method       string  `json:"method"`
+	URI          string  `json:"uri,omitempty"`
+	Message      string  `json:"message,omitempty"` // First alert of a failing test
+	Time         float64 `json:"time"`              // Seconds
+	PreviousTime float64 `json:"previousTime,omitempty"`
+}
+
+// UnitTestRunDiff compares two runs of the same tests. Tests that only
+// occur in the previous run are ignored, so a targeted run can be compared
+// with a full one.
+type UnitTestRunDiff struct {
+	NewlyFailing []UnitTestChange `json:"newlyFailing"`
+	Fixed        []UnitTestChange `json:"fixed"`
+	Slower       []UnitTestChange `json:"slower"`
+	StillFailing int              `json:"stillFailing"`
+	Compared     int              `json:"compared"`
+}
+
+// CompareUnitTestRuns returns the differences between a previous and the
+// current result. New tests that fail count as newly failing.
+func CompareUnitTestRuns(previous, current *UnitTestResult) *UnitTestRunDiff {
+	type prevTest struct {
+		failed bool
+		time   float64
+	}
+	prev := make(map[string]prevTest)
+	if previous != nil {
+		for _, class := range previous.Classes {
+			for _, m := range class.TestMethods {
+				prev[unitTestKey(class, m)] = prevTest{failed: len(m.Alerts) > 0 || len(class.Alerts) > 0, time: m.ExecutionTime}
+			}
+		}
+	}
+
+	diff := &UnitTestRunDiff{NewlyFailing: []UnitTestChange{}, Fixed: []UnitTestChange{}, Slower: []UnitTestChange{}}
+	for _, class := range current.Classes {
+		for _, m := range class.TestMethods {
+			change := UnitTestChange{Class: class.Name, Method: m.Name, URI: unitTestRunURI(m.URI, m.NavigationURI), Time: m.ExecutionTime}
+			failed := len(m.Alerts) > 0 || len(class.Alerts) > 0
+			if failed {
+				if len(m.Alerts) > 0 {
+					change.Message = m.Alerts[0].Title
+				} else {
+					change.Message = class.Alerts[0].Title
+				}
+			}
+
+			p, known := prev[unitTestKey(class, m)]
+			if known {
+				diff.Compared++
+				change.PreviousTime = p.time
+			}
+			switch {
+			case failed && (!known || !p.failed):
+				diff.NewlyFailing = append(diff.NewlyFailing, change)
+			case failed:
+				diff.StillFailing++
+			case known && p.failed:
+				diff.Fixed = append(diff.Fixed, change)
+			}
+			if known && !failed && !p.failed && m.ExecutionTime > p.time*slowerFactor && m.ExecutionTime-p.time >= slowerMinDelta {
+				diff.Slower = append(diff.Slower, change)
+			}
+		}
+	}
+	return diff
+}
+
+// UnitTestRunRecord is the stored last result of an object.
+type UnitTestRunRecord struct {
+	Object    string          `json:"object"`
+	Timestamp time.Time       `json:"timestamp"`
+	Result    *UnitTestResult `json:"result"`
+}
+
+// UnitTestStore keeps the last unit test result per object as JSON files.
+type UnitTestStore struct {
+	Dir string
+}
+
+// NewUnitTestStore creates a store in dir (DefaultUnitTestStoreDir if empty).
+func NewUnitTestStore(dir string) *UnitTestStore {
+	if dir == "" {
+		dir = DefaultUnitTestStoreDir
+	}
+	return &UnitTestStore{Dir: dir}
+}
+
+var unitTestFileNameRegex = regexp.MustCompile(`[^a-z0-9_$-]+`)
+
+func (s *UnitTestStore) path(objectURL string) string {
+	name := strings.ToLower(strings.TrimPrefix(objectURL, "/sap/bc/adt/"))
+	name = strings.Trim(unitTestFileNameRegex.ReplaceAllString(name, "_"), "_")
+	return filepath.Join(s.Dir, name+".json")
+}
+
+// Load returns the last recorded run of an object, or nil if there is none.
+func (s *UnitTestStore) Load(objectURL string) (*UnitTestRunRecord, error) {
+	data, err := os.ReadFile(s.path(objectURL))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading unit test history: %w", err)
+	}
+	var record UnitTestRunRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("parsing unit test history of %s: %w", objectURL, err)
+	}
+	return &record, nil
+}
+
+// Save records the result of an object. Coverage data is not stored.
+func (s *UnitTestStore) Save(objectURL string, result *UnitTestResult) error {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return fmt.Errorf("creating unit test history: %w", err)
+	}
+	stored := *result
+	stored.Coverage = nil
+	stored.CoverageURI = ""
+	data, err := json.MarshalIndent(UnitTestRunRecord{Object: objectURL, Timestamp: time.Now().UTC().Truncate(time.Second), Result: &stored}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding unit test history: %w", err)
+	}
+	if err := os.WriteFile(s.path(objectURL), data, 0644); err != nil {
+		return fmt.Errorf("writing unit test history: %w", err)
+	}
+	return nil
+}
+
+// UnitTestRunOptions selects the tests of a tracked run.
+type UnitTestRunOptions struct {
+	Flags      *UnitTestRunFlags
+	Tests      []string // Test class/method URIs; empty = all tests of the object
+	FailedOnly bool     // Run the tests that failed in the last recorded run
+}
+
+// TrackedUnitTestRun is the result of RunUnitTestsTracked.
+type TrackedUnitTestRun struct {
+	Object  string           `json:"object"`
+	Tests   []string         `json:"tests,omitempty"` // Tests that were run (empty = all)
+	Result  *UnitTestResult  `json:"result"`
+	Diff    *UnitTestRunDiff `json:"diff,omitempty"` // Against the last recorded run
+	Message string           `json:"message,omitempty"`
+}
+
+// RunUnitTestsTracked runs the tests of an object, compares the result with
+// the last recorded run and records the new state. After a targeted run the
+// recorded result is the previous one with the executed tests replaced. A
+// nil store disables recording; FailedOnly needs a store.
+func (c *Client) RunUnitTestsTracked(ctx context.Context, objectURL string, store *UnitTestStore, opts UnitTestRunOptions) (*TrackedUnitTestRun, error) {
+	var previous *UnitTestRunRecord
+	if store != nil {
+		var err error
+		if previous, err = store.Load(objectURL); err != nil {
+			return nil, err
+		}
+	}
+
+	run := &TrackedUnitTestRun{Object: objectURL, Tests: opts.Tests}
+	if opts.FailedOnly {
+		if previous == nil {
+			return nil, fmt.Errorf("no recorded unit test run for %s", objectURL)
+		}
+		run.Tests = previous.Result.FailedTestURIs()
+		if len(run.Tests) == 0 {
+			run.Result = &UnitTestResult{Classes: []UnitTestClass{}}
+			run.Message = "No failed tests in the last run"
+			return run, nil
+		}
+	}
+
+	targets := run.Tests
+	if len(targets) == 0 {
+		targets = []string{objectURL}
+	}
+	result, err := c.RunUnitTestsFor(ctx, targets, opts.Flags)
+	if err != nil {
+		return nil, err
+	}
+	run.Result = result
+
+	recorded := result
+	if previous != nil {
+		run.Diff = CompareUnitTestRuns(previous.Result, result)
+		if len(run.Tests) > 0 {
+			recorded = MergeUnitTestResults(previous.Result, result)
+		}
+	}
+	if store != nil {
+		if err := store.Save(objectURL, recorded); err != nil {
+			return nil, err
+		}
+	}
+	return run, nil
+}
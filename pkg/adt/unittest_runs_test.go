@@ -0,0 +1,142 @@
+package adt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testMethodAddURI = "/sap/bc/adt/oo/classes/zcl_calc/includes/testclasses#type=CLAS%2FOLD;name=LTCL_CALC%20ADD"
+	testMethodSubURI = "/sap/bc/adt/oo/classes/zcl_calc/includes/testclasses#type=CLAS%2FOLD;name=LTCL_CALC%20SUB"
+)
+
+func testUnitRunResult(subFails bool, addTime string) string {
+	subAlerts := ""
+	if subFails {
+		subAlerts = `<alerts><alert kind="failedAssertion" severity="critical"><title>Expected 1, got 2</title></alert></alerts>`
+	}
+	return `<?xml version="1.0" encoding="utf-8"?>
+<aunit:runResult xmlns:aunit="http://www.sap.com/adt/aunit" xmlns:adtcore="http://www.sap.com/adt/core">
+  <program adtcore:uri="/sap/bc/adt/oo/classes/zcl_calc" adtcore:type="CLAS/OC" adtcore:name="ZCL_CALC">
+    <testClasses>
+      <testClass adtcore:uri="/sap/bc/adt/oo/classes/zcl_calc/includes/testclasses#type=CLAS%2FOL;name=LTCL_CALC" adtcore:type="CLAS/OL" adtcore:name="LTCL_CALC">
+        <testMethods>
+          <testMethod adtcore:uri="` + testMethodAddURI + `" adtcore:type="CLAS/OLD" adtcore:name="ADD" executionTime="` + addTime + `"/>
+          <testMethod adtcore:uri="` + testMethodSubURI + `" adtcore:type="CLAS/OLD" adtcore:name="SUB" executionTime="0.010">` + subAlerts + `</testMethod>
+        </testMethods>
+      </testClass>
+    </testClasses>
+  </program>
+</aunit:runResult>`
+}
+
+func TestCompareUnitTestRuns(t *testing.T) {
+	prev, err := parseUnitTestResult([]byte(testUnitRunResult(true, "0.010")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur, err := parseUnitTestResult([]byte(testUnitRunResult(false, "0.500")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if failed := prev.FailedTestURIs(); len(failed) != 1 || failed[0] != testMethodSubURI {
+		t.Errorf("FailedTestURIs = %v", failed)
+	}
+
+	diff := CompareUnitTestRuns(prev, cur)
+	if diff.Compared != 2 || len(diff.Fixed) != 1 || diff.Fixed[0].Method != "SUB" {
+		t.Errorf("unexpected fixed: %+v", diff)
+	}
+	if len(diff.Slower) != 1 || diff.Slower[0].Method != "ADD" || diff.Slower[0].PreviousTime != 0.010 {
+		t.Errorf("unexpected slower: %+v", diff.Slower)
+	}
+
+	back := CompareUnitTestRuns(cur, prev)
+	if len(back.NewlyFailing) != 1 || back.NewlyFailing[0].Message != "Expected 1, got 2" {
+		t.Errorf("unexpected newly failing: %+v", back.NewlyFailing)
+	}
+
+	if got := UnitTestObjectURLs([]string{testMethodAddURI, testMethodSubURI, "/sap/bc/adt/programs/programs/zfoo"}); len(got) != 2 || got[0] != "/sap/bc/adt/oo/classes/zcl_calc" {
+		t.Errorf("UnitTestObjectURLs = %v", got)
+	}
+}
+
+func TestRunUnitTestsTracked_RerunFailed(t *testing.T) {
+	var runBodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/sap/bc/adt/core/discovery":
+			w.Header().Set("X-CSRF-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+		case "/sap/bc/adt/abapunit/testruns":
+			runBodies = append(runBodies, string(body))
+			if len(runBodies) == 1 {
+				w.Write([]byte(testUnitRunResult(true, "0.010")))
+				return
+			}
+			// Targeted run: only the failed method, now passing
+			w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+<aunit:runResult xmlns:aunit="http://www.sap.com/adt/aunit" xmlns:adtcore="http://www.sap.com/adt/core">
+  <program adtcore:uri="/sap/bc/adt/oo/classes/zcl_calc" adtcore:type="CLAS/OC" adtcore:name="ZCL_CALC">
+    <testClasses>
+      <testClass adtcore:uri="/sap/bc/adt/oo/classes/zcl_calc/includes/testclasses#type=CLAS%2FOL;name=LTCL_CALC" adtcore:type="CLAS/OL" adtcore:name="LTCL_CALC">
+        <testMethods>
+          <testMethod adtcore:uri="` + testMethodSubURI + `" adtcore:type="CLAS/OLD" adtcore:name="SUB" executionTime="0.010"/>
+        </testMethods>
+      </testClass>
+    </testClasses>
+  </program>
+</aunit:runResult>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"))
+	store := NewUnitTestStore(t.TempDir())
+	ctx := context.Background()
+	objectURL := "/sap/bc/adt/oo/classes/zcl_calc"
+
+	if _, err := client.RunUnitTestsTracked(ctx, objectURL, store, UnitTestRunOptions{FailedOnly: true}); err == nil {
+		t.Error("expected error for rerun without recorded run")
+	}
+
+	first, err := client.RunUnitTestsTracked(ctx, objectURL, store, UnitTestRunOptions{})
+	if err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+	if first.Diff != nil {
+		t.Errorf("first run should have no diff")
+	}
+
+	rerun, err := client.RunUnitTestsTracked(ctx, objectURL, store, UnitTestRunOptions{FailedOnly: true})
+	if err != nil {
+		t.Fatalf("rerun failed: %v", err)
+	}
+	if len(rerun.Tests) != 1 || !strings.Contains(runBodies[1], `adtcore:uri="`+xmlEscape(testMethodSubURI)+`"`) || strings.Contains(runBodies[1], `adtcore:uri="`+objectURL+`"`) {
+		t.Errorf("rerun did not target the failed method: %s", runBodies[1])
+	}
+	if rerun.Diff == nil || len(rerun.Diff.Fixed) != 1 || rerun.Diff.Compared != 1 {
+		t.Errorf("unexpected rerun diff: %+v", rerun.Diff)
+	}
+
+	// The recorded state still has both methods, none failing
+	record, err := store.Load(objectURL)
+	if err != nil || record == nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if methods := record.Result.Classes[0].TestMethods; len(methods) != 2 {
+		t.Errorf("recorded methods = %d, want 2", len(methods))
+	}
+	again, err := client.RunUnitTestsTracked(ctx, objectURL, store, UnitTestRunOptions{FailedOnly: true})
+	if err != nil || again.Message == "" || len(runBodies) != 2 {
+		t.Errorf("expected no run when nothing failed: %+v, %v", again, err)
+	}
+}
@@ -0,0 +1,196 @@
+package adt
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// --- Watchpoints (data breakpoints) ---
+//
+// Watchpoints are session-bound: they can only be created while attached to a
+// debuggee and are discarded when the debug session ends.
+
+// WatchpointKind is the scope of the watched variable as reported by SAP.
+type WatchpointKind string
+
+const (
+	WatchpointKindSystemGlobal  WatchpointKind = "system_global"
+	WatchpointKindProgramGlobal WatchpointKind = "program_global"
+	WatchpointKindLocal         WatchpointKind = "local"
+	WatchpointKindInstanceAttr  WatchpointKind = "instance_attribute"
+)
+
+// Watchpoint represents a debugger watchpoint.
+type Watchpoint struct {
+	ID           string         `json:"id"`
+	Variable     string         `json:"variable"`
+	Active       bool           `json:"active"`
+	Condition    string         `json:"condition,omitempty"`
+	Kind         WatchpointKind `json:"kind,omitempty"`
+	Program      string         `json:"program,omitempty"`
+	Procedure    string         `json:"procedure,omitempty"`
+	CurrentValue string         `json:"currentValue,omitempty"`
+	OldValue     string         `json:"oldValue,omitempty"`
+}
+
+// WatchpointCondition combines an optional target value and an optional
+// condition into a single ABAP debugger condition for variable.
+// Example: WatchpointCondition("LV_STATUS", "E", "SY-TABIX > 5")
+// returns "LV_STATUS = 'E' AND ( SY-TABIX > 5 )".
+func WatchpointCondition(variable, value, condition string) string {
+	var parts []string
+	if value != "" {
+		parts = append(parts, fmt.Sprintf("%s = %s", variable, quoteConditionLiteral(value)))
+	}
+	if condition = strings.TrimSpace(condition); condition != "" {
+		if len(parts) > 0 {
+			condition = "( " + condition + " )"
+		}
+		parts = append(parts, condition)
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// CheckWatchpointOptions rejects modifiers a watchpoint cannot honour. The
+// ABAP debugger evaluates the condition itself, but a watchpoint stop cannot
+// be told apart from other stops, so hit counts and log expressions cannot
+// be tracked.
+func CheckWatchpointOptions(opts BreakpointOptions) error {
+	if opts.HitCount > 1 {
+		return fmt.Errorf("hit counts are not supported for watchpoints, use a condition instead")
+	}
+	if opts.IsLogPoint() {
+		return fmt.Errorf("log expressions are not supported for watchpoints")
+	}
+	return nil
+}
+
+// quoteConditionLiteral quotes a value for use in a debugger condition.
+// Numbers and already quoted literals are returned unchanged.
+func quoteConditionLiteral(value string) string {
+	if isQuotedConditionLiteral(value) {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// DebuggerSetWatchpoint creates a watchpoint in the current debug session.
+// The debugger stops when variable changes and condition (if any) holds.
+// Requires an attached debuggee (see DebuggerAttach).
+func (c *Client) DebuggerSetWatchpoint(ctx context.Context, variable, condition string) (*Watchpoint, error) {
+	if variable == "" {
+		return nil, fmt.Errorf("variable is required")
+	}
+
+	query := url.Values{}
+	query.Set("variableName", strings.ToUpper(variable))
+	if condition != "" {
+		query.Set("condition", condition)
+	}
+
+	resp, err := c.transport.Request(ctx, "/sap/bc/adt/debugger/watchpoints", &RequestOptions{
+		Method: http.MethodPost,
+		Accept: "application/xml",
+		Query:  query,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("debugger set watchpoint failed: %w", err)
+	}
+
+	wps, err := parseWatchpointsResponse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(wps) == 0 {
+		return &Watchpoint{Variable: strings.ToUpper(variable), Condition: condition, Active: true}, nil
+	}
+	return &wps[0], nil
+}
+
+// DebuggerGetWatchpoints lists the watchpoints of the current debug session.
+func (c *Client) DebuggerGetWatchpoints(ctx context.Context) ([]Watchpoint, error) {
+	resp, err := c.transport.Request(ctx, "/sap/bc/adt/debugger/watchpoints", &RequestOptions{
+		Method: http.MethodGet,
+		Accept: "application/xml",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("debugger get watchpoints failed: %w", err)
+	}
+
+	return parseWatchpointsResponse(resp.Body)
+}
+
+// DebuggerDeleteWatchpoint removes a watchpoint from the current debug session.
+func (c *Client) DebuggerDeleteWatchpoint(ctx context.Context, watchpointID string) error {
+	_, err := c.transport.Request(ctx, "/sap/bc/adt/debugger/watchpoints/"+url.PathEscape(watchpointID), &RequestOptions{
+		Method: http.MethodDelete,
+	})
+	if err != nil {
+		return fmt.Errorf("debugger delete watchpoint failed: %w", err)
+	}
+	return nil
+}
+
+func parseWatchpointsResponse(data []byte) ([]Watchpoint, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	// Strip namespace prefixes
+	xmlStr := string(data)
+	xmlStr = strings.ReplaceAll(xmlStr, "dbg:", "")
+
+	type xmlWatchpoint struct {
+		ID           string `xml:"id,attr"`
+		VarName      string `xml:"varname,attr"`
+		Active       bool   `xml:"active,attr"`
+		Condition    string `xml:"condition,attr"`
+		Kind         string `xml:"kind,attr"`
+		Program      string `xml:"program,attr"`
+		Procedure    string `xml:"procedure,attr"`
+		CurrentValue string `xml:"currentvalue,attr"`
+		OldValue     string `xml:"oldvalue,attr"`
+	}
+
+	type xmlWatchpoints struct {
+		Watchpoints []xmlWatchpoint `xml:"watchpoint"`
+	}
+
+	var resp xmlWatchpoints
+	if err := xml.Unmarshal([]byte(xmlStr), &resp); err != nil {
+		return nil, fmt.Errorf("parsing watchpoints response: %w", err)
+	}
+
+	// A single created watchpoint may be returned as root element
+	if len(resp.Watchpoints) == 0 {
+		var single xmlWatchpoint
+		if err := xml.Unmarshal([]byte(xmlStr), &single); err == nil && single.ID != "" {
+			resp.Watchpoints = append(resp.Watchpoints, single)
+		}
+	}
+
+	var result []Watchpoint
+	for _, wp := range resp.Watchpoints {
+		result = append(result, Watchpoint{
+			ID:           wp.ID,
+			Variable:     wp.VarName,
+			Active:       wp.Active,
+			Condition:    wp.Condition,
+			Kind:         WatchpointKind(wp.Kind),
+			Program:      wp.Program,
+			Procedure:    wp.Procedure,
+			CurrentValue: wp.CurrentValue,
+			OldValue:     wp.OldValue,
+		})
+	}
+
+	return result, nil
+}
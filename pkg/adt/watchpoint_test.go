@@ -0,0 +1,132 @@
+package adt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWatchpointCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		variable  string
+		value     string
+		condition string
+		want      string
+	}{
+		{"no value no condition", "LV_X", "", "", ""},
+		{"character value", "LV_STATUS", "E", "", "LV_STATUS = 'E'"},
+		{"numeric value", "LV_COUNT", "42", "", "LV_COUNT = 42"},
+		{"quoted value", "LV_STATUS", "'E'", "", "LV_STATUS = 'E'"},
+		{"value with quote", "LV_TEXT", "it's", "", "LV_TEXT = 'it''s'"},
+		{"condition only", "LV_X", "", "SY-TABIX > 5", "SY-TABIX > 5"},
+		{"value and condition", "LV_STATUS", "E", "SY-TABIX > 5", "LV_STATUS = 'E' AND ( SY-TABIX > 5 )"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WatchpointCondition(tt.variable, tt.value, tt.condition)
+			if got != tt.want {
+				t.Errorf("WatchpointCondition() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckWatchpointOptions(t *testing.T) {
+	if err := CheckWatchpointOptions(BreakpointOptions{Condition: "SY-TABIX > 5", HitCount: 1}); err != nil {
+		t.Errorf("condition rejected: %v", err)
+	}
+	if err := CheckWatchpointOptions(BreakpointOptions{HitCount: 3}); err == nil {
+		t.Error("expected hit count to be rejected")
+	}
+	if err := CheckWatchpointOptions(BreakpointOptions{LogExpressions: []string{"LV_X"}}); err == nil {
+		t.Error("expected log expressions to be rejected")
+	}
+}
+
+func TestParseWatchpointsResponse(t *testing.T) {
+	xmlData := `<?xml version="1.0" encoding="utf-8"?>
+<dbg:watchpoints xmlns:dbg="http://www.sap.com/adt/debugger">
+  <dbg:watchpoint id="WP1" varname="LV_STATUS" active="true" kind="local" program="ZTEST" procedure="MAIN" currentvalue="A" condition="LV_STATUS = 'E'"/>
+  <dbg:watchpoint id="WP2" varname="GV_COUNT" active="false" kind="program_global"/>
+</dbg:watchpoints>`
+
+	wps, err := parseWatchpointsResponse([]byte(xmlData))
+	if err != nil {
+		t.Fatalf("parseWatchpointsResponse failed: %v", err)
+	}
+	if len(wps) != 2 {
+		t.Fatalf("expected 2 watchpoints, got %d", len(wps))
+	}
+
+	wp := wps[0]
+	if wp.ID != "WP1" || wp.Variable != "LV_STATUS" || !wp.Active {
+		t.Errorf("unexpected watchpoint: %+v", wp)
+	}
+	if wp.Kind != WatchpointKindLocal {
+		t.Errorf("expected kind local, got %s", wp.Kind)
+	}
+	if wp.Condition != "LV_STATUS = 'E'" {
+		t.Errorf("unexpected condition: %s", wp.Condition)
+	}
+	if wps[1].Kind != WatchpointKindProgramGlobal || wps[1].Active {
+		t.Errorf("unexpected watchpoint: %+v", wps[1])
+	}
+}
+
+func TestParseWatchpointsResponse_Single(t *testing.T) {
+	xmlData := `<dbg:watchpoint xmlns:dbg="http://www.sap.com/adt/debugger" id="WP9" varname="LV_X" active="true"/>`
+
+	wps, err := parseWatchpointsResponse([]byte(xmlData))
+	if err != nil {
+		t.Fatalf("parseWatchpointsResponse failed: %v", err)
+	}
+	if len(wps) != 1 || wps[0].ID != "WP9" {
+		t.Fatalf("unexpected result: %+v", wps)
+	}
+}
+
+func TestDebuggerSetWatchpoint_Integration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// CSRF token request via discovery endpoint
+		if r.URL.Path == "/sap/bc/adt/core/discovery" {
+			w.Header().Set("X-CSRF-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		if r.Method == http.MethodPost && r.URL.Path == "/sap/bc/adt/debugger/watchpoints" {
+			if got := r.URL.Query().Get("variableName"); got != "LV_STATUS" {
+				t.Errorf("expected variableName LV_STATUS, got %q", got)
+			}
+			if got := r.URL.Query().Get("condition"); got != "LV_STATUS = 'E'" {
+				t.Errorf("unexpected condition %q", got)
+			}
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+<dbg:watchpoints xmlns:dbg="http://www.sap.com/adt/debugger">
+  <dbg:watchpoint id="WP1" varname="LV_STATUS" active="true" condition="LV_STATUS = 'E'"/>
+</dbg:watchpoints>`))
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser", "testpass", WithClient("001"))
+
+	wp, err := client.DebuggerSetWatchpoint(context.Background(), "lv_status", "LV_STATUS = 'E'")
+	if err != nil {
+		t.Fatalf("DebuggerSetWatchpoint failed: %v", err)
+	}
+	if wp.ID != "WP1" || wp.Variable != "LV_STATUS" {
+		t.Errorf("unexpected watchpoint: %+v", wp)
+	}
+
+	if _, err := client.DebuggerSetWatchpoint(context.Background(), "", ""); err == nil {
+		t.Error("expected error for empty variable")
+	}
+}
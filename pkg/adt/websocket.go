@@ -19,7 +19,11 @@ type DebugWebSocketClient struct {
 	isAttached bool
 	debuggeeID string
 
-	// Event channel for async events (debuggee caught, etc.)
+	// Client-side breakpoint modifiers (conditions, hit counts, log points)
+	breakpoints *breakpointTracker
+	onLogPoint  func(LogPointEntry)
+
+	// Event channel for async events (debuggee caught, log point hit, etc.)
 	Events chan *DebugEvent
 }
 
@@ -64,6 +68,7 @@ type WSDebugVariable struct {
 func NewDebugWebSocketClient(baseURL, client, user, password string, insecure bool) *DebugWebSocketClient {
 	c := &DebugWebSocketClient{
 		BaseWebSocketClient: NewBaseWebSocketClient(baseURL, client, user, password, insecure),
+		breakpoints:         newBreakpointTracker(),
 		Events:              make(chan *DebugEvent, 10),
 	}
 
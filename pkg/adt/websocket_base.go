@@ -25,6 +25,7 @@ type BaseWebSocketClient struct {
 
 	conn      *websocket.Conn
 	sessionID string
+	version   string // ZADT_VSP version from the welcome message
 	mu        sync.RWMutex
 
 	// Request/response handling
@@ -141,6 +142,13 @@ func (c *BaseWebSocketClient) GetUser() string {
 	return c.user
 }
 
+// Version returns the ZADT_VSP version announced by the welcome message.
+func (c *BaseWebSocketClient) Version() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.version
+}
+
 // readMessages reads messages from WebSocket and routes them.
 func (c *BaseWebSocketClient) readMessages() {
 	for {
@@ -192,6 +200,7 @@ func (c *BaseWebSocketClient) readMessages() {
 			if err := json.Unmarshal(resp.Data, &welcomeData); err == nil {
 				c.mu.Lock()
 				c.sessionID = welcomeData.Session
+				c.version = welcomeData.Version
 				c.mu.Unlock()
 			}
 			select {
@@ -0,0 +1,428 @@
+package adt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Conditional, Hit-Count and Log-Point Breakpoints ---
+//
+// ZADT_VSP registers plain TPDAPI breakpoints. Conditions, hit counts and log
+// points of line breakpoints are layered on top client-side: when a debuggee
+// stops, the client matches the position to a registered line breakpoint,
+// evaluates its modifiers and decides whether to stop or to log and continue.
+// Stops of statement and exception breakpoints and of watchpoints cannot be
+// told apart from other stops, so they always stop; watchpoint conditions are
+// evaluated by the ABAP debugger.
+
+// BreakpointOptions are optional modifiers for WebSocket breakpoints.
+type BreakpointOptions struct {
+	// Condition is an ABAP-like logical expression evaluated when the breakpoint is hit.
+	// Supported: comparisons (=, <>, <, >, <=, >=, EQ, NE, LT, GT, LE, GE),
+	// IS [NOT] INITIAL, joined by AND / OR (AND binds tighter). No parentheses.
+	// Example: "LV_COUNT > 10 AND LS_DATA-STATUS = 'E'"
+	Condition string `json:"condition,omitempty"`
+
+	// HitCount skips the first HitCount-1 qualifying hits and stops from the
+	// HitCount-th hit on (0 or 1 = every hit). Hits only count when Condition holds.
+	HitCount int `json:"hitCount,omitempty"`
+
+	// LogExpressions turns the breakpoint into a log point: the expressions are
+	// evaluated on every qualifying hit and execution continues automatically.
+	LogExpressions []string `json:"logExpressions,omitempty"`
+}
+
+// IsLogPoint returns true if the breakpoint logs values instead of stopping.
+func (o BreakpointOptions) IsLogPoint() bool {
+	return len(o.LogExpressions) > 0
+}
+
+// IsEmpty returns true if no modifier is set.
+func (o BreakpointOptions) IsEmpty() bool {
+	return o.Condition == "" && o.HitCount <= 1 && len(o.LogExpressions) == 0
+}
+
+// BreakpointAction is the decision taken for a breakpoint hit.
+type BreakpointAction string
+
+const (
+	BreakpointActionStop BreakpointAction = "stop" // Suspend and hand control to the user
+	BreakpointActionSkip BreakpointAction = "skip" // Condition false or hit count not reached
+	BreakpointActionLog  BreakpointAction = "log"  // Log point: evaluate, report, continue
+)
+
+// BreakpointHit describes how a debuggee stop was resolved against registered breakpoints.
+type BreakpointHit struct {
+	BreakpointID string           `json:"breakpointId,omitempty"`
+	Hits         int              `json:"hits"`
+	Action       BreakpointAction `json:"action"`
+	Frame        *DebugStackFrame `json:"frame,omitempty"`
+}
+
+// LogPointEntry is one evaluation of a log point.
+type LogPointEntry struct {
+	BreakpointID string            `json:"breakpointId"`
+	Program      string            `json:"program"`
+	Include      string            `json:"include,omitempty"`
+	Line         int               `json:"line"`
+	Hit          int               `json:"hit"`
+	Values       map[string]string `json:"values"`
+	Expressions  []string          `json:"expressions"` // Evaluation order of Values
+	Time         time.Time         `json:"time"`
+}
+
+// String formats the entry as a single log line.
+func (e LogPointEntry) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[%s] %s:%d #%d", e.BreakpointID, e.Program, e.Line, e.Hit)
+	for _, expr := range e.Expressions {
+		fmt.Fprintf(&sb, " %s=%s", expr, e.Values[expr])
+	}
+	return sb.String()
+}
+
+// trackedBreakpoint is the client-side state of a registered breakpoint.
+type trackedBreakpoint struct {
+	id      string
+	kind    string
+	program string
+	method  string
+	line    int
+	opts    BreakpointOptions
+	hits    int
+
+	// Watchpoints
+	variable string
+	value    string
+}
+
+// positional returns true if the breakpoint can be matched by program and line.
+func (b *trackedBreakpoint) positional() bool {
+	return b.kind == "line" && b.line > 0
+}
+
+// matches returns true if the stack frame is at this breakpoint's location.
+// Frame lines are include-relative. A method include starts with the METHOD
+// statement, so method breakpoints (line 1 = METHOD) compare the procedure
+// and the include line.
+func (b *trackedBreakpoint) matches(frame *DebugStackFrame) bool {
+	if !b.positional() || frame == nil || frame.Line != b.line {
+		return false
+	}
+	prog := strings.ToUpper(b.program)
+	if strings.ToUpper(frame.Program) != prog && strings.ToUpper(frame.Include) != prog {
+		return false
+	}
+	if b.method == "" {
+		return true
+	}
+	procedure := frame.Procedure
+	if i := strings.LastIndex(procedure, "=>"); i >= 0 {
+		procedure = procedure[i+2:]
+	}
+	return strings.EqualFold(strings.TrimSpace(procedure), b.method)
+}
+
+// breakpointTracker keeps client-side breakpoint modifiers and hit counters.
+type breakpointTracker struct {
+	mu  sync.Mutex
+	bps map[string]*trackedBreakpoint
+}
+
+func newBreakpointTracker() *breakpointTracker {
+	return &breakpointTracker{bps: make(map[string]*trackedBreakpoint)}
+}
+
+func (t *breakpointTracker) track(bp *trackedBreakpoint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.bps[bp.id] = bp
+}
+
+func (t *breakpointTracker) forget(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.bps, id)
+}
+
+// options returns a breakpoint's modifiers and current hit counter.
+func (t *breakpointTracker) options(id string) (BreakpointOptions, int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	bp, ok := t.bps[id]
+	if !ok {
+		return BreakpointOptions{}, 0, false
+	}
+	return bp.opts, bp.hits, true
+}
+
+// watch returns the watchpoint state of a breakpoint, if it is a watchpoint.
+func (t *breakpointTracker) watch(id string) (trackedBreakpoint, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	bp, ok := t.bps[id]
+	if !ok || bp.kind != "watchpoint" {
+		return trackedBreakpoint{}, false
+	}
+	return *bp, true
+}
+
+// match finds the line breakpoint registered at frame. Stops anywhere else
+// belong to no tracked breakpoint, even if statement, exception or watchpoint
+// breakpoints are registered: their stops carry no reason to match on.
+func (t *breakpointTracker) match(frame *DebugStackFrame) *trackedBreakpoint {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, bp := range t.bps {
+		if bp.matches(frame) {
+			return bp
+		}
+	}
+	return nil
+}
+
+// recordHit applies hit-count and log-point rules to a hit whose condition held.
+func (t *breakpointTracker) recordHit(bp *trackedBreakpoint) (int, BreakpointAction) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	bp.hits++
+	if bp.opts.HitCount > 1 && bp.hits < bp.opts.HitCount {
+		return bp.hits, BreakpointActionSkip
+	}
+	if bp.opts.IsLogPoint() {
+		return bp.hits, BreakpointActionLog
+	}
+	return bp.hits, BreakpointActionStop
+}
+
+// --- Condition evaluation ---
+
+// conditionComparison is a single "lhs op rhs" term of a breakpoint condition.
+type conditionComparison struct {
+	lhs string
+	op  string // normalized: =, <>, <, >, <=, >=, INITIAL, NOT INITIAL
+	rhs string
+}
+
+// parsedCondition is a condition in disjunctive form: OR of AND-groups.
+type parsedCondition [][]conditionComparison
+
+var conditionOperators = map[string]string{
+	"=": "=", "EQ": "=",
+	"<>": "<>", "NE": "<>",
+	"<": "<", "LT": "<",
+	">": ">", "GT": ">",
+	"<=": "<=", "LE": "<=",
+	">=": ">=", "GE": ">=",
+}
+
+// parseBreakpointCondition parses a breakpoint condition into OR-of-AND groups.
+func parseBreakpointCondition(cond string) (parsedCondition, error) {
+	tokens, err := tokenizeCondition(cond)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+
+	var result parsedCondition
+	var group []conditionComparison
+	i := 0
+	for i < len(tokens) {
+		if len(tokens)-i < 3 {
+			return nil, fmt.Errorf("incomplete comparison near %q", strings.Join(tokens[i:], " "))
+		}
+		lhs := tokens[i]
+		var cmp conditionComparison
+
+		upper1 := strings.ToUpper(tokens[i+1])
+		switch {
+		case upper1 == "IS" && strings.ToUpper(tokens[i+2]) == "INITIAL":
+			cmp = conditionComparison{lhs: lhs, op: "INITIAL"}
+			i += 3
+		case upper1 == "IS" && strings.ToUpper(tokens[i+2]) == "NOT" && i+3 < len(tokens) && strings.ToUpper(tokens[i+3]) == "INITIAL":
+			cmp = conditionComparison{lhs: lhs, op: "NOT INITIAL"}
+			i += 4
+		default:
+			op, ok := conditionOperators[upper1]
+			if !ok {
+				return nil, fmt.Errorf("unsupported operator %q", tokens[i+1])
+			}
+			cmp = conditionComparison{lhs: lhs, op: op, rhs: tokens[i+2]}
+			i += 3
+		}
+		group = append(group, cmp)
+
+		if i == len(tokens) {
+			break
+		}
+		switch strings.ToUpper(tokens[i]) {
+		case "AND":
+		case "OR":
+			result = append(result, group)
+			group = nil
+		default:
+			return nil, fmt.Errorf("expected AND/OR, got %q", tokens[i])
+		}
+		i++
+		if i == len(tokens) {
+			return nil, fmt.Errorf("condition ends with a connective")
+		}
+	}
+	result = append(result, group)
+	return result, nil
+}
+
+// tokenizeCondition splits a condition into operands, operators and connectives.
+// Quoted literals ('text' or `text`) are kept as a single token including quotes.
+func tokenizeCondition(cond string) ([]string, error) {
+	var tokens []string
+	i := 0
+	for i < len(cond) {
+		ch := cond[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			i++
+		case ch == '\'' || ch == '`':
+			end := strings.IndexByte(cond[i+1:], ch)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated literal at position %d", i)
+			}
+			tokens = append(tokens, cond[i:i+end+2])
+			i += end + 2
+		case ch == '<' || ch == '>' || ch == '=':
+			if i+1 < len(cond) && (cond[i+1] == '=' || (ch == '<' && cond[i+1] == '>')) {
+				tokens = append(tokens, cond[i:i+2])
+				i += 2
+			} else {
+				tokens = append(tokens, cond[i:i+1])
+				i++
+			}
+		default:
+			start := i
+			for i < len(cond) && !strings.ContainsRune(" \t\n<>='`", rune(cond[i])) {
+				i++
+			}
+			tokens = append(tokens, cond[start:i])
+		}
+	}
+	return tokens, nil
+}
+
+// evaluate evaluates the condition using lookup to resolve variable operands.
+func (p parsedCondition) evaluate(lookup func(name string) (string, error)) (bool, error) {
+	for _, group := range p {
+		all := true
+		for _, cmp := range group {
+			ok, err := cmp.evaluate(lookup)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c conditionComparison) evaluate(lookup func(name string) (string, error)) (bool, error) {
+	left, err := resolveConditionOperand(c.lhs, lookup)
+	if err != nil {
+		return false, err
+	}
+
+	switch c.op {
+	case "INITIAL":
+		return isInitialValue(left), nil
+	case "NOT INITIAL":
+		return !isInitialValue(left), nil
+	}
+
+	right, err := resolveConditionOperand(c.rhs, lookup)
+	if err != nil {
+		return false, err
+	}
+
+	var cmp int
+	lf, lerr := strconv.ParseFloat(left, 64)
+	rf, rerr := strconv.ParseFloat(right, 64)
+	if lerr == nil && rerr == nil {
+		switch {
+		case lf < rf:
+			cmp = -1
+		case lf > rf:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(left, right)
+	}
+
+	switch c.op {
+	case "=":
+		return cmp == 0, nil
+	case "<>":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case ">":
+		return cmp > 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return false, fmt.Errorf("unsupported operator %q", c.op)
+}
+
+// resolveConditionOperand returns the literal value of a quoted or numeric
+// operand, or looks the operand up as a variable.
+func resolveConditionOperand(operand string, lookup func(name string) (string, error)) (string, error) {
+	if isQuotedConditionLiteral(operand) {
+		return unquoteConditionLiteral(operand), nil
+	}
+	if _, err := strconv.ParseFloat(operand, 64); err == nil {
+		return operand, nil
+	}
+	value, err := lookup(operand)
+	if err != nil {
+		return "", fmt.Errorf("evaluate %s: %w", operand, err)
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// isQuotedConditionLiteral reports whether s is a 'text' or `text` literal.
+func isQuotedConditionLiteral(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '`') && s[len(s)-1] == s[0]
+}
+
+// unquoteConditionLiteral strips literal quotes; unquoted values are returned trimmed.
+func unquoteConditionLiteral(s string) string {
+	s = strings.TrimSpace(s)
+	if isQuotedConditionLiteral(s) {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// isInitialValue reports whether a debugger value display is the ABAP initial value.
+func isInitialValue(value string) bool {
+	v := strings.TrimSpace(value)
+	if v == "" {
+		return true
+	}
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		return f == 0
+	}
+	return strings.Trim(v, "0") == "" // Initial dates/times (00000000, 000000)
+}
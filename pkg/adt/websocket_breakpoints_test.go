@@ -0,0 +1,258 @@
+package adt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func conditionLookup(vars map[string]string) func(string) (string, error) {
+	return func(name string) (string, error) {
+		v, ok := vars[strings.ToUpper(name)]
+		if !ok {
+			return "", fmt.Errorf("unknown variable %s", name)
+		}
+		return v, nil
+	}
+}
+
+func TestParseBreakpointCondition(t *testing.T) {
+	tests := []struct {
+		cond    string
+		groups  int
+		wantErr bool
+	}{
+		{"LV_COUNT > 10", 1, false},
+		{"lv_count GT 10 AND ls_data-status = 'E'", 1, false},
+		{"A = 1 OR B = 2 AND C = 3", 2, false},
+		{"LV_NAME IS INITIAL", 1, false},
+		{"LV_NAME IS NOT INITIAL OR LV_X <> `abc`", 2, false},
+		{"", 0, true},
+		{"LV_COUNT >", 0, true},
+		{"LV_COUNT LIKE 5", 0, true},
+		{"A = 1 AND", 0, true},
+		{"A = 1 B = 2", 0, true},
+		{"A = 'unterminated", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cond, func(t *testing.T) {
+			parsed, err := parseBreakpointCondition(tt.cond)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error for %q", tt.cond)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(parsed) != tt.groups {
+				t.Errorf("got %d OR-groups, want %d", len(parsed), tt.groups)
+			}
+		})
+	}
+}
+
+func TestBreakpointConditionEvaluate(t *testing.T) {
+	vars := map[string]string{
+		"LV_COUNT":       "42",
+		"LS_DATA-STATUS": "E",
+		"LV_EMPTY":       "  ",
+		"LV_DATE":        "00000000",
+		"LV_LIMIT":       "100",
+	}
+
+	tests := []struct {
+		cond string
+		want bool
+	}{
+		{"LV_COUNT > 10", true},
+		{"LV_COUNT >= 42", true},
+		{"LV_COUNT LT 42", false},
+		{"LV_COUNT = 42.0", true},
+		{"LV_COUNT < LV_LIMIT", true},
+		{"LS_DATA-STATUS = 'E'", true},
+		{"LS_DATA-STATUS NE 'E'", false},
+		{"LV_EMPTY IS INITIAL", true},
+		{"LV_DATE IS INITIAL", true},
+		{"LV_COUNT IS NOT INITIAL", true},
+		{"LV_COUNT > 100 OR LS_DATA-STATUS = 'E'", true},
+		{"LV_COUNT > 10 AND LS_DATA-STATUS = 'W'", false},
+		{"LV_COUNT > 100 OR LV_COUNT < 50 AND LS_DATA-STATUS = 'E'", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cond, func(t *testing.T) {
+			parsed, err := parseBreakpointCondition(tt.cond)
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+			got, err := parsed.evaluate(conditionLookup(vars))
+			if err != nil {
+				t.Fatalf("evaluate failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("evaluate(%q) = %v, want %v", tt.cond, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreakpointConditionEvaluate_UnknownVariable(t *testing.T) {
+	parsed, err := parseBreakpointCondition("LV_MISSING = 1")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if _, err := parsed.evaluate(conditionLookup(nil)); err == nil {
+		t.Error("expected error for unknown variable")
+	}
+}
+
+func TestBreakpointTracker_HitCount(t *testing.T) {
+	tracker := newBreakpointTracker()
+	bp := &trackedBreakpoint{id: "BP1", kind: "line", program: "ZTEST", line: 10, opts: BreakpointOptions{HitCount: 3}}
+	tracker.track(bp)
+
+	want := []BreakpointAction{BreakpointActionSkip, BreakpointActionSkip, BreakpointActionStop, BreakpointActionStop}
+	for i, w := range want {
+		hits, action := tracker.recordHit(bp)
+		if hits != i+1 {
+			t.Errorf("hit %d: hits = %d", i+1, hits)
+		}
+		if action != w {
+			t.Errorf("hit %d: action = %s, want %s", i+1, action, w)
+		}
+	}
+}
+
+func TestBreakpointTracker_LogPoint(t *testing.T) {
+	tracker := newBreakpointTracker()
+	bp := &trackedBreakpoint{id: "BP1", kind: "line", program: "ZTEST", line: 10,
+		opts: BreakpointOptions{HitCount: 2, LogExpressions: []string{"LV_X"}}}
+	tracker.track(bp)
+
+	if _, action := tracker.recordHit(bp); action != BreakpointActionSkip {
+		t.Errorf("first hit: action = %s, want skip", action)
+	}
+	if _, action := tracker.recordHit(bp); action != BreakpointActionLog {
+		t.Errorf("second hit: action = %s, want log", action)
+	}
+}
+
+func TestBreakpointTracker_Match(t *testing.T) {
+	tracker := newBreakpointTracker()
+	tracker.track(&trackedBreakpoint{id: "LINE", kind: "line", program: "ZCL_TEST================CP", line: 5})
+	tracker.track(&trackedBreakpoint{id: "METH", kind: "line", program: "ZCL_TEST================CP", method: "calc", line: 3})
+	tracker.track(&trackedBreakpoint{id: "STMT", kind: "statement"})
+
+	tests := []struct {
+		name  string
+		frame *DebugStackFrame
+		want  string
+	}{
+		{"program match", &DebugStackFrame{Program: "ZCL_TEST================CP", Line: 5}, "LINE"},
+		{"include match", &DebugStackFrame{Program: "SAPLZ", Include: "zcl_test================cp", Line: 5}, "LINE"},
+		{"method match", &DebugStackFrame{Program: "ZCL_TEST================CP", Include: "ZCL_TEST================CM001", Procedure: "ZCL_TEST=>CALC", Line: 3}, "METH"},
+		{"other method", &DebugStackFrame{Program: "ZCL_TEST================CP", Include: "ZCL_TEST================CM002", Procedure: "RUN", Line: 3}, ""},
+		// An unregistered stop (e.g., a SAP GUI breakpoint) is never attributed to the statement breakpoint
+		{"other line", &DebugStackFrame{Program: "ZCL_TEST================CP", Line: 6}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := tracker.match(tt.frame)
+			got := ""
+			if bp != nil {
+				got = bp.id
+			}
+			if got != tt.want {
+				t.Errorf("match = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	tracker.forget("LINE")
+	if _, _, ok := tracker.options("LINE"); ok {
+		t.Error("forgotten breakpoint still tracked")
+	}
+}
+
+func TestSetBreakpointOptionsByKind(t *testing.T) {
+	client := NewDebugWebSocketClient("http://localhost", "001", "user", "pass", false)
+	ctx := context.Background()
+	for _, spec := range []WSBreakpointSpec{
+		{Kind: "statement", Statement: "SELECT", Options: BreakpointOptions{Condition: "LV_I > 1"}},
+		{Kind: "exception", Exception: "CX_SY_ZERODIVIDE", Options: BreakpointOptions{HitCount: 2}},
+		{Kind: "watchpoint", Variable: "LV_I", Options: BreakpointOptions{LogExpressions: []string{"LV_I"}}},
+		{Kind: "line", Program: "ZTEST", Line: 1, Options: BreakpointOptions{Condition: "LV_I >"}},
+	} {
+		// Rejected before anything is sent to the (unconnected) ZADT_VSP
+		if _, err := client.SetBreakpoint(ctx, spec); err == nil || strings.Contains(err.Error(), "connect") {
+			t.Errorf("expected %s breakpoint with %+v to be rejected, got %v", spec.Kind, spec.Options, err)
+		}
+	}
+}
+
+func TestLogPointEntryString(t *testing.T) {
+	entry := LogPointEntry{
+		BreakpointID: "BP1",
+		Program:      "ZTEST",
+		Line:         42,
+		Hit:          3,
+		Expressions:  []string{"LV_A", "LV_B"},
+		Values:       map[string]string{"LV_A": "1", "LV_B": "X"},
+	}
+	want := "[BP1] ZTEST:42 #3 LV_A=1 LV_B=X"
+	if got := entry.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+// testBreakpointTarget replays stops of a debuggee whose LV_I counts up.
+type testBreakpointTarget struct {
+	frames    []*DebugStackFrame
+	i         int
+	continues int
+}
+
+func (t *testBreakpointTarget) EvaluateBreakpointExpression(ctx context.Context, expression string) (string, error) {
+	if strings.ToUpper(expression) != "LV_I" {
+		return "", fmt.Errorf("unknown variable %s", expression)
+	}
+	return fmt.Sprint(t.i + 1), nil
+}
+
+func (t *testBreakpointTarget) ContinueToNextStop(ctx context.Context) (*DebugStackFrame, error) {
+	t.continues++
+	t.i++
+	if t.i >= len(t.frames) {
+		return nil, nil
+	}
+	return t.frames[t.i], nil
+}
+
+func TestContinueToStopIn(t *testing.T) {
+	frame := &DebugStackFrame{Program: "ZTEST", Line: 10}
+	client := NewDebugWebSocketClient("http://localhost", "001", "user", "pass", false)
+	client.breakpoints.track(&trackedBreakpoint{id: "BP1", kind: "line", program: "ZTEST", line: 10,
+		opts: BreakpointOptions{Condition: "LV_I >= 3", HitCount: 2}})
+
+	// Stops 1-3 fail the condition, stop 4 is the first counted hit, stop 5 stops
+	target := &testBreakpointTarget{frames: []*DebugStackFrame{frame, frame, frame, frame, frame, frame}}
+	hit, err := client.ContinueToStopIn(context.Background(), target, frame)
+	if err != nil {
+		t.Fatalf("ContinueToStopIn failed: %v", err)
+	}
+	if hit == nil || hit.BreakpointID != "BP1" || hit.Hits != 2 || target.i != 3 || target.continues != 3 {
+		t.Errorf("hit = %+v after %d continues", hit, target.continues)
+	}
+
+	// A debuggee that ends before the condition holds does not stop
+	client.breakpoints.track(&trackedBreakpoint{id: "BP1", kind: "line", program: "ZTEST", line: 10,
+		opts: BreakpointOptions{Condition: "LV_I > 10"}})
+	target = &testBreakpointTarget{frames: []*DebugStackFrame{frame, frame}}
+	if hit, err := client.ContinueToStopIn(context.Background(), target, frame); hit != nil || err != nil {
+		t.Errorf("expected the debuggee to end, got %+v, %v", hit, err)
+	}
+}
@@ -2,13 +2,105 @@ package adt
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // --- Breakpoint Operations ---
 
+// WSBreakpointSpec describes a breakpoint to register via ZADT_VSP.
+type WSBreakpointSpec struct {
+	Kind      string // "line" (default), "statement", "exception", "watchpoint"
+	Program   string // Line breakpoints: program or class pool (ZCL_TEST================CP)
+	Method    string // Line breakpoints: method name for include-relative line numbers
+	Line      int    // Line breakpoints: line number
+	Statement string // Statement breakpoints: ABAP statement (e.g., "CALL FUNCTION")
+	Exception string // Exception breakpoints: exception class (e.g., "CX_SY_ZERODIVIDE")
+	Variable  string // Watchpoints: variable to watch (e.g., "LV_STATUS", "ME->MV_STATE")
+	Value     string // Watchpoints: only stop when the variable changes to this value
+
+	// Options are the modifiers (condition, hit count, log point) of line
+	// breakpoints; watchpoints take a condition only.
+	Options BreakpointOptions
+}
+
+// SetBreakpoint registers a breakpoint described by spec and returns its ID.
+// Conditions, hit counts and log points of line breakpoints are tracked
+// client-side and applied by ResolveBreakpointHit / ContinueToStop when a
+// debuggee stops. Watchpoint conditions and values are sent to ZADT_VSP and
+// evaluated by the ABAP debugger only.
+func (c *DebugWebSocketClient) SetBreakpoint(ctx context.Context, spec WSBreakpointSpec) (string, error) {
+	if spec.Kind == "" {
+		spec.Kind = "line"
+	}
+	switch spec.Kind {
+	case "line":
+		if spec.Options.Condition != "" {
+			if _, err := parseBreakpointCondition(spec.Options.Condition); err != nil {
+				return "", fmt.Errorf("invalid condition: %w", err)
+			}
+		}
+	case "watchpoint":
+		if err := CheckWatchpointOptions(spec.Options); err != nil {
+			return "", err
+		}
+	default:
+		if !spec.Options.IsEmpty() {
+			return "", fmt.Errorf("%s breakpoints support no condition, hit count or log expressions", spec.Kind)
+		}
+	}
+
+	params := map[string]any{
+		"kind": spec.Kind,
+	}
+	switch spec.Kind {
+	case "line":
+		params["program"] = spec.Program
+		params["line"] = spec.Line
+		if spec.Method != "" {
+			params["method"] = spec.Method
+		}
+	case "statement":
+		params["statement"] = spec.Statement
+	case "exception":
+		params["exception"] = spec.Exception
+	case "watchpoint":
+		// Watchpoints are session-bound: ZADT_VSP requires an attached debuggee
+		if spec.Variable == "" {
+			return "", fmt.Errorf("variable is required for watchpoints")
+		}
+		params["variable"] = strings.ToUpper(spec.Variable)
+		// The ABAP debugger evaluates watchpoint conditions (TPDAPI i_condition)
+		if cond := WatchpointCondition(strings.ToUpper(spec.Variable), spec.Value, spec.Options.Condition); cond != "" {
+			params["condition"] = cond
+		}
+	default:
+		return "", fmt.Errorf("unsupported breakpoint kind: %s", spec.Kind)
+	}
+
+	bpID, err := c.setBreakpointInternal(ctx, params)
+	if err != nil {
+		return "", err
+	}
+
+	c.breakpoints.track(&trackedBreakpoint{
+		id:       bpID,
+		kind:     spec.Kind,
+		program:  spec.Program,
+		method:   spec.Method,
+		line:     spec.Line,
+		variable: strings.ToUpper(spec.Variable),
+		value:    spec.Value,
+		opts:     spec.Options,
+	})
+	return bpID, nil
+}
+
 // setBreakpointInternal sends a breakpoint request and parses the response.
 func (c *DebugWebSocketClient) setBreakpointInternal(ctx context.Context, params map[string]any) (string, error) {
 	resp, err := c.sendRequest(ctx, "setBreakpoint", params)
@@ -38,12 +130,7 @@ func (c *DebugWebSocketClient) setBreakpointInternal(ctx context.Context, params
 // For classes, program should be in class pool format: ZCL_TEST================CP
 // The line number is pool-absolute (the line in the consolidated class source).
 func (c *DebugWebSocketClient) SetLineBreakpoint(ctx context.Context, program string, line int) (string, error) {
-	params := map[string]any{
-		"kind":    "line",
-		"program": program,
-		"line":    line,
-	}
-	return c.setBreakpointInternal(ctx, params)
+	return c.SetBreakpoint(ctx, WSBreakpointSpec{Kind: "line", Program: program, Line: line})
 }
 
 // SetMethodBreakpoint sets a breakpoint at a specific line within a method.
@@ -51,33 +138,31 @@ func (c *DebugWebSocketClient) SetLineBreakpoint(ctx context.Context, program st
 // The method name is used to resolve the correct include for the breakpoint.
 // Example: SetMethodBreakpoint(ctx, "ZCL_TEST================CP", "MY_METHOD", 5)
 func (c *DebugWebSocketClient) SetMethodBreakpoint(ctx context.Context, program, method string, line int) (string, error) {
-	params := map[string]any{
-		"kind":    "line",
-		"program": program,
-		"method":  method,
-		"line":    line,
-	}
-	return c.setBreakpointInternal(ctx, params)
+	return c.SetBreakpoint(ctx, WSBreakpointSpec{Kind: "line", Program: program, Method: method, Line: line})
 }
 
 // SetStatementBreakpoint sets a breakpoint on a specific ABAP statement.
 // Example statements: "CALL FUNCTION", "SELECT", "LOOP", "CALL METHOD"
 func (c *DebugWebSocketClient) SetStatementBreakpoint(ctx context.Context, statement string) (string, error) {
-	params := map[string]any{
-		"kind":      "statement",
-		"statement": statement,
-	}
-	return c.setBreakpointInternal(ctx, params)
+	return c.SetBreakpoint(ctx, WSBreakpointSpec{Kind: "statement", Statement: statement})
 }
 
 // SetExceptionBreakpoint sets a breakpoint that triggers when an exception is raised.
 // Example exceptions: "CX_SY_ZERODIVIDE", "CX_SY_OPEN_SQL_DB"
 func (c *DebugWebSocketClient) SetExceptionBreakpoint(ctx context.Context, exception string) (string, error) {
-	params := map[string]any{
-		"kind":      "exception",
-		"exception": exception,
-	}
-	return c.setBreakpointInternal(ctx, params)
+	return c.SetBreakpoint(ctx, WSBreakpointSpec{Kind: "exception", Exception: exception})
+}
+
+// SetWatchpoint sets a watchpoint that stops when variable changes.
+// If value is non-empty, only changes to that value stop. Requires an attached debuggee.
+// Example: SetWatchpoint(ctx, "ME->MV_STATUS", "E", BreakpointOptions{})
+func (c *DebugWebSocketClient) SetWatchpoint(ctx context.Context, variable, value string, opts BreakpointOptions) (string, error) {
+	return c.SetBreakpoint(ctx, WSBreakpointSpec{Kind: "watchpoint", Variable: variable, Value: value, Options: opts})
+}
+
+// GetBreakpointOptions returns the client-side modifiers and hit counter of a breakpoint.
+func (c *DebugWebSocketClient) GetBreakpointOptions(breakpointID string) (BreakpointOptions, int, bool) {
+	return c.breakpoints.options(breakpointID)
 }
 
 // GetBreakpoints returns all active breakpoints.
@@ -101,6 +186,31 @@ func (c *DebugWebSocketClient) GetBreakpoints(ctx context.Context) ([]map[string
 		return nil, err
 	}
 
+	// Merge client-side modifiers tracked for this session
+	for _, bp := range result.Breakpoints {
+		id, _ := bp["id"].(string)
+		opts, hits, ok := c.breakpoints.options(id)
+		if !ok {
+			continue
+		}
+		bp["hits"] = hits
+		if wp, ok := c.breakpoints.watch(id); ok {
+			bp["variable"] = wp.variable
+			if wp.value != "" {
+				bp["value"] = wp.value
+			}
+		}
+		if opts.Condition != "" {
+			bp["condition"] = opts.Condition
+		}
+		if opts.HitCount > 1 {
+			bp["hitCount"] = opts.HitCount
+		}
+		if opts.IsLogPoint() {
+			bp["logExpressions"] = opts.LogExpressions
+		}
+	}
+
 	return result.Breakpoints, nil
 }
 
@@ -122,9 +232,329 @@ func (c *DebugWebSocketClient) DeleteBreakpoint(ctx context.Context, breakpointI
 		return fmt.Errorf("deleteBreakpoint failed")
 	}
 
+	c.breakpoints.forget(breakpointID)
 	return nil
 }
 
+// SetLogPointHandler registers a callback invoked for every log point hit.
+// Log points are also published on the Events channel (kind "logpoint") if it has room.
+func (c *DebugWebSocketClient) SetLogPointHandler(fn func(LogPointEntry)) {
+	c.mu.Lock()
+	c.onLogPoint = fn
+	c.mu.Unlock()
+}
+
+// BreakpointTarget is the stopped debuggee in which breakpoint modifiers are
+// evaluated: the WebSocket session itself, or a REST debug session attached
+// with DebuggerAttach (see Client.BreakpointTarget).
+type BreakpointTarget interface {
+	// EvaluateBreakpointExpression returns the display value of a variable
+	// or expression in the current stack frame.
+	EvaluateBreakpointExpression(ctx context.Context, expression string) (string, error)
+	// ContinueToNextStop resumes the debuggee and returns the frame it stops
+	// at next, or nil if it ended.
+	ContinueToNextStop(ctx context.Context) (*DebugStackFrame, error)
+}
+
+// EvaluateBreakpointExpression implements BreakpointTarget.
+func (c *DebugWebSocketClient) EvaluateBreakpointExpression(ctx context.Context, expression string) (string, error) {
+	return c.evaluate(ctx, expression)
+}
+
+// ContinueToNextStop implements BreakpointTarget.
+func (c *DebugWebSocketClient) ContinueToNextStop(ctx context.Context) (*DebugStackFrame, error) {
+	return c.Step(ctx, "continue")
+}
+
+// ResolveBreakpointHit matches a stop position of the WebSocket session to a
+// registered breakpoint; see ResolveBreakpointHitIn.
+func (c *DebugWebSocketClient) ResolveBreakpointHit(ctx context.Context, frame *DebugStackFrame) (*BreakpointHit, error) {
+	return c.ResolveBreakpointHitIn(ctx, c, frame)
+}
+
+// ResolveBreakpointHitIn matches a stop position to a registered breakpoint and
+// applies its condition, hit count and log-point settings, evaluating them in
+// target. Stops that match no registered breakpoint (e.g., breakpoints set
+// from SAP GUI) always stop. For log points the expressions are evaluated and
+// reported before returning.
+func (c *DebugWebSocketClient) ResolveBreakpointHitIn(ctx context.Context, target BreakpointTarget, frame *DebugStackFrame) (*BreakpointHit, error) {
+	bp := c.breakpoints.match(frame)
+	if bp == nil {
+		return &BreakpointHit{Action: BreakpointActionStop, Frame: frame}, nil
+	}
+
+	hit := &BreakpointHit{BreakpointID: bp.id, Frame: frame}
+
+	if bp.opts.Condition != "" {
+		cond, err := parseBreakpointCondition(bp.opts.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("breakpoint %s: invalid condition: %w", bp.id, err)
+		}
+		ok, err := cond.evaluate(func(name string) (string, error) {
+			return target.EvaluateBreakpointExpression(ctx, name)
+		})
+		if err != nil {
+			// Unevaluable condition: stop so the user can inspect, like SAP GUI does
+			hit.Action = BreakpointActionStop
+			return hit, fmt.Errorf("breakpoint %s: condition: %w", bp.id, err)
+		}
+		if !ok {
+			hit.Action = BreakpointActionSkip
+			return hit, nil
+		}
+	}
+
+	hit.Hits, hit.Action = c.breakpoints.recordHit(bp)
+
+	if hit.Action == BreakpointActionLog {
+		entry := LogPointEntry{
+			BreakpointID: bp.id,
+			Hit:          hit.Hits,
+			Values:       make(map[string]string, len(bp.opts.LogExpressions)),
+			Expressions:  bp.opts.LogExpressions,
+			Time:         time.Now(),
+		}
+		if frame != nil {
+			entry.Program = frame.Program
+			entry.Include = frame.Include
+			entry.Line = frame.Line
+		}
+		for _, expr := range bp.opts.LogExpressions {
+			value, err := target.EvaluateBreakpointExpression(ctx, expr)
+			if err != nil {
+				value = fmt.Sprintf("<error: %v>", err)
+			}
+			entry.Values[expr] = value
+		}
+		c.publishLogPoint(entry)
+	}
+
+	return hit, nil
+}
+
+// ContinueToStop resolves the stop at frame in the WebSocket session; see
+// ContinueToStopIn.
+func (c *DebugWebSocketClient) ContinueToStop(ctx context.Context, frame *DebugStackFrame) (*BreakpointHit, error) {
+	return c.ContinueToStopIn(ctx, c, frame)
+}
+
+// ContinueToStopIn resolves the stop at frame and keeps continuing target past
+// skipped hits and log points until a breakpoint really stops or the debuggee
+// ends. Returns nil if the debuggee ended without stopping.
+func (c *DebugWebSocketClient) ContinueToStopIn(ctx context.Context, target BreakpointTarget, frame *DebugStackFrame) (*BreakpointHit, error) {
+	for frame != nil {
+		hit, err := c.ResolveBreakpointHitIn(ctx, target, frame)
+		if err != nil {
+			return hit, err
+		}
+		if hit.Action == BreakpointActionStop {
+			return hit, nil
+		}
+
+		frame, err = target.ContinueToNextStop(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// publishLogPoint delivers a log point entry to the handler and Events channel.
+func (c *DebugWebSocketClient) publishLogPoint(entry LogPointEntry) {
+	c.mu.RLock()
+	handler := c.onLogPoint
+	c.mu.RUnlock()
+
+	if handler != nil {
+		handler(entry)
+	}
+
+	data := make(map[string]any, len(entry.Values)+1)
+	for k, v := range entry.Values {
+		data[k] = v
+	}
+	data["hit"] = entry.Hit
+
+	select {
+	case c.Events <- &DebugEvent{
+		Kind:    "logpoint",
+		Program: entry.Program,
+		Include: entry.Include,
+		Line:    entry.Line,
+		Data:    data,
+	}:
+	default:
+	}
+}
+
+// evaluate returns the debugger display value of a single expression
+// (variable, structure component, field symbol) in the current stack frame.
+func (c *DebugWebSocketClient) evaluate(ctx context.Context, expression string) (string, error) {
+	resp, err := c.sendRequest(ctx, "evaluate", map[string]any{
+		"expression": expression,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if !resp.Success {
+		if resp.Error != nil {
+			return "", fmt.Errorf("%s: %s", resp.Error.Code, resp.Error.Message)
+		}
+		return "", fmt.Errorf("evaluate failed")
+	}
+
+	var result struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return "", err
+	}
+
+	return result.Value, nil
+}
+
+// ReadTable reads a page of rows from an internal table of the attached
+// debuggee. Unlike Client.DebuggerReadTable, ZADT_VSP scans the table and
+// applies the filter in the debugger session, so only matching rows are
+// transferred. Structured rows need Columns; without them every row is
+// returned as TABLE_LINE. TotalLines is only set once the end of the table
+// was reached. Continue with NextOffset while HasMore is set.
+func (c *DebugWebSocketClient) ReadTable(ctx context.Context, table string, opts DebugTableReadOptions) (*DebugTablePage, error) {
+	params, err := readTableParams(table, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.sendRequest(ctx, "readTable", params)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		if resp.Error != nil {
+			return nil, fmt.Errorf("%s: %s", resp.Error.Code, resp.Error.Message)
+		}
+		return nil, fmt.Errorf("readTable failed")
+	}
+	return parseReadTableResult(resp.Data, params)
+}
+
+// tableFilterOps are the operator codes ZADT_VSP understands.
+var tableFilterOps = map[string]string{
+	"=": "EQ", "<>": "NE", "<": "LT", ">": "GT", "<=": "LE", ">=": "GE",
+	"INITIAL": "INITIAL", "NOT INITIAL": "NOTINITIAL",
+}
+
+// readTableParams builds the readTable request. The filter is parsed here
+// and sent as terms; table names and operands are hex-encoded UTF-8 since
+// ZADT_VSP reads parameters without JSON unescaping.
+func readTableParams(table string, opts DebugTableReadOptions) (map[string]any, error) {
+	name, err := NormalizeDebugExpression(table)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = debugTableDefaultLimit
+	}
+	if limit > debugTableMaxLimit {
+		limit = debugTableMaxLimit
+	}
+	maxScan := opts.MaxScan
+	if maxScan <= 0 {
+		maxScan = debugTableDefaultScan
+	}
+	params := map[string]any{
+		"table":   strings.ToUpper(hex.EncodeToString([]byte(name))),
+		"offset":  max(opts.Offset, 0),
+		"limit":   limit,
+		"maxScan": maxScan,
+	}
+
+	var columns []string
+	for _, col := range opts.Columns {
+		col = strings.ToUpper(strings.TrimSpace(col))
+		if !tableColumnName.MatchString(col) {
+			return nil, fmt.Errorf("invalid column name: %s", col)
+		}
+		columns = append(columns, col)
+	}
+	if len(columns) > 0 {
+		params["columns"] = strings.Join(columns, ",")
+	}
+
+	if strings.TrimSpace(opts.Filter) == "" {
+		return params, nil
+	}
+	filter, err := parseBreakpointCondition(opts.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	n := 0
+	for group, terms := range filter {
+		for _, term := range terms {
+			prefix := fmt.Sprintf("t%d_", n)
+			params[prefix+"group"] = group
+			params[prefix+"op"] = tableFilterOps[term.op]
+			for side, operand := range map[string]string{"left": term.lhs, "right": term.rhs} {
+				kind := "value"
+				value := operand
+				if operand != "" && !isQuotedConditionLiteral(operand) {
+					if _, err := strconv.ParseFloat(operand, 64); err != nil {
+						kind = "column"
+					}
+				}
+				if kind == "value" {
+					value = unquoteConditionLiteral(operand)
+				}
+				params[prefix+side] = strings.ToUpper(hex.EncodeToString([]byte(value)))
+				params[prefix+side+"Kind"] = kind
+			}
+			n++
+		}
+	}
+	params["terms"] = n
+	return params, nil
+}
+
+// tableColumnName matches a column (component) name of a table row.
+var tableColumnName = regexp.MustCompile(`^[A-Z0-9_/]+(-[A-Z0-9_/]+)*$`)
+
+// parseReadTableResult converts a readTable response into a table page.
+func parseReadTableResult(data []byte, params map[string]any) (*DebugTablePage, error) {
+	var result struct {
+		Table      string          `json:"table"`
+		Rows       []DebugTableRow `json:"rows"`
+		Scanned    int             `json:"scanned"`
+		NextOffset int             `json:"nextOffset"`
+		Ended      bool            `json:"ended"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parsing readTable response: %w", err)
+	}
+
+	page := &DebugTablePage{
+		Table:      result.Table,
+		Offset:     params["offset"].(int),
+		Columns:    []string{"TABLE_LINE"},
+		Rows:       result.Rows,
+		Scanned:    result.Scanned,
+		NextOffset: result.NextOffset,
+		HasMore:    !result.Ended,
+	}
+	if cols, ok := params["columns"].(string); ok {
+		page.Columns = strings.Split(cols, ",")
+	}
+	if page.Rows == nil {
+		page.Rows = []DebugTableRow{}
+	}
+	if result.Ended {
+		page.TotalLines = result.NextOffset
+	}
+	return page, nil
+}
+
 // --- Debugger Session Operations ---
 
 // Listen waits for a debuggee to hit a breakpoint.
@@ -869,6 +869,8 @@ func (c *Client) buildObjectURLWithParent(objType CreatableObjectType, name, par
 	// RAP object types
 	case ObjectTypeDDLS:
 		return fmt.Sprintf("/sap/bc/adt/ddic/ddl/sources/%s", encodedName), nil
+	case ObjectTypeDDLX:
+		return fmt.Sprintf("/sap/bc/adt/ddic/ddlx/sources/%s", encodedName), nil
 	case ObjectTypeBDEF:
 		return fmt.Sprintf("/sap/bc/adt/bo/behaviordefinitions/%s", encodedName), nil
 	case ObjectTypeSRVD:
@@ -1033,6 +1035,8 @@ func (c *Client) SaveToFile(ctx context.Context, objType CreatableObjectType, ob
 	// RAP object types (using ABAPGit-compatible extensions)
 	case ObjectTypeDDLS:
 		ext = ".ddls.asddls"
+	case ObjectTypeDDLX:
+		ext = ".ddlx.asddlxs"
 	case ObjectTypeBDEF:
 		ext = ".bdef.asbdef"
 	case ObjectTypeSRVD:
@@ -1312,6 +1316,12 @@ func (c *Client) EditSourceWithOptions(ctx context.Context, objectURL, oldString
 		NewString: newString,
 	}
 
+	// ABAP Cloud: the replacement may use released APIs only
+	if err := c.checkCloudSource(ctx, newString); err != nil {
+		result.Message = err.Error()
+		return result, nil
+	}
+
 	// Extract object name from URL for error messages
 	parts := strings.Split(objectURL, "/")
 	if len(parts) > 0 {
@@ -1936,6 +1946,7 @@ type GetSourceOptions struct {
 //   - FUGR: Function groups (name = function group name)
 //   - INCL: Includes (name = include name)
 //   - DDLS: CDS DDL sources (name = DDL source name)
+//   - DDLX: CDS metadata extensions (name = DDLX name)
 //   - VIEW: DDIC database views (name = view name) - classic SE11 views
 //   - BDEF: Behavior Definitions (name = BDEF name) - RAP behavior implementation
 //   - SRVD: Service Definitions (name = SRVD name) - RAP service exposure
@@ -1997,6 +2008,9 @@ func (c *Client) GetSource(ctx context.Context, objectType, name string, opts *G
 	case "DDLS":
 		return c.GetDDLS(ctx, name)
 
+	case "DDLX":
+		return c.GetDDLX(ctx, name)
+
 	case "VIEW":
 		return c.GetView(ctx, name)
 
@@ -2116,6 +2130,18 @@ func (c *Client) WriteSource(ctx context.Context, objectType, name, source strin
 		return result, nil
 	}
 
+	// ABAP Cloud: no classic programs, released APIs only
+	if c.CloudMode() {
+		if objectType == "PROG" {
+			result.Message = "ABAP Cloud: programs are not available, use a class implementing IF_OO_ADT_CLASSRUN"
+			return result, nil
+		}
+		if err := c.checkCloudSource(ctx, source+"\n"+opts.TestSource); err != nil {
+			result.Message = err.Error()
+			return result, nil
+		}
+	}
+
 	// Determine if object exists (for upsert mode)
 	objectExists := false
 	if opts.Mode == WriteModeUpsert {
@@ -2582,6 +2608,30 @@ type ExecuteABAPOptions struct {
 	// ProgramPrefix is the prefix for the temp program name.
 	// Default is "ZTEMP_EXEC_".
 	ProgramPrefix string
+
+	// ClassPrefix is the prefix for the temp class runner in ABAP Cloud mode.
+	// Default is "ZCL_VSP_EXEC_".
+	ClassPrefix string
+
+	// Package for the temp program or class. Default is "$TMP".
+	Package string
+
+	// Runner selects how the code is executed:
+	// - "program" (default): temp program with a unit test wrapper
+	// - "class": temp class implementing IF_OO_ADT_CLASSRUN (see RunClass)
+	// In ABAP Cloud mode the class runner is always used.
+	Runner string
+}
+
+// Runners of ExecuteABAP.
+const (
+	ExecuteRunnerProgram = "program"
+	ExecuteRunnerClass   = "class"
+)
+
+// usesClassRunner reports whether ExecuteABAP runs the code in a class runner.
+func (c *Client) usesClassRunner(opts *ExecuteABAPOptions) bool {
+	return c.CloudMode() || (opts != nil && strings.EqualFold(opts.Runner, ExecuteRunnerClass))
 }
 
 // ExecuteABAP executes arbitrary ABAP code via a temporary unit test wrapper.
@@ -2608,6 +2658,10 @@ type ExecuteABAPOptions struct {
 //	`, nil)
 //	// result.Output contains the assertion message with lv_result value
 //
+// With Runner "class", and always in ABAP Cloud mode (see CloudMode), the
+// code runs in a temporary IF_OO_ADT_CLASSRUN class instead, whose console
+// output is returned; in ABAP Cloud mode the code must use released APIs only.
+//
 // Security: This is gated by OpWorkflow safety check.
 func (c *Client) ExecuteABAP(ctx context.Context, code string, opts *ExecuteABAPOptions) (*ExecuteABAPResult, error) {
 	// Safety check for workflow operations
@@ -2627,6 +2681,18 @@ func (c *Client) ExecuteABAP(ctx context.Context, code string, opts *ExecuteABAP
 	if opts.ProgramPrefix == "" {
 		opts.ProgramPrefix = "ZTEMP_EXEC_"
 	}
+	if opts.Package == "" {
+		opts.Package = "$TMP"
+	}
+
+	switch strings.ToLower(opts.Runner) {
+	case "", ExecuteRunnerProgram, ExecuteRunnerClass:
+	default:
+		return nil, fmt.Errorf("unknown runner %q (use %s or %s)", opts.Runner, ExecuteRunnerProgram, ExecuteRunnerClass)
+	}
+	if c.usesClassRunner(opts) {
+		return c.executeABAPClassRunner(ctx, code, opts)
+	}
 
 	result := &ExecuteABAPResult{
 		Output: []string{},
@@ -2678,7 +2744,7 @@ ENDCLASS.
 		ObjectType:  ObjectTypeProgram,
 		Name:        programName,
 		Description: "Temp program for ExecuteABAP",
-		PackageName: "$TMP",
+		PackageName: opts.Package,
 	})
 	if err != nil {
 		result.Message = fmt.Sprintf("Failed to create temp program: %v", err)
@@ -2789,6 +2855,12 @@ ENDCLASS.
 //	`, nil)
 //	// result.Output contains one entry per client
 func (c *Client) ExecuteABAPMultiple(ctx context.Context, code string, opts *ExecuteABAPOptions) (*ExecuteABAPResult, error) {
+	// A class runner writes the results to the console
+	output := "cl_abap_unit_assert=>fail( msg = |EXEC_RESULT:{ lv_exec_result }| )."
+	if c.usesClassRunner(opts) {
+		output = "out->write( |EXEC_RESULT:{ lv_exec_result }| )."
+	}
+
 	// Wrap the code with a macro that chains assertions
 	wrappedCode := `
     DATA lt_exec_results TYPE string_table.
@@ -2803,7 +2875,7 @@ func (c *Client) ExecuteABAPMultiple(ctx context.Context, code string, opts *Exe
     DATA lv_idx TYPE i.
     LOOP AT lt_exec_results INTO DATA(lv_exec_result).
       lv_idx = lv_idx + 1.
-      cl_abap_unit_assert=>fail( msg = |EXEC_RESULT:{ lv_exec_result }| ).
+      ` + output + `
     ENDLOOP.
 
     " Mark completion
@@ -25,6 +25,9 @@ type SystemConfig struct {
 	// Optional safety settings per system
 	ReadOnly        bool     `json:"read_only,omitempty"`
 	AllowedPackages []string `json:"allowed_packages,omitempty"`
+
+	// UI5 deployment backend: "odata" (default) or "filestore"
+	UI5Backend string `json:"ui5_backend,omitempty"`
 }
 
 // SystemsConfig is the root configuration containing all systems.
@@ -137,10 +140,11 @@ func ExampleConfig() string {
 				Client: "001",
 			},
 			"a4h": {
-				URL:      "http://a4h.local:50000",
-				User:     "ADMIN",
-				Client:   "001",
-				Insecure: true,
+				URL:        "http://a4h.local:50000",
+				User:       "ADMIN",
+				Client:     "001",
+				Insecure:   true,
+				UI5Backend: "odata",
 			},
 			"prod": {
 				URL:             "https://prod.example.com:44300",
@@ -364,6 +364,39 @@ func (s *StageBuilder) SyntaxCheck(objectsVar string, saveAs string) *StageBuild
 	return s
 }
 
+// ATC adds an ATC check step.
+func (s *StageBuilder) ATC(objectsVar string, saveAs string) *StageBuilder {
+	s.steps = append(s.steps, Step{
+		Action:     "atc",
+		Parameters: map[string]interface{}{"objects": objectsVar},
+		SaveAs:     saveAs,
+	})
+	return s
+}
+
+// ATCWithBaseline adds an ATC check step that reports only findings not in
+// the baseline file (created on the first run).
+func (s *StageBuilder) ATCWithBaseline(objectsVar, baseline string, saveAs string) *StageBuilder {
+	s.steps = append(s.steps, Step{
+		Action:     "atc",
+		Parameters: map[string]interface{}{"objects": objectsVar, "baseline": baseline},
+		SaveAs:     saveAs,
+	})
+	return s
+}
+
+// FailIfNewATCFindings adds a fail condition for new ATC findings with
+// maxPriority or higher (1=error, 2=warning, 3=info).
+func (s *StageBuilder) FailIfNewATCFindings(resultsVar string, maxPriority int) *StageBuilder {
+	s.steps = append(s.steps, Step{
+		Action: "fail_if",
+		Parameters: map[string]interface{}{
+			"condition": fmt.Sprintf("atc_new_findings:%s:%d", resultsVar, maxPriority),
+		},
+	})
+	return s
+}
+
 // FailIfTestsFailed adds a fail condition for test failures.
 func (s *StageBuilder) FailIfTestsFailed(resultsVar string) *StageBuilder {
 	s.steps = append(s.steps, Step{
@@ -589,6 +622,22 @@ func CIPipeline(client *adt.Client, packagePattern string) *Pipeline {
 		Build()
 }
 
+// ATCGatePipeline creates a pipeline that fails on new ATC findings
+// relative to a baseline file.
+func ATCGatePipeline(client *adt.Client, packagePattern, baseline string, maxPriority int) *Pipeline {
+	return NewPipeline(client, "atc-gate").
+		Stage("discover").
+			Search(packagePattern, "objects").
+			Then().
+		Stage("atc").
+			DependsOn("discover").
+			ATCWithBaseline("objects", baseline, "atcResults").
+			FailIfNewATCFindings("atcResults", maxPriority).
+			Print("No new ATC findings").
+			Then().
+		Build()
+}
+
 // DeployPipeline creates a deployment pipeline (import → activate → test).
 func DeployPipeline(client *adt.Client, sourceDir, packageName string) *Pipeline {
 	return NewPipeline(client, "deploy").
@@ -2,7 +2,13 @@ package dsl
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestSearchBuilder(t *testing.T) {
@@ -209,6 +215,25 @@ func TestTestRunner(t *testing.T) {
 		}
 	})
 
+	t.Run("TargetedTests", func(t *testing.T) {
+		runner := Test(nil).
+			Tests(
+				"/sap/bc/adt/oo/classes/zcl_calc/includes/testclasses#type=CLAS%2FOLD;name=LTCL_CALC%20ADD",
+				"/sap/bc/adt/oo/classes/zcl_calc/includes/testclasses#type=CLAS%2FOLD;name=LTCL_CALC%20SUB",
+			).
+			RerunFailed()
+
+		if len(runner.objects) != 1 || runner.objects[0].URL != "/sap/bc/adt/oo/classes/zcl_calc" || runner.objects[0].Name != "ZCL_CALC" {
+			t.Errorf("unexpected objects: %+v", runner.objects)
+		}
+		if tests := runner.tests["/sap/bc/adt/oo/classes/zcl_calc"]; len(tests) != 2 {
+			t.Errorf("expected 2 tests for ZCL_CALC, got %v", tests)
+		}
+		if !runner.config.RerunFailed || runner.config.History == "" {
+			t.Errorf("RerunFailed should enable the history: %+v", runner.config)
+		}
+	})
+
 	t.Run("BuildObjectURL", func(t *testing.T) {
 		runner := Test(nil)
 
@@ -459,3 +484,102 @@ func TestObjectRef(t *testing.T) {
 		t.Errorf("expected name 'ZCL_TEST', got '%s'", obj.Name)
 	}
 }
+
+func TestWatcher(t *testing.T) {
+	t.Run("IsWatchedFile", func(t *testing.T) {
+		for name, want := range map[string]bool{
+			"src/zcl_foo.clas.abap":             true,
+			"src/zcl_foo.clas.testclasses.abap": true,
+			"src/zi_foo.ddls.asddls":            true,
+			"src/zcl_foo.clas.xml":              false,
+			"src/.zcl_foo.clas.abap.swp":        false,
+			"src/.#zcl_foo.clas.abap":           false,
+		} {
+			if got := IsWatchedFile(name); got != want {
+				t.Errorf("IsWatchedFile(%q) = %v, want %v", name, got, want)
+			}
+		}
+	})
+
+	t.Run("Debounce", func(t *testing.T) {
+		dir := t.TempDir()
+		var mu sync.Mutex
+		var processed []string
+		w := Watch(nil, dir).Debounce(50 * time.Millisecond)
+		w.process = func(ctx context.Context, path string) {
+			mu.Lock()
+			processed = append(processed, filepath.Base(path))
+			mu.Unlock()
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- w.Run(ctx) }()
+		time.Sleep(100 * time.Millisecond)
+
+		file := filepath.Join(dir, "zcl_foo.clas.abap")
+		for i := 0; i < 3; i++ {
+			if err := os.WriteFile(file, []byte("CLASS zcl_foo DEFINITION."), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(dir, "zcl_foo.clas.xml"), []byte("<xml/>"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			mu.Lock()
+			n := len(processed)
+			mu.Unlock()
+			if n > 0 {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		time.Sleep(150 * time.Millisecond)
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(processed) != 1 || processed[0] != "zcl_foo.clas.abap" {
+			t.Errorf("expected zcl_foo.clas.abap processed once, got %v", processed)
+		}
+	})
+
+	t.Run("NoLeakOnCancel", func(t *testing.T) {
+		dir := t.TempDir()
+		baseline := runtime.NumGoroutine()
+		w := Watch(nil, dir).Debounce(10 * time.Millisecond)
+		w.process = func(ctx context.Context, path string) { <-ctx.Done() }
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- w.Run(ctx) }()
+		time.Sleep(100 * time.Millisecond)
+
+		// More quiet files than the ready queue holds while one is processed
+		for i := 0; i < 100; i++ {
+			name := filepath.Join(dir, fmt.Sprintf("zcl_foo%03d.clas.abap", i))
+			if err := os.WriteFile(name, []byte("CLASS x DEFINITION."), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		time.Sleep(200 * time.Millisecond)
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		deadline := time.Now().Add(2 * time.Second)
+		for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+			time.Sleep(20 * time.Millisecond)
+		}
+		if n := runtime.NumGoroutine(); n > baseline {
+			t.Errorf("%d goroutines left after Run returned, want %d", n, baseline)
+		}
+	})
+}
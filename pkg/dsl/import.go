@@ -51,10 +51,11 @@ type ExportResult struct {
 
 // BatchExportResult represents the result of a batch export.
 type BatchExportResult struct {
-	TotalObjects int            `json:"totalObjects"`
-	SuccessCount int            `json:"successCount"`
-	FailureCount int            `json:"failureCount"`
-	Results      []ExportResult `json:"results"`
+	TotalObjects int                       `json:"totalObjects"`
+	SuccessCount int                       `json:"successCount"`
+	FailureCount int                       `json:"failureCount"`
+	Results      []ExportResult            `json:"results"`
+	Skipped      []adt.TransportExportSkip `json:"skipped,omitempty"` // Transport entries without source
 }
 
 // ImportBuilder provides a fluent interface for batch imports.
@@ -139,13 +140,15 @@ func (b *ImportBuilder) DDLSFirst() *ImportBuilder {
 	return b
 }
 
-// RAPOrder sets RAP-specific import order: DDLS → BDEF → SRVD → Classes.
+// RAPOrder sets RAP-specific import order: DDLS → DDLX → BDEF → Classes → SRVD.
 // Standard RAP development order where CDS views come first.
 func (b *ImportBuilder) RAPOrder() *ImportBuilder {
 	for i := range b.files {
 		switch b.files[i].ObjectType {
 		case adt.ObjectTypeDDLS:
 			b.files[i].Priority = 10 // CDS views first
+		case adt.ObjectTypeDDLX:
+			b.files[i].Priority = 15 // Metadata extensions on their views
 		case adt.ObjectTypeBDEF:
 			b.files[i].Priority = 20 // Behavior after CDS
 		case adt.ObjectTypeInterface:
@@ -306,10 +309,11 @@ func (b *ImportBuilder) Files() []ImportFile {
 
 // ExportBuilder provides a fluent interface for batch exports.
 type ExportBuilder struct {
-	client    *adt.Client
-	objects   []ExportObject
-	outputDir string
-	verbose   bool
+	client     *adt.Client
+	objects    []ExportObject
+	transports []string
+	outputDir  string
+	verbose    bool
 
 	// Callbacks
 	onStart    func(obj ExportObject)
@@ -403,6 +407,14 @@ func (b *ExportBuilder) DDLSources(names ...string) *ExportBuilder {
 	return b
 }
 
+// Transport adds the objects of a transport request and its tasks. Entries
+// without ADT source (dictionary objects, table contents) are reported in
+// the result's Skipped list.
+func (b *ExportBuilder) Transport(numbers ...string) *ExportBuilder {
+	b.transports = append(b.transports, numbers...)
+	return b
+}
+
 // ToDirectory sets the output directory.
 func (b *ExportBuilder) ToDirectory(dir string) *ExportBuilder {
 	b.outputDir = dir
@@ -435,9 +447,22 @@ func (b *ExportBuilder) OnError(fn func(obj ExportObject, err error)) *ExportBui
 
 // Execute runs the batch export.
 func (b *ExportBuilder) Execute(ctx context.Context) (*BatchExportResult, error) {
+	var skipped []adt.TransportExportSkip
+	for _, number := range b.transports {
+		plan, err := b.client.PlanTransportExport(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		_, unsupported := b.addTransportPlan(plan)
+		skipped = append(skipped, plan.Skipped...)
+		skipped = append(skipped, unsupported...)
+	}
+	b.transports = nil
+
 	result := &BatchExportResult{
 		TotalObjects: len(b.objects),
 		Results:      make([]ExportResult, 0, len(b.objects)),
+		Skipped:      skipped,
 	}
 
 	// Create output directory if needed
@@ -575,6 +600,7 @@ func isABAPSourceFile(path string) bool {
 	extensions := []string{
 		".abap",
 		".asddls",
+		".asddlxs",
 		".asbdef",
 		".srvdsrv",
 	}
@@ -603,6 +629,8 @@ func getPriority(objType adt.CreatableObjectType, includeType adt.ClassIncludeTy
 		return 40
 	case adt.ObjectTypeDDLS:
 		return 50
+	case adt.ObjectTypeDDLX:
+		return 55
 	case adt.ObjectTypeBDEF:
 		return 60
 	case adt.ObjectTypeSRVD:
@@ -0,0 +1,225 @@
+package dsl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oisee/vibing-steampunk/pkg/adt"
+)
+
+// RAPStackOptions configures GenerateRAPStack.
+type RAPStackOptions struct {
+	adt.RAPStackOptions
+	Package   string // Target package (required unless DryRun)
+	Transport string // Transport request (optional for local packages)
+	OutputDir string // Directory for the generated files (default: lower-case root view name in dry-run, a temporary directory otherwise)
+	DryRun    bool   // Only write the abapGit files and the manifest
+}
+
+// RAPStackResult is the result of GenerateRAPStack.
+type RAPStackResult struct {
+	Stack      *adt.RAPStack             `json:"stack"`
+	Directory  string                    `json:"directory,omitempty"` // Only set if the files were kept
+	Files      []string                  `json:"files"`
+	DryRun     bool                      `json:"dryRun,omitempty"`
+	Import     *BatchImportResult        `json:"import,omitempty"`
+	Activation *adt.ActivationPlanResult `json:"activation,omitempty"`
+	Errors     []string                  `json:"errors,omitempty"`
+	Success    bool                      `json:"success"`
+}
+
+// WriteRAPStack writes the file-based artifacts of a generated stack into dir
+// in abapGit layout, plus the manifest (rap-stack.json) that also holds the
+// draft table and the service binding. Returns the written file names.
+func WriteRAPStack(stack *adt.RAPStack, dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating output directory: %w", err)
+	}
+	var files []string
+	for _, a := range stack.Artifacts {
+		if a.FileName == "" {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, a.FileName), []byte(a.Source), 0644); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", a.FileName, err)
+		}
+		files = append(files, a.FileName)
+	}
+
+	data, err := json.MarshalIndent(stack, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, adt.RAPStackManifestFile), append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("writing manifest: %w", err)
+	}
+	return append(files, adt.RAPStackManifestFile), nil
+}
+
+// GenerateRAPStack generates a managed RAP business object for a table and
+// deploys it: the draft table is created first, the sources go through the
+// file import in RAP order, then the service binding is created and all
+// objects are activated together in dependency order. The package is checked
+// against the safety configuration before anything is created. In dry-run
+// only the abapGit files are written.
+func GenerateRAPStack(ctx context.Context, client *adt.Client, table string, opts RAPStackOptions) (*RAPStackResult, error) {
+	if !opts.DryRun {
+		if opts.Package == "" {
+			return nil, fmt.Errorf("package is required (or use dry run)")
+		}
+		if err := client.Safety().CheckPackage(opts.Package); err != nil {
+			return nil, err
+		}
+	}
+	def, err := client.ReadTableDefinition(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	stack, err := adt.GenerateRAPStack(def, opts.RAPStackOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := opts.OutputDir
+	switch {
+	case dir != "":
+	case opts.DryRun:
+		dir = strings.ReplaceAll(strings.ToLower(stack.RootView), "/", "#")
+	default:
+		tmp, err := os.MkdirTemp("", "rap-stack-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(tmp)
+		dir = tmp
+	}
+	files, err := WriteRAPStack(stack, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RAPStackResult{Stack: stack, Files: files, DryRun: opts.DryRun}
+	if opts.OutputDir != "" || opts.DryRun {
+		result.Directory = dir
+	}
+	if opts.DryRun {
+		result.Success = true
+		return result, nil
+	}
+
+	artifacts := make(map[string]adt.RAPArtifact)
+	for _, a := range stack.Artifacts {
+		switch {
+		case a.Type == "TABL":
+			if _, err := client.GetTable(ctx, a.Name); err == nil {
+				continue // Keep an existing draft table
+			}
+			if err := client.CreateTableFromDDL(ctx, adt.CreateTableOptions{
+				Name:        a.Name,
+				Description: "Draft table for " + stack.RootView,
+				Package:     opts.Package,
+				Transport:   opts.Transport,
+			}, a.Source); err != nil {
+				return result, fmt.Errorf("creating draft table %s: %w", a.Name, err)
+			}
+		case a.FileName != "":
+			artifacts[filepath.Join(dir, a.FileName)] = a
+		}
+	}
+
+	builder, err := Import(client).FromDirectory(dir)
+	if err != nil {
+		return result, err
+	}
+	result.Import, err = rapImportOrder(builder, stack).ToPackage(opts.Package).WithTransport(opts.Transport).Execute(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	// The behavior definition and its behavior pool refer to each other, so
+	// the syntax check of the import can fail for either of them. Such
+	// sources are written unchecked and activated together below.
+	for _, r := range result.Import.Results {
+		if r.Success {
+			continue
+		}
+		if err := writeRAPSource(ctx, client, artifacts[r.File.Path], opts.Transport); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %s; writing unchecked: %v", filepath.Base(r.File.Path), r.Message, err))
+		}
+	}
+
+	if err := client.CreateObject(ctx, adt.CreateObjectOptions{
+		ObjectType:        adt.ObjectTypeSRVB,
+		Name:              stack.ServiceBinding,
+		Description:       stack.Description,
+		PackageName:       opts.Package,
+		Transport:         opts.Transport,
+		ServiceDefinition: stack.ServiceDefinition,
+		BindingType:       "ODATA",
+		BindingVersion:    stack.BindingVersion,
+		BindingCategory:   "1", // UI
+	}); err != nil && !strings.Contains(strings.ToLower(err.Error()), "already exist") {
+		result.Errors = append(result.Errors, fmt.Sprintf("creating service binding %s: %v", stack.ServiceBinding, err))
+	}
+
+	result.Activation, err = client.ActivateObjectsPlanned(ctx, stack.Objects(), adt.ActivationPlanOptions{})
+	if err != nil {
+		return result, fmt.Errorf("activating %s: %w", stack.RootView, err)
+	}
+	result.Success = len(result.Errors) == 0 && len(result.Activation.Failed) == 0
+	return result, nil
+}
+
+// rapImportOrder applies the RAP import order to the generated files; the
+// projection layer follows the layer it projects.
+func rapImportOrder(b *ImportBuilder, stack *adt.RAPStack) *ImportBuilder {
+	b.RAPOrder()
+	for _, f := range b.Files() {
+		if f.ObjectName == stack.ProjectionView && (f.ObjectType == adt.ObjectTypeDDLS || f.ObjectType == adt.ObjectTypeBDEF) {
+			b.WithPriority(f.Path, f.Priority+1)
+		}
+	}
+	return b
+}
+
+// writeRAPSource writes the source of a generated artifact without syntax
+// check and activation.
+func writeRAPSource(ctx context.Context, client *adt.Client, a adt.RAPArtifact, transport string) error {
+	objectURL := a.ObjectURI()
+	if objectURL == "" {
+		return fmt.Errorf("not a generated artifact")
+	}
+	sourceURL := objectURL + "/source/main"
+	if a.Include != "" {
+		sourceURL = objectURL + "/includes/" + a.Include
+	}
+
+	lock, err := client.LockObject(ctx, objectURL, "MODIFY")
+	if err != nil {
+		return err
+	}
+	defer client.UnlockObject(ctx, objectURL, lock.LockHandle)
+	return client.UpdateSource(ctx, sourceURL, a.Source, lock.LockHandle, transport)
+}
+
+// RAPStackSummary returns a one-line summary of a RAP stack result.
+func RAPStackSummary(result *RAPStackResult) string {
+	s := result.Stack
+	if result.DryRun {
+		return fmt.Sprintf("Generated %s (%d files) for %s into %s", s.RootView, len(result.Files), s.Table, result.Directory)
+	}
+	status := "deployed"
+	if !result.Success {
+		status = "deployed with errors"
+	}
+	activated := 0
+	if result.Activation != nil {
+		activated = len(result.Activation.Activated)
+	}
+	return fmt.Sprintf("%s %s for %s: %d objects activated, service binding %s (%s)",
+		s.RootView, status, s.Table, activated, s.ServiceBinding, s.BindingVersion)
+}
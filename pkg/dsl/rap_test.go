@@ -0,0 +1,68 @@
+package dsl
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/oisee/vibing-steampunk/pkg/adt"
+)
+
+func TestWriteRAPStack(t *testing.T) {
+	def := &adt.TableDefinition{Name: "ZBOOK", Description: "Books", Fields: []adt.TableDefinitionField{
+		{Name: "client", Type: "abap.clnt", IsKey: true},
+		{Name: "book_uuid", Type: "sysuuid_x16", IsKey: true},
+		{Name: "title", Type: "abap.char(80)"},
+		{Name: "last_changed_at", Type: "abp_lastchange_tstmpl"},
+	}}
+	stack, err := adt.GenerateRAPStack(def, adt.RAPStackOptions{})
+	if err != nil {
+		t.Fatalf("GenerateRAPStack failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	files, err := WriteRAPStack(stack, dir)
+	if err != nil {
+		t.Fatalf("WriteRAPStack failed: %v", err)
+	}
+	if len(files) != 9 || files[len(files)-1] != adt.RAPStackManifestFile {
+		t.Errorf("files = %v", files)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, adt.RAPStackManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest adt.RAPStack
+	if err := json.Unmarshal(data, &manifest); err != nil || manifest.DraftTable != "ZBOOK_D" || manifest.ServiceBinding != "ZUI_BOOK_O4" {
+		t.Errorf("manifest = %+v, %v", manifest, err)
+	}
+
+	// The written files import in RAP order: views, extension, behavior, class, service
+	builder, err := Import(nil).FromDirectory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported := rapImportOrder(builder, stack).Files()
+	sort.SliceStable(imported, func(i, j int) bool { return imported[i].Priority < imported[j].Priority })
+	var order []string
+	for _, f := range imported {
+		order = append(order, string(f.ObjectType)+" "+f.ObjectName)
+	}
+	if got := strings.Join(order, ","); got != "DDLS/DF ZR_BOOK,DDLS/DF ZC_BOOK,DDLX/EX ZC_BOOK,BDEF/BDO ZR_BOOK,BDEF/BDO ZC_BOOK,"+
+		"CLAS/OC ZBP_R_BOOK,CLAS/OC ZBP_R_BOOK,SRVD/SRV ZUI_BOOK" {
+		t.Errorf("order = %s", got)
+	}
+}
+
+func TestGenerateRAPStackPackageSafety(t *testing.T) {
+	client := adt.NewClient("http://localhost:1", "u", "p", adt.WithAllowedPackages("$TMP"))
+	_, err := GenerateRAPStack(context.Background(), client, "ZBOOK", RAPStackOptions{Package: "ZPROD"})
+	if err == nil || !strings.Contains(err.Error(), "ZPROD") {
+		t.Errorf("expected package safety error, got %v", err)
+	}
+}
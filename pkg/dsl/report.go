@@ -0,0 +1,143 @@
+package dsl
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/oisee/vibing-steampunk/pkg/adt"
+)
+
+// UnitTestRuns returns the raw unit test results of the summary as input for
+// adt.JUnit.
+func (s *TestSummary) UnitTestRuns() []adt.UnitTestRun {
+	runs := make([]adt.UnitTestRun, 0, len(s.Results))
+	for _, r := range s.Results {
+		runs = append(runs, adt.UnitTestRun{Object: r.Object.Name, Result: r.Unit, Error: r.Error})
+	}
+	return runs
+}
+
+// Findings returns the failed tests of the summary as findings.
+func (s *TestSummary) Findings() []adt.Finding {
+	var findings []adt.Finding
+	for _, r := range s.Results {
+		if r.Unit != nil {
+			findings = append(findings, r.Unit.Findings()...)
+		}
+	}
+	return findings
+}
+
+// RenderReport renders a test summary, ATC worklist and syntax check
+// messages in one of the CI formats: junit (tests only), sarif, github or
+// gitlab. Any of the inputs may be nil.
+func RenderReport(format, name, srcDir string, tests *TestSummary, atc *adt.ATCWorklist, syntax []adt.SyntaxCheckResult) ([]byte, error) {
+	if strings.EqualFold(format, "junit") {
+		if tests == nil {
+			return nil, fmt.Errorf("junit report requires test results")
+		}
+		return adt.JUnit(name, tests.UnitTestRuns(), srcDir)
+	}
+
+	var findings []adt.Finding
+	if atc != nil {
+		findings = append(findings, atc.Findings()...)
+	}
+	findings = append(findings, adt.SyntaxFindings(syntax)...)
+	if tests != nil {
+		findings = append(findings, tests.Findings()...)
+	}
+	return adt.RenderFindings(format, findings, srcDir)
+}
+
+// handleReport renders results of earlier steps as a CI report.
+//
+// Parameters: format (junit, sarif, github, gitlab), tests, atc and syntax
+// (names of saved step results), output (file, default stdout), srcDir
+// (abapGit source folder, default "src") and name (JUnit suite name).
+func handleReport(ctx *ExecutionContext, params map[string]interface{}) (interface{}, error) {
+	format, _ := params["format"].(string)
+	if format == "" {
+		return nil, fmt.Errorf("report requires 'format' parameter")
+	}
+	srcDir, _ := params["srcDir"].(string)
+	if srcDir == "" {
+		srcDir = "src"
+	}
+	name, _ := params["name"].(string)
+	if name == "" {
+		name = "ABAP Unit"
+	}
+
+	lookup := func(param string) (interface{}, error) {
+		varName, _ := params[param].(string)
+		if varName == "" {
+			return nil, nil
+		}
+		val, exists := ctx.Get(varName)
+		if !exists {
+			return nil, fmt.Errorf("variable '%s' not found", varName)
+		}
+		return val, nil
+	}
+
+	var tests *TestSummary
+	if val, err := lookup("tests"); err != nil {
+		return nil, err
+	} else if val != nil {
+		summary, ok := val.(*TestSummary)
+		if !ok {
+			return nil, fmt.Errorf("'tests' is not a test result")
+		}
+		tests = summary
+	}
+
+	var atc *adt.ATCWorklist
+	if val, err := lookup("atc"); err != nil {
+		return nil, err
+	} else if val != nil {
+		switch v := val.(type) {
+		case *adt.ATCWorklist:
+			atc = v
+		case *adt.ATCComparison:
+			atc = v.New
+		default:
+			return nil, fmt.Errorf("'atc' is not an ATC result")
+		}
+	}
+
+	var syntax []adt.SyntaxCheckResult
+	if val, err := lookup("syntax"); err != nil {
+		return nil, err
+	} else if val != nil {
+		results, ok := val.([]map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("'syntax' is not a syntax check result")
+		}
+		for _, r := range results {
+			if messages, ok := r["messages"].([]adt.SyntaxCheckResult); ok {
+				syntax = append(syntax, messages...)
+			}
+		}
+	}
+
+	if tests == nil && atc == nil && syntax == nil {
+		return nil, fmt.Errorf("report requires at least one of 'tests', 'atc' or 'syntax'")
+	}
+
+	data, err := RenderReport(format, name, srcDir, tests, atc, syntax)
+	if err != nil {
+		return nil, err
+	}
+
+	output, _ := params["output"].(string)
+	if output == "" {
+		fmt.Print(string(data))
+		return map[string]interface{}{"format": format}, nil
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return nil, fmt.Errorf("writing report: %w", err)
+	}
+	return map[string]interface{}{"format": format, "output": output}, nil
+}
@@ -3,6 +3,7 @@ package dsl
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,6 +15,8 @@ type TestRunner struct {
 	client  *adt.Client
 	objects []ObjectRef
 	config  TestConfig
+	tests   map[string][]string // Test class/method URIs by lowercase object URL
+	impacts []impactTarget      // Changes whose impacted tests are run
 
 	// Callbacks
 	onStart    func(obj ObjectRef)
@@ -21,6 +24,12 @@ type TestRunner struct {
 	onError    func(obj ObjectRef, err error)
 }
 
+// impactTarget is a change given to ImpactOf.
+type impactTarget struct {
+	objectURL string
+	opts      adt.ImpactOptions
+}
+
 // Test creates a new test runner.
 func Test(client *adt.Client) *TestRunner {
 	return &TestRunner{
@@ -59,6 +68,53 @@ func (t *TestRunner) Package(pkgName string) *TestRunner {
 	return t
 }
 
+// Tests runs only the given test classes or methods, identified by their
+// URI or navigation URI from an earlier result. Their objects are added to
+// the runner.
+func (t *TestRunner) Tests(uris ...string) *TestRunner {
+	if t.tests == nil {
+		t.tests = make(map[string][]string)
+	}
+	for _, uri := range uris {
+		objectURL := adt.UnitTestObjectURLs([]string{uri})[0]
+		key := strings.ToLower(objectURL)
+		if _, known := t.tests[key]; !known {
+			name := strings.ToUpper(objectURL[strings.LastIndex(objectURL, "/")+1:])
+			t.objects = append(t.objects, ObjectRef{Name: name, URL: objectURL})
+		}
+		t.tests[key] = append(t.tests[key], uri)
+	}
+	return t
+}
+
+// ImpactOf runs the tests that cover a change of an object (or of
+// opts.Method): the tests of the object and of the objects that use it,
+// found by impact analysis.
+func (t *TestRunner) ImpactOf(objectURL string, opts adt.ImpactOptions) *TestRunner {
+	t.impacts = append(t.impacts, impactTarget{objectURL: objectURL, opts: opts})
+	return t
+}
+
+// WithHistory records the last result per object in dir and compares each
+// run with the recorded one (adt.DefaultUnitTestStoreDir if dir is empty).
+func (t *TestRunner) WithHistory(dir string) *TestRunner {
+	if dir == "" {
+		dir = adt.DefaultUnitTestStoreDir
+	}
+	t.config.History = dir
+	return t
+}
+
+// RerunFailed runs only the tests that failed in the recorded run of each
+// object. Enables the history if not set.
+func (t *TestRunner) RerunFailed() *TestRunner {
+	t.config.RerunFailed = true
+	if t.config.History == "" {
+		t.config.History = adt.DefaultUnitTestStoreDir
+	}
+	return t
+}
+
 // FromSearch uses search results as test targets.
 func (t *TestRunner) FromSearch(search *SearchBuilder) *TestRunner {
 	// Objects will be resolved during execution
@@ -83,6 +139,12 @@ func (t *TestRunner) IncludeLong() *TestRunner {
 	return t
 }
 
+// WithCoverage measures code coverage during the test runs.
+func (t *TestRunner) WithCoverage() *TestRunner {
+	t.config.Coverage = true
+	return t
+}
+
 // StopOnFirstFailure stops execution on first test failure.
 func (t *TestRunner) StopOnFirstFailure() *TestRunner {
 	t.config.StopOnFirstFailure = true
@@ -240,10 +302,19 @@ func (t *TestRunner) runSingleTest(ctx context.Context, obj ObjectRef) TestResul
 		Short:     t.config.Short,
 		Medium:    t.config.Medium,
 		Long:      t.config.Long,
+		Coverage:  t.config.Coverage,
 	}
 
 	// Run the tests
-	testResult, err := t.client.RunUnitTests(ctx, objectURL, flags)
+	var store *adt.UnitTestStore
+	if t.config.History != "" {
+		store = adt.NewUnitTestStore(t.config.History)
+	}
+	run, err := t.client.RunUnitTestsTracked(ctx, objectURL, store, adt.UnitTestRunOptions{
+		Flags:      flags,
+		Tests:      t.tests[strings.ToLower(objectURL)],
+		FailedOnly: t.config.RerunFailed,
+	})
 	if err != nil {
 		result.Error = err.Error()
 		if t.onError != nil {
@@ -253,9 +324,13 @@ func (t *TestRunner) runSingleTest(ctx context.Context, obj ObjectRef) TestResul
 	}
 
 	result.ExecutionTime = time.Since(startTime)
+	testResult := run.Result
+	result.Diff = run.Diff
 
 	// Parse results
 	result.Success = true
+	result.Coverage = testResult.Coverage
+	result.Unit = testResult
 	for _, class := range testResult.Classes {
 		classResult := TestClassResult{
 			Name:    class.Name,
@@ -316,9 +391,30 @@ func (t *TestRunner) resolveObjects(ctx context.Context) ([]ObjectRef, error) {
 		}
 	}
 
+	for _, target := range t.impacts {
+		impact, err := t.client.AnalyzeImpact(ctx, target.objectURL, target.opts)
+		if err != nil {
+			return nil, fmt.Errorf("analyzing impact of %s: %w", target.objectURL, err)
+		}
+		for _, test := range impact.Tests {
+			if !containsObjectURL(resolved, test.ObjectURL) {
+				resolved = append(resolved, ObjectRef{Type: strings.SplitN(test.Type, "/", 2)[0], Name: test.Name, Package: test.Package, URL: test.ObjectURL})
+			}
+		}
+	}
+
 	return resolved, nil
 }
 
+func containsObjectURL(objects []ObjectRef, objectURL string) bool {
+	for _, obj := range objects {
+		if strings.EqualFold(obj.URL, objectURL) {
+			return true
+		}
+	}
+	return false
+}
+
 // buildObjectURL constructs the ADT URL for an object.
 func (t *TestRunner) buildObjectURL(obj ObjectRef) string {
 	if obj.URL != "" {
@@ -355,6 +451,13 @@ func (t *TestRunner) aggregateResult(summary *TestSummary, result TestResult) {
 	summary.PassedTests += result.PassedTests
 	summary.FailedTests += result.FailedTests
 	summary.SkippedTests += result.SkippedTests
+
+	if result.Coverage != nil {
+		if summary.Coverage == nil {
+			summary.Coverage = &adt.CoverageReport{}
+		}
+		summary.Coverage.Merge(result.Coverage)
+	}
 }
 
 // --- Convenience Functions ---
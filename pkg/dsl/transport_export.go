@@ -0,0 +1,178 @@
+package dsl
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/oisee/vibing-steampunk/pkg/adt"
+)
+
+// addTransportPlan queues the planned objects that have ADT source and
+// returns them; the others are returned as skipped.
+func (b *ExportBuilder) addTransportPlan(plan *adt.TransportExportPlan) ([]adt.GitObjectRef, []adt.TransportExportSkip) {
+	var added []adt.GitObjectRef
+	var skipped []adt.TransportExportSkip
+	for _, ref := range plan.Objects {
+		switch ref.Type {
+		case "CLAS":
+			b.Classes(ref.Name)
+		case "PROG":
+			b.Programs(ref.Name)
+		case "INTF":
+			b.Interfaces(ref.Name)
+		case "DDLS":
+			b.DDLSources(ref.Name)
+		case "BDEF":
+			b.objects = append(b.objects, ExportObject{Type: adt.ObjectTypeBDEF, Name: ref.Name})
+		case "SRVD":
+			b.objects = append(b.objects, ExportObject{Type: adt.ObjectTypeSRVD, Name: ref.Name})
+		default:
+			skipped = append(skipped, adt.TransportExportSkip{
+				PgmID: "R3TR", Type: ref.Type, Name: ref.Name,
+				Reason: "no ADT source for this type; export with the abapGit backend (ZADT_VSP)",
+			})
+			continue
+		}
+		added = append(added, ref)
+	}
+	return added, skipped
+}
+
+// ExportTransport writes every object of a transport request (including its
+// tasks) into dir in abapGit layout, plus a manifest listing the entries
+// that could not be serialized. With a connected WebSocket client the
+// objects are serialized by abapGit (GitExport); without one only their
+// source is exported via ADT. dir defaults to the lower-case transport
+// number.
+func ExportTransport(ctx context.Context, client *adt.Client, ws *adt.AMDPWebSocketClient, number, dir string) (*adt.TransportExportManifest, error) {
+	plan, err := client.PlanTransportExport(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		dir = strings.ToLower(plan.Transport)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating output directory: %w", err)
+	}
+
+	exported := *plan
+	var files []string
+	backend := adt.TransportExportAbapGit
+	if ws != nil {
+		if len(plan.Objects) > 0 {
+			zipData, _, err := ws.GitExportToBytes(ctx, adt.GitExportParams{Objects: plan.Objects})
+			if err != nil {
+				return nil, fmt.Errorf("abapGit export of %s: %w", plan.Transport, err)
+			}
+			if files, err = extractExportZip(zipData, dir); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		backend = adt.TransportExportSource
+		b := Export(client).ToDirectory(dir)
+		var skipped []adt.TransportExportSkip
+		exported.Objects, skipped = b.addTransportPlan(plan)
+		exported.Skipped = append(append([]adt.TransportExportSkip{}, plan.Skipped...), skipped...)
+		result, err := b.Execute(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range result.Results {
+			if r.Success {
+				if rel, err := filepath.Rel(dir, r.FilePath); err == nil {
+					files = append(files, filepath.ToSlash(rel))
+				}
+			}
+		}
+	}
+
+	manifest := adt.NewTransportExportManifest(&exported, files, backend)
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, adt.TransportExportManifestFile), append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("writing manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// extractExportZip unpacks an abapGit ZIP into dir and returns the file
+// paths (slash-separated, relative to dir).
+func extractExportZip(data []byte, dir string) ([]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("reading export ZIP: %w", err)
+	}
+	var files []string
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Clean(strings.TrimPrefix(f.Name, "/"))
+		if name == "." || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("export ZIP contains invalid path %q", f.Name)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s from export ZIP: %w", f.Name, err)
+		}
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s from export ZIP: %w", f.Name, err)
+		}
+		if err := os.WriteFile(target, buf.Bytes(), 0644); err != nil {
+			return nil, err
+		}
+		files = append(files, name)
+	}
+	return files, nil
+}
+
+// handleExportTransport exports the source of all objects of a transport
+// into a directory (abapGit file names) with a manifest.
+//
+//	steps:
+//	  - action: export_transport
+//	    parameters:
+//	      transport: A4HK900123
+//	      dir: ./review/a4hk900123
+func handleExportTransport(ctx *ExecutionContext, params map[string]interface{}) (interface{}, error) {
+	transport, _ := params["transport"].(string)
+	if transport == "" {
+		return nil, fmt.Errorf("export_transport requires 'transport' parameter")
+	}
+	dir, _ := params["dir"].(string)
+	manifest, err := ExportTransport(ctx.Context(), ctx.Client(), nil, transport, dir)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.IsVerbose() {
+		fmt.Println(TransportExportSummary(manifest))
+	}
+	return manifest, nil
+}
+
+// TransportExportSummary returns a one-line summary of a transport export.
+func TransportExportSummary(m *adt.TransportExportManifest) string {
+	files := 0
+	for _, o := range m.Objects {
+		files += len(o.Files)
+	}
+	return fmt.Sprintf("%s: %d objects exported (%d files, %s), %d not serialized", m.Transport, len(m.Objects), files, m.Backend, len(m.NotSerialized))
+}
@@ -62,6 +62,15 @@ type TestConfig struct {
 	StopOnFirstFailure bool `json:"stopOnFirstFailure" yaml:"stopOnFirstFailure"`
 	Parallel           int  `json:"parallel" yaml:"parallel"` // Number of parallel executions
 	Timeout            time.Duration `json:"timeout" yaml:"timeout"`
+
+	// Coverage measures ABAP Unit code coverage for every tested object
+	Coverage bool `json:"coverage" yaml:"coverage"`
+
+	// History is the directory where the last result per object is kept
+	// (empty = no recording). Results are compared with the recorded run.
+	History string `json:"history,omitempty" yaml:"history,omitempty"`
+	// RerunFailed runs only the tests that failed in the recorded run
+	RerunFailed bool `json:"rerunFailed,omitempty" yaml:"rerunFailed,omitempty"`
 }
 
 // DefaultTestConfig returns sensible defaults for test execution.
@@ -89,6 +98,9 @@ type TestResult struct {
 	SkippedTests  int              `json:"skippedTests"`
 	ExecutionTime time.Duration    `json:"executionTime"`
 	Classes       []TestClassResult `json:"classes,omitempty"`
+	Coverage      *adt.CoverageReport `json:"coverage,omitempty"`
+	Unit          *adt.UnitTestResult `json:"-"` // Raw ADT result, used for reports
+	Diff          *adt.UnitTestRunDiff `json:"diff,omitempty"` // Against the recorded run (History only)
 	Error         string           `json:"error,omitempty"`
 }
 
@@ -119,6 +131,7 @@ type TestSummary struct {
 	SkippedTests   int           `json:"skippedTests"`
 	TotalTime      time.Duration `json:"totalTime"`
 	Results        []TestResult  `json:"results"`
+	Coverage       *adt.CoverageReport `json:"coverage,omitempty"` // Merged coverage of all objects
 }
 
 // BatchOperation represents a batch modification operation.
@@ -0,0 +1,64 @@
+package dsl
+
+import (
+	"fmt"
+
+	"github.com/oisee/vibing-steampunk/pkg/adt"
+)
+
+// handleUI5Pull downloads a BSP application into a folder.
+//
+//	steps:
+//	  - action: ui5_pull
+//	    parameters:
+//	      app: ZACME_APP
+//	      dir: ./zacme_app
+func handleUI5Pull(ctx *ExecutionContext, params map[string]interface{}) (interface{}, error) {
+	app, _ := params["app"].(string)
+	dir, _ := params["dir"].(string)
+	if app == "" || dir == "" {
+		return nil, fmt.Errorf("ui5_pull requires 'app' and 'dir' parameters")
+	}
+	result, err := ctx.Client().UI5Pull(ctx.Context(), app, dir, adt.UI5SyncOptions{DryRun: ctx.IsDryRun()})
+	if err != nil {
+		return nil, err
+	}
+	if ctx.IsVerbose() {
+		fmt.Println(result.Summary())
+	}
+	return result, nil
+}
+
+// handleUI5Push deploys a folder to a BSP application (backend filestore:
+// changed files only). App name, package and transport default to
+// ui5-deploy.yaml.
+//
+//	steps:
+//	  - action: ui5_push
+//	    parameters:
+//	      dir: ./zacme_app
+//	      transport: ${TRANSPORT}
+//	      backend: odata
+func handleUI5Push(ctx *ExecutionContext, params map[string]interface{}) (interface{}, error) {
+	dir, _ := params["dir"].(string)
+	if dir == "" {
+		return nil, fmt.Errorf("ui5_push requires 'dir' parameter")
+	}
+	app, _ := params["app"].(string)
+	opts := adt.UI5SyncOptions{DryRun: ctx.IsDryRun()}
+	opts.Package, _ = params["package"].(string)
+	opts.Transport, _ = params["transport"].(string)
+	opts.Source, _ = params["source"].(string)
+	opts.KeepRemoved, _ = params["keepRemoved"].(bool)
+	opts.Backend, _ = params["backend"].(string)
+	opts.UnsafeMode, _ = params["unsafe"].(bool)
+
+	result, err := ctx.Client().UI5Push(ctx.Context(), dir, app, opts)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.IsVerbose() {
+		fmt.Println(result.Summary())
+	}
+	return result, nil
+}
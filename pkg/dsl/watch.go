@@ -0,0 +1,298 @@
+package dsl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/oisee/vibing-steampunk/pkg/adt"
+)
+
+// Watch event kinds.
+const (
+	WatchEventDeploy = "deploy" // Object deployed, syntax checked and activated
+	WatchEventTest   = "test"   // Unit tests of the object and its callers
+	WatchEventError  = "error"  // Watcher or request error
+)
+
+// WatchEvent reports one step of the watch loop.
+type WatchEvent struct {
+	Time         time.Time `json:"time"`
+	Kind         string    `json:"kind"`
+	File         string    `json:"file,omitempty"`
+	Object       string    `json:"object,omitempty"`
+	Success      bool      `json:"success"`
+	Message      string    `json:"message,omitempty"`
+	SyntaxErrors []string  `json:"syntaxErrors,omitempty"`
+	TestedURLs   []string  `json:"testedObjects,omitempty"`
+	TotalTests   int       `json:"totalTests,omitempty"`
+	FailedTests  int       `json:"failedTests,omitempty"`
+	Failures     []string  `json:"failures,omitempty"` // CLASS=>METHOD: message
+}
+
+// Watcher deploys changed abapGit files from a local directory and gives
+// feedback: syntax check and activation on deploy, then the unit tests of
+// the object and of its direct callers.
+type Watcher struct {
+	client      *adt.Client
+	dir         string
+	packageName string
+	transport   string
+	debounce    time.Duration
+	callers     bool
+	onEvent     func(WatchEvent)
+
+	process func(ctx context.Context, path string) // Replaced in tests
+}
+
+// Watch creates a watcher for dir.
+func Watch(client *adt.Client, dir string) *Watcher {
+	w := &Watcher{
+		client:      client,
+		dir:         dir,
+		packageName: "$TMP",
+		debounce:    300 * time.Millisecond,
+		callers:     true,
+		onEvent:     func(WatchEvent) {},
+	}
+	w.process = w.Process
+	return w
+}
+
+// Package sets the package for objects that do not exist yet.
+func (w *Watcher) Package(name string) *Watcher {
+	w.packageName = name
+	return w
+}
+
+// Transport sets the transport request for changes.
+func (w *Watcher) Transport(transport string) *Watcher {
+	w.transport = transport
+	return w
+}
+
+// Debounce sets how long a file must be quiet before it is deployed.
+// Editors often write a file several times per save.
+func (w *Watcher) Debounce(d time.Duration) *Watcher {
+	w.debounce = d
+	return w
+}
+
+// WithoutCallers runs only the tests of the changed object.
+func (w *Watcher) WithoutCallers() *Watcher {
+	w.callers = false
+	return w
+}
+
+// OnEvent sets the callback for watch events. It is called from one
+// goroutine at a time.
+func (w *Watcher) OnEvent(fn func(WatchEvent)) *Watcher {
+	w.onEvent = fn
+	return w
+}
+
+func (w *Watcher) emit(e WatchEvent) {
+	e.Time = time.Now()
+	w.onEvent(e)
+}
+
+// IsWatchedFile reports whether a file name is an abapGit source file that
+// can be deployed.
+func IsWatchedFile(path string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+	for _, suffix := range []string{".abap", ".asddls", ".asbdef", ".srvdsrv"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run watches the directory tree until ctx is cancelled. Changed files are
+// processed one at a time, in the order they became quiet.
+func (w *Watcher) Run(ctx context.Context) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating file watcher: %w", err)
+	}
+	defer fsw.Close()
+
+	err = filepath.WalkDir(w.dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != w.dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return fsw.Add(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("watching %s: %w", w.dir, err)
+	}
+
+	var mu sync.Mutex
+	pending := make(map[string]*time.Timer)
+	ready := make(chan string, 64)
+	// Timers that fire while Run returns must not block on a full ready
+	// channel nobody reads any more.
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		mu.Lock()
+		for _, t := range pending {
+			t.Stop()
+		}
+		mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = fsw.Add(event.Name)
+					continue
+				}
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+			if !IsWatchedFile(event.Name) {
+				continue
+			}
+			path := event.Name
+			mu.Lock()
+			if t, ok := pending[path]; ok {
+				t.Stop()
+			}
+			pending[path] = time.AfterFunc(w.debounce, func() {
+				mu.Lock()
+				delete(pending, path)
+				mu.Unlock()
+				select {
+				case ready <- path:
+				case <-done:
+				}
+			})
+			mu.Unlock()
+
+		case path := <-ready:
+			// Renamed-away temp files no longer exist
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			w.process(ctx, path)
+
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			w.emit(WatchEvent{Kind: WatchEventError, Message: err.Error()})
+		}
+	}
+}
+
+// Process deploys one file and, if that succeeds, runs the affected unit
+// tests. Results are reported as events.
+func (w *Watcher) Process(ctx context.Context, path string) {
+	deploy, err := w.client.DeployFromFile(ctx, path, w.packageName, w.transport)
+	if err != nil {
+		w.emit(WatchEvent{Kind: WatchEventError, File: path, Message: err.Error()})
+		return
+	}
+
+	event := WatchEvent{
+		Kind:         WatchEventDeploy,
+		File:         path,
+		Object:       deploy.ObjectName,
+		Success:      deploy.Success,
+		Message:      deploy.Message,
+		SyntaxErrors: deploy.SyntaxErrors,
+	}
+	if !deploy.Success && event.Message == "" {
+		event.Message = strings.Join(deploy.Errors, "; ")
+	}
+	w.emit(event)
+	if !deploy.Success {
+		return
+	}
+
+	targets := w.AffectedTestObjects(ctx, deploy.ObjectURL)
+	if len(targets) == 0 {
+		return
+	}
+	result, err := w.client.RunUnitTestsFor(ctx, targets, nil)
+	if err != nil {
+		w.emit(WatchEvent{Kind: WatchEventError, File: path, Object: deploy.ObjectName, Message: fmt.Sprintf("running unit tests: %v", err)})
+		return
+	}
+	w.emit(testWatchEvent(path, deploy.ObjectName, targets, result))
+}
+
+// AffectedTestObjects returns the objects whose unit tests cover a changed
+// object: the object itself (if it can contain tests) and its direct
+// callers.
+func (w *Watcher) AffectedTestObjects(ctx context.Context, objectURL string) []string {
+	var urls []string
+	if u := adt.UnitTestContainerURL(objectURL); u != "" {
+		urls = append(urls, u)
+	}
+	if w.callers {
+		if node, err := w.client.GetCallersOf(ctx, objectURL, 1); err == nil && node != nil {
+			for _, caller := range node.Children {
+				if u := adt.UnitTestContainerURL(caller.URI); u != "" {
+					urls = append(urls, u)
+				}
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	var unique []string
+	for _, u := range urls {
+		if key := strings.ToLower(u); !seen[key] {
+			seen[key] = true
+			unique = append(unique, u)
+		}
+	}
+	return unique
+}
+
+func testWatchEvent(path, object string, targets []string, result *adt.UnitTestResult) WatchEvent {
+	event := WatchEvent{Kind: WatchEventTest, File: path, Object: object, TestedURLs: targets, Success: true}
+	for _, class := range result.Classes {
+		for _, m := range class.TestMethods {
+			event.TotalTests++
+			alerts := m.Alerts
+			if len(alerts) == 0 {
+				alerts = class.Alerts
+			}
+			if len(alerts) > 0 {
+				event.FailedTests++
+				event.Failures = append(event.Failures, fmt.Sprintf("%s=>%s: %s", class.Name, m.Name, alerts[0].Title))
+			}
+		}
+	}
+	sort.Strings(event.Failures)
+	event.Success = event.FailedTests == 0
+	event.Message = fmt.Sprintf("%d/%d tests passed", event.TotalTests-event.FailedTests, event.TotalTests)
+	return event
+}
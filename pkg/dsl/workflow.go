@@ -2,9 +2,11 @@ package dsl
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -70,12 +72,17 @@ func NewWorkflowEngine(client *adt.Client) *WorkflowEngine {
 	engine.RegisterHandler("search", handleSearch)
 	engine.RegisterHandler("test", handleTest)
 	engine.RegisterHandler("syntax_check", handleSyntaxCheck)
+	engine.RegisterHandler("atc", handleATC)
+	engine.RegisterHandler("report", handleReport)
 	engine.RegisterHandler("transform", handleTransform)
 	engine.RegisterHandler("save", handleSave)
 	engine.RegisterHandler("activate", handleActivate)
 	engine.RegisterHandler("print", handlePrint)
 	engine.RegisterHandler("fail_if", handleFailIf)
 	engine.RegisterHandler("foreach", handleForEach)
+	engine.RegisterHandler("ui5_pull", handleUI5Pull)
+	engine.RegisterHandler("ui5_push", handleUI5Push)
+	engine.RegisterHandler("export_transport", handleExportTransport)
 
 	return engine
 }
@@ -401,6 +408,35 @@ func handleTest(ctx *ExecutionContext, params map[string]interface{}) (interface
 		runner.StopOnFirstFailure()
 	}
 
+	if coverage, ok := params["coverage"].(bool); ok && coverage {
+		runner.WithCoverage()
+	}
+
+	if tests, ok := params["tests"].([]interface{}); ok {
+		for _, uri := range tests {
+			if s, ok := uri.(string); ok && s != "" {
+				runner.Tests(s)
+			}
+		}
+	}
+
+	if history, ok := params["history"].(string); ok {
+		runner.WithHistory(history)
+	}
+
+	if rerun, ok := params["rerunFailed"].(bool); ok && rerun {
+		runner.RerunFailed()
+	}
+
+	if impactOf, ok := params["impactOf"].(string); ok && impactOf != "" {
+		opts := adt.ImpactOptions{}
+		opts.Method, _ = params["method"].(string)
+		if depth, ok := params["impactDepth"].(int); ok {
+			opts.MaxDepth = depth
+		}
+		runner.ImpactOf(impactOf, opts)
+	}
+
 	return runner.Run(ctx.Context())
 }
 
@@ -470,6 +506,102 @@ func handleSyntaxCheck(ctx *ExecutionContext, params map[string]interface{}) (in
 	return results, nil
 }
 
+func handleATC(ctx *ExecutionContext, params map[string]interface{}) (interface{}, error) {
+	variant, _ := params["variant"].(string)
+	maxResults := 0
+	if mr, ok := params["maxResults"].(int); ok {
+		maxResults = mr
+	}
+	opts := adt.ATCSetOptions{Variant: variant, MaxResults: maxResults}
+
+	var worklist *adt.ATCWorklist
+	if pkg, _ := params["package"].(string); pkg != "" {
+		result, err := ctx.Client().RunATCCheckPackage(ctx.Context(), pkg, opts)
+		if err != nil {
+			return nil, err
+		}
+		worklist = result.Worklist
+	} else if transport, _ := params["transport"].(string); transport != "" {
+		result, err := ctx.Client().RunATCCheckTransport(ctx.Context(), transport, opts)
+		if err != nil {
+			return nil, err
+		}
+		worklist = result.Worklist
+	} else {
+		objectsVar, _ := params["objects"].(string)
+		if objectsVar == "" {
+			return nil, fmt.Errorf("atc requires 'objects', 'package' or 'transport' parameter")
+		}
+
+		val, exists := ctx.Get(objectsVar)
+		if !exists {
+			return nil, fmt.Errorf("variable '%s' not found", objectsVar)
+		}
+
+		objects, ok := val.([]ObjectRef)
+		if !ok {
+			return nil, fmt.Errorf("variable '%s' is not a list of objects", objectsVar)
+		}
+
+		var err error
+		if worklist, err = RunATC(ctx.Context(), ctx.Client(), objects, variant, maxResults); err != nil {
+			return nil, err
+		}
+	}
+
+	// With a baseline, the result is the comparison (new and fixed findings)
+	baselinePath, _ := params["baseline"].(string)
+	if baselinePath == "" {
+		return worklist, nil
+	}
+	updateBaseline, _ := params["updateBaseline"].(bool)
+	return CompareATCBaseline(worklist, baselinePath, updateBaseline)
+}
+
+// CompareATCBaseline compares the worklist with the baseline file. A missing
+// baseline is created from the worklist, so the first run reports no new
+// findings. With update set, the baseline is overwritten after comparing.
+func CompareATCBaseline(worklist *adt.ATCWorklist, path string, update bool) (*adt.ATCComparison, error) {
+	baseline, err := adt.LoadATCBaseline(path)
+	if errors.Is(err, os.ErrNotExist) {
+		baseline = adt.NewATCBaseline(worklist)
+		if err := baseline.Save(path); err != nil {
+			return nil, err
+		}
+		return baseline.Compare(worklist), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := baseline.Compare(worklist)
+	if update {
+		if err := adt.NewATCBaseline(worklist).Save(path); err != nil {
+			return nil, err
+		}
+	}
+	return comparison, nil
+}
+
+// RunATC runs ATC checks for the objects in one merged worklist.
+func RunATC(ctx context.Context, client *adt.Client, objects []ObjectRef, variant string, maxResults int) (*adt.ATCWorklist, error) {
+	var urls []string
+	for _, obj := range objects {
+		if objectURL := buildObjectURL(obj); objectURL != "" {
+			urls = append(urls, objectURL)
+		}
+	}
+	if len(urls) == 0 {
+		return &adt.ATCWorklist{Objects: []adt.ATCObject{}}, nil
+	}
+
+	result, err := client.RunATCCheckObjects(ctx, urls, adt.ATCSetOptions{Variant: variant, MaxResults: maxResults})
+	if err != nil {
+		return nil, err
+	}
+	return result.Worklist, nil
+}
+
 func handleTransform(ctx *ExecutionContext, params map[string]interface{}) (interface{}, error) {
 	if ctx.IsDryRun() {
 		return map[string]interface{}{"dryRun": true, "action": "transform"}, nil
@@ -551,6 +683,64 @@ func handleFailIf(ctx *ExecutionContext, params map[string]interface{}) (interfa
 		}
 	}
 
+	// coverage_below:<var>:<percent> fails if statement coverage is below percent
+	if strings.HasPrefix(condition, "coverage_below:") {
+		parts := strings.SplitN(strings.TrimPrefix(condition, "coverage_below:"), ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("coverage_below requires <var>:<percent>")
+		}
+		minPercent, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coverage percent %q", parts[1])
+		}
+		val, exists := ctx.Get(parts[0])
+		if exists {
+			if summary, ok := val.(*TestSummary); ok {
+				if summary.Coverage == nil {
+					return nil, fmt.Errorf("no coverage measured in '%s' (set coverage: true on the test step)", parts[0])
+				}
+				if pct := summary.Coverage.Statement.Percent(); pct < minPercent {
+					if message == "" {
+						message = fmt.Sprintf("statement coverage %.1f%% is below %.1f%%", pct, minPercent)
+					}
+					return nil, fmt.Errorf(message)
+				}
+			}
+		}
+	}
+
+	// atc_new_findings:<var>:<priority> fails on (new) ATC findings with the
+	// given priority or higher; <var> is an atc step result
+	if strings.HasPrefix(condition, "atc_new_findings:") {
+		parts := strings.SplitN(strings.TrimPrefix(condition, "atc_new_findings:"), ":", 2)
+		maxPriority := 1
+		if len(parts) == 2 {
+			p, err := strconv.Atoi(parts[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid ATC priority %q", parts[1])
+			}
+			maxPriority = p
+		}
+		val, exists := ctx.Get(parts[0])
+		if exists {
+			var worklist *adt.ATCWorklist
+			switch v := val.(type) {
+			case *adt.ATCComparison:
+				worklist = v.New
+			case *adt.ATCWorklist:
+				worklist = v
+			}
+			if worklist != nil {
+				if n := worklist.CountFindings(maxPriority); n > 0 {
+					if message == "" {
+						message = fmt.Sprintf("%d new ATC findings with priority %d or higher", n, maxPriority)
+					}
+					return nil, fmt.Errorf(message)
+				}
+			}
+		}
+	}
+
 	if strings.HasPrefix(condition, "syntax_errors:") {
 		varName := strings.TrimPrefix(condition, "syntax_errors:")
 		val, exists := ctx.Get(varName)
@@ -3,7 +3,12 @@ package dsl
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/oisee/vibing-steampunk/pkg/adt"
 )
 
 func TestWorkflowExecution(t *testing.T) {
@@ -486,6 +491,149 @@ func TestFailIfAction(t *testing.T) {
 			t.Error("expected workflow to fail due to syntax errors")
 		}
 	})
+
+	t.Run("CoverageBelowCondition", func(t *testing.T) {
+		engine := NewWorkflowEngine(nil)
+
+		engine.RegisterHandler("mock_test", func(ctx *ExecutionContext, params map[string]interface{}) (interface{}, error) {
+			summary := &TestSummary{TotalTests: 5, PassedTests: 5}
+			summary.Coverage = &adt.CoverageReport{}
+			summary.Coverage.Statement = adt.CoverageCounter{Total: 10, Executed: 7}
+			return summary, nil
+		})
+
+		for _, tc := range []struct {
+			min     string
+			success bool
+		}{{"70", true}, {"80", false}} {
+			workflow := &Workflow{
+				Name: "test",
+				Steps: []WorkflowStep{
+					{Action: "mock_test", SaveAs: "testResults"},
+					{
+						Action: "fail_if",
+						Parameters: map[string]interface{}{
+							"condition": "coverage_below:testResults:" + tc.min,
+						},
+					},
+				},
+			}
+
+			result, err := engine.Execute(context.Background(), workflow)
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if result.Success != tc.success {
+				t.Errorf("min %s%%: success = %v, want %v (%s)", tc.min, result.Success, tc.success, result.Error)
+			}
+		}
+	})
+
+	t.Run("ATCNewFindingsCondition", func(t *testing.T) {
+		engine := NewWorkflowEngine(nil)
+		baselinePath := filepath.Join(t.TempDir(), "atc-baseline.json")
+
+		findings := []adt.ATCFinding{{Priority: 2, CheckID: "LEGACY", MessageTitle: "Old", Location: "/sap/bc/adt/oo/classes/zcl_a/source/main#start=1,0"}}
+		engine.RegisterHandler("mock_atc", func(ctx *ExecutionContext, params map[string]interface{}) (interface{}, error) {
+			worklist := &adt.ATCWorklist{Objects: []adt.ATCObject{{Type: "CLAS", Name: "ZCL_A", Findings: findings}}}
+			return CompareATCBaseline(worklist, baselinePath, false)
+		})
+
+		workflow := &Workflow{
+			Name: "gate",
+			Steps: []WorkflowStep{
+				{Action: "mock_atc", SaveAs: "atc"},
+				{Action: "fail_if", Parameters: map[string]interface{}{"condition": "atc_new_findings:atc:2"}},
+			},
+		}
+
+		// First run creates the baseline, legacy finding is accepted
+		result, err := engine.Execute(context.Background(), workflow)
+		if err != nil || !result.Success {
+			t.Fatalf("first run failed: %v %s", err, result.Error)
+		}
+
+		// A new info finding passes, a new warning fails
+		findings = append(findings, adt.ATCFinding{Priority: 3, CheckID: "INFO", MessageTitle: "Info"})
+		if result, _ := engine.Execute(context.Background(), workflow); !result.Success {
+			t.Errorf("new info finding should pass: %s", result.Error)
+		}
+		findings = append(findings, adt.ATCFinding{Priority: 2, CheckID: "NEW", MessageTitle: "New"})
+		result, _ = engine.Execute(context.Background(), workflow)
+		if result.Success || !strings.Contains(result.Error, "1 new ATC findings") {
+			t.Errorf("expected failure on new warning, got success=%v %s", result.Success, result.Error)
+		}
+	})
+}
+
+func TestReportAction(t *testing.T) {
+	engine := NewWorkflowEngine(nil)
+
+	engine.RegisterHandler("mock_test", func(ctx *ExecutionContext, params map[string]interface{}) (interface{}, error) {
+		unit := &adt.UnitTestResult{Classes: []adt.UnitTestClass{{
+			URI:  "/sap/bc/adt/oo/classes/zcl_calc/includes/testclasses",
+			Name: "LTCL_CALC",
+			TestMethods: []adt.UnitTestMethod{
+				{Name: "ADD"},
+				{Name: "SUB", Alerts: []adt.UnitTestAlert{{Kind: "failedAssertion", Title: "Expected 2"}}},
+			},
+		}}}
+		return &TestSummary{Results: []TestResult{{Object: ObjectRef{Name: "ZCL_CALC"}, Unit: unit}}}, nil
+	})
+	engine.RegisterHandler("mock_atc", func(ctx *ExecutionContext, params map[string]interface{}) (interface{}, error) {
+		return &adt.ATCWorklist{Objects: []adt.ATCObject{{
+			URI:      "/sap/bc/adt/oo/classes/zcl_calc",
+			Name:     "ZCL_CALC",
+			Findings: []adt.ATCFinding{{Priority: 1, CheckID: "CHECK", MessageTitle: "Bad", Line: 5}},
+		}}}, nil
+	})
+
+	dir := t.TempDir()
+	workflow := &Workflow{
+		Name: "report",
+		Steps: []WorkflowStep{
+			{Action: "mock_test", SaveAs: "tests"},
+			{Action: "mock_atc", SaveAs: "atc"},
+			{Action: "report", Parameters: map[string]interface{}{
+				"format": "junit", "tests": "tests", "output": filepath.Join(dir, "junit.xml"),
+			}},
+			{Action: "report", Parameters: map[string]interface{}{
+				"format": "gitlab", "tests": "tests", "atc": "atc", "srcDir": "abap", "output": filepath.Join(dir, "quality.json"),
+			}},
+		},
+	}
+
+	result, err := engine.Execute(context.Background(), workflow)
+	if err != nil || !result.Success {
+		t.Fatalf("Execute failed: %v %s", err, result.Error)
+	}
+
+	junit, err := os.ReadFile(filepath.Join(dir, "junit.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(junit), `<testsuite name="ZCL_CALC.LTCL_CALC" tests="2" failures="1"`) {
+		t.Errorf("unexpected junit report:\n%s", junit)
+	}
+
+	quality, err := os.ReadFile(filepath.Join(dir, "quality.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"path": "abap/zcl_calc.clas.abap"`, `"path": "abap/zcl_calc.clas.testclasses.abap"`, `"description": "LTCL_CALC-\u003eSUB: Expected 2"`} {
+		if !strings.Contains(string(quality), want) {
+			t.Errorf("code quality report missing %s:\n%s", want, quality)
+		}
+	}
+
+	// JUnit without test results fails
+	bad := &Workflow{Name: "bad", Steps: []WorkflowStep{
+		{Action: "mock_atc", SaveAs: "atc"},
+		{Action: "report", Parameters: map[string]interface{}{"format": "junit", "atc": "atc"}},
+	}}
+	if result, _ := engine.Execute(context.Background(), bad); result.Success {
+		t.Error("expected junit report without tests to fail")
+	}
 }
 
 func TestPrintAction(t *testing.T) {
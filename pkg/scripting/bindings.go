@@ -30,10 +30,16 @@ func (e *LuaEngine) registerADTBindings() {
 	e.L.SetGlobal("getBreakpoints", e.L.NewFunction(e.luaGetBreakpoints))
 	e.L.SetGlobal("deleteBreakpoint", e.L.NewFunction(e.luaDeleteBreakpoint))
 
+	// Debugging - Log Points (WebSocket/ZADT_VSP)
+	e.L.SetGlobal("setLogPoint", e.L.NewFunction(e.luaSetLogPoint))
+	e.L.SetGlobal("collectLogPoints", e.L.NewFunction(e.luaCollectLogPoints))
+
 	// Debugging - Session
 	e.L.SetGlobal("listen", e.L.NewFunction(e.luaListen))
 	e.L.SetGlobal("attach", e.L.NewFunction(e.luaAttach))
 	e.L.SetGlobal("detach", e.L.NewFunction(e.luaDetach))
+	e.L.SetGlobal("listenAll", e.L.NewFunction(e.luaListenAll))
+	e.L.SetGlobal("debugSessions", e.L.NewFunction(e.luaDebugSessions))
 
 	// Debugging - Execution
 	e.L.SetGlobal("stepOver", e.L.NewFunction(e.luaStepOver))
@@ -45,6 +51,8 @@ func (e *LuaEngine) registerADTBindings() {
 	e.L.SetGlobal("getStack", e.L.NewFunction(e.luaGetStack))
 	e.L.SetGlobal("getVariables", e.L.NewFunction(e.luaGetVariables))
 	e.L.SetGlobal("setVariable", e.L.NewFunction(e.luaSetVariable))
+	e.L.SetGlobal("evaluate", e.L.NewFunction(e.luaEvaluate))
+	e.L.SetGlobal("readTable", e.L.NewFunction(e.luaReadTable))
 
 	// Call Graph
 	e.L.SetGlobal("getCallGraph", e.L.NewFunction(e.luaGetCallGraph))
@@ -79,9 +87,16 @@ func (e *LuaEngine) registerADTBindings() {
 	e.L.SetGlobal("listDumps", e.L.NewFunction(e.luaGetDumps)) // New canonical name
 	e.L.SetGlobal("getDumps", e.L.NewFunction(e.luaGetDumps))  // Backwards compatibility
 	e.L.SetGlobal("getDump", e.L.NewFunction(e.luaGetDump))
+	e.L.SetGlobal("analyzeDump", e.L.NewFunction(e.luaAnalyzeDump))
 	e.L.SetGlobal("getMessages", e.L.NewFunction(e.luaGetMessages))
 	e.L.SetGlobal("runUnitTests", e.L.NewFunction(e.luaRunUnitTests))
 	e.L.SetGlobal("syntaxCheck", e.L.NewFunction(e.luaSyntaxCheck))
+	e.L.SetGlobal("runClass", e.L.NewFunction(e.luaRunClass))
+
+	// CDS
+	e.L.SetGlobal("cdsElementInfo", e.L.NewFunction(e.luaCDSElementInfo))
+	e.L.SetGlobal("cdsAnnotations", e.L.NewFunction(e.luaCDSAnnotations))
+	e.L.SetGlobal("previewCDS", e.L.NewFunction(e.luaPreviewCDS))
 }
 
 // --- Search & Source ---
@@ -393,33 +408,34 @@ func (e *LuaEngine) luaSetEnhancementBreakpoint(L *lua.LState) int {
 	return 1
 }
 
-// setWatchpoint(variable, [condition]) - Break when variable changes
-// condition: "change" (default), "read", "any"
+// setWatchpoint(variable, [condition], [value]) - Break when variable changes
+// Requires an attached debug session. condition is an ABAP debugger condition;
+// the legacy modes "change", "read" and "any" are accepted and mean no condition.
 func (e *LuaEngine) luaSetWatchpoint(L *lua.LState) int {
 	variable := getString(L, 1)
-	condition := getOptString(L, 2, "change")
+	condition := getOptString(L, 2, "")
+	value := getOptString(L, 3, "")
 
-	req := &adt.BreakpointRequest{
-		Breakpoints: []adt.Breakpoint{{
-			Kind:           adt.BreakpointKindWatchpoint,
-			Variable:       variable,
-			WatchCondition: condition,
-			Enabled:        true,
-		}},
+	switch strings.ToLower(condition) {
+	case "change", "read", "any":
+		condition = ""
 	}
 
-	resp, err := e.client.SetExternalBreakpoint(e.ctx, req)
+	client, err := e.debugClient(L, 4)
 	if err != nil {
 		L.Push(lua.LNil)
 		L.Push(lua.LString(err.Error()))
 		return 2
 	}
 
-	if len(resp.Breakpoints) > 0 {
-		L.Push(lua.LString(resp.Breakpoints[0].ID))
-	} else {
-		L.Push(lua.LString(""))
+	wp, err := client.DebuggerSetWatchpoint(e.ctx, variable, adt.WatchpointCondition(variable, value, condition))
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
 	}
+
+	L.Push(lua.LString(wp.ID))
 	return 1
 }
 
@@ -492,55 +508,199 @@ func (e *LuaEngine) luaDeleteBreakpoint(L *lua.LState) int {
 	return 1
 }
 
-// --- Debugging: Session ---
+// --- Debugging: Log Points ---
 
-func (e *LuaEngine) luaListen(L *lua.LState) int {
-	timeout := getOptInt(L, 1, 30)
+// setLogPoint(program, line, {expr, ...}, [{condition=..., hitCount=...}])
+// Registers a non-stopping log point via ZADT_VSP. Returns the breakpoint ID.
+func (e *LuaEngine) luaSetLogPoint(L *lua.LState) int {
+	program := getString(L, 1)
+	line := getInt(L, 2)
+	exprTbl := L.CheckTable(3)
 
-	opts := &adt.ListenOptions{
-		TimeoutSeconds: timeout,
+	if e.wsClient == nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString("WebSocket (ZADT_VSP) not connected"))
+		return 2
+	}
+
+	var opts adt.BreakpointOptions
+	exprTbl.ForEach(func(_, v lua.LValue) {
+		if str, ok := v.(lua.LString); ok {
+			opts.LogExpressions = append(opts.LogExpressions, string(str))
+		}
+	})
+	if extra := getTable(L, 4); extra != nil {
+		opts.Condition, _ = extra["condition"].(string)
+		if hc, ok := extra["hitCount"].(int64); ok {
+			opts.HitCount = int(hc)
+		}
 	}
 
-	result, err := e.client.DebuggerListen(e.ctx, opts)
+	id, err := e.wsClient.SetBreakpoint(e.ctx, adt.WSBreakpointSpec{
+		Kind:    "line",
+		Program: strings.ToUpper(program),
+		Line:    line,
+		Options: opts,
+	})
 	if err != nil {
 		L.Push(lua.LNil)
 		L.Push(lua.LString(err.Error()))
 		return 2
 	}
 
-	if result == nil || result.Debuggee == nil {
-		if result != nil && result.TimedOut {
+	L.Push(lua.LString(id))
+	return 1
+}
+
+// collectLogPoints(callback, [timeout]) - Wait for a debuggee and run it past
+// log points, calling callback(entry) for each hit. Returns a table describing
+// where execution stopped ({ended=true} or {program=..., line=..., breakpoint=...}).
+func (e *LuaEngine) luaCollectLogPoints(L *lua.LState) int {
+	callback := L.CheckFunction(1)
+	timeout := getOptInt(L, 2, 60)
+
+	if e.wsClient == nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString("WebSocket (ZADT_VSP) not connected"))
+		return 2
+	}
+
+	e.wsClient.SetLogPointHandler(func(entry adt.LogPointEntry) {
+		row := L.NewTable()
+		L.SetField(row, "breakpoint", lua.LString(entry.BreakpointID))
+		L.SetField(row, "program", lua.LString(entry.Program))
+		L.SetField(row, "line", lua.LNumber(entry.Line))
+		L.SetField(row, "hit", lua.LNumber(entry.Hit))
+		values := L.NewTable()
+		for k, v := range entry.Values {
+			L.SetField(values, k, lua.LString(v))
+		}
+		L.SetField(row, "values", values)
+		if err := L.CallByParam(lua.P{Fn: callback, NRet: 0, Protect: true}, row); err != nil {
+			fmt.Fprintf(e.output, "[logpoint callback error] %v\n", err)
+		}
+	})
+	defer e.wsClient.SetLogPointHandler(nil)
+
+	var frame *adt.DebugStackFrame
+	var err error
+	if e.wsClient.IsAttached() {
+		frame, err = e.wsClient.Step(e.ctx, "continue")
+	} else {
+		var debuggees []adt.DebugDebuggee
+		debuggees, err = e.wsClient.Listen(e.ctx, timeout)
+		if err == nil && len(debuggees) == 0 {
 			L.Push(lua.LNil)
 			L.Push(lua.LString("timeout: no debuggee caught"))
-		} else {
-			L.Push(lua.LNil)
-			L.Push(lua.LString("no debuggee caught"))
+			return 2
+		}
+		if err == nil {
+			frame, err = e.wsClient.Attach(e.ctx, debuggees[0].ID)
 		}
+	}
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
 		return 2
 	}
 
-	tbl := L.NewTable()
-	L.SetField(tbl, "id", lua.LString(result.Debuggee.ID))
-	L.SetField(tbl, "program", lua.LString(result.Debuggee.Program))
-	L.SetField(tbl, "user", lua.LString(result.Debuggee.User))
-	L.SetField(tbl, "line", lua.LNumber(result.Debuggee.Line))
+	hit, err := e.wsClient.ContinueToStop(e.ctx, frame)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	result := L.NewTable()
+	if hit == nil {
+		L.SetField(result, "ended", lua.LTrue)
+	} else {
+		L.SetField(result, "program", lua.LString(hit.Frame.Program))
+		L.SetField(result, "line", lua.LNumber(hit.Frame.Line))
+		L.SetField(result, "breakpoint", lua.LString(hit.BreakpointID))
+		L.SetField(result, "hits", lua.LNumber(hit.Hits))
+	}
+	L.Push(result)
+	return 1
+}
+
+// --- Debugging: Session ---
+
+func (e *LuaEngine) luaListen(L *lua.LState) int {
+	debuggees, err := e.listenAll(L)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	L.Push(debuggeeToLua(L, debuggees[0]))
+	return 1
+}
+
+// listenAll([timeout]) - Wait for debuggees and return all that are waiting
+func (e *LuaEngine) luaListenAll(L *lua.LState) int {
+	debuggees, err := e.listenAll(L)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
 
+	tbl := L.NewTable()
+	for _, d := range debuggees {
+		tbl.Append(debuggeeToLua(L, d))
+	}
 	L.Push(tbl)
 	return 1
 }
 
+func (e *LuaEngine) listenAll(L *lua.LState) ([]adt.Debuggee, error) {
+	timeout := getOptInt(L, 1, 30)
+
+	opts := &adt.ListenOptions{
+		TimeoutSeconds: timeout,
+	}
+
+	debuggees, conflict, err := e.debugSessions.ListenAll(e.ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if conflict != nil {
+		return nil, fmt.Errorf("listener conflict: %s", conflict.ConflictText)
+	}
+	if len(debuggees) == 0 {
+		return nil, fmt.Errorf("timeout: no debuggee caught")
+	}
+	return debuggees, nil
+}
+
+func debuggeeToLua(L *lua.LState, d adt.Debuggee) *lua.LTable {
+	tbl := L.NewTable()
+	L.SetField(tbl, "id", lua.LString(d.ID))
+	L.SetField(tbl, "program", lua.LString(d.Program))
+	L.SetField(tbl, "user", lua.LString(d.User))
+	L.SetField(tbl, "line", lua.LNumber(d.Line))
+	return tbl
+}
+
+// attach(debuggeeId, [user]) - Attach in a new debug session
+// Returns {session, session_id, server, stepping_possible}; pass session to the
+// other debugger functions when several debuggees are attached.
 func (e *LuaEngine) luaAttach(L *lua.LState) int {
 	debuggeeID := getString(L, 1)
 	user := getOptString(L, 2, "")
 
-	result, err := e.client.DebuggerAttach(e.ctx, debuggeeID, user)
+	session, err := e.debugSessions.Attach(e.ctx, debuggeeID, user)
 	if err != nil {
 		L.Push(lua.LNil)
 		L.Push(lua.LString(err.Error()))
 		return 2
 	}
+	result := session.Attach
 
 	tbl := L.NewTable()
+	L.SetField(tbl, "session", lua.LString(session.ID))
 	L.SetField(tbl, "session_id", lua.LString(result.DebugSessionID))
 	L.SetField(tbl, "server", lua.LString(result.ServerName))
 	L.SetField(tbl, "stepping_possible", lua.LBool(result.IsSteppingPossible))
@@ -549,8 +709,31 @@ func (e *LuaEngine) luaAttach(L *lua.LState) int {
 	return 1
 }
 
+// debugSessions() - List attached debug sessions
+func (e *LuaEngine) luaDebugSessions(L *lua.LState) int {
+	tbl := L.NewTable()
+	for _, session := range e.debugSessions.Sessions() {
+		row := debuggeeToLua(L, session.Debuggee)
+		L.SetField(row, "session", lua.LString(session.ID))
+		tbl.Append(row)
+	}
+	L.Push(tbl)
+	return 1
+}
+
+// debugClient returns the client of the debug session passed as argument n.
+// The session is optional while at most one debuggee is attached.
+func (e *LuaEngine) debugClient(L *lua.LState, n int) (*adt.Client, error) {
+	return e.debugSessions.Client(getOptString(L, n, ""))
+}
+
 func (e *LuaEngine) luaDetach(L *lua.LState) int {
-	err := e.client.DebuggerDetach(e.ctx)
+	var err error
+	if session := getOptString(L, 1, ""); session == "" && e.debugSessions.Count() == 0 {
+		err = e.client.DebuggerDetach(e.ctx)
+	} else {
+		_, err = e.debugSessions.Detach(e.ctx, session)
+	}
 	if err != nil {
 		L.Push(lua.LBool(false))
 		L.Push(lua.LString(err.Error()))
@@ -564,7 +747,14 @@ func (e *LuaEngine) luaDetach(L *lua.LState) int {
 // --- Debugging: Execution ---
 
 func (e *LuaEngine) luaStepOver(L *lua.LState) int {
-	result, err := e.client.DebuggerStep(e.ctx, adt.DebugStepOver, "")
+	client, err := e.debugClient(L, 1)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	result, err := client.DebuggerStep(e.ctx, adt.DebugStepOver, "")
 	if err != nil {
 		L.Push(lua.LNil)
 		L.Push(lua.LString(err.Error()))
@@ -580,7 +770,14 @@ func (e *LuaEngine) luaStepOver(L *lua.LState) int {
 }
 
 func (e *LuaEngine) luaStepInto(L *lua.LState) int {
-	result, err := e.client.DebuggerStep(e.ctx, adt.DebugStepInto, "")
+	client, err := e.debugClient(L, 1)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	result, err := client.DebuggerStep(e.ctx, adt.DebugStepInto, "")
 	if err != nil {
 		L.Push(lua.LNil)
 		L.Push(lua.LString(err.Error()))
@@ -596,7 +793,14 @@ func (e *LuaEngine) luaStepInto(L *lua.LState) int {
 }
 
 func (e *LuaEngine) luaStepReturn(L *lua.LState) int {
-	result, err := e.client.DebuggerStep(e.ctx, adt.DebugStepReturn, "")
+	client, err := e.debugClient(L, 1)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	result, err := client.DebuggerStep(e.ctx, adt.DebugStepReturn, "")
 	if err != nil {
 		L.Push(lua.LNil)
 		L.Push(lua.LString(err.Error()))
@@ -612,7 +816,14 @@ func (e *LuaEngine) luaStepReturn(L *lua.LState) int {
 }
 
 func (e *LuaEngine) luaContinue(L *lua.LState) int {
-	result, err := e.client.DebuggerStep(e.ctx, adt.DebugStepContinue, "")
+	client, err := e.debugClient(L, 1)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	result, err := client.DebuggerStep(e.ctx, adt.DebugStepContinue, "")
 	if err != nil {
 		L.Push(lua.LNil)
 		L.Push(lua.LString(err.Error()))
@@ -630,7 +841,14 @@ func (e *LuaEngine) luaContinue(L *lua.LState) int {
 // --- Debugging: Inspection ---
 
 func (e *LuaEngine) luaGetStack(L *lua.LState) int {
-	stack, err := e.client.DebuggerGetStack(e.ctx, false)
+	client, err := e.debugClient(L, 1)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	stack, err := client.DebuggerGetStack(e.ctx, false)
 	if err != nil {
 		L.Push(lua.LNil)
 		L.Push(lua.LString(err.Error()))
@@ -674,7 +892,14 @@ func (e *LuaEngine) luaGetVariables(L *lua.LState) int {
 		return 2
 	}
 
-	vars, err := e.client.DebuggerGetVariables(e.ctx, varIDs)
+	client, err := e.debugClient(L, 2)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	vars, err := client.DebuggerGetVariables(e.ctx, varIDs)
 	if err != nil {
 		L.Push(lua.LNil)
 		L.Push(lua.LString(err.Error()))
@@ -695,6 +920,109 @@ func (e *LuaEngine) luaGetVariables(L *lua.LState) int {
 	return 1
 }
 
+// evaluate(expression) - Evaluate a variable expression, e.g. "lt_tab[ 5 ]-field"
+// Returns {name, value, type, metaType, tableLines, components = {NAME = value}}.
+func (e *LuaEngine) luaEvaluate(L *lua.LState) int {
+	client, err := e.debugClient(L, 2)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	result, err := client.DebuggerEvaluateExpression(e.ctx, getString(L, 1))
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	v := result.Variable
+	tbl := L.NewTable()
+	L.SetField(tbl, "name", lua.LString(result.Expression))
+	L.SetField(tbl, "value", lua.LString(v.Value))
+	L.SetField(tbl, "type", lua.LString(v.DeclaredTypeName))
+	L.SetField(tbl, "metaType", lua.LString(string(v.MetaType)))
+	L.SetField(tbl, "tableLines", lua.LNumber(v.TableLines))
+	if len(result.Components) > 0 {
+		comps := L.NewTable()
+		for _, c := range result.Components {
+			comps.RawSetString(c.Name, lua.LString(c.Value))
+		}
+		L.SetField(tbl, "components", comps)
+	}
+
+	L.Push(tbl)
+	return 1
+}
+
+// readTableOptions converts the options table of readTable. Integral Lua
+// numbers arrive as int64 (see luaToGo).
+func readTableOptions(extra map[string]interface{}) adt.DebugTableReadOptions {
+	var opts adt.DebugTableReadOptions
+	if extra == nil {
+		return opts
+	}
+	if v, ok := extra["offset"].(int64); ok {
+		opts.Offset = int(v)
+	}
+	if v, ok := extra["limit"].(int64); ok {
+		opts.Limit = int(v)
+	}
+	if v, ok := extra["maxScan"].(int64); ok {
+		opts.MaxScan = int(v)
+	}
+	opts.Filter, _ = extra["filter"].(string)
+	if cols, ok := extra["columns"].([]interface{}); ok {
+		for _, c := range cols {
+			if name, ok := c.(string); ok {
+				opts.Columns = append(opts.Columns, name)
+			}
+		}
+	}
+	return opts
+}
+
+// readTable(name, [{offset=, limit=, columns={}, filter=, maxScan=}]) - Read a page of table rows
+// Returns {total, rows = {{index, NAME = value, ...}}, nextOffset, hasMore}.
+func (e *LuaEngine) luaReadTable(L *lua.LState) int {
+	table := getString(L, 1)
+	opts := readTableOptions(getTable(L, 2))
+
+	client, err := e.debugClient(L, 3)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	page, err := client.DebuggerReadTable(e.ctx, table, opts)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	rows := L.NewTable()
+	for _, row := range page.Rows {
+		r := L.NewTable()
+		L.SetField(r, "index", lua.LNumber(row.Index))
+		for col, value := range row.Values {
+			r.RawSetString(col, lua.LString(value))
+		}
+		rows.Append(r)
+	}
+
+	tbl := L.NewTable()
+	L.SetField(tbl, "total", lua.LNumber(page.TotalLines))
+	L.SetField(tbl, "rows", rows)
+	L.SetField(tbl, "nextOffset", lua.LNumber(page.NextOffset))
+	L.SetField(tbl, "hasMore", lua.LBool(page.HasMore))
+
+	L.Push(tbl)
+	return 1
+}
+
 // setVariable(name, value) - Modify variable value in live debug session (FORCE REPLAY!)
 func (e *LuaEngine) luaSetVariable(L *lua.LState) int {
 	name := getString(L, 1)
@@ -720,7 +1048,14 @@ func (e *LuaEngine) luaSetVariable(L *lua.LState) int {
 		valueStr = string(jsonBytes)
 	}
 
-	result, err := e.client.DebuggerSetVariableValue(e.ctx, name, valueStr)
+	client, err := e.debugClient(L, 3)
+	if err != nil {
+		L.Push(lua.LBool(false))
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	result, err := client.DebuggerSetVariableValue(e.ctx, name, valueStr)
 	if err != nil {
 		L.Push(lua.LBool(false))
 		L.Push(lua.LString(err.Error()))
@@ -869,6 +1204,13 @@ func (e *LuaEngine) luaInjectCheckpoint(L *lua.LState) int {
 	failed := 0
 	var lastError string
 
+	client, err := e.debugSessions.Client("")
+	if err != nil {
+		L.Push(lua.LBool(false))
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
 	for varName, value := range checkpoint {
 		// Skip metadata fields
 		if strings.HasPrefix(varName, "_") {
@@ -893,7 +1235,7 @@ func (e *LuaEngine) luaInjectCheckpoint(L *lua.LState) int {
 			valueStr = string(jsonBytes)
 		}
 
-		_, err := e.client.DebuggerSetVariableValue(e.ctx, varName, valueStr)
+		_, err := client.DebuggerSetVariableValue(e.ctx, varName, valueStr)
 		if err != nil {
 			failed++
 			lastError = fmt.Sprintf("%s: %v", varName, err)
@@ -981,6 +1323,94 @@ func (e *LuaEngine) luaGetDump(L *lua.LState) int {
 	return 1
 }
 
+// luaAnalyzeDump analyzes a dump and saves the crash state as a recording,
+// so it can be replayed with forceReplay(recordingId).
+func (e *LuaEngine) luaAnalyzeDump(L *lua.LState) int {
+	dumpID := getString(L, 1)
+
+	opts := adt.DumpAnalysisOptions{Sessions: e.debugSessions}
+	storePath := ".vsp-recordings"
+	if extra := getTable(L, 2); extra != nil {
+		opts.PostMortem, _ = extra["postMortem"].(bool)
+		if v, ok := extra["timeout"].(int64); ok {
+			opts.ListenTimeout = int(v)
+		}
+		if v, ok := extra["context"].(int64); ok {
+			opts.ContextLines = int(v)
+		}
+		if v, ok := extra["frames"].(int64); ok {
+			opts.MaxFrames = int(v)
+		}
+		if v, ok := extra["path"].(string); ok && v != "" {
+			storePath = v
+		}
+	}
+
+	analysis, err := e.client.AnalyzeDump(e.ctx, dumpID, opts)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	if e.historyManager == nil {
+		e.historyManager, err = adt.NewHistoryManager(storePath)
+		if err != nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+	}
+	if err := e.historyManager.SaveRecording(analysis.Recording); err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	tbl := L.NewTable()
+	L.SetField(tbl, "id", lua.LString(analysis.Dump.ID))
+	L.SetField(tbl, "category", lua.LString(analysis.Dump.Category))
+	L.SetField(tbl, "exception", lua.LString(analysis.Dump.ExceptionType))
+	L.SetField(tbl, "recordingId", lua.LString(analysis.RecordingID))
+	L.SetField(tbl, "postMortem", lua.LBool(analysis.PostMortem))
+	if analysis.PostMortemError != "" {
+		L.SetField(tbl, "postMortemError", lua.LString(analysis.PostMortemError))
+	}
+	if analysis.Session != "" {
+		L.SetField(tbl, "session", lua.LString(analysis.Session))
+	}
+
+	frames := L.NewTable()
+	for i, f := range analysis.Frames {
+		row := L.NewTable()
+		L.SetField(row, "program", lua.LString(f.Program))
+		L.SetField(row, "include", lua.LString(f.Include))
+		L.SetField(row, "line", lua.LNumber(f.Line))
+		L.SetField(row, "eventType", lua.LString(f.EventType))
+		L.SetField(row, "event", lua.LString(f.Event))
+		source := L.NewTable()
+		for j, l := range f.Source {
+			sl := L.NewTable()
+			L.SetField(sl, "line", lua.LNumber(l.Line))
+			L.SetField(sl, "text", lua.LString(l.Text))
+			L.SetField(sl, "current", lua.LBool(l.Current))
+			source.RawSetInt(j+1, sl)
+		}
+		L.SetField(row, "source", source)
+		frames.RawSetInt(i+1, row)
+	}
+	L.SetField(tbl, "frames", frames)
+
+	vars := L.NewTable()
+	for _, v := range analysis.Variables {
+		L.SetField(vars, v.Name, lua.LString(v.Value))
+	}
+	L.SetField(tbl, "variables", vars)
+
+	L.Push(tbl)
+	return 1
+}
+
 func (e *LuaEngine) luaGetMessages(L *lua.LState) int {
 	msgClass := getString(L, 1)
 
@@ -1073,6 +1503,20 @@ func (e *LuaEngine) luaSyntaxCheck(L *lua.LState) int {
 	return 1
 }
 
+func (e *LuaEngine) luaRunClass(L *lua.LState) int {
+	className := getString(L, 1)
+
+	console, err := e.client.RunClass(e.ctx, className)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	L.Push(lua.LString(console))
+	return 1
+}
+
 // --- Execution Recording (Phase 5.2) ---
 
 func (e *LuaEngine) luaStartRecording(L *lua.LState) int {
@@ -1399,6 +1843,13 @@ func (e *LuaEngine) luaForceReplay(L *lua.LState) int {
 	failed := 0
 	var lastError string
 
+	client, err := e.debugSessions.Client("")
+	if err != nil {
+		L.Push(lua.LBool(false))
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
 	fmt.Fprintf(e.output, "Force Replay: Injecting state from %s step %d\n", recordingID, stepNumber)
 	fmt.Fprintf(e.output, "Location: %s:%d\n", frame.Location.Program, frame.Location.Line)
 
@@ -1408,7 +1859,7 @@ func (e *LuaEngine) luaForceReplay(L *lua.LState) int {
 		}
 
 		valueStr := fmt.Sprintf("%v", v.Value)
-		_, err := e.client.DebuggerSetVariableValue(e.ctx, v.Name, valueStr)
+		_, err := client.DebuggerSetVariableValue(e.ctx, v.Name, valueStr)
 		if err != nil {
 			failed++
 			lastError = fmt.Sprintf("%s: %v", v.Name, err)
@@ -1456,11 +1907,18 @@ func (e *LuaEngine) luaReplayFromStep(L *lua.LState) int {
 	injected := 0
 	failed := 0
 
+	client, err := e.debugSessions.Client("")
+	if err != nil {
+		L.Push(lua.LBool(false))
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
 	fmt.Fprintf(e.output, "Replay from step %d\n", stepNumber)
 
 	for name, v := range vars {
 		valueStr := fmt.Sprintf("%v", v.Value)
-		_, err := e.client.DebuggerSetVariableValue(e.ctx, name, valueStr)
+		_, err := client.DebuggerSetVariableValue(e.ctx, name, valueStr)
 		if err != nil {
 			failed++
 		} else {
@@ -1475,3 +1933,79 @@ func (e *LuaEngine) luaReplayFromStep(L *lua.LState) int {
 	L.Push(lua.LNumber(injected))
 	return 2
 }
+
+// --- CDS ---
+
+func (e *LuaEngine) luaCDSElementInfo(L *lua.LState) int {
+	entity := getString(L, 1)
+
+	info, err := e.client.GetCDSElementInfo(e.ctx, entity)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	L.Push(goToLua(L, info))
+	return 1
+}
+
+func (e *LuaEngine) luaCDSAnnotations(L *lua.LState) int {
+	// cdsAnnotations(entity, [extensions])
+	entity := getString(L, 1)
+	var extensions []string
+	if tbl, ok := L.Get(2).(*lua.LTable); ok {
+		tbl.ForEach(func(_, v lua.LValue) {
+			extensions = append(extensions, v.String())
+		})
+	}
+
+	active, err := e.client.GetCDSActiveAnnotations(e.ctx, entity, extensions)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	L.Push(goToLua(L, active))
+	return 1
+}
+
+func (e *LuaEngine) luaPreviewCDS(L *lua.LState) int {
+	// previewCDS(entity, [{parameters={...}, association=, fields={...}, where=, orderBy=, maxRows=}])
+	entity := getString(L, 1)
+	opts := adt.CDSPreviewOptions{}
+	if o := getTable(L, 2); o != nil {
+		if params, ok := o["parameters"].(map[string]interface{}); ok {
+			opts.Parameters = make(map[string]string, len(params))
+			for k, v := range params {
+				opts.Parameters[k] = fmt.Sprint(v)
+			}
+		}
+		if fields, ok := o["fields"].([]interface{}); ok {
+			for _, f := range fields {
+				opts.Fields = append(opts.Fields, fmt.Sprint(f))
+			}
+		}
+		opts.Association, _ = o["association"].(string)
+		opts.Where, _ = o["where"].(string)
+		opts.OrderBy, _ = o["orderBy"].(string)
+		if n, ok := o["maxRows"].(int64); ok {
+			opts.MaxRows = int(n)
+		}
+	}
+
+	result, err := e.client.PreviewCDS(e.ctx, entity, opts)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	tbl := L.NewTable()
+	for i, row := range result.Rows {
+		tbl.RawSetInt(i+1, goToLua(L, row))
+	}
+	L.Push(tbl)
+	return 1
+}
@@ -22,6 +22,12 @@ type LuaEngine struct {
 	ctx    context.Context
 	output io.Writer
 
+	// Optional WebSocket debug client (ZADT_VSP) for log points
+	wsClient *adt.DebugWebSocketClient
+
+	// Attached debug sessions (one per debuggee)
+	debugSessions *adt.DebugSessionManager
+
 	// Checkpoints (for Force Replay)
 	checkpoints map[string]map[string]interface{}
 
@@ -47,6 +53,7 @@ func NewLuaEngine(client *adt.Client) *LuaEngine {
 		output:      os.Stdout,
 		checkpoints: make(map[string]map[string]interface{}),
 	}
+	engine.debugSessions = adt.NewDebugSessionManager(client)
 
 	engine.registerBuiltins()
 	engine.registerADTBindings()
@@ -59,6 +66,11 @@ func (e *LuaEngine) SetContext(ctx context.Context) {
 	e.ctx = ctx
 }
 
+// SetDebugWebSocketClient sets the ZADT_VSP WebSocket client used by log point functions.
+func (e *LuaEngine) SetDebugWebSocketClient(ws *adt.DebugWebSocketClient) {
+	e.wsClient = ws
+}
+
 // SetOutput sets the output writer for print statements.
 func (e *LuaEngine) SetOutput(w io.Writer) {
 	e.output = w
@@ -132,6 +144,7 @@ Search & Source:
   getSource(type, name)           Get source code
   writeSource(type, name, src)    Write source code
   editSource(type, name, old, new) Edit source code
+  runClass(class)                 Run IF_OO_ADT_CLASSRUN class, returns console output
 
 Debugging - Breakpoints:
   setBreakpoint(prog, line)       Set line breakpoint
@@ -140,14 +153,17 @@ Debugging - Breakpoints:
   setMessageBP(class, num, [type]) Set message breakpoint (e.g., "00", "001", "E")
   setBadiBP(badiName)             Set BAdi breakpoint
   setEnhancementBP(spot, [impl])  Set enhancement point breakpoint
-  setWatchpoint(var, [condition]) Set watchpoint (condition: change/read/any)
+  setWatchpoint(var, [cond], [value]) Set watchpoint (attached session)
   setMethodBP(class, method)      Set method entry breakpoint
   getBreakpoints()                List active breakpoints
   deleteBreakpoint(id)            Delete a breakpoint
 
   listen([timeout])               Wait for debuggee
-  attach(debuggeeId)              Attach to debuggee
-  detach()                        Detach from debuggee
+  listenAll([timeout])            Wait and return all waiting debuggees
+  attach(debuggeeId)              Attach in a new session (returns .session)
+  detach([session])               Detach from debuggee
+  debugSessions()                 List attached sessions
+  (step*/getStack/getVariables/evaluate/readTable take an optional session last)
 
   stepOver()                      Step over
   stepInto()                      Step into
@@ -156,6 +172,8 @@ Debugging - Breakpoints:
 
   getStack()                      Get call stack
   getVariables([scope])           Get variables
+  evaluate(expr)                  Evaluate expression (e.g., "lt_tab[ 5 ]-field")
+  readTable(name, [opts])         Page table rows {offset, limit, columns, filter}
   setVariable(name, value)        Set variable value (FORCE REPLAY!)
 
 Recording & Checkpoints:
@@ -182,6 +200,7 @@ Force Replay (Phase 5.5) - THE KILLER FEATURE:
   setVariable(name, value)        Modify variable in live session
   injectCheckpoint(name)          Inject all vars from checkpoint
   forceReplay(recordingId, [step]) Inject state from saved recording
+  analyzeDump(id, [opts])         Dump stack + source, saved as recording {postMortem}
   replayFromStep(stepNumber)      Inject state from current recording
 
 Utilities:
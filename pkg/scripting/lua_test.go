@@ -546,6 +546,12 @@ func TestGlobalFunctionsRegistered(t *testing.T) {
 		"saveCheckpoint", "getCheckpoint", "listCheckpoints",
 		"startRecording", "stopRecording", "getRecording",
 		"findWhenChanged", "findChanges",
+		"setLogPoint", "collectLogPoints",
+		"evaluate", "readTable",
+		"listenAll", "debugSessions",
+		"analyzeDump",
+		"cdsElementInfo", "cdsAnnotations", "previewCDS",
+		"runClass",
 	}
 
 	var buf bytes.Buffer
@@ -564,3 +570,45 @@ func TestGlobalFunctionsRegistered(t *testing.T) {
 		t.Errorf("json module is not registered: %v", err)
 	}
 }
+
+func TestLogPointsWithoutWebSocket(t *testing.T) {
+	engine := NewLuaEngine(nil)
+	defer engine.Close()
+
+	var buf bytes.Buffer
+	engine.SetOutput(&buf)
+
+	err := engine.Execute(`
+		local id, err = setLogPoint("ZTEST", 10, {"LV_COUNT"})
+		print(tostring(id), err)
+		local res, err2 = collectLogPoints(function(e) end)
+		print(tostring(res), err2)
+	`)
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	output := buf.String()
+	if strings.Count(output, "not connected") != 2 {
+		t.Errorf("expected both calls to report missing WebSocket, got: %s", output)
+	}
+}
+
+func TestReadTableOptions(t *testing.T) {
+	engine := NewLuaEngine(nil)
+	defer engine.Close()
+
+	var got map[string]interface{}
+	engine.L.SetGlobal("capture", engine.L.NewFunction(func(L *lua.LState) int {
+		got = getTable(L, 1)
+		return 0
+	}))
+	if err := engine.Execute(`capture({offset = 100, limit = 20, maxScan = 500, filter = "STATUS = 'E'", columns = {"MATNR"}})`); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	opts := readTableOptions(got)
+	if opts.Offset != 100 || opts.Limit != 20 || opts.MaxScan != 500 || opts.Filter != "STATUS = 'E'" || len(opts.Columns) != 1 {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+}